@@ -9,6 +9,13 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/dnldd/entry/engine"
+	"github.com/dnldd/entry/indicator"
+	"github.com/dnldd/entry/market"
+	"github.com/dnldd/entry/position"
+	"github.com/dnldd/entry/priceaction"
+	"github.com/dnldd/entry/service"
+	"github.com/dnldd/entry/shared"
 	"github.com/joho/godotenv"
 )
 
@@ -16,12 +23,303 @@ import (
 type Config struct {
 	// Markets represents the tracked markets.
 	Markets []string
+	// AssetClasses is the asset class of markets as market=class entries, where the class is
+	// index, fx or crypto. Markets without an asset class are equity indices.
+	AssetClasses []string
+	// DirectionModes is the direction mode of markets as market=mode entries, where the mode
+	// is both, long or short. Markets without a direction mode take entries in both directions.
+	DirectionModes []string
+	// RTHWindows are the regular trading hours of markets as market=HH:MM-HH:MM entries in new
+	// york time. Entries outside them are suppressed, markets without them are not restricted.
+	RTHWindows []string
+	// ReasonPriority ranks reasons by name, most significant first, ordering the reasons of
+	// entry explanations and audit records. The default priority is used if empty.
+	ReasonPriority []string
 	// FMPAPIkey is the FMP service API Key.
 	FMPAPIKey string
 	// Backtest is the backtesting flag.
 	Backtest bool
+	// Paper is the paper trading flag, live data is evaluated with simulated fills.
+	Paper bool
 	// BacktestDataFilepath is the filepath to the backtest data.
 	BacktestDataFilepath string
+	// SortBacktestData is the flag for sorting backtest data with out of order candles
+	// instead of rejecting it.
+	SortBacktestData bool
+	// ReplayMode is how backtest candles are paced when replayed.
+	ReplayMode string
+	// ReplayDelay is the number of seconds between backtest candles for delayed replays.
+	ReplayDelay float64
+	// ReplaySpeed is the multiple of wall-clock session timing scaled replays run at.
+	ReplaySpeed float64
+	// VWAPTypicalPrice is the typical price formula used for vwap calculations.
+	VWAPTypicalPrice string
+	// VWAPRollingWindow is the number of candles covered by a rolling vwap. A zero window
+	// anchors the vwap to the session.
+	VWAPRollingWindow int
+	// VWAPAnchors is when the vwaps of markets are reset, as market=anchor entries where the
+	// anchor is daily, session or an RFC3339 anchor time.
+	VWAPAnchors []string
+	// MovingAverage is the formula used for moving average calculations.
+	MovingAverage string
+	// MovingAveragePeriod is the number of candles covered by the moving average.
+	MovingAveragePeriod int
+	// AggregateCandles is the flag for building higher timeframe candles from one-minute candles.
+	AggregateCandles bool
+	// VolumeProfileBinSize is the price range covered by a session volume profile bin.
+	VolumeProfileBinSize float64
+	// Sessions are the name=HH:MM-HH:MM[@location] daily sessions tracked, the last session
+	// closes the trading day. The asia, london and new york sessions are used if empty.
+	Sessions []string
+	// EqualLevelTolerance is the percentage of price equal highs and lows can differ by to
+	// be signalled as liquidity levels.
+	EqualLevelTolerance float64
+	// ImbalanceGapRatio is the minimum ratio of an imbalance gap to its displacement candle's body.
+	ImbalanceGapRatio float64
+	// ImbalanceGapSize is the minimum absolute size of an imbalance gap.
+	ImbalanceGapSize float64
+	// ImbalanceVolumeMultiple is the multiple of the average volume an imbalance displacement
+	// candle's volume must reach.
+	ImbalanceVolumeMultiple float64
+	// IgnoreImbalanceVolume is the flag for detecting imbalances regardless of the
+	// displacement candle's volume.
+	IgnoreImbalanceVolume bool
+	// PinbarWickPercent is the minimum percentage of the candle range the longest wick of a
+	// pinbar covers.
+	PinbarWickPercent float64
+	// DojiBodyPercent is the maximum percentage of the candle range the body of a doji covers.
+	DojiBodyPercent float64
+	// DojiWickPercent is the minimum percentage of the candle range both wicks of a doji cover.
+	DojiWickPercent float64
+	// MarubozuBodyPercent is the minimum percentage of the candle range the body of a marubozu
+	// covers.
+	MarubozuBodyPercent float64
+	// EngulfingBodyPercent is the minimum percentage of the candle range the body of an
+	// engulfing candle covers.
+	EngulfingBodyPercent float64
+	// StatusTimeout is the number of seconds markets wait on the status of a relayed update
+	// or signal.
+	StatusTimeout float64
+	// StatusTimeoutPolicy is how markets handle status timeouts.
+	StatusTimeoutPolicy string
+	// WarmupCandles is the number of candles of a timeframe a market needs before reactions
+	// are evaluated.
+	WarmupCandles int
+	// PriceActionMarketWorkers is the number of signals of a market handled concurrently by
+	// the price action manager.
+	PriceActionMarketWorkers int
+	// PriceActionRequestWorkers is the number of requests handled concurrently by the price
+	// action manager across all markets.
+	PriceActionRequestWorkers int
+	// DrainGracePeriod is the number of seconds spent handling buffered signals on shutdown.
+	DrainGracePeriod float64
+	// DropReportInterval is the number of seconds between reports of signals dropped by
+	// channels at capacity.
+	DropReportInterval float64
+	// HealthAddress is the address the /healthz and /readyz probes are served on. The
+	// probes are not served if empty.
+	HealthAddress string
+	// ReactionWindow is the number of candles price reactions are evaluated over.
+	ReactionWindow int
+	// RequireFullWindow is the flag for only evaluating reactions once the full reaction
+	// window has formed.
+	RequireFullWindow bool
+	// MinReactionCandles is the minimum number of candles price reactions are evaluated with,
+	// reactions with fewer candles are deferred. The classification minimum is used if zero.
+	MinReactionCandles int
+	// PriceDataTimeout is the number of seconds to wait for price data responses. The default
+	// timeout is used if zero.
+	PriceDataTimeout float64
+	// VWAPTimeout is the number of seconds to wait for vwap responses. The default timeout is
+	// used if zero.
+	VWAPTimeout float64
+	// CandleMetadataTimeout is the number of seconds to wait for candle metadata responses to
+	// be received. The default timeout is used if zero.
+	CandleMetadataTimeout float64
+	// SignalStatusTimeout is the number of seconds to wait for relayed signals to be
+	// processed. The default timeout is used if zero.
+	SignalStatusTimeout float64
+	// ReactionDebounce is the price distance within which reactions to different focus types
+	// on the same candle are collapsed into one.
+	ReactionDebounce float64
+	// StaleDistance is the distance from the current price, as a percentage of it, beyond
+	// which tracked levels and imbalances are swept as stale.
+	StaleDistance float64
+	// StaleCleanupInterval is the number of seconds between stale level and imbalance sweeps.
+	StaleCleanupInterval float64
+	// VWAPBands is the flag for evaluating reactions at the standard deviation bands of the
+	// vwap along with the vwap line.
+	VWAPBands bool
+	// LateBreakHandling is how breaks only confirmed by the last close of the reaction window
+	// are classified.
+	LateBreakHandling string
+	// ReactionEvaluation is when market updates are evaluated for reactions, only on candle
+	// closes (the default when empty) or on every update.
+	ReactionEvaluation string
+	// VWAPMismatchTolerance is the number of entries vwap and price data of a vwap reaction
+	// can differ in length by.
+	VWAPMismatchTolerance int
+	// RequireImbalancePurge is the flag for only reacting to imbalances purged by price.
+	RequireImbalancePurge bool
+	// IgnoreChopReactions is the flag for filtering out chop reactions before they reach the engine.
+	IgnoreChopReactions bool
+	// MinReactionMovement is the minimum distance between the current price and a reaction's
+	// focus, as a percentage of the focus price, for the reaction to reach the engine.
+	MinReactionMovement float64
+	// NeutralSkewMode is how entries are taken for markets with neutral skew.
+	NeutralSkewMode string
+	// TrendBias is how reversals are biased by the moving average trend.
+	TrendBias string
+	// ExitPolicy is how open positions are exited on opposing reactions.
+	ExitPolicy string
+	// CapacityPolicy is how reactions are relayed when the engine is at capacity.
+	CapacityPolicy string
+	// VolumeNormalization is how reaction volume is measured against the average volume
+	// threshold.
+	VolumeNormalization string
+	// VolumeFloors are the minimum average volume of markets as market=volume entries, below
+	// which markets are too thin to trade. Markets without a floor are not checked.
+	VolumeFloors []string
+	// CapacityTimeout is the number of seconds spent waiting on engine capacity under the
+	// block capacity policy.
+	CapacityTimeout float64
+	// ConfluenceWeight is the weight of engine confluence in signal confidence.
+	ConfluenceWeight float64
+	// LevelQualityWeight is the weight of level quality in signal confidence.
+	LevelQualityWeight float64
+	// TrendAlignmentWeight is the weight of trend alignment in signal confidence.
+	TrendAlignmentWeight float64
+	// NewsEvents represents the RFC3339 release times of scheduled high-impact news entries
+	// are suppressed around.
+	NewsEvents []string
+	// NewsBlackoutWindow is the number of minutes entries are suppressed for before and after
+	// a news release.
+	NewsBlackoutWindow float64
+	// RegimeWindow is the number of candles the market regime is evaluated over. Entries are
+	// not suppressed in ranging markets if zero.
+	RegimeWindow int
+	// RegimeEfficiency is the minimum ratio of net displacement to summed true range for a
+	// market to be trending.
+	RegimeEfficiency float64
+	// RegimeOverrideConfluence is the confluence at or above which entries are taken in
+	// ranging markets.
+	RegimeOverrideConfluence int
+	// BracketRewardRatio is the multiple of the points risked to the stop loss the target of
+	// bracketed entries is placed at. Entries are not bracketed if zero.
+	BracketRewardRatio float64
+	// StructureSkew is the flag for biasing entries of neutral skewed markets towards the
+	// direction of confirmed level breaks against the prevailing trend.
+	StructureSkew bool
+	// TightestStop is the flag for placing stops at the tighter of the signal candle and
+	// reaction window stops that still sits beyond the reacted level.
+	TightestStop bool
+	// WickStopBuffer is the multiple of the average wick length of the reaction's candles
+	// stops are buffered by. Stops use the fixed points buffer if zero.
+	WickStopBuffer float64
+	// StopBuffers are the distances stops are placed beyond the reaction's candles as
+	// market=mode:value entries, the mode is points or atr. Markets without one use the
+	// fixed points buffer.
+	StopBuffers []string
+	// StopOutBump is the confluence the entry threshold of the next reversal at a level is
+	// raised by after a position entered at it is stopped out. Thresholds are not raised if zero.
+	StopOutBump int
+	// StopOutWindow is the number of minutes after a stop out the next reversal at its level is
+	// held to the raised entry threshold. The default window is used if zero.
+	StopOutWindow float64
+	// VWAPStretch is the number of standard deviations price must be stretched from vwap by
+	// for reversals at vwap to be awarded mean reversion confluence. The default stretch is
+	// used if zero.
+	VWAPStretch float64
+	// LevelVWAPGap is the price distance within which a level and vwap reacted to on the same
+	// candle are treated as one focus. Levels and vwap are not checked for overlap if zero.
+	LevelVWAPGap float64
+	// HighVolumeWindows are the HH:MM-HH:MM daily windows in new york time reactions are
+	// awarded high volume session confluence in. The asset class windows are used if empty.
+	HighVolumeWindows []string
+	// MinDistinctReasons is the minimum number of distinct reasons entry signals must be
+	// backed by. Signals are not checked for breadth if zero.
+	MinDistinctReasons int
+	// BreakConfirmationCloses is the number of trailing closes beyond the broken focus
+	// required to confirm breaks. Only the last close is required if zero.
+	BreakConfirmationCloses int
+	// MinPointsRange is the minimum points range of entry stops. Stops are not bounded if
+	// both the minimum and maximum points range are zero.
+	MinPointsRange float64
+	// MaxPointsRange is the maximum points range of entry stops. The points range limit is
+	// used if zero and a minimum is set.
+	MaxPointsRange float64
+	// StopRangeMode is how entries with stops outside of the points range bounds are handled.
+	StopRangeMode string
+	// EnabledReactions are the reaction kinds entries are taken on, as reactions, focuses or
+	// focus:reaction pairs. Entries are taken on all reaction kinds if empty.
+	EnabledReactions []string
+	// LevelWeights are the confluence weights of reversals at levels by the session the level
+	// was derived from, as source=weight entries. The default weights are used if empty.
+	LevelWeights []string
+	// Thresholds are the confluence thresholds of the engine as kind=value entries. The
+	// default thresholds are used for kinds not provided.
+	Thresholds []string
+	// NotifyThrottle is how position notifications are throttled.
+	NotifyThrottle string
+	// NotifyThrottleInterval is the number of seconds market notifications are rate limited
+	// within, or the interval notification digests are delivered at. The default interval is
+	// used if zero.
+	NotifyThrottleInterval float64
+	// PositionSize is the base size of a position. Positions are not sized if zero.
+	PositionSize float64
+	// MaxPositionSize is the maximum size of a position, the base size is used if zero.
+	MaxPositionSize float64
+	// AdjustSizeForWinRate is the flag for scaling position sizes by the rolling win rate.
+	AdjustSizeForWinRate bool
+	// WinRateWindow is the number of closed positions the rolling win rate is evaluated over.
+	WinRateWindow int
+	// MaxRisk is the maximum dollar amount a position risks to its stop loss. Sizes are not
+	// capped by risk if zero.
+	MaxRisk float64
+	// MaxOpenPositions is the maximum number of positions open across all markets. Open
+	// positions are not limited if zero.
+	MaxOpenPositions int
+	// CorrelationGroups is the correlation group of markets as market=group entries. Same
+	// direction exposure is not limited by correlation if empty.
+	CorrelationGroups []string
+	// MaxCorrelatedPositions is the maximum number of same direction positions open within a
+	// correlation group. One position is allowed if zero.
+	MaxCorrelatedPositions int
+	// Instruments is the contract specification of markets as
+	// market=pointvalue:ticksize:minstopdistance entries. Markets without an instrument are
+	// priced one-to-one in dollars.
+	Instruments []string
+	// TrailDistance is the distance trailing stops follow price by, in points or as a multiple
+	// of the average true range. Stops are not trailed if zero.
+	TrailDistance float64
+	// TrailMode is how the distance of trailing stops from price is measured.
+	TrailMode string
+	// TrailActivation is the number of points positions have to be in profit by before their
+	// stops start trailing.
+	TrailActivation float64
+	// TrailATRPeriod is the number of candles the average true range of trailing stops is
+	// evaluated over.
+	TrailATRPeriod int
+	// SlippageMode is how the slippage of simulated fills is measured.
+	SlippageMode string
+	// EntrySlippage is the slippage of simulated entry fills, in points or as a percentage of
+	// price. Fills are not slipped if entry and exit slippage are zero.
+	EntrySlippage float64
+	// ExitSlippage is the slippage of simulated exit fills, in points or as a percentage of
+	// price. Exits use the entry slippage if zero.
+	ExitSlippage float64
+	// PositionsDBFilepath is the filepath to the sqlite database for closed positions.
+	PositionsDBFilepath string
+	// StateDBFilepath is the filepath to the sqlite database for level and imbalance state.
+	StateDBFilepath string
+	// ExportFilepath is the filepath to the json export of chart data.
+	ExportFilepath string
+	// AuditLogFilepath is the filepath to the json lines audit log of evaluated reactions.
+	AuditLogFilepath string
+	// TradesCSVFilepath is the filepath to the csv export of backtest and paper trades.
+	TradesCSVFilepath string
+	// SnapshotFilepath is the filepath to the json state snapshot restored on startup.
+	SnapshotFilepath string
 
 	registeredFlags map[string]bool
 }
@@ -30,6 +328,10 @@ type Config struct {
 func (cfg *Config) Validate() error {
 	var errs error
 
+	if cfg.Backtest && cfg.Paper {
+		errs = errors.Join(errs, fmt.Errorf("backtest and paper modes cannot be combined"))
+	}
+
 	switch cfg.Backtest {
 	case true:
 		if cfg.BacktestDataFilepath == "" {
@@ -44,9 +346,321 @@ func (cfg *Config) Validate() error {
 		}
 	}
 
+	_, err := shared.ParseAssetClasses(cfg.AssetClasses)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	_, err = engine.ParseDirectionModes(cfg.DirectionModes)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	_, err = engine.ParseRTHWindows(cfg.RTHWindows)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	_, err = shared.ParseReasonPriority(cfg.ReasonPriority)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	_, err = indicator.ParseTypicalPrice(cfg.VWAPTypicalPrice)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	replayMode, err := shared.ParseReplayMode(cfg.ReplayMode)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if replayMode == shared.DelayedReplay && cfg.ReplayDelay <= 0 {
+		errs = errors.Join(errs, fmt.Errorf("replay delay must be greater than zero for delayed replays"))
+	}
+	if replayMode == shared.ScaledReplay && cfg.ReplaySpeed <= 0 {
+		errs = errors.Join(errs, fmt.Errorf("replay speed must be greater than zero for scaled replays"))
+	}
+	_, err = engine.ParseNeutralSkewMode(cfg.NeutralSkewMode)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	_, err = shared.ParseLateBreakHandling(cfg.LateBreakHandling)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	_, err = priceaction.ParseReactionEvaluation(cfg.ReactionEvaluation)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	_, err = engine.ParseCapacityPolicy(cfg.CapacityPolicy)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	_, err = engine.ParseVolumeNormalization(cfg.VolumeNormalization)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	_, err = engine.ParseVolumeFloors(cfg.VolumeFloors)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if cfg.CapacityTimeout < 0 {
+		errs = errors.Join(errs, fmt.Errorf("capacity timeout cannot be negative"))
+	}
+	if cfg.VWAPRollingWindow < 0 {
+		errs = errors.Join(errs, fmt.Errorf("vwap rolling window cannot be negative"))
+	}
+	_, err = market.ParseVWAPAnchors(cfg.VWAPAnchors)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	_, err = indicator.ParseMovingAverageKind(cfg.MovingAverage)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if cfg.MovingAveragePeriod < 0 {
+		errs = errors.Join(errs, fmt.Errorf("moving average period cannot be negative"))
+	}
+	_, err = engine.ParseTrendBiasMode(cfg.TrendBias)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	_, err = engine.ParseExitPolicy(cfg.ExitPolicy)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	_, err = position.ParseThrottleMode(cfg.NotifyThrottle)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if cfg.NotifyThrottleInterval < 0 {
+		errs = errors.Join(errs, fmt.Errorf("notify throttle interval cannot be negative"))
+	}
+	if cfg.VolumeProfileBinSize < 0 {
+		errs = errors.Join(errs, fmt.Errorf("volume profile bin size cannot be negative"))
+	}
+	_, err = shared.ParseSessionDefinitions(cfg.Sessions)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if cfg.EqualLevelTolerance < 0 {
+		errs = errors.Join(errs, fmt.Errorf("equal level tolerance cannot be negative"))
+	}
+	if cfg.ImbalanceGapRatio < 0 || cfg.ImbalanceGapSize < 0 || cfg.ImbalanceVolumeMultiple < 0 {
+		errs = errors.Join(errs, fmt.Errorf("imbalance criteria cannot be negative"))
+	}
+	if cfg.StatusTimeout < 0 {
+		errs = errors.Join(errs, fmt.Errorf("status timeout cannot be negative"))
+	}
+	if cfg.WarmupCandles < 0 {
+		errs = errors.Join(errs, fmt.Errorf("warmup candles cannot be negative"))
+	}
+	if cfg.PriceActionMarketWorkers < 0 {
+		errs = errors.Join(errs, fmt.Errorf("price action market workers must be positive"))
+	}
+	if cfg.PriceActionRequestWorkers < 0 {
+		errs = errors.Join(errs, fmt.Errorf("price action request workers must be positive"))
+	}
+	if cfg.DrainGracePeriod < 0 {
+		errs = errors.Join(errs, fmt.Errorf("drain grace period cannot be negative"))
+	}
+	if cfg.DropReportInterval < 0 {
+		errs = errors.Join(errs, fmt.Errorf("drop report interval cannot be negative"))
+	}
+	_, err = market.ParseStatusTimeoutPolicy(cfg.StatusTimeoutPolicy)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if cfg.ReactionWindow < 0 || (cfg.ReactionWindow > 0 && cfg.ReactionWindow < shared.MinReactionWindow) {
+		errs = errors.Join(errs, fmt.Errorf("reaction window must be zero or at least %d candles",
+			shared.MinReactionWindow))
+	}
+	window := cfg.ReactionWindow
+	if window == 0 {
+		window = shared.PriceDataPayloadSize
+	}
+	if cfg.MinReactionCandles < 0 || (cfg.MinReactionCandles > 0 &&
+		(cfg.MinReactionCandles < shared.MinReactionWindow || cfg.MinReactionCandles > window)) {
+		errs = errors.Join(errs, fmt.Errorf("minimum reaction candles must be zero or between "+
+			"%d and the reaction window of %d candles", shared.MinReactionWindow, window))
+	}
+	if cfg.PriceDataTimeout < 0 {
+		errs = errors.Join(errs, fmt.Errorf("price data timeout cannot be negative"))
+	}
+	if cfg.VWAPTimeout < 0 {
+		errs = errors.Join(errs, fmt.Errorf("vwap timeout cannot be negative"))
+	}
+	if cfg.CandleMetadataTimeout < 0 {
+		errs = errors.Join(errs, fmt.Errorf("candle metadata timeout cannot be negative"))
+	}
+	if cfg.SignalStatusTimeout < 0 {
+		errs = errors.Join(errs, fmt.Errorf("signal status timeout cannot be negative"))
+	}
+	if cfg.ReactionDebounce < 0 {
+		errs = errors.Join(errs, fmt.Errorf("reaction debounce cannot be negative"))
+	}
+	if cfg.StaleDistance < 0 {
+		errs = errors.Join(errs, fmt.Errorf("stale distance cannot be negative"))
+	}
+	if cfg.StaleCleanupInterval < 0 {
+		errs = errors.Join(errs, fmt.Errorf("stale cleanup interval cannot be negative"))
+	}
+	if cfg.VWAPMismatchTolerance < 0 {
+		errs = errors.Join(errs, fmt.Errorf("vwap mismatch tolerance cannot be negative"))
+	}
+	_, err = engine.ParseNewsEvents(cfg.NewsEvents)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if cfg.NewsBlackoutWindow < 0 {
+		errs = errors.Join(errs, fmt.Errorf("news blackout window cannot be negative"))
+	}
+	if cfg.RegimeWindow < 0 || cfg.RegimeEfficiency < 0 || cfg.RegimeOverrideConfluence < 0 {
+		errs = errors.Join(errs, fmt.Errorf("regime filter criteria cannot be negative"))
+	}
+	if cfg.RegimeWindow > 0 {
+		filter := engine.RegimeFilter{
+			Window:        uint32(cfg.RegimeWindow),
+			MinEfficiency: cfg.RegimeEfficiency,
+		}
+		err := filter.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating regime filter: %v", err))
+		}
+	}
+	if cfg.BracketRewardRatio < 0 {
+		errs = errors.Join(errs, fmt.Errorf("bracket reward ratio cannot be negative"))
+	}
+	if cfg.WickStopBuffer < 0 {
+		errs = errors.Join(errs, fmt.Errorf("wick stop buffer cannot be negative"))
+	}
+	_, err = engine.ParseStopBuffers(cfg.StopBuffers)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if cfg.StopOutBump < 0 {
+		errs = errors.Join(errs, fmt.Errorf("stop out bump cannot be negative"))
+	}
+	if cfg.StopOutWindow < 0 {
+		errs = errors.Join(errs, fmt.Errorf("stop out window cannot be negative"))
+	}
+	if cfg.VWAPStretch < 0 {
+		errs = errors.Join(errs, fmt.Errorf("vwap stretch cannot be negative"))
+	}
+	if cfg.LevelVWAPGap < 0 {
+		errs = errors.Join(errs, fmt.Errorf("level vwap gap cannot be negative"))
+	}
+	_, err = shared.ParseSessionWindows(cfg.HighVolumeWindows)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if cfg.MinDistinctReasons < 0 {
+		errs = errors.Join(errs, fmt.Errorf("minimum distinct reasons cannot be negative"))
+	}
+	if cfg.BreakConfirmationCloses < 0 {
+		errs = errors.Join(errs, fmt.Errorf("break confirmation closes cannot be negative"))
+	}
+	stopRangeMode, err := engine.ParseStopRangeMode(cfg.StopRangeMode)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	_, err = engine.ParseEnabledReactions(cfg.EnabledReactions)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	_, err = engine.ParseLevelWeights(cfg.LevelWeights)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	_, err = engine.ParseThresholds(cfg.Thresholds)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if cfg.MinPointsRange != 0 || cfg.MaxPointsRange != 0 {
+		stopRange := engine.StopRange{
+			MinPointsRange: cfg.MinPointsRange,
+			MaxPointsRange: cfg.MaxPointsRange,
+			Mode:           stopRangeMode,
+		}
+		err := stopRange.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating stop range: %v", err))
+		}
+	}
+	if cfg.PositionSize < 0 || cfg.MaxPositionSize < 0 {
+		errs = errors.Join(errs, fmt.Errorf("position sizes cannot be negative"))
+	}
+	if cfg.MaxPositionSize > 0 && cfg.MaxPositionSize < cfg.PositionSize {
+		errs = errors.Join(errs, fmt.Errorf("max position size cannot be less than the position size"))
+	}
+	if cfg.WinRateWindow < 0 {
+		errs = errors.Join(errs, fmt.Errorf("win rate window cannot be negative"))
+	}
+	if cfg.MaxOpenPositions < 0 {
+		errs = errors.Join(errs, fmt.Errorf("maximum open positions cannot be negative"))
+	}
+	_, err = position.ParseCorrelationGroups(cfg.CorrelationGroups)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if cfg.MaxCorrelatedPositions < 0 {
+		errs = errors.Join(errs, fmt.Errorf("maximum correlated positions cannot be negative"))
+	}
+	if cfg.MaxRisk < 0 {
+		errs = errors.Join(errs, fmt.Errorf("max risk cannot be negative"))
+	}
+	_, err = shared.ParseInstruments(cfg.Instruments)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	_, err = position.ParseTrailMode(cfg.TrailMode)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if cfg.TrailDistance < 0 || cfg.TrailActivation < 0 || cfg.TrailATRPeriod < 0 {
+		errs = errors.Join(errs, fmt.Errorf("trailing stop settings cannot be negative"))
+	}
+	_, err = position.ParseSlippageMode(cfg.SlippageMode)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if cfg.EntrySlippage < 0 || cfg.ExitSlippage < 0 {
+		errs = errors.Join(errs, fmt.Errorf("slippage cannot be negative"))
+	}
+	if cfg.ConfluenceWeight < 0 || cfg.LevelQualityWeight < 0 || cfg.TrendAlignmentWeight < 0 {
+		errs = errors.Join(errs, fmt.Errorf("confidence weights cannot be negative"))
+	}
+	if cfg.MinReactionMovement < 0 {
+		errs = errors.Join(errs, fmt.Errorf("minimum reaction movement cannot be negative"))
+	}
+	classification := cfg.ClassificationConfig()
+	err = classification.Validate()
+	if err != nil {
+		errs = errors.Join(errs, fmt.Errorf("validating classification config: %v", err))
+	}
+
 	return errs
 }
 
+// ClassificationConfig returns the candle classification config, unset percentages use
+// the defaults.
+func (cfg *Config) ClassificationConfig() shared.ClassificationConfig {
+	classification := shared.DefaultClassificationConfig()
+	if cfg.PinbarWickPercent != 0 {
+		classification.MinPinbarLongestWickPercent = cfg.PinbarWickPercent
+	}
+	if cfg.DojiBodyPercent != 0 {
+		classification.MaxDojiBodyPercent = cfg.DojiBodyPercent
+	}
+	if cfg.DojiWickPercent != 0 {
+		classification.MinDojiWickPercent = cfg.DojiWickPercent
+	}
+	if cfg.MarubozuBodyPercent != 0 {
+		classification.MinMarubozuBodyPercent = cfg.MarubozuBodyPercent
+	}
+	if cfg.EngulfingBodyPercent != 0 {
+		classification.MinEngulfingBodyPercent = cfg.EngulfingBodyPercent
+	}
+
+	return classification
+}
+
 // registerFlag registers command line arguments of any type and tracks them to avoid reregistration.
 func (cfg *Config) registerFlag(name string, value interface{}, usage string) error {
 	if cfg.registeredFlags == nil {
@@ -80,6 +694,12 @@ func (cfg *Config) registerFlag(name string, value interface{}, usage string) er
 			def, _ = strconv.Atoi(defValue)
 		}
 		flag.IntVar(value.(*int), name, def, usage)
+	case reflect.Float64:
+		var def float64
+		if defValue != "" {
+			def, _ = strconv.ParseFloat(defValue, 64)
+		}
+		flag.Float64Var(value.(*float64), name, def, usage)
 	case reflect.Slice:
 		// Only handle []string
 		if val.Elem().Type().Elem().Kind() == reflect.String {
@@ -125,6 +745,22 @@ func loadConfig(cfg *Config, path string) error {
 	if err != nil {
 		return err
 	}
+	err = cfg.registerFlag("assetclasses", &cfg.AssetClasses, "the market=class asset classes of markets, classes are index, fx or crypto")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("directionmodes", &cfg.DirectionModes, "the market=mode directions entries are taken in for markets, modes are both, long or short")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("reasonpriority", &cfg.ReasonPriority, "the reasons entry explanations and audit records are ordered by, most significant first")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("rthwindows", &cfg.RTHWindows, "the market=HH:MM-HH:MM new york time regular trading hours of markets, entries outside them are suppressed")
+	if err != nil {
+		return err
+	}
 	err = cfg.registerFlag("fmpapikey", &cfg.FMPAPIKey, "the FMP api key")
 	if err != nil {
 		return err
@@ -133,13 +769,510 @@ func loadConfig(cfg *Config, path string) error {
 	if err != nil {
 		return err
 	}
+	err = cfg.registerFlag("paper", &cfg.Paper, "the paper trading flag")
+	if err != nil {
+		return err
+	}
 	err = cfg.registerFlag("backtestdatafilepath", &cfg.BacktestDataFilepath, "the backtest data filepath")
 	if err != nil {
 		return err
 	}
+	err = cfg.registerFlag("sortbacktestdata", &cfg.SortBacktestData, "sort backtest data with out of order candles instead of rejecting it")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("replaymode", &cfg.ReplayMode, "how backtest candles are paced when replayed (instant, delayed or scaled)")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("replaydelay", &cfg.ReplayDelay, "the seconds between backtest candles for delayed replays")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("replayspeed", &cfg.ReplaySpeed, "the multiple of wall-clock session timing scaled replays run at")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("positionsdbfilepath", &cfg.PositionsDBFilepath, "the closed positions sqlite database filepath")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("statedbfilepath", &cfg.StateDBFilepath, "the level and imbalance state sqlite database filepath, state is not persisted if empty")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("exportfilepath", &cfg.ExportFilepath, "the chart data json export filepath, chart data is not exported if empty")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("auditlogfilepath", &cfg.AuditLogFilepath, "the evaluated reaction json lines audit log filepath, reactions are not audited if empty")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("tradescsvfilepath", &cfg.TradesCSVFilepath, "the backtest and paper trade csv export filepath, trades are not exported if empty")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("snapshotfilepath", &cfg.SnapshotFilepath, "the state snapshot filepath written on shutdown and restored on startup, state is not snapshotted if empty")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("vwaptypicalprice", &cfg.VWAPTypicalPrice, "the vwap typical price formula (hlc3, ohlc4 or close)")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("vwaprollingwindow", &cfg.VWAPRollingWindow, "the rolling vwap window, zero anchors the vwap to the session")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("vwapanchors", &cfg.VWAPAnchors, "the market=anchor vwap resets of markets, anchors are daily, session or an RFC3339 anchor time")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("movingaverage", &cfg.MovingAverage, "the moving average formula (sma or ema)")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("movingaverageperiod", &cfg.MovingAveragePeriod, "the number of candles covered by the moving average, zero uses the default")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("aggregatecandles", &cfg.AggregateCandles, "build higher timeframe candles from one-minute candles")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("volumeprofilebinsize", &cfg.VolumeProfileBinSize, "the session volume profile bin size, zero uses the default bin size")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("sessions", &cfg.Sessions, "the name=HH:MM-HH:MM[@location] daily sessions tracked with the last closing the trading day, empty uses the asia, london and new york sessions")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("equalleveltolerance", &cfg.EqualLevelTolerance, "the percentage of price equal highs and lows can differ by to be signalled as levels, zero disables detection")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("imbalancegapratio", &cfg.ImbalanceGapRatio, "the minimum ratio of an imbalance gap to its displacement candle body, zero uses the default ratio")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("imbalancegapsize", &cfg.ImbalanceGapSize, "the minimum absolute size of an imbalance gap, gaps of any size qualify if zero")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("imbalancevolumemultiple", &cfg.ImbalanceVolumeMultiple, "the multiple of the average volume imbalance displacement candles must reach, zero uses the default multiple")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("ignoreimbalancevolume", &cfg.IgnoreImbalanceVolume, "detect imbalances regardless of the displacement candle volume")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("pinbarwickpercent", &cfg.PinbarWickPercent, "the minimum percentage of the candle range the longest wick of a pinbar covers, zero uses the default")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("dojibodypercent", &cfg.DojiBodyPercent, "the maximum percentage of the candle range the body of a doji covers, zero uses the default")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("dojiwickpercent", &cfg.DojiWickPercent, "the minimum percentage of the candle range both wicks of a doji cover, zero uses the default")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("marubozubodypercent", &cfg.MarubozuBodyPercent, "the minimum percentage of the candle range the body of a marubozu covers, zero uses the default")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("engulfingbodypercent", &cfg.EngulfingBodyPercent, "the minimum percentage of the candle range the body of an engulfing candle covers, zero uses the default")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("statustimeout", &cfg.StatusTimeout, "the seconds markets wait on the status of relayed updates and signals, zero uses the default timeout")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("statustimeoutpolicy", &cfg.StatusTimeoutPolicy, "how markets handle status timeouts (error, retry or proceed)")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("warmupcandles", &cfg.WarmupCandles, "the number of candles of a timeframe a market needs before reactions are evaluated, zero uses the default")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("pamarketworkers", &cfg.PriceActionMarketWorkers, "the number of signals of a market handled concurrently by the price action manager, zero uses the default")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("parequestworkers", &cfg.PriceActionRequestWorkers, "the number of requests handled concurrently by the price action manager, zero uses the default")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("draingraceperiod", &cfg.DrainGracePeriod, "the seconds spent handling buffered signals on shutdown, zero uses the default grace period")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("dropreportinterval", &cfg.DropReportInterval, "the seconds between reports of signals dropped at capacity, zero uses the default interval")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("healthaddress", &cfg.HealthAddress, "the address the /healthz and /readyz probes are served on, empty disables them")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("reactionwindow", &cfg.ReactionWindow, "the number of candles reactions are evaluated over, zero uses the default window")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("requirefullwindow", &cfg.RequireFullWindow, "only evaluate reactions once the full reaction window has formed")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("minreactioncandles", &cfg.MinReactionCandles, "the minimum number of candles reactions are evaluated with, fewer candles defer reactions, zero uses the classification minimum")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("pricedatatimeout", &cfg.PriceDataTimeout, "the seconds to wait for price data responses, zero uses the default timeout")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("vwaptimeout", &cfg.VWAPTimeout, "the seconds to wait for vwap responses, zero uses the default timeout")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("candlemetadatatimeout", &cfg.CandleMetadataTimeout, "the seconds to wait for candle metadata responses to be received, zero uses the default timeout")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("signalstatustimeout", &cfg.SignalStatusTimeout, "the seconds to wait for relayed signals to be processed, zero uses the default timeout")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("reactiondebounce", &cfg.ReactionDebounce, "the price distance within which reactions to different focus types on the same candle are collapsed, zero disables debouncing")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("staledistance", &cfg.StaleDistance, "the distance from the current price as a percentage, beyond which tracked levels and imbalances are swept as stale, zero keeps distant entries")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("stalecleanupinterval", &cfg.StaleCleanupInterval, "the seconds between sweeps of stale levels and imbalances, zero uses the default interval")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("vwapbands", &cfg.VWAPBands, "evaluate reactions at the first and second standard deviation bands of the vwap")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("latebreak", &cfg.LateBreakHandling, "how breaks only confirmed by the last close of a reaction window are classified (break, reversal or chop)")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("reactionevaluation", &cfg.ReactionEvaluation, "when market updates are evaluated for reactions, close (default) evaluates candle closes only, update evaluates every update")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("vwapmismatchtolerance", &cfg.VWAPMismatchTolerance, "the number of entries vwap and price data of vwap reactions can differ in length by")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("requireimbalancepurge", &cfg.RequireImbalancePurge, "only react to imbalances after price has purged them")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("ignorechopreactions", &cfg.IgnoreChopReactions, "filter out chop reactions before they reach the engine")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("minreactionmovement", &cfg.MinReactionMovement, "the minimum price movement from a reaction's focus as a percentage, for the reaction to reach the engine")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("neutralskewmode", &cfg.NeutralSkewMode, "how entries are taken for neutral skewed markets (both, trend or net)")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("trendbias", &cfg.TrendBias, "how reversals against the moving average trend are handled (none, penalize or block)")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("exitpolicy", &cfg.ExitPolicy, "how open positions are exited on opposing reactions (opposing, stoptarget or hybrid)")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("notifythrottle", &cfg.NotifyThrottle, "how position notifications are throttled (immediate, ratelimit or digest)")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("notifythrottleinterval", &cfg.NotifyThrottleInterval, "the seconds market notifications are rate limited within or digests are delivered at, zero uses the default interval")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("capacitypolicy", &cfg.CapacityPolicy, "how reactions are relayed when the engine is at capacity (drop or block)")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("volumenormalization", &cfg.VolumeNormalization, "how reaction volume is measured against the average volume threshold (raw or zscore)")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("volumefloors", &cfg.VolumeFloors, "the market=volume minimum average volume of markets, reactions of markets below it are not signalled")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("capacitytimeout", &cfg.CapacityTimeout, "the seconds spent waiting on engine capacity under the block capacity policy, zero uses the default timeout")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("confluenceweight", &cfg.ConfluenceWeight, "the weight of confluence in signal confidence, default weights are used if all weights are zero")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("levelqualityweight", &cfg.LevelQualityWeight, "the weight of level quality in signal confidence, default weights are used if all weights are zero")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("trendalignmentweight", &cfg.TrendAlignmentWeight, "the weight of trend alignment in signal confidence, default weights are used if all weights are zero")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("newsevents", &cfg.NewsEvents, "the RFC3339 release times of scheduled high-impact news to suppress entries around")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("newsblackoutwindow", &cfg.NewsBlackoutWindow, "the minutes entries are suppressed for around news releases, zero uses the default window")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("regimewindow", &cfg.RegimeWindow, "the candles the market regime is evaluated over, entries are not suppressed in ranging markets if zero")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("regimeefficiency", &cfg.RegimeEfficiency, "the minimum ratio of net displacement to true range of trending markets, zero uses the default efficiency")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("regimeoverrideconfluence", &cfg.RegimeOverrideConfluence, "the confluence at which entries are taken in ranging markets, zero never overrides the regime")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("bracketrewardratio", &cfg.BracketRewardRatio, "the reward to risk ratio of oco bracket targets around entries, entries are not bracketed if zero")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("structureskew", &cfg.StructureSkew, "treat confirmed level breaks against the trend as a change of structure for neutral skewed markets")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("tighteststop", &cfg.TightestStop, "place stops at the tighter of the signal candle and reaction window stops beyond the reacted level")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("wickstopbuffer", &cfg.WickStopBuffer, "the multiple of the average wick length stops are buffered by, zero uses the fixed points buffer")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("stopoutbump", &cfg.StopOutBump, "the confluence the entry threshold of the next reversal at a stopped out level is raised by, zero disables raising")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("stopoutwindow", &cfg.StopOutWindow, "the minutes after a stop out the next reversal at its level is held to the raised threshold, zero uses the default window")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("stopbuffers", &cfg.StopBuffers, "the market=mode:value distances stops are placed beyond reaction candles, modes are points or atr")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("vwapstretch", &cfg.VWAPStretch, "the standard deviations price must be stretched from vwap by for mean reversion confluence, zero uses the default stretch")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("levelvwapgap", &cfg.LevelVWAPGap, "the price distance within which a level and vwap reacted to on the same candle are treated as one focus, zero disables the check")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("highvolumewindows", &cfg.HighVolumeWindows, "the HH:MM-HH:MM new york time windows reactions are awarded high volume confluence in, empty uses the asset class windows")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("mindistinctreasons", &cfg.MinDistinctReasons, "the minimum number of distinct reasons entry signals must be backed by, zero disables the check")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("breakconfirmationcloses", &cfg.BreakConfirmationCloses, "the number of trailing closes beyond the broken focus required to confirm breaks, zero requires only the last close")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("minpointsrange", &cfg.MinPointsRange, "the minimum points range of entry stops, stops are not bounded if both points range bounds are zero")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("maxpointsrange", &cfg.MaxPointsRange, "the maximum points range of entry stops, zero uses the points range limit if a minimum is set")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("stoprangemode", &cfg.StopRangeMode, "how entries with stops outside of the points range bounds are handled (reject or clamp)")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("enabledreactions", &cfg.EnabledReactions, "the reactions (reversal, break, sweep), focuses (level, vwap, imbalance) or focus:reaction pairs entries are taken on, empty enables all")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("levelweights", &cfg.LevelWeights, "the confluence weights of reversals at levels by level source (unsourced, asia, london, newyork, daily) as source=weight entries, empty uses the defaults")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("thresholds", &cfg.Thresholds, "the confluence thresholds of the engine (levelreversal, levelbreak, vwapreversal, vwapbreak, imbalancereversal, imbalancebreak, averagevolumepercent) as kind=value entries, unset kinds use the defaults")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("positionsize", &cfg.PositionSize, "the base size of a position, positions are not sized if zero")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("maxpositionsize", &cfg.MaxPositionSize, "the maximum size of a position, zero uses the base size")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("adjustsizeforwinrate", &cfg.AdjustSizeForWinRate, "scale position sizes by the rolling win rate of closed positions")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("winratewindow", &cfg.WinRateWindow, "the number of closed positions the rolling win rate is evaluated over, zero uses the default window")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("maxopenpositions", &cfg.MaxOpenPositions, "the maximum number of positions open across all markets, zero does not limit open positions")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("correlationgroups", &cfg.CorrelationGroups, "the market=group correlation groups same direction exposure is limited within")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("maxcorrelatedpositions", &cfg.MaxCorrelatedPositions, "the maximum same direction positions open within a correlation group, zero allows one")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("maxrisk", &cfg.MaxRisk, "the maximum dollar amount a position risks to its stop loss, sizes are not capped by risk if zero")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("instruments", &cfg.Instruments, "the market=pointvalue:ticksize:minstopdistance contract specifications of markets")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("traildistance", &cfg.TrailDistance, "the distance trailing stops follow price by, in points or atr multiples, stops are not trailed if zero")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("trailmode", &cfg.TrailMode, "how trailing stop distances are measured, either points or atr")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("trailactivation", &cfg.TrailActivation, "the points of profit positions need before their stops trail")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("trailatrperiod", &cfg.TrailATRPeriod, "the number of candles the trailing stop atr is evaluated over, zero uses the default period")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("slippagemode", &cfg.SlippageMode, "how the slippage of simulated fills is measured, either points or percent")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("entryslippage", &cfg.EntrySlippage, "the slippage of simulated entry fills in points or percent, fills are not slipped if zero")
+	if err != nil {
+		return err
+	}
+	err = cfg.registerFlag("exitslippage", &cfg.ExitSlippage, "the slippage of simulated exit fills in points or percent, zero uses the entry slippage")
+	if err != nil {
+		return err
+	}
 
 	// Parse command-line flags.
 	flag.Parse()
 
 	return cfg.Validate()
 }
+
+// newConfidenceWeights returns the confidence weights of the provided factor weights, nil is
+// returned for the default weights to be used if all weights are zero.
+func newConfidenceWeights(confluence float64, levelQuality float64, trendAlignment float64) *engine.ConfidenceWeights {
+	if confluence == 0 && levelQuality == 0 && trendAlignment == 0 {
+		return nil
+	}
+
+	return &engine.ConfidenceWeights{
+		Confluence:     confluence,
+		LevelQuality:   levelQuality,
+		TrendAlignment: trendAlignment,
+	}
+}
+
+// loadReloadConfig reads the reloadable config, the tracked markets along with the engine
+// thresholds and weights, from the provided .env file. Thresholds and weights not set in the
+// file are left unset for the running config to be kept.
+func loadReloadConfig(path string) (*service.ReloadConfig, error) {
+	if path == "" {
+		path = ".env"
+	}
+
+	env, err := godotenv.Read(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading .env file: %w", err)
+	}
+
+	markets := env["markets"]
+	if markets == "" {
+		return nil, fmt.Errorf("no markets found in %s", path)
+	}
+
+	// list splits the provided comma separated entries, nil is returned if empty.
+	list := func(entries string) []string {
+		if entries == "" {
+			return nil
+		}
+
+		return strings.Split(entries, ",")
+	}
+
+	thresholds, err := engine.ParseThresholds(list(env["thresholds"]))
+	if err != nil {
+		return nil, fmt.Errorf("parsing thresholds: %v", err)
+	}
+
+	levelWeights, err := engine.ParseLevelWeights(list(env["levelweights"]))
+	if err != nil {
+		return nil, fmt.Errorf("parsing level weights: %v", err)
+	}
+
+	weights := make(map[string]float64, 3)
+	for _, name := range []string{"confluenceweight", "levelqualityweight", "trendalignmentweight"} {
+		value := env[name]
+		if value == "" {
+			continue
+		}
+
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %v", name, err)
+		}
+
+		weights[name] = weight
+	}
+
+	cfg := &service.ReloadConfig{
+		Markets:      strings.Split(markets, ","),
+		Thresholds:   thresholds,
+		LevelWeights: levelWeights,
+		ConfidenceWeights: newConfidenceWeights(weights["confluenceweight"],
+			weights["levelqualityweight"], weights["trendalignmentweight"]),
+	}
+
+	return cfg, nil
+}
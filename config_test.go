@@ -3,8 +3,14 @@ package main
 import (
 	"flag"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/dnldd/entry/engine"
+	"github.com/dnldd/entry/service"
+	"github.com/dnldd/entry/shared"
 )
 
 func TestConfigValidate(t *testing.T) {
@@ -68,6 +74,611 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErr: []string{"backtest data filepath cannot be an empty string"},
 		},
+		{
+			name: "backtest and paper combined",
+			cfg: Config{
+				Backtest:             true,
+				Paper:                true,
+				BacktestDataFilepath: "/path/to/data.csv",
+			},
+			wantErr: []string{"backtest and paper modes cannot be combined"},
+		},
+		{
+			name: "unknown vwap typical price",
+			cfg: Config{
+				Markets:          []string{"AAPL"},
+				FMPAPIKey:        "apikey",
+				VWAPTypicalPrice: "hl2",
+			},
+			wantErr: []string{"unknown typical price formula provided: hl2"},
+		},
+		{
+			name: "unknown neutral skew mode",
+			cfg: Config{
+				Markets:         []string{"AAPL"},
+				FMPAPIKey:       "apikey",
+				NeutralSkewMode: "hedge",
+			},
+			wantErr: []string{"unknown neutral skew mode provided: hedge"},
+		},
+		{
+			name: "unknown late break handling",
+			cfg: Config{
+				Markets:           []string{"AAPL"},
+				FMPAPIKey:         "apikey",
+				LateBreakHandling: "fade",
+			},
+			wantErr: []string{"unknown late break handling provided: fade"},
+		},
+		{
+			name: "unknown reaction evaluation",
+			cfg: Config{
+				Markets:            []string{"AAPL"},
+				FMPAPIKey:          "apikey",
+				ReactionEvaluation: "tick",
+			},
+			wantErr: []string{"unknown reaction evaluation provided: tick"},
+		},
+		{
+			name: "unknown capacity policy",
+			cfg: Config{
+				Markets:        []string{"AAPL"},
+				FMPAPIKey:      "apikey",
+				CapacityPolicy: "spill",
+			},
+			wantErr: []string{"unknown capacity policy provided: spill"},
+		},
+		{
+			name: "unknown volume normalization",
+			cfg: Config{
+				Markets:             []string{"AAPL"},
+				FMPAPIKey:           "apikey",
+				VolumeNormalization: "percentile",
+			},
+			wantErr: []string{"unknown volume normalization provided: percentile"},
+		},
+		{
+			name: "negative capacity timeout",
+			cfg: Config{
+				Markets:         []string{"AAPL"},
+				FMPAPIKey:       "apikey",
+				CapacityTimeout: -1,
+			},
+			wantErr: []string{"capacity timeout cannot be negative"},
+		},
+		{
+			name: "invalid asset class",
+			cfg: Config{
+				Markets:      []string{"AAPL", "BTCUSD"},
+				FMPAPIKey:    "apikey",
+				AssetClasses: []string{"BTCUSD=coin"},
+			},
+			wantErr: []string{"parsing BTCUSD asset class: unknown asset class provided: coin"},
+		},
+		{
+			name: "invalid direction mode",
+			cfg: Config{
+				Markets:        []string{"AAPL"},
+				FMPAPIKey:      "apikey",
+				DirectionModes: []string{"AAPL=sideways"},
+			},
+			wantErr: []string{"parsing AAPL direction mode: unknown direction mode provided: sideways"},
+		},
+		{
+			name: "invalid regular trading hours",
+			cfg: Config{
+				Markets:    []string{"AAPL"},
+				FMPAPIKey:  "apikey",
+				RTHWindows: []string{"AAPL=09:30"},
+			},
+			wantErr: []string{"parsing AAPL regular trading hours: invalid session window entry provided: 09:30"},
+		},
+		{
+			name: "negative candle metadata timeout",
+			cfg: Config{
+				Markets:               []string{"AAPL"},
+				FMPAPIKey:             "apikey",
+				CandleMetadataTimeout: -1,
+			},
+			wantErr: []string{"candle metadata timeout cannot be negative"},
+		},
+		{
+			name: "negative volume floor",
+			cfg: Config{
+				Markets:      []string{"AAPL"},
+				FMPAPIKey:    "apikey",
+				VolumeFloors: []string{"AAPL=-10"},
+			},
+			wantErr: []string{"AAPL volume floor cannot be negative"},
+		},
+		{
+			name: "negative stop buffer",
+			cfg: Config{
+				Markets:     []string{"AAPL"},
+				FMPAPIKey:   "apikey",
+				StopBuffers: []string{"AAPL=points:-2"},
+			},
+			wantErr: []string{"validating AAPL stop buffer: stop buffer cannot be negative"},
+		},
+		{
+			name: "negative stop out bump",
+			cfg: Config{
+				Markets:     []string{"AAPL"},
+				FMPAPIKey:   "apikey",
+				StopOutBump: -1,
+			},
+			wantErr: []string{"stop out bump cannot be negative"},
+		},
+		{
+			name: "invalid session",
+			cfg: Config{
+				Markets:   []string{"AAPL"},
+				FMPAPIKey: "apikey",
+				Sessions:  []string{"rth=09:30"},
+			},
+			wantErr: []string{"invalid session entry provided"},
+		},
+		{
+			name: "invalid reason priority",
+			cfg: Config{
+				Markets:        []string{"AAPL"},
+				FMPAPIKey:      "apikey",
+				ReasonPriority: []string{"strong volume", "gut feeling"},
+			},
+			wantErr: []string{"parsing reason priority: unknown reason provided: gut feeling"},
+		},
+		{
+			name: "invalid instrument",
+			cfg: Config{
+				Markets:     []string{"ES"},
+				FMPAPIKey:   "apikey",
+				Instruments: []string{"ES=50:-0.25:1"},
+			},
+			wantErr: []string{"validating ES instrument: tick size cannot be negative"},
+		},
+		{
+			name: "invalid high volume window",
+			cfg: Config{
+				Markets:           []string{"AAPL"},
+				FMPAPIKey:         "apikey",
+				HighVolumeWindows: []string{"8:30"},
+			},
+			wantErr: []string{"invalid session window entry provided: 8:30"},
+		},
+		{
+			name: "invalid enabled reaction",
+			cfg: Config{
+				Markets:          []string{"AAPL"},
+				FMPAPIKey:        "apikey",
+				EnabledReactions: []string{"level:chop"},
+			},
+			wantErr: []string{"unknown reaction provided for level:chop reaction entry"},
+		},
+		{
+			name: "invalid level weight",
+			cfg: Config{
+				Markets:      []string{"AAPL"},
+				FMPAPIKey:    "apikey",
+				LevelWeights: []string{"weekly=2"},
+			},
+			wantErr: []string{"parsing weekly=2 level weight source: unknown level source provided: weekly"},
+		},
+		{
+			name: "invalid thresholds",
+			cfg: Config{
+				Markets:    []string{"AAPL"},
+				FMPAPIKey:  "apikey",
+				Thresholds: []string{"sweep=4"},
+			},
+			wantErr: []string{"unknown threshold provided: sweep"},
+		},
+		{
+			name: "invalid stop range",
+			cfg: Config{
+				Markets:        []string{"AAPL"},
+				FMPAPIKey:      "apikey",
+				MinPointsRange: 6,
+				MaxPointsRange: 4,
+				StopRangeMode:  "widen",
+			},
+			wantErr: []string{
+				"unknown stop range mode provided: widen",
+				"validating stop range: minimum points range cannot exceed the maximum",
+			},
+		},
+		{
+			name: "invalid vwap anchor",
+			cfg: Config{
+				Markets:     []string{"AAPL"},
+				FMPAPIKey:   "apikey",
+				VWAPAnchors: []string{"AAPL=weekly"},
+			},
+			wantErr: []string{"parsing AAPL vwap anchor: unknown vwap anchor provided: weekly"},
+		},
+		{
+			name: "negative vwap rolling window",
+			cfg: Config{
+				Markets:           []string{"AAPL"},
+				FMPAPIKey:         "apikey",
+				VWAPRollingWindow: -1,
+			},
+			wantErr: []string{"vwap rolling window cannot be negative"},
+		},
+		{
+			name: "unknown moving average",
+			cfg: Config{
+				Markets:       []string{"AAPL"},
+				FMPAPIKey:     "apikey",
+				MovingAverage: "wma",
+			},
+			wantErr: []string{"unknown moving average kind provided: wma"},
+		},
+		{
+			name: "negative moving average period",
+			cfg: Config{
+				Markets:             []string{"AAPL"},
+				FMPAPIKey:           "apikey",
+				MovingAveragePeriod: -1,
+			},
+			wantErr: []string{"moving average period cannot be negative"},
+		},
+		{
+			name: "unknown trend bias",
+			cfg: Config{
+				Markets:   []string{"AAPL"},
+				FMPAPIKey: "apikey",
+				TrendBias: "ignore",
+			},
+			wantErr: []string{"unknown trend bias mode provided: ignore"},
+		},
+		{
+			name: "unknown exit policy",
+			cfg: Config{
+				Markets:    []string{"AAPL"},
+				FMPAPIKey:  "apikey",
+				ExitPolicy: "never",
+			},
+			wantErr: []string{"unknown exit policy provided: never"},
+		},
+		{
+			name: "unknown notify throttle",
+			cfg: Config{
+				Markets:        []string{"AAPL"},
+				FMPAPIKey:      "apikey",
+				NotifyThrottle: "hourly",
+			},
+			wantErr: []string{"unknown throttle mode provided: hourly"},
+		},
+		{
+			name: "negative notify throttle interval",
+			cfg: Config{
+				Markets:                []string{"AAPL"},
+				FMPAPIKey:              "apikey",
+				NotifyThrottleInterval: -1,
+			},
+			wantErr: []string{"notify throttle interval cannot be negative"},
+		},
+		{
+			name: "negative volume profile bin size",
+			cfg: Config{
+				Markets:              []string{"AAPL"},
+				FMPAPIKey:            "apikey",
+				VolumeProfileBinSize: -0.25,
+			},
+			wantErr: []string{"volume profile bin size cannot be negative"},
+		},
+		{
+			name: "unknown status timeout policy",
+			cfg: Config{
+				Markets:             []string{"AAPL"},
+				FMPAPIKey:           "apikey",
+				StatusTimeoutPolicy: "ignore",
+			},
+			wantErr: []string{"unknown status timeout policy provided: ignore"},
+		},
+		{
+			name: "negative status timeout",
+			cfg: Config{
+				Markets:       []string{"AAPL"},
+				FMPAPIKey:     "apikey",
+				StatusTimeout: -1,
+			},
+			wantErr: []string{"status timeout cannot be negative"},
+		},
+		{
+			name: "max position size below position size",
+			cfg: Config{
+				Markets:         []string{"AAPL"},
+				FMPAPIKey:       "apikey",
+				PositionSize:    2,
+				MaxPositionSize: 1,
+			},
+			wantErr: []string{"max position size cannot be less than the position size"},
+		},
+		{
+			name: "unknown replay mode",
+			cfg: Config{
+				Backtest:             true,
+				BacktestDataFilepath: "/tmp/data.csv",
+				ReplayMode:           "slow",
+			},
+			wantErr: []string{"unknown replay mode provided: slow"},
+		},
+		{
+			name: "scaled replay without speed",
+			cfg: Config{
+				Backtest:             true,
+				BacktestDataFilepath: "/tmp/data.csv",
+				ReplayMode:           "scaled",
+			},
+			wantErr: []string{"replay speed must be greater than zero for scaled replays"},
+		},
+		{
+			name: "negative price action workers",
+			cfg: Config{
+				Markets:                   []string{"AAPL"},
+				FMPAPIKey:                 "apikey",
+				PriceActionMarketWorkers:  -1,
+				PriceActionRequestWorkers: -1,
+			},
+			wantErr: []string{"price action market workers must be positive",
+				"price action request workers must be positive"},
+		},
+		{
+			name: "negative drain grace period",
+			cfg: Config{
+				Markets:          []string{"AAPL"},
+				FMPAPIKey:        "apikey",
+				DrainGracePeriod: -1,
+			},
+			wantErr: []string{"drain grace period cannot be negative"},
+		},
+		{
+			name: "invalid news event",
+			cfg: Config{
+				Markets:    []string{"AAPL"},
+				FMPAPIKey:  "apikey",
+				NewsEvents: []string{"2025-03-07 08:30"},
+			},
+			wantErr: []string{"parsing news event 2025-03-07 08:30"},
+		},
+		{
+			name: "negative news blackout window",
+			cfg: Config{
+				Markets:            []string{"AAPL"},
+				FMPAPIKey:          "apikey",
+				NewsEvents:         []string{"2025-03-07T08:30:00-05:00"},
+				NewsBlackoutWindow: -1,
+			},
+			wantErr: []string{"news blackout window cannot be negative"},
+		},
+		{
+			name: "regime window below minimum",
+			cfg: Config{
+				Markets:      []string{"AAPL"},
+				FMPAPIKey:    "apikey",
+				RegimeWindow: 2,
+			},
+			wantErr: []string{"validating regime filter: regime window must be at least 3 candles"},
+		},
+		{
+			name: "negative wick stop buffer",
+			cfg: Config{
+				Markets:        []string{"AAPL"},
+				FMPAPIKey:      "apikey",
+				WickStopBuffer: -1,
+			},
+			wantErr: []string{"wick stop buffer cannot be negative"},
+		},
+		{
+			name: "negative vwap stretch",
+			cfg: Config{
+				Markets:     []string{"AAPL"},
+				FMPAPIKey:   "apikey",
+				VWAPStretch: -1,
+			},
+			wantErr: []string{"vwap stretch cannot be negative"},
+		},
+		{
+			name: "negative level vwap gap",
+			cfg: Config{
+				Markets:      []string{"AAPL"},
+				FMPAPIKey:    "apikey",
+				LevelVWAPGap: -1,
+			},
+			wantErr: []string{"level vwap gap cannot be negative"},
+		},
+		{
+			name: "negative minimum distinct reasons",
+			cfg: Config{
+				Markets:            []string{"AAPL"},
+				FMPAPIKey:          "apikey",
+				MinDistinctReasons: -1,
+			},
+			wantErr: []string{"minimum distinct reasons cannot be negative"},
+		},
+		{
+			name: "negative break confirmation closes",
+			cfg: Config{
+				Markets:                 []string{"AAPL"},
+				FMPAPIKey:               "apikey",
+				BreakConfirmationCloses: -1,
+			},
+			wantErr: []string{"break confirmation closes cannot be negative"},
+		},
+		{
+			name: "negative maximum open positions",
+			cfg: Config{
+				Markets:          []string{"AAPL"},
+				FMPAPIKey:        "apikey",
+				MaxOpenPositions: -1,
+			},
+			wantErr: []string{"maximum open positions cannot be negative"},
+		},
+		{
+			name: "invalid correlation group",
+			cfg: Config{
+				Markets:           []string{"AAPL"},
+				FMPAPIKey:         "apikey",
+				CorrelationGroups: []string{"AAPL"},
+			},
+			wantErr: []string{"invalid correlation group entry provided: AAPL"},
+		},
+		{
+			name: "negative bracket reward ratio",
+			cfg: Config{
+				Markets:            []string{"AAPL"},
+				FMPAPIKey:          "apikey",
+				BracketRewardRatio: -1,
+			},
+			wantErr: []string{"bracket reward ratio cannot be negative"},
+		},
+		{
+			name: "reaction window below minimum",
+			cfg: Config{
+				Markets:        []string{"AAPL"},
+				FMPAPIKey:      "apikey",
+				ReactionWindow: 2,
+			},
+			wantErr: []string{"reaction window must be zero or at least 3 candles"},
+		},
+		{
+			name: "minimum reaction candles above reaction window",
+			cfg: Config{
+				Markets:            []string{"AAPL"},
+				FMPAPIKey:          "apikey",
+				MinReactionCandles: 5,
+			},
+			wantErr: []string{"minimum reaction candles must be zero or between 3 and the reaction window of 4 candles"},
+		},
+		{
+			name: "negative equal level tolerance",
+			cfg: Config{
+				Markets:             []string{"AAPL"},
+				FMPAPIKey:           "apikey",
+				EqualLevelTolerance: -1,
+			},
+			wantErr: []string{"equal level tolerance cannot be negative"},
+		},
+		{
+			name: "negative imbalance gap size",
+			cfg: Config{
+				Markets:          []string{"AAPL"},
+				FMPAPIKey:        "apikey",
+				ImbalanceGapSize: -1,
+			},
+			wantErr: []string{"imbalance criteria cannot be negative"},
+		},
+		{
+			name: "negative warmup candles",
+			cfg: Config{
+				Markets:       []string{"AAPL"},
+				FMPAPIKey:     "apikey",
+				WarmupCandles: -1,
+			},
+			wantErr: []string{"warmup candles cannot be negative"},
+		},
+		{
+			name: "doji body overlapping marubozu body",
+			cfg: Config{
+				Markets:         []string{"AAPL"},
+				FMPAPIKey:       "apikey",
+				DojiBodyPercent: 0.8,
+			},
+			wantErr: []string{"validating classification config: doji body percent must be below the marubozu body percent"},
+		},
+		{
+			name: "engulfing body percent above range",
+			cfg: Config{
+				Markets:              []string{"AAPL"},
+				FMPAPIKey:            "apikey",
+				EngulfingBodyPercent: 1.5,
+			},
+			wantErr: []string{"validating classification config: engulfing body percent must be within (0, 1], got 1.50"},
+		},
+		{
+			name: "unknown trail mode",
+			cfg: Config{
+				Markets:       []string{"AAPL"},
+				FMPAPIKey:     "apikey",
+				TrailDistance: 2,
+				TrailMode:     "percent",
+			},
+			wantErr: []string{"unknown trail mode provided: percent"},
+		},
+		{
+			name: "unknown slippage mode",
+			cfg: Config{
+				Markets:       []string{"AAPL"},
+				FMPAPIKey:     "apikey",
+				EntrySlippage: 1,
+				SlippageMode:  "ticks",
+			},
+			wantErr: []string{"unknown slippage mode provided: ticks"},
+		},
+		{
+			name: "negative slippage",
+			cfg: Config{
+				Markets:      []string{"AAPL"},
+				FMPAPIKey:    "apikey",
+				ExitSlippage: -1,
+			},
+			wantErr: []string{"slippage cannot be negative"},
+		},
+		{
+			name: "negative vwap mismatch tolerance",
+			cfg: Config{
+				Markets:               []string{"AAPL"},
+				FMPAPIKey:             "apikey",
+				VWAPMismatchTolerance: -1,
+			},
+			wantErr: []string{"vwap mismatch tolerance cannot be negative"},
+		},
+		{
+			name: "negative reaction debounce",
+			cfg: Config{
+				Markets:          []string{"AAPL"},
+				FMPAPIKey:        "apikey",
+				ReactionDebounce: -1,
+			},
+			wantErr: []string{"reaction debounce cannot be negative"},
+		},
+		{
+			name: "negative stale distance",
+			cfg: Config{
+				Markets:       []string{"AAPL"},
+				FMPAPIKey:     "apikey",
+				StaleDistance: -1,
+			},
+			wantErr: []string{"stale distance cannot be negative"},
+		},
+		{
+			name: "negative stale cleanup interval",
+			cfg: Config{
+				Markets:              []string{"AAPL"},
+				FMPAPIKey:            "apikey",
+				StaleCleanupInterval: -1,
+			},
+			wantErr: []string{"stale cleanup interval cannot be negative"},
+		},
+		{
+			name: "negative confidence weight",
+			cfg: Config{
+				Markets:            []string{"AAPL"},
+				FMPAPIKey:          "apikey",
+				LevelQualityWeight: -1,
+			},
+			wantErr: []string{"confidence weights cannot be negative"},
+		},
+		{
+			name: "negative minimum reaction movement",
+			cfg: Config{
+				Markets:             []string{"AAPL"},
+				FMPAPIKey:           "apikey",
+				MinReactionMovement: -0.5,
+			},
+			wantErr: []string{"minimum reaction movement cannot be negative"},
+		},
 		{
 			name: "backtest true, other fields missing",
 			cfg: Config{
@@ -150,6 +761,19 @@ func TestLoadConfig(t *testing.T) {
 				Backtest:  false,
 			},
 		},
+		{
+			name: "reaction filter from flags",
+			env:  map[string]string{},
+			args: []string{"cmd", "-markets=AAPL", "-fmpapikey=apikey", "-ignorechopreactions=true",
+				"-minreactionmovement=0.25"},
+			expectErr: false,
+			expectCfg: Config{
+				Markets:             []string{"AAPL"},
+				FMPAPIKey:           "apikey",
+				IgnoreChopReactions: true,
+				MinReactionMovement: 0.25,
+			},
+		},
 		{
 			name:        "missing markets and fmpapikey",
 			env:         map[string]string{},
@@ -222,6 +846,12 @@ func TestLoadConfig(t *testing.T) {
 				if tt.expectCfg.BacktestDataFilepath != "" && cfg.BacktestDataFilepath != tt.expectCfg.BacktestDataFilepath {
 					t.Errorf("BacktestDataFilepath: got %v, want %v", cfg.BacktestDataFilepath, tt.expectCfg.BacktestDataFilepath)
 				}
+				if cfg.IgnoreChopReactions != tt.expectCfg.IgnoreChopReactions {
+					t.Errorf("IgnoreChopReactions: got %v, want %v", cfg.IgnoreChopReactions, tt.expectCfg.IgnoreChopReactions)
+				}
+				if cfg.MinReactionMovement != tt.expectCfg.MinReactionMovement {
+					t.Errorf("MinReactionMovement: got %v, want %v", cfg.MinReactionMovement, tt.expectCfg.MinReactionMovement)
+				}
 			}
 
 			// Clean up env
@@ -231,3 +861,84 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadReloadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	thresholds := engine.DefaultThresholds()
+	thresholds.LevelBreak = 8
+
+	tests := []struct {
+		name      string
+		content   string
+		expectErr bool
+		expected  *service.ReloadConfig
+	}{
+		{
+			name:     "markets provided",
+			content:  "markets=^GSPC,^IXIC\nfmpapikey=apikey\n",
+			expected: &service.ReloadConfig{Markets: []string{"^GSPC", "^IXIC"}},
+		},
+		{
+			name: "thresholds and weights provided",
+			content: "markets=^GSPC\nthresholds=levelbreak=8\nlevelweights=daily=3\n" +
+				"confluenceweight=1\ntrendalignmentweight=0.5\n",
+			expected: &service.ReloadConfig{
+				Markets:           []string{"^GSPC"},
+				Thresholds:        thresholds,
+				ConfidenceWeights: &engine.ConfidenceWeights{Confluence: 1, TrendAlignment: 0.5},
+				LevelWeights:      map[shared.LevelSource]uint32{shared.DailyLevel: 3},
+			},
+		},
+		{
+			name:      "no markets",
+			content:   "fmpapikey=apikey\n",
+			expectErr: true,
+		},
+		{
+			name:      "invalid thresholds",
+			content:   "markets=^GSPC\nthresholds=levelbreak=0\n",
+			expectErr: true,
+		},
+		{
+			name:      "invalid level weights",
+			content:   "markets=^GSPC\nlevelweights=weekly=3\n",
+			expectErr: true,
+		},
+		{
+			name:      "invalid confidence weight",
+			content:   "markets=^GSPC\nconfluenceweight=high\n",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".env")
+			err := os.WriteFile(path, []byte(tt.content), 0600)
+			if err != nil {
+				t.Fatalf("writing env file: %v", err)
+			}
+
+			cfg, err := loadReloadConfig(path)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(cfg, tt.expected) {
+				t.Errorf("ReloadConfig: got %+v, want %+v", cfg, tt.expected)
+			}
+		})
+	}
+
+	// Ensure a missing env file errors.
+	_, err := loadReloadConfig(filepath.Join(dir, "missing.env"))
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
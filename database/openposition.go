@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dnldd/entry/position"
+	"github.com/dnldd/entry/shared"
+)
+
+const (
+	// SQLite statements.
+	createOpenPositionTableSQLite = "CREATE TABLE IF NOT EXISTS openposition (market TEXT NOT NULL, seq INTEGER NOT NULL, id TEXT NOT NULL, timeframe INTEGER, direction INTEGER, stoploss REAL, stoplosspointsrange REAL, size REAL, entryprice REAL, entryreasons TEXT, confluence INTEGER, levelprice REAL, createdon INTEGER, PRIMARY KEY (market, seq))"
+	deleteOpenPositionsSQLite     = "DELETE FROM openposition WHERE market = ?"
+	persistOpenPositionSQLite     = "INSERT INTO openposition (market, seq, id, timeframe, direction, stoploss, stoplosspointsrange, size, entryprice, entryreasons, confluence, levelprice, createdon) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?)"
+	queryOpenPositionsSQLite      = "SELECT id, timeframe, direction, stoploss, stoplosspointsrange, size, entryprice, entryreasons, confluence, levelprice, createdon FROM openposition WHERE market = ? ORDER BY seq ASC"
+)
+
+// OpenPositionStorer defines the requirements for storing the open positions of markets.
+type OpenPositionStorer interface {
+	// PersistOpenPositions replaces the stored open positions of the provided market.
+	PersistOpenPositions(ctx context.Context, market string, positions []*position.Position) error
+	// QueryOpenPositions returns the stored open positions of the provided market, ordered
+	// from the oldest.
+	QueryOpenPositions(market string) ([]*position.Position, error)
+}
+
+// Ensure sqlite implements the OpenPositionStorer interface.
+var _ OpenPositionStorer = (*SQLite)(nil)
+
+// PersistOpenPositions replaces the stored open positions of the provided market.
+func (s *SQLite) PersistOpenPositions(ctx context.Context, market string, positions []*position.Position) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting %s open positions transaction: %w", market, err)
+	}
+
+	_, err = tx.ExecContext(ctx, deleteOpenPositionsSQLite, market)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("clearing %s open positions: %w", market, err)
+	}
+
+	for idx, pos := range positions {
+		_, err := tx.ExecContext(ctx, persistOpenPositionSQLite, market, idx, pos.ID,
+			int(pos.Timeframe), int(pos.Direction), pos.StopLoss, pos.StopLossPointsRange, pos.Size,
+			pos.EntryPrice, pos.EntryReasons, pos.Confluence, pos.LevelPrice, pos.CreatedOn.UnixNano())
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("persisting %s open position %s: %w", market, pos.ID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// QueryOpenPositions returns the stored open positions of the provided market, ordered from
+// the oldest.
+func (s *SQLite) QueryOpenPositions(market string) ([]*position.Position, error) {
+	rows, err := s.db.Query(queryOpenPositionsSQLite, market)
+	if err != nil {
+		return nil, fmt.Errorf("querying open positions: %w", err)
+	}
+	defer rows.Close()
+
+	positions := make([]*position.Position, 0)
+	for rows.Next() {
+		pos := position.Position{Market: market, Status: position.Active}
+		var timeframe, direction int
+		var createdOn int64
+
+		err := rows.Scan(&pos.ID, &timeframe, &direction, &pos.StopLoss, &pos.StopLossPointsRange,
+			&pos.Size, &pos.EntryPrice, &pos.EntryReasons, &pos.Confluence, &pos.LevelPrice, &createdOn)
+		if err != nil {
+			return nil, fmt.Errorf("scanning open position: %w", err)
+		}
+
+		pos.Timeframe = shared.Timeframe(timeframe)
+		pos.Direction = shared.Direction(direction)
+		pos.CreatedOn = time.Unix(0, createdOn).In(s.location)
+
+		positions = append(positions, &pos)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("iterating open positions: %w", err)
+	}
+
+	return positions, nil
+}
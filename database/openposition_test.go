@@ -0,0 +1,120 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dnldd/entry/position"
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+	"github.com/rs/zerolog/log"
+)
+
+func TestSQLiteOpenPositions(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "positions.db")
+	cfg := &SQLiteConfig{
+		Filepath: path,
+		Logger:   &log.Logger,
+	}
+
+	db, err := NewSQLite(ctx, cfg)
+	assert.NoError(t, err)
+
+	gspc := "^GSPC"
+	ixic := "^IXIC"
+
+	// Ensure querying a market without stored open positions returns nothing.
+	positions, err := db.QueryOpenPositions(gspc)
+	assert.NoError(t, err)
+	assert.Equal(t, len(positions), 0)
+
+	now, _, err := shared.NewYorkTime()
+	assert.NoError(t, err)
+	now = now.Truncate(time.Second)
+
+	long := &position.Position{
+		ID:                  "long",
+		Market:              gspc,
+		Timeframe:           shared.FiveMinute,
+		Direction:           shared.Long,
+		StopLoss:            8,
+		StopLossPointsRange: 2,
+		Size:                3,
+		EntryPrice:          10,
+		EntryReasons:        "bullish engulfing, strong volume",
+		Confluence:          6,
+		Status:              position.Active,
+		LevelPrice:          9.5,
+		CreatedOn:           now,
+	}
+	short := &position.Position{
+		ID:                  "short",
+		Market:              gspc,
+		Timeframe:           shared.OneHour,
+		Direction:           shared.Short,
+		StopLoss:            22,
+		StopLossPointsRange: 2,
+		Size:                1,
+		EntryPrice:          20,
+		EntryReasons:        "bearish engulfing",
+		Confluence:          7,
+		Status:              position.Active,
+		CreatedOn:           now.Add(time.Hour),
+	}
+
+	// Ensure the open positions of a market can be persisted.
+	err = db.PersistOpenPositions(ctx, gspc, []*position.Position{long, short})
+	assert.NoError(t, err)
+	err = db.PersistOpenPositions(ctx, ixic, []*position.Position{{ID: "other", Market: ixic,
+		Direction: shared.Long, CreatedOn: now}})
+	assert.NoError(t, err)
+
+	// Ensure stored open positions are returned in order.
+	positions, err = db.QueryOpenPositions(gspc)
+	assert.NoError(t, err)
+	assert.Equal(t, len(positions), 2)
+	for idx, want := range []*position.Position{long, short} {
+		got := positions[idx]
+		assert.Equal(t, got.ID, want.ID)
+		assert.Equal(t, got.Market, want.Market)
+		assert.Equal(t, got.Timeframe, want.Timeframe)
+		assert.Equal(t, got.Direction, want.Direction)
+		assert.Equal(t, got.StopLoss, want.StopLoss)
+		assert.Equal(t, got.StopLossPointsRange, want.StopLossPointsRange)
+		assert.Equal(t, got.Size, want.Size)
+		assert.Equal(t, got.EntryPrice, want.EntryPrice)
+		assert.Equal(t, got.EntryReasons, want.EntryReasons)
+		assert.Equal(t, got.Confluence, want.Confluence)
+		assert.Equal(t, got.Status, position.Active)
+		assert.Equal(t, got.LevelPrice, want.LevelPrice)
+		assert.True(t, got.CreatedOn.Equal(want.CreatedOn))
+	}
+
+	// Ensure persisting replaces the stored open positions of only that market.
+	err = db.PersistOpenPositions(ctx, gspc, []*position.Position{short})
+	assert.NoError(t, err)
+	positions, err = db.QueryOpenPositions(gspc)
+	assert.NoError(t, err)
+	assert.Equal(t, len(positions), 1)
+	assert.Equal(t, positions[0].ID, short.ID)
+
+	positions, err = db.QueryOpenPositions(ixic)
+	assert.NoError(t, err)
+	assert.Equal(t, len(positions), 1)
+
+	// Ensure stored open positions survive reopening the database.
+	err = db.Close()
+	assert.NoError(t, err)
+	db, err = NewSQLite(ctx, cfg)
+	assert.NoError(t, err)
+	positions, err = db.QueryOpenPositions(gspc)
+	assert.NoError(t, err)
+	assert.Equal(t, len(positions), 1)
+	assert.Equal(t, positions[0].Direction, shared.Short)
+
+	err = db.Close()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,184 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dnldd/entry/position"
+	"github.com/dnldd/entry/shared"
+	"github.com/rs/zerolog"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	// sqliteDriver is the registered name of the sqlite driver.
+	sqliteDriver = "sqlite"
+
+	// SQLite statements.
+	createClosedPositionTableSQLite = "CREATE TABLE IF NOT EXISTS position (id TEXT PRIMARY KEY, market TEXT NOT NULL, timeframe INTEGER, direction INTEGER, stoploss REAL, stoplosspointsrange REAL, pnlpercent REAL, entryprice REAL, entryreasons TEXT, exitprice REAL, exitreasons TEXT, status INTEGER, createdon INTEGER, closedon INTEGER)"
+	createClosedPositionIndexSQLite = "CREATE INDEX IF NOT EXISTS position_market_closedon ON position (market, closedon)"
+	persistClosedPositionSQLite     = "INSERT OR REPLACE INTO position (id, market, timeframe, direction, stoploss, stoplosspointsrange, pnlpercent, entryprice, entryreasons, exitprice, exitreasons, status, createdon, closedon) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?)"
+	queryClosedPositionsSQLite      = "SELECT id, market, timeframe, direction, stoploss, stoplosspointsrange, pnlpercent, entryprice, entryreasons, exitprice, exitreasons, status, createdon, closedon FROM position WHERE (? = '' OR market = ?) AND closedon >= ? ORDER BY closedon ASC"
+)
+
+// ClosedPositionQuerier defines the requirements for querying stored closed positions.
+type ClosedPositionQuerier interface {
+	// QueryClosedPositions returns the closed positions of the provided market closed on or after
+	// the provided time.
+	QueryClosedPositions(market string, since time.Time) ([]*position.Position, error)
+}
+
+// SQLiteConfig is the configuration for the sqlite database.
+type SQLiteConfig struct {
+	// Filepath is the filepath to the sqlite database file.
+	Filepath string
+	// Logger is the database logger.
+	Logger *zerolog.Logger
+}
+
+// Validate asserts the config sane inputs.
+func (cfg *SQLiteConfig) Validate() error {
+	var errs error
+
+	if cfg.Filepath == "" {
+		errs = errors.Join(errs, fmt.Errorf("sqlite filepath cannot be an empty string"))
+	}
+	if cfg.Logger == nil {
+		errs = errors.Join(errs, fmt.Errorf("logger cannot be nil"))
+	}
+
+	return errs
+}
+
+// SQLite represents a sqlite database connection.
+type SQLite struct {
+	cfg      *SQLiteConfig
+	db       *sql.DB
+	location *time.Location
+}
+
+// Ensure sqlite implements the PositionStorer and ClosedPositionQuerier interfaces.
+var _ PositionStorer = (*SQLite)(nil)
+var _ ClosedPositionQuerier = (*SQLite)(nil)
+
+// NewSQLite initializes a new sqlite database connection.
+func NewSQLite(ctx context.Context, cfg *SQLiteConfig) (*SQLite, error) {
+	err := cfg.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("validating sqlite config: %v", err)
+	}
+
+	loc, err := time.LoadLocation(shared.NewYorkLocation)
+	if err != nil {
+		return nil, fmt.Errorf("loading new york location: %v", err)
+	}
+
+	db, err := sql.Open(sqliteDriver, cfg.Filepath)
+	if err != nil {
+		return nil, fmt.Errorf("opening sqlite database: %w", err)
+	}
+
+	// Serialize writes through a single connection to avoid lock contention.
+	db.SetMaxOpenConns(1)
+
+	store := &SQLite{
+		cfg:      cfg,
+		db:       db,
+		location: loc,
+	}
+
+	err = store.bootstrap(ctx)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("bootstrapping sqlite database: %w", err)
+	}
+
+	return store, nil
+}
+
+// bootstrap idempotently creates the database schema.
+func (s *SQLite) bootstrap(ctx context.Context) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	stmts := []string{createClosedPositionTableSQLite, createClosedPositionIndexSQLite,
+		createLevelTableSQLite, createImbalanceTableSQLite, createOpenPositionTableSQLite}
+	for _, stmt := range stmts {
+		_, err := tx.ExecContext(ctx, stmt)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// PersistClosedPosition stores the provided closed position to the database.
+func (s *SQLite) PersistClosedPosition(ctx context.Context, pos *position.Position) error {
+	if pos == nil {
+		return fmt.Errorf("position cannot be nil")
+	}
+	if pos.ClosedOn.IsZero() {
+		return fmt.Errorf("position %s is not closed", pos.ID)
+	}
+
+	_, err := s.db.ExecContext(ctx, persistClosedPositionSQLite, pos.ID, pos.Market,
+		int(pos.Timeframe), int(pos.Direction), pos.StopLoss, pos.StopLossPointsRange,
+		pos.PNLPercent, pos.EntryPrice, pos.EntryReasons, pos.ExitPrice, pos.ExitReasons,
+		int(pos.Status), pos.CreatedOn.UnixNano(), pos.ClosedOn.UnixNano())
+	if err != nil {
+		return fmt.Errorf("persisting closed position %s: %w", pos.ID, err)
+	}
+
+	return nil
+}
+
+// QueryClosedPositions returns the closed positions of the provided market closed on or after
+// the provided time, ordered by their closing time. An empty market matches all markets.
+func (s *SQLite) QueryClosedPositions(market string, since time.Time) ([]*position.Position, error) {
+	rows, err := s.db.Query(queryClosedPositionsSQLite, market, market, since.UnixNano())
+	if err != nil {
+		return nil, fmt.Errorf("querying closed positions: %w", err)
+	}
+	defer rows.Close()
+
+	positions := make([]*position.Position, 0)
+	for rows.Next() {
+		var pos position.Position
+		var timeframe, direction, status int
+		var createdOn, closedOn int64
+
+		err := rows.Scan(&pos.ID, &pos.Market, &timeframe, &direction, &pos.StopLoss,
+			&pos.StopLossPointsRange, &pos.PNLPercent, &pos.EntryPrice, &pos.EntryReasons,
+			&pos.ExitPrice, &pos.ExitReasons, &status, &createdOn, &closedOn)
+		if err != nil {
+			return nil, fmt.Errorf("scanning closed position: %w", err)
+		}
+
+		pos.Timeframe = shared.Timeframe(timeframe)
+		pos.Direction = shared.Direction(direction)
+		pos.Status = position.PositionStatus(status)
+		pos.CreatedOn = time.Unix(0, createdOn).In(s.location)
+		pos.ClosedOn = time.Unix(0, closedOn).In(s.location)
+
+		positions = append(positions, &pos)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("iterating closed positions: %w", err)
+	}
+
+	return positions, nil
+}
+
+// Close closes the database connection.
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,176 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dnldd/entry/position"
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+	"github.com/rs/zerolog/log"
+)
+
+func TestSQLiteConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     SQLiteConfig
+		wantErr bool
+	}{
+		{
+			name:    "valid config",
+			cfg:     SQLiteConfig{Filepath: "positions.db", Logger: &log.Logger},
+			wantErr: false,
+		},
+		{
+			name:    "empty filepath",
+			cfg:     SQLiteConfig{Filepath: "", Logger: &log.Logger},
+			wantErr: true,
+		},
+		{
+			name:    "nil logger",
+			cfg:     SQLiteConfig{Filepath: "positions.db", Logger: nil},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.cfg.Validate()
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSQLite(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "positions.db")
+	cfg := &SQLiteConfig{
+		Filepath: path,
+		Logger:   &log.Logger,
+	}
+
+	// Ensure a sqlite database can be created.
+	db, err := NewSQLite(ctx, cfg)
+	assert.NoError(t, err)
+
+	// Ensure schema creation is idempotent.
+	err = db.bootstrap(ctx)
+	assert.NoError(t, err)
+
+	now, _, err := shared.NewYorkTime()
+	assert.NoError(t, err)
+	now = now.Truncate(time.Second)
+
+	gspc := "^GSPC"
+	ixic := "^IXIC"
+
+	positions := []*position.Position{
+		{
+			ID:                  "1",
+			Market:              gspc,
+			Timeframe:           shared.FiveMinute,
+			Direction:           shared.Long,
+			StopLoss:            8,
+			StopLossPointsRange: 2,
+			PNLPercent:          20,
+			EntryPrice:          10,
+			EntryReasons:        "strong volume",
+			ExitPrice:           12,
+			ExitReasons:         "price reversal at resistance",
+			Status:              position.Closed,
+			CreatedOn:           now.Add(-time.Hour * 50),
+			ClosedOn:            now.Add(-time.Hour * 49),
+		},
+		{
+			ID:                  "2",
+			Market:              gspc,
+			Timeframe:           shared.FiveMinute,
+			Direction:           shared.Short,
+			StopLoss:            12,
+			StopLossPointsRange: 2,
+			PNLPercent:          -20,
+			EntryPrice:          10,
+			EntryReasons:        "strong move",
+			ExitPrice:           12,
+			ExitReasons:         "stop loss hit",
+			Status:              position.StoppedOut,
+			CreatedOn:           now.Add(-time.Hour * 2),
+			ClosedOn:            now.Add(-time.Hour),
+		},
+		{
+			ID:                  "3",
+			Market:              ixic,
+			Timeframe:           shared.FiveMinute,
+			Direction:           shared.Long,
+			StopLoss:            98,
+			StopLossPointsRange: 2,
+			PNLPercent:          5,
+			EntryPrice:          100,
+			EntryReasons:        "high volume session",
+			ExitPrice:           105,
+			ExitReasons:         "price break below support",
+			Status:              position.Closed,
+			CreatedOn:           now.Add(-time.Minute * 30),
+			ClosedOn:            now,
+		},
+	}
+
+	// Ensure an open position cannot be persisted.
+	err = db.PersistClosedPosition(ctx, &position.Position{ID: "4", Market: gspc})
+	assert.Error(t, err)
+
+	// Ensure closed positions can be persisted.
+	for idx := range positions {
+		err = db.PersistClosedPosition(ctx, positions[idx])
+		assert.NoError(t, err)
+	}
+
+	// Ensure persisting an already stored position does not error.
+	err = db.PersistClosedPosition(ctx, positions[0])
+	assert.NoError(t, err)
+
+	// Ensure closed positions can be queried across all markets.
+	set, err := db.QueryClosedPositions("", time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, len(set), 3)
+	assert.Equal(t, set[0].ID, "1")
+	assert.Equal(t, set[1].ID, "2")
+	assert.Equal(t, set[2].ID, "3")
+
+	// Ensure closed positions can be filtered by market.
+	set, err = db.QueryClosedPositions(gspc, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, len(set), 2)
+	assert.Equal(t, set[0], positions[0])
+	assert.Equal(t, set[1], positions[1])
+
+	// Ensure closed positions can be filtered by market and closing time.
+	set, err = db.QueryClosedPositions(gspc, now.Add(-time.Hour*24))
+	assert.NoError(t, err)
+	assert.Equal(t, len(set), 1)
+	assert.Equal(t, set[0], positions[1])
+
+	// Ensure querying an untracked market returns no positions.
+	set, err = db.QueryClosedPositions("^DJI", time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, len(set), 0)
+
+	// Ensure persisted positions survive reopening the database.
+	err = db.Close()
+	assert.NoError(t, err)
+
+	db, err = NewSQLite(ctx, cfg)
+	assert.NoError(t, err)
+	defer db.Close()
+
+	set, err = db.QueryClosedPositions(ixic, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, len(set), 1)
+	assert.Equal(t, set[0], positions[2])
+}
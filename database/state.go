@@ -0,0 +1,183 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+)
+
+const (
+	// SQLite statements.
+	createLevelTableSQLite     = "CREATE TABLE IF NOT EXISTS level (market TEXT NOT NULL, seq INTEGER NOT NULL, price REAL, kind INTEGER, reversals INTEGER, breaks INTEGER, breaking INTEGER, invalidated INTEGER, orderblock INTEGER, manual INTEGER, source INTEGER, PRIMARY KEY (market, seq))"
+	createImbalanceTableSQLite = "CREATE TABLE IF NOT EXISTS imbalance (market TEXT NOT NULL, seq INTEGER NOT NULL, timeframe INTEGER, high REAL, midpoint REAL, low REAL, sentiment INTEGER, gapratio REAL, purged INTEGER, invalidated INTEGER, fill REAL, date INTEGER, orderblock INTEGER, orderblocktimeframe INTEGER, orderblockhigh REAL, orderblocklow REAL, orderblocksentiment INTEGER, orderblockdate INTEGER, PRIMARY KEY (market, seq))"
+	deleteLevelsSQLite         = "DELETE FROM level WHERE market = ?"
+	deleteImbalancesSQLite     = "DELETE FROM imbalance WHERE market = ?"
+	persistLevelSQLite         = "INSERT INTO level (market, seq, price, kind, reversals, breaks, breaking, invalidated, orderblock, manual, source) VALUES (?,?,?,?,?,?,?,?,?,?,?)"
+	persistImbalanceSQLite     = "INSERT INTO imbalance (market, seq, timeframe, high, midpoint, low, sentiment, gapratio, purged, invalidated, fill, date, orderblock, orderblocktimeframe, orderblockhigh, orderblocklow, orderblocksentiment, orderblockdate) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)"
+	queryLevelsSQLite          = "SELECT price, kind, reversals, breaks, breaking, invalidated, orderblock, manual, source FROM level WHERE market = ? ORDER BY seq ASC"
+	queryImbalancesSQLite      = "SELECT timeframe, high, midpoint, low, sentiment, gapratio, purged, invalidated, fill, date, orderblock, orderblocktimeframe, orderblockhigh, orderblocklow, orderblocksentiment, orderblockdate FROM imbalance WHERE market = ? ORDER BY seq ASC"
+)
+
+// PriceActionStateStorer defines the requirements for storing the level and imbalance state of markets.
+type PriceActionStateStorer interface {
+	// PersistPriceActionState replaces the stored levels and imbalances of the provided market.
+	PersistPriceActionState(ctx context.Context, market string, levels []*shared.Level, imbalances []*shared.Imbalance) error
+	// QueryPriceActionState returns the stored levels and imbalances of the provided market,
+	// ordered from the oldest to the newest.
+	QueryPriceActionState(market string) ([]*shared.Level, []*shared.Imbalance, error)
+}
+
+// Ensure sqlite implements the PriceActionStateStorer interface.
+var _ PriceActionStateStorer = (*SQLite)(nil)
+
+// PersistPriceActionState replaces the stored levels and imbalances of the provided market.
+func (s *SQLite) PersistPriceActionState(ctx context.Context, market string, levels []*shared.Level, imbalances []*shared.Imbalance) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting %s state transaction: %w", market, err)
+	}
+
+	for _, stmt := range []string{deleteLevelsSQLite, deleteImbalancesSQLite} {
+		_, err := tx.ExecContext(ctx, stmt, market)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("clearing %s state: %w", market, err)
+		}
+	}
+
+	for idx, level := range levels {
+		_, err := tx.ExecContext(ctx, persistLevelSQLite, market, idx, level.Price, int(level.Kind),
+			level.Reversals.Load(), level.Breaks.Load(), level.Breaking.Load(), level.Invalidated.Load(),
+			level.OrderBlock, level.Manual, int(level.Source))
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("persisting %s level @ %.2f: %w", market, level.Price, err)
+		}
+	}
+
+	for idx, imb := range imbalances {
+		// Imbalances without an order block store a zero order block.
+		var orderBlock shared.OrderBlockZone
+		if imb.OrderBlock != nil {
+			orderBlock = *imb.OrderBlock
+		}
+
+		_, err := tx.ExecContext(ctx, persistImbalanceSQLite, market, idx, int(imb.Timeframe), imb.High,
+			imb.Midpoint, imb.Low, int(imb.Sentiment), imb.GapRatio, imb.Purged.Load(),
+			imb.Invalidated.Load(), imb.Fill.Load(), imb.Date.UnixNano(), imb.OrderBlock != nil,
+			int(orderBlock.Timeframe), orderBlock.High, orderBlock.Low, int(orderBlock.Sentiment),
+			orderBlock.Date.UnixNano())
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("persisting %s imbalance %.2f - %.2f: %w", market, imb.High, imb.Low, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// QueryPriceActionState returns the stored levels and imbalances of the provided market,
+// ordered from the oldest to the newest.
+func (s *SQLite) QueryPriceActionState(market string) ([]*shared.Level, []*shared.Imbalance, error) {
+	levels, err := s.queryLevels(market)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	imbalances, err := s.queryImbalances(market)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return levels, imbalances, nil
+}
+
+// queryLevels returns the stored levels of the provided market.
+func (s *SQLite) queryLevels(market string) ([]*shared.Level, error) {
+	rows, err := s.db.Query(queryLevelsSQLite, market)
+	if err != nil {
+		return nil, fmt.Errorf("querying levels: %w", err)
+	}
+	defer rows.Close()
+
+	levels := make([]*shared.Level, 0)
+	for rows.Next() {
+		level := &shared.Level{Market: market}
+		var kind, source int
+		var reversals, breaks uint32
+		var breaking, invalidated bool
+
+		err := rows.Scan(&level.Price, &kind, &reversals, &breaks, &breaking, &invalidated,
+			&level.OrderBlock, &level.Manual, &source)
+		if err != nil {
+			return nil, fmt.Errorf("scanning level: %w", err)
+		}
+
+		level.Kind = shared.LevelKind(kind)
+		level.Source = shared.LevelSource(source)
+		level.Reversals.Store(reversals)
+		level.Breaks.Store(breaks)
+		level.Breaking.Store(breaking)
+		level.Invalidated.Store(invalidated)
+
+		levels = append(levels, level)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("iterating levels: %w", err)
+	}
+
+	return levels, nil
+}
+
+// queryImbalances returns the stored imbalances of the provided market.
+func (s *SQLite) queryImbalances(market string) ([]*shared.Imbalance, error) {
+	rows, err := s.db.Query(queryImbalancesSQLite, market)
+	if err != nil {
+		return nil, fmt.Errorf("querying imbalances: %w", err)
+	}
+	defer rows.Close()
+
+	imbalances := make([]*shared.Imbalance, 0)
+	for rows.Next() {
+		var timeframe, sentiment, orderBlockTimeframe, orderBlockSentiment int
+		var high, midpoint, low, gapRatio, fill, orderBlockHigh, orderBlockLow float64
+		var purged, invalidated, orderBlock bool
+		var date, orderBlockDate int64
+
+		err := rows.Scan(&timeframe, &high, &midpoint, &low, &sentiment, &gapRatio, &purged,
+			&invalidated, &fill, &date, &orderBlock, &orderBlockTimeframe, &orderBlockHigh,
+			&orderBlockLow, &orderBlockSentiment, &orderBlockDate)
+		if err != nil {
+			return nil, fmt.Errorf("scanning imbalance: %w", err)
+		}
+
+		imb := shared.NewImbalance(market, shared.Timeframe(timeframe), high, midpoint, low,
+			shared.Sentiment(sentiment), gapRatio, time.Unix(0, date).In(s.location))
+		imb.Purged.Store(purged)
+		imb.Invalidated.Store(invalidated)
+		imb.Fill.Store(fill)
+		if orderBlock {
+			imb.OrderBlock = &shared.OrderBlockZone{
+				Market:    market,
+				Timeframe: shared.Timeframe(orderBlockTimeframe),
+				High:      orderBlockHigh,
+				Low:       orderBlockLow,
+				Sentiment: shared.Sentiment(orderBlockSentiment),
+				Date:      time.Unix(0, orderBlockDate).In(s.location),
+			}
+		}
+
+		imbalances = append(imbalances, imb)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("iterating imbalances: %w", err)
+	}
+
+	return imbalances, nil
+}
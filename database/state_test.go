@@ -0,0 +1,135 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+	"github.com/rs/zerolog/log"
+)
+
+func TestSQLitePriceActionState(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "state.db")
+	cfg := &SQLiteConfig{
+		Filepath: path,
+		Logger:   &log.Logger,
+	}
+
+	db, err := NewSQLite(ctx, cfg)
+	assert.NoError(t, err)
+
+	gspc := "^GSPC"
+	ixic := "^IXIC"
+
+	// Ensure querying a market without stored state returns nothing.
+	levels, imbalances, err := db.QueryPriceActionState(gspc)
+	assert.NoError(t, err)
+	assert.Equal(t, len(levels), 0)
+	assert.Equal(t, len(imbalances), 0)
+
+	now, _, err := shared.NewYorkTime()
+	assert.NoError(t, err)
+	now = now.Truncate(time.Second)
+
+	support := shared.NewLevel(gspc, 10, 12)
+	support.ApplyPriceReaction(shared.Reversal)
+	support.ApplyPriceReaction(shared.Reversal)
+	support.Source = shared.LondonLevel
+	resistance := shared.NewLevel(gspc, 20, 15)
+	resistance.ApplyPriceReaction(shared.Break)
+	resistance.Breaks.Store(1)
+	invalidated := shared.NewLevel(gspc, 30, 25)
+	invalidated.Invalidated.Store(true)
+	orderBlock := &shared.OrderBlockZone{Market: gspc, Timeframe: shared.FiveMinute, High: 14,
+		Low: 12, Sentiment: shared.Bullish, Date: now.Add(-time.Minute * 5)}
+	orderBlockLevel := shared.NewOrderBlockLevel(orderBlock)
+	manual := shared.NewManualLevel(gspc, 40, shared.Resistance)
+
+	bullish := shared.NewImbalance(gspc, shared.FiveMinute, 18, 16, 14, shared.Bullish, 0.7, now)
+	bullish.Purged.Store(true)
+	bullish.Fill.Store(100)
+	bullish.OrderBlock = orderBlock
+	bearish := shared.NewImbalance(gspc, shared.OneHour, 28, 26, 24, shared.Bearish, 0.5, now.Add(time.Hour))
+
+	// Ensure the state of a market can be persisted.
+	err = db.PersistPriceActionState(ctx, gspc, []*shared.Level{support, resistance, invalidated,
+		orderBlockLevel, manual}, []*shared.Imbalance{bullish, bearish})
+	assert.NoError(t, err)
+	err = db.PersistPriceActionState(ctx, ixic, []*shared.Level{shared.NewLevel(ixic, 5, 6)}, nil)
+	assert.NoError(t, err)
+
+	// Ensure stored levels and their state are returned in order.
+	levels, imbalances, err = db.QueryPriceActionState(gspc)
+	assert.NoError(t, err)
+	assert.Equal(t, len(levels), 5)
+	assert.Equal(t, levels[0].Market, gspc)
+	assert.Equal(t, levels[0].Price, float64(10))
+	assert.Equal(t, levels[0].Kind, shared.Support)
+	assert.Equal(t, levels[0].Reversals.Load(), uint32(2))
+	assert.Equal(t, levels[0].Source, shared.LondonLevel)
+	assert.Equal(t, levels[1].Source, shared.UnsourcedLevel)
+	assert.Equal(t, levels[1].Price, float64(20))
+	assert.Equal(t, levels[1].Kind, shared.Resistance)
+	assert.True(t, levels[1].Breaking.Load())
+	assert.Equal(t, levels[1].Breaks.Load(), uint32(1))
+	assert.True(t, levels[2].IsInvalidated())
+	assert.False(t, levels[2].OrderBlock)
+	assert.True(t, levels[3].OrderBlock)
+	assert.Equal(t, levels[3].Price, orderBlockLevel.Price)
+	assert.False(t, levels[3].Manual)
+	assert.True(t, levels[4].Manual)
+	assert.Equal(t, levels[4].Kind, shared.Resistance)
+
+	// Ensure stored imbalances and their state are returned in order.
+	assert.Equal(t, len(imbalances), 2)
+	assert.Equal(t, imbalances[0].Market, gspc)
+	assert.Equal(t, imbalances[0].Timeframe, shared.FiveMinute)
+	assert.Equal(t, imbalances[0].High, float64(18))
+	assert.Equal(t, imbalances[0].Midpoint, float64(16))
+	assert.Equal(t, imbalances[0].Low, float64(14))
+	assert.Equal(t, imbalances[0].Sentiment, shared.Bullish)
+	assert.Equal(t, imbalances[0].GapRatio, 0.7)
+	assert.True(t, imbalances[0].Purged.Load())
+	assert.False(t, imbalances[0].Invalidated.Load())
+	assert.Equal(t, imbalances[0].FillPercent(), float64(100))
+	assert.True(t, imbalances[0].Date.Equal(now))
+	assert.Equal(t, imbalances[0].OrderBlock.Market, gspc)
+	assert.Equal(t, imbalances[0].OrderBlock.Timeframe, orderBlock.Timeframe)
+	assert.Equal(t, imbalances[0].OrderBlock.High, orderBlock.High)
+	assert.Equal(t, imbalances[0].OrderBlock.Low, orderBlock.Low)
+	assert.Equal(t, imbalances[0].OrderBlock.Sentiment, orderBlock.Sentiment)
+	assert.True(t, imbalances[0].OrderBlock.Date.Equal(orderBlock.Date))
+	assert.Equal(t, imbalances[1].Timeframe, shared.OneHour)
+	assert.Equal(t, imbalances[1].Sentiment, shared.Bearish)
+	assert.False(t, imbalances[1].Purged.Load())
+	assert.True(t, imbalances[1].OrderBlock == nil)
+
+	// Ensure persisting replaces the stored state of only the provided market.
+	err = db.PersistPriceActionState(ctx, gspc, []*shared.Level{resistance}, nil)
+	assert.NoError(t, err)
+	levels, imbalances, err = db.QueryPriceActionState(gspc)
+	assert.NoError(t, err)
+	assert.Equal(t, len(levels), 1)
+	assert.Equal(t, levels[0].Price, float64(20))
+	assert.Equal(t, len(imbalances), 0)
+
+	levels, _, err = db.QueryPriceActionState(ixic)
+	assert.NoError(t, err)
+	assert.Equal(t, len(levels), 1)
+
+	// Ensure the stored state survives reopening the database.
+	err = db.Close()
+	assert.NoError(t, err)
+	db, err = NewSQLite(ctx, cfg)
+	assert.NoError(t, err)
+	defer db.Close()
+
+	levels, _, err = db.QueryPriceActionState(gspc)
+	assert.NoError(t, err)
+	assert.Equal(t, len(levels), 1)
+	assert.True(t, levels[0].Breaking.Load())
+}
@@ -0,0 +1,176 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+)
+
+const (
+	// Audit decisions.
+	entryDecision = "entry"
+	exitDecision  = "exit"
+	noDecision    = "none"
+)
+
+// AuditCandle represents an audited candle metadata snapshot.
+type AuditCandle struct {
+	Kind      string    `json:"kind"`
+	Sentiment string    `json:"sentiment"`
+	Momentum  string    `json:"momentum"`
+	Volume    float64   `json:"volume"`
+	Engulfing bool      `json:"engulfing"`
+	Open      float64   `json:"open"`
+	High      float64   `json:"high"`
+	Low       float64   `json:"low"`
+	Close     float64   `json:"close"`
+	Date      time.Time `json:"date"`
+}
+
+// AuditRecord represents the confluence breakdown and outcome of an evaluated reaction.
+type AuditRecord struct {
+	CorrelationID  string            `json:"correlationID"`
+	Market         string            `json:"market"`
+	Timeframe      string            `json:"timeframe"`
+	Focus          string            `json:"focus"`
+	Reaction       string            `json:"reaction"`
+	LevelKind      string            `json:"levelKind"`
+	Price          float64           `json:"price"`
+	Reasons        map[string]uint32 `json:"reasons"`
+	ReasonOrder    []string          `json:"reasonOrder"`
+	Confluence     uint32            `json:"confluence"`
+	EntryThreshold uint32            `json:"entryThreshold"`
+	ExitThreshold  uint32            `json:"exitThreshold"`
+	Candles        []AuditCandle     `json:"candles"`
+	AverageVolume  float64           `json:"averageVolume"`
+	Skew           string            `json:"skew"`
+	Decision       string            `json:"decision"`
+	Direction      string            `json:"direction,omitempty"`
+	Date           time.Time         `json:"date"`
+}
+
+// newAuditRecord initializes a new audit record for the provided reaction.
+func newAuditRecord(reaction *shared.ReactionAtFocus, meta []*shared.CandleMetadata, entryThreshold uint32, exitThreshold uint32) *AuditRecord {
+	candles := make([]AuditCandle, 0, len(meta))
+	for idx := range meta {
+		candleMeta := meta[idx]
+		candles = append(candles, AuditCandle{
+			Kind:      candleMeta.Kind.String(),
+			Sentiment: candleMeta.Sentiment.String(),
+			Momentum:  candleMeta.Momentum.String(),
+			Volume:    candleMeta.Volume,
+			Engulfing: candleMeta.Engulfing,
+			Open:      candleMeta.Open,
+			High:      candleMeta.High,
+			Low:       candleMeta.Low,
+			Close:     candleMeta.Close,
+			Date:      candleMeta.Date,
+		})
+	}
+
+	return &AuditRecord{
+		CorrelationID:  reaction.CorrelationID,
+		Market:         reaction.Market,
+		Timeframe:      reaction.Timeframe.String(),
+		Focus:          reaction.Focus.String(),
+		Reaction:       reaction.Reaction.String(),
+		LevelKind:      reaction.LevelKind.String(),
+		Price:          reaction.CurrentPrice,
+		Reasons:        make(map[string]uint32),
+		EntryThreshold: entryThreshold,
+		ExitThreshold:  exitThreshold,
+		Candles:        candles,
+		Decision:       noDecision,
+		Date:           reaction.CreatedOn,
+	}
+}
+
+// setEvaluation records the provided confluence breakdown of the reaction, ordering its
+// reasons by the provided priority.
+func (r *AuditRecord) setEvaluation(reasons map[shared.Reason]uint32, averageVolume float64, priority shared.ReasonPriority) {
+	for reason, weight := range reasons {
+		r.Reasons[reason.String()] = weight
+	}
+	sorted := priority.Sort(extractReasons(reasons))
+	r.ReasonOrder = make([]string, 0, len(sorted))
+	for idx := range sorted {
+		r.ReasonOrder = append(r.ReasonOrder, sorted[idx].String())
+	}
+	r.AverageVolume = averageVolume
+}
+
+// setDecision records the signal generated for the reaction.
+func (r *AuditRecord) setDecision(decision string, direction shared.Direction) {
+	r.Decision = decision
+	r.Direction = direction.String()
+}
+
+// AuditWriter appends audit records as json lines to the underlying writer.
+type AuditWriter struct {
+	writer  io.Writer
+	encoder *json.Encoder
+	mtx     sync.Mutex
+}
+
+// NewAuditWriter initializes a new audit writer.
+func NewAuditWriter(writer io.Writer) *AuditWriter {
+	return &AuditWriter{
+		writer:  writer,
+		encoder: json.NewEncoder(writer),
+	}
+}
+
+// OpenAuditLog opens the audit log at the provided filepath for appending, creating it if
+// it does not exist.
+func OpenAuditLog(filepath string) (*AuditWriter, error) {
+	file, err := os.OpenFile(filepath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("opening audit log: %v", err)
+	}
+
+	return NewAuditWriter(file), nil
+}
+
+// Write appends the provided record as a json line.
+func (w *AuditWriter) Write(record *AuditRecord) error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	err := w.encoder.Encode(record)
+	if err != nil {
+		return fmt.Errorf("encoding audit record: %v", err)
+	}
+
+	return nil
+}
+
+// Close closes the underlying writer if it is closable.
+func (w *AuditWriter) Close() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	closer, ok := w.writer.(io.Closer)
+	if !ok {
+		return nil
+	}
+
+	return closer.Close()
+}
+
+// writeAudit writes the provided audit record, logging write errors. Records are not written
+// if auditing is disabled.
+func (e *Engine) writeAudit(record *AuditRecord) {
+	if e.cfg.Audit == nil {
+		return
+	}
+
+	err := e.cfg.Audit.Write(record)
+	if err != nil {
+		e.cfg.Logger.Error().Msgf("writing %s audit record for %s: %v", record.Reaction, record.Market, err)
+	}
+}
@@ -0,0 +1,115 @@
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestAuditWriter(t *testing.T) {
+	avgVolume := float64(4)
+	asiaSessionTime, _ := generateSessionTimes(t)
+	candleMeta := []*shared.CandleMetadata{
+		{
+			Kind:      shared.Marubozu,
+			Sentiment: shared.Bullish,
+			Momentum:  shared.Medium,
+			Volume:    float64(5),
+			High:      9,
+			Low:       6,
+			Date:      asiaSessionTime,
+		},
+		{
+			Kind:      shared.Marubozu,
+			Sentiment: shared.Bullish,
+			Momentum:  shared.High,
+			Volume:    float64(8),
+			High:      14,
+			Low:       9,
+			Date:      asiaSessionTime,
+		},
+	}
+
+	marketSkew := shared.LongSkewed
+	eng, entrySignals, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+	var buf bytes.Buffer
+	eng.cfg.Audit = NewAuditWriter(&buf)
+
+	market := "^GSPC"
+	reversal := shared.ReactionAtFocus{
+		Market:        market,
+		LevelKind:     shared.Support,
+		CurrentPrice:  float64(14),
+		Timeframe:     shared.FiveMinute,
+		PriceMovement: []shared.PriceMovement{shared.Above, shared.Above, shared.Above, shared.Above},
+		Reaction:      shared.Reversal,
+		CreatedOn:     asiaSessionTime,
+	}
+	breakReaction := shared.ReactionAtFocus{
+		Market:        market,
+		LevelKind:     shared.Resistance,
+		CurrentPrice:  float64(14),
+		Timeframe:     shared.FiveMinute,
+		PriceMovement: []shared.PriceMovement{shared.Below, shared.Below, shared.Above, shared.Above},
+		Reaction:      shared.Break,
+		CreatedOn:     asiaSessionTime,
+	}
+
+	// Ensure an evaluated reversal signalling an entry is audited.
+	err := eng.evaluatePriceReversalStrength(context.Background(), &reversal, nil, candleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
+	assert.NoError(t, err)
+	<-entrySignals
+
+	// Ensure an evaluated break below the confluence threshold is audited.
+	err = eng.evaluateBreakStrength(context.Background(), &breakReaction, nil, candleMeta, 100, 100)
+	assert.NoError(t, err)
+
+	// Ensure one line is written per evaluation.
+	records := []AuditRecord{}
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var record AuditRecord
+		err := json.Unmarshal(scanner.Bytes(), &record)
+		assert.NoError(t, err)
+		records = append(records, record)
+	}
+	assert.Equal(t, len(records), 2)
+
+	// Ensure the reversal record carries the full confluence breakdown and decision.
+	record := records[0]
+	assert.Equal(t, record.Market, market)
+	assert.Equal(t, record.Timeframe, shared.FiveMinute.String())
+	assert.Equal(t, record.Focus, shared.LevelFocus.String())
+	assert.Equal(t, record.Reaction, shared.Reversal.String())
+	assert.Equal(t, record.AverageVolume, avgVolume)
+	assert.Equal(t, record.Skew, shared.LongSkewed.String())
+	assert.Equal(t, record.Decision, entryDecision)
+	assert.Equal(t, record.Direction, shared.Long.String())
+	assert.Equal(t, len(record.Candles), len(candleMeta))
+	assert.Equal(t, record.Candles[1].Kind, shared.Marubozu.String())
+	assert.Equal(t, record.Reasons[shared.ReversalAtSupport.String()], uint32(1))
+	assert.Equal(t, record.Reasons[shared.StrongMove.String()], uint32(2))
+	assert.Equal(t, record.Reasons[shared.StrongVolume.String()], uint32(3))
+	var total uint32
+	for _, weight := range record.Reasons {
+		total += weight
+	}
+	assert.Equal(t, total, record.Confluence)
+
+	// Ensure the reversal record orders its reasons by priority.
+	assert.Equal(t, record.ReasonOrder, []string{shared.StrongMove.String(),
+		shared.ReversalAtSupport.String(), shared.StrongVolume.String()})
+
+	// Ensure the break record carries no decision.
+	record = records[1]
+	assert.Equal(t, record.Reaction, shared.Break.String())
+	assert.Equal(t, record.EntryThreshold, uint32(100))
+	assert.Equal(t, record.Decision, noDecision)
+	assert.Equal(t, record.Direction, "")
+	assert.Equal(t, record.Skew, "")
+}
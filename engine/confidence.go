@@ -0,0 +1,127 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dnldd/entry/shared"
+)
+
+const (
+	// confluenceWeight is the default weight of engine confluence in signal confidence.
+	confluenceWeight = float64(0.5)
+	// levelQualityWeight is the default weight of level quality in signal confidence.
+	levelQualityWeight = float64(0.25)
+	// trendAlignmentWeight is the default weight of trend alignment in signal confidence.
+	trendAlignmentWeight = float64(0.25)
+	// provenLevelReversals is the number of reversals that renders a level proven.
+	provenLevelReversals = float64(3)
+	// maxLevelBreaks is the number of breaks that renders a level void.
+	maxLevelBreaks = float64(3)
+	// neutralConfidence is the confidence component used when a factor cannot be evaluated.
+	neutralConfidence = float64(0.5)
+	// maxConfidence is the upper bound of signal confidence.
+	maxConfidence = float64(100)
+)
+
+// ConfidenceWeights represents the weighting of the factors combined into signal confidence.
+type ConfidenceWeights struct {
+	// Confluence is the weight of engine confluence relative to the signal threshold.
+	Confluence float64
+	// LevelQuality is the weight of the strength and proven count of the reaction's level.
+	LevelQuality float64
+	// TrendAlignment is the weight of the signal direction's alignment with the higher
+	// timeframe trend.
+	TrendAlignment float64
+}
+
+// DefaultConfidenceWeights returns the default confidence weights.
+func DefaultConfidenceWeights() *ConfidenceWeights {
+	return &ConfidenceWeights{
+		Confluence:     confluenceWeight,
+		LevelQuality:   levelQualityWeight,
+		TrendAlignment: trendAlignmentWeight,
+	}
+}
+
+// Validate asserts the confidence weights are sane.
+func (w *ConfidenceWeights) Validate() error {
+	var errs error
+
+	if w.Confluence < 0 {
+		errs = errors.Join(errs, fmt.Errorf("confluence weight cannot be negative"))
+	}
+	if w.LevelQuality < 0 {
+		errs = errors.Join(errs, fmt.Errorf("level quality weight cannot be negative"))
+	}
+	if w.TrendAlignment < 0 {
+		errs = errors.Join(errs, fmt.Errorf("trend alignment weight cannot be negative"))
+	}
+	if w.Confluence+w.LevelQuality+w.TrendAlignment <= 0 {
+		errs = errors.Join(errs, fmt.Errorf("confidence weights must sum to a positive value"))
+	}
+
+	return errs
+}
+
+// Score combines the provided confluence, level quality and trend alignment into a
+// normalized confidence between 0 and 100.
+//
+// Confluence is scored relative to the threshold it was confirmed against, see
+// shared.ConfluenceConfidence, and is scored neutral without a threshold. Level quality and
+// trend alignment are expected in [0, 1].
+func (w *ConfidenceWeights) Score(confluence uint32, threshold uint32, levelQuality float64, trendAlignment float64) float64 {
+	total := w.Confluence + w.LevelQuality + w.TrendAlignment
+	if total <= 0 {
+		return 0
+	}
+
+	confluenceScore := neutralConfidence
+	if threshold > 0 {
+		confluenceScore = shared.ConfluenceConfidence(confluence, threshold)
+	}
+
+	levelQuality = max(min(levelQuality, 1), 0)
+	trendAlignment = max(min(trendAlignment, 1), 0)
+
+	score := (w.Confluence*confluenceScore + w.LevelQuality*levelQuality +
+		w.TrendAlignment*trendAlignment) / total
+
+	return max(min(score*maxConfidence, maxConfidence), 0)
+}
+
+// levelQuality scores the provided level in [0, 1] by its proven reversals, discounted by
+// its breaks. Reactions without a level are scored neutral.
+func levelQuality(level *shared.Level) float64 {
+	if level == nil {
+		return neutralConfidence
+	}
+
+	proven := min(float64(level.Reversals.Load()), provenLevelReversals) / provenLevelReversals
+	broken := min(float64(level.Breaks.Load()), maxLevelBreaks) / maxLevelBreaks
+
+	return (proven + (1 - broken)) / 2
+}
+
+// trendAlignment scores the alignment of the provided direction with the provided trend in [0, 1].
+func trendAlignment(trend shared.Trend, direction shared.Direction) float64 {
+	var alignment float64
+	switch trend {
+	case shared.StrongBullishTrend:
+		alignment = 1
+	case shared.MildBullishTrend:
+		alignment = 0.75
+	case shared.MildBearishTrend:
+		alignment = 0.25
+	case shared.StrongBearishTrend:
+		alignment = 0
+	default:
+		return neutralConfidence
+	}
+
+	if direction == shared.Short {
+		alignment = 1 - alignment
+	}
+
+	return alignment
+}
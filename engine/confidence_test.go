@@ -0,0 +1,122 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestConfidenceWeightsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights *ConfidenceWeights
+		wantErr bool
+	}{
+		{"default weights", DefaultConfidenceWeights(), false},
+		{"single factor", &ConfidenceWeights{Confluence: 1}, false},
+		{"negative confluence weight", &ConfidenceWeights{Confluence: -1, LevelQuality: 1}, true},
+		{"negative level quality weight", &ConfidenceWeights{Confluence: 1, LevelQuality: -1}, true},
+		{"negative trend alignment weight", &ConfidenceWeights{Confluence: 1, TrendAlignment: -1}, true},
+		{"zero weights", &ConfidenceWeights{}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.weights.Validate()
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestConfidenceScore(t *testing.T) {
+	weights := DefaultConfidenceWeights()
+
+	tests := []struct {
+		name           string
+		weights        *ConfidenceWeights
+		confluence     uint32
+		threshold      uint32
+		levelQuality   float64
+		trendAlignment float64
+		want           float64
+	}{
+		{"maximum confidence", weights, 12, 6, 1, 1, 100},
+		{"minimum confidence", weights, 0, 6, 0, 0, 0},
+		{"threshold confluence, neutral factors", weights, 6, 6, 0.5, 0.5, 50},
+		{"confluence capped at twice the threshold", weights, 24, 6, 1, 1, 100},
+		{"zero threshold scores confluence neutral", weights, 6, 0, 1, 1, 75},
+		{"out of range factors are clamped", weights, 12, 6, 4, -2, 75},
+		{"confluence only", &ConfidenceWeights{Confluence: 1}, 9, 6, 0, 0, 75},
+		{"zero weights", &ConfidenceWeights{}, 12, 6, 1, 1, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			score := test.weights.Score(test.confluence, test.threshold, test.levelQuality, test.trendAlignment)
+			assert.Equal(t, score, test.want)
+
+			// Ensure the confidence is within bounds.
+			assert.True(t, score >= 0 && score <= maxConfidence)
+
+			// Ensure the confidence is deterministic for a fixed input.
+			for range 5 {
+				assert.Equal(t, test.weights.Score(test.confluence, test.threshold,
+					test.levelQuality, test.trendAlignment), score)
+			}
+		})
+	}
+}
+
+func TestLevelQuality(t *testing.T) {
+	market := "^GSPC"
+
+	// Ensure reactions without a level are scored neutral.
+	assert.Equal(t, levelQuality(nil), neutralConfidence)
+
+	// Ensure a fresh level is scored neutral.
+	level := shared.NewLevel(market, 10, 12)
+	assert.Equal(t, levelQuality(level), float64(0.5))
+
+	// Ensure a proven level is scored higher.
+	level.Reversals.Store(3)
+	assert.Equal(t, levelQuality(level), float64(1))
+
+	// Ensure reversals beyond the proven count are capped.
+	level.Reversals.Store(10)
+	assert.Equal(t, levelQuality(level), float64(1))
+
+	// Ensure breaks discount the level quality.
+	level.Reversals.Store(0)
+	level.Breaks.Store(3)
+	assert.Equal(t, levelQuality(level), float64(0))
+}
+
+func TestTrendAlignment(t *testing.T) {
+	tests := []struct {
+		name      string
+		trend     shared.Trend
+		direction shared.Direction
+		want      float64
+	}{
+		{"long with strong bullish trend", shared.StrongBullishTrend, shared.Long, 1},
+		{"long with mild bullish trend", shared.MildBullishTrend, shared.Long, 0.75},
+		{"long with choppy trend", shared.ChoppyTrend, shared.Long, 0.5},
+		{"long against mild bearish trend", shared.MildBearishTrend, shared.Long, 0.25},
+		{"long against strong bearish trend", shared.StrongBearishTrend, shared.Long, 0},
+		{"short with strong bearish trend", shared.StrongBearishTrend, shared.Short, 1},
+		{"short against strong bullish trend", shared.StrongBullishTrend, shared.Short, 0},
+		{"short with choppy trend", shared.ChoppyTrend, shared.Short, 0.5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, trendAlignment(test.trend, test.direction), test.want)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dnldd/entry/shared"
+)
+
+// DirectionMode represents the directions entries are taken in for a market.
+type DirectionMode int
+
+const (
+	// Both takes entries in both directions.
+	Both DirectionMode = iota
+	// LongOnly only takes long entries.
+	LongOnly
+	// ShortOnly only takes short entries.
+	ShortOnly
+)
+
+// String stringifies the provided direction mode.
+func (m DirectionMode) String() string {
+	switch m {
+	case Both:
+		return "both"
+	case LongOnly:
+		return "long"
+	case ShortOnly:
+		return "short"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseDirectionMode parses the direction mode from the provided string. An empty string
+// defaults to Both.
+func ParseDirectionMode(mode string) (DirectionMode, error) {
+	switch mode {
+	case "", "both":
+		return Both, nil
+	case "long":
+		return LongOnly, nil
+	case "short":
+		return ShortOnly, nil
+	default:
+		return 0, fmt.Errorf("unknown direction mode provided: %s", mode)
+	}
+}
+
+// ParseDirectionModes parses per market direction modes from the provided market=mode entries.
+func ParseDirectionModes(entries []string) (map[string]DirectionMode, error) {
+	modes := make(map[string]DirectionMode, len(entries))
+	for idx := range entries {
+		market, value, ok := strings.Cut(entries[idx], "=")
+		if !ok || market == "" {
+			return nil, fmt.Errorf("invalid direction mode entry provided: %s", entries[idx])
+		}
+
+		mode, err := ParseDirectionMode(value)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s direction mode: %v", market, err)
+		}
+
+		modes[market] = mode
+	}
+
+	return modes, nil
+}
+
+// Allows checks whether entries in the provided direction are taken under the direction mode.
+func (m DirectionMode) Allows(direction shared.Direction) bool {
+	switch m {
+	case LongOnly:
+		return direction == shared.Long
+	case ShortOnly:
+		return direction == shared.Short
+	default:
+		return true
+	}
+}
+
+// directionAllowed checks whether entries in the provided direction are taken for the market
+// of the provided reaction, logging disallowed directions. Exits are not restricted by the
+// direction mode of a market.
+func (e *Engine) directionAllowed(reaction *shared.ReactionAtFocus, direction shared.Direction) bool {
+	mode := e.cfg.DirectionModes[reaction.Market]
+	if mode.Allows(direction) {
+		return true
+	}
+
+	e.reactionLogger(reaction.CorrelationID).Info().Msgf("skipping %s entry for %s, the market is %s only",
+		direction.String(), reaction.Market, mode.String())
+
+	return false
+}
@@ -0,0 +1,110 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestParseDirectionModes(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		want    map[string]DirectionMode
+		wantErr bool
+	}{
+		{"no entries", nil, map[string]DirectionMode{}, false},
+		{"valid entries", []string{"^GSPC=long", "^IXIC=short", "^DJI=both"},
+			map[string]DirectionMode{"^GSPC": LongOnly, "^IXIC": ShortOnly, "^DJI": Both}, false},
+		{"missing separator", []string{"^GSPC"}, nil, true},
+		{"missing market", []string{"=long"}, nil, true},
+		{"unknown mode", []string{"^GSPC=sideways"}, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			modes, err := ParseDirectionModes(test.entries)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, modes, test.want)
+		})
+	}
+}
+
+func TestDirectionMode(t *testing.T) {
+	avgVolume := float64(4)
+	asiaSessionTime, _ := generateSessionTimes(t)
+	bullishMeta := []*shared.CandleMetadata{
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 5, High: 9, Low: 6, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.High, Volume: 8, High: 14, Low: 9, Date: asiaSessionTime},
+	}
+	bearishMeta := []*shared.CandleMetadata{
+		{Kind: shared.Marubozu, Sentiment: shared.Bearish, Momentum: shared.Medium, Volume: 5, High: 14, Low: 11, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bearish, Momentum: shared.High, Volume: 8, High: 11, Low: 6, Date: asiaSessionTime},
+	}
+
+	market := "^GSPC"
+	supportReversal := shared.ReactionAtFocus{
+		Market:        market,
+		Timeframe:     shared.FiveMinute,
+		LevelKind:     shared.Support,
+		CurrentPrice:  14,
+		PriceMovement: []shared.PriceMovement{shared.Above, shared.Above, shared.Above, shared.Above},
+		Reaction:      shared.Reversal,
+		CreatedOn:     asiaSessionTime,
+	}
+
+	tests := []struct {
+		name      string
+		mode      DirectionMode
+		wantEntry bool
+	}{
+		{"both", Both, true},
+		{"long only", LongOnly, true},
+		{"short only", ShortOnly, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			marketSkew := shared.NeutralSkew
+			eng, entrySignals, _ := setupEngine(&avgVolume, bullishMeta, &marketSkew)
+			eng.cfg.DirectionModes = map[string]DirectionMode{market: test.mode}
+
+			err := eng.evaluatePriceReversalStrength(context.Background(), &supportReversal, nil, bullishMeta,
+				minLevelReversalConfluence, minLevelReversalConfluence)
+			assert.NoError(t, err)
+
+			// Ensure support reversals only produce long entries when longs are allowed.
+			if !test.wantEntry {
+				assert.Equal(t, len(entrySignals), 0)
+				return
+			}
+
+			assert.Equal(t, len(entrySignals), 1)
+			entry := <-entrySignals
+			assert.Equal(t, entry.Direction, shared.Long)
+		})
+	}
+
+	// Ensure reactions opposing a disallowed direction still exit existing positions.
+	resistanceReversal := supportReversal
+	resistanceReversal.LevelKind = shared.Resistance
+	resistanceReversal.CurrentPrice = 6
+	resistanceReversal.PriceMovement = []shared.PriceMovement{shared.Below, shared.Below, shared.Below, shared.Below}
+
+	marketSkew := shared.LongSkewed
+	eng, entrySignals, exitSignals := setupEngine(&avgVolume, bearishMeta, &marketSkew)
+	eng.cfg.DirectionModes = map[string]DirectionMode{market: LongOnly}
+
+	err := eng.evaluatePriceReversalStrength(context.Background(), &resistanceReversal, nil, bearishMeta,
+		minLevelReversalConfluence, minLevelReversalConfluence)
+	assert.NoError(t, err)
+	assert.Equal(t, len(entrySignals), 0)
+	assert.Equal(t, len(exitSignals), 1)
+}
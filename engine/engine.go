@@ -2,12 +2,18 @@ package engine
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"math"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/dnldd/entry/shared"
 	"github.com/rs/zerolog"
+	"go.uber.org/atomic"
 )
 
 const (
@@ -15,6 +21,9 @@ const (
 	bufferSize = 64
 	// maxWorkers is the maximum number of concurrent workers.
 	maxWorkers = 16
+	// reactionTimeout is the maximum time a worker spends handling a reaction before it is
+	// cancelled.
+	reactionTimeout = shared.TimeoutDuration * 8
 	// minLevelReversalConfluence is the minumum required confluence to confirm a level reversal.
 	minLevelReversalConfluence = 6
 	// minBreakConfluence is the minumum required confluence to confirm a level break.
@@ -27,108 +36,783 @@ const (
 	minImbalanceReversalConfluence = 6
 	// minImbalanceBreakConfluence is the minumum required confluence to confirm a imbalance break.
 	minImbalanceBreakConfluence = 6
+	// freshImbalanceFill is the maximum fill percentage of a largely unfilled imbalance.
+	freshImbalanceFill = float64(25)
+	// partialImbalanceFill is the maximum fill percentage of an imbalance still considered
+	// open enough to react at.
+	partialImbalanceFill = float64(50)
+	// maxStackedImbalanceConfluence is the maximum confluence awarded for imbalances stacked
+	// at a reacted to imbalance.
+	maxStackedImbalanceConfluence = uint32(2)
+	// defaultVWAPStretch is the default number of standard deviations price must be stretched
+	// from the vwap line by for reversals to favour mean reversion.
+	defaultVWAPStretch = float64(2)
 	// minAverageVolumePercent is the minimum percentage above average volume to be considered
 	// substantive.
 	minAverageVolumePercent = float64(0.3)
-	// stopLossBuffer is buffer for setting stoplosses in points.
+	// stopLossPointsBuffer is the default buffer for setting stoplosses in points.
 	stopLossPointsBuffer = float64(1)
 )
 
+// Thresholds represents the confluence thresholds used in evaluating reactions.
+type Thresholds struct {
+	// LevelReversal is the minimum required confluence to confirm a level reversal.
+	LevelReversal uint32
+	// LevelBreak is the minimum required confluence to confirm a level break.
+	LevelBreak uint32
+	// VWAPReversal is the minimum required confluence to confirm a vwap reversal.
+	VWAPReversal uint32
+	// VWAPBreak is the minimum required confluence to confirm a vwap break.
+	VWAPBreak uint32
+	// ImbalanceReversal is the minimum required confluence to confirm an imbalance reversal.
+	ImbalanceReversal uint32
+	// ImbalanceBreak is the minimum required confluence to confirm an imbalance break.
+	ImbalanceBreak uint32
+	// AverageVolumePercent is the minimum percentage above average volume to be considered
+	// substantive.
+	AverageVolumePercent float64
+}
+
+// DefaultThresholds returns the default confluence thresholds.
+func DefaultThresholds() *Thresholds {
+	return &Thresholds{
+		LevelReversal:        minLevelReversalConfluence,
+		LevelBreak:           minLevelBreakConfluence,
+		VWAPReversal:         minVWAPReversalConfluence,
+		VWAPBreak:            minVWAPBreakConfluence,
+		ImbalanceReversal:    minImbalanceReversalConfluence,
+		ImbalanceBreak:       minImbalanceBreakConfluence,
+		AverageVolumePercent: minAverageVolumePercent,
+	}
+}
+
+// Validate asserts the thresholds are sane.
+func (t *Thresholds) Validate() error {
+	var errs error
+
+	if t.LevelReversal == 0 {
+		errs = errors.Join(errs, fmt.Errorf("level reversal threshold cannot be zero"))
+	}
+	if t.LevelBreak == 0 {
+		errs = errors.Join(errs, fmt.Errorf("level break threshold cannot be zero"))
+	}
+	if t.VWAPReversal == 0 {
+		errs = errors.Join(errs, fmt.Errorf("vwap reversal threshold cannot be zero"))
+	}
+	if t.VWAPBreak == 0 {
+		errs = errors.Join(errs, fmt.Errorf("vwap break threshold cannot be zero"))
+	}
+	if t.ImbalanceReversal == 0 {
+		errs = errors.Join(errs, fmt.Errorf("imbalance reversal threshold cannot be zero"))
+	}
+	if t.ImbalanceBreak == 0 {
+		errs = errors.Join(errs, fmt.Errorf("imbalance break threshold cannot be zero"))
+	}
+	if t.AverageVolumePercent <= 0 {
+		errs = errors.Join(errs, fmt.Errorf("average volume percent threshold must be positive"))
+	}
+
+	return errs
+}
+
+// ParseThresholds parses thresholds from the provided entries of the form kind=value, kinds
+// are levelreversal, levelbreak, vwapreversal, vwapbreak, imbalancereversal, imbalancebreak
+// and averagevolumepercent. Kinds not provided keep their default threshold, nil is returned
+// if no entries are provided.
+func ParseThresholds(entries []string) (*Thresholds, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	thresholds := DefaultThresholds()
+	confluences := map[string]*uint32{
+		"levelreversal":     &thresholds.LevelReversal,
+		"levelbreak":        &thresholds.LevelBreak,
+		"vwapreversal":      &thresholds.VWAPReversal,
+		"vwapbreak":         &thresholds.VWAPBreak,
+		"imbalancereversal": &thresholds.ImbalanceReversal,
+		"imbalancebreak":    &thresholds.ImbalanceBreak,
+	}
+
+	for idx := range entries {
+		kind, valueStr, ok := strings.Cut(entries[idx], "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid threshold entry provided: %s", entries[idx])
+		}
+
+		if kind == "averagevolumepercent" {
+			value, err := strconv.ParseFloat(valueStr, 64)
+			if err != nil {
+				return nil, fmt.Errorf("parsing %s threshold: %v", kind, err)
+			}
+
+			thresholds.AverageVolumePercent = value
+			continue
+		}
+
+		confluence, ok := confluences[kind]
+		if !ok {
+			return nil, fmt.Errorf("unknown threshold provided: %s", kind)
+		}
+
+		value, err := strconv.ParseUint(valueStr, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s threshold: %v", kind, err)
+		}
+
+		*confluence = uint32(value)
+	}
+
+	err := thresholds.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("validating thresholds: %v", err)
+	}
+
+	return thresholds, nil
+}
+
+// ExitThresholds represents the confluence thresholds used in evaluating reactions as exits.
+// A zero threshold falls back to the corresponding entry threshold.
+type ExitThresholds struct {
+	// LevelReversal is the minimum required confluence to exit on a level reversal.
+	LevelReversal uint32
+	// LevelBreak is the minimum required confluence to exit on a level break.
+	LevelBreak uint32
+	// VWAPReversal is the minimum required confluence to exit on a vwap reversal.
+	VWAPReversal uint32
+	// VWAPBreak is the minimum required confluence to exit on a vwap break.
+	VWAPBreak uint32
+	// ImbalanceReversal is the minimum required confluence to exit on an imbalance reversal.
+	ImbalanceReversal uint32
+	// ImbalanceBreak is the minimum required confluence to exit on an imbalance break.
+	ImbalanceBreak uint32
+}
+
+// exitThreshold returns the provided exit threshold, falling back to the provided entry
+// threshold if it is not set.
+func exitThreshold(exit uint32, entry uint32) uint32 {
+	if exit == 0 {
+		return entry
+	}
+
+	return exit
+}
+
+// NeutralSkewMode represents how entries are taken for markets with neutral skew.
+type NeutralSkewMode int
+
+const (
+	// BothDirections takes both long and short entries under neutral skew.
+	BothDirections NeutralSkewMode = iota
+	// TrendDirection only takes entries aligned with the higher timeframe trend under
+	// neutral skew.
+	TrendDirection
+	// NetOpposing nets an entry opposing the latest open leg of a neutral skewed market by
+	// exiting the leg instead.
+	NetOpposing
+)
+
+// String stringifies the provided neutral skew mode.
+func (m NeutralSkewMode) String() string {
+	switch m {
+	case BothDirections:
+		return "both"
+	case TrendDirection:
+		return "trend"
+	case NetOpposing:
+		return "net"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseNeutralSkewMode parses the neutral skew mode from the provided string. An empty string
+// defaults to BothDirections.
+func ParseNeutralSkewMode(mode string) (NeutralSkewMode, error) {
+	switch mode {
+	case "", "both":
+		return BothDirections, nil
+	case "trend":
+		return TrendDirection, nil
+	case "net":
+		return NetOpposing, nil
+	default:
+		return 0, fmt.Errorf("unknown neutral skew mode provided: %s", mode)
+	}
+}
+
+// CapacityPolicy represents how reactions are relayed when the engine's reaction signals
+// are at capacity.
+type CapacityPolicy int
+
+const (
+	// DropOnCapacity logs and drops reactions relayed at capacity.
+	DropOnCapacity CapacityPolicy = iota
+	// BlockOnCapacity waits on capacity for reactions relayed at capacity, bounded by the
+	// capacity timeout, before dropping them.
+	BlockOnCapacity
+)
+
+// String stringifies the provided capacity policy.
+func (p CapacityPolicy) String() string {
+	switch p {
+	case DropOnCapacity:
+		return "drop"
+	case BlockOnCapacity:
+		return "block"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseCapacityPolicy parses the capacity policy from the provided string. An empty string
+// defaults to DropOnCapacity.
+func ParseCapacityPolicy(policy string) (CapacityPolicy, error) {
+	switch policy {
+	case "", "drop":
+		return DropOnCapacity, nil
+	case "block":
+		return BlockOnCapacity, nil
+	default:
+		return 0, fmt.Errorf("unknown capacity policy provided: %s", policy)
+	}
+}
+
 type EngineConfig struct {
+	// Markets represents the collection of ids of the markets to evaluate reactions for.
+	Markets []string
+	// Thresholds represents the confluence thresholds of the engine. The default thresholds
+	// are used if not provided.
+	Thresholds *Thresholds
+	// ExitThresholds represents the confluence thresholds of the engine for exits. Exits use
+	// the entry thresholds if not provided.
+	ExitThresholds *ExitThresholds
+	// NeutralSkewMode is how entries are taken for markets with neutral skew.
+	NeutralSkewMode NeutralSkewMode
+	// ConfidenceWeights represents the weighting of the factors combined into signal
+	// confidence. The default weights are used if not provided.
+	ConfidenceWeights *ConfidenceWeights
+	// Classification is the config the candles of replayed reactions are classified by. The
+	// default config is used if nil.
+	Classification *shared.ClassificationConfig
+	// LevelWeights is the confluence awarded to reversals at levels, keyed by the session the
+	// level was derived from. The default weights are used if nil.
+	LevelWeights map[shared.LevelSource]uint32
 	// RequestCandleMetadata relays the provided candle metadata request for processing.
 	RequestCandleMetadata func(req shared.CandleMetadataRequest)
 	// RequestAverageVolume relays the provided average volume request for processing.
 	RequestAverageVolume func(request shared.AverageVolumeRequest)
+	// VolumeNormalization is how the volume of reaction candles is measured against the
+	// average volume threshold.
+	VolumeNormalization VolumeNormalization
+	// VolumeFloors is the minimum average volume of markets, keyed by market. Markets with
+	// average volume below their floor are too thin to trade and their reactions are not
+	// signalled. Markets without a floor are not checked.
+	VolumeFloors map[string]float64
 	// SendEntrySignal relays the provided entry signal for processing.
 	SendEntrySignal func(signal shared.EntrySignal)
 	// SendExitSignal relays the provided exit signal for processing.
 	SendExitSignal func(signal shared.ExitSignal)
 	// RequestMarketSkew relays the provided market skew request for processing.
 	RequestMarketSkew func(request shared.MarketSkewRequest)
+	// LatestOpenLeg returns the direction of the most recently opened leg of the provided
+	// market that is still open. It is only required for the net opposing neutral skew mode.
+	LatestOpenLeg func(market string) (shared.Direction, bool, error)
+	// RTHWindows are the regular trading hours of markets, keyed by market. Entries of markets
+	// with regular trading hours are suppressed outside them, markets without them are not
+	// restricted.
+	RTHWindows map[string]shared.SessionWindow
+	// NewsBlackout represents the scheduled news releases entries are suppressed around.
+	// Entries are not suppressed if not provided.
+	NewsBlackout *NewsBlackout
+	// BracketRewardRatio is the multiple of the points risked to the stop loss the target of
+	// bracketed entries is placed at. Entry signals are not bracketed if zero.
+	BracketRewardRatio float64
+	// StructureSkew is the flag for biasing entries of markets with neutral skew towards
+	// the direction of confirmed level breaks against the prevailing trend.
+	StructureSkew bool
+	// RegimeFilter represents the criteria for suppressing entries in ranging markets.
+	// Entries are not suppressed by market regime if not provided.
+	RegimeFilter *RegimeFilter
+	// RequestPriceData relays the provided price data request for processing. It is only
+	// required for the regime filter.
+	RequestPriceData func(request shared.PriceDataRequest)
+	// TightestStop is the flag for placing stops at the tighter of the signal candle and
+	// reaction window stops that still sits beyond the reacted level.
+	TightestStop bool
+	// WickStopBuffer is the multiple of the average wick length of the reaction's candles
+	// stops are buffered by, the market's stop buffer is the minimum. Stops are buffered by
+	// the market's stop buffer if zero.
+	WickStopBuffer float64
+	// StopBuffers is the distance stops are placed beyond the reaction's candles, keyed by
+	// market. Markets without a stop buffer use the default points buffer.
+	StopBuffers map[string]StopBuffer
+	// StopOutBump is the confluence the entry threshold of the next reversal at a level is
+	// raised by after a position entered at the level is stopped out. Entry thresholds are
+	// not raised if zero.
+	StopOutBump uint32
+	// StopOutWindow is the time after a stop out the next reversal at its level is held to
+	// the raised entry threshold. The default window is used if zero.
+	StopOutWindow time.Duration
+	// AssetClasses is the asset class of markets, keyed by market. It determines the high
+	// volume window of the market, markets without an asset class are equity indices.
+	AssetClasses map[string]shared.AssetClass
+	// DirectionModes is the direction mode of markets, keyed by market. Markets without a
+	// direction mode take entries in both directions.
+	DirectionModes map[string]DirectionMode
+	// ReasonPriority is the priority reasons of entry signals and audit records are ordered
+	// by. The default priority is used if nil.
+	ReasonPriority shared.ReasonPriority
+	// Timeouts represents the timeouts of price data, candle metadata and signal status waits.
+	// The default timeouts are used if nil.
+	Timeouts *shared.Timeouts
+	// HighVolumeWindows are the daily windows reactions are awarded high volume session
+	// confluence in, they apply to all markets. The asset class windows of markets are used if
+	// not provided.
+	HighVolumeWindows []shared.SessionWindow
+	// MinDistinctReasons is the minimum number of distinct reasons a signal must be backed by,
+	// signals meeting the confluence threshold from fewer sources are rejected. Signals are
+	// not checked for breadth if zero.
+	MinDistinctReasons int
+	// BreakConfirmationCloses is the number of trailing closes of a break that must be beyond
+	// the broken focus for the break to be awarded confirmation. Only the last close is
+	// required if zero.
+	BreakConfirmationCloses int
+	// Instruments is the contract specification of markets, keyed by market. Stops of
+	// markets without an instrument are not rounded or widened.
+	Instruments map[string]shared.Instrument
+	// EnabledReactions is the set of reaction kinds entries are taken on, exits are signalled
+	// for all reaction kinds. Entries are taken on all reaction kinds if nil.
+	EnabledReactions map[ReactionKind]struct{}
+	// StopRange represents the bounds of the points range of entry stops. Stops are not
+	// bounded if not provided.
+	StopRange *StopRange
+	// ErrorSink receives processing errors in addition to them being logged. Errors are
+	// dropped if the sink is at capacity, they are only logged if nil.
+	ErrorSink chan error
+	// DrainGracePeriod is the maximum time spent handling buffered and in-flight reactions
+	// on shutdown. The default grace period is used if zero.
+	DrainGracePeriod time.Duration
+	// CapacityPolicy is how reactions are relayed when reaction signals are at capacity.
+	CapacityPolicy CapacityPolicy
+	// CapacityTimeout is the maximum time spent waiting on capacity under the block on
+	// capacity policy. The default timeout is used if zero.
+	CapacityTimeout time.Duration
+	// RequestTrend relays the provided trend request for processing. It is only required
+	// for the trend direction neutral skew mode, trend alignment is scored neutral in signal
+	// confidence without it.
+	RequestTrend func(request shared.TrendRequest)
+	// TrendBias is how reversals are biased by the moving average trend of their timeframe.
+	TrendBias TrendBiasMode
+	// RequestMovingAverage relays the provided moving average request for processing. It is
+	// only required for trend bias.
+	RequestMovingAverage func(request shared.MovingAverageRequest)
+	// ExitPolicy is how open positions are exited on opposing reactions.
+	ExitPolicy ExitPolicy
+	// VWAPStretch is the number of standard deviations price must be stretched from the vwap
+	// line by for reversals at vwap to be awarded mean reversion confluence. The default
+	// stretch is used if zero.
+	VWAPStretch float64
+	// LevelVWAPGap is the price distance within which a level and vwap reacted to on the same
+	// candle are treated as one focus, only the reaction at the stronger of them contributes
+	// confluence. Levels and vwap are not checked for overlap if zero.
+	LevelVWAPGap float64
+	// Audit receives the confluence breakdown and outcome of every evaluated reaction.
+	// Reactions are not audited if nil.
+	Audit *AuditWriter
 	// Logger represents the application logger.
 	Logger zerolog.Logger
 }
 
 type Engine struct {
 	cfg                        *EngineConfig
+	drops                      *shared.DropCounter
+	markets                    map[string]struct{}
+	paused                     map[string]struct{}
+	marketsMtx                 sync.RWMutex
+	thresholds                 atomic.Pointer[Thresholds]
+	confidenceWeights          atomic.Pointer[ConfidenceWeights]
+	levelWeights               atomic.Pointer[map[shared.LevelSource]uint32]
+	structure                  map[string]shared.Direction
+	structureMtx               sync.Mutex
 	workers                    chan struct{}
+	inflight                   sync.WaitGroup
+	reactionTimeout            time.Duration
 	reactionAtLevelSignals     chan shared.ReactionAtLevel
 	reactionAtVWAPSignals      chan shared.ReactionAtVWAP
 	reactionAtImbalanceSignals chan shared.ReactionAtImbalance
+	// runIterations counts the iterations of the run loop, an idle run loop blocks without
+	// advancing it.
+	runIterations atomic.Uint64
 }
 
 // NewEngine initializes a new market engine.
 func NewEngine(cfg *EngineConfig) *Engine {
-	return &Engine{
+	markets := make(map[string]struct{}, len(cfg.Markets))
+	for idx := range cfg.Markets {
+		markets[cfg.Markets[idx]] = struct{}{}
+	}
+
+	eng := &Engine{
 		cfg:                        cfg,
+		drops:                      shared.NewDropCounter(),
+		markets:                    markets,
+		paused:                     make(map[string]struct{}),
+		structure:                  make(map[string]shared.Direction),
 		workers:                    make(chan struct{}, maxWorkers),
+		reactionTimeout:            reactionTimeout,
 		reactionAtLevelSignals:     make(chan shared.ReactionAtLevel, bufferSize),
 		reactionAtVWAPSignals:      make(chan shared.ReactionAtVWAP, bufferSize),
 		reactionAtImbalanceSignals: make(chan shared.ReactionAtImbalance, bufferSize),
 	}
+
+	thresholds := cfg.Thresholds
+	if thresholds == nil {
+		thresholds = DefaultThresholds()
+	}
+	eng.thresholds.Store(thresholds)
+
+	confidenceWeights := cfg.ConfidenceWeights
+	if confidenceWeights == nil {
+		confidenceWeights = DefaultConfidenceWeights()
+	}
+	eng.confidenceWeights.Store(confidenceWeights)
+
+	levelWeights := cfg.LevelWeights
+	if levelWeights == nil {
+		levelWeights = DefaultLevelWeights()
+	}
+	eng.levelWeights.Store(&levelWeights)
+
+	return eng
 }
 
-// SignalReactionAtLevel relays the provided reaction at level for processing.
-func (e *Engine) SignalReactionAtLevel(reaction shared.ReactionAtLevel) {
+// SetThresholds atomically swaps the confluence thresholds of the engine. Reactions already
+// being evaluated complete with the thresholds they started with.
+func (e *Engine) SetThresholds(thresholds *Thresholds) error {
+	if thresholds == nil {
+		return fmt.Errorf("thresholds cannot be nil")
+	}
+
+	err := thresholds.Validate()
+	if err != nil {
+		return fmt.Errorf("validating thresholds: %v", err)
+	}
+
+	updated := *thresholds
+	e.thresholds.Store(&updated)
+
+	return nil
+}
+
+// SetConfidenceWeights atomically swaps the confidence weights of the engine.
+func (e *Engine) SetConfidenceWeights(weights *ConfidenceWeights) error {
+	if weights == nil {
+		return fmt.Errorf("confidence weights cannot be nil")
+	}
+
+	err := weights.Validate()
+	if err != nil {
+		return fmt.Errorf("validating confidence weights: %v", err)
+	}
+
+	updated := *weights
+	e.confidenceWeights.Store(&updated)
+
+	return nil
+}
+
+// ConfidenceWeights returns the current confidence weights of the engine.
+func (e *Engine) ConfidenceWeights() ConfidenceWeights {
+	return *e.confidenceWeights.Load()
+}
+
+// SetLevelWeights atomically swaps the level weights of the engine.
+func (e *Engine) SetLevelWeights(weights map[shared.LevelSource]uint32) error {
+	if weights == nil {
+		return fmt.Errorf("level weights cannot be nil")
+	}
+
+	updated := maps.Clone(weights)
+	e.levelWeights.Store(&updated)
+
+	return nil
+}
+
+// LevelWeights returns the current level weights of the engine.
+func (e *Engine) LevelWeights() map[shared.LevelSource]uint32 {
+	return maps.Clone(*e.levelWeights.Load())
+}
+
+// levelWeight returns the confluence awarded to reversals at levels of the provided source.
+func (e *Engine) levelWeight(source shared.LevelSource) uint32 {
+	return (*e.levelWeights.Load())[source]
+}
+
+// exitThresholds returns the exit confluence thresholds of the engine.
+func (e *Engine) exitThresholds() ExitThresholds {
+	if e.cfg.ExitThresholds == nil {
+		return ExitThresholds{}
+	}
+
+	return *e.cfg.ExitThresholds
+}
+
+// Thresholds returns the current confluence thresholds of the engine.
+func (e *Engine) Thresholds() Thresholds {
+	return *e.thresholds.Load()
+}
+
+// AddMarket starts evaluating reactions for the provided market.
+func (e *Engine) AddMarket(market string) error {
+	e.marketsMtx.Lock()
+	defer e.marketsMtx.Unlock()
+
+	_, ok := e.markets[market]
+	if ok {
+		return fmt.Errorf("market %s is already tracked", market)
+	}
+
+	e.markets[market] = struct{}{}
+
+	return nil
+}
+
+// RemoveMarket stops evaluating reactions for the provided market. Reactions received for the
+// market afterwards are discarded.
+func (e *Engine) RemoveMarket(market string) error {
+	e.marketsMtx.Lock()
+	defer e.marketsMtx.Unlock()
+
+	_, ok := e.markets[market]
+	if !ok {
+		return fmt.Errorf("no market found with name %s", market)
+	}
+
+	delete(e.markets, market)
+	delete(e.paused, market)
+	e.clearStructure(market)
+
+	return nil
+}
+
+// isTracked returns whether reactions for the provided market are evaluated.
+func (e *Engine) isTracked(market string) bool {
+	e.marketsMtx.RLock()
+	defer e.marketsMtx.RUnlock()
+
+	_, ok := e.markets[market]
+	return ok
+}
+
+// Pause stops evaluating reactions for the provided market until it is resumed. Reactions
+// received for the market while paused are dropped.
+func (e *Engine) Pause(market string) error {
+	e.marketsMtx.Lock()
+	defer e.marketsMtx.Unlock()
+
+	_, ok := e.markets[market]
+	if !ok {
+		return fmt.Errorf("no market found with name %s", market)
+	}
+
+	e.paused[market] = struct{}{}
+
+	return nil
+}
+
+// Resume resumes evaluating reactions for the provided paused market.
+func (e *Engine) Resume(market string) error {
+	e.marketsMtx.Lock()
+	defer e.marketsMtx.Unlock()
+
+	_, ok := e.markets[market]
+	if !ok {
+		return fmt.Errorf("no market found with name %s", market)
+	}
+
+	delete(e.paused, market)
+
+	return nil
+}
+
+// Paused returns whether reactions for the provided market are dropped.
+func (e *Engine) Paused(market string) bool {
+	e.marketsMtx.RLock()
+	defer e.marketsMtx.RUnlock()
+
+	_, ok := e.paused[market]
+	return ok
+}
+
+// relaySignal relays the provided signal, the signal is handled according to the capacity
+// policy of the engine if the signals channel is at capacity.
+func relaySignal[T any](e *Engine, signals chan T, signal T, focus string) {
 	select {
-	case e.reactionAtLevelSignals <- reaction:
-		// do nothing.
+	case signals <- signal:
+		return
 	default:
-		e.cfg.Logger.Error().Msgf("reaction at level signals channel at capacity: %d/%d",
-			len(e.reactionAtLevelSignals), bufferSize)
 	}
+
+	if e.cfg.CapacityPolicy == BlockOnCapacity {
+		timeout := e.cfg.CapacityTimeout
+		if timeout == 0 {
+			timeout = shared.TimeoutDuration
+		}
+
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		select {
+		case signals <- signal:
+			return
+		case <-timer.C:
+		}
+	}
+
+	e.drops.Drop(fmt.Sprintf("reaction at %s", focus))
+}
+
+// Dropped returns the number of reactions dropped by the engine's channels at capacity,
+// keyed by channel.
+func (e *Engine) Dropped() map[string]uint64 {
+	return e.drops.Counts()
+}
+
+// SignalReactionAtLevel relays the provided reaction at level for processing.
+func (e *Engine) SignalReactionAtLevel(reaction shared.ReactionAtLevel) {
+	relaySignal(e, e.reactionAtLevelSignals, reaction, "level")
 }
 
 // SignalReactionAtVWAP relays the provided reaction at VWAP for processing.
 func (e *Engine) SignalReactionAtVWAP(reaction shared.ReactionAtVWAP) {
-	select {
-	case e.reactionAtVWAPSignals <- reaction:
-		// do nothing.
-	default:
-		e.cfg.Logger.Error().Msgf("reaction at vwap signals channel at capacity: %d/%d",
-			len(e.reactionAtVWAPSignals), bufferSize)
-	}
+	relaySignal(e, e.reactionAtVWAPSignals, reaction, "vwap")
 }
 
 // SignalReactionAtImbalance relays the provided reaction at imbalance for processing.
 func (e *Engine) SignalReactionAtImbalance(reaction shared.ReactionAtImbalance) {
-	select {
-	case e.reactionAtImbalanceSignals <- reaction:
-		// do nothing.
-	default:
-		e.cfg.Logger.Error().Msgf("reaction at imbalance signals channel at capacity: %d/%d",
-			len(e.reactionAtImbalanceSignals), bufferSize)
-	}
+	relaySignal(e, e.reactionAtImbalanceSignals, reaction, "imbalance")
 }
 
 // evaluateHighVolumeSession awards confluence points if the provided time occured during a high volume session.
-func (e *Engine) evaluateHighVolumeSession(reaction *shared.ReactionAtFocus, confluence *uint32, reasons map[shared.Reason]struct{}) error {
+func (e *Engine) evaluateHighVolumeSession(reaction *shared.ReactionAtFocus, confluence *uint32, reasons map[shared.Reason]uint32) error {
 	// Any notable price action move occuring during the high volume window indicates strength.
-	highVolumeWindow, err := shared.InHighVolumeWindow(reaction.CreatedOn)
+	var highVolumeWindow bool
+	var err error
+	switch {
+	case len(e.cfg.HighVolumeWindows) > 0:
+		highVolumeWindow, err = shared.InHighVolumeWindow(reaction.CreatedOn, e.cfg.HighVolumeWindows)
+	default:
+		highVolumeWindow, err = e.cfg.AssetClasses[reaction.Market].InHighVolumeWindow(reaction.CreatedOn)
+	}
 	if err != nil {
 		return fmt.Errorf("checking high volume window status: %v", err)
 	}
 
 	if highVolumeWindow {
 		(*confluence)++
-		reasons[shared.HighVolumeSession] = struct{}{}
+		reasons[shared.HighVolumeSession]++
 	}
 
 	return nil
 }
 
-// evaluateVolumeStrength awards confluence points if the provided volume difference is greater than the provided average volume.
-func (e *Engine) evaluateVolumeStrength(averageVolume float64, volumeDifference float64, confluence *uint32, reasons map[shared.Reason]struct{}) error {
+// evaluateCoincidentFocuses awards a confluence point for each focus coinciding with the
+// provided reaction's focus. A level and vwap within the level vwap gap of each other are
+// the same price and are not awarded a point.
+func (e *Engine) evaluateCoincidentFocuses(reaction *shared.ReactionAtFocus, confluence *uint32, reasons map[shared.Reason]uint32) {
+	coincident := uint32(len(reaction.CoincidentFocuses))
+	if e.collapsedOverlap(reaction) {
+		coincident--
+	}
+	if coincident == 0 {
+		return
+	}
+
+	*confluence += coincident
+	reasons[shared.CoincidentFocus] += coincident
+}
+
+// evaluateImbalanceFreshness awards confluence points scaled by how unfilled the imbalance
+// of the provided reaction is, under the fresh imbalance reason.
+func (e *Engine) evaluateImbalanceFreshness(reaction *shared.ReactionAtFocus, confluence *uint32, reasons map[shared.Reason]uint32) {
+	if reaction.ImbalanceFill == nil {
+		return
+	}
+
+	switch fill := *reaction.ImbalanceFill; {
+	case fill <= freshImbalanceFill:
+		// Reactions at largely unfilled imbalances are the most likely to hold.
+		*confluence += 2
+		reasons[shared.FreshImbalance] += 2
+	case fill <= partialImbalanceFill:
+		*confluence++
+		reasons[shared.FreshImbalance]++
+	}
+}
+
+// evaluateImbalanceStacking awards a confluence point for each imbalance stacked at the
+// imbalance of the provided reaction, up to the maximum stacked imbalance confluence.
+func (e *Engine) evaluateImbalanceStacking(reaction *shared.ReactionAtFocus, confluence *uint32, reasons map[shared.Reason]uint32) {
+	if reaction.ImbalanceStack <= 1 {
+		return
+	}
+
+	stacked := min(reaction.ImbalanceStack-1, maxStackedImbalanceConfluence)
+	*confluence += stacked
+	reasons[shared.StackedImbalance] += stacked
+}
+
+// evaluateOrderBlock awards confluence points if the provided reaction is at an order block.
+func (e *Engine) evaluateOrderBlock(reaction *shared.ReactionAtFocus, confluence *uint32, reasons map[shared.Reason]uint32) {
+	if reaction.Focus != shared.OrderBlockFocus {
+		return
+	}
+
+	// Order blocks mark where the displacement originated, reversals there indicate strength.
+	*confluence++
+	reasons[shared.OrderBlock]++
+}
+
+// evaluateVWAPStretch awards confluence points if price is stretched from the vwap line of the
+// provided reaction in the direction being reversed. Only reactions at vwap are evaluated.
+func (e *Engine) evaluateVWAPStretch(reaction *shared.ReactionAtFocus, reactionSentiment shared.Sentiment, confluence *uint32, reasons map[shared.Reason]uint32) {
+	if reaction.VWAP == nil || reaction.VWAP.StdDev <= 0 {
+		return
+	}
+
+	threshold := e.cfg.VWAPStretch
+	if threshold == 0 {
+		threshold = defaultVWAPStretch
+	}
+
+	// Bullish reversals revert price stretched below vwap, bearish reversals price stretched
+	// above it.
+	stretch := (reaction.CurrentPrice - reaction.VWAP.Value) / reaction.VWAP.StdDev
+	if reactionSentiment == shared.Bullish {
+		stretch = -stretch
+	}
+
+	if stretch >= threshold {
+		*confluence++
+		reasons[shared.VWAPStretch]++
+	}
+}
+
+// evaluateVolumeStrength awards confluence points if the provided volume difference is above
+// average volume. The difference is measured against the provided volume scale, the average
+// volume or the standard deviation of volume when normalized.
+func (e *Engine) evaluateVolumeStrength(volumeScale float64, volumeDifference float64, confluence *uint32, reasons map[shared.Reason]uint32) error {
 	// A break with above average volume signifies strength.
-	if averageVolume > 0 {
+	if volumeScale > 0 {
 		switch {
-		case volumeDifference/averageVolume >= minAverageVolumePercent:
+		case volumeDifference/volumeScale >= e.thresholds.Load().AverageVolumePercent:
 			// A break substantially above average volume is a great indicator of strength.
 			(*confluence) += 2
-			reasons[shared.StrongVolume] = struct{}{}
+			reasons[shared.StrongVolume] += 2
 		case volumeDifference > 0:
 			(*confluence)++
-			reasons[shared.StrongVolume] = struct{}{}
+			reasons[shared.StrongVolume]++
 		}
 	}
 
@@ -136,7 +820,7 @@ func (e *Engine) evaluateVolumeStrength(averageVolume float64, volumeDifference
 }
 
 // evaluateCandleMetadataStrength awards confluence points based on the provided candle structure and momentum.
-func (e *Engine) evaluateCandleMetadataStrength(candleMeta shared.CandleMetadata, reactionSentiment shared.Sentiment, confluence *uint32, reasons map[shared.Reason]struct{}) error {
+func (e *Engine) evaluateCandleMetadataStrength(candleMeta shared.CandleMetadata, reactionSentiment shared.Sentiment, confluence *uint32, reasons map[shared.Reason]uint32) error {
 	// Only evaluate candle metadata that supports the sentiment of the reaction.
 	if candleMeta.Sentiment != reactionSentiment {
 		// do nothing.
@@ -144,10 +828,12 @@ func (e *Engine) evaluateCandleMetadataStrength(candleMeta shared.CandleMetadata
 	}
 
 	// A reversal must show strength (candle structure and momentum) in order to be actionable.
-	if (candleMeta.Kind == shared.Marubozu || candleMeta.Kind == shared.Pinbar) &&
+	// Pin bars only count towards the sentiment they reject price in favour of, a hammer for
+	// bullish reactions and a shooting star for bearish ones.
+	if (candleMeta.Kind == shared.Marubozu || candleMeta.Kind.PinbarSentiment() == reactionSentiment) &&
 		(candleMeta.Momentum == shared.High || candleMeta.Momentum == shared.Medium) {
 		(*confluence)++
-		reasons[shared.StrongMove] = struct{}{}
+		reasons[shared.StrongMove]++
 	}
 
 	// An engulfing reversal signifies directional strength.
@@ -155,9 +841,9 @@ func (e *Engine) evaluateCandleMetadataStrength(candleMeta shared.CandleMetadata
 		(*confluence)++
 		switch candleMeta.Sentiment {
 		case shared.Bullish:
-			reasons[shared.BullishEngulfing] = struct{}{}
+			reasons[shared.BullishEngulfing]++
 		case shared.Bearish:
-			reasons[shared.BearishEngulfing] = struct{}{}
+			reasons[shared.BearishEngulfing]++
 		}
 	}
 
@@ -165,8 +851,8 @@ func (e *Engine) evaluateCandleMetadataStrength(candleMeta shared.CandleMetadata
 }
 
 // evaluatePriceReversalConfirmation awards confluence points based on confirmation of the level reaction being a reversal.
-func (e *Engine) evaluatePriceReversalConfirmation(reaction *shared.ReactionAtFocus, confluence *uint32, reactionSentiment *shared.Sentiment, reasons map[shared.Reason]struct{}) error {
-	if reaction.Reaction != shared.Reversal {
+func (e *Engine) evaluatePriceReversalConfirmation(reaction *shared.ReactionAtFocus, confluence *uint32, reactionSentiment *shared.Sentiment, reasons map[shared.Reason]uint32) error {
+	if reaction.Reaction != shared.Reversal && reaction.Reaction != shared.Sweep {
 		return fmt.Errorf("level reaction is not a reversal, got %s", reaction.Reaction.String())
 	}
 
@@ -175,20 +861,30 @@ func (e *Engine) evaluatePriceReversalConfirmation(reaction *shared.ReactionAtFo
 	case shared.Resistance:
 		*confluence++
 		*reactionSentiment = shared.Bearish
-		reasons[shared.ReversalAtResistance] = struct{}{}
+		reasons[shared.ReversalAtResistance]++
 	case shared.Support:
 		*confluence++
 		*reactionSentiment = shared.Bullish
-		reasons[shared.ReversalAtSupport] = struct{}{}
+		reasons[shared.ReversalAtSupport]++
 	default:
 		return fmt.Errorf("unknown level kind provided: %s", reaction.LevelKind.String())
 	}
 
+	// Reversals at levels of significant sessions indicate strength.
+	e.evaluateLevelSignificance(reaction, confluence, reasons)
+
+	// A sweep of the liquidity beyond a level that is reclaimed indicates trapped traders
+	// fueling the reversal.
+	if reaction.Reaction == shared.Sweep {
+		*confluence++
+		reasons[shared.LiquiditySweep]++
+	}
+
 	return nil
 }
 
 // extractReasons generates a reasons key slice from the provided map.
-func extractReasons(reasons map[shared.Reason]struct{}) []shared.Reason {
+func extractReasons(reasons map[shared.Reason]uint32) []shared.Reason {
 	data := make([]shared.Reason, 0, len(reasons))
 	for k := range reasons {
 		data = append(data, k)
@@ -197,54 +893,253 @@ func extractReasons(reasons map[shared.Reason]struct{}) []shared.Reason {
 	return data
 }
 
-// fetchAverageVolume fetches the average volume of the provided market.
-func (e *Engine) fetchAverageVolume(market string, timeframe shared.Timeframe) (float64, error) {
+// fetchAverageVolume fetches the average volume of the provided market, along with the scale
+// volume differences are measured against. The scale is the average volume, or the standard
+// deviation of volume if volume is normalized.
+func (e *Engine) fetchAverageVolume(ctx context.Context, market string, timeframe shared.Timeframe) (float64, float64, error) {
 	req := shared.NewAverageVolumeRequest(market, timeframe)
+	req.Normalize = e.cfg.VolumeNormalization == ZScoreVolume
 	e.cfg.RequestAverageVolume(*req)
 
+	var averageVolume float64
 	select {
-	case averageVolume := <-req.Response:
-		return averageVolume, nil
-	case <-time.After(time.Second * 5):
-		return 0, fmt.Errorf("timed out fetching average volume for %s", market)
+	case averageVolume = <-req.Response:
+	case err := <-req.Err:
+		return 0, 0, err
+	case <-time.After(e.cfg.Timeouts.PriceDataTimeout()):
+		return 0, 0, fmt.Errorf("timed out fetching average volume for %s", market)
+	case <-ctx.Done():
+		return 0, 0, ctx.Err()
+	}
+
+	if !req.Normalize {
+		return averageVolume, averageVolume, nil
+	}
+
+	select {
+	case deviation := <-req.Deviation:
+		return averageVolume, deviation, nil
+	case <-time.After(e.cfg.Timeouts.PriceDataTimeout()):
+		return 0, 0, fmt.Errorf("timed out fetching volume deviation for %s", market)
+	case <-ctx.Done():
+		return 0, 0, ctx.Err()
 	}
 }
 
 // fetchMarketSkew fetches the market skew for the provided market.
-func (e *Engine) fetchMarketSkew(market string) (shared.MarketSkew, error) {
+func (e *Engine) fetchMarketSkew(ctx context.Context, market string) (shared.MarketSkew, error) {
 	req := shared.NewMarketSkewRequest(market)
 	e.cfg.RequestMarketSkew(*req)
 
 	select {
 	case skew := <-req.Response:
 		return skew, nil
-	case <-time.After(time.Second * 5):
+	case <-time.After(e.cfg.Timeouts.PriceDataTimeout()):
 		return 0, fmt.Errorf("timed out fetching market skew for %s", market)
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// fetchTrend fetches the higher timeframe trend for the provided market.
+func (e *Engine) fetchTrend(ctx context.Context, market string) (shared.Trend, error) {
+	if e.cfg.RequestTrend == nil {
+		return 0, fmt.Errorf("no trend request function configured")
+	}
+
+	req := shared.NewTrendRequest(market, shared.FiveMinute, shared.MacroTrend)
+	e.cfg.RequestTrend(*req)
+
+	select {
+	case trend := <-req.Response:
+		return trend, nil
+	case <-time.After(e.cfg.Timeouts.PriceDataTimeout()):
+		return 0, fmt.Errorf("timed out fetching trend for %s", market)
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// evaluateConfidence scores the confidence of a signal in the provided direction for the
+// provided reaction and its level, if any.
+func (e *Engine) evaluateConfidence(ctx context.Context, reaction *shared.ReactionAtFocus, level *shared.Level, direction shared.Direction, confluence uint32, minConfluenceThreshold uint32) (float64, error) {
+	alignment := neutralConfidence
+	if e.cfg.RequestTrend != nil {
+		trend, err := e.fetchTrend(ctx, reaction.Market)
+		if err != nil {
+			return 0, fmt.Errorf("fetching trend: %v", err)
+		}
+
+		alignment = trendAlignment(trend, direction)
+	}
+
+	return e.confidenceWeights.Load().Score(confluence, minConfluenceThreshold, levelQuality(level), alignment), nil
+}
+
+// suppressEntry determines whether an entry in the provided direction for the provided reaction
+// falls outside the regular trading hours of its market or within a news blackout. Exits are
+// never suppressed.
+func (e *Engine) suppressEntry(reaction *shared.ReactionAtFocus, direction shared.Direction) bool {
+	if e.outsideRTH(reaction, direction) {
+		return true
+	}
+
+	if e.cfg.NewsBlackout == nil {
+		return false
+	}
+
+	release, ok := e.cfg.NewsBlackout.Covers(reaction.CreatedOn)
+	if !ok {
+		return false
+	}
+
+	e.reactionLogger(reaction.CorrelationID).Info().Msgf("suppressing %s %s entry @ %v within the news blackout of the %v release",
+		reaction.Market, direction.String(), reaction.CreatedOn, release)
+
+	return true
+}
+
+// bracketEntry links the stop loss and target of the provided entry signal as a one-cancels-other
+// bracket. Entries with a stop loss inconsistent with their direction are signalled unbracketed.
+func (e *Engine) bracketEntry(signal *shared.EntrySignal) {
+	if e.cfg.BracketRewardRatio == 0 {
+		return
+	}
+
+	bracket, err := shared.NewBracket(signal.Direction, signal.Price, signal.StopLoss,
+		e.cfg.BracketRewardRatio)
+	if err != nil {
+		e.reactionLogger(signal.CorrelationID).Error().Msgf("bracketing %s %s entry: %v", signal.Market,
+			signal.Direction.String(), err)
+		return
+	}
+
+	signal.Bracket = bracket
+}
+
+// evaluateNeutralSkewEntry applies the neutral skew mode to an entry in the provided direction
+// for a market with neutral skew. It returns whether the entry should be signalled.
+func (e *Engine) evaluateNeutralSkewEntry(ctx context.Context, reaction *shared.ReactionAtFocus, direction shared.Direction, reasons []shared.Reason, confluence uint32, confidence float64) (bool, error) {
+	if e.cfg.StructureSkew {
+		// Only take entries aligned with the structure of the market once it has been set.
+		structure := e.structuralSkew(reaction.Market)
+		if (structure == shared.LongSkewed && direction == shared.Short) ||
+			(structure == shared.ShortSkewed && direction == shared.Long) {
+			e.reactionLogger(reaction.CorrelationID).Info().Msgf("skipping %s entry for %s against the %s structure",
+				direction.String(), reaction.Market, structure.String())
+			return false, nil
+		}
+	}
+
+	switch e.cfg.NeutralSkewMode {
+	case TrendDirection:
+		// Only take entries aligned with the higher timeframe trend.
+		trend, err := e.fetchTrend(ctx, reaction.Market)
+		if err != nil {
+			return false, fmt.Errorf("fetching trend: %v", err)
+		}
+
+		var aligned bool
+		switch trend {
+		case shared.MildBullishTrend, shared.StrongBullishTrend:
+			aligned = direction == shared.Long
+		case shared.MildBearishTrend, shared.StrongBearishTrend:
+			aligned = direction == shared.Short
+		}
+
+		if !aligned {
+			e.reactionLogger(reaction.CorrelationID).Info().Msgf("skipping %s entry for %s against the %s",
+				direction.String(), reaction.Market, trend.String())
+		}
+
+		return aligned, nil
+
+	case NetOpposing:
+		// Net an entry opposing the latest open leg by exiting the leg. Legs are only opened
+		// for acknowledged entries and are removed once closed or stopped out.
+		if e.cfg.LatestOpenLeg == nil {
+			return false, fmt.Errorf("no latest open leg function configured")
+		}
+
+		previous, ok, err := e.cfg.LatestOpenLeg(reaction.Market)
+		if err != nil {
+			return false, fmt.Errorf("fetching latest open leg: %v", err)
+		}
+		if !ok || previous == direction {
+			return true, nil
+		}
+
+		e.reactionLogger(reaction.CorrelationID).Info().Msgf("netting %s entry for %s against the open %s leg",
+			direction.String(), reaction.Market, previous.String())
+
+		signal := shared.NewExitSignal(reaction.Market, reaction.Timeframe, previous,
+			reaction.CurrentPrice, reasons, confluence, reaction.CreatedOn)
+		signal.WeightedConfidence = confidence
+		signal.CorrelationID = reaction.CorrelationID
+		e.cfg.SendExitSignal(signal)
+		select {
+		case <-signal.Status:
+		case <-time.After(e.cfg.Timeouts.SignalStatusTimeout()):
+			return false, fmt.Errorf("timed out waiting for exit signal status")
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+
+		return false, nil
+
+	default:
+		return true, nil
 	}
 }
 
-// fetchCandleMetadata fetches the candle metadata for the provided market.
-func (e *Engine) fetchCandleMetadata(market string, timeframe shared.Timeframe) ([]*shared.CandleMetadata, error) {
-	req := shared.NewCandleMetadataRequest(market, timeframe)
+// fetchCandleMetadata fetches the candle metadata for the provided market covering the provided
+// lookback. Metadata spanning the reaction window is fetched if the lookback is zero.
+func (e *Engine) fetchCandleMetadata(ctx context.Context, market string, timeframe shared.Timeframe, lookback uint32) ([]*shared.CandleMetadata, error) {
+	req := shared.NewCandleMetadataRequest(market, timeframe, lookback)
 	e.cfg.RequestCandleMetadata(*req)
 
 	select {
 	case meta := <-req.Response:
 		return meta, nil
-	case <-time.After(time.Second * 5):
+	case <-time.After(e.cfg.Timeouts.CandleMetadataTimeout()):
 		return nil, fmt.Errorf("timed out fetching candle metadata for %s", market)
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
 }
 
 // evaluatePriceReversal determines whether an actionable price reversal has occured.
-func (e *Engine) evaluatePriceReversal(reaction *shared.ReactionAtFocus, meta []*shared.CandleMetadata, minConfluenceThreshold uint32) (bool, uint32, []shared.Reason, error) {
+func (e *Engine) evaluatePriceReversal(ctx context.Context, reaction *shared.ReactionAtFocus, meta []*shared.CandleMetadata, minConfluenceThreshold uint32, record *AuditRecord) (bool, uint32, []shared.Reason, error) {
 	if len(meta) == 0 {
 		return false, 0, nil, fmt.Errorf("candle metadata is empty")
 	}
 
+	averageVolume, volumeScale, err := e.fetchAverageVolume(ctx, reaction.Market, reaction.Timeframe)
+	if err != nil {
+		return false, 0, nil, fmt.Errorf("fetching average volume: %w", err)
+	}
+	if e.tooThin(reaction, averageVolume) {
+		return false, 0, nil, nil
+	}
+
+	var movingAverage *shared.MovingAverage
+	if e.cfg.TrendBias != NoTrendBias {
+		movingAverage, err = e.fetchMovingAverage(ctx, reaction.Market, reaction.Timeframe)
+		if err != nil {
+			return false, 0, nil, fmt.Errorf("fetching moving average: %v", err)
+		}
+	}
+
+	return e.scorePriceReversal(reaction, meta, averageVolume, volumeScale, movingAverage,
+		minConfluenceThreshold, record)
+}
+
+// scorePriceReversal scores the confluence of a price reversal against the provided average
+// volume and volume scale. Reversals are not biased by trend without a moving average.
+func (e *Engine) scorePriceReversal(reaction *shared.ReactionAtFocus, meta []*shared.CandleMetadata, averageVolume float64, volumeScale float64, movingAverage *shared.MovingAverage, minConfluenceThreshold uint32, record *AuditRecord) (bool, uint32, []shared.Reason, error) {
 	var confluence uint32
 	var reactionSentiment shared.Sentiment
-	reasonsKV := make(map[shared.Reason]struct{})
+	reasonsKV := make(map[shared.Reason]uint32)
 
 	// Confirmed price reactions at key focus indicate strength.
 	err := e.evaluatePriceReversalConfirmation(reaction, &confluence, &reactionSentiment, reasonsKV)
@@ -252,16 +1147,29 @@ func (e *Engine) evaluatePriceReversal(reaction *shared.ReactionAtFocus, meta []
 		return false, 0, nil, fmt.Errorf("evaluating price reversal confirmation: %v", err)
 	}
 
+	// Reactions at several coinciding focuses indicate strength.
+	e.evaluateCoincidentFocuses(reaction, &confluence, reasonsKV)
+
+	// Reversals at imbalances that are still largely open indicate strength.
+	e.evaluateImbalanceFreshness(reaction, &confluence, reasonsKV)
+
+	// Reversals at several stacked imbalances indicate strength.
+	e.evaluateImbalanceStacking(reaction, &confluence, reasonsKV)
+
+	// Reversals at order blocks indicate strength.
+	e.evaluateOrderBlock(reaction, &confluence, reasonsKV)
+
+	// Reversals of price stretched far from vwap favour mean reversion.
+	e.evaluateVWAPStretch(reaction, reactionSentiment, &confluence, reasonsKV)
+
 	// A reversal occuring during sessions known for high volume indicates strength.
 	err = e.evaluateHighVolumeSession(reaction, &confluence, reasonsKV)
 	if err != nil {
 		return false, 0, nil, fmt.Errorf("evaluating high volume session: %v", err)
 	}
 
-	averageVolume, err := e.fetchAverageVolume(reaction.Market, reaction.Timeframe)
-	if err != nil {
-		return false, 0, nil, fmt.Errorf("fetching average volume: %v", err)
-	}
+	// Reversals aligned with the prevailing trend indicate strength.
+	blocked := e.evaluateTrendBias(reaction, movingAverage, reactionSentiment, &confluence, reasonsKV)
 
 	for idx := range meta {
 		candleMeta := meta[idx]
@@ -273,21 +1181,26 @@ func (e *Engine) evaluatePriceReversal(reaction *shared.ReactionAtFocus, meta []
 
 		// A reversal with above average volume signifies strength.
 		volumeDiff := candleMeta.Volume - averageVolume
-		err = e.evaluateVolumeStrength(averageVolume, volumeDiff, &confluence, reasonsKV)
+		err = e.evaluateVolumeStrength(volumeScale, volumeDiff, &confluence, reasonsKV)
 		if err != nil {
 			return false, 0, nil, fmt.Errorf("evaluating volume strength: %v", err)
 		}
 	}
 
-	signal := confluence >= minConfluenceThreshold
+	if record != nil {
+		record.setEvaluation(reasonsKV, averageVolume, e.cfg.ReasonPriority)
+	}
 
 	reasons := extractReasons(reasonsKV)
 
+	signal := !blocked && !e.defersToStrongerFocus(reaction) &&
+		e.hasBreadth(reaction, confluence >= minConfluenceThreshold, reasons)
+
 	return signal, confluence, reasons, nil
 }
 
 // evaluateLevelBreakConfirmation awards confluence points based on confirmation of the level reaction being a break.
-func (e *Engine) evaluateBreakConfirmation(reaction *shared.ReactionAtFocus, confluence *uint32, reactionSentiment *shared.Sentiment, reasons map[shared.Reason]struct{}) error {
+func (e *Engine) evaluateBreakConfirmation(reaction *shared.ReactionAtFocus, confluence *uint32, reactionSentiment *shared.Sentiment, reasons map[shared.Reason]uint32) error {
 	if reaction.Reaction != shared.Break {
 		return fmt.Errorf("level reaction is not a break, got %s", reaction.Reaction.String())
 	}
@@ -295,27 +1208,68 @@ func (e *Engine) evaluateBreakConfirmation(reaction *shared.ReactionAtFocus, con
 	// Confirmed breaks at key levels indicate strength.
 	switch reaction.LevelKind {
 	case shared.Resistance:
-		*confluence++
 		*reactionSentiment = shared.Bullish
-		reasons[shared.BreakAboveResistance] = struct{}{}
+		if e.breakConfirmed(reaction, shared.Above) {
+			*confluence++
+			reasons[shared.BreakAboveResistance]++
+		}
 	case shared.Support:
-		*confluence++
 		*reactionSentiment = shared.Bearish
-		reasons[shared.BreakBelowSupport] = struct{}{}
+		if e.breakConfirmed(reaction, shared.Below) {
+			*confluence++
+			reasons[shared.BreakBelowSupport]++
+		}
 	}
 
 	return nil
 }
 
+// breakConfirmed checks whether the trailing closes of the provided break are beyond the
+// broken focus for at least the configured number of break confirmation closes.
+func (e *Engine) breakConfirmed(reaction *shared.ReactionAtFocus, breakSide shared.PriceMovement) bool {
+	required := max(e.cfg.BreakConfirmationCloses, 1)
+
+	var closes int
+	for idx := len(reaction.PriceMovement) - 1; idx >= 0; idx-- {
+		if reaction.PriceMovement[idx] != breakSide {
+			break
+		}
+		closes++
+	}
+
+	if closes >= required {
+		return true
+	}
+
+	e.reactionLogger(reaction.CorrelationID).Info().Msgf("unconfirmed %s %s break for %s, %d of %d closes beyond it",
+		reaction.LevelKind.String(), reaction.Focus.String(), reaction.Market, closes, required)
+
+	return false
+}
+
 // evaluateLevelBreak determines whether an actionable level break has occured.
-func (e *Engine) evaluateLevelBreak(reaction *shared.ReactionAtFocus, meta []*shared.CandleMetadata, minConfluenceThreshold uint32) (bool, uint32, []shared.Reason, error) {
+func (e *Engine) evaluateLevelBreak(ctx context.Context, reaction *shared.ReactionAtFocus, meta []*shared.CandleMetadata, minConfluenceThreshold uint32, record *AuditRecord) (bool, uint32, []shared.Reason, error) {
 	if len(meta) == 0 {
 		return false, 0, nil, fmt.Errorf("candle metadata is empty")
 	}
 
+	averageVolume, volumeScale, err := e.fetchAverageVolume(ctx, reaction.Market, reaction.Timeframe)
+	if err != nil {
+		return false, 0, nil, fmt.Errorf("fetching average volume: %w", err)
+	}
+	if e.tooThin(reaction, averageVolume) {
+		return false, 0, nil, nil
+	}
+
+	return e.scoreLevelBreak(reaction, meta, averageVolume, volumeScale, minConfluenceThreshold, record)
+}
+
+// scoreLevelBreak scores the confluence of a level break against the provided average volume
+// and volume scale.
+func (e *Engine) scoreLevelBreak(reaction *shared.ReactionAtFocus, meta []*shared.CandleMetadata, averageVolume float64, volumeScale float64, minConfluenceThreshold uint32, record *AuditRecord) (bool, uint32, []shared.Reason, error) {
 	var confluence uint32
 	var reactionSentiment shared.Sentiment
-	reasonsKV := make(map[shared.Reason]struct{})
+	reasonsKV := make(map[shared.Reason]uint32)
 
 	// Confirmed breaks at key focus indicate strength.
 	err := e.evaluateBreakConfirmation(reaction, &confluence, &reactionSentiment, reasonsKV)
@@ -323,17 +1277,15 @@ func (e *Engine) evaluateLevelBreak(reaction *shared.ReactionAtFocus, meta []*sh
 		return false, 0, nil, fmt.Errorf("evaluating level break confirmation: %v", err)
 	}
 
+	// Reactions at several coinciding focuses indicate strength.
+	e.evaluateCoincidentFocuses(reaction, &confluence, reasonsKV)
+
 	// A reversal occuring during sessions known for high volume indicates strength.
 	err = e.evaluateHighVolumeSession(reaction, &confluence, reasonsKV)
 	if err != nil {
 		return false, 0, nil, fmt.Errorf("evaluating high volume session: %v", err)
 	}
 
-	averageVolume, err := e.fetchAverageVolume(reaction.Market, reaction.Timeframe)
-	if err != nil {
-		return false, 0, nil, fmt.Errorf("fetching average volume: %v", err)
-	}
-
 	for idx := range meta {
 		candleMeta := meta[idx]
 
@@ -344,22 +1296,79 @@ func (e *Engine) evaluateLevelBreak(reaction *shared.ReactionAtFocus, meta []*sh
 
 		// A break with above average volume signifies strength.
 		volumeDiff := meta[idx].Volume - averageVolume
-		err = e.evaluateVolumeStrength(averageVolume, volumeDiff, &confluence, reasonsKV)
+		err = e.evaluateVolumeStrength(volumeScale, volumeDiff, &confluence, reasonsKV)
 		if err != nil {
 			return false, 0, nil, fmt.Errorf("evaluating volume strength: %v", err)
 		}
 	}
 
-	signal := confluence >= minConfluenceThreshold
+	if record != nil {
+		record.setEvaluation(reasonsKV, averageVolume, e.cfg.ReasonPriority)
+	}
 
 	reasons := extractReasons(reasonsKV)
 
+	signal := !e.defersToStrongerFocus(reaction) &&
+		e.hasBreadth(reaction, confluence >= minConfluenceThreshold, reasons)
+
 	return signal, confluence, reasons, nil
 }
 
+// hasBreadth checks whether a signal meeting the confluence threshold is backed by the minimum
+// number of distinct reasons.
+func (e *Engine) hasBreadth(reaction *shared.ReactionAtFocus, signal bool, reasons []shared.Reason) bool {
+	if !signal || len(reasons) >= e.cfg.MinDistinctReasons {
+		return signal
+	}
+
+	e.reactionLogger(reaction.CorrelationID).Info().Msgf("rejecting %s %s signal backed by %d distinct reasons, %d required",
+		reaction.Market, reaction.Reaction.String(), len(reasons), e.cfg.MinDistinctReasons)
+
+	return false
+}
+
+// validStop checks whether the provided stop loss sits beyond both the current price and the
+// reacted level, if any, for the provided sentiment.
+func validStop(sentiment shared.Sentiment, stopLoss float64, price float64, level *shared.Level) bool {
+	switch sentiment {
+	case shared.Bullish:
+		return stopLoss < price && (level == nil || stopLoss < level.Price)
+	case shared.Bearish:
+		return stopLoss > price && (level == nil || stopLoss > level.Price)
+	default:
+		return false
+	}
+}
+
+// stopLossBuffer returns the distance stops for the provided sentiment are placed beyond the
+// reaction's candles for the provided market. When wick buffering is enabled stops clear the
+// average wick on the stop side so they sit beyond typical noise.
+func (e *Engine) stopLossBuffer(market string, sentiment shared.Sentiment, meta []*shared.CandleMetadata) float64 {
+	buffer := stopLossPointsBuffer
+	stopBuffer, ok := e.cfg.StopBuffers[market]
+	if ok {
+		buffer = stopBuffer.distance(meta)
+	}
+
+	if e.cfg.WickStopBuffer == 0 {
+		return buffer
+	}
+
+	upper, lower := shared.CandleMetaAverageWicks(meta)
+	var wick float64
+	switch sentiment {
+	case shared.Bullish:
+		wick = lower
+	case shared.Bearish:
+		wick = upper
+	}
+
+	return math.Max(buffer, wick*e.cfg.WickStopBuffer)
+}
+
 // estimateStopLoss calculates the stoploss and the point range from entry for a position using
-// the provided candle metadata.
-func (e *Engine) estimateStopLoss(reaction *shared.ReactionAtFocus, meta []*shared.CandleMetadata) (float64, float64, error) {
+// the provided candle metadata. The level is nil for reactions at dynamic levels.
+func (e *Engine) estimateStopLoss(reaction *shared.ReactionAtFocus, level *shared.Level, meta []*shared.CandleMetadata) (float64, float64, error) {
 	if len(meta) == 0 {
 		return 0, 0, fmt.Errorf("no candle metadata provided")
 	}
@@ -371,7 +1380,7 @@ func (e *Engine) estimateStopLoss(reaction *shared.ReactionAtFocus, meta []*shar
 		switch reaction.Reaction {
 		case shared.Break:
 			sentiment = shared.Bearish
-		case shared.Reversal:
+		case shared.Reversal, shared.Sweep:
 			sentiment = shared.Bullish
 		case shared.Chop:
 			return 0, 0, fmt.Errorf("no stop loss set for chop level reaction")
@@ -380,36 +1389,47 @@ func (e *Engine) estimateStopLoss(reaction *shared.ReactionAtFocus, meta []*shar
 		switch reaction.Reaction {
 		case shared.Break:
 			sentiment = shared.Bullish
-		case shared.Reversal:
+		case shared.Reversal, shared.Sweep:
 			sentiment = shared.Bearish
 		case shared.Chop:
 			return 0, 0, fmt.Errorf("no stop loss set for chop level reaction")
 		}
 	}
 
+	// Fallback on the high and low of the candle metadata range for stop loss placement.
 	var stopLoss float64
+	buffer := e.stopLossBuffer(reaction.Market, sentiment, meta)
+	high, low := shared.CandleMetaRangeHighAndLow(meta)
+	switch sentiment {
+	case shared.Bullish:
+		stopLoss = low - buffer
+	case shared.Bearish:
+		stopLoss = high + buffer
+	}
 
 	signalCandle := shared.FetchSignalCandle(meta, sentiment)
-	if signalCandle == nil {
-		// Fallback on the high and low of the candle metadata range for stop loss placement.
-		high, low := shared.CandleMetaRangeHighAndLow(meta)
+	if signalCandle != nil {
+		// Use the signal candle as the focal point for the stop loss placement.
+		var signalStop float64
 		switch sentiment {
 		case shared.Bullish:
-			stopLoss = low - stopLossPointsBuffer
+			signalStop = signalCandle.Low - buffer
 		case shared.Bearish:
-			stopLoss = high + stopLossPointsBuffer
+			signalStop = signalCandle.High + buffer
 		}
 
-	} else {
-		// Use the signal candle as the focal point for the stop loss placement.
-		switch sentiment {
-		case shared.Bullish:
-			stopLoss = signalCandle.Low - stopLossPointsBuffer
-		case shared.Bearish:
-			stopLoss = signalCandle.High + stopLossPointsBuffer
+		// The signal candle stop is never wider than the range stop, it is only passed
+		// over for the range stop when it does not sit beyond the reacted level.
+		if !e.cfg.TightestStop || validStop(sentiment, signalStop, reaction.CurrentPrice, level) {
+			stopLoss = signalStop
 		}
 	}
 
+	// Stops closer than the instrument's minimum distance are widened, all stops are placed
+	// on a tick.
+	instrument := e.cfg.Instruments[reaction.Market]
+	stopLoss = instrument.FitStop(sentiment, reaction.CurrentPrice, stopLoss)
+
 	pointsRange := math.Abs(reaction.CurrentPrice - stopLoss)
 
 	if stopLoss <= 0 {
@@ -419,83 +1439,162 @@ func (e *Engine) estimateStopLoss(reaction *shared.ReactionAtFocus, meta []*shar
 	return stopLoss, pointsRange, nil
 }
 
+// meetsThreshold checks whether the provided confluence meets the provided threshold for the
+// provided kind of signal.
+func (e *Engine) meetsThreshold(reaction *shared.ReactionAtFocus, kind string, confluence uint32, threshold uint32) bool {
+	if confluence >= threshold {
+		return true
+	}
+
+	e.reactionLogger(reaction.CorrelationID).Info().Msgf("skipping %s %s for %s, confluence (%d) below threshold (%d)",
+		reaction.Reaction.String(), kind, reaction.Market, confluence, threshold)
+
+	return false
+}
+
+// signalEntry relays an entry signal in the provided direction for the provided reaction once
+// it passes the entry gates and its stop loss is within bounds. The level is nil for reactions
+// at dynamic levels.
+func (e *Engine) signalEntry(ctx context.Context, reaction *shared.ReactionAtFocus, level *shared.Level, meta []*shared.CandleMetadata, skew shared.MarketSkew, direction shared.Direction, reasons []shared.Reason, confluence uint32, confidence float64, entryThreshold uint32, record *AuditRecord) error {
+	if !e.meetsThreshold(reaction, "entry", confluence, entryThreshold) {
+		return nil
+	}
+	if !e.reactionEnabled(reaction) {
+		return nil
+	}
+	if !e.directionAllowed(reaction, direction) {
+		return nil
+	}
+	if e.suppressEntry(reaction, direction) {
+		return nil
+	}
+	choppy, err := e.evaluateMarketRegime(ctx, reaction, direction, confluence)
+	if err != nil {
+		return fmt.Errorf("evaluating market regime: %w", err)
+	}
+	if choppy {
+		return nil
+	}
+	if skew == shared.NeutralSkew {
+		take, err := e.evaluateNeutralSkewEntry(ctx, reaction, direction, reasons, confluence, confidence)
+		if err != nil {
+			return fmt.Errorf("evaluating neutral skew entry: %v", err)
+		}
+		if !take {
+			return nil
+		}
+	}
+
+	stopLoss, pointsRange, err := e.estimateStopLoss(reaction, level, meta)
+	if err != nil {
+		return fmt.Errorf("estimating stop loss: %v", err)
+	}
+	stopLoss, pointsRange, ok := e.boundStopLoss(reaction, direction, stopLoss, pointsRange)
+	if !ok {
+		return nil
+	}
+
+	signal := shared.NewEntrySignal(reaction.Market, reaction.Timeframe, direction,
+		reaction.CurrentPrice, reasons, confluence, entryThreshold, reaction.CreatedOn, stopLoss, pointsRange)
+	signal.WeightedConfidence = confidence
+	signal.CorrelationID = reaction.CorrelationID
+	signal.ReasonPriority = e.cfg.ReasonPriority
+	signal.LevelPrice = levelPrice(level)
+	e.bracketEntry(&signal)
+	record.setDecision(entryDecision, direction)
+	e.cfg.SendEntrySignal(signal)
+	select {
+	case <-signal.Status:
+	case <-time.After(e.cfg.Timeouts.SignalStatusTimeout()):
+		return fmt.Errorf("timed out waiting for entry signal status")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+// signalExit relays an exit signal for open positions in the provided direction for the
+// provided reaction if the exit policy exits on it.
+func (e *Engine) signalExit(ctx context.Context, reaction *shared.ReactionAtFocus, direction shared.Direction, reasons []shared.Reason, confluence uint32, confidence float64, entryThreshold uint32, exitThreshold uint32, record *AuditRecord) error {
+	if !e.exitsOnReaction(reaction, confluence, entryThreshold, exitThreshold) {
+		return nil
+	}
+
+	signal := shared.NewExitSignal(reaction.Market, reaction.Timeframe, direction,
+		reaction.CurrentPrice, reasons, confluence, reaction.CreatedOn)
+	signal.WeightedConfidence = confidence
+	signal.CorrelationID = reaction.CorrelationID
+	record.setDecision(exitDecision, direction)
+	e.cfg.SendExitSignal(signal)
+	select {
+	case <-signal.Status:
+	case <-time.After(e.cfg.Timeouts.SignalStatusTimeout()):
+		return fmt.Errorf("timed out waiting for exit signal status")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return nil
+}
+
 // evaluatePriceReversalStrength determines whether a price reversal at a level has enough confluences to
 // be classified as strong. An associated entry or exit signal is generated and relayed for it based on
-// the skew of the associated market.
-func (e *Engine) evaluatePriceReversalStrength(reaction *shared.ReactionAtFocus, meta []*shared.CandleMetadata, minConfluenceThreshold uint32) error {
-	signal, confluence, reasons, err := e.evaluatePriceReversal(reaction, meta, minConfluenceThreshold)
+// the skew of the associated market. The level is nil for reactions at dynamic levels.
+func (e *Engine) evaluatePriceReversalStrength(ctx context.Context, reaction *shared.ReactionAtFocus, level *shared.Level, meta []*shared.CandleMetadata, entryThreshold uint32, exitThreshold uint32) error {
+	record := newAuditRecord(reaction, meta, entryThreshold, exitThreshold)
+	signal, confluence, reasons, err := e.evaluatePriceReversal(ctx, reaction, meta, min(entryThreshold, exitThreshold), record)
 	if err != nil {
-		return fmt.Errorf("evaluating price reversal reaction: %v", err)
+		return fmt.Errorf("evaluating price reversal reaction: %w", err)
 	}
 
-	e.cfg.Logger.Info().Msgf("price reversal confluence – (%d), signal status – %v", confluence, signal)
+	record.Confluence = confluence
+	defer e.writeAudit(record)
+
+	e.reactionLogger(reaction.CorrelationID).Info().Msgf("price reversal confluence – (%d), signal status – %v", confluence, signal)
 
 	if signal {
-		skew, err := e.fetchMarketSkew(reaction.Market)
+		// A reversal at support is bullish, a reversal at resistance is bearish.
+		bias := shared.Long
+		if reaction.LevelKind == shared.Resistance {
+			bias = shared.Short
+		}
+
+		confidence, err := e.evaluateConfidence(ctx, reaction, level, bias, confluence, entryThreshold)
+		if err != nil {
+			return fmt.Errorf("evaluating confidence: %v", err)
+		}
+
+		e.reactionLogger(reaction.CorrelationID).Info().Msgf("price reversal confidence – (%.2f)", confidence)
+
+		skew, err := e.fetchMarketSkew(ctx, reaction.Market)
 		if err != nil {
 			return fmt.Errorf("fetching market skew: %v", err)
 		}
+		record.Skew = skew.String()
 
 		switch {
 		case (skew == shared.NeutralSkew || skew == shared.LongSkewed) && reaction.LevelKind == shared.Support:
 			// Signal a long position on a confirmed support level reversal if the market is
 			// neutral skewed or already long skewed.
-			direction := shared.Long
-			stopLoss, pointsRange, err := e.estimateStopLoss(reaction, meta)
-			if err != nil {
-				return fmt.Errorf("estimating stop loss: %v", err)
-			}
-
-			signal := shared.NewEntrySignal(reaction.Market, reaction.Timeframe, direction,
-				reaction.CurrentPrice, reasons, confluence, reaction.CreatedOn, stopLoss, pointsRange)
-			e.cfg.SendEntrySignal(signal)
-			select {
-			case <-signal.Status:
-			case <-time.After(shared.TimeoutDuration):
-				return fmt.Errorf("timed out waiting for entry signal status")
-			}
+			return e.signalEntry(ctx, reaction, level, meta, skew, shared.Long, reasons, confluence,
+				confidence, entryThreshold, record)
 
 		case skew == shared.LongSkewed && reaction.LevelKind == shared.Resistance:
 			// A confirmed resistance level reversal for a long skewed market acts as an exit condition.
-			direction := shared.Long
-			signal := shared.NewExitSignal(reaction.Market, reaction.Timeframe, direction,
-				reaction.CurrentPrice, reasons, confluence, reaction.CreatedOn)
-			e.cfg.SendExitSignal(signal)
-			select {
-			case <-signal.Status:
-			case <-time.After(shared.TimeoutDuration):
-				return fmt.Errorf("timed out waiting for entry signal status")
-			}
+			return e.signalExit(ctx, reaction, shared.Long, reasons, confluence, confidence,
+				entryThreshold, exitThreshold, record)
 
 		case (skew == shared.NeutralSkew || skew == shared.ShortSkewed) && reaction.LevelKind == shared.Resistance:
 			// Signal a short position on a confirmed resistance reversal if the market is
 			// neutral skewed or already short skewed.
-			direction := shared.Short
-			stopLoss, pointsRange, err := e.estimateStopLoss(reaction, meta)
-			if err != nil {
-				return fmt.Errorf("estimating stop loss: %v", err)
-			}
-
-			signal := shared.NewEntrySignal(reaction.Market, reaction.Timeframe, direction,
-				reaction.CurrentPrice, reasons, confluence, reaction.CreatedOn, stopLoss, pointsRange)
-			e.cfg.SendEntrySignal(signal)
-			select {
-			case <-signal.Status:
-			case <-time.After(shared.TimeoutDuration):
-				return fmt.Errorf("timed out waiting for entry signal status")
-			}
+			return e.signalEntry(ctx, reaction, level, meta, skew, shared.Short, reasons, confluence,
+				confidence, entryThreshold, record)
 
 		case skew == shared.ShortSkewed && reaction.LevelKind == shared.Support:
 			// A confirmed support reversal for a short skewed market acts as an exit condition.
-			direction := shared.Short
-			signal := shared.NewExitSignal(reaction.Market, reaction.Timeframe, direction,
-				reaction.CurrentPrice, reasons, confluence, reaction.CreatedOn)
-			e.cfg.SendExitSignal(signal)
-			select {
-			case <-signal.Status:
-			case <-time.After(shared.TimeoutDuration):
-				return fmt.Errorf("timed out waiting for entry signal status")
-			}
+			return e.signalExit(ctx, reaction, shared.Short, reasons, confluence, confidence,
+				entryThreshold, exitThreshold, record)
 		}
 	}
 
@@ -504,59 +1603,65 @@ func (e *Engine) evaluatePriceReversalStrength(reaction *shared.ReactionAtFocus,
 
 // evaluateBreakStrength determines whether a break has enough confluences to be
 // classified as strong. An associated entry or exit signal is generated and relayed for it based on
-// the skew of the associated market.
-func (e *Engine) evaluateBreakStrength(reaction *shared.ReactionAtFocus, meta []*shared.CandleMetadata, minConfluenceThreshold uint32) error {
-	signal, confluence, reasons, err := e.evaluateLevelBreak(reaction, meta, minConfluenceThreshold)
+// the skew of the associated market. The level is nil for reactions at dynamic levels.
+func (e *Engine) evaluateBreakStrength(ctx context.Context, reaction *shared.ReactionAtFocus, level *shared.Level, meta []*shared.CandleMetadata, entryThreshold uint32, exitThreshold uint32) error {
+	record := newAuditRecord(reaction, meta, entryThreshold, exitThreshold)
+	signal, confluence, reasons, err := e.evaluateLevelBreak(ctx, reaction, meta, min(entryThreshold, exitThreshold), record)
 	if err != nil {
-		return fmt.Errorf("evaluating break reaction: %v", err)
+		return fmt.Errorf("evaluating break reaction: %w", err)
 	}
 
-	e.cfg.Logger.Info().Msgf("break confluence – (%d), signal status – %v", confluence, signal)
+	record.Confluence = confluence
+	defer e.writeAudit(record)
+
+	e.reactionLogger(reaction.CorrelationID).Info().Msgf("break confluence – (%d), signal status – %v", confluence, signal)
 
 	if signal {
-		skew, err := e.fetchMarketSkew(reaction.Market)
+		// A break of resistance is bullish, a break of support is bearish.
+		bias := shared.Short
+		if reaction.LevelKind == shared.Resistance {
+			bias = shared.Long
+		}
+
+		confidence, err := e.evaluateConfidence(ctx, reaction, level, bias, confluence, entryThreshold)
+		if err != nil {
+			return fmt.Errorf("evaluating confidence: %v", err)
+		}
+
+		e.reactionLogger(reaction.CorrelationID).Info().Msgf("break confidence – (%.2f)", confidence)
+
+		if confluence >= entryThreshold {
+			e.recordStructureBreak(ctx, reaction.Market, bias)
+		}
+
+		skew, err := e.fetchMarketSkew(ctx, reaction.Market)
 		if err != nil {
 			return fmt.Errorf("fetching market skew: %v", err)
 		}
+		record.Skew = skew.String()
 
 		switch {
 		case (skew == shared.NeutralSkew || skew == shared.LongSkewed) && reaction.LevelKind == shared.Resistance:
 			// Signal a long position on a confirmed resistance level break if the market is
 			// neutral skewed or already long skewed.
-			direction := shared.Long
-			stopLoss, pointsRange, err := e.estimateStopLoss(reaction, meta)
-			if err != nil {
-				return fmt.Errorf("estimating stop loss: %v", err)
-			}
+			return e.signalEntry(ctx, reaction, level, meta, skew, shared.Long, reasons, confluence,
+				confidence, entryThreshold, record)
 
-			signal := shared.NewEntrySignal(reaction.Market, reaction.Timeframe, direction,
-				reaction.CurrentPrice, reasons, confluence, reaction.CreatedOn, stopLoss, pointsRange)
-			e.cfg.SendEntrySignal(signal)
 		case skew == shared.LongSkewed && reaction.LevelKind == shared.Support:
 			// A confirmed support break for a long skewed market acts as an exit condition.
-			direction := shared.Long
-			signal := shared.NewExitSignal(reaction.Market, reaction.Timeframe, direction,
-				reaction.CurrentPrice, reasons, confluence, reaction.CreatedOn)
-			e.cfg.SendExitSignal(signal)
+			return e.signalExit(ctx, reaction, shared.Long, reasons, confluence, confidence,
+				entryThreshold, exitThreshold, record)
+
 		case (skew == shared.NeutralSkew || skew == shared.ShortSkewed) && reaction.LevelKind == shared.Support:
 			// Signal a short position on a confirmed support break if the market is
 			// neutral skewed or already short skewed.
-			direction := shared.Short
-			stopLoss, pointsRange, err := e.estimateStopLoss(reaction, meta)
-			if err != nil {
-				return fmt.Errorf("estimating stop loss: %v", err)
-			}
-
-			signal := shared.NewEntrySignal(reaction.Market, reaction.Timeframe, direction,
-				reaction.CurrentPrice, reasons, confluence, reaction.CreatedOn, stopLoss, pointsRange)
-			e.cfg.SendEntrySignal(signal)
+			return e.signalEntry(ctx, reaction, level, meta, skew, shared.Short, reasons, confluence,
+				confidence, entryThreshold, record)
 
 		case skew == shared.ShortSkewed && reaction.LevelKind == shared.Resistance:
 			// A confirmed resistance break for a short skewed market acts as an exit condition.
-			direction := shared.Short
-			signal := shared.NewExitSignal(reaction.Market, reaction.Timeframe, direction,
-				reaction.CurrentPrice, reasons, confluence, reaction.CreatedOn)
-			e.cfg.SendExitSignal(signal)
+			return e.signalExit(ctx, reaction, shared.Short, reasons, confluence, confidence,
+				entryThreshold, exitThreshold, record)
 		}
 	}
 
@@ -564,33 +1669,49 @@ func (e *Engine) evaluateBreakStrength(reaction *shared.ReactionAtFocus, meta []
 }
 
 // handleReactionAtLevel processes the provided reaction at level signal.
-func (e *Engine) handleReactionAtLevel(reaction *shared.ReactionAtLevel) error {
+func (e *Engine) handleReactionAtLevel(ctx context.Context, reaction *shared.ReactionAtLevel) error {
 	defer func() {
 		reaction.Status <- shared.Processed
 	}()
 
-	e.cfg.Logger.Info().Msgf("%s level reaction detected @ %.2f",
+	if !e.isTracked(reaction.Market) {
+		return fmt.Errorf("no market found with name %s for level reaction", reaction.Market)
+	}
+
+	if e.Paused(reaction.Market) {
+		e.reactionLogger(reaction.CorrelationID).Info().Msgf("dropping level reaction for paused market %s", reaction.Market)
+		return nil
+	}
+
+	thresholds := e.thresholds.Load()
+	exits := e.exitThresholds()
+
+	e.reactionLogger(reaction.CorrelationID).Info().Msgf("%s level reaction detected @ %.2f",
 		reaction.Level.Kind.String(), reaction.Level.Price)
 
-	meta, err := e.fetchCandleMetadata(reaction.Market, reaction.Timeframe)
+	meta, err := e.fetchCandleMetadata(ctx, reaction.Market, reaction.Timeframe, 0)
 	if err != nil {
 		return fmt.Errorf("fetching candle metadata: %v", err)
 	}
 
 	switch reaction.Reaction {
-	case shared.Reversal:
-		err := e.evaluatePriceReversalStrength(&reaction.ReactionAtFocus, meta, minLevelReversalConfluence)
+	case shared.Reversal, shared.Sweep:
+		// Re-entries at a level that recently stopped out a position require stronger confirmation.
+		entryThreshold := e.reentryThreshold(reaction, thresholds.LevelReversal)
+		err := e.evaluatePriceReversalStrength(ctx, &reaction.ReactionAtFocus, reaction.Level, meta, entryThreshold,
+			exitThreshold(exits.LevelReversal, thresholds.LevelReversal))
 		if err != nil {
-			return fmt.Errorf("evaluating price reversal at vwap strength: %v", err)
+			return e.skipUntracked(&reaction.ReactionAtFocus, fmt.Errorf("evaluating price reversal at vwap strength: %w", err))
 		}
 	case shared.Break:
-		err := e.evaluateBreakStrength(&reaction.ReactionAtFocus, meta, minLevelBreakConfluence)
+		err := e.evaluateBreakStrength(ctx, &reaction.ReactionAtFocus, reaction.Level, meta, thresholds.LevelBreak,
+			exitThreshold(exits.LevelBreak, thresholds.LevelBreak))
 		if err != nil {
-			return fmt.Errorf("evaluating level break strength: %v", err)
+			return e.skipUntracked(&reaction.ReactionAtFocus, fmt.Errorf("evaluating level break strength: %w", err))
 		}
 	case shared.Chop:
 		// Do nothing.
-		e.cfg.Logger.Info().Msgf("chop level reaction encountered for market %s", reaction.Market)
+		e.reactionLogger(reaction.CorrelationID).Info().Msgf("chop level reaction encountered for market %s", reaction.Market)
 	}
 
 	reaction.ApplyPriceReaction()
@@ -599,109 +1720,229 @@ func (e *Engine) handleReactionAtLevel(reaction *shared.ReactionAtLevel) error {
 }
 
 // handleReactionAtVWAP processes the provided reaction at vwap signal.
-func (e *Engine) handleReactionAtVWAP(reaction *shared.ReactionAtVWAP) error {
+func (e *Engine) handleReactionAtVWAP(ctx context.Context, reaction *shared.ReactionAtVWAP) error {
 	defer func() {
 		reaction.Status <- shared.Processed
 	}()
 
-	e.cfg.Logger.Info().Msgf("vwap reaction detected @ %.2f", reaction.VWAPData[0].Value)
+	if !e.isTracked(reaction.Market) {
+		return fmt.Errorf("no market found with name %s for vwap reaction", reaction.Market)
+	}
+
+	if e.Paused(reaction.Market) {
+		e.reactionLogger(reaction.CorrelationID).Info().Msgf("dropping vwap reaction for paused market %s", reaction.Market)
+		return nil
+	}
+
+	thresholds := e.thresholds.Load()
+	exits := e.exitThresholds()
+
+	e.reactionLogger(reaction.CorrelationID).Info().Msgf("vwap reaction detected @ %.2f", reaction.VWAPData[0].Value)
 
-	meta, err := e.fetchCandleMetadata(reaction.Market, reaction.Timeframe)
+	meta, err := e.fetchCandleMetadata(ctx, reaction.Market, reaction.Timeframe, 0)
 	if err != nil {
 		return fmt.Errorf("fetching candle metadata: %v", err)
 	}
 
 	switch reaction.Reaction {
 	case shared.Reversal:
-		err := e.evaluatePriceReversalStrength(&reaction.ReactionAtFocus, meta, minVWAPReversalConfluence)
+		err := e.evaluatePriceReversalStrength(ctx, &reaction.ReactionAtFocus, nil, meta, thresholds.VWAPReversal,
+			exitThreshold(exits.VWAPReversal, thresholds.VWAPReversal))
 		if err != nil {
-			return fmt.Errorf("evaluating price reversal at vwap strength: %v", err)
+			return e.skipUntracked(&reaction.ReactionAtFocus, fmt.Errorf("evaluating price reversal at vwap strength: %w", err))
 		}
 	case shared.Break:
-		err := e.evaluateBreakStrength(&reaction.ReactionAtFocus, meta, minVWAPBreakConfluence)
+		err := e.evaluateBreakStrength(ctx, &reaction.ReactionAtFocus, nil, meta, thresholds.VWAPBreak,
+			exitThreshold(exits.VWAPBreak, thresholds.VWAPBreak))
 		if err != nil {
-			return fmt.Errorf("evaluating vwap break strength: %v", err)
+			return e.skipUntracked(&reaction.ReactionAtFocus, fmt.Errorf("evaluating vwap break strength: %w", err))
 		}
 	case shared.Chop:
 		// Do nothing.
-		e.cfg.Logger.Info().Msgf("chop vwap reaction encountered for market %s", reaction.Market)
+		e.reactionLogger(reaction.CorrelationID).Info().Msgf("chop vwap reaction encountered for market %s", reaction.Market)
 	}
 
 	return nil
 }
 
 // handleReactionAtImbalance processes the provided reaction at imbalance signal.
-func (e *Engine) handleReactionAtImbalance(reaction *shared.ReactionAtImbalance) error {
+func (e *Engine) handleReactionAtImbalance(ctx context.Context, reaction *shared.ReactionAtImbalance) error {
 	defer func() {
 		reaction.Status <- shared.Processed
 	}()
 
-	e.cfg.Logger.Info().Msgf("%s imbalance @ [%.2f,%.2f] reaction detected on the %s timeframe",
+	if !e.isTracked(reaction.Market) {
+		return fmt.Errorf("no market found with name %s for imbalance reaction", reaction.Market)
+	}
+
+	if e.Paused(reaction.Market) {
+		e.reactionLogger(reaction.CorrelationID).Info().Msgf("dropping imbalance reaction for paused market %s", reaction.Market)
+		return nil
+	}
+
+	thresholds := e.thresholds.Load()
+	exits := e.exitThresholds()
+
+	e.reactionLogger(reaction.CorrelationID).Info().Msgf("%s imbalance @ [%.2f,%.2f] reaction detected on the %s timeframe",
 		reaction.Imbalance.Sentiment.String(), reaction.Imbalance.High,
 		reaction.Imbalance.Low, reaction.Imbalance.Timeframe.String())
 
-	meta, err := e.fetchCandleMetadata(reaction.Market, reaction.Timeframe)
+	meta, err := e.fetchCandleMetadata(ctx, reaction.Market, reaction.Timeframe, 0)
 	if err != nil {
 		return fmt.Errorf("fetching candle metadata: %v", err)
 	}
 
 	switch reaction.Reaction {
 	case shared.Reversal:
-		err := e.evaluatePriceReversalStrength(&reaction.ReactionAtFocus, meta, minVWAPReversalConfluence)
+		err := e.evaluatePriceReversalStrength(ctx, &reaction.ReactionAtFocus, nil, meta, thresholds.ImbalanceReversal,
+			exitThreshold(exits.ImbalanceReversal, thresholds.ImbalanceReversal))
 		if err != nil {
-			return fmt.Errorf("evaluating price reversal at imbalance strength: %v", err)
+			return e.skipUntracked(&reaction.ReactionAtFocus, fmt.Errorf("evaluating price reversal at imbalance strength: %w", err))
 		}
 	case shared.Break:
-		err := e.evaluateBreakStrength(&reaction.ReactionAtFocus, meta, minVWAPBreakConfluence)
+		err := e.evaluateBreakStrength(ctx, &reaction.ReactionAtFocus, nil, meta, thresholds.ImbalanceBreak,
+			exitThreshold(exits.ImbalanceBreak, thresholds.ImbalanceBreak))
 		if err != nil {
-			return fmt.Errorf("evaluating imbalance break strength: %v", err)
+			return e.skipUntracked(&reaction.ReactionAtFocus, fmt.Errorf("evaluating imbalance break strength: %w", err))
 		}
 	case shared.Chop:
 		// Do nothing.
-		e.cfg.Logger.Info().Msgf("chop imbalance reaction encountered for market %s", reaction.Market)
+		e.reactionLogger(reaction.CorrelationID).Info().Msgf("chop imbalance reaction encountered for market %s", reaction.Market)
+	}
+
+	return nil
+}
+
+// reactionLogger returns the engine logger tagged with the provided reaction correlation id.
+func (e *Engine) reactionLogger(correlationID string) *zerolog.Logger {
+	logger := e.cfg.Logger.With().Str(shared.CorrelationIDKey, correlationID).Logger()
+	return &logger
+}
+
+// skipUntracked drops the provided reaction if it could not be evaluated because its timeframe
+// is not tracked for its market, any other error is returned as is.
+func (e *Engine) skipUntracked(reaction *shared.ReactionAtFocus, err error) error {
+	if !errors.Is(err, shared.ErrTimeframeNotTracked) {
+		return err
 	}
 
+	e.reactionLogger(reaction.CorrelationID).Info().Msgf("skipping %s reaction for %s, the %s timeframe is not tracked",
+		reaction.Reaction.String(), reaction.Market, reaction.Timeframe.String())
+
 	return nil
 }
 
+// reportError logs the provided processing error and relays it to the error sink.
+func (e *Engine) reportError(err error) {
+	e.cfg.Logger.Error().Err(err).Send()
+	shared.ReportError(e.cfg.ErrorSink, err, e.drops)
+}
+
+// runReactionHandler runs the provided reaction handler bounded by the reaction timeout and
+// releases the handler's worker slot once it returns.
+//
+// The handler is cancelled when it times out so a handler blocked on a downstream consumer
+// returns instead of holding on to its worker slot indefinitely.
+func (e *Engine) runReactionHandler(ctx context.Context, focus string, market string, handler func(context.Context) error) {
+	defer func() {
+		<-e.workers
+		e.inflight.Done()
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, e.reactionTimeout)
+	defer cancel()
+
+	err := handler(ctx)
+	if err == nil {
+		return
+	}
+
+	if ctx.Err() != nil {
+		e.cfg.Logger.Warn().Msgf("cancelled %s %s reaction: %v", market, focus, err)
+		return
+	}
+
+	e.reportError(err)
+}
+
+// dispatchReaction hands the provided reaction handler to a worker once one is available. It
+// returns false if the provided context is done before a worker becomes available.
+func (e *Engine) dispatchReaction(ctx context.Context, focus string, market string, handler func(context.Context) error) bool {
+	select {
+	case e.workers <- struct{}{}:
+	case <-ctx.Done():
+		return false
+	}
+
+	e.inflight.Add(1)
+	go e.runReactionHandler(ctx, focus, market, handler)
+
+	return true
+}
+
+// drain dispatches the reactions buffered when the engine is shut down and waits for
+// in-flight reactions to complete, bounded by the drain grace period.
+func (e *Engine) drain(ctx context.Context) {
+	ctx, cancel := shared.NewDrainContext(ctx, e.cfg.DrainGracePeriod)
+	defer cancel()
+
+	for {
+		dispatched := true
+		select {
+		case signal := <-e.reactionAtLevelSignals:
+			dispatched = e.dispatchReaction(ctx, "level", signal.Market, func(ctx context.Context) error {
+				return e.handleReactionAtLevel(ctx, &signal)
+			})
+		case signal := <-e.reactionAtVWAPSignals:
+			dispatched = e.dispatchReaction(ctx, "vwap", signal.Market, func(ctx context.Context) error {
+				return e.handleReactionAtVWAP(ctx, &signal)
+			})
+		case signal := <-e.reactionAtImbalanceSignals:
+			dispatched = e.dispatchReaction(ctx, "imbalance", signal.Market, func(ctx context.Context) error {
+				return e.handleReactionAtImbalance(ctx, &signal)
+			})
+		default:
+			if !shared.Await(ctx, &e.inflight) {
+				e.cfg.Logger.Warn().Msg("drain grace period elapsed with in-flight reactions")
+			}
+			return
+		}
+
+		if !dispatched {
+			e.cfg.Logger.Warn().Msg("drain grace period elapsed with buffered reactions")
+			return
+		}
+	}
+}
+
 // Run manages the lifecycle processes of the market engine.
 func (e *Engine) Run(ctx context.Context) {
+	// Reaction handlers outlive the cancellation of the engine so they can complete while
+	// the engine drains.
+	handlerCtx := context.WithoutCancel(ctx)
+
 	for {
+		e.runIterations.Inc()
+
 		select {
 		case <-ctx.Done():
+			e.drain(ctx)
 			return
 		case signal := <-e.reactionAtLevelSignals:
 			// use workers to process reactions at levels concurrently.
-			e.workers <- struct{}{}
-			go func(signal shared.ReactionAtLevel) {
-				err := e.handleReactionAtLevel(&signal)
-				if err != nil {
-					e.cfg.Logger.Error().Err(err).Send()
-				}
-				<-e.workers
-			}(signal)
+			e.dispatchReaction(handlerCtx, "level", signal.Market, func(ctx context.Context) error {
+				return e.handleReactionAtLevel(ctx, &signal)
+			})
 		case signal := <-e.reactionAtVWAPSignals:
 			// use workers to process reactions at vwap concurrently.
-			e.workers <- struct{}{}
-			go func(signal shared.ReactionAtVWAP) {
-				err := e.handleReactionAtVWAP(&signal)
-				if err != nil {
-					e.cfg.Logger.Error().Err(err).Send()
-				}
-				<-e.workers
-			}(signal)
+			e.dispatchReaction(handlerCtx, "vwap", signal.Market, func(ctx context.Context) error {
+				return e.handleReactionAtVWAP(ctx, &signal)
+			})
 		case signal := <-e.reactionAtImbalanceSignals:
 			// use workers to process reactions at imbalances concurrently.
-			e.workers <- struct{}{}
-			go func(signal shared.ReactionAtImbalance) {
-				err := e.handleReactionAtImbalance(&signal)
-				if err != nil {
-					e.cfg.Logger.Error().Err(err).Send()
-				}
-				<-e.workers
-			}(signal)
-		default:
-			// fallthrough
+			e.dispatchReaction(handlerCtx, "imbalance", signal.Market, func(ctx context.Context) error {
+				return e.handleReactionAtImbalance(ctx, &signal)
+			})
 		}
 	}
 }
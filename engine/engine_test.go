@@ -1,12 +1,18 @@
 package engine
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/dnldd/entry/shared"
 	"github.com/peterldowns/testy/assert"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"go.uber.org/atomic"
 )
@@ -36,6 +42,7 @@ func setupEngine(avgVolume *float64, candleMeta []*shared.CandleMetadata, market
 	}
 
 	cfg := &EngineConfig{
+		Markets:               []string{"^GSPC"},
 		RequestCandleMetadata: requestCandleMetadata,
 		RequestAverageVolume:  requestAvgVolume,
 		SendEntrySignal:       signalEntry,
@@ -158,6 +165,189 @@ func TestEngine(t *testing.T) {
 	<-done
 }
 
+func TestEngineErrorSink(t *testing.T) {
+	avgVolume := float64(4)
+	candleMeta := []*shared.CandleMetadata{}
+	marketSkew := shared.NeutralSkew
+	eng, _, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+
+	sink := make(chan error, 1)
+	eng.cfg.ErrorSink = sink
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go eng.Run(ctx)
+
+	// Ensure a failed reaction handler delivers its error to the error sink.
+	reaction := shared.ReactionAtLevel{
+		ReactionAtFocus: shared.ReactionAtFocus{
+			Market:    "^AAPL",
+			Timeframe: shared.FiveMinute,
+			Reaction:  shared.Reversal,
+			Status:    make(chan shared.StatusCode, 1),
+		},
+		Level: shared.NewLevel("^AAPL", float64(3), float64(14)),
+	}
+	eng.SignalReactionAtLevel(reaction)
+
+	select {
+	case err := <-sink:
+		assert.Error(t, err)
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for reaction error")
+	}
+}
+
+func TestEngineCancelsBlockedReactions(t *testing.T) {
+	avgVolume := float64(4)
+	asiaSessionTime, _ := generateSessionTimes(t)
+	candleMeta := []*shared.CandleMetadata{
+		{Kind: shared.Doji, Sentiment: shared.Bearish, Momentum: shared.Low, Volume: 1, High: 5, Low: 4, Date: asiaSessionTime},
+		{Kind: shared.Hammer, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 4, High: 6, Low: 4, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 5, High: 9, Low: 6, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.High, Volume: 8, High: 14, Low: 9, Date: asiaSessionTime},
+	}
+	marketSkew := shared.LongSkewed
+	eng, _, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+	eng.reactionTimeout = time.Millisecond * 50
+	// Signal status waits outlast the test so only cancellation can release them.
+	eng.cfg.Timeouts = &shared.Timeouts{SignalStatus: time.Minute}
+
+	// A downstream consumer that accepts entry signals but never processes them.
+	accepted := atomic.NewUint32(0)
+	eng.cfg.SendEntrySignal = func(signal shared.EntrySignal) {
+		accepted.Inc()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go eng.Run(ctx)
+
+	// Ensure blocked reaction handlers are cancelled and return, releasing their workers and
+	// allowing more reactions than workers to be handled.
+	market := "^GSPC"
+	reactions := make([]shared.ReactionAtLevel, maxWorkers+4)
+	for idx := range reactions {
+		reactions[idx] = shared.ReactionAtLevel{
+			ReactionAtFocus: shared.ReactionAtFocus{
+				Market:        market,
+				LevelKind:     shared.Support,
+				CurrentPrice:  float64(14),
+				Timeframe:     shared.FiveMinute,
+				PriceMovement: []shared.PriceMovement{shared.Above, shared.Above, shared.Above, shared.Above},
+				Reaction:      shared.Reversal,
+				CreatedOn:     asiaSessionTime,
+				Status:        make(chan shared.StatusCode, 1),
+			},
+			Level: shared.NewLevel(market, float64(3), float64(14)),
+		}
+		eng.SignalReactionAtLevel(reactions[idx])
+	}
+
+	for idx := range reactions {
+		select {
+		case <-reactions[idx].Status:
+		case <-time.After(time.Second * 5):
+			t.Fatalf("reaction handler %d did not return, %d of %d reactions handled with %d workers busy",
+				idx, accepted.Load(), len(reactions), len(eng.workers))
+		}
+	}
+	assert.Equal(t, accepted.Load(), uint32(len(reactions)))
+
+	// Ensure no handler outlives its cancellation.
+	awaitCtx, awaitCancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer awaitCancel()
+	assert.True(t, shared.Await(awaitCtx, &eng.inflight))
+	assert.Equal(t, len(eng.workers), 0)
+}
+
+func TestEngineDrain(t *testing.T) {
+	avgVolume := float64(4)
+	asiaSessionTime, _ := generateSessionTimes(t)
+	candleMeta := []*shared.CandleMetadata{
+		{Kind: shared.Doji, Sentiment: shared.Bearish, Momentum: shared.Low, Volume: 1, High: 5, Low: 4, Date: asiaSessionTime},
+		{Kind: shared.Hammer, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 4, High: 6, Low: 4, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 5, High: 9, Low: 6, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.High, Volume: 8, High: 14, Low: 9, Date: asiaSessionTime},
+	}
+	marketSkew := shared.LongSkewed
+	eng, entrySignals, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	market := "^GSPC"
+	reactions := 4
+	for range reactions {
+		eng.SignalReactionAtLevel(shared.ReactionAtLevel{
+			ReactionAtFocus: shared.ReactionAtFocus{
+				Market:        market,
+				LevelKind:     shared.Support,
+				CurrentPrice:  float64(14),
+				Timeframe:     shared.FiveMinute,
+				PriceMovement: []shared.PriceMovement{shared.Above, shared.Above, shared.Above, shared.Above},
+				Reaction:      shared.Reversal,
+				CreatedOn:     asiaSessionTime,
+				Status:        make(chan shared.StatusCode, 1),
+			},
+			Level: shared.NewLevel(market, float64(3), float64(14)),
+		})
+	}
+
+	// Ensure reactions buffered at shutdown are evaluated before the engine returns.
+	eng.Run(ctx)
+	assert.Equal(t, len(entrySignals), reactions)
+}
+
+func TestEngineRunIdle(t *testing.T) {
+	avgVolume := float64(10)
+	candleMeta := []*shared.CandleMetadata{}
+	marketSkew := shared.NeutralSkew
+	eng, _, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		eng.Run(ctx)
+		close(done)
+	}()
+
+	// Ensure a reaction sent once the engine is idle is handled.
+	time.Sleep(time.Millisecond * 50)
+	market := "^GSPC"
+	reaction := shared.ReactionAtLevel{
+		ReactionAtFocus: shared.ReactionAtFocus{
+			Market:    market,
+			LevelKind: shared.Support,
+			Timeframe: shared.FiveMinute,
+			Reaction:  shared.Chop,
+			Status:    make(chan shared.StatusCode, 1),
+		},
+		Level: shared.NewLevel(market, float64(3), float64(14)),
+	}
+	eng.SignalReactionAtLevel(reaction)
+	select {
+	case <-reaction.Status:
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for the idle engine to handle the reaction")
+	}
+
+	// Ensure the idle engine blocks instead of spinning.
+	iterations := eng.runIterations.Load()
+	time.Sleep(time.Millisecond * 50)
+	assert.True(t, eng.runIterations.Load()-iterations <= 1)
+
+	// Ensure the idle engine returns promptly once cancelled.
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the idle engine to return")
+	}
+}
+
 func TestFillManagerChannels(t *testing.T) {
 	avgVolume := float64(10)
 	candleMeta := []*shared.CandleMetadata{}
@@ -249,6 +439,463 @@ func TestFillManagerChannels(t *testing.T) {
 	assert.Equal(t, len(eng.reactionAtLevelSignals), bufferSize)
 	assert.Equal(t, len(eng.reactionAtVWAPSignals), bufferSize)
 	assert.Equal(t, len(eng.reactionAtImbalanceSignals), bufferSize)
+
+	// Ensure reactions dropped at capacity are counted per channel.
+	for range 2 {
+		eng.SignalReactionAtLevel(levelReaction)
+	}
+	assert.Equal(t, eng.Dropped(), map[string]uint64{
+		"reaction at level":     3,
+		"reaction at vwap":      1,
+		"reaction at imbalance": 1,
+	})
+}
+
+func TestBlockOnCapacity(t *testing.T) {
+	avgVolume := float64(10)
+	candleMeta := []*shared.CandleMetadata{}
+	marketSkew := shared.NeutralSkew
+	eng, _, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+	eng.cfg.CapacityPolicy = BlockOnCapacity
+	eng.cfg.CapacityTimeout = time.Second * 2
+
+	market := "^GSPC"
+	reaction := func(price float64) shared.ReactionAtLevel {
+		return shared.ReactionAtLevel{
+			ReactionAtFocus: shared.ReactionAtFocus{
+				Market:       market,
+				Timeframe:    shared.FiveMinute,
+				LevelKind:    shared.Support,
+				CurrentPrice: price,
+				Reaction:     shared.Reversal,
+				Status:       make(chan shared.StatusCode, 1),
+			},
+			Level: shared.NewLevel(market, price, price+1),
+		}
+	}
+
+	// Fill the reaction at level signals channel.
+	for range bufferSize {
+		eng.SignalReactionAtLevel(reaction(1))
+	}
+	assert.Equal(t, len(eng.reactionAtLevelSignals), bufferSize)
+
+	// Ensure a reaction relayed at capacity waits on capacity instead of being dropped.
+	done := make(chan struct{})
+	go func() {
+		eng.SignalReactionAtLevel(reaction(2))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("expected the reaction relayed at capacity to block")
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	<-eng.reactionAtLevelSignals
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the reaction relayed at capacity to be requeued")
+	}
+
+	assert.Equal(t, len(eng.reactionAtLevelSignals), bufferSize)
+	var last shared.ReactionAtLevel
+	for range bufferSize {
+		last = <-eng.reactionAtLevelSignals
+	}
+	assert.Equal(t, last.CurrentPrice, float64(2))
+
+	// Ensure a reaction relayed at capacity is dropped once the capacity timeout elapses.
+	eng.cfg.CapacityTimeout = time.Millisecond * 10
+	for range bufferSize + 1 {
+		eng.SignalReactionAtLevel(reaction(1))
+	}
+	assert.Equal(t, len(eng.reactionAtLevelSignals), bufferSize)
+	assert.Equal(t, eng.Dropped()["reaction at level"], uint64(1))
+}
+
+func TestThresholdsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(thresholds *Thresholds)
+		wantErr bool
+	}{
+		{
+			name:    "default thresholds",
+			modify:  func(thresholds *Thresholds) {},
+			wantErr: false,
+		},
+		{
+			name:    "zero level reversal threshold",
+			modify:  func(thresholds *Thresholds) { thresholds.LevelReversal = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "zero vwap break threshold",
+			modify:  func(thresholds *Thresholds) { thresholds.VWAPBreak = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "zero imbalance reversal threshold",
+			modify:  func(thresholds *Thresholds) { thresholds.ImbalanceReversal = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "negative average volume percent",
+			modify:  func(thresholds *Thresholds) { thresholds.AverageVolumePercent = -0.3 },
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			thresholds := DefaultThresholds()
+			test.modify(thresholds)
+			err := thresholds.Validate()
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSetThresholds(t *testing.T) {
+	avgVolume := float64(10)
+	candleMeta := []*shared.CandleMetadata{}
+	marketSkew := shared.NeutralSkew
+	eng, _, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+
+	// Ensure the engine uses the default thresholds when none are provided.
+	assert.Equal(t, eng.Thresholds(), *DefaultThresholds())
+
+	// Ensure nil or invalid thresholds cannot be set.
+	err := eng.SetThresholds(nil)
+	assert.Error(t, err)
+
+	err = eng.SetThresholds(&Thresholds{})
+	assert.Error(t, err)
+	assert.Equal(t, eng.Thresholds(), *DefaultThresholds())
+
+	// Ensure valid thresholds can be swapped in.
+	thresholds := DefaultThresholds()
+	thresholds.LevelReversal = 8
+	thresholds.AverageVolumePercent = 0.5
+	err = eng.SetThresholds(thresholds)
+	assert.NoError(t, err)
+	assert.Equal(t, eng.Thresholds(), *thresholds)
+
+	// Ensure modifying the provided thresholds does not affect the engine.
+	thresholds.LevelReversal = 10
+	assert.Equal(t, eng.Thresholds().LevelReversal, uint32(8))
+
+	// Ensure evaluations use the swapped thresholds.
+	confluence := uint32(0)
+	reasons := map[shared.Reason]uint32{}
+	err = eng.evaluateVolumeStrength(float64(10), float64(4), &confluence, reasons)
+	assert.NoError(t, err)
+	assert.Equal(t, confluence, uint32(1))
+}
+
+func TestSetWeights(t *testing.T) {
+	avgVolume := float64(10)
+	candleMeta := []*shared.CandleMetadata{}
+	marketSkew := shared.NeutralSkew
+	eng, _, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+
+	// Ensure the engine uses the default weights when none are provided.
+	assert.Equal(t, eng.ConfidenceWeights(), *DefaultConfidenceWeights())
+	assert.Equal(t, eng.LevelWeights(), DefaultLevelWeights())
+
+	// Ensure nil or invalid weights cannot be set.
+	err := eng.SetConfidenceWeights(nil)
+	assert.Error(t, err)
+	err = eng.SetConfidenceWeights(&ConfidenceWeights{})
+	assert.Error(t, err)
+	assert.Equal(t, eng.ConfidenceWeights(), *DefaultConfidenceWeights())
+	err = eng.SetLevelWeights(nil)
+	assert.Error(t, err)
+	assert.Equal(t, eng.LevelWeights(), DefaultLevelWeights())
+
+	// Ensure valid weights can be swapped in.
+	weights := &ConfidenceWeights{Confluence: 1}
+	err = eng.SetConfidenceWeights(weights)
+	assert.NoError(t, err)
+	assert.Equal(t, eng.ConfidenceWeights(), *weights)
+	levelWeights := map[shared.LevelSource]uint32{shared.DailyLevel: 3}
+	err = eng.SetLevelWeights(levelWeights)
+	assert.NoError(t, err)
+	assert.Equal(t, eng.LevelWeights(), levelWeights)
+
+	// Ensure modifying the provided weights does not affect the engine.
+	weights.Confluence = 2
+	levelWeights[shared.DailyLevel] = 5
+	assert.Equal(t, eng.ConfidenceWeights().Confluence, float64(1))
+	assert.Equal(t, eng.levelWeight(shared.DailyLevel), uint32(3))
+	assert.Equal(t, eng.levelWeight(shared.AsiaLevel), uint32(0))
+}
+
+func TestParseThresholds(t *testing.T) {
+	custom := DefaultThresholds()
+	custom.LevelBreak = 8
+	custom.AverageVolumePercent = 0.5
+
+	tests := []struct {
+		name    string
+		entries []string
+		want    *Thresholds
+		wantErr bool
+	}{
+		{"no entries", nil, nil, false},
+		{"custom thresholds", []string{"levelbreak=8", "averagevolumepercent=0.5"}, custom, false},
+		{"missing separator", []string{"levelbreak"}, nil, true},
+		{"unknown threshold", []string{"sweep=4"}, nil, true},
+		{"invalid confluence", []string{"levelbreak=high"}, nil, true},
+		{"invalid average volume percent", []string{"averagevolumepercent=high"}, nil, true},
+		{"zero threshold", []string{"vwapbreak=0"}, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			thresholds, err := ParseThresholds(test.entries)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, thresholds, test.want)
+		})
+	}
+}
+
+func TestEngineAddRemoveMarket(t *testing.T) {
+	avgVolume := float64(10)
+	candleMeta := []*shared.CandleMetadata{}
+	marketSkew := shared.NeutralSkew
+	eng, _, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+
+	asiaSessionTime, _ := generateSessionTimes(t)
+
+	ixic := "^IXIC"
+	reaction := &shared.ReactionAtLevel{
+		ReactionAtFocus: shared.ReactionAtFocus{
+			Market:        ixic,
+			Timeframe:     shared.FiveMinute,
+			LevelKind:     shared.Support,
+			PriceMovement: []shared.PriceMovement{shared.Above, shared.Below, shared.Above, shared.Below},
+			Reaction:      shared.Chop,
+			CreatedOn:     asiaSessionTime,
+			Status:        make(chan shared.StatusCode, 1),
+		},
+		Level: &shared.Level{
+			Market: ixic,
+			Price:  float64(2),
+			Kind:   shared.Support,
+		},
+	}
+
+	// Ensure reactions for untracked markets are not processed.
+	err := eng.handleReactionAtLevel(context.Background(), reaction)
+	assert.Error(t, err)
+	<-reaction.Status
+
+	// Ensure a market can be added at runtime.
+	err = eng.AddMarket(ixic)
+	assert.NoError(t, err)
+
+	// Ensure an already tracked market cannot be added.
+	err = eng.AddMarket(ixic)
+	assert.Error(t, err)
+
+	err = eng.handleReactionAtLevel(context.Background(), reaction)
+	assert.NoError(t, err)
+	<-reaction.Status
+
+	// Ensure a market can be removed at runtime.
+	err = eng.RemoveMarket(ixic)
+	assert.NoError(t, err)
+
+	// Ensure an untracked market cannot be removed.
+	err = eng.RemoveMarket(ixic)
+	assert.Error(t, err)
+
+	// Ensure reactions for removed markets are not processed.
+	err = eng.handleReactionAtLevel(context.Background(), reaction)
+	assert.Error(t, err)
+	<-reaction.Status
+}
+
+func TestEnginePauseResume(t *testing.T) {
+	avgVolume := float64(4)
+	asiaSessionTime, _ := generateSessionTimes(t)
+	candleMeta := []*shared.CandleMetadata{
+		{Kind: shared.Doji, Sentiment: shared.Bearish, Momentum: shared.Low, Volume: 1, High: 5, Low: 4, Date: asiaSessionTime},
+		{Kind: shared.Hammer, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 4, High: 6, Low: 4, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 5, High: 9, Low: 6, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.High, Volume: 8, High: 14, Low: 9, Date: asiaSessionTime},
+	}
+	marketSkew := shared.LongSkewed
+	eng, entrySignals, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+
+	market := "^GSPC"
+	level := &shared.Level{Market: market, Price: 3, Kind: shared.Support}
+	reversal := func() *shared.ReactionAtLevel {
+		return &shared.ReactionAtLevel{
+			ReactionAtFocus: shared.ReactionAtFocus{
+				Market:        market,
+				LevelKind:     shared.Support,
+				CurrentPrice:  14,
+				Timeframe:     shared.FiveMinute,
+				PriceMovement: []shared.PriceMovement{shared.Above, shared.Above, shared.Above, shared.Above},
+				Reaction:      shared.Reversal,
+				CreatedOn:     asiaSessionTime,
+				Status:        make(chan shared.StatusCode, 1),
+			},
+			Level: level,
+		}
+	}
+
+	// Ensure untracked markets cannot be paused or resumed.
+	err := eng.Pause("^IXIC")
+	assert.Error(t, err)
+	err = eng.Resume("^IXIC")
+	assert.Error(t, err)
+
+	// Ensure reactions for paused markets are dropped.
+	err = eng.Pause(market)
+	assert.NoError(t, err)
+	assert.True(t, eng.Paused(market))
+
+	reaction := reversal()
+	err = eng.handleReactionAtLevel(context.Background(), reaction)
+	assert.NoError(t, err)
+	<-reaction.Status
+	assert.Equal(t, len(entrySignals), 0)
+	assert.Equal(t, level.Reversals.Load(), uint32(0))
+
+	// Ensure reactions for resumed markets are evaluated.
+	err = eng.Resume(market)
+	assert.NoError(t, err)
+	assert.False(t, eng.Paused(market))
+
+	reaction = reversal()
+	err = eng.handleReactionAtLevel(context.Background(), reaction)
+	assert.NoError(t, err)
+	<-reaction.Status
+	assert.Equal(t, len(entrySignals), 1)
+	assert.Equal(t, level.Reversals.Load(), uint32(1))
+}
+
+func TestReactionLogCorrelation(t *testing.T) {
+	avgVolume := float64(4)
+	asiaSessionTime, _ := generateSessionTimes(t)
+	candleMeta := []*shared.CandleMetadata{
+		{Kind: shared.Doji, Sentiment: shared.Bearish, Momentum: shared.Low, Volume: 1, High: 5, Low: 4, Date: asiaSessionTime},
+		{Kind: shared.Hammer, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 4, High: 6, Low: 4, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 5, High: 9, Low: 6, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.High, Volume: 8, High: 14, Low: 9, Date: asiaSessionTime},
+	}
+	marketSkew := shared.LongSkewed
+	eng, entrySignals, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+
+	var logs bytes.Buffer
+	eng.cfg.Logger = zerolog.New(&logs)
+
+	market := "^GSPC"
+	reaction := &shared.ReactionAtLevel{
+		ReactionAtFocus: shared.ReactionAtFocus{
+			CorrelationID: shared.NewCorrelationID(),
+			Market:        market,
+			LevelKind:     shared.Support,
+			CurrentPrice:  14,
+			Timeframe:     shared.FiveMinute,
+			PriceMovement: []shared.PriceMovement{shared.Above, shared.Above, shared.Above, shared.Above},
+			Reaction:      shared.Reversal,
+			CreatedOn:     asiaSessionTime,
+			Status:        make(chan shared.StatusCode, 1),
+		},
+		Level: &shared.Level{Market: market, Price: 3, Kind: shared.Support},
+	}
+
+	err := eng.handleReactionAtLevel(context.Background(), reaction)
+	assert.NoError(t, err)
+	<-reaction.Status
+
+	// Ensure the entry signal carries the correlation id of its reaction.
+	assert.Equal(t, len(entrySignals), 1)
+	signal := <-entrySignals
+	assert.Equal(t, signal.CorrelationID, reaction.CorrelationID)
+
+	// Ensure every log line of the reaction's evaluation is tagged with its correlation id.
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	assert.True(t, len(lines) > 1)
+	for _, line := range lines {
+		var entry map[string]any
+		err := json.Unmarshal([]byte(line), &entry)
+		assert.NoError(t, err)
+		assert.Equal(t, entry[shared.CorrelationIDKey], any(reaction.CorrelationID))
+	}
+}
+
+func TestUntrackedTimeframeReaction(t *testing.T) {
+	avgVolume := float64(4)
+	asiaSessionTime, _ := generateSessionTimes(t)
+	candleMeta := []*shared.CandleMetadata{
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 5, High: 9, Low: 6, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.High, Volume: 8, High: 14, Low: 9, Date: asiaSessionTime},
+	}
+	marketSkew := shared.LongSkewed
+
+	market := "^GSPC"
+	reversal := func() *shared.ReactionAtLevel {
+		return &shared.ReactionAtLevel{
+			ReactionAtFocus: shared.ReactionAtFocus{
+				Market:        market,
+				LevelKind:     shared.Support,
+				CurrentPrice:  14,
+				Timeframe:     shared.OneHour,
+				PriceMovement: []shared.PriceMovement{shared.Above, shared.Above, shared.Above, shared.Above},
+				Reaction:      shared.Reversal,
+				CreatedOn:     asiaSessionTime,
+				Status:        make(chan shared.StatusCode, 1),
+			},
+			Level: &shared.Level{Market: market, Price: 3, Kind: shared.Support},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		err     error
+		wantErr bool
+	}{
+		{"untracked timeframe", fmt.Errorf("%w: no candle snapshot", shared.ErrTimeframeNotTracked), false},
+		{"other failure", errors.New("snapshot unavailable"), true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eng, entrySignals, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+			eng.cfg.RequestAverageVolume = func(req shared.AverageVolumeRequest) {
+				req.Err <- test.err
+			}
+
+			// Ensure reactions on untracked timeframes are skipped cleanly while other
+			// failures are still reported.
+			reaction := reversal()
+			err := eng.handleReactionAtLevel(context.Background(), reaction)
+			<-reaction.Status
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, len(entrySignals), 0)
+		})
+	}
 }
 
 func TestHandleLevelReaction(t *testing.T) {
@@ -278,7 +925,7 @@ func TestHandleLevelReaction(t *testing.T) {
 	}
 
 	// Ensure the engine can handle a price reversal level reaction signal.
-	eng.handleReactionAtLevel(priceReversalReaction)
+	eng.handleReactionAtLevel(context.Background(), priceReversalReaction)
 	<-priceReversalReaction.Status
 
 	breakLevelReaction := &shared.ReactionAtLevel{
@@ -299,29 +946,50 @@ func TestHandleLevelReaction(t *testing.T) {
 	}
 
 	// Ensure the engine can handle a break level reaction signal.
-	eng.handleReactionAtLevel(breakLevelReaction)
+	eng.handleReactionAtLevel(context.Background(), breakLevelReaction)
 	<-breakLevelReaction.Status
 
-	chopLevelReaction := &shared.ReactionAtLevel{
+	chopLevelReaction := &shared.ReactionAtLevel{
+		ReactionAtFocus: shared.ReactionAtFocus{
+			Market:        market,
+			Timeframe:     shared.FiveMinute,
+			LevelKind:     shared.Resistance,
+			PriceMovement: []shared.PriceMovement{shared.Above, shared.Below, shared.Above, shared.Below},
+			Reaction:      shared.Chop,
+			CreatedOn:     asiaSessionTime,
+			Status:        make(chan shared.StatusCode, 1),
+		},
+		Level: &shared.Level{
+			Market: market,
+			Price:  float64(2),
+			Kind:   shared.Resistance,
+		},
+	}
+
+	// Ensure the engine can handle a break chop level reaction signal.
+	eng.handleReactionAtLevel(context.Background(), chopLevelReaction)
+	<-chopLevelReaction.Status
+
+	sweepLevelReaction := &shared.ReactionAtLevel{
 		ReactionAtFocus: shared.ReactionAtFocus{
 			Market:        market,
 			Timeframe:     shared.FiveMinute,
-			LevelKind:     shared.Resistance,
-			PriceMovement: []shared.PriceMovement{shared.Above, shared.Below, shared.Above, shared.Below},
-			Reaction:      shared.Chop,
+			LevelKind:     shared.Support,
+			PriceMovement: []shared.PriceMovement{shared.Above, shared.Below, shared.Above, shared.Above},
+			Reaction:      shared.Sweep,
 			CreatedOn:     asiaSessionTime,
 			Status:        make(chan shared.StatusCode, 1),
 		},
 		Level: &shared.Level{
 			Market: market,
 			Price:  float64(2),
-			Kind:   shared.Resistance,
+			Kind:   shared.Support,
 		},
 	}
 
-	// Ensure the engine can handle a break chop level reaction signal.
-	eng.handleReactionAtLevel(chopLevelReaction)
-	<-chopLevelReaction.Status
+	// Ensure the engine can handle a sweep level reaction signal.
+	eng.handleReactionAtLevel(context.Background(), sweepLevelReaction)
+	<-sweepLevelReaction.Status
 }
 
 func TestHandleVWAPReaction(t *testing.T) {
@@ -364,7 +1032,7 @@ func TestHandleVWAPReaction(t *testing.T) {
 	}
 
 	// Ensure the engine can handle a reversal vwap reaction signal.
-	eng.handleReactionAtVWAP(&reversalVWAPReaction)
+	eng.handleReactionAtVWAP(context.Background(), &reversalVWAPReaction)
 	<-reversalVWAPReaction.Status
 
 	breakVWAPReaction := shared.ReactionAtVWAP{
@@ -398,7 +1066,7 @@ func TestHandleVWAPReaction(t *testing.T) {
 	}
 
 	// Ensure the engine can handle a vwap break reaction signal.
-	eng.handleReactionAtVWAP(&breakVWAPReaction)
+	eng.handleReactionAtVWAP(context.Background(), &breakVWAPReaction)
 	<-breakVWAPReaction.Status
 
 	chopVWAPReaction := shared.ReactionAtVWAP{
@@ -432,7 +1100,7 @@ func TestHandleVWAPReaction(t *testing.T) {
 	}
 
 	// Ensure the engine can handle a vwap chop reaction signal.
-	eng.handleReactionAtVWAP(&chopVWAPReaction)
+	eng.handleReactionAtVWAP(context.Background(), &chopVWAPReaction)
 	<-chopVWAPReaction.Status
 }
 
@@ -470,7 +1138,7 @@ func TestHandleImbalanceReaction(t *testing.T) {
 	}
 
 	// Ensure the engine can handle an imbalance reversal reaction signal.
-	eng.handleReactionAtImbalance(&reversalImbalanceReaction)
+	eng.handleReactionAtImbalance(context.Background(), &reversalImbalanceReaction)
 	<-reversalImbalanceReaction.Status
 
 	breakImbalanceReaction := shared.ReactionAtImbalance{
@@ -498,7 +1166,7 @@ func TestHandleImbalanceReaction(t *testing.T) {
 	}
 
 	// Ensure the engine can handle an imbalance break reaction signal.
-	eng.handleReactionAtImbalance(&breakImbalanceReaction)
+	eng.handleReactionAtImbalance(context.Background(), &breakImbalanceReaction)
 	<-breakImbalanceReaction.Status
 
 	chopImbalanceReaction := shared.ReactionAtImbalance{
@@ -526,7 +1194,7 @@ func TestHandleImbalanceReaction(t *testing.T) {
 	}
 
 	// Ensure the engine can handle an imbalance break reaction signal.
-	eng.handleReactionAtImbalance(&chopImbalanceReaction)
+	eng.handleReactionAtImbalance(context.Background(), &chopImbalanceReaction)
 	<-chopImbalanceReaction.Status
 }
 
@@ -572,7 +1240,7 @@ func TestEstimateStopLoss(t *testing.T) {
 			Date:      asianSessionTime,
 		},
 		{
-			Kind:      shared.Pinbar,
+			Kind:      shared.Hammer,
 			Sentiment: shared.Bullish,
 			Momentum:  shared.Medium,
 			Volume:    float64(4),
@@ -582,7 +1250,7 @@ func TestEstimateStopLoss(t *testing.T) {
 			Date:      asianSessionTime,
 		},
 		{
-			Kind:      shared.Pinbar,
+			Kind:      shared.Hammer,
 			Sentiment: shared.Bullish,
 			Momentum:  shared.Medium,
 			Volume:    float64(4),
@@ -615,7 +1283,7 @@ func TestEstimateStopLoss(t *testing.T) {
 			Date:      asianSessionTime,
 		},
 		{
-			Kind:      shared.Pinbar,
+			Kind:      shared.ShootingStar,
 			Sentiment: shared.Bearish,
 			Momentum:  shared.Medium,
 			Volume:    float64(4),
@@ -625,7 +1293,7 @@ func TestEstimateStopLoss(t *testing.T) {
 			Date:      asianSessionTime,
 		},
 		{
-			Kind:      shared.Pinbar,
+			Kind:      shared.ShootingStar,
 			Sentiment: shared.Bearish,
 			Momentum:  shared.Medium,
 			Volume:    float64(4),
@@ -761,7 +1429,7 @@ func TestEstimateStopLoss(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		sl, pr, err := eng.estimateStopLoss(&test.levelReaction.ReactionAtFocus, test.meta)
+		sl, pr, err := eng.estimateStopLoss(&test.levelReaction.ReactionAtFocus, test.levelReaction.Level, test.meta)
 		if test.wantErr && err == nil {
 			t.Errorf("%s: expected an error, got none", test.name)
 		}
@@ -808,7 +1476,7 @@ func TestEvaluateHighVolumeSession(t *testing.T) {
 
 	// Ensure confluence points are not awarded for asian session.
 	confluence := uint32(0)
-	reasons := map[shared.Reason]struct{}{}
+	reasons := map[shared.Reason]uint32{}
 	err := eng.evaluateHighVolumeSession(&levelReaction.ReactionAtFocus, &confluence, reasons)
 	assert.NoError(t, err)
 	assert.Equal(t, confluence, uint32(0))
@@ -828,6 +1496,687 @@ func TestEvaluateHighVolumeSession(t *testing.T) {
 	}
 
 	assert.Equal(t, keys[0], shared.HighVolumeSession)
+
+	// Ensure confluence points are not awarded to crypto markets, which have no high volume window.
+	crypto := "BTCUSD"
+	eng.cfg.AssetClasses = map[string]shared.AssetClass{crypto: shared.Crypto}
+	levelReaction.Market = crypto
+
+	confluence = uint32(0)
+	reasons = map[shared.Reason]uint32{}
+	err = eng.evaluateHighVolumeSession(&levelReaction.ReactionAtFocus, &confluence, reasons)
+	assert.NoError(t, err)
+	assert.Equal(t, confluence, uint32(0))
+	assert.Equal(t, len(reasons), 0)
+}
+
+func TestEvaluateCustomHighVolumeWindows(t *testing.T) {
+	avgVolume := float64(10)
+	candleMeta := []*shared.CandleMetadata{}
+	marketSkew := shared.NeutralSkew
+	eng, _, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+	eng.cfg.HighVolumeWindows = []shared.SessionWindow{
+		{Start: "18:00", End: "20:00"},
+		{Start: "23:00", End: "01:00"},
+	}
+
+	asianSessionTime, londonSessionTime := generateSessionTimes(t)
+	overnightTime := time.Date(asianSessionTime.Year(), asianSessionTime.Month(), asianSessionTime.Day()+1,
+		0, 30, 0, 0, asianSessionTime.Location())
+
+	tests := []struct {
+		name      string
+		market    string
+		createdOn time.Time
+		want      uint32
+	}{
+		{"inside evening window", "^GSPC", asianSessionTime, 1},
+		{"inside overnight window", "^GSPC", overnightTime, 1},
+		{"outside windows", "^GSPC", londonSessionTime, 0},
+		{"windows apply to all asset classes", "BTCUSD", asianSessionTime, 1},
+	}
+
+	eng.cfg.AssetClasses = map[string]shared.AssetClass{"BTCUSD": shared.Crypto}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reaction := &shared.ReactionAtFocus{
+				Market:    test.market,
+				Timeframe: shared.FiveMinute,
+				LevelKind: shared.Support,
+				Reaction:  shared.Reversal,
+				CreatedOn: test.createdOn,
+			}
+
+			// Ensure confluence is awarded inside any configured window and withheld outside.
+			confluence := uint32(0)
+			reasons := map[shared.Reason]uint32{}
+			err := eng.evaluateHighVolumeSession(reaction, &confluence, reasons)
+			assert.NoError(t, err)
+			assert.Equal(t, confluence, test.want)
+			assert.Equal(t, len(reasons), int(test.want))
+		})
+	}
+}
+
+func TestEstimateTightestStop(t *testing.T) {
+	avgVolume := float64(10)
+	asianSessionTime, _ := generateSessionTimes(t)
+	market := "^GSPC"
+
+	// bullishMeta returns bullish candle metadata with a signal candle low at the provided
+	// price and a range low of 2.
+	bullishMeta := func(signalLow float64) []*shared.CandleMetadata {
+		return []*shared.CandleMetadata{
+			{Kind: shared.Doji, Sentiment: shared.Bearish, Momentum: shared.Low, Volume: 2, High: 6, Low: 2, Date: asianSessionTime},
+			{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.High, Volume: 6, High: 13, Low: signalLow, Date: asianSessionTime},
+		}
+	}
+
+	// bearishMeta returns bearish candle metadata with a signal candle high at the provided
+	// price and a range high of 15.
+	bearishMeta := func(signalHigh float64) []*shared.CandleMetadata {
+		return []*shared.CandleMetadata{
+			{Kind: shared.Doji, Sentiment: shared.Bullish, Momentum: shared.Low, Volume: 2, High: 15, Low: 11, Date: asianSessionTime},
+			{Kind: shared.Marubozu, Sentiment: shared.Bearish, Momentum: shared.High, Volume: 6, High: signalHigh, Low: 4, Date: asianSessionTime},
+		}
+	}
+
+	supportReversal := &shared.ReactionAtFocus{
+		Market:       market,
+		Timeframe:    shared.FiveMinute,
+		LevelKind:    shared.Support,
+		Reaction:     shared.Reversal,
+		CreatedOn:    asianSessionTime,
+		CurrentPrice: float64(14),
+	}
+	resistanceReversal := &shared.ReactionAtFocus{
+		Market:       market,
+		Timeframe:    shared.FiveMinute,
+		LevelKind:    shared.Resistance,
+		Reaction:     shared.Reversal,
+		CreatedOn:    asianSessionTime,
+		CurrentPrice: float64(5),
+	}
+	support := shared.NewLevel(market, 8, 14)
+	resistance := shared.NewLevel(market, 10, 5)
+
+	tests := []struct {
+		name         string
+		tightestStop bool
+		reaction     *shared.ReactionAtFocus
+		level        *shared.Level
+		meta         []*shared.CandleMetadata
+		stopLoss     float64
+		pointsRange  float64
+	}{
+		{
+			name:         "signal candle stop is tighter and beyond support",
+			tightestStop: true,
+			reaction:     supportReversal,
+			level:        support,
+			meta:         bullishMeta(7),
+			stopLoss:     6,
+			pointsRange:  8,
+		},
+		{
+			name:         "range stop required beyond support",
+			tightestStop: true,
+			reaction:     supportReversal,
+			level:        support,
+			meta:         bullishMeta(9.5),
+			stopLoss:     1,
+			pointsRange:  13,
+		},
+		{
+			name:         "signal candle stop is tighter and beyond resistance",
+			tightestStop: true,
+			reaction:     resistanceReversal,
+			level:        resistance,
+			meta:         bearishMeta(12),
+			stopLoss:     13,
+			pointsRange:  8,
+		},
+		{
+			name:         "range stop required beyond resistance",
+			tightestStop: true,
+			reaction:     resistanceReversal,
+			level:        resistance,
+			meta:         bearishMeta(8.5),
+			stopLoss:     16,
+			pointsRange:  11,
+		},
+		{
+			name:         "signal candle stop for a dynamic level",
+			tightestStop: true,
+			reaction:     supportReversal,
+			level:        nil,
+			meta:         bullishMeta(9.5),
+			stopLoss:     8.5,
+			pointsRange:  5.5,
+		},
+		{
+			name:         "signal candle stop when disabled",
+			tightestStop: false,
+			reaction:     supportReversal,
+			level:        support,
+			meta:         bullishMeta(9.5),
+			stopLoss:     8.5,
+			pointsRange:  5.5,
+		},
+	}
+
+	marketSkew := shared.NeutralSkew
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eng, _, _ := setupEngine(&avgVolume, test.meta, &marketSkew)
+			eng.cfg.TightestStop = test.tightestStop
+
+			stopLoss, pointsRange, err := eng.estimateStopLoss(test.reaction, test.level, test.meta)
+			assert.NoError(t, err)
+			assert.Equal(t, stopLoss, test.stopLoss)
+			assert.Equal(t, pointsRange, test.pointsRange)
+		})
+	}
+}
+
+func TestEstimateWickStopBuffer(t *testing.T) {
+	avgVolume := float64(10)
+	asianSessionTime, _ := generateSessionTimes(t)
+	market := "^GSPC"
+
+	// Clean candles have no wicks beyond their bodies.
+	cleanBullish := []*shared.CandleMetadata{
+		{Kind: shared.Doji, Sentiment: shared.Bearish, Momentum: shared.Low, Volume: 2, Open: 6, High: 6, Low: 2, Close: 2, Date: asianSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.High, Volume: 6, Open: 7, High: 13, Low: 7, Close: 13, Date: asianSessionTime},
+	}
+	// Wicky candles have lower wicks of 2 and 1, averaging 1.5.
+	wickyBullish := []*shared.CandleMetadata{
+		{Kind: shared.Doji, Sentiment: shared.Bearish, Momentum: shared.Low, Volume: 2, Open: 5, High: 6, Low: 2, Close: 4, Date: asianSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.High, Volume: 6, Open: 8, High: 13, Low: 7, Close: 12.5, Date: asianSessionTime},
+	}
+	cleanBearish := []*shared.CandleMetadata{
+		{Kind: shared.Doji, Sentiment: shared.Bullish, Momentum: shared.Low, Volume: 2, Open: 11, High: 15, Low: 11, Close: 15, Date: asianSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bearish, Momentum: shared.High, Volume: 6, Open: 12, High: 12, Low: 4, Close: 4, Date: asianSessionTime},
+	}
+	// Wicky candles have upper wicks of 1 and 2, averaging 1.5.
+	wickyBearish := []*shared.CandleMetadata{
+		{Kind: shared.Doji, Sentiment: shared.Bullish, Momentum: shared.Low, Volume: 2, Open: 12, High: 15, Low: 11, Close: 14, Date: asianSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bearish, Momentum: shared.High, Volume: 6, Open: 10, High: 12, Low: 4, Close: 4.5, Date: asianSessionTime},
+	}
+
+	supportReversal := &shared.ReactionAtFocus{
+		Market:       market,
+		Timeframe:    shared.FiveMinute,
+		LevelKind:    shared.Support,
+		Reaction:     shared.Reversal,
+		CreatedOn:    asianSessionTime,
+		CurrentPrice: float64(14),
+	}
+	resistanceReversal := &shared.ReactionAtFocus{
+		Market:       market,
+		Timeframe:    shared.FiveMinute,
+		LevelKind:    shared.Resistance,
+		Reaction:     shared.Reversal,
+		CreatedOn:    asianSessionTime,
+		CurrentPrice: float64(5),
+	}
+
+	tests := []struct {
+		name           string
+		wickStopBuffer float64
+		reaction       *shared.ReactionAtFocus
+		meta           []*shared.CandleMetadata
+		stopLoss       float64
+		pointsRange    float64
+	}{
+		{
+			name:           "fixed buffer when disabled",
+			wickStopBuffer: 0,
+			reaction:       supportReversal,
+			meta:           wickyBullish,
+			stopLoss:       6,
+			pointsRange:    8,
+		},
+		{
+			name:           "clean bullish series falls back on the fixed buffer",
+			wickStopBuffer: 2,
+			reaction:       supportReversal,
+			meta:           cleanBullish,
+			stopLoss:       6,
+			pointsRange:    8,
+		},
+		{
+			name:           "wicky bullish series widens the buffer",
+			wickStopBuffer: 2,
+			reaction:       supportReversal,
+			meta:           wickyBullish,
+			stopLoss:       4,
+			pointsRange:    10,
+		},
+		{
+			name:           "clean bearish series falls back on the fixed buffer",
+			wickStopBuffer: 2,
+			reaction:       resistanceReversal,
+			meta:           cleanBearish,
+			stopLoss:       13,
+			pointsRange:    8,
+		},
+		{
+			name:           "wicky bearish series widens the buffer",
+			wickStopBuffer: 2,
+			reaction:       resistanceReversal,
+			meta:           wickyBearish,
+			stopLoss:       15,
+			pointsRange:    10,
+		},
+	}
+
+	marketSkew := shared.NeutralSkew
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eng, _, _ := setupEngine(&avgVolume, test.meta, &marketSkew)
+			eng.cfg.WickStopBuffer = test.wickStopBuffer
+
+			// Ensure stops are buffered beyond the typical wick of the reaction's candles.
+			stopLoss, pointsRange, err := eng.estimateStopLoss(test.reaction, nil, test.meta)
+			assert.NoError(t, err)
+			assert.Equal(t, stopLoss, test.stopLoss)
+			assert.Equal(t, pointsRange, test.pointsRange)
+		})
+	}
+}
+
+func TestEstimateStopLossInstruments(t *testing.T) {
+	avgVolume := float64(10)
+	asianSessionTime, _ := generateSessionTimes(t)
+	coarse := "^NDX"
+	fine := "^GSPC"
+
+	// Stops off the tick grid, the signal candle low places a bullish stop at 6.3 and the
+	// signal candle high places a bearish stop at 13.1.
+	bullish := []*shared.CandleMetadata{
+		{Kind: shared.Doji, Sentiment: shared.Bearish, Momentum: shared.Low, Volume: 2, Open: 6, High: 6, Low: 2, Close: 2, Date: asianSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.High, Volume: 6, Open: 7.3, High: 13, Low: 7.3, Close: 13, Date: asianSessionTime},
+	}
+	bearish := []*shared.CandleMetadata{
+		{Kind: shared.Doji, Sentiment: shared.Bullish, Momentum: shared.Low, Volume: 2, Open: 11, High: 15, Low: 11, Close: 15, Date: asianSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bearish, Momentum: shared.High, Volume: 6, Open: 12.1, High: 12.1, Low: 4, Close: 4, Date: asianSessionTime},
+	}
+
+	newReaction := func(market string, kind shared.LevelKind, price float64) *shared.ReactionAtFocus {
+		return &shared.ReactionAtFocus{
+			Market:       market,
+			Timeframe:    shared.FiveMinute,
+			LevelKind:    kind,
+			Reaction:     shared.Reversal,
+			CreatedOn:    asianSessionTime,
+			CurrentPrice: price,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		instruments map[string]shared.Instrument
+		reaction    *shared.ReactionAtFocus
+		meta        []*shared.CandleMetadata
+		stopLoss    float64
+		pointsRange float64
+	}{
+		{
+			name:        "bullish stop rounds down to the coarse tick",
+			instruments: map[string]shared.Instrument{coarse: {TickSize: 0.5}, fine: {TickSize: 0.25}},
+			reaction:    newReaction(coarse, shared.Support, 14),
+			meta:        bullish,
+			stopLoss:    6,
+			pointsRange: 8,
+		},
+		{
+			name:        "bullish stop rounds down to the fine tick",
+			instruments: map[string]shared.Instrument{coarse: {TickSize: 0.5}, fine: {TickSize: 0.25}},
+			reaction:    newReaction(fine, shared.Support, 14),
+			meta:        bullish,
+			stopLoss:    6.25,
+			pointsRange: 7.75,
+		},
+		{
+			name:        "bearish stop rounds up to the coarse tick",
+			instruments: map[string]shared.Instrument{coarse: {TickSize: 0.5}, fine: {TickSize: 0.25}},
+			reaction:    newReaction(coarse, shared.Resistance, 5),
+			meta:        bearish,
+			stopLoss:    13.5,
+			pointsRange: 8.5,
+		},
+		{
+			name:        "bearish stop rounds up to the fine tick",
+			instruments: map[string]shared.Instrument{coarse: {TickSize: 0.5}, fine: {TickSize: 0.25}},
+			reaction:    newReaction(fine, shared.Resistance, 5),
+			meta:        bearish,
+			stopLoss:    13.25,
+			pointsRange: 8.25,
+		},
+		{
+			name:        "sub-minimum bullish stop is widened",
+			instruments: map[string]shared.Instrument{fine: {TickSize: 0.25, MinStopDistance: 10}},
+			reaction:    newReaction(fine, shared.Support, 14),
+			meta:        bullish,
+			stopLoss:    4,
+			pointsRange: 10,
+		},
+		{
+			name:        "sub-minimum bearish stop is widened",
+			instruments: map[string]shared.Instrument{coarse: {TickSize: 0.5, MinStopDistance: 9.25}},
+			reaction:    newReaction(coarse, shared.Resistance, 5),
+			meta:        bearish,
+			stopLoss:    14.5,
+			pointsRange: 9.5,
+		},
+	}
+
+	marketSkew := shared.NeutralSkew
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eng, _, _ := setupEngine(&avgVolume, test.meta, &marketSkew)
+			eng.cfg.Instruments = test.instruments
+
+			// Ensure stops are fit to the tick size and minimum stop distance of the instrument.
+			stopLoss, pointsRange, err := eng.estimateStopLoss(test.reaction, nil, test.meta)
+			assert.NoError(t, err)
+			assert.Equal(t, stopLoss, test.stopLoss)
+			assert.Equal(t, pointsRange, test.pointsRange)
+		})
+	}
+}
+
+func TestEvaluateCoincidentFocuses(t *testing.T) {
+	avgVolume := float64(10)
+	candleMeta := []*shared.CandleMetadata{}
+	marketSkew := shared.NeutralSkew
+	eng, _, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+
+	reaction := &shared.ReactionAtFocus{
+		Market:    "^GSPC",
+		Timeframe: shared.FiveMinute,
+		LevelKind: shared.Support,
+		Reaction:  shared.Reversal,
+	}
+
+	// Ensure confluence points are not awarded for a reaction without coincident focuses.
+	confluence := uint32(0)
+	reasons := map[shared.Reason]uint32{}
+	eng.evaluateCoincidentFocuses(reaction, &confluence, reasons)
+	assert.Equal(t, confluence, uint32(0))
+	assert.Equal(t, len(reasons), 0)
+
+	// Ensure a confluence point is awarded for each coincident focus.
+	reaction.CoincidentFocuses = []shared.FocusKind{shared.VWAPFocus, shared.ImbalanceFocus}
+	eng.evaluateCoincidentFocuses(reaction, &confluence, reasons)
+	assert.Equal(t, confluence, uint32(2))
+	_, ok := reasons[shared.CoincidentFocus]
+	assert.True(t, ok)
+}
+
+func TestEvaluateImbalanceFreshness(t *testing.T) {
+	avgVolume := float64(10)
+	candleMeta := []*shared.CandleMetadata{}
+	marketSkew := shared.NeutralSkew
+	eng, _, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+
+	// fill returns a pointer to the provided fill percentage.
+	fill := func(percent float64) *float64 {
+		return &percent
+	}
+
+	tests := []struct {
+		name       string
+		fill       *float64
+		confluence uint32
+	}{
+		{
+			name:       "reaction at a non-imbalance focus",
+			fill:       nil,
+			confluence: 0,
+		},
+		{
+			name:       "reaction at an unfilled imbalance",
+			fill:       fill(0),
+			confluence: 2,
+		},
+		{
+			name:       "reaction at a largely unfilled imbalance",
+			fill:       fill(25),
+			confluence: 2,
+		},
+		{
+			name:       "reaction at a partially filled imbalance",
+			fill:       fill(40),
+			confluence: 1,
+		},
+		{
+			name:       "reaction at a mostly filled imbalance",
+			fill:       fill(80),
+			confluence: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reaction := &shared.ReactionAtFocus{
+				Market:        "^GSPC",
+				Timeframe:     shared.FiveMinute,
+				LevelKind:     shared.Support,
+				Reaction:      shared.Reversal,
+				ImbalanceFill: test.fill,
+			}
+
+			confluence := uint32(0)
+			reasons := map[shared.Reason]uint32{}
+			eng.evaluateImbalanceFreshness(reaction, &confluence, reasons)
+			assert.Equal(t, confluence, test.confluence)
+
+			// Ensure the awarded confluence is accounted for by the fresh imbalance reason.
+			assert.Equal(t, reasons[shared.FreshImbalance], test.confluence)
+		})
+	}
+}
+
+func TestEvaluateImbalanceStacking(t *testing.T) {
+	avgVolume := float64(10)
+	candleMeta := []*shared.CandleMetadata{}
+	marketSkew := shared.NeutralSkew
+	eng, _, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+
+	tests := []struct {
+		name       string
+		stack      uint32
+		confluence uint32
+	}{
+		{
+			name:       "reaction at another focus",
+			stack:      0,
+			confluence: 0,
+		},
+		{
+			name:       "reaction at a lone imbalance",
+			stack:      1,
+			confluence: 0,
+		},
+		{
+			name:       "reaction at two stacked imbalances",
+			stack:      2,
+			confluence: 1,
+		},
+		{
+			name:       "reaction at many stacked imbalances",
+			stack:      5,
+			confluence: maxStackedImbalanceConfluence,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reaction := &shared.ReactionAtFocus{
+				Market:         "^GSPC",
+				Timeframe:      shared.FiveMinute,
+				LevelKind:      shared.Support,
+				Reaction:       shared.Reversal,
+				ImbalanceStack: test.stack,
+			}
+
+			// Ensure confluence is awarded scaled by the stacked imbalances.
+			confluence := uint32(0)
+			reasons := map[shared.Reason]uint32{}
+			eng.evaluateImbalanceStacking(reaction, &confluence, reasons)
+			assert.Equal(t, confluence, test.confluence)
+			assert.Equal(t, reasons[shared.StackedImbalance], test.confluence)
+		})
+	}
+}
+
+func TestEvaluateOrderBlock(t *testing.T) {
+	avgVolume := float64(10)
+	candleMeta := []*shared.CandleMetadata{}
+	marketSkew := shared.NeutralSkew
+	eng, _, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+
+	tests := []struct {
+		name       string
+		focus      shared.FocusKind
+		confluence uint32
+		orderBlock bool
+	}{
+		{
+			name:       "reaction at an order block",
+			focus:      shared.OrderBlockFocus,
+			confluence: 1,
+			orderBlock: true,
+		},
+		{
+			name:       "reaction at a level",
+			focus:      shared.LevelFocus,
+			confluence: 0,
+			orderBlock: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reaction := &shared.ReactionAtFocus{
+				Market:    "^GSPC",
+				Timeframe: shared.FiveMinute,
+				Focus:     test.focus,
+				LevelKind: shared.Support,
+				Reaction:  shared.Reversal,
+			}
+
+			// Ensure only reactions at order blocks are awarded the order block reason.
+			confluence := uint32(0)
+			reasons := map[shared.Reason]uint32{}
+			eng.evaluateOrderBlock(reaction, &confluence, reasons)
+			assert.Equal(t, confluence, test.confluence)
+			_, ok := reasons[shared.OrderBlock]
+			assert.Equal(t, ok, test.orderBlock)
+		})
+	}
+}
+
+func TestEvaluateVWAPStretch(t *testing.T) {
+	avgVolume := float64(10)
+	candleMeta := []*shared.CandleMetadata{}
+	marketSkew := shared.NeutralSkew
+	eng, _, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+
+	vwap := &shared.VWAP{Value: 100, StdDev: 2}
+
+	tests := []struct {
+		name         string
+		vwap         *shared.VWAP
+		levelKind    shared.LevelKind
+		sentiment    shared.Sentiment
+		currentPrice float64
+		stretch      float64
+		confluence   uint32
+	}{
+		{
+			name:         "bullish reversal stretched below vwap",
+			vwap:         vwap,
+			levelKind:    shared.Support,
+			sentiment:    shared.Bullish,
+			currentPrice: 95,
+			confluence:   1,
+		},
+		{
+			name:         "bearish reversal stretched above vwap",
+			vwap:         vwap,
+			levelKind:    shared.Resistance,
+			sentiment:    shared.Bearish,
+			currentPrice: 105,
+			confluence:   1,
+		},
+		{
+			name:         "bullish reversal near vwap",
+			vwap:         vwap,
+			levelKind:    shared.Support,
+			sentiment:    shared.Bullish,
+			currentPrice: 99,
+			confluence:   0,
+		},
+		{
+			name:         "bullish reversal stretched above vwap",
+			vwap:         vwap,
+			levelKind:    shared.Support,
+			sentiment:    shared.Bullish,
+			currentPrice: 105,
+			confluence:   0,
+		},
+		{
+			name:         "bullish reversal below a custom stretch",
+			vwap:         vwap,
+			levelKind:    shared.Support,
+			sentiment:    shared.Bullish,
+			currentPrice: 95,
+			stretch:      3,
+			confluence:   0,
+		},
+		{
+			name:         "reversal without a standard deviation",
+			vwap:         &shared.VWAP{Value: 100},
+			levelKind:    shared.Support,
+			sentiment:    shared.Bullish,
+			currentPrice: 90,
+			confluence:   0,
+		},
+		{
+			name:         "reaction at a non-vwap focus",
+			vwap:         nil,
+			levelKind:    shared.Support,
+			sentiment:    shared.Bullish,
+			currentPrice: 90,
+			confluence:   0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eng.cfg.VWAPStretch = test.stretch
+			reaction := &shared.ReactionAtFocus{
+				Market:       "^GSPC",
+				Timeframe:    shared.FiveMinute,
+				Focus:        shared.VWAPFocus,
+				LevelKind:    test.levelKind,
+				Reaction:     shared.Reversal,
+				CurrentPrice: test.currentPrice,
+				VWAP:         test.vwap,
+			}
+
+			// Ensure only reversals of price stretched from vwap are awarded the stretch reason.
+			confluence := uint32(0)
+			reasons := map[shared.Reason]uint32{}
+			eng.evaluateVWAPStretch(reaction, test.sentiment, &confluence, reasons)
+			assert.Equal(t, confluence, test.confluence)
+			_, ok := reasons[shared.VWAPStretch]
+			assert.Equal(t, ok, test.confluence > 0)
+		})
+	}
 }
 
 func TestEvaluateVolumeStrength(t *testing.T) {
@@ -839,7 +2188,7 @@ func TestEvaluateVolumeStrength(t *testing.T) {
 	averageVolume := float64(10)
 	volumeDifference := float64(-2)
 	confluence := uint32(0)
-	reasons := map[shared.Reason]struct{}{}
+	reasons := map[shared.Reason]uint32{}
 
 	// Ensure no confluence points are awarded for a volume difference below the average volume.
 	err := eng.evaluateVolumeStrength(averageVolume, volumeDifference, &confluence, reasons)
@@ -886,10 +2235,10 @@ func TestEvaluateCandleVolumeStrength(t *testing.T) {
 	eng, _, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
 
 	confluence := uint32(0)
-	reasons := map[shared.Reason]struct{}{}
+	reasons := map[shared.Reason]uint32{}
 	reactionSentiment := shared.Bullish
 	mediumStrengthCandleMeta := shared.CandleMetadata{
-		Kind:      shared.Pinbar,
+		Kind:      shared.ShootingStar,
 		Sentiment: shared.Bearish,
 		Momentum:  shared.Medium,
 		Volume:    float64(4),
@@ -898,19 +2247,49 @@ func TestEvaluateCandleVolumeStrength(t *testing.T) {
 		Low:       float64(2),
 	}
 
-	// Ensure only candle metadata supporting the reaction sentiment are evaluated.
-	err := eng.evaluateCandleMetadataStrength(mediumStrengthCandleMeta, reactionSentiment, &confluence, reasons)
+	// Ensure only candle metadata supporting the reaction sentiment are evaluated.
+	err := eng.evaluateCandleMetadataStrength(mediumStrengthCandleMeta, reactionSentiment, &confluence, reasons)
+	assert.NoError(t, err)
+	assert.Equal(t, confluence, uint32(0))
+	assert.Equal(t, len(reasons), 0)
+
+	// Ensure a confluence point is awarded for a strong candle structure with medium momemtum.
+	reactionSentiment = shared.Bearish
+	err = eng.evaluateCandleMetadataStrength(mediumStrengthCandleMeta, reactionSentiment, &confluence, reasons)
+	assert.NoError(t, err)
+	assert.Equal(t, confluence, uint32(1))
+	assert.Equal(t, len(reasons), 1)
+
+	// Ensure a shooting star does not strengthen a bullish reaction.
+	confluence = uint32(0)
+	reasons = map[shared.Reason]uint32{}
+	reactionSentiment = shared.Bullish
+	bullishShootingStarMeta := mediumStrengthCandleMeta
+	bullishShootingStarMeta.Sentiment = shared.Bullish
+	err = eng.evaluateCandleMetadataStrength(bullishShootingStarMeta, reactionSentiment, &confluence, reasons)
 	assert.NoError(t, err)
 	assert.Equal(t, confluence, uint32(0))
 	assert.Equal(t, len(reasons), 0)
 
-	// Ensure a confluence point is awarded for a strong candle structure with medium momemtum.
-	reactionSentiment = shared.Bearish
-	err = eng.evaluateCandleMetadataStrength(mediumStrengthCandleMeta, reactionSentiment, &confluence, reasons)
+	// Ensure a hammer strengthens a bullish reaction.
+	hammerMeta := bullishShootingStarMeta
+	hammerMeta.Kind = shared.Hammer
+	err = eng.evaluateCandleMetadataStrength(hammerMeta, reactionSentiment, &confluence, reasons)
 	assert.NoError(t, err)
 	assert.Equal(t, confluence, uint32(1))
 	assert.Equal(t, len(reasons), 1)
 
+	// Ensure a hammer does not strengthen a bearish reaction.
+	confluence = uint32(0)
+	reasons = map[shared.Reason]uint32{}
+	reactionSentiment = shared.Bearish
+	bearishHammerMeta := hammerMeta
+	bearishHammerMeta.Sentiment = shared.Bearish
+	err = eng.evaluateCandleMetadataStrength(bearishHammerMeta, reactionSentiment, &confluence, reasons)
+	assert.NoError(t, err)
+	assert.Equal(t, confluence, uint32(0))
+	assert.Equal(t, len(reasons), 0)
+
 	highStrengthCandleMeta := shared.CandleMetadata{
 		Kind:      shared.Marubozu,
 		Sentiment: shared.Bullish,
@@ -947,7 +2326,7 @@ func TestEvaluatePriceReversalAtLevelConfirmation(t *testing.T) {
 	asianSessionTime, _ := generateSessionTimes(t)
 
 	confluence := uint32(0)
-	reasons := map[shared.Reason]struct{}{}
+	reasons := map[shared.Reason]uint32{}
 	sentiment := shared.Neutral
 	market := "^GSPC"
 	supportLevelReaction := shared.ReactionAtLevel{
@@ -994,7 +2373,7 @@ func TestEvaluatePriceReversalAtLevelConfirmation(t *testing.T) {
 
 	// Ensure bearish price reactions can be confirmed.
 	confluence = 0
-	reasons = map[shared.Reason]struct{}{}
+	reasons = map[shared.Reason]uint32{}
 	sentiment = shared.Neutral
 	err = eng.evaluatePriceReversalConfirmation(&resistanceLevelReaction.ReactionAtFocus, &confluence, &sentiment, reasons)
 	assert.NoError(t, err)
@@ -1005,6 +2384,22 @@ func TestEvaluatePriceReversalAtLevelConfirmation(t *testing.T) {
 
 	assert.Equal(t, slice[0], shared.ReversalAtResistance)
 
+	// Ensure sweeps are confirmed as reversals with an additional liquidity sweep confluence.
+	confluence = 0
+	reasons = map[shared.Reason]uint32{}
+	sentiment = shared.Neutral
+	sweepLevelReaction := supportLevelReaction.ReactionAtFocus
+	sweepLevelReaction.Reaction = shared.Sweep
+	err = eng.evaluatePriceReversalConfirmation(&sweepLevelReaction, &confluence, &sentiment, reasons)
+	assert.NoError(t, err)
+	assert.Equal(t, confluence, uint32(2))
+	assert.Equal(t, sentiment, shared.Bullish)
+
+	slice = extractReasons(reasons)
+	assert.Equal(t, len(slice), 2)
+	assert.In(t, shared.ReversalAtSupport, slice)
+	assert.In(t, shared.LiquiditySweep, slice)
+
 	// Ensure the reversal confirmation errors if the level reaction is not a reversal.
 	invalidReversalLevelReaction := shared.ReactionAtLevel{
 		ReactionAtFocus: shared.ReactionAtFocus{
@@ -1028,9 +2423,9 @@ func TestEvaluatePriceReversalAtLevelConfirmation(t *testing.T) {
 }
 
 func TestExtractReasons(t *testing.T) {
-	reasons := map[shared.Reason]struct{}{}
-	reasons[shared.BearishEngulfing] = struct{}{}
-	reasons[shared.BreakAboveResistance] = struct{}{}
+	reasons := map[shared.Reason]uint32{}
+	reasons[shared.BearishEngulfing] = 1
+	reasons[shared.BreakAboveResistance] = 1
 
 	// Ensure reasons are sliced as epxected from the provided map.
 	slice := extractReasons(reasons)
@@ -1048,9 +2443,10 @@ func TestFetchAverageVolume(t *testing.T) {
 
 	// Ensure average volume requests can be processed.
 	market := "^GSPC"
-	avgVol, err := eng.fetchAverageVolume(market, timeframe)
+	avgVol, volumeScale, err := eng.fetchAverageVolume(context.Background(), market, timeframe)
 	assert.NoError(t, err)
 	assert.Equal(t, avgVol, float64(10))
+	assert.Equal(t, volumeScale, float64(10))
 }
 
 func TestFetchCandleMetadata(t *testing.T) {
@@ -1069,7 +2465,7 @@ func TestFetchCandleMetadata(t *testing.T) {
 			Date:      asiaSessionTime,
 		},
 		{
-			Kind:      shared.Pinbar,
+			Kind:      shared.Hammer,
 			Sentiment: shared.Bullish,
 			Momentum:  shared.Medium,
 			Volume:    float64(4),
@@ -1104,9 +2500,19 @@ func TestFetchCandleMetadata(t *testing.T) {
 
 	// Ensure average volume requests can be processed.
 	market := "^GSPC"
-	meta, err := eng.fetchCandleMetadata(market, timeframe)
+	meta, err := eng.fetchCandleMetadata(context.Background(), market, timeframe, 0)
 	assert.NoError(t, err)
 	assert.Equal(t, len(meta), 4)
+
+	// Ensure the requested lookback is relayed with the request.
+	var lookback uint32
+	eng.cfg.RequestCandleMetadata = func(req shared.CandleMetadataRequest) {
+		lookback = req.Lookback
+		req.Response <- candleMeta
+	}
+	_, err = eng.fetchCandleMetadata(context.Background(), market, timeframe, 12)
+	assert.NoError(t, err)
+	assert.Equal(t, lookback, uint32(12))
 }
 
 func TestFetchMarketSkew(t *testing.T) {
@@ -1117,7 +2523,7 @@ func TestFetchMarketSkew(t *testing.T) {
 
 	// Ensure market skew requests can be processed.
 	market := "^GSPC"
-	avgVol, err := eng.fetchMarketSkew(market)
+	avgVol, err := eng.fetchMarketSkew(context.Background(), market)
 	assert.NoError(t, err)
 	assert.Equal(t, avgVol, shared.NeutralSkew)
 }
@@ -1137,7 +2543,7 @@ func TestEvaluatePriceReversalAtLevel(t *testing.T) {
 			Date:      asiaSessionTime,
 		},
 		{
-			Kind:      shared.Pinbar,
+			Kind:      shared.Hammer,
 			Sentiment: shared.Bullish,
 			Momentum:  shared.Medium,
 			Volume:    float64(4),
@@ -1187,17 +2593,86 @@ func TestEvaluatePriceReversalAtLevel(t *testing.T) {
 	}
 
 	// Ensure price reversal is not evaluated if the meta is an empty slice.
-	signal, _, _, err := eng.evaluatePriceReversal(&levelReaction.ReactionAtFocus, []*shared.CandleMetadata{}, minLevelReversalConfluence)
+	signal, _, _, err := eng.evaluatePriceReversal(context.Background(), &levelReaction.ReactionAtFocus, []*shared.CandleMetadata{}, minLevelReversalConfluence, nil)
 	assert.Error(t, err)
 
 	// Ensure price reversal is evualuated as expected with valid input.
-	signal, confluence, reasons, err := eng.evaluatePriceReversal(&levelReaction.ReactionAtFocus, candleMeta, minLevelReversalConfluence)
+	signal, confluence, reasons, err := eng.evaluatePriceReversal(context.Background(), &levelReaction.ReactionAtFocus, candleMeta, minLevelReversalConfluence, nil)
 	assert.NoError(t, err)
 	assert.In(t, shared.ReversalAtSupport, reasons)
 	assert.In(t, shared.StrongMove, reasons)
 	assert.In(t, shared.StrongVolume, reasons)
 	assert.Equal(t, confluence, uint32(7))
 	assert.Equal(t, signal, true)
+
+	// Ensure a signal passing the confluence threshold is rejected when backed by fewer
+	// distinct reasons than required.
+	eng.cfg.MinDistinctReasons = len(reasons) + 1
+	signal, confluence, _, err = eng.evaluatePriceReversal(context.Background(), &levelReaction.ReactionAtFocus, candleMeta, minLevelReversalConfluence, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, confluence, uint32(7))
+	assert.Equal(t, signal, false)
+
+	// Ensure a signal backed by exactly the required distinct reasons is not rejected.
+	eng.cfg.MinDistinctReasons = len(reasons)
+	signal, _, _, err = eng.evaluatePriceReversal(context.Background(), &levelReaction.ReactionAtFocus, candleMeta, minLevelReversalConfluence, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, signal, true)
+}
+
+func TestBreakConfirmationCloses(t *testing.T) {
+	avgVolume := float64(10)
+	candleMeta := []*shared.CandleMetadata{}
+	marketSkew := shared.NeutralSkew
+	eng, _, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+
+	poke := []shared.PriceMovement{shared.Below, shared.Below, shared.Below, shared.Above}
+	twoCloses := []shared.PriceMovement{shared.Below, shared.Below, shared.Above, shared.Above}
+
+	tests := []struct {
+		name      string
+		closes    int
+		levelKind shared.LevelKind
+		movement  []shared.PriceMovement
+		confirmed bool
+	}{
+		{"default confirms a one candle poke", 0, shared.Resistance, poke, true},
+		{"one close confirms a one candle poke", 1, shared.Resistance, poke, true},
+		{"two closes reject a one candle poke", 2, shared.Resistance, poke, false},
+		{"two closes confirm a two close break", 2, shared.Resistance, twoCloses, true},
+		{"three closes reject a two close break", 3, shared.Resistance, twoCloses, false},
+		{"two closes confirm a two close support break", 2, shared.Support,
+			[]shared.PriceMovement{shared.Above, shared.Above, shared.Below, shared.Below}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eng.cfg.BreakConfirmationCloses = test.closes
+			reaction := &shared.ReactionAtFocus{
+				Market:        "^GSPC",
+				Timeframe:     shared.FiveMinute,
+				LevelKind:     test.levelKind,
+				PriceMovement: test.movement,
+				Reaction:      shared.Break,
+			}
+
+			var sentiment shared.Sentiment
+			confluence := uint32(0)
+			reasons := map[shared.Reason]uint32{}
+			err := eng.evaluateBreakConfirmation(reaction, &confluence, &sentiment, reasons)
+			assert.NoError(t, err)
+
+			// Ensure breaks are only awarded confirmation with enough closes beyond the level.
+			if !test.confirmed {
+				assert.Equal(t, confluence, uint32(0))
+				assert.Equal(t, len(reasons), 0)
+				return
+			}
+
+			assert.Equal(t, confluence, uint32(1))
+			assert.Equal(t, len(reasons), 1)
+		})
+	}
 }
 
 func TestEvaluateLevelBreak(t *testing.T) {
@@ -1215,7 +2690,7 @@ func TestEvaluateLevelBreak(t *testing.T) {
 			Date:      asiaSessionTime,
 		},
 		{
-			Kind:      shared.Pinbar,
+			Kind:      shared.Hammer,
 			Sentiment: shared.Bullish,
 			Momentum:  shared.Medium,
 			Volume:    float64(6),
@@ -1266,17 +2741,135 @@ func TestEvaluateLevelBreak(t *testing.T) {
 	}
 
 	// Ensure price break is not evaluated if the meta is an empty slice.
-	signal, _, _, err := eng.evaluateLevelBreak(&levelReaction.ReactionAtFocus, []*shared.CandleMetadata{}, minLevelBreakConfluence)
+	signal, _, _, err := eng.evaluateLevelBreak(context.Background(), &levelReaction.ReactionAtFocus, []*shared.CandleMetadata{}, minLevelBreakConfluence, nil)
 	assert.Error(t, err)
 
 	// Ensure price reversal is evualuated as expected with valid input.
-	signal, confluence, reasons, err := eng.evaluateLevelBreak(&levelReaction.ReactionAtFocus, candleMeta, minLevelBreakConfluence)
+	signal, confluence, reasons, err := eng.evaluateLevelBreak(context.Background(), &levelReaction.ReactionAtFocus, candleMeta, minLevelBreakConfluence, nil)
 	assert.NoError(t, err)
 	assert.In(t, shared.BreakAboveResistance, reasons)
 	assert.In(t, shared.StrongMove, reasons)
 	assert.In(t, shared.StrongVolume, reasons)
 	assert.Equal(t, confluence, uint32(10))
 	assert.Equal(t, signal, true)
+
+	// Ensure a signal passing the confluence threshold is rejected when backed by fewer
+	// distinct reasons than required.
+	eng.cfg.MinDistinctReasons = len(reasons) + 1
+	signal, confluence, _, err = eng.evaluateLevelBreak(context.Background(), &levelReaction.ReactionAtFocus, candleMeta, minLevelBreakConfluence, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, confluence, uint32(10))
+	assert.Equal(t, signal, false)
+
+	// Ensure a signal backed by exactly the required distinct reasons is not rejected.
+	eng.cfg.MinDistinctReasons = len(reasons)
+	signal, _, _, err = eng.evaluateLevelBreak(context.Background(), &levelReaction.ReactionAtFocus, candleMeta, minLevelBreakConfluence, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, signal, true)
+}
+
+func TestExitThresholds(t *testing.T) {
+	avgVolume := float64(4)
+	asiaSessionTime, _ := generateSessionTimes(t)
+	supportCandleMeta := []*shared.CandleMetadata{
+		{
+			Kind:      shared.Doji,
+			Sentiment: shared.Bearish,
+			Momentum:  shared.Low,
+			Volume:    float64(1),
+			Engulfing: false,
+			High:      5,
+			Low:       4,
+			Date:      asiaSessionTime,
+		},
+		{
+			Kind:      shared.Hammer,
+			Sentiment: shared.Bullish,
+			Momentum:  shared.Medium,
+			Volume:    float64(4),
+			Engulfing: false,
+			High:      6,
+			Low:       4,
+			Date:      asiaSessionTime,
+		},
+		{
+			Kind:      shared.Marubozu,
+			Sentiment: shared.Bullish,
+			Momentum:  shared.Medium,
+			Volume:    float64(5),
+			Engulfing: false,
+			High:      9,
+			Low:       6,
+			Date:      asiaSessionTime,
+		},
+		{
+			Kind:      shared.Marubozu,
+			Sentiment: shared.Bullish,
+			Momentum:  shared.High,
+			Volume:    float64(8),
+			Engulfing: false,
+			High:      14,
+			Low:       9,
+			Date:      asiaSessionTime,
+		},
+	}
+
+	marketSkew := shared.ShortSkewed
+	eng, entrySignals, exitSignals := setupEngine(&avgVolume, supportCandleMeta, &marketSkew)
+	market := "^GSPC"
+	supportLevelReaction := &shared.ReactionAtLevel{
+		ReactionAtFocus: shared.ReactionAtFocus{
+			Market:        market,
+			LevelKind:     shared.Support,
+			CurrentPrice:  float64(14),
+			Timeframe:     shared.FiveMinute,
+			PriceMovement: []shared.PriceMovement{shared.Above, shared.Above, shared.Above, shared.Above},
+			Reaction:      shared.Reversal,
+			CreatedOn:     asiaSessionTime,
+		},
+		Level: &shared.Level{
+			Market: market,
+			Price:  float64(3),
+			Kind:   shared.Support,
+		},
+	}
+
+	// Ensure unset exit thresholds fall back to the entry thresholds.
+	assert.Equal(t, exitThreshold(0, minLevelReversalConfluence), uint32(minLevelReversalConfluence))
+	assert.Equal(t, exitThreshold(2, minLevelReversalConfluence), uint32(2))
+
+	// Raise the entry threshold above the confluence of the reaction while keeping the exit
+	// threshold at the default.
+	thresholds := DefaultThresholds()
+	thresholds.LevelReversal = 100
+	err := eng.SetThresholds(thresholds)
+	assert.NoError(t, err)
+	eng.cfg.ExitThresholds = &ExitThresholds{LevelReversal: minLevelReversalConfluence}
+
+	// Ensure a reaction meeting the exit threshold triggers an exit for a short skewed market.
+	supportLevelReaction.Status = make(chan shared.StatusCode, 1)
+	err = eng.handleReactionAtLevel(context.Background(), supportLevelReaction)
+	assert.NoError(t, err)
+	assert.Equal(t, len(exitSignals), 1)
+	exitSignal := <-exitSignals
+	assert.Equal(t, exitSignal.Direction, shared.Short)
+
+	// Ensure the same reaction does not trigger an entry for a long skewed market since it
+	// does not meet the entry threshold.
+	marketSkew = shared.LongSkewed
+	supportLevelReaction.Status = make(chan shared.StatusCode, 1)
+	err = eng.handleReactionAtLevel(context.Background(), supportLevelReaction)
+	assert.NoError(t, err)
+	assert.Equal(t, len(entrySignals), 0)
+	assert.Equal(t, len(exitSignals), 0)
+
+	// Ensure exits use the entry thresholds when exit thresholds are not provided.
+	eng.cfg.ExitThresholds = nil
+	marketSkew = shared.ShortSkewed
+	supportLevelReaction.Status = make(chan shared.StatusCode, 1)
+	err = eng.handleReactionAtLevel(context.Background(), supportLevelReaction)
+	assert.NoError(t, err)
+	assert.Equal(t, len(exitSignals), 0)
 }
 
 func TestEvaluatePriceReversalStrength(t *testing.T) {
@@ -1294,7 +2887,7 @@ func TestEvaluatePriceReversalStrength(t *testing.T) {
 			Date:      asiaSessionTime,
 		},
 		{
-			Kind:      shared.Pinbar,
+			Kind:      shared.Hammer,
 			Sentiment: shared.Bullish,
 			Momentum:  shared.Medium,
 			Volume:    float64(4),
@@ -1337,7 +2930,7 @@ func TestEvaluatePriceReversalStrength(t *testing.T) {
 			Date:      asiaSessionTime,
 		},
 		{
-			Kind:      shared.Pinbar,
+			Kind:      shared.ShootingStar,
 			Sentiment: shared.Bearish,
 			Momentum:  shared.Medium,
 			Volume:    float64(4),
@@ -1409,14 +3002,25 @@ func TestEvaluatePriceReversalStrength(t *testing.T) {
 	}
 
 	// Ensure a support price reversal triggers a long entry signal for a market long or neutral skewed.
-	err := eng.evaluatePriceReversalStrength(&supportLevelReaction.ReactionAtFocus, candleMeta, minLevelReversalConfluence)
+	err := eng.evaluatePriceReversalStrength(context.Background(), &supportLevelReaction.ReactionAtFocus, nil, candleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
 	assert.NoError(t, err)
 	entrySignal := <-entrySignals
 	assert.Equal(t, entrySignal.Direction, shared.Long)
 
+	// Ensure the entry signal carries a bounded confidence.
+	assert.True(t, entrySignal.WeightedConfidence > 0 && entrySignal.WeightedConfidence <= maxConfidence)
+
+	// Ensure a proven level raises the confidence of the signal.
+	provenLevel := shared.NewLevel(market, supportLevelReaction.Level.Price, supportLevelReaction.CurrentPrice)
+	provenLevel.Reversals.Store(3)
+	err = eng.evaluatePriceReversalStrength(context.Background(), &supportLevelReaction.ReactionAtFocus, provenLevel, candleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
+	assert.NoError(t, err)
+	provenEntrySignal := <-entrySignals
+	assert.True(t, provenEntrySignal.WeightedConfidence > entrySignal.WeightedConfidence)
+
 	// Ensure a support price reversal triggers a short exit signal for a market short skewed.
 	marketSkew = shortSkew
-	err = eng.evaluatePriceReversalStrength(&supportLevelReaction.ReactionAtFocus, candleMeta, minLevelReversalConfluence)
+	err = eng.evaluatePriceReversalStrength(context.Background(), &supportLevelReaction.ReactionAtFocus, nil, candleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
 	assert.NoError(t, err)
 	exitSignal := <-exitSignals
 	assert.Equal(t, exitSignal.Direction, shared.Short)
@@ -1424,7 +3028,7 @@ func TestEvaluatePriceReversalStrength(t *testing.T) {
 	// Ensure a resistance price reversal triggers a long exit signal for a market long skewed.
 	marketSkew = longSkew
 	candleMeta = resistanceCandleMeta
-	err = eng.evaluatePriceReversalStrength(&resistanceLevelReaction.ReactionAtFocus, candleMeta, minLevelReversalConfluence)
+	err = eng.evaluatePriceReversalStrength(context.Background(), &resistanceLevelReaction.ReactionAtFocus, nil, candleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
 	assert.NoError(t, err)
 	exitSignal = <-exitSignals
 	assert.Equal(t, exitSignal.Direction, shared.Long)
@@ -1432,10 +3036,73 @@ func TestEvaluatePriceReversalStrength(t *testing.T) {
 	// Ensure a resistance price reversal triggers a short entry signal for a market short or neutral skewed.
 	marketSkew = shortSkew
 	candleMeta = resistanceCandleMeta
-	err = eng.evaluatePriceReversalStrength(&resistanceLevelReaction.ReactionAtFocus, candleMeta, minLevelReversalConfluence)
+	err = eng.evaluatePriceReversalStrength(context.Background(), &resistanceLevelReaction.ReactionAtFocus, nil, candleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
 	assert.NoError(t, err)
 	entrySignal = <-entrySignals
 	assert.Equal(t, entrySignal.Direction, shared.Short)
+
+	// Ensure a bullish sweep of support triggers a long entry signal with a liquidity sweep reason
+	// for a market long or neutral skewed.
+	marketSkew = longSkew
+	candleMeta = supportCandleMeta
+	supportSweep := supportLevelReaction.ReactionAtFocus
+	supportSweep.Reaction = shared.Sweep
+	supportSweep.PriceMovement = []shared.PriceMovement{shared.Above, shared.Above, shared.Above, shared.Above}
+	err = eng.evaluatePriceReversalStrength(context.Background(), &supportSweep, nil, candleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
+	assert.NoError(t, err)
+	entrySignal = <-entrySignals
+	assert.Equal(t, entrySignal.Direction, shared.Long)
+	assert.In(t, shared.LiquiditySweep, entrySignal.Reasons)
+
+	// Ensure a support price reversal inside a news blackout does not trigger an entry signal.
+	eng.cfg.NewsBlackout = &NewsBlackout{
+		Events: []time.Time{asiaSessionTime.Add(time.Minute * 5)},
+		Window: time.Minute * 10,
+	}
+	err = eng.evaluatePriceReversalStrength(context.Background(), &supportLevelReaction.ReactionAtFocus, nil, candleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
+	assert.NoError(t, err)
+	assert.Equal(t, len(entrySignals), 0)
+
+	// Ensure exits are still signalled inside a news blackout.
+	marketSkew = shortSkew
+	err = eng.evaluatePriceReversalStrength(context.Background(), &supportLevelReaction.ReactionAtFocus, nil, candleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
+	assert.NoError(t, err)
+	exitSignal = <-exitSignals
+	assert.Equal(t, exitSignal.Direction, shared.Short)
+
+	// Ensure a support price reversal outside a news blackout triggers an entry signal.
+	marketSkew = longSkew
+	eng.cfg.NewsBlackout.Events = []time.Time{asiaSessionTime.Add(time.Hour)}
+	err = eng.evaluatePriceReversalStrength(context.Background(), &supportLevelReaction.ReactionAtFocus, nil, candleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
+	assert.NoError(t, err)
+	entrySignal = <-entrySignals
+	assert.Equal(t, entrySignal.Direction, shared.Long)
+	assert.Nil(t, entrySignal.Bracket)
+
+	// Ensure entry signals are bracketed with a target consistent with the stop loss and
+	// direction when a bracket reward ratio is configured.
+	eng.cfg.NewsBlackout = nil
+	eng.cfg.BracketRewardRatio = 2
+	err = eng.evaluatePriceReversalStrength(context.Background(), &supportLevelReaction.ReactionAtFocus, nil, candleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
+	assert.NoError(t, err)
+	entrySignal = <-entrySignals
+	assert.NotNil(t, entrySignal.Bracket)
+	assert.Equal(t, entrySignal.Bracket.Direction, shared.Long)
+	assert.Equal(t, entrySignal.Bracket.Entry, entrySignal.Price)
+	assert.Equal(t, entrySignal.Bracket.StopLoss, entrySignal.StopLoss)
+	assert.Equal(t, entrySignal.Bracket.Target, entrySignal.Price+(entrySignal.Price-entrySignal.StopLoss)*2)
+	assert.True(t, entrySignal.Bracket.Target > entrySignal.Price)
+
+	marketSkew = shortSkew
+	candleMeta = resistanceCandleMeta
+	err = eng.evaluatePriceReversalStrength(context.Background(), &resistanceLevelReaction.ReactionAtFocus, nil, candleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
+	assert.NoError(t, err)
+	entrySignal = <-entrySignals
+	assert.NotNil(t, entrySignal.Bracket)
+	assert.Equal(t, entrySignal.Bracket.Direction, shared.Short)
+	assert.Equal(t, entrySignal.Bracket.StopLoss, entrySignal.StopLoss)
+	assert.Equal(t, entrySignal.Bracket.Target, entrySignal.Price-(entrySignal.StopLoss-entrySignal.Price)*2)
+	assert.True(t, entrySignal.Bracket.Target < entrySignal.Price)
 }
 
 func TestEvaluateLevelBreakStrength(t *testing.T) {
@@ -1453,7 +3120,7 @@ func TestEvaluateLevelBreakStrength(t *testing.T) {
 			Date:      asiaSessionTime,
 		},
 		{
-			Kind:      shared.Pinbar,
+			Kind:      shared.Hammer,
 			Sentiment: shared.Bullish,
 			Momentum:  shared.Medium,
 			Volume:    float64(6),
@@ -1496,7 +3163,7 @@ func TestEvaluateLevelBreakStrength(t *testing.T) {
 			Date:      asiaSessionTime,
 		},
 		{
-			Kind:      shared.Pinbar,
+			Kind:      shared.ShootingStar,
 			Sentiment: shared.Bearish,
 			Momentum:  shared.Medium,
 			Volume:    float64(4),
@@ -1558,29 +3225,371 @@ func TestEvaluateLevelBreakStrength(t *testing.T) {
 	}
 
 	// Ensure a support price break triggers a short entry signal for a market short or neutral skewed.
-	err := eng.evaluateBreakStrength(&supportLevelReaction.ReactionAtFocus, candleMeta, minLevelBreakConfluence)
+	err := eng.evaluateBreakStrength(context.Background(), &supportLevelReaction.ReactionAtFocus, nil, candleMeta, minLevelBreakConfluence, minLevelBreakConfluence)
 	assert.NoError(t, err)
 	entrySignal := <-entrySignals
 	assert.Equal(t, entrySignal.Direction, shared.Short)
 
 	// Ensure a support price break triggers a short exit signal for a market long skewed.
 	marketSkew = longSkew
-	err = eng.evaluateBreakStrength(&supportLevelReaction.ReactionAtFocus, candleMeta, minLevelBreakConfluence)
+	err = eng.evaluateBreakStrength(context.Background(), &supportLevelReaction.ReactionAtFocus, nil, candleMeta, minLevelBreakConfluence, minLevelBreakConfluence)
 	assert.NoError(t, err)
 	exitSignal := <-exitSignals
 	assert.Equal(t, exitSignal.Direction, shared.Long)
 
 	// Ensure a resistance level break triggers a long entry signal for a market long skewed.
 	candleMeta = resistanceBreakCandleMeta
-	err = eng.evaluateBreakStrength(&resistanceLevelReaction.ReactionAtFocus, candleMeta, minLevelBreakConfluence)
+	err = eng.evaluateBreakStrength(context.Background(), &resistanceLevelReaction.ReactionAtFocus, nil, candleMeta, minLevelBreakConfluence, minLevelBreakConfluence)
 	assert.NoError(t, err)
 	entrySignal = <-entrySignals
 	assert.Equal(t, entrySignal.Direction, shared.Long)
 
 	// Ensure a resistance level break triggers a short exit signal for a market short skewed.
 	marketSkew = shortSkew
-	err = eng.evaluateBreakStrength(&resistanceLevelReaction.ReactionAtFocus, candleMeta, minLevelBreakConfluence)
+	err = eng.evaluateBreakStrength(context.Background(), &resistanceLevelReaction.ReactionAtFocus, nil, candleMeta, minLevelBreakConfluence, minLevelBreakConfluence)
 	assert.NoError(t, err)
 	exitSignal = <-exitSignals
 	assert.Equal(t, exitSignal.Direction, shared.Short)
 }
+
+func TestSignalEntry(t *testing.T) {
+	avgVolume := float64(4)
+	asiaSessionTime, _ := generateSessionTimes(t)
+	market := "^GSPC"
+	candleMeta := []*shared.CandleMetadata{
+		{Kind: shared.Doji, Sentiment: shared.Bearish, Momentum: shared.Low, Volume: 1, High: 5, Low: 4, Date: asiaSessionTime},
+		{Kind: shared.Hammer, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 4, High: 6, Low: 4, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 5, High: 9, Low: 6, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.High, Volume: 8, High: 14, Low: 9, Date: asiaSessionTime},
+	}
+	reaction := &shared.ReactionAtFocus{
+		Market:        market,
+		LevelKind:     shared.Support,
+		CurrentPrice:  float64(14),
+		Timeframe:     shared.FiveMinute,
+		PriceMovement: []shared.PriceMovement{shared.Above, shared.Above, shared.Above, shared.Above},
+		Reaction:      shared.Reversal,
+		CreatedOn:     asiaSessionTime,
+	}
+
+	tests := []struct {
+		name       string
+		confluence uint32
+		// configure applies the entry gate under test to the engine.
+		configure func(eng *Engine)
+		wantEntry bool
+		wantErr   bool
+	}{
+		{"entry signalled", minLevelReversalConfluence, func(*Engine) {}, true, false},
+		{"below entry threshold", minLevelReversalConfluence - 1, func(*Engine) {}, false, false},
+		{"reaction disabled", minLevelReversalConfluence, func(eng *Engine) {
+			eng.cfg.EnabledReactions = map[ReactionKind]struct{}{{Reaction: shared.Break}: {}}
+		}, false, false},
+		{"direction disallowed", minLevelReversalConfluence, func(eng *Engine) {
+			eng.cfg.DirectionModes = map[string]DirectionMode{market: ShortOnly}
+		}, false, false},
+		{"stop out of range", minLevelReversalConfluence, func(eng *Engine) {
+			eng.cfg.StopRange = &StopRange{MaxPointsRange: 1}
+		}, false, false},
+		{"entry status timeout", minLevelReversalConfluence, func(eng *Engine) {
+			eng.cfg.Timeouts = &shared.Timeouts{SignalStatus: time.Millisecond * 20}
+			eng.cfg.SendEntrySignal = func(shared.EntrySignal) {}
+		}, false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			marketSkew := shared.LongSkewed
+			eng, entrySignals, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+			test.configure(eng)
+
+			// Ensure every entry gate is applied before an entry is signalled.
+			record := newAuditRecord(reaction, candleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
+			err := eng.signalEntry(context.Background(), reaction, nil, candleMeta, marketSkew, shared.Long,
+				nil, test.confluence, 0, minLevelReversalConfluence, record)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			if !test.wantEntry {
+				assert.Equal(t, len(entrySignals), 0)
+				return
+			}
+
+			assert.Equal(t, len(entrySignals), 1)
+			signal := <-entrySignals
+			assert.Equal(t, signal.Direction, shared.Long)
+			assert.True(t, signal.StopLoss < reaction.CurrentPrice)
+		})
+	}
+}
+
+func TestParseNeutralSkewMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		want    NeutralSkewMode
+		wantErr bool
+	}{
+		{"empty defaults to both", "", BothDirections, false},
+		{"both", "both", BothDirections, false},
+		{"trend", "trend", TrendDirection, false},
+		{"net", "net", NetOpposing, false},
+		{"unknown", "hedge", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mode, err := ParseNeutralSkewMode(test.mode)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, mode, test.want)
+			if test.mode != "" {
+				assert.Equal(t, mode.String(), test.mode)
+			}
+		})
+	}
+}
+
+func TestParseCapacityPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		want    CapacityPolicy
+		wantErr bool
+	}{
+		{"empty defaults to drop", "", DropOnCapacity, false},
+		{"drop", "drop", DropOnCapacity, false},
+		{"block", "block", BlockOnCapacity, false},
+		{"unknown", "spill", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policy, err := ParseCapacityPolicy(test.policy)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, policy, test.want)
+			if test.policy != "" {
+				assert.Equal(t, policy.String(), test.policy)
+			}
+		})
+	}
+}
+
+func TestNeutralSkewModes(t *testing.T) {
+	avgVolume := float64(4)
+	asiaSessionTime, _ := generateSessionTimes(t)
+	market := "^GSPC"
+	supportCandleMeta := []*shared.CandleMetadata{
+		{Kind: shared.Doji, Sentiment: shared.Bearish, Momentum: shared.Low, Volume: 1, High: 5, Low: 4, Date: asiaSessionTime},
+		{Kind: shared.Hammer, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 4, High: 6, Low: 4, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 5, High: 9, Low: 6, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.High, Volume: 8, High: 14, Low: 9, Date: asiaSessionTime},
+	}
+	resistanceCandleMeta := []*shared.CandleMetadata{
+		{Kind: shared.Doji, Sentiment: shared.Bullish, Momentum: shared.Low, Volume: 1, High: 11, Low: 9, Date: asiaSessionTime},
+		{Kind: shared.ShootingStar, Sentiment: shared.Bearish, Momentum: shared.Medium, Volume: 4, High: 9, Low: 7, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bearish, Momentum: shared.Medium, Volume: 5, High: 7, Low: 5, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bearish, Momentum: shared.High, Volume: 8, High: 6, Low: 1, Date: asiaSessionTime},
+	}
+	supportReversal := &shared.ReactionAtFocus{
+		Market:        market,
+		LevelKind:     shared.Support,
+		CurrentPrice:  float64(14),
+		Timeframe:     shared.FiveMinute,
+		PriceMovement: []shared.PriceMovement{shared.Above, shared.Above, shared.Above, shared.Above},
+		Reaction:      shared.Reversal,
+		CreatedOn:     asiaSessionTime,
+	}
+	resistanceReversal := &shared.ReactionAtFocus{
+		Market:        market,
+		LevelKind:     shared.Resistance,
+		CurrentPrice:  float64(1),
+		Timeframe:     shared.FiveMinute,
+		PriceMovement: []shared.PriceMovement{shared.Below, shared.Below, shared.Below, shared.Below},
+		Reaction:      shared.Reversal,
+		CreatedOn:     asiaSessionTime,
+	}
+
+	marketSkew := shared.NeutralSkew
+	trend := shared.StrongBullishTrend
+	requestTrend := func(req shared.TrendRequest) {
+		req.Response <- trend
+	}
+
+	// ranging alternates support and resistance reversals for a neutral skewed market.
+	ranging := func(eng *Engine) {
+		for range 2 {
+			err := eng.evaluatePriceReversalStrength(context.Background(), supportReversal, nil, supportCandleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
+			assert.NoError(t, err)
+			err = eng.evaluatePriceReversalStrength(context.Background(), resistanceReversal, nil, resistanceCandleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
+			assert.NoError(t, err)
+		}
+	}
+
+	// trackLegs opens and closes the legs of signalled entries and exits like the position
+	// manager does.
+	var legs []shared.Direction
+	trackLegs := func(eng *Engine) {
+		legs = nil
+		sendEntry := eng.cfg.SendEntrySignal
+		eng.cfg.SendEntrySignal = func(signal shared.EntrySignal) {
+			legs = append(legs, signal.Direction)
+			sendEntry(signal)
+		}
+		sendExit := eng.cfg.SendExitSignal
+		eng.cfg.SendExitSignal = func(signal shared.ExitSignal) {
+			for idx := range legs {
+				if legs[idx] == signal.Direction {
+					legs = append(legs[:idx], legs[idx+1:]...)
+					break
+				}
+			}
+			sendExit(signal)
+		}
+		eng.cfg.LatestOpenLeg = func(string) (shared.Direction, bool, error) {
+			if len(legs) == 0 {
+				return 0, false, nil
+			}
+			return legs[len(legs)-1], true, nil
+		}
+	}
+
+	// Ensure both directions are taken for a ranging neutral market by default.
+	eng, entrySignals, exitSignals := setupEngine(&avgVolume, supportCandleMeta, &marketSkew)
+	ranging(eng)
+	assert.Equal(t, len(entrySignals), 4)
+	assert.Equal(t, len(exitSignals), 0)
+
+	// Ensure opposing entries are netted for a ranging neutral market in net mode.
+	eng, entrySignals, exitSignals = setupEngine(&avgVolume, supportCandleMeta, &marketSkew)
+	eng.cfg.NeutralSkewMode = NetOpposing
+	trackLegs(eng)
+	ranging(eng)
+	assert.Equal(t, len(entrySignals), 2)
+	assert.Equal(t, len(exitSignals), 2)
+	for range 2 {
+		entrySignal := <-entrySignals
+		assert.Equal(t, entrySignal.Direction, shared.Long)
+		exitSignal := <-exitSignals
+		assert.Equal(t, exitSignal.Direction, shared.Long)
+	}
+
+	assert.Equal(t, len(legs), 0)
+
+	// Ensure an entry rejected by its stop bounds opens no leg to net against in net mode.
+	eng.cfg.StopRange = &StopRange{MaxPointsRange: 1}
+	err := eng.evaluatePriceReversalStrength(context.Background(), supportReversal, nil, supportCandleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
+	assert.NoError(t, err)
+	assert.Equal(t, len(entrySignals), 0)
+	assert.Equal(t, len(legs), 0)
+	eng.cfg.StopRange = nil
+	err = eng.evaluatePriceReversalStrength(context.Background(), resistanceReversal, nil, resistanceCandleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
+	assert.NoError(t, err)
+	entrySignal := <-entrySignals
+	assert.Equal(t, entrySignal.Direction, shared.Short)
+	assert.Equal(t, len(exitSignals), 0)
+
+	// Ensure a leg stopped out by the position manager is not netted against in net mode.
+	legs = nil
+	err = eng.evaluatePriceReversalStrength(context.Background(), supportReversal, nil, supportCandleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
+	assert.NoError(t, err)
+	entrySignal = <-entrySignals
+	assert.Equal(t, entrySignal.Direction, shared.Long)
+	assert.Equal(t, len(exitSignals), 0)
+
+	// Ensure net mode requires the latest open leg of markets.
+	eng.cfg.LatestOpenLeg = nil
+	err = eng.evaluatePriceReversalStrength(context.Background(), resistanceReversal, nil, resistanceCandleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
+	assert.Error(t, err)
+
+	// Ensure only entries aligned with the trend are taken for a ranging neutral market in trend mode.
+	eng, entrySignals, exitSignals = setupEngine(&avgVolume, supportCandleMeta, &marketSkew)
+	eng.cfg.NeutralSkewMode = TrendDirection
+	eng.cfg.RequestTrend = requestTrend
+	ranging(eng)
+	assert.Equal(t, len(entrySignals), 2)
+	assert.Equal(t, len(exitSignals), 0)
+	for range 2 {
+		entrySignal := <-entrySignals
+		assert.Equal(t, entrySignal.Direction, shared.Long)
+	}
+
+	// Ensure no entries are taken for a choppy trend in trend mode.
+	trend = shared.ChoppyTrend
+	ranging(eng)
+	assert.Equal(t, len(entrySignals), 0)
+}
+
+func TestEngineRequestTimeouts(t *testing.T) {
+	market := "^GSPC"
+
+	tests := []struct {
+		name     string
+		timeouts shared.Timeouts
+		// wantVolumeErr is the flag for expecting the average volume fetch to time out.
+		wantVolumeErr bool
+		// wantMetaErr is the flag for expecting the candle metadata fetch to time out.
+		wantMetaErr bool
+	}{
+		{"short metadata timeout", shared.Timeouts{PriceData: time.Minute, CandleMetadata: time.Millisecond * 20},
+			false, true},
+		{"short price data timeout", shared.Timeouts{PriceData: time.Millisecond * 20, CandleMetadata: time.Minute},
+			true, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			avgVolume := float64(10)
+			marketSkew := shared.NeutralSkew
+			eng, _, _ := setupEngine(&avgVolume, []*shared.CandleMetadata{}, &marketSkew)
+			eng.cfg.Timeouts = &test.timeouts
+
+			// Responses are delayed past the shorter timeout.
+			delay := time.Millisecond * 60
+			eng.cfg.RequestAverageVolume = func(req shared.AverageVolumeRequest) {
+				go func() {
+					time.Sleep(delay)
+					req.Response <- avgVolume
+				}()
+			}
+			eng.cfg.RequestCandleMetadata = func(req shared.CandleMetadataRequest) {
+				go func() {
+					time.Sleep(delay)
+					req.Response <- []*shared.CandleMetadata{}
+				}()
+			}
+
+			// Ensure each fetch is bounded by its own timeout, the short timeout fires while
+			// the longer timeout does not.
+			start := time.Now()
+			_, _, err := eng.fetchAverageVolume(context.Background(), market, shared.FiveMinute)
+			if test.wantVolumeErr {
+				assert.Error(t, err)
+				assert.Equal(t, err.Error(), "timed out fetching average volume for "+market)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			_, err = eng.fetchCandleMetadata(context.Background(), market, shared.FiveMinute, 0)
+			if test.wantMetaErr {
+				assert.Error(t, err)
+				assert.Equal(t, err.Error(), "timed out fetching candle metadata for "+market)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.True(t, time.Since(start) < time.Second*2)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/dnldd/entry/shared"
+)
+
+// ExitPolicy represents how open positions are exited on reactions opposing them.
+type ExitPolicy int
+
+const (
+	// OnOpposingReaction exits positions on opposing reactions meeting the exit threshold.
+	OnOpposingReaction ExitPolicy = iota
+	// StopAndTargetOnly ignores opposing reactions, positions are only exited by their stop
+	// loss or target.
+	StopAndTargetOnly
+	// Hybrid only exits positions on opposing reactions strong enough to meet the entry
+	// threshold, weaker opposing reactions are held through to the stop loss or target.
+	Hybrid
+)
+
+// String stringifies the provided exit policy.
+func (p ExitPolicy) String() string {
+	switch p {
+	case OnOpposingReaction:
+		return "opposing"
+	case StopAndTargetOnly:
+		return "stoptarget"
+	case Hybrid:
+		return "hybrid"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseExitPolicy parses the exit policy from the provided string. An empty string defaults to
+// OnOpposingReaction.
+func ParseExitPolicy(policy string) (ExitPolicy, error) {
+	switch policy {
+	case "", "opposing":
+		return OnOpposingReaction, nil
+	case "stoptarget":
+		return StopAndTargetOnly, nil
+	case "hybrid":
+		return Hybrid, nil
+	default:
+		return 0, fmt.Errorf("unknown exit policy provided: %s", policy)
+	}
+}
+
+// exitsOnReaction checks whether the provided opposing reaction exits open positions under the
+// exit policy of the engine.
+func (e *Engine) exitsOnReaction(reaction *shared.ReactionAtFocus, confluence uint32, entryThreshold uint32, exitThreshold uint32) bool {
+	switch e.cfg.ExitPolicy {
+	case StopAndTargetOnly:
+		e.reactionLogger(reaction.CorrelationID).Info().Msgf("holding %s positions through %s %s to stop and target",
+			reaction.Market, reaction.LevelKind.String(), reaction.Reaction.String())
+		return false
+	case Hybrid:
+		return e.meetsThreshold(reaction, "exit", confluence, max(entryThreshold, exitThreshold))
+	default:
+		return e.meetsThreshold(reaction, "exit", confluence, exitThreshold)
+	}
+}
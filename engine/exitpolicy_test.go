@@ -0,0 +1,95 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestParseExitPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		want    ExitPolicy
+		wantErr bool
+	}{
+		{"empty defaults to opposing", "", OnOpposingReaction, false},
+		{"opposing", "opposing", OnOpposingReaction, false},
+		{"stop and target only", "stoptarget", StopAndTargetOnly, false},
+		{"hybrid", "hybrid", Hybrid, false},
+		{"unknown", "never", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policy, err := ParseExitPolicy(test.policy)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, policy, test.want)
+		})
+	}
+}
+
+func TestExitPolicy(t *testing.T) {
+	avgVolume := float64(4)
+	asiaSessionTime, _ := generateSessionTimes(t)
+	candleMeta := []*shared.CandleMetadata{
+		{Kind: shared.Marubozu, Sentiment: shared.Bearish, Momentum: shared.Medium, Volume: 5, High: 14, Low: 11, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bearish, Momentum: shared.High, Volume: 8, High: 11, Low: 6, Date: asiaSessionTime},
+	}
+	marketSkew := shared.LongSkewed
+
+	reaction := shared.ReactionAtFocus{
+		Market:        "^GSPC",
+		Timeframe:     shared.FiveMinute,
+		LevelKind:     shared.Resistance,
+		CurrentPrice:  6,
+		PriceMovement: []shared.PriceMovement{shared.Below, shared.Below, shared.Below, shared.Below},
+		Reaction:      shared.Reversal,
+		CreatedOn:     asiaSessionTime,
+	}
+
+	// Score the opposing reversal to derive thresholds around its confluence.
+	eng, _, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+	signal, confluence, _, err := eng.evaluatePriceReversal(context.Background(), &reaction, candleMeta, minLevelReversalConfluence, nil)
+	assert.NoError(t, err)
+	assert.True(t, signal)
+
+	tests := []struct {
+		name           string
+		policy         ExitPolicy
+		entryThreshold uint32
+		wantExit       bool
+	}{
+		{"opposing reaction exits", OnOpposingReaction, confluence + 1, true},
+		{"stop and target only holds", StopAndTargetOnly, confluence, false},
+		{"hybrid holds through a weak opposing reaction", Hybrid, confluence + 1, false},
+		{"hybrid exits on a strong opposing reaction", Hybrid, confluence, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eng, _, exitSignals := setupEngine(&avgVolume, candleMeta, &marketSkew)
+			eng.cfg.ExitPolicy = test.policy
+
+			err := eng.evaluatePriceReversalStrength(context.Background(), &reaction, nil, candleMeta, test.entryThreshold, confluence)
+			assert.NoError(t, err)
+
+			// Ensure opposing reactions only exit long positions as dictated by the exit policy.
+			if !test.wantExit {
+				assert.Equal(t, len(exitSignals), 0)
+				return
+			}
+
+			assert.Equal(t, len(exitSignals), 1)
+			exit := <-exitSignals
+			assert.Equal(t, exit.Direction, shared.Long)
+		})
+	}
+}
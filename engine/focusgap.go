@@ -0,0 +1,87 @@
+package engine
+
+import (
+	"slices"
+
+	"github.com/dnldd/entry/shared"
+)
+
+// vwapFocusWeight is the strength of the vwap as a focus, weighed against the level weight of
+// levels within the level vwap gap of it.
+const vwapFocusWeight = uint32(1)
+
+// focusStrength returns the strength of the provided focus, levels are as strong as their
+// level weight.
+func (e *Engine) focusStrength(focus shared.FocusKind, source shared.LevelSource) uint32 {
+	if focus == shared.VWAPFocus {
+		return vwapFocusWeight
+	}
+
+	return e.levelWeight(source)
+}
+
+// overlappingFocus returns the nearby vwap of the provided reaction at a level, or the nearby
+// level of the provided reaction at vwap, within the level vwap gap of the reacted to focus.
+func (e *Engine) overlappingFocus(reaction *shared.ReactionAtFocus) (*shared.NearbyFocus, bool) {
+	if e.cfg.LevelVWAPGap <= 0 {
+		return nil, false
+	}
+
+	var other shared.FocusKind
+	switch reaction.Focus {
+	case shared.LevelFocus:
+		other = shared.VWAPFocus
+	case shared.VWAPFocus:
+		other = shared.LevelFocus
+	default:
+		return nil, false
+	}
+
+	for idx := range reaction.NearbyFoci {
+		nearby := &reaction.NearbyFoci[idx]
+		if nearby.Focus == other && nearby.Distance <= e.cfg.LevelVWAPGap {
+			return nearby, true
+		}
+	}
+
+	return nil, false
+}
+
+// collapsedOverlap checks whether the focus overlapping the provided reaction's focus was
+// collapsed into the reaction as a coincident focus.
+func (e *Engine) collapsedOverlap(reaction *shared.ReactionAtFocus) bool {
+	nearby, ok := e.overlappingFocus(reaction)
+	if !ok {
+		return false
+	}
+
+	return slices.Contains(reaction.CoincidentFocuses, nearby.Focus)
+}
+
+// defersToStrongerFocus checks whether the provided reaction is at the weaker of a level and
+// vwap within the level vwap gap of each other. Only the reaction at the stronger focus
+// contributes, levels are favoured over vwap when equally strong.
+func (e *Engine) defersToStrongerFocus(reaction *shared.ReactionAtFocus) bool {
+	nearby, ok := e.overlappingFocus(reaction)
+	if !ok || slices.Contains(reaction.CoincidentFocuses, nearby.Focus) {
+		return false
+	}
+
+	strength := e.focusStrength(reaction.Focus, reaction.LevelSource)
+	nearbyStrength := e.focusStrength(nearby.Focus, nearby.LevelSource)
+
+	var defers bool
+	switch {
+	case nearbyStrength != strength:
+		defers = nearbyStrength > strength
+	default:
+		defers = reaction.Focus == shared.VWAPFocus
+	}
+
+	if defers {
+		e.reactionLogger(reaction.CorrelationID).Info().Msgf("deferring %s %s reaction to %s within %.2f of it",
+			reaction.Market, reaction.Focus.String(), nearby.Focus.String(), nearby.Distance)
+	}
+
+	return defers
+}
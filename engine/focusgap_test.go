@@ -0,0 +1,177 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestLevelVWAPGap(t *testing.T) {
+	avgVolume := float64(4)
+	asiaSessionTime, _ := generateSessionTimes(t)
+	marketSkew := shared.NeutralSkew
+	eng, _, _ := setupEngine(&avgVolume, nil, &marketSkew)
+
+	meta := []*shared.CandleMetadata{
+		{
+			Kind:      shared.Marubozu,
+			Sentiment: shared.Bullish,
+			Momentum:  shared.High,
+			Volume:    float64(8),
+		},
+	}
+
+	// reaction creates a support reversal at the provided focus with the provided nearby foci.
+	reaction := func(focus shared.FocusKind, source shared.LevelSource, nearby ...shared.NearbyFocus) *shared.ReactionAtFocus {
+		return &shared.ReactionAtFocus{
+			Market:      "^GSPC",
+			Timeframe:   shared.FiveMinute,
+			Focus:       focus,
+			LevelSource: source,
+			LevelKind:   shared.Support,
+			Reaction:    shared.Reversal,
+			NearbyFoci:  nearby,
+			CreatedOn:   asiaSessionTime,
+		}
+	}
+
+	nearVWAP := shared.NearbyFocus{Focus: shared.VWAPFocus, Distance: 0.25}
+	nearDailyLevel := shared.NearbyFocus{Focus: shared.LevelFocus, LevelSource: shared.DailyLevel, Distance: 0.25}
+	nearAsiaLevel := shared.NearbyFocus{Focus: shared.LevelFocus, LevelSource: shared.AsiaLevel, Distance: 0.25}
+
+	tests := []struct {
+		name     string
+		gap      float64
+		reaction *shared.ReactionAtFocus
+		signal   bool
+	}{
+		{
+			name:     "vwap reaction without a gap configured",
+			gap:      0,
+			reaction: reaction(shared.VWAPFocus, shared.UnsourcedLevel, nearDailyLevel),
+			signal:   true,
+		},
+		{
+			name:     "vwap reaction at a stronger level",
+			gap:      0.5,
+			reaction: reaction(shared.VWAPFocus, shared.UnsourcedLevel, nearDailyLevel),
+			signal:   false,
+		},
+		{
+			name:     "level reaction at a weaker vwap",
+			gap:      0.5,
+			reaction: reaction(shared.LevelFocus, shared.DailyLevel, nearVWAP),
+			signal:   true,
+		},
+		{
+			name:     "vwap reaction at a weaker level",
+			gap:      0.5,
+			reaction: reaction(shared.VWAPFocus, shared.UnsourcedLevel, nearAsiaLevel),
+			signal:   true,
+		},
+		{
+			name:     "level reaction at a stronger vwap",
+			gap:      0.5,
+			reaction: reaction(shared.LevelFocus, shared.AsiaLevel, nearVWAP),
+			signal:   false,
+		},
+		{
+			name:     "equally strong level and vwap favour the level",
+			gap:      0.5,
+			reaction: reaction(shared.LevelFocus, shared.NewYorkLevel, nearVWAP),
+			signal:   true,
+		},
+		{
+			name:     "vwap reaction at an equally strong level",
+			gap:      0.5,
+			reaction: reaction(shared.VWAPFocus, shared.UnsourcedLevel, shared.NearbyFocus{Focus: shared.LevelFocus, LevelSource: shared.LondonLevel, Distance: 0.25}),
+			signal:   false,
+		},
+		{
+			name:     "vwap reaction at a level beyond the gap",
+			gap:      0.1,
+			reaction: reaction(shared.VWAPFocus, shared.UnsourcedLevel, nearDailyLevel),
+			signal:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eng.cfg.LevelVWAPGap = test.gap
+
+			// Ensure only the reaction at the stronger of an overlapping level and vwap signals.
+			signal, _, _, err := eng.scorePriceReversal(test.reaction, meta, avgVolume, 1, nil, 1, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, signal, test.signal)
+		})
+	}
+	eng.cfg.LevelVWAPGap = 0
+}
+
+func TestLevelVWAPGapCoincidentFocuses(t *testing.T) {
+	avgVolume := float64(10)
+	marketSkew := shared.NeutralSkew
+	eng, _, _ := setupEngine(&avgVolume, nil, &marketSkew)
+
+	tests := []struct {
+		name       string
+		gap        float64
+		distance   float64
+		coincident []shared.FocusKind
+		confluence uint32
+	}{
+		{
+			name:       "collapsed vwap without a gap configured",
+			gap:        0,
+			distance:   0.25,
+			coincident: []shared.FocusKind{shared.VWAPFocus},
+			confluence: 1,
+		},
+		{
+			name:       "collapsed vwap within the gap",
+			gap:        0.5,
+			distance:   0.25,
+			coincident: []shared.FocusKind{shared.VWAPFocus},
+			confluence: 0,
+		},
+		{
+			name:       "collapsed vwap beyond the gap",
+			gap:        0.5,
+			distance:   0.75,
+			coincident: []shared.FocusKind{shared.VWAPFocus},
+			confluence: 1,
+		},
+		{
+			name:       "collapsed vwap and imbalance within the gap",
+			gap:        0.5,
+			distance:   0.25,
+			coincident: []shared.FocusKind{shared.VWAPFocus, shared.ImbalanceFocus},
+			confluence: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eng.cfg.LevelVWAPGap = test.gap
+			reaction := &shared.ReactionAtFocus{
+				Market:            "^GSPC",
+				Focus:             shared.LevelFocus,
+				LevelSource:       shared.DailyLevel,
+				CoincidentFocuses: test.coincident,
+				NearbyFoci:        []shared.NearbyFocus{{Focus: shared.VWAPFocus, Distance: test.distance}},
+			}
+
+			// Ensure a collapsed vwap overlapping the level is not counted as a coincident focus.
+			confluence := uint32(0)
+			reasons := map[shared.Reason]uint32{}
+			eng.evaluateCoincidentFocuses(reaction, &confluence, reasons)
+			assert.Equal(t, confluence, test.confluence)
+			assert.Equal(t, reasons[shared.CoincidentFocus], test.confluence)
+
+			// Ensure a collapsed reaction is not deferred, it carries both foci.
+			assert.False(t, eng.defersToStrongerFocus(reaction))
+		})
+	}
+	eng.cfg.LevelVWAPGap = 0
+}
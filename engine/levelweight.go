@@ -0,0 +1,67 @@
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dnldd/entry/shared"
+)
+
+// DefaultLevelWeights returns the default confluence awarded to reversals at levels by the
+// session the level was derived from. Daily levels are the most significant, asia session
+// levels and levels not derived from a session are not awarded confluence.
+func DefaultLevelWeights() map[shared.LevelSource]uint32 {
+	return map[shared.LevelSource]uint32{
+		shared.LondonLevel:  1,
+		shared.NewYorkLevel: 1,
+		shared.DailyLevel:   2,
+	}
+}
+
+// ParseLevelWeights parses level weights from the provided entries of the form
+// source=weight.
+func ParseLevelWeights(entries []string) (map[shared.LevelSource]uint32, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	weights := make(map[shared.LevelSource]uint32, len(entries))
+	for idx := range entries {
+		sourceStr, weightStr, ok := strings.Cut(entries[idx], "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid level weight entry provided: %s", entries[idx])
+		}
+
+		source, err := shared.ParseLevelSource(sourceStr)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s level weight source: %v", entries[idx], err)
+		}
+
+		weight, err := strconv.ParseUint(weightStr, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s level weight: %v", entries[idx], err)
+		}
+
+		_, ok = weights[source]
+		if ok {
+			return nil, fmt.Errorf("multiple level weights provided for %s", source.String())
+		}
+
+		weights[source] = uint32(weight)
+	}
+
+	return weights, nil
+}
+
+// evaluateLevelSignificance awards confluence points weighted by the significance tier of
+// the session the reacted to level was derived from.
+func (e *Engine) evaluateLevelSignificance(reaction *shared.ReactionAtFocus, confluence *uint32, reasons map[shared.Reason]uint32) {
+	weight := e.levelWeight(reaction.LevelSource)
+	if weight == 0 {
+		return
+	}
+
+	*confluence += weight
+	reasons[shared.SignificantLevel] += weight
+}
@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestParseLevelWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		want    map[shared.LevelSource]uint32
+		wantErr bool
+	}{
+		{"empty uses defaults", nil, nil, false},
+		{"valid weights", []string{"daily=3", "asia=1"},
+			map[shared.LevelSource]uint32{shared.DailyLevel: 3, shared.AsiaLevel: 1}, false},
+		{"missing weight", []string{"daily"}, nil, true},
+		{"unknown source", []string{"weekly=3"}, nil, true},
+		{"invalid weight", []string{"daily=-1"}, nil, true},
+		{"duplicate source", []string{"daily=3", "daily=2"}, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			weights, err := ParseLevelWeights(test.entries)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, weights, test.want)
+		})
+	}
+}
+
+func TestLevelWeightedReversal(t *testing.T) {
+	avgVolume := float64(4)
+	asiaSessionTime, _ := generateSessionTimes(t)
+	candleMeta := []*shared.CandleMetadata{
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 5, High: 9, Low: 6, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.High, Volume: 8, High: 14, Low: 9, Date: asiaSessionTime},
+	}
+	marketSkew := shared.NeutralSkew
+	eng, _, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+
+	reaction := shared.ReactionAtFocus{
+		Market:        "^GSPC",
+		Timeframe:     shared.FiveMinute,
+		LevelKind:     shared.Support,
+		PriceMovement: []shared.PriceMovement{shared.Above, shared.Above, shared.Above, shared.Above},
+		Reaction:      shared.Reversal,
+		CreatedOn:     asiaSessionTime,
+	}
+
+	asiaReaction := reaction
+	asiaReaction.LevelSource = shared.AsiaLevel
+	_, asiaConfluence, asiaReasons, err := eng.evaluatePriceReversal(context.Background(), &asiaReaction, candleMeta, minLevelReversalConfluence, nil)
+	assert.NoError(t, err)
+	assert.NotIn(t, shared.SignificantLevel, asiaReasons)
+
+	dailyReaction := reaction
+	dailyReaction.LevelSource = shared.DailyLevel
+	_, dailyConfluence, dailyReasons, err := eng.evaluatePriceReversal(context.Background(), &dailyReaction, candleMeta, minLevelReversalConfluence, nil)
+	assert.NoError(t, err)
+	assert.In(t, shared.SignificantLevel, dailyReasons)
+
+	// Ensure a reversal at a daily level scores higher confluence than one at an intrasession
+	// level given identical candles.
+	assert.Equal(t, dailyConfluence, asiaConfluence+DefaultLevelWeights()[shared.DailyLevel])
+
+	// Ensure configured level weights take precedence over the defaults.
+	err = eng.SetLevelWeights(map[shared.LevelSource]uint32{shared.AsiaLevel: 4})
+	assert.NoError(t, err)
+	_, confluence, _, err := eng.evaluatePriceReversal(context.Background(), &asiaReaction, candleMeta, minLevelReversalConfluence, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, confluence, asiaConfluence+4)
+}
@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// defaultNewsBlackoutWindow is the default duration entries are suppressed for before
+	// and after a scheduled news release.
+	defaultNewsBlackoutWindow = time.Minute * 15
+)
+
+// NewsBlackout represents the scheduled high-impact news releases entries are suppressed around.
+type NewsBlackout struct {
+	// Events represents the scheduled release times of high-impact news.
+	Events []time.Time
+	// Window is the duration entries are suppressed for before and after a release. The
+	// default window is used if zero.
+	Window time.Duration
+}
+
+// Validate asserts the news blackout is sane.
+func (b *NewsBlackout) Validate() error {
+	var errs error
+
+	if len(b.Events) == 0 {
+		errs = errors.Join(errs, fmt.Errorf("no news events provided for blackout"))
+	}
+	if b.Window < 0 {
+		errs = errors.Join(errs, fmt.Errorf("news blackout window cannot be negative"))
+	}
+
+	return errs
+}
+
+// Covers returns the news release the provided time falls within the blackout window of.
+func (b *NewsBlackout) Covers(date time.Time) (time.Time, bool) {
+	window := b.Window
+	if window == 0 {
+		window = defaultNewsBlackoutWindow
+	}
+
+	for idx := range b.Events {
+		release := b.Events[idx]
+		if !date.Before(release.Add(-window)) && !date.After(release.Add(window)) {
+			return release, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// ParseNewsEvents parses the provided RFC3339 news release timestamps.
+func ParseNewsEvents(events []string) ([]time.Time, error) {
+	times := make([]time.Time, 0, len(events))
+	for idx := range events {
+		release, err := time.Parse(time.RFC3339, events[idx])
+		if err != nil {
+			return nil, fmt.Errorf("parsing news event %s: %v", events[idx], err)
+		}
+
+		times = append(times, release)
+	}
+
+	return times, nil
+}
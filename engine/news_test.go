@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestNewsBlackoutValidate(t *testing.T) {
+	release := time.Date(2025, 3, 7, 8, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		blackout *NewsBlackout
+		wantErr  bool
+	}{
+		{"default window", &NewsBlackout{Events: []time.Time{release}}, false},
+		{"custom window", &NewsBlackout{Events: []time.Time{release}, Window: time.Minute * 5}, false},
+		{"no events", &NewsBlackout{Window: time.Minute * 5}, true},
+		{"negative window", &NewsBlackout{Events: []time.Time{release}, Window: -time.Minute}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.blackout.Validate()
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNewsBlackoutCovers(t *testing.T) {
+	release := time.Date(2025, 3, 7, 8, 30, 0, 0, time.UTC)
+	blackout := &NewsBlackout{Events: []time.Time{release}, Window: time.Minute * 10}
+
+	tests := []struct {
+		name   string
+		date   time.Time
+		covers bool
+	}{
+		{"at release", release, true},
+		{"before release within window", release.Add(-time.Minute * 5), true},
+		{"after release within window", release.Add(time.Minute * 10), true},
+		{"before window", release.Add(-time.Minute * 11), false},
+		{"after window", release.Add(time.Minute * 11), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			covered, ok := blackout.Covers(test.date)
+			assert.Equal(t, ok, test.covers)
+			if ok {
+				assert.Equal(t, covered, release)
+			}
+		})
+	}
+
+	// Ensure the default window is used if the window is zero.
+	blackout.Window = 0
+	_, ok := blackout.Covers(release.Add(defaultNewsBlackoutWindow))
+	assert.True(t, ok)
+	_, ok = blackout.Covers(release.Add(defaultNewsBlackoutWindow + time.Minute))
+	assert.False(t, ok)
+}
+
+func TestParseNewsEvents(t *testing.T) {
+	// Ensure valid RFC3339 timestamps are parsed.
+	events, err := ParseNewsEvents([]string{"2025-03-07T08:30:00-05:00", "2025-03-19T14:00:00-04:00"})
+	assert.NoError(t, err)
+	assert.Equal(t, len(events), 2)
+	assert.True(t, events[0].Equal(time.Date(2025, 3, 7, 13, 30, 0, 0, time.UTC)))
+
+	// Ensure invalid timestamps error.
+	_, err = ParseNewsEvents([]string{"2025-03-07 08:30"})
+	assert.Error(t, err)
+}
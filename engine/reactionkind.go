@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dnldd/entry/shared"
+)
+
+// ReactionKind represents a reaction pathway of the engine, a price reaction at a type of
+// focus.
+type ReactionKind struct {
+	Focus    shared.FocusKind
+	Reaction shared.PriceReaction
+}
+
+// String stringifies the provided reaction kind.
+func (k ReactionKind) String() string {
+	return fmt.Sprintf("%s %s", k.Focus.String(), k.Reaction.String())
+}
+
+var (
+	// reactionFocuses are the focus types reactions are evaluated at.
+	reactionFocuses = []shared.FocusKind{shared.LevelFocus, shared.VWAPFocus, shared.ImbalanceFocus,
+		shared.OrderBlockFocus}
+	// actionableReactions are the price reactions entries are taken on.
+	actionableReactions = []shared.PriceReaction{shared.Reversal, shared.Break, shared.Sweep}
+)
+
+// parseFocusKind parses the focus kind from the provided string.
+func parseFocusKind(focus string) (shared.FocusKind, bool) {
+	for _, kind := range reactionFocuses {
+		if kind.String() == focus {
+			return kind, true
+		}
+	}
+
+	return 0, false
+}
+
+// parsePriceReaction parses the actionable price reaction from the provided string.
+func parsePriceReaction(reaction string) (shared.PriceReaction, bool) {
+	for _, kind := range actionableReactions {
+		if kind.String() == reaction {
+			return kind, true
+		}
+	}
+
+	return 0, false
+}
+
+// ParseEnabledReactions parses the set of enabled reaction kinds from the provided entries. An
+// entry is a price reaction enabled at all focuses (reversal, break or sweep), a focus with
+// all reactions at it enabled (level, vwap, imbalance or orderblock), or a focus:reaction pair.
+func ParseEnabledReactions(entries []string) (map[ReactionKind]struct{}, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	enabled := make(map[ReactionKind]struct{})
+	for idx := range entries {
+		entry := entries[idx]
+		focusStr, reactionStr, paired := strings.Cut(entry, ":")
+
+		focuses := reactionFocuses
+		reactions := actionableReactions
+		switch {
+		case paired:
+			focus, ok := parseFocusKind(focusStr)
+			if !ok {
+				return nil, fmt.Errorf("unknown focus provided for %s reaction entry", entry)
+			}
+			reaction, ok := parsePriceReaction(reactionStr)
+			if !ok {
+				return nil, fmt.Errorf("unknown reaction provided for %s reaction entry", entry)
+			}
+
+			focuses = []shared.FocusKind{focus}
+			reactions = []shared.PriceReaction{reaction}
+		default:
+			if focus, ok := parseFocusKind(entry); ok {
+				focuses = []shared.FocusKind{focus}
+				break
+			}
+			if reaction, ok := parsePriceReaction(entry); ok {
+				reactions = []shared.PriceReaction{reaction}
+				break
+			}
+
+			return nil, fmt.Errorf("unknown reaction entry provided: %s", entry)
+		}
+
+		for _, focus := range focuses {
+			for _, reaction := range reactions {
+				enabled[ReactionKind{Focus: focus, Reaction: reaction}] = struct{}{}
+			}
+		}
+	}
+
+	return enabled, nil
+}
+
+// reactionEnabled checks whether entries are enabled for the kind of the provided reaction,
+// logging disabled reactions.
+func (e *Engine) reactionEnabled(reaction *shared.ReactionAtFocus) bool {
+	if e.cfg.EnabledReactions == nil {
+		return true
+	}
+
+	kind := ReactionKind{Focus: reaction.Focus, Reaction: reaction.Reaction}
+	if _, ok := e.cfg.EnabledReactions[kind]; ok {
+		return true
+	}
+
+	e.reactionLogger(reaction.CorrelationID).Info().Msgf("skipping %s entry for %s, %s reactions are disabled",
+		reaction.Reaction.String(), reaction.Market, kind.String())
+
+	return false
+}
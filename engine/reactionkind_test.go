@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestParseEnabledReactions(t *testing.T) {
+	levelReversal := ReactionKind{Focus: shared.LevelFocus, Reaction: shared.Reversal}
+	vwapReversal := ReactionKind{Focus: shared.VWAPFocus, Reaction: shared.Reversal}
+	imbalanceReversal := ReactionKind{Focus: shared.ImbalanceFocus, Reaction: shared.Reversal}
+	imbalanceBreak := ReactionKind{Focus: shared.ImbalanceFocus, Reaction: shared.Break}
+	imbalanceSweep := ReactionKind{Focus: shared.ImbalanceFocus, Reaction: shared.Sweep}
+	vwapBreak := ReactionKind{Focus: shared.VWAPFocus, Reaction: shared.Break}
+	orderBlockReversal := ReactionKind{Focus: shared.OrderBlockFocus, Reaction: shared.Reversal}
+
+	tests := []struct {
+		name    string
+		entries []string
+		want    map[ReactionKind]struct{}
+		wantErr bool
+	}{
+		{"empty enables all", nil, nil, false},
+		{"reaction at all focuses", []string{"reversal"},
+			map[ReactionKind]struct{}{levelReversal: {}, vwapReversal: {}, imbalanceReversal: {},
+				orderBlockReversal: {}}, false},
+		{"all reactions at focus", []string{"imbalance"},
+			map[ReactionKind]struct{}{imbalanceReversal: {}, imbalanceBreak: {}, imbalanceSweep: {}}, false},
+		{"focus reaction pairs", []string{"level:reversal", "vwap:break"},
+			map[ReactionKind]struct{}{levelReversal: {}, vwapBreak: {}}, false},
+		{"unknown entry", []string{"chop"}, nil, true},
+		{"unknown focus", []string{"trend:break"}, nil, true},
+		{"unknown reaction", []string{"level:chop"}, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			enabled, err := ParseEnabledReactions(test.entries)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, enabled, test.want)
+		})
+	}
+}
+
+func TestEnabledReactions(t *testing.T) {
+	avgVolume := float64(4)
+	asiaSessionTime, _ := generateSessionTimes(t)
+	reversalMeta := []*shared.CandleMetadata{
+		{Kind: shared.Doji, Sentiment: shared.Bearish, Momentum: shared.Low, Volume: 1, High: 5, Low: 4, Date: asiaSessionTime},
+		{Kind: shared.Hammer, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 4, High: 6, Low: 4, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 5, High: 9, Low: 6, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.High, Volume: 8, High: 14, Low: 9, Date: asiaSessionTime},
+	}
+	breakMeta := []*shared.CandleMetadata{
+		{Kind: shared.Doji, Sentiment: shared.Bearish, Momentum: shared.Low, Volume: 1, High: 11, Low: 9, Date: asiaSessionTime},
+		{Kind: shared.ShootingStar, Sentiment: shared.Bearish, Momentum: shared.Medium, Volume: 4, High: 9, Low: 7, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bearish, Momentum: shared.Medium, Volume: 5, High: 7, Low: 5, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bearish, Momentum: shared.High, Volume: 8, High: 6, Low: 1, Date: asiaSessionTime},
+	}
+
+	market := "^GSPC"
+	level := &shared.Level{Market: market, Price: 3, Kind: shared.Support}
+	reversal := func() *shared.ReactionAtFocus {
+		return &shared.ReactionAtFocus{
+			Market:        market,
+			Focus:         shared.LevelFocus,
+			LevelKind:     shared.Support,
+			CurrentPrice:  14,
+			Timeframe:     shared.FiveMinute,
+			PriceMovement: []shared.PriceMovement{shared.Above, shared.Above, shared.Above, shared.Above},
+			Reaction:      shared.Reversal,
+			CreatedOn:     asiaSessionTime,
+		}
+	}
+	breakdown := func() *shared.ReactionAtFocus {
+		return &shared.ReactionAtFocus{
+			Market:        market,
+			Focus:         shared.ImbalanceFocus,
+			LevelKind:     shared.Support,
+			CurrentPrice:  1,
+			Timeframe:     shared.FiveMinute,
+			PriceMovement: []shared.PriceMovement{shared.Below, shared.Below, shared.Below, shared.Below},
+			Reaction:      shared.Break,
+			CreatedOn:     asiaSessionTime,
+		}
+	}
+
+	tests := []struct {
+		name          string
+		entries       []string
+		wantReversal  bool
+		wantBreakdown bool
+	}{
+		{"all enabled", nil, true, true},
+		{"reversals only", []string{"reversal"}, true, false},
+		{"breaks only", []string{"break"}, false, true},
+		{"imbalance only", []string{"imbalance"}, false, true},
+		{"level reversals only", []string{"level:reversal"}, true, false},
+		{"vwap only", []string{"vwap"}, false, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			enabled, err := ParseEnabledReactions(test.entries)
+			assert.NoError(t, err)
+
+			// Ensure only enabled reversals generate entry signals.
+			longSkew := shared.LongSkewed
+			eng, entrySignals, _ := setupEngine(&avgVolume, reversalMeta, &longSkew)
+			eng.cfg.EnabledReactions = enabled
+			err = eng.evaluatePriceReversalStrength(context.Background(), reversal(), level, reversalMeta,
+				minLevelReversalConfluence, minLevelReversalConfluence)
+			assert.NoError(t, err)
+			assert.Equal(t, len(entrySignals) == 1, test.wantReversal)
+
+			// Ensure only enabled breaks generate entry signals.
+			shortSkew := shared.ShortSkewed
+			eng, entrySignals, _ = setupEngine(&avgVolume, breakMeta, &shortSkew)
+			eng.cfg.EnabledReactions = enabled
+			err = eng.evaluateBreakStrength(context.Background(), breakdown(), nil, breakMeta,
+				minImbalanceBreakConfluence, minImbalanceBreakConfluence)
+			assert.NoError(t, err)
+			assert.Equal(t, len(entrySignals) == 1, test.wantBreakdown)
+		})
+	}
+
+	// Ensure exits are signalled for disabled reactions.
+	longSkew := shared.LongSkewed
+	eng, _, exitSignals := setupEngine(&avgVolume, breakMeta, &longSkew)
+	eng.cfg.EnabledReactions = map[ReactionKind]struct{}{}
+	err := eng.evaluateBreakStrength(context.Background(), breakdown(), nil, breakMeta, minImbalanceBreakConfluence,
+		minImbalanceBreakConfluence)
+	assert.NoError(t, err)
+	assert.Equal(t, len(exitSignals), 1)
+}
@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/dnldd/entry/shared"
+)
+
+const (
+	// defaultStopOutWindow is the default time after a stop out the next reaction at its level
+	// is held to the raised entry threshold.
+	defaultStopOutWindow = time.Hour * 4
+)
+
+// stopOutWindow returns the time after a stop out the next reaction at its level is held to
+// the raised entry threshold.
+func (e *Engine) stopOutWindow() time.Duration {
+	if e.cfg.StopOutWindow == 0 {
+		return defaultStopOutWindow
+	}
+
+	return e.cfg.StopOutWindow
+}
+
+// reentryThreshold returns the entry threshold of the provided reaction at a level, raised by
+// the stop out bump if a position entered at the level was recently stopped out. The level's
+// stop out is consumed by the reaction.
+func (e *Engine) reentryThreshold(reaction *shared.ReactionAtLevel, threshold uint32) uint32 {
+	if e.cfg.StopOutBump == 0 {
+		return threshold
+	}
+
+	stoppedOn, ok := reaction.Level.TakeStopOut()
+	if !ok || reaction.CreatedOn.Sub(stoppedOn) > e.stopOutWindow() {
+		return threshold
+	}
+
+	raised := threshold + e.cfg.StopOutBump
+	e.reactionLogger(reaction.CorrelationID).Info().Msgf("raising %s entry threshold @ %.2f from %d to %d after a stop out @ %v",
+		reaction.Market, reaction.Level.Price, threshold, raised, stoppedOn)
+
+	return raised
+}
+
+// levelPrice returns the price of the provided level, zero for reactions at dynamic levels.
+func levelPrice(level *shared.Level) float64 {
+	if level == nil {
+		return 0
+	}
+
+	return level.Price
+}
@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestReentryThreshold(t *testing.T) {
+	avgVolume := float64(4)
+	asiaSessionTime, _ := generateSessionTimes(t)
+	marketSkew := shared.NeutralSkew
+	eng, _, _ := setupEngine(&avgVolume, nil, &marketSkew)
+
+	market := "^GSPC"
+	threshold := uint32(minLevelReversalConfluence)
+	bump := uint32(2)
+
+	tests := []struct {
+		name      string
+		bump      uint32
+		window    time.Duration
+		stoppedOn time.Duration
+		want      uint32
+	}{
+		{"no stop out bump", 0, 0, time.Hour, threshold},
+		{"stop out within the default window", bump, 0, time.Hour, threshold + bump},
+		{"stop out outside the default window", bump, 0, time.Hour * 5, threshold},
+		{"stop out within a configured window", bump, time.Minute * 30, time.Minute * 20, threshold + bump},
+		{"stop out outside a configured window", bump, time.Minute * 30, time.Hour, threshold},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eng.cfg.StopOutBump = test.bump
+			eng.cfg.StopOutWindow = test.window
+
+			level := shared.NewLevel(market, float64(10), float64(12))
+			level.RecordStopOut(asiaSessionTime.Add(-test.stoppedOn))
+			reaction := shared.ReactionAtLevel{
+				ReactionAtFocus: shared.ReactionAtFocus{
+					Market:    market,
+					Timeframe: shared.FiveMinute,
+					LevelKind: shared.Support,
+					Reaction:  shared.Reversal,
+					CreatedOn: asiaSessionTime,
+				},
+				Level: level,
+			}
+
+			// Ensure the entry threshold is only raised for recent stop outs at the level.
+			assert.Equal(t, eng.reentryThreshold(&reaction, threshold), test.want)
+
+			// Ensure the stop out only raises the threshold of the next reaction at the level.
+			assert.Equal(t, eng.reentryThreshold(&reaction, threshold), threshold)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+)
+
+const (
+	// minRegimeWindow is the minimum number of candles a market regime is evaluated over.
+	minRegimeWindow = 3
+	// defaultRegimeEfficiency is the default minimum efficiency of a trending market.
+	defaultRegimeEfficiency = float64(0.3)
+)
+
+// RegimeFilter represents the criteria for suppressing entries in ranging markets.
+type RegimeFilter struct {
+	// Window is the number of candles the market regime is evaluated over.
+	Window uint32
+	// MinEfficiency is the minimum ratio of the net displacement to the summed true range of
+	// the window for the market to be trending. Markets below it are choppy. The default
+	// efficiency is used if zero.
+	MinEfficiency float64
+	// OverrideConfluence is the confluence at or above which entries are taken in choppy
+	// markets. Entries are never taken in choppy markets if zero.
+	OverrideConfluence uint32
+}
+
+// Validate asserts the filter sane inputs.
+func (f *RegimeFilter) Validate() error {
+	var errs error
+
+	if f.Window < minRegimeWindow {
+		errs = errors.Join(errs, fmt.Errorf("regime window must be at least %d candles", minRegimeWindow))
+	}
+	if f.MinEfficiency < 0 || f.MinEfficiency > 1 {
+		errs = errors.Join(errs, fmt.Errorf("regime minimum efficiency must be in the [0, 1] range"))
+	}
+
+	return errs
+}
+
+// efficiency returns the ratio of the net displacement of the provided candles to their
+// summed true range. Trending candles approach one, ranging candles approach zero.
+func efficiency(candles []*shared.Candlestick) float64 {
+	if len(candles) == 0 {
+		return 0
+	}
+
+	var trueRange float64
+	for idx, candle := range candles {
+		high, low := candle.High, candle.Low
+		if idx > 0 {
+			prevClose := candles[idx-1].Close
+			high = math.Max(high, prevClose)
+			low = math.Min(low, prevClose)
+		}
+
+		trueRange += high - low
+	}
+
+	if trueRange == 0 {
+		return 0
+	}
+
+	displacement := math.Abs(candles[len(candles)-1].Close - candles[0].Open)
+
+	return displacement / trueRange
+}
+
+// fetchPriceData fetches the most recent price data of the provided market and timeframe.
+func (e *Engine) fetchPriceData(ctx context.Context, market string, timeframe shared.Timeframe, n uint32) ([]*shared.Candlestick, error) {
+	if e.cfg.RequestPriceData == nil {
+		return nil, fmt.Errorf("no price data request function configured")
+	}
+
+	req := shared.NewPriceDataRequest(market, timeframe, n)
+	e.cfg.RequestPriceData(*req)
+
+	select {
+	case data := <-req.Response:
+		return data, nil
+	case err := <-req.Err:
+		return nil, err
+	case <-time.After(e.cfg.Timeouts.PriceDataTimeout()):
+		return nil, fmt.Errorf("timed out fetching price data for %s", market)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// evaluateMarketRegime returns whether entries for the provided reaction should be suppressed
+// because its market is ranging. Exceptionally high confluence overrides the regime.
+func (e *Engine) evaluateMarketRegime(ctx context.Context, reaction *shared.ReactionAtFocus, direction shared.Direction, confluence uint32) (bool, error) {
+	filter := e.cfg.RegimeFilter
+	if filter == nil {
+		return false, nil
+	}
+
+	if filter.OverrideConfluence > 0 && confluence >= filter.OverrideConfluence {
+		return false, nil
+	}
+
+	data, err := e.fetchPriceData(ctx, reaction.Market, reaction.Timeframe, filter.Window)
+	if err != nil {
+		return false, fmt.Errorf("fetching price data: %w", err)
+	}
+
+	minEfficiency := filter.MinEfficiency
+	if minEfficiency == 0 {
+		minEfficiency = defaultRegimeEfficiency
+	}
+
+	ratio := efficiency(data)
+	if ratio >= minEfficiency {
+		return false, nil
+	}
+
+	e.reactionLogger(reaction.CorrelationID).Info().Msgf("suppressing %s %s entry @ %v in choppy market, efficiency – (%.2f)",
+		reaction.Market, direction.String(), reaction.CreatedOn, ratio)
+
+	return true, nil
+}
@@ -0,0 +1,145 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+// trendingSeries returns candles steadily rising over the provided number of candles.
+func trendingSeries(n int) []*shared.Candlestick {
+	candles := make([]*shared.Candlestick, 0, n)
+	for idx := range n {
+		open := float64(10 + idx)
+		candles = append(candles, &shared.Candlestick{
+			Open:  open,
+			High:  open + 1.2,
+			Low:   open - 0.2,
+			Close: open + 1,
+		})
+	}
+
+	return candles
+}
+
+// rangingSeries returns candles alternating within a tight range over the provided number
+// of candles.
+func rangingSeries(n int) []*shared.Candlestick {
+	candles := make([]*shared.Candlestick, 0, n)
+	for idx := range n {
+		open, close := float64(10), float64(11)
+		if idx%2 == 1 {
+			open, close = close, open
+		}
+		candles = append(candles, &shared.Candlestick{
+			Open:  open,
+			High:  11.2,
+			Low:   9.8,
+			Close: close,
+		})
+	}
+
+	return candles
+}
+
+func TestRegimeFilterValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  RegimeFilter
+		wantErr bool
+	}{
+		{"valid filter", RegimeFilter{Window: 12, MinEfficiency: 0.3, OverrideConfluence: 10}, false},
+		{"window below minimum", RegimeFilter{Window: 2, MinEfficiency: 0.3}, true},
+		{"default efficiency", RegimeFilter{Window: 12}, false},
+		{"negative efficiency", RegimeFilter{Window: 12, MinEfficiency: -0.3}, true},
+		{"efficiency above one", RegimeFilter{Window: 12, MinEfficiency: 1.5}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.filter.Validate()
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestEfficiency(t *testing.T) {
+	// Ensure trending candles are efficient.
+	assert.True(t, efficiency(trendingSeries(12)) > 0.5)
+
+	// Ensure ranging candles are inefficient.
+	assert.True(t, efficiency(rangingSeries(12)) < 0.1)
+
+	// Ensure flat or no candles are inefficient.
+	assert.Equal(t, efficiency(nil), float64(0))
+	flat := []*shared.Candlestick{{Open: 10, High: 10, Low: 10, Close: 10}}
+	assert.Equal(t, efficiency(flat), float64(0))
+}
+
+func TestMarketRegime(t *testing.T) {
+	avgVolume := float64(4)
+	asiaSessionTime, _ := generateSessionTimes(t)
+	market := "^GSPC"
+	candleMeta := []*shared.CandleMetadata{
+		{Kind: shared.Doji, Sentiment: shared.Bearish, Momentum: shared.Low, Volume: 1, High: 5, Low: 4, Date: asiaSessionTime},
+		{Kind: shared.Hammer, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 4, High: 6, Low: 4, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 5, High: 9, Low: 6, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.High, Volume: 8, High: 14, Low: 9, Date: asiaSessionTime},
+	}
+	supportReversal := &shared.ReactionAtFocus{
+		Market:        market,
+		LevelKind:     shared.Support,
+		CurrentPrice:  float64(14),
+		Timeframe:     shared.FiveMinute,
+		PriceMovement: []shared.PriceMovement{shared.Above, shared.Above, shared.Above, shared.Above},
+		Reaction:      shared.Reversal,
+		CreatedOn:     asiaSessionTime,
+	}
+
+	var series []*shared.Candlestick
+	requestPriceData := func(req shared.PriceDataRequest) {
+		req.Response <- series
+	}
+
+	marketSkew := shared.NeutralSkew
+	eng, entrySignals, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+	eng.cfg.RequestPriceData = requestPriceData
+	eng.cfg.RegimeFilter = &RegimeFilter{Window: 12, MinEfficiency: 0.3}
+
+	// Ensure entries fire when the market is trending.
+	series = trendingSeries(12)
+	err := eng.evaluatePriceReversalStrength(context.Background(), supportReversal, nil, candleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
+	assert.NoError(t, err)
+	assert.Equal(t, len(entrySignals), 1)
+	entrySignal := <-entrySignals
+	assert.Equal(t, entrySignal.Direction, shared.Long)
+
+	// Ensure entries are suppressed when the market is ranging.
+	eng, entrySignals, _ = setupEngine(&avgVolume, candleMeta, &marketSkew)
+	eng.cfg.RequestPriceData = requestPriceData
+	eng.cfg.RegimeFilter = &RegimeFilter{Window: 12}
+	series = rangingSeries(12)
+	err = eng.evaluatePriceReversalStrength(context.Background(), supportReversal, nil, candleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
+	assert.NoError(t, err)
+	assert.Equal(t, len(entrySignals), 0)
+
+	// Ensure exceptionally high confluence overrides a ranging market.
+	eng.cfg.RegimeFilter.OverrideConfluence = minLevelReversalConfluence
+	err = eng.evaluatePriceReversalStrength(context.Background(), supportReversal, nil, candleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
+	assert.NoError(t, err)
+	assert.Equal(t, len(entrySignals), 1)
+
+	// Ensure entries are not suppressed without a regime filter.
+	<-entrySignals
+	eng.cfg.RegimeFilter = nil
+	err = eng.evaluatePriceReversalStrength(context.Background(), supportReversal, nil, candleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
+	assert.NoError(t, err)
+	assert.Equal(t, len(entrySignals), 1)
+}
@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/dnldd/entry/shared"
+)
+
+// replayCandleMetadata generates the metadata of the provided candles, the first candle only
+// seeds the momentum of the candle following it.
+func (e *Engine) replayCandleMetadata(candles []*shared.Candlestick) []*shared.CandleMetadata {
+	classification := shared.DefaultClassificationConfig()
+	if e.cfg.Classification != nil {
+		classification = *e.cfg.Classification
+	}
+
+	meta := make([]*shared.CandleMetadata, 0, len(candles)-1)
+	for idx := 1; idx < len(candles); idx++ {
+		current := candles[idx]
+		previous := candles[idx-1]
+
+		meta = append(meta, &shared.CandleMetadata{
+			Kind:      current.FetchKind(classification),
+			Sentiment: current.FetchSentiment(),
+			Momentum:  shared.GenerateMomentum(current, previous),
+			Volume:    current.Volume,
+			Engulfing: shared.IsEngulfing(current, previous, classification),
+			Open:      current.Open,
+			High:      current.High,
+			Low:       current.Low,
+			Close:     current.Close,
+			Date:      current.Date,
+		})
+	}
+
+	return meta
+}
+
+// replayVolume returns the average volume of the provided candles and the scale volume
+// differences are measured in.
+func (e *Engine) replayVolume(candles []*shared.Candlestick) (float64, float64) {
+	var volumeSum float64
+	for idx := range candles {
+		volumeSum += candles[idx].Volume
+	}
+	average := volumeSum / float64(len(candles))
+
+	if e.cfg.VolumeNormalization != ZScoreVolume {
+		return average, average
+	}
+
+	var squaredSum float64
+	for idx := range candles {
+		diff := candles[idx].Volume - average
+		squaredSum += diff * diff
+	}
+
+	return average, math.Sqrt(squaredSum / float64(len(candles)))
+}
+
+// ReplayLevelReaction evaluates the reaction of the provided candles at the provided level,
+// returning the confluence breakdown and the entry decision it would lead to. The candles are
+// ordered oldest first, the first candle precedes the reaction window and only seeds the
+// momentum of the candle following it. The average volume is measured over all the provided
+// candles.
+//
+// Replays are side-effect free, they do not fire signals, consult positions or write to the
+// audit log, the market skew, trend bias and entry filters are not applied.
+func (e *Engine) ReplayLevelReaction(market string, timeframe shared.Timeframe, level *shared.Level, candles []*shared.Candlestick) (*AuditRecord, error) {
+	if level == nil {
+		return nil, fmt.Errorf("no level provided")
+	}
+	if len(candles) < shared.MinReactionWindow+1 {
+		return nil, fmt.Errorf("replay requires at least %d candles, got %d",
+			shared.MinReactionWindow+1, len(candles))
+	}
+	for idx := range candles {
+		candle := candles[idx]
+		if candle.Timeframe != timeframe {
+			return nil, fmt.Errorf("expected %s candle at index %d, got %s", timeframe.String(),
+				idx, candle.Timeframe.String())
+		}
+		if idx > 0 && !candle.Date.After(candles[idx-1].Date) {
+			return nil, fmt.Errorf("candle at index %d is not newer than the candle preceding it", idx)
+		}
+	}
+
+	reaction, err := shared.NewReactionAtLevel(market, level, candles[1:])
+	if err != nil {
+		return nil, fmt.Errorf("creating reaction at level: %v", err)
+	}
+
+	meta := e.replayCandleMetadata(candles)
+	averageVolume, volumeScale := e.replayVolume(candles)
+	thresholds := e.Thresholds()
+	exitThresholds := e.exitThresholds()
+
+	var signal bool
+	var confluence uint32
+	var direction shared.Direction
+	var record *AuditRecord
+	switch reaction.Reaction {
+	case shared.Reversal, shared.Sweep:
+		record = newAuditRecord(&reaction.ReactionAtFocus, meta, thresholds.LevelReversal,
+			exitThreshold(exitThresholds.LevelReversal, thresholds.LevelReversal))
+		signal, confluence, _, err = e.scorePriceReversal(&reaction.ReactionAtFocus, meta, averageVolume,
+			volumeScale, nil, thresholds.LevelReversal, record)
+		if err != nil {
+			return nil, fmt.Errorf("scoring price reversal: %v", err)
+		}
+
+		// A reversal at support is bullish, a reversal at resistance is bearish.
+		direction = shared.Long
+		if reaction.LevelKind == shared.Resistance {
+			direction = shared.Short
+		}
+	case shared.Break:
+		record = newAuditRecord(&reaction.ReactionAtFocus, meta, thresholds.LevelBreak,
+			exitThreshold(exitThresholds.LevelBreak, thresholds.LevelBreak))
+		signal, confluence, _, err = e.scoreLevelBreak(&reaction.ReactionAtFocus, meta, averageVolume,
+			volumeScale, thresholds.LevelBreak, record)
+		if err != nil {
+			return nil, fmt.Errorf("scoring level break: %v", err)
+		}
+
+		// A break above resistance is bullish, a break below support is bearish.
+		direction = shared.Long
+		if reaction.LevelKind == shared.Support {
+			direction = shared.Short
+		}
+	default:
+		// Chop is not actionable.
+		return newAuditRecord(&reaction.ReactionAtFocus, meta, 0, 0), nil
+	}
+
+	record.Confluence = confluence
+	if signal {
+		record.setDecision(entryDecision, direction)
+	}
+
+	return record, nil
+}
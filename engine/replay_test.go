@@ -0,0 +1,97 @@
+package engine
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestReplayLevelReaction(t *testing.T) {
+	avgVolume := float64(4)
+	marketSkew := shared.NeutralSkew
+	eng, entrySignals, exitSignals := setupEngine(&avgVolume, nil, &marketSkew)
+	var buf bytes.Buffer
+	eng.cfg.Audit = NewAuditWriter(&buf)
+
+	asiaSessionTime, _ := generateSessionTimes(t)
+	market := "^GSPC"
+	candle := func(idx int, open, high, low, close, volume float64) *shared.Candlestick {
+		return &shared.Candlestick{
+			Open:      open,
+			High:      high,
+			Low:       low,
+			Close:     close,
+			Volume:    volume,
+			Market:    market,
+			Timeframe: shared.FiveMinute,
+			Date:      asiaSessionTime.Add(time.Minute * 5 * time.Duration(idx)),
+			Closed:    true,
+		}
+	}
+
+	// A strong bullish reversal off support at 10.
+	reversal := []*shared.Candlestick{
+		candle(0, 12, 12.5, 10.5, 11, 2),
+		candle(1, 11, 11.5, 10, 11, 2),
+		candle(2, 11, 13, 10.5, 12.8, 4),
+		candle(3, 12.8, 16, 12.5, 15.8, 12),
+		candle(4, 15.8, 20, 15.5, 19.8, 30),
+	}
+	support := &shared.Level{Market: market, Price: 10, Kind: shared.Support, Source: shared.DailyLevel}
+
+	// Ensure a known good window reports the expected reasons and entry decision.
+	record, err := eng.ReplayLevelReaction(market, shared.FiveMinute, support, reversal)
+	assert.NoError(t, err)
+	assert.Equal(t, record.Market, market)
+	assert.Equal(t, record.Reaction, shared.Reversal.String())
+	assert.Equal(t, record.LevelKind, shared.Support.String())
+	assert.Equal(t, record.Price, float64(19.8))
+	assert.Equal(t, len(record.Candles), len(reversal)-1)
+	assert.Equal(t, record.AverageVolume, float64(10))
+	assert.Equal(t, record.EntryThreshold, uint32(minLevelReversalConfluence))
+	assert.Equal(t, record.Reasons[shared.ReversalAtSupport.String()], uint32(1))
+	assert.Equal(t, record.Reasons[shared.SignificantLevel.String()], DefaultLevelWeights()[shared.DailyLevel])
+	assert.True(t, record.Reasons[shared.StrongMove.String()] > 0)
+	assert.True(t, record.Reasons[shared.StrongVolume.String()] > 0)
+	var total uint32
+	for _, weight := range record.Reasons {
+		total += weight
+	}
+	assert.Equal(t, total, record.Confluence)
+	assert.True(t, record.Confluence >= minLevelReversalConfluence)
+	assert.Equal(t, record.Decision, entryDecision)
+	assert.Equal(t, record.Direction, shared.Long.String())
+
+	// Ensure a replayed window below the confluence threshold reports no decision.
+	weak := []*shared.Candlestick{
+		candle(0, 12, 12.5, 10.5, 11, 10),
+		candle(1, 11, 11.5, 10, 11, 10),
+		candle(2, 11, 11.2, 10.5, 10.8, 9),
+		candle(3, 10.8, 11, 10.4, 10.9, 9),
+	}
+	record, err = eng.ReplayLevelReaction(market, shared.FiveMinute, &shared.Level{Market: market, Price: 10, Kind: shared.Support}, weak)
+	assert.NoError(t, err)
+	assert.Equal(t, record.Reaction, shared.Reversal.String())
+	assert.True(t, record.Confluence < minLevelReversalConfluence)
+	assert.Equal(t, record.Decision, noDecision)
+	assert.Equal(t, record.Direction, "")
+
+	// Ensure replays require a level, enough candles and ordered candles of the timeframe.
+	_, err = eng.ReplayLevelReaction(market, shared.FiveMinute, nil, reversal)
+	assert.Error(t, err)
+	_, err = eng.ReplayLevelReaction(market, shared.FiveMinute, support, reversal[:shared.MinReactionWindow])
+	assert.Error(t, err)
+	_, err = eng.ReplayLevelReaction(market, shared.OneHour, support, reversal)
+	assert.Error(t, err)
+	unordered := []*shared.Candlestick{reversal[0], reversal[2], reversal[1], reversal[3]}
+	_, err = eng.ReplayLevelReaction(market, shared.FiveMinute, support, unordered)
+	assert.Error(t, err)
+
+	// Ensure replays are side-effect free.
+	assert.Equal(t, len(entrySignals), 0)
+	assert.Equal(t, len(exitSignals), 0)
+	assert.Equal(t, buf.Len(), 0)
+}
@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dnldd/entry/shared"
+)
+
+// ParseRTHWindows parses per market regular trading hours from the provided
+// market=HH:MM-HH:MM entries.
+func ParseRTHWindows(entries []string) (map[string]shared.SessionWindow, error) {
+	windows := make(map[string]shared.SessionWindow, len(entries))
+	for idx := range entries {
+		market, value, ok := strings.Cut(entries[idx], "=")
+		if !ok || market == "" {
+			return nil, fmt.Errorf("invalid regular trading hours entry provided: %s", entries[idx])
+		}
+
+		parsed, err := shared.ParseSessionWindows([]string{value})
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s regular trading hours: %v", market, err)
+		}
+
+		windows[market] = parsed[0]
+	}
+
+	return windows, nil
+}
+
+// outsideRTH checks whether the provided reaction occurred outside the regular trading hours
+// of its market, logging reactions outside them. Markets without regular trading hours are
+// never outside them.
+func (e *Engine) outsideRTH(reaction *shared.ReactionAtFocus, direction shared.Direction) bool {
+	window, ok := e.cfg.RTHWindows[reaction.Market]
+	if !ok {
+		return false
+	}
+
+	inside, err := window.Contains(reaction.CreatedOn)
+	if err != nil {
+		e.reactionLogger(reaction.CorrelationID).Error().Msgf("checking %s regular trading hours: %v",
+			reaction.Market, err)
+		return false
+	}
+	if inside {
+		return false
+	}
+
+	e.reactionLogger(reaction.CorrelationID).Info().Msgf("suppressing %s %s entry @ %v outside regular trading hours (%s-%s)",
+		reaction.Market, direction.String(), reaction.CreatedOn, window.Start, window.End)
+
+	return true
+}
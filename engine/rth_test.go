@@ -0,0 +1,125 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestParseRTHWindows(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		want    map[string]shared.SessionWindow
+		wantErr bool
+	}{
+		{"no entries", nil, map[string]shared.SessionWindow{}, false},
+		{"valid entries", []string{"^GSPC=09:30-16:00", "^IXIC=09:30-16:00"},
+			map[string]shared.SessionWindow{
+				"^GSPC": {Start: "09:30", End: "16:00"},
+				"^IXIC": {Start: "09:30", End: "16:00"},
+			}, false},
+		{"missing separator", []string{"^GSPC"}, nil, true},
+		{"missing market", []string{"=09:30-16:00"}, nil, true},
+		{"missing window end", []string{"^GSPC=09:30"}, nil, true},
+		{"invalid window", []string{"^GSPC=09:30-25:00"}, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			windows, err := ParseRTHWindows(test.entries)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, windows, test.want)
+		})
+	}
+}
+
+func TestRTHWindows(t *testing.T) {
+	avgVolume := float64(4)
+	asiaSessionTime, _ := generateSessionTimes(t)
+	rthTime := time.Date(asiaSessionTime.Year(), asiaSessionTime.Month(), asiaSessionTime.Day(),
+		10, 0, 0, 0, asiaSessionTime.Location())
+	bullishMeta := []*shared.CandleMetadata{
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 5, High: 9, Low: 6, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.High, Volume: 8, High: 14, Low: 9, Date: asiaSessionTime},
+	}
+	bearishMeta := []*shared.CandleMetadata{
+		{Kind: shared.Marubozu, Sentiment: shared.Bearish, Momentum: shared.Medium, Volume: 5, High: 14, Low: 11, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bearish, Momentum: shared.High, Volume: 8, High: 11, Low: 6, Date: asiaSessionTime},
+	}
+
+	market := "^GSPC"
+	rthWindows := map[string]shared.SessionWindow{market: {Start: "09:30", End: "16:00"}}
+
+	tests := []struct {
+		name      string
+		windows   map[string]shared.SessionWindow
+		createdOn time.Time
+		wantEntry bool
+	}{
+		{"inside regular trading hours", rthWindows, rthTime, true},
+		{"outside regular trading hours", rthWindows, asiaSessionTime, false},
+		{"market without regular trading hours", map[string]shared.SessionWindow{}, asiaSessionTime, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			supportReversal := shared.ReactionAtFocus{
+				Market:        market,
+				Timeframe:     shared.FiveMinute,
+				LevelKind:     shared.Support,
+				CurrentPrice:  14,
+				PriceMovement: []shared.PriceMovement{shared.Above, shared.Above, shared.Above, shared.Above},
+				Reaction:      shared.Reversal,
+				CreatedOn:     test.createdOn,
+			}
+
+			marketSkew := shared.NeutralSkew
+			eng, entrySignals, _ := setupEngine(&avgVolume, bullishMeta, &marketSkew)
+			eng.cfg.RTHWindows = test.windows
+
+			err := eng.evaluatePriceReversalStrength(context.Background(), &supportReversal, nil, bullishMeta,
+				minLevelReversalConfluence, minLevelReversalConfluence)
+			assert.NoError(t, err)
+
+			// Ensure entries are only produced within regular trading hours of rth only markets.
+			if !test.wantEntry {
+				assert.Equal(t, len(entrySignals), 0)
+				return
+			}
+
+			assert.Equal(t, len(entrySignals), 1)
+			entry := <-entrySignals
+			assert.Equal(t, entry.Direction, shared.Long)
+		})
+	}
+
+	// Ensure reactions outside regular trading hours still exit existing positions.
+	resistanceReversal := shared.ReactionAtFocus{
+		Market:        market,
+		Timeframe:     shared.FiveMinute,
+		LevelKind:     shared.Resistance,
+		CurrentPrice:  6,
+		PriceMovement: []shared.PriceMovement{shared.Below, shared.Below, shared.Below, shared.Below},
+		Reaction:      shared.Reversal,
+		CreatedOn:     asiaSessionTime,
+	}
+
+	marketSkew := shared.LongSkewed
+	eng, entrySignals, exitSignals := setupEngine(&avgVolume, bearishMeta, &marketSkew)
+	eng.cfg.RTHWindows = rthWindows
+
+	err := eng.evaluatePriceReversalStrength(context.Background(), &resistanceReversal, nil, bearishMeta,
+		minLevelReversalConfluence, minLevelReversalConfluence)
+	assert.NoError(t, err)
+	assert.Equal(t, len(entrySignals), 0)
+	assert.Equal(t, len(exitSignals), 1)
+}
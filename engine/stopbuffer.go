@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dnldd/entry/shared"
+)
+
+// StopBufferMode represents how the stop loss buffer of a market is measured.
+type StopBufferMode int
+
+const (
+	// BufferPoints buffers stops a fixed number of points.
+	BufferPoints StopBufferMode = iota
+	// BufferATR buffers stops a multiple of the average true range of the reaction's candles.
+	BufferATR
+)
+
+// String stringifies the provided stop buffer mode.
+func (m StopBufferMode) String() string {
+	switch m {
+	case BufferPoints:
+		return "points"
+	case BufferATR:
+		return "atr"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseStopBufferMode parses the stop buffer mode from the provided string. An empty string
+// defaults to BufferPoints.
+func ParseStopBufferMode(mode string) (StopBufferMode, error) {
+	switch mode {
+	case "", "points":
+		return BufferPoints, nil
+	case "atr":
+		return BufferATR, nil
+	default:
+		return 0, fmt.Errorf("unknown stop buffer mode provided: %s", mode)
+	}
+}
+
+// StopBuffer represents the distance stops are placed beyond the reaction's candles.
+type StopBuffer struct {
+	// Mode is how the buffer is measured.
+	Mode StopBufferMode
+	// Value is the points of the buffer, or the multiple of the average true range.
+	Value float64
+}
+
+// Validate asserts the stop buffer sane inputs.
+func (b *StopBuffer) Validate() error {
+	var errs error
+
+	if b.Mode != BufferPoints && b.Mode != BufferATR {
+		errs = errors.Join(errs, fmt.Errorf("unknown stop buffer mode provided: %d", b.Mode))
+	}
+	if b.Value < 0 {
+		errs = errors.Join(errs, fmt.Errorf("stop buffer cannot be negative"))
+	}
+
+	return errs
+}
+
+// distance returns the buffer distance for the provided candle metadata.
+func (b *StopBuffer) distance(meta []*shared.CandleMetadata) float64 {
+	if b.Mode == BufferATR {
+		return shared.CandleMetaAverageTrueRange(meta) * b.Value
+	}
+
+	return b.Value
+}
+
+// ParseStopBuffers parses per market stop buffers from the provided market=mode:value entries,
+// the mode is points or atr.
+func ParseStopBuffers(entries []string) (map[string]StopBuffer, error) {
+	buffers := make(map[string]StopBuffer, len(entries))
+	for idx := range entries {
+		market, spec, ok := strings.Cut(entries[idx], "=")
+		if !ok || market == "" {
+			return nil, fmt.Errorf("invalid stop buffer entry provided: %s", entries[idx])
+		}
+
+		modeStr, valueStr, ok := strings.Cut(spec, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid %s stop buffer provided: %s", market, spec)
+		}
+
+		mode, err := ParseStopBufferMode(modeStr)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s stop buffer mode: %v", market, err)
+		}
+
+		value, err := strconv.ParseFloat(valueStr, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s stop buffer: %v", market, err)
+		}
+
+		buffer := StopBuffer{Mode: mode, Value: value}
+		err = buffer.Validate()
+		if err != nil {
+			return nil, fmt.Errorf("validating %s stop buffer: %v", market, err)
+		}
+
+		buffers[market] = buffer
+	}
+
+	return buffers, nil
+}
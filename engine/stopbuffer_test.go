@@ -0,0 +1,95 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestParseStopBuffers(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		want    map[string]StopBuffer
+		wantErr bool
+	}{
+		{"no entries", nil, map[string]StopBuffer{}, false},
+		{"valid entries", []string{"^GSPC=points:2.5", "^NDX=atr:0.5"},
+			map[string]StopBuffer{
+				"^GSPC": {Mode: BufferPoints, Value: 2.5},
+				"^NDX":  {Mode: BufferATR, Value: 0.5},
+			}, false},
+		{"missing separator", []string{"^GSPC"}, nil, true},
+		{"missing market", []string{"=points:2"}, nil, true},
+		{"missing value", []string{"^GSPC=points"}, nil, true},
+		{"unknown mode", []string{"^GSPC=ticks:2"}, nil, true},
+		{"invalid value", []string{"^GSPC=points:wide"}, nil, true},
+		{"negative value", []string{"^GSPC=atr:-1"}, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buffers, err := ParseStopBuffers(test.entries)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, buffers, test.want)
+		})
+	}
+}
+
+func TestStopBuffers(t *testing.T) {
+	avgVolume := float64(10)
+	asianSessionTime, _ := generateSessionTimes(t)
+
+	// The candles have an average true range of (4 + 9) / 2 = 6.5, the signal candle low is 7.
+	meta := []*shared.CandleMetadata{
+		{Kind: shared.Doji, Sentiment: shared.Bearish, Momentum: shared.Low, Volume: 2, Open: 5, High: 6, Low: 2, Close: 4, Date: asianSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.High, Volume: 6, Open: 8, High: 13, Low: 7, Close: 12.5, Date: asianSessionTime},
+	}
+
+	buffers := map[string]StopBuffer{
+		"^GSPC": {Mode: BufferPoints, Value: 3},
+		"^NDX":  {Mode: BufferATR, Value: 0.5},
+		"^DJI":  {Mode: BufferPoints, Value: 0},
+	}
+
+	tests := []struct {
+		name        string
+		market      string
+		stopLoss    float64
+		pointsRange float64
+	}{
+		{"default points buffer", "^IXIC", 6, 8},
+		{"points buffer", "^GSPC", 4, 10},
+		{"atr scaled buffer", "^NDX", 3.75, 10.25},
+		{"zero buffer", "^DJI", 7, 7},
+	}
+
+	marketSkew := shared.NeutralSkew
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eng, _, _ := setupEngine(&avgVolume, meta, &marketSkew)
+			eng.cfg.StopBuffers = buffers
+
+			reaction := &shared.ReactionAtFocus{
+				Market:       test.market,
+				Timeframe:    shared.FiveMinute,
+				LevelKind:    shared.Support,
+				Reaction:     shared.Reversal,
+				CreatedOn:    asianSessionTime,
+				CurrentPrice: float64(14),
+			}
+
+			// Ensure stops shift by the configured buffer of the market.
+			stopLoss, pointsRange, err := eng.estimateStopLoss(reaction, nil, meta)
+			assert.NoError(t, err)
+			assert.Equal(t, stopLoss, test.stopLoss)
+			assert.Equal(t, pointsRange, test.pointsRange)
+		})
+	}
+}
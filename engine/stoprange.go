@@ -0,0 +1,148 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/dnldd/entry/shared"
+)
+
+// tickTolerance is the tolerance for floating point noise when rounding distances to ticks.
+const tickTolerance = 1e-9
+
+// StopRangeMode represents how stops outside of the stop range are handled.
+type StopRangeMode int
+
+const (
+	// RejectOutOfRange rejects entries with stops outside of the stop range.
+	RejectOutOfRange StopRangeMode = iota
+	// ClampOutOfRange moves stops outside of the stop range to the nearest bound.
+	ClampOutOfRange
+)
+
+// String stringifies the provided stop range mode.
+func (m StopRangeMode) String() string {
+	switch m {
+	case RejectOutOfRange:
+		return "reject"
+	case ClampOutOfRange:
+		return "clamp"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseStopRangeMode parses the stop range mode from the provided string. An empty string
+// defaults to RejectOutOfRange.
+func ParseStopRangeMode(mode string) (StopRangeMode, error) {
+	switch mode {
+	case "", "reject":
+		return RejectOutOfRange, nil
+	case "clamp":
+		return ClampOutOfRange, nil
+	default:
+		return 0, fmt.Errorf("unknown stop range mode provided: %s", mode)
+	}
+}
+
+// StopRange represents the bounds of the points range between the entry price and the
+// stop loss of entries.
+type StopRange struct {
+	// MinPointsRange is the minimum points range of a stop loss. The minimum is not enforced
+	// if zero.
+	MinPointsRange float64
+	// MaxPointsRange is the maximum points range of a stop loss. The points range limit is
+	// used if zero.
+	MaxPointsRange float64
+	// Mode is how stops outside of the range are handled.
+	Mode StopRangeMode
+}
+
+// Validate asserts the stop range sane inputs.
+func (r *StopRange) Validate() error {
+	var errs error
+
+	if r.MinPointsRange < 0 {
+		errs = errors.Join(errs, fmt.Errorf("minimum points range cannot be negative"))
+	}
+	if r.MaxPointsRange < 0 {
+		errs = errors.Join(errs, fmt.Errorf("maximum points range cannot be negative"))
+	}
+	if r.MinPointsRange > r.maxPointsRange() {
+		errs = errors.Join(errs, fmt.Errorf("minimum points range cannot exceed the maximum"))
+	}
+	if r.Mode != RejectOutOfRange && r.Mode != ClampOutOfRange {
+		errs = errors.Join(errs, fmt.Errorf("unknown stop range mode provided: %d", r.Mode))
+	}
+
+	return errs
+}
+
+// maxPointsRange returns the maximum points range, falling back on the points range limit.
+func (r *StopRange) maxPointsRange() float64 {
+	if r.MaxPointsRange == 0 {
+		return shared.PointsRangeLimit
+	}
+
+	return r.MaxPointsRange
+}
+
+// boundStopLoss enforces the stop range on the provided stop loss of an entry in the provided
+// direction. It returns the bounded stop loss and points range, and false if the entry is
+// rejected.
+func (e *Engine) boundStopLoss(reaction *shared.ReactionAtFocus, direction shared.Direction, stopLoss float64, pointsRange float64) (float64, float64, bool) {
+	bounds := e.cfg.StopRange
+	if bounds == nil {
+		return stopLoss, pointsRange, true
+	}
+
+	var distance float64
+	instrument := e.cfg.Instruments[reaction.Market]
+	switch {
+	case pointsRange > bounds.maxPointsRange():
+		if bounds.Mode == RejectOutOfRange {
+			e.reactionLogger(reaction.CorrelationID).Info().Msgf("rejecting %s entry for %s, points range (%.2f) above maximum (%.2f)",
+				direction.String(), reaction.Market, pointsRange, bounds.maxPointsRange())
+			return 0, 0, false
+		}
+
+		// Pull the stop in to the largest tick multiple within the maximum.
+		distance = bounds.maxPointsRange()
+		if instrument.TickSize > 0 {
+			distance = math.Floor(distance/instrument.TickSize+tickTolerance) * instrument.TickSize
+		}
+	case pointsRange < bounds.MinPointsRange:
+		if bounds.Mode == RejectOutOfRange {
+			e.reactionLogger(reaction.CorrelationID).Info().Msgf("rejecting %s entry for %s, points range (%.2f) below minimum (%.2f)",
+				direction.String(), reaction.Market, pointsRange, bounds.MinPointsRange)
+			return 0, 0, false
+		}
+
+		// Push the stop out to the smallest tick multiple beyond the minimum.
+		distance = bounds.MinPointsRange
+		if instrument.TickSize > 0 {
+			distance = math.Ceil(distance/instrument.TickSize-tickTolerance) * instrument.TickSize
+		}
+	default:
+		return stopLoss, pointsRange, true
+	}
+
+	switch direction {
+	case shared.Long:
+		stopLoss = reaction.CurrentPrice - distance
+	case shared.Short:
+		stopLoss = reaction.CurrentPrice + distance
+	}
+
+	if stopLoss <= 0 {
+		e.reactionLogger(reaction.CorrelationID).Info().Msgf("rejecting %s entry for %s, clamped stop loss (%.2f) not above zero",
+			direction.String(), reaction.Market, stopLoss)
+		return 0, 0, false
+	}
+
+	e.reactionLogger(reaction.CorrelationID).Info().Msgf("clamping %s entry stop loss for %s from a points range of %.2f to %.2f",
+		direction.String(), reaction.Market, pointsRange, distance)
+
+	return stopLoss, distance, true
+}
@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestParseStopRangeMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		want    StopRangeMode
+		wantErr bool
+	}{
+		{"empty defaults to reject", "", RejectOutOfRange, false},
+		{"reject", "reject", RejectOutOfRange, false},
+		{"clamp", "clamp", ClampOutOfRange, false},
+		{"unknown", "widen", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mode, err := ParseStopRangeMode(test.mode)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, mode, test.want)
+		})
+	}
+}
+
+func TestStopRangeValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		stopRange StopRange
+		wantErr   bool
+	}{
+		{"valid range", StopRange{MinPointsRange: 1, MaxPointsRange: 8, Mode: ClampOutOfRange}, false},
+		{"default maximum", StopRange{MinPointsRange: 1}, false},
+		{"negative minimum", StopRange{MinPointsRange: -1}, true},
+		{"negative maximum", StopRange{MaxPointsRange: -1}, true},
+		{"minimum above maximum", StopRange{MinPointsRange: 6, MaxPointsRange: 4}, true},
+		{"minimum above points range limit", StopRange{MinPointsRange: 20}, true},
+		{"unknown mode", StopRange{Mode: StopRangeMode(99)}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.stopRange.Validate()
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestBoundStopLoss(t *testing.T) {
+	avgVolume := float64(10)
+	candleMeta := []*shared.CandleMetadata{}
+	marketSkew := shared.NeutralSkew
+	eng, _, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+
+	reaction := &shared.ReactionAtFocus{Market: "^GSPC", CurrentPrice: 100}
+
+	tests := []struct {
+		name            string
+		stopRange       *StopRange
+		instruments     map[string]shared.Instrument
+		direction       shared.Direction
+		stopLoss        float64
+		wantOk          bool
+		wantStopLoss    float64
+		wantPointsRange float64
+	}{
+		{"unbounded", nil, nil, shared.Long, 80, true, 80, 20},
+		{"within range", &StopRange{MinPointsRange: 1}, nil, shared.Long, 95, true, 95, 5},
+		{"over limit rejected", &StopRange{}, nil, shared.Long, 80, false, 0, 0},
+		{"over limit clamped long", &StopRange{Mode: ClampOutOfRange}, nil, shared.Long, 80, true, 88, 12},
+		{"over limit clamped short", &StopRange{MaxPointsRange: 8, Mode: ClampOutOfRange}, nil, shared.Short, 115, true, 108, 8},
+		{"over limit clamped to tick", &StopRange{MaxPointsRange: 7.3, Mode: ClampOutOfRange},
+			map[string]shared.Instrument{"^GSPC": {TickSize: 0.5}}, shared.Long, 90, true, 93, 7},
+		{"under minimum rejected", &StopRange{MinPointsRange: 2}, nil, shared.Short, 100.5, false, 0, 0},
+		{"under minimum clamped", &StopRange{MinPointsRange: 2, Mode: ClampOutOfRange}, nil, shared.Short, 100.5, true, 102, 2},
+		{"under minimum clamped to tick", &StopRange{MinPointsRange: 1.1, Mode: ClampOutOfRange},
+			map[string]shared.Instrument{"^GSPC": {TickSize: 0.25}}, shared.Long, 99.5, true, 98.75, 1.25},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eng.cfg.StopRange = test.stopRange
+			eng.cfg.Instruments = test.instruments
+
+			// Ensure stops outside of the stop range are rejected or clamped per the mode.
+			pointsRange := reaction.CurrentPrice - test.stopLoss
+			if pointsRange < 0 {
+				pointsRange = -pointsRange
+			}
+			stopLoss, pointsRange, ok := eng.boundStopLoss(reaction, test.direction, test.stopLoss, pointsRange)
+			assert.Equal(t, ok, test.wantOk)
+			assert.Equal(t, stopLoss, test.wantStopLoss)
+			assert.Equal(t, pointsRange, test.wantPointsRange)
+		})
+	}
+}
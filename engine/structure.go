@@ -0,0 +1,88 @@
+package engine
+
+import (
+	"context"
+	"github.com/dnldd/entry/shared"
+)
+
+// trendDirection returns the direction of the provided trend, it is false for a chop trend.
+func trendDirection(trend shared.Trend) (shared.Direction, bool) {
+	switch trend {
+	case shared.MildBullishTrend, shared.StrongBullishTrend:
+		return shared.Long, true
+	case shared.MildBearishTrend, shared.StrongBearishTrend:
+		return shared.Short, true
+	default:
+		return 0, false
+	}
+}
+
+// prevailingDirection returns the prevailing direction of the provided market. The structure
+// of the market takes precedence over the higher timeframe trend.
+func (e *Engine) prevailingDirection(ctx context.Context, market string) (shared.Direction, bool) {
+	e.structureMtx.Lock()
+	direction, ok := e.structure[market]
+	e.structureMtx.Unlock()
+	if ok {
+		return direction, true
+	}
+
+	if e.cfg.RequestTrend == nil {
+		return 0, false
+	}
+
+	trend, err := e.fetchTrend(ctx, market)
+	if err != nil {
+		e.cfg.Logger.Error().Msgf("fetching %s trend for structure: %v", market, err)
+		return 0, false
+	}
+
+	return trendDirection(trend)
+}
+
+// recordStructureBreak records a confirmed level break in the provided direction for the
+// provided market. A break against the prevailing direction changes the structure of the market.
+func (e *Engine) recordStructureBreak(ctx context.Context, market string, direction shared.Direction) {
+	if !e.cfg.StructureSkew {
+		return
+	}
+
+	prevailing, ok := e.prevailingDirection(ctx, market)
+	if ok && prevailing == direction {
+		return
+	}
+
+	e.structureMtx.Lock()
+	e.structure[market] = direction
+	e.structureMtx.Unlock()
+
+	e.cfg.Logger.Info().Msgf("%s structure changed to %s on a confirmed level break", market,
+		direction.String())
+}
+
+// structuralSkew returns the skew implied by the structure of the provided market.
+func (e *Engine) structuralSkew(market string) shared.MarketSkew {
+	e.structureMtx.Lock()
+	defer e.structureMtx.Unlock()
+
+	direction, ok := e.structure[market]
+	if !ok {
+		return shared.NeutralSkew
+	}
+
+	switch direction {
+	case shared.Long:
+		return shared.LongSkewed
+	case shared.Short:
+		return shared.ShortSkewed
+	default:
+		return shared.NeutralSkew
+	}
+}
+
+// clearStructure clears the structure tracked for the provided market.
+func (e *Engine) clearStructure(market string) {
+	e.structureMtx.Lock()
+	delete(e.structure, market)
+	e.structureMtx.Unlock()
+}
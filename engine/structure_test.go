@@ -0,0 +1,146 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestTrendDirection(t *testing.T) {
+	tests := []struct {
+		name      string
+		trend     shared.Trend
+		direction shared.Direction
+		ok        bool
+	}{
+		{"strong bullish", shared.StrongBullishTrend, shared.Long, true},
+		{"mild bullish", shared.MildBullishTrend, shared.Long, true},
+		{"strong bearish", shared.StrongBearishTrend, shared.Short, true},
+		{"mild bearish", shared.MildBearishTrend, shared.Short, true},
+		{"choppy", shared.ChoppyTrend, 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			direction, ok := trendDirection(test.trend)
+			assert.Equal(t, ok, test.ok)
+			assert.Equal(t, direction, test.direction)
+		})
+	}
+}
+
+func TestStructureSkew(t *testing.T) {
+	avgVolume := float64(4)
+	asiaSessionTime, _ := generateSessionTimes(t)
+	market := "^GSPC"
+	supportBreakCandleMeta := []*shared.CandleMetadata{
+		{Kind: shared.Doji, Sentiment: shared.Bearish, Momentum: shared.Low, Volume: 1, High: 11, Low: 9, Date: asiaSessionTime},
+		{Kind: shared.ShootingStar, Sentiment: shared.Bearish, Momentum: shared.Medium, Volume: 4, High: 9, Low: 7, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bearish, Momentum: shared.Medium, Volume: 5, High: 7, Low: 5, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bearish, Momentum: shared.High, Volume: 8, High: 6, Low: 1, Date: asiaSessionTime},
+	}
+	resistanceBreakCandleMeta := []*shared.CandleMetadata{
+		{Kind: shared.Doji, Sentiment: shared.Bullish, Momentum: shared.Low, Volume: 1, High: 8, Low: 4, Date: asiaSessionTime},
+		{Kind: shared.Hammer, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 6, High: 12, Low: 8, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 6, High: 15, Low: 8, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.High, Volume: 8, High: 18, Low: 15, Date: asiaSessionTime},
+	}
+	supportReversalCandleMeta := []*shared.CandleMetadata{
+		{Kind: shared.Doji, Sentiment: shared.Bearish, Momentum: shared.Low, Volume: 1, High: 5, Low: 4, Date: asiaSessionTime},
+		{Kind: shared.Hammer, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 4, High: 6, Low: 4, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 5, High: 9, Low: 6, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.High, Volume: 8, High: 14, Low: 9, Date: asiaSessionTime},
+	}
+	supportBreak := &shared.ReactionAtFocus{
+		Market:        market,
+		LevelKind:     shared.Support,
+		CurrentPrice:  float64(1),
+		Timeframe:     shared.FiveMinute,
+		PriceMovement: []shared.PriceMovement{shared.Below, shared.Below, shared.Below, shared.Below},
+		Reaction:      shared.Break,
+		CreatedOn:     asiaSessionTime,
+	}
+	resistanceBreak := &shared.ReactionAtFocus{
+		Market:        market,
+		LevelKind:     shared.Resistance,
+		CurrentPrice:  float64(18),
+		Timeframe:     shared.FiveMinute,
+		PriceMovement: []shared.PriceMovement{shared.Above, shared.Above, shared.Above, shared.Above},
+		Reaction:      shared.Break,
+		CreatedOn:     asiaSessionTime,
+	}
+	supportReversal := &shared.ReactionAtFocus{
+		Market:        market,
+		LevelKind:     shared.Support,
+		CurrentPrice:  float64(14),
+		Timeframe:     shared.FiveMinute,
+		PriceMovement: []shared.PriceMovement{shared.Above, shared.Above, shared.Above, shared.Above},
+		Reaction:      shared.Reversal,
+		CreatedOn:     asiaSessionTime,
+	}
+
+	marketSkew := shared.NeutralSkew
+	trend := shared.StrongBullishTrend
+	requestTrend := func(req shared.TrendRequest) {
+		req.Response <- trend
+	}
+
+	// Ensure a break against the trend does not set the structure when disabled.
+	eng, entrySignals, _ := setupEngine(&avgVolume, supportBreakCandleMeta, &marketSkew)
+	eng.cfg.RequestTrend = requestTrend
+	err := eng.evaluateBreakStrength(context.Background(), supportBreak, nil, supportBreakCandleMeta, minLevelBreakConfluence, minLevelBreakConfluence)
+	assert.NoError(t, err)
+	entrySignal := <-entrySignals
+	assert.Equal(t, entrySignal.Direction, shared.Short)
+	assert.Equal(t, eng.structuralSkew(market), shared.NeutralSkew)
+
+	eng, entrySignals, _ = setupEngine(&avgVolume, supportBreakCandleMeta, &marketSkew)
+	eng.cfg.RequestTrend = requestTrend
+	eng.cfg.StructureSkew = true
+
+	// Ensure a break aligned with the trend leaves the structure unset.
+	err = eng.evaluateBreakStrength(context.Background(), resistanceBreak, nil, resistanceBreakCandleMeta, minLevelBreakConfluence, minLevelBreakConfluence)
+	assert.NoError(t, err)
+	entrySignal = <-entrySignals
+	assert.Equal(t, entrySignal.Direction, shared.Long)
+	assert.Equal(t, eng.structuralSkew(market), shared.NeutralSkew)
+
+	// Ensure a support break against a bullish trend flips the structure short.
+	err = eng.evaluateBreakStrength(context.Background(), supportBreak, nil, supportBreakCandleMeta, minLevelBreakConfluence, minLevelBreakConfluence)
+	assert.NoError(t, err)
+	entrySignal = <-entrySignals
+	assert.Equal(t, entrySignal.Direction, shared.Short)
+	assert.Equal(t, eng.structuralSkew(market), shared.ShortSkewed)
+
+	// Ensure neutral skew entries against the structure are skipped.
+	err = eng.evaluatePriceReversalStrength(context.Background(), supportReversal, nil, supportReversalCandleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
+	assert.NoError(t, err)
+	assert.Equal(t, len(entrySignals), 0)
+
+	// Ensure the structure takes precedence over the trend.
+	err = eng.evaluateBreakStrength(context.Background(), supportBreak, nil, supportBreakCandleMeta, minLevelBreakConfluence, minLevelBreakConfluence)
+	assert.NoError(t, err)
+	entrySignal = <-entrySignals
+	assert.Equal(t, entrySignal.Direction, shared.Short)
+	assert.Equal(t, eng.structuralSkew(market), shared.ShortSkewed)
+
+	// Ensure a resistance break against the short structure flips it long.
+	err = eng.evaluateBreakStrength(context.Background(), resistanceBreak, nil, resistanceBreakCandleMeta, minLevelBreakConfluence, minLevelBreakConfluence)
+	assert.NoError(t, err)
+	entrySignal = <-entrySignals
+	assert.Equal(t, entrySignal.Direction, shared.Long)
+	assert.Equal(t, eng.structuralSkew(market), shared.LongSkewed)
+
+	// Ensure neutral skew entries aligned with the structure are taken.
+	err = eng.evaluatePriceReversalStrength(context.Background(), supportReversal, nil, supportReversalCandleMeta, minLevelReversalConfluence, minLevelReversalConfluence)
+	assert.NoError(t, err)
+	entrySignal = <-entrySignals
+	assert.Equal(t, entrySignal.Direction, shared.Long)
+
+	// Ensure the structure is cleared when the market is removed.
+	err = eng.RemoveMarket(market)
+	assert.NoError(t, err)
+	assert.Equal(t, eng.structuralSkew(market), shared.NeutralSkew)
+}
@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+)
+
+// TrendBiasMode represents how reversals are biased by the trend defined by the moving
+// average of their timeframe.
+type TrendBiasMode int
+
+const (
+	// NoTrendBias does not bias reversals by the moving average trend.
+	NoTrendBias TrendBiasMode = iota
+	// PenalizeCounterTrend awards confluence to reversals aligned with the moving average
+	// trend and deducts it from counter-trend reversals.
+	PenalizeCounterTrend
+	// BlockCounterTrend awards confluence to reversals aligned with the moving average trend
+	// and rejects counter-trend reversals.
+	BlockCounterTrend
+)
+
+// String stringifies the provided trend bias mode.
+func (m TrendBiasMode) String() string {
+	switch m {
+	case NoTrendBias:
+		return "none"
+	case PenalizeCounterTrend:
+		return "penalize"
+	case BlockCounterTrend:
+		return "block"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseTrendBiasMode parses the trend bias mode from the provided string. An empty string
+// defaults to NoTrendBias.
+func ParseTrendBiasMode(mode string) (TrendBiasMode, error) {
+	switch mode {
+	case "", "none":
+		return NoTrendBias, nil
+	case "penalize":
+		return PenalizeCounterTrend, nil
+	case "block":
+		return BlockCounterTrend, nil
+	default:
+		return 0, fmt.Errorf("unknown trend bias mode provided: %s", mode)
+	}
+}
+
+// fetchMovingAverage fetches the current moving average of the provided market timeframe.
+func (e *Engine) fetchMovingAverage(ctx context.Context, market string, timeframe shared.Timeframe) (*shared.MovingAverage, error) {
+	if e.cfg.RequestMovingAverage == nil {
+		return nil, fmt.Errorf("no moving average request function configured")
+	}
+
+	req := shared.NewMovingAverageRequest(market, timeframe)
+	e.cfg.RequestMovingAverage(*req)
+
+	select {
+	case movingAverage := <-req.Response:
+		return movingAverage, nil
+	case <-time.After(e.cfg.Timeouts.PriceDataTimeout()):
+		return nil, fmt.Errorf("timed out fetching moving average for %s", market)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// evaluateTrendBias awards confluence points to reversals aligned with the trend defined by
+// the provided moving average, counter-trend reversals are penalized or blocked as dictated by
+// the trend bias mode. It returns whether the reversal is blocked.
+//
+// Reversals are not biased without a moving average or with price at it.
+func (e *Engine) evaluateTrendBias(reaction *shared.ReactionAtFocus, movingAverage *shared.MovingAverage, reactionSentiment shared.Sentiment, confluence *uint32, reasons map[shared.Reason]uint32) bool {
+	if e.cfg.TrendBias == NoTrendBias || movingAverage == nil {
+		return false
+	}
+
+	bias := movingAverage.Bias(reaction.CurrentPrice)
+	switch bias {
+	case shared.Neutral:
+		return false
+	case reactionSentiment:
+		// Reversals in the direction of the trend are the most likely to follow through.
+		*confluence++
+		reasons[shared.TrendAligned]++
+		return false
+	}
+
+	if e.cfg.TrendBias == BlockCounterTrend {
+		e.reactionLogger(reaction.CorrelationID).Info().Msgf("blocking %s reversal for %s against the %s moving average trend",
+			reactionSentiment.String(), reaction.Market, bias.String())
+		return true
+	}
+
+	if *confluence > 0 {
+		*confluence--
+	}
+
+	return false
+}
@@ -0,0 +1,139 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestParseTrendBiasMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		want    TrendBiasMode
+		wantErr bool
+	}{
+		{"empty defaults to none", "", NoTrendBias, false},
+		{"none", "none", NoTrendBias, false},
+		{"penalize", "penalize", PenalizeCounterTrend, false},
+		{"block", "block", BlockCounterTrend, false},
+		{"unknown", "ignore", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mode, err := ParseTrendBiasMode(test.mode)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, mode, test.want)
+		})
+	}
+}
+
+func TestTrendBias(t *testing.T) {
+	avgVolume := float64(4)
+	asiaSessionTime, _ := generateSessionTimes(t)
+	candleMeta := []*shared.CandleMetadata{
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 5, High: 9, Low: 6, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.High, Volume: 8, High: 14, Low: 9, Date: asiaSessionTime},
+	}
+	marketSkew := shared.NeutralSkew
+
+	reaction := shared.ReactionAtFocus{
+		Market:        "^GSPC",
+		Timeframe:     shared.FiveMinute,
+		LevelKind:     shared.Support,
+		CurrentPrice:  14,
+		PriceMovement: []shared.PriceMovement{shared.Above, shared.Above, shared.Above, shared.Above},
+		Reaction:      shared.Reversal,
+		CreatedOn:     asiaSessionTime,
+	}
+
+	// Score the reversal without trend bias as the baseline.
+	eng, _, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+	baseSignal, baseConfluence, _, err := eng.evaluatePriceReversal(context.Background(), &reaction, candleMeta, minLevelReversalConfluence, nil)
+	assert.NoError(t, err)
+	assert.True(t, baseSignal)
+	assert.Equal(t, baseConfluence, minLevelReversalConfluence)
+
+	tests := []struct {
+		name           string
+		mode           TrendBiasMode
+		movingAverage  *shared.MovingAverage
+		wantSignal     bool
+		wantConfluence uint32
+		wantAligned    bool
+	}{
+		{
+			name:           "no moving average",
+			mode:           BlockCounterTrend,
+			movingAverage:  nil,
+			wantSignal:     true,
+			wantConfluence: baseConfluence,
+		},
+		{
+			name:           "price at the moving average",
+			mode:           BlockCounterTrend,
+			movingAverage:  &shared.MovingAverage{Value: 14},
+			wantSignal:     true,
+			wantConfluence: baseConfluence,
+		},
+		{
+			name:           "aligned reversal",
+			mode:           PenalizeCounterTrend,
+			movingAverage:  &shared.MovingAverage{Value: 10},
+			wantSignal:     true,
+			wantConfluence: baseConfluence + 1,
+			wantAligned:    true,
+		},
+		{
+			name:           "penalized counter-trend reversal",
+			mode:           PenalizeCounterTrend,
+			movingAverage:  &shared.MovingAverage{Value: 20},
+			wantSignal:     false,
+			wantConfluence: baseConfluence - 1,
+		},
+		{
+			name:           "blocked counter-trend reversal",
+			mode:           BlockCounterTrend,
+			movingAverage:  &shared.MovingAverage{Value: 20},
+			wantSignal:     false,
+			wantConfluence: baseConfluence,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eng, _, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+			eng.cfg.TrendBias = test.mode
+			eng.cfg.RequestMovingAverage = func(req shared.MovingAverageRequest) {
+				assert.Equal(t, req.Timeframe, reaction.Timeframe)
+				req.Response <- test.movingAverage
+			}
+
+			signal, confluence, reasons, err := eng.evaluatePriceReversal(context.Background(), &reaction, candleMeta, minLevelReversalConfluence, nil)
+			assert.NoError(t, err)
+
+			// Ensure aligned reversals are awarded confluence and counter-trend reversals are
+			// penalized or blocked.
+			assert.Equal(t, signal, test.wantSignal)
+			assert.Equal(t, confluence, test.wantConfluence)
+			if test.wantAligned {
+				assert.In(t, shared.TrendAligned, reasons)
+			} else {
+				assert.NotIn(t, shared.TrendAligned, reasons)
+			}
+		})
+	}
+
+	// Ensure trend bias without a moving average request function errors.
+	eng.cfg.TrendBias = PenalizeCounterTrend
+	_, _, _, err = eng.evaluatePriceReversal(context.Background(), &reaction, candleMeta, minLevelReversalConfluence, nil)
+	assert.Error(t, err)
+}
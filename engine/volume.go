@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dnldd/entry/shared"
+)
+
+// VolumeNormalization represents how the volume of reaction candles is measured against
+// the volume history of a market.
+type VolumeNormalization int
+
+const (
+	// RawVolume measures volume differences as a fraction of the average volume.
+	RawVolume VolumeNormalization = iota
+	// ZScoreVolume measures volume differences in standard deviations of volume, making the
+	// average volume threshold comparable across markets with different volume profiles.
+	ZScoreVolume
+)
+
+// String stringifies the provided volume normalization.
+func (n VolumeNormalization) String() string {
+	switch n {
+	case RawVolume:
+		return "raw"
+	case ZScoreVolume:
+		return "zscore"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseVolumeNormalization parses the volume normalization from the provided string. An
+// empty string defaults to RawVolume.
+func ParseVolumeNormalization(normalization string) (VolumeNormalization, error) {
+	switch normalization {
+	case "", "raw":
+		return RawVolume, nil
+	case "zscore":
+		return ZScoreVolume, nil
+	default:
+		return 0, fmt.Errorf("unknown volume normalization provided: %s", normalization)
+	}
+}
+
+// ParseVolumeFloors parses per market volume floors from the provided market=volume entries.
+func ParseVolumeFloors(entries []string) (map[string]float64, error) {
+	floors := make(map[string]float64, len(entries))
+	for idx := range entries {
+		market, value, ok := strings.Cut(entries[idx], "=")
+		if !ok || market == "" {
+			return nil, fmt.Errorf("invalid volume floor entry provided: %s", entries[idx])
+		}
+
+		floor, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s volume floor: %v", market, err)
+		}
+		if floor < 0 {
+			return nil, fmt.Errorf("%s volume floor cannot be negative", market)
+		}
+
+		floors[market] = floor
+	}
+
+	return floors, nil
+}
+
+// tooThin checks whether the provided average volume of the reaction's market is below its
+// volume floor, logging reactions of markets too thin to trade. Markets without a volume
+// floor are never too thin.
+func (e *Engine) tooThin(reaction *shared.ReactionAtFocus, averageVolume float64) bool {
+	floor, ok := e.cfg.VolumeFloors[reaction.Market]
+	if !ok || averageVolume >= floor {
+		return false
+	}
+
+	e.reactionLogger(reaction.CorrelationID).Info().Msgf("suppressing %s %s signals @ %v, average volume %.2f is below the volume floor of %.2f",
+		reaction.Market, reaction.Reaction.String(), reaction.CreatedOn, averageVolume, floor)
+
+	return true
+}
@@ -0,0 +1,223 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestParseVolumeNormalization(t *testing.T) {
+	tests := []struct {
+		name          string
+		normalization string
+		want          VolumeNormalization
+		wantErr       bool
+	}{
+		{"empty defaults to raw", "", RawVolume, false},
+		{"raw", "raw", RawVolume, false},
+		{"zscore", "zscore", ZScoreVolume, false},
+		{"unknown", "percentile", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			normalization, err := ParseVolumeNormalization(test.normalization)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, normalization, test.want)
+			if test.normalization != "" {
+				assert.Equal(t, normalization.String(), test.normalization)
+			}
+		})
+	}
+}
+
+func TestVolumeNormalization(t *testing.T) {
+	avgVolume := float64(10)
+	candleMeta := []*shared.CandleMetadata{}
+	marketSkew := shared.NeutralSkew
+
+	// volumeHistory is the average and standard deviation of volume of a market.
+	type volumeHistory struct {
+		average   float64
+		deviation float64
+	}
+
+	// A futures contract trading steady volume and an equity trading erratic volume at a much
+	// larger scale.
+	histories := map[string]volumeHistory{
+		"ES":   {average: 100, deviation: 10},
+		"AAPL": {average: 1_000_000, deviation: 1_000_000},
+	}
+
+	tests := []struct {
+		name          string
+		market        string
+		volume        float64
+		rawConfluence uint32
+		zConfluence   uint32
+	}{
+		{
+			name:          "steady volume market modestly above average",
+			market:        "ES",
+			volume:        115,
+			rawConfluence: 1,
+			zConfluence:   2,
+		},
+		{
+			name:          "erratic volume market well above average",
+			market:        "AAPL",
+			volume:        1_400_000,
+			rawConfluence: 2,
+			zConfluence:   2,
+		},
+		{
+			name:          "erratic volume market modestly above average",
+			market:        "AAPL",
+			volume:        1_200_000,
+			rawConfluence: 1,
+			zConfluence:   1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, normalization := range []VolumeNormalization{RawVolume, ZScoreVolume} {
+				eng, _, _ := setupEngine(&avgVolume, candleMeta, &marketSkew)
+				eng.cfg.VolumeNormalization = normalization
+				eng.cfg.RequestAverageVolume = func(req shared.AverageVolumeRequest) {
+					history := histories[req.Market]
+					req.Response <- history.average
+					if req.Normalize {
+						req.Deviation <- history.deviation
+					}
+				}
+
+				average, scale, err := eng.fetchAverageVolume(context.Background(), test.market, shared.FiveMinute)
+				assert.NoError(t, err)
+				assert.Equal(t, average, histories[test.market].average)
+
+				// Ensure volume strength is scored against the normalization's volume scale.
+				confluence := uint32(0)
+				reasons := map[shared.Reason]uint32{}
+				err = eng.evaluateVolumeStrength(scale, test.volume-average, &confluence, reasons)
+				assert.NoError(t, err)
+
+				switch normalization {
+				case RawVolume:
+					assert.Equal(t, scale, average)
+					assert.Equal(t, confluence, test.rawConfluence)
+				case ZScoreVolume:
+					assert.Equal(t, scale, histories[test.market].deviation)
+					assert.Equal(t, confluence, test.zConfluence)
+				}
+			}
+		})
+	}
+}
+
+func TestParseVolumeFloors(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		want    map[string]float64
+		wantErr bool
+	}{
+		{"no entries", nil, map[string]float64{}, false},
+		{"valid entries", []string{"^GSPC=1000", "^IXIC=250.5"},
+			map[string]float64{"^GSPC": 1000, "^IXIC": 250.5}, false},
+		{"missing separator", []string{"^GSPC"}, nil, true},
+		{"missing market", []string{"=1000"}, nil, true},
+		{"invalid volume", []string{"^GSPC=lots"}, nil, true},
+		{"negative volume", []string{"^GSPC=-1"}, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			floors, err := ParseVolumeFloors(test.entries)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, floors, test.want)
+		})
+	}
+}
+
+func TestVolumeFloors(t *testing.T) {
+	asiaSessionTime, _ := generateSessionTimes(t)
+	bullishMeta := []*shared.CandleMetadata{
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.Medium, Volume: 5, High: 9, Low: 6, Date: asiaSessionTime},
+		{Kind: shared.Marubozu, Sentiment: shared.Bullish, Momentum: shared.High, Volume: 8, High: 14, Low: 9, Date: asiaSessionTime},
+	}
+
+	market := "^GSPC"
+	supportReversal := shared.ReactionAtFocus{
+		Market:        market,
+		Timeframe:     shared.FiveMinute,
+		LevelKind:     shared.Support,
+		CurrentPrice:  14,
+		PriceMovement: []shared.PriceMovement{shared.Above, shared.Above, shared.Above, shared.Above},
+		Reaction:      shared.Reversal,
+		CreatedOn:     asiaSessionTime,
+	}
+
+	tests := []struct {
+		name      string
+		floors    map[string]float64
+		avgVolume float64
+		wantEntry bool
+	}{
+		{"near zero average volume below the floor", map[string]float64{market: 1}, 0.01, false},
+		{"normalized average volume above the floor", map[string]float64{market: 1}, 4, true},
+		{"market without a volume floor", map[string]float64{"^IXIC": 1}, 0.01, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			avgVolume := test.avgVolume
+			marketSkew := shared.NeutralSkew
+			eng, entrySignals, _ := setupEngine(&avgVolume, bullishMeta, &marketSkew)
+			eng.cfg.VolumeFloors = test.floors
+
+			err := eng.evaluatePriceReversalStrength(context.Background(), &supportReversal, nil, bullishMeta,
+				minLevelReversalConfluence, minLevelReversalConfluence)
+			assert.NoError(t, err)
+
+			// Ensure reactions of markets too thin to trade are not signalled.
+			if !test.wantEntry {
+				assert.Equal(t, len(entrySignals), 0)
+				return
+			}
+
+			assert.Equal(t, len(entrySignals), 1)
+			entry := <-entrySignals
+			assert.Equal(t, entry.Direction, shared.Long)
+		})
+	}
+
+	// Ensure signals resume once the average volume of a thin market normalizes.
+	avgVolume := 0.01
+	marketSkew := shared.NeutralSkew
+	eng, entrySignals, _ := setupEngine(&avgVolume, bullishMeta, &marketSkew)
+	eng.cfg.VolumeFloors = map[string]float64{market: 1}
+
+	err := eng.evaluatePriceReversalStrength(context.Background(), &supportReversal, nil, bullishMeta,
+		minLevelReversalConfluence, minLevelReversalConfluence)
+	assert.NoError(t, err)
+	assert.Equal(t, len(entrySignals), 0)
+
+	avgVolume = 4
+	err = eng.evaluatePriceReversalStrength(context.Background(), &supportReversal, nil, bullishMeta,
+		minLevelReversalConfluence, minLevelReversalConfluence)
+	assert.NoError(t, err)
+	assert.Equal(t, len(entrySignals), 1)
+}
@@ -0,0 +1,272 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// Reaction focus kinds.
+	levelFocus     = "level"
+	vwapFocus      = "vwap"
+	imbalanceFocus = "imbalance"
+)
+
+// ExporterConfig represents the chart data exporter configuration.
+type ExporterConfig struct {
+	// Filepath is the filepath to the json export.
+	Filepath string
+	// Logger represents the application logger.
+	Logger *zerolog.Logger
+}
+
+// Validate asserts the config sane inputs.
+func (cfg *ExporterConfig) Validate() error {
+	var errs error
+
+	if cfg.Filepath == "" {
+		errs = errors.Join(errs, fmt.Errorf("export filepath cannot be an empty string"))
+	}
+	if cfg.Logger == nil {
+		errs = errors.Join(errs, fmt.Errorf("logger cannot be nil"))
+	}
+
+	return errs
+}
+
+// Level represents an exported level.
+type Level struct {
+	Price float64 `json:"price"`
+	Kind  string  `json:"kind"`
+}
+
+// Imbalance represents an exported imbalance.
+type Imbalance struct {
+	High      float64   `json:"high"`
+	Midpoint  float64   `json:"midpoint"`
+	Low       float64   `json:"low"`
+	Sentiment string    `json:"sentiment"`
+	GapRatio  float64   `json:"gapRatio"`
+	Date      time.Time `json:"date"`
+}
+
+// VWAP represents an exported vwap value.
+type VWAP struct {
+	Value float64   `json:"value"`
+	Date  time.Time `json:"date"`
+}
+
+// Reaction represents an exported price reaction at a level, vwap or imbalance.
+type Reaction struct {
+	Focus         string    `json:"focus"`
+	FocusPrice    float64   `json:"focusPrice"`
+	LevelKind     string    `json:"levelKind"`
+	Reaction      string    `json:"reaction"`
+	PriceMovement []string  `json:"priceMovement"`
+	CurrentPrice  float64   `json:"currentPrice"`
+	Date          time.Time `json:"date"`
+}
+
+// TimeframeExport represents the exported chart data of a market timeframe.
+type TimeframeExport struct {
+	Imbalances []Imbalance `json:"imbalances"`
+	VWAP       []VWAP      `json:"vwap"`
+	Reactions  []Reaction  `json:"reactions"`
+}
+
+// MarketExport represents the exported chart data of a market.
+type MarketExport struct {
+	Levels     []Level                     `json:"levels"`
+	Timeframes map[string]*TimeframeExport `json:"timeframes"`
+}
+
+// Export represents the exported chart data of all markets.
+type Export struct {
+	Markets map[string]*MarketExport `json:"markets"`
+}
+
+// Exporter collects levels, imbalances, vwaps and reactions of tracked markets for charting.
+type Exporter struct {
+	cfg     *ExporterConfig
+	export  Export
+	dataMtx sync.Mutex
+}
+
+// NewExporter initializes a new chart data exporter.
+func NewExporter(cfg *ExporterConfig) (*Exporter, error) {
+	err := cfg.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("validating exporter config: %v", err)
+	}
+
+	return &Exporter{
+		cfg:    cfg,
+		export: Export{Markets: make(map[string]*MarketExport)},
+	}, nil
+}
+
+// fetchMarket returns the export of the provided market, creating it if it does not exist.
+//
+// This assumes the data mutex is held by the caller.
+func (e *Exporter) fetchMarket(market string) *MarketExport {
+	mkt, ok := e.export.Markets[market]
+	if !ok {
+		mkt = &MarketExport{
+			Levels:     make([]Level, 0),
+			Timeframes: make(map[string]*TimeframeExport),
+		}
+		e.export.Markets[market] = mkt
+	}
+
+	return mkt
+}
+
+// fetchTimeframe returns the export of the provided market timeframe, creating it if it
+// does not exist.
+//
+// This assumes the data mutex is held by the caller.
+func (e *Exporter) fetchTimeframe(market string, timeframe shared.Timeframe) *TimeframeExport {
+	mkt := e.fetchMarket(market)
+	tf, ok := mkt.Timeframes[timeframe.String()]
+	if !ok {
+		tf = &TimeframeExport{
+			Imbalances: make([]Imbalance, 0),
+			VWAP:       make([]VWAP, 0),
+			Reactions:  make([]Reaction, 0),
+		}
+		mkt.Timeframes[timeframe.String()] = tf
+	}
+
+	return tf
+}
+
+// newReaction creates an exported reaction from the provided reaction details.
+func newReaction(focus string, focusPrice float64, reaction *shared.ReactionAtFocus) Reaction {
+	movement := make([]string, 0, len(reaction.PriceMovement))
+	for idx := range reaction.PriceMovement {
+		movement = append(movement, reaction.PriceMovement[idx].String())
+	}
+
+	return Reaction{
+		Focus:         focus,
+		FocusPrice:    focusPrice,
+		LevelKind:     reaction.LevelKind.String(),
+		Reaction:      reaction.Reaction.String(),
+		PriceMovement: movement,
+		CurrentPrice:  reaction.CurrentPrice,
+		Date:          reaction.CreatedOn,
+	}
+}
+
+// RecordLevel records the level of the provided level signal.
+func (e *Exporter) RecordLevel(signal shared.LevelSignal) {
+	level := shared.NewLevel(signal.Market, signal.Price, signal.Close)
+
+	e.dataMtx.Lock()
+	defer e.dataMtx.Unlock()
+
+	mkt := e.fetchMarket(signal.Market)
+	mkt.Levels = append(mkt.Levels, Level{
+		Price: level.Price,
+		Kind:  level.Kind.String(),
+	})
+}
+
+// RecordImbalance records the provided imbalance.
+func (e *Exporter) RecordImbalance(imbalance *shared.Imbalance) {
+	e.dataMtx.Lock()
+	defer e.dataMtx.Unlock()
+
+	tf := e.fetchTimeframe(imbalance.Market, imbalance.Timeframe)
+	tf.Imbalances = append(tf.Imbalances, Imbalance{
+		High:      imbalance.High,
+		Midpoint:  imbalance.Midpoint,
+		Low:       imbalance.Low,
+		Sentiment: imbalance.Sentiment.String(),
+		GapRatio:  imbalance.GapRatio,
+		Date:      imbalance.Date,
+	})
+}
+
+// RecordVWAP records the provided vwap of the provided market timeframe.
+func (e *Exporter) RecordVWAP(market string, timeframe shared.Timeframe, vwap *shared.VWAP) {
+	e.dataMtx.Lock()
+	defer e.dataMtx.Unlock()
+
+	tf := e.fetchTimeframe(market, timeframe)
+	tf.VWAP = append(tf.VWAP, VWAP{
+		Value: vwap.Value,
+		Date:  vwap.Date,
+	})
+}
+
+// RecordReactionAtLevel records the provided reaction at level.
+func (e *Exporter) RecordReactionAtLevel(reaction *shared.ReactionAtLevel) {
+	e.dataMtx.Lock()
+	defer e.dataMtx.Unlock()
+
+	tf := e.fetchTimeframe(reaction.Market, reaction.Timeframe)
+	tf.Reactions = append(tf.Reactions, newReaction(levelFocus, reaction.Level.Price,
+		&reaction.ReactionAtFocus))
+}
+
+// RecordReactionAtVWAP records the provided reaction at vwap.
+func (e *Exporter) RecordReactionAtVWAP(reaction *shared.ReactionAtVWAP) {
+	var price float64
+	if len(reaction.VWAPData) > 0 {
+		price = reaction.VWAPData[len(reaction.VWAPData)-1].Value
+	}
+
+	e.dataMtx.Lock()
+	defer e.dataMtx.Unlock()
+
+	tf := e.fetchTimeframe(reaction.Market, reaction.Timeframe)
+	tf.Reactions = append(tf.Reactions, newReaction(vwapFocus, price, &reaction.ReactionAtFocus))
+}
+
+// RecordReactionAtImbalance records the provided reaction at imbalance.
+func (e *Exporter) RecordReactionAtImbalance(reaction *shared.ReactionAtImbalance) {
+	e.dataMtx.Lock()
+	defer e.dataMtx.Unlock()
+
+	tf := e.fetchTimeframe(reaction.Market, reaction.Timeframe)
+	tf.Reactions = append(tf.Reactions, newReaction(imbalanceFocus, reaction.Imbalance.Midpoint,
+		&reaction.ReactionAtFocus))
+}
+
+// Marshal returns the json encoding of the recorded chart data.
+func (e *Exporter) Marshal() ([]byte, error) {
+	e.dataMtx.Lock()
+	defer e.dataMtx.Unlock()
+
+	data, err := json.MarshalIndent(e.export, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshalling export: %w", err)
+	}
+
+	return data, nil
+}
+
+// Persist writes the recorded chart data to the export file.
+func (e *Exporter) Persist() error {
+	data, err := e.Marshal()
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(e.cfg.Filepath, data, 0644)
+	if err != nil {
+		return fmt.Errorf("writing export to %s: %w", e.cfg.Filepath, err)
+	}
+
+	e.cfg.Logger.Info().Msgf("exported chart data to %s", e.cfg.Filepath)
+
+	return nil
+}
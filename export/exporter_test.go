@@ -0,0 +1,146 @@
+package export
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+	"github.com/rs/zerolog/log"
+)
+
+func TestExporterConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     ExporterConfig
+		wantErr bool
+	}{
+		{
+			name:    "valid config",
+			cfg:     ExporterConfig{Filepath: "export.json", Logger: &log.Logger},
+			wantErr: false,
+		},
+		{
+			name:    "empty filepath",
+			cfg:     ExporterConfig{Filepath: "", Logger: &log.Logger},
+			wantErr: true,
+		},
+		{
+			name:    "nil logger",
+			cfg:     ExporterConfig{Filepath: "export.json", Logger: nil},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.cfg.Validate()
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.json")
+
+	// Ensure an exporter can be created.
+	exporter, err := NewExporter(&ExporterConfig{Filepath: path, Logger: &log.Logger})
+	assert.NoError(t, err)
+
+	now, _, err := shared.NewYorkTime()
+	assert.NoError(t, err)
+	now = now.Truncate(time.Second)
+
+	market := "^GSPC"
+
+	// Ensure levels, imbalances, vwaps and reactions can be recorded.
+	exporter.RecordLevel(shared.NewLevelSignal(market, float64(10), float64(12)))
+
+	imbalance := shared.NewImbalance(market, shared.FiveMinute, float64(15), float64(12.5),
+		float64(10), shared.Bullish, float64(0.5), now)
+	exporter.RecordImbalance(imbalance)
+
+	exporter.RecordVWAP(market, shared.FiveMinute, &shared.VWAP{Value: float64(11), Date: now})
+
+	levelReaction := &shared.ReactionAtLevel{
+		ReactionAtFocus: shared.ReactionAtFocus{
+			Market:        market,
+			Timeframe:     shared.FiveMinute,
+			LevelKind:     shared.Support,
+			CurrentPrice:  float64(13),
+			Reaction:      shared.Reversal,
+			PriceMovement: []shared.PriceMovement{shared.Above, shared.Above, shared.Above, shared.Above},
+			CreatedOn:     now,
+		},
+		Level: shared.NewLevel(market, float64(10), float64(12)),
+	}
+	exporter.RecordReactionAtLevel(levelReaction)
+
+	vwapReaction := &shared.ReactionAtVWAP{
+		ReactionAtFocus: shared.ReactionAtFocus{
+			Market:    market,
+			Timeframe: shared.FiveMinute,
+			LevelKind: shared.Resistance,
+			Reaction:  shared.Break,
+			CreatedOn: now,
+		},
+		VWAPData: []*shared.VWAP{{Value: float64(10.5), Date: now}, {Value: float64(11), Date: now}},
+	}
+	exporter.RecordReactionAtVWAP(vwapReaction)
+
+	imbalanceReaction := &shared.ReactionAtImbalance{
+		ReactionAtFocus: shared.ReactionAtFocus{
+			Market:    market,
+			Timeframe: shared.OneHour,
+			LevelKind: shared.Support,
+			Reaction:  shared.Chop,
+			CreatedOn: now,
+		},
+		Imbalance: imbalance,
+	}
+	exporter.RecordReactionAtImbalance(imbalanceReaction)
+
+	// Ensure recorded data can be persisted.
+	err = exporter.Persist()
+	assert.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	var export Export
+	err = json.Unmarshal(data, &export)
+	assert.NoError(t, err)
+
+	mkt, ok := export.Markets[market]
+	assert.True(t, ok)
+	assert.Equal(t, mkt.Levels, []Level{{Price: float64(10), Kind: shared.Support.String()}})
+
+	fiveMinute, ok := mkt.Timeframes[shared.FiveMinute.String()]
+	assert.True(t, ok)
+	assert.Equal(t, len(fiveMinute.Imbalances), 1)
+	assert.Equal(t, fiveMinute.Imbalances[0].Midpoint, float64(12.5))
+	assert.Equal(t, fiveMinute.Imbalances[0].Sentiment, shared.Bullish.String())
+	assert.Equal(t, len(fiveMinute.VWAP), 1)
+	assert.Equal(t, fiveMinute.VWAP[0].Value, float64(11))
+	assert.True(t, fiveMinute.VWAP[0].Date.Equal(now))
+	assert.Equal(t, len(fiveMinute.Reactions), 2)
+	assert.Equal(t, fiveMinute.Reactions[0].Focus, levelFocus)
+	assert.Equal(t, fiveMinute.Reactions[0].FocusPrice, float64(10))
+	assert.Equal(t, fiveMinute.Reactions[0].Reaction, shared.Reversal.String())
+	assert.Equal(t, len(fiveMinute.Reactions[0].PriceMovement), 4)
+	assert.Equal(t, fiveMinute.Reactions[1].Focus, vwapFocus)
+	assert.Equal(t, fiveMinute.Reactions[1].FocusPrice, float64(11))
+
+	oneHour, ok := mkt.Timeframes[shared.OneHour.String()]
+	assert.True(t, ok)
+	assert.Equal(t, len(oneHour.Reactions), 1)
+	assert.Equal(t, oneHour.Reactions[0].Focus, imbalanceFocus)
+	assert.Equal(t, oneHour.Reactions[0].FocusPrice, float64(12.5))
+}
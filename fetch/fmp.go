@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/dnldd/entry/shared"
@@ -43,9 +44,10 @@ func (cfg *FMPConfig) Validate() error {
 
 // FMPClient represents the Financial Modeling Preparation (FMP) API client.
 type FMPClient struct {
-	cfg   *FMPConfig
-	httpc http.Client
-	buf   *bytes.Buffer
+	cfg    *FMPConfig
+	httpc  http.Client
+	buf    *bytes.Buffer
+	bufMtx sync.Mutex
 }
 
 // Ensure the FMPClient implements the MarketFetcher interface.
@@ -67,8 +69,12 @@ func NewFMPClient(cfg *FMPConfig) (*FMPClient, error) {
 	return fmpc, nil
 }
 
-// formURL creates full urls including paramters for the api.
+// formURL creates full urls including paramters for the api. Urls are formed concurrently
+// when catching up multiple timeframes.
 func (c *FMPClient) formURL(path string, params string) string {
+	c.bufMtx.Lock()
+	defer c.bufMtx.Unlock()
+
 	c.buf.WriteString(c.cfg.BaseURL)
 	c.buf.WriteString(path)
 	c.buf.WriteString("?")
@@ -10,6 +10,7 @@ import (
 	"github.com/dnldd/entry/shared"
 	"github.com/go-co-op/gocron"
 	"github.com/rs/zerolog"
+	"go.uber.org/atomic"
 )
 
 const (
@@ -21,6 +22,8 @@ const (
 	minSubscriberBuffer = 24
 	// notifyTimeout is the maximum time to wait before timing out for a market update notification.
 	notifyTimeout = time.Second * 3
+	// jobComponent is the component name used in tagging scheduled fetch jobs.
+	jobComponent = "fetch"
 )
 
 // ManagerConfig represents the configuration for the query manager.
@@ -31,8 +34,16 @@ type ManagerConfig struct {
 	ExchangeClient shared.MarketFetcher
 	// SignalCaughtUp signals a market is caught up on market data.
 	SignalCaughtUp func(signal shared.CaughtUpSignal)
+	// AssetClasses is the asset class of markets, keyed by market. It determines when the
+	// market is open, markets without an asset class are equity indices.
+	AssetClasses map[string]shared.AssetClass
+	// Sessions are the daily sessions markets are open in. The default sessions are used if
+	// empty.
+	Sessions []shared.SessionDefinition
 	// JobScheduler represents the job scheduler.
 	JobScheduler *gocron.Scheduler
+	// Clock is the source of the current time. The wall clock is used if nil.
+	Clock shared.Clock
 	// Logger represents the application logger.
 	Logger *zerolog.Logger
 }
@@ -63,14 +74,22 @@ func (cfg *ManagerConfig) Validate() error {
 // Manager represents the market query manager.
 type Manager struct {
 	cfg                 *ManagerConfig
+	drops               *shared.DropCounter
+	markets             map[string]struct{}
+	marketsMtx          sync.RWMutex
 	lastUpdatedTimes    map[string]time.Time
 	lastUpdatedTimesMtx sync.RWMutex
 	catchUpSignals      chan shared.CatchUpSignal
 	subscribers         map[string]chan shared.Candlestick
 	subscribersMtx      sync.RWMutex
 	location            *time.Location
+	clock               shared.Clock
 	workers             chan struct{}
 	timer               *time.Timer
+	connected           atomic.Bool
+	// runIterations counts the iterations of the run loop, an idle run loop blocks without
+	// advancing it.
+	runIterations atomic.Uint64
 }
 
 // NewManager initializes the fetch manager.
@@ -88,19 +107,81 @@ func NewManager(cfg *ManagerConfig) (*Manager, error) {
 	timer := time.NewTimer(notifyTimeout)
 	timer.Stop()
 
+	markets := make(map[string]struct{}, len(cfg.Markets))
+	for idx := range cfg.Markets {
+		markets[cfg.Markets[idx]] = struct{}{}
+	}
+
 	mgr := &Manager{
 		cfg:              cfg,
+		drops:            shared.NewDropCounter(),
+		markets:          markets,
 		lastUpdatedTimes: make(map[string]time.Time),
 		catchUpSignals:   make(chan shared.CatchUpSignal, bufferSize),
 		subscribers:      make(map[string]chan shared.Candlestick),
 		workers:          make(chan struct{}, maxWorkers),
 		location:         loc,
+		clock:            cfg.Clock,
 		timer:            timer,
 	}
+	if mgr.clock == nil {
+		mgr.clock = shared.WallClock{}
+	}
 
 	return mgr, nil
 }
 
+// AddMarket starts tracking the provided market. Market data for the market is fetched once it
+// is caught up.
+func (m *Manager) AddMarket(market string) error {
+	m.marketsMtx.Lock()
+	defer m.marketsMtx.Unlock()
+
+	_, ok := m.markets[market]
+	if ok {
+		return fmt.Errorf("market %s is already tracked", market)
+	}
+
+	m.markets[market] = struct{}{}
+
+	return nil
+}
+
+// RemoveMarket stops tracking the provided market and removes its periodic market data jobs.
+func (m *Manager) RemoveMarket(market string) error {
+	m.marketsMtx.Lock()
+	_, ok := m.markets[market]
+	if !ok {
+		m.marketsMtx.Unlock()
+		return fmt.Errorf("no market found with name %s", market)
+	}
+
+	delete(m.markets, market)
+	m.marketsMtx.Unlock()
+
+	err := m.cfg.JobScheduler.RemoveByTag(shared.MarketJobTag(jobComponent, market))
+	if err != nil && !errors.Is(err, gocron.ErrJobNotFoundWithTag) {
+		return fmt.Errorf("removing %s market data jobs: %w", market, err)
+	}
+
+	m.lastUpdatedTimesMtx.Lock()
+	for _, timeframe := range []shared.Timeframe{shared.OneMinute, shared.FiveMinute, shared.OneHour} {
+		delete(m.lastUpdatedTimes, shared.MarketDataKey(market, timeframe.String()))
+	}
+	m.lastUpdatedTimesMtx.Unlock()
+
+	return nil
+}
+
+// isTracked returns whether the provided market is tracked.
+func (m *Manager) isTracked(market string) bool {
+	m.marketsMtx.RLock()
+	defer m.marketsMtx.RUnlock()
+
+	_, ok := m.markets[market]
+	return ok
+}
+
 // Subscriber registers the provided subscriber for market updates.
 func (m *Manager) Subscribe(name string, sub chan shared.Candlestick) {
 	m.subscribersMtx.Lock()
@@ -136,30 +217,48 @@ func (m *Manager) NotifySubscribers(candle shared.Candlestick) error {
 	return nil
 }
 
+// Dropped returns the number of signals dropped by the manager's channels at capacity,
+// keyed by channel.
+func (m *Manager) Dropped() map[string]uint64 {
+	return m.drops.Counts()
+}
+
 // SendCatchUpSignal relays the provided market catch up signal for processing.
 func (m *Manager) SendCatchUpSignal(catchUp shared.CatchUpSignal) {
 	select {
 	case m.catchUpSignals <- catchUp:
 		// do nothing.
 	default:
-		m.cfg.Logger.Error().Msgf("catchup signal channel at capacity: %d/%d",
-			len(m.catchUpSignals), bufferSize)
+		m.drops.Drop("catchup signal")
 	}
 }
 
+// Connected returns whether the exchange client is connected, it is connected once market
+// data is fetched and disconnected while fetches fail.
+func (m *Manager) Connected() bool {
+	return m.connected.Load()
+}
+
 // fetchMarketData fetches market data using the provided parameters.
 func (m *Manager) fetchMarketData(market string, timeframe shared.Timeframe, start time.Time) error {
 	data, err := m.cfg.ExchangeClient.FetchIndexIntradayHistorical(context.Background(), market,
 		timeframe, start, time.Time{})
 	if err != nil {
+		m.connected.Store(false)
 		return fmt.Errorf("fetching market data %s: %v", market, err)
 	}
+	m.connected.Store(true)
 
 	candles, err := shared.ParseCandlesticks(data, market, timeframe, m.location)
 	if err != nil {
 		return fmt.Errorf("parsing candlesticks for %s: %v", market, err)
 	}
 
+	if len(candles) == 0 {
+		// Do nothing.
+		return nil
+	}
+
 	for idx := range candles {
 		m.NotifySubscribers(candles[idx])
 	}
@@ -176,6 +275,10 @@ func (m *Manager) fetchMarketData(market string, timeframe shared.Timeframe, sta
 //
 // This job should be scheduled for periodic execution.
 func (m *Manager) fetchMarketDataJob(marketName string, timeframe shared.Timeframe) error {
+	if !m.isTracked(marketName) {
+		return fmt.Errorf("%s is no longer tracked, skipping update", marketName)
+	}
+
 	key := shared.MarketDataKey(marketName, timeframe.String())
 
 	m.lastUpdatedTimesMtx.Lock()
@@ -190,12 +293,12 @@ func (m *Manager) fetchMarketDataJob(marketName string, timeframe shared.Timefra
 	}
 
 	// Avoid fetching periodic market data if the market is not open.
-	now, _, err := shared.NewYorkTime()
+	now, _, err := shared.NewYorkTimeFrom(m.clock)
 	if err != nil {
 		return fmt.Errorf("creating new york time: %v", err)
 	}
 
-	open, _, err := shared.IsMarketOpen(now)
+	open, _, err := m.cfg.AssetClasses[marketName].IsMarketOpen(now, m.cfg.Sessions)
 	if err != nil {
 		return fmt.Errorf("checking market open status: %v", err)
 	}
@@ -217,16 +320,7 @@ func (m *Manager) handleCatchUpSignal(signal shared.CatchUpSignal) error {
 		signal.Status <- shared.Processed
 	}()
 
-	match := false
-	for idx := range m.cfg.Markets {
-		market := m.cfg.Markets[idx]
-		if market == signal.Market {
-			match = true
-			break
-		}
-	}
-
-	if !match {
+	if !m.isTracked(signal.Market) {
 		return fmt.Errorf("unexpected market %s provided for catch up signal", signal.Market)
 	}
 
@@ -246,7 +340,7 @@ func (m *Manager) handleCatchUpSignal(signal shared.CatchUpSignal) error {
 	m.cfg.SignalCaughtUp(sig)
 
 	// Periodically fetch market updates once caught up.
-	now, _, err := shared.NewYorkTime()
+	now, _, err := shared.NewYorkTimeFrom(m.clock)
 	if err != nil {
 		return fmt.Errorf("fetching new york time: %v", err)
 	}
@@ -271,12 +365,12 @@ func (m *Manager) handleCatchUpSignal(signal shared.CatchUpSignal) error {
 		}
 
 		_, err = m.cfg.JobScheduler.Every(jobIntervalSeconds).Seconds().StartAt(startTime).
-			Do(func() {
-				err := m.fetchMarketDataJob(signal.Market, timeframe)
-				if err != nil {
-					m.cfg.Logger.Error().Err(err).Send()
-				}
-			})
+			Tag(shared.MarketJobTag(jobComponent, signal.Market)).Do(func() {
+			err := m.fetchMarketDataJob(signal.Market, timeframe)
+			if err != nil {
+				m.cfg.Logger.Error().Err(err).Send()
+			}
+		})
 		if err != nil {
 			return fmt.Errorf("scheduling %s market update job for %s: %v", signal.Market,
 				timeframe.String(), err)
@@ -289,6 +383,8 @@ func (m *Manager) handleCatchUpSignal(signal shared.CatchUpSignal) error {
 // Run manages the lifecycle processes of the query manager.
 func (m *Manager) Run(ctx context.Context) {
 	for {
+		m.runIterations.Inc()
+
 		select {
 		case <-ctx.Done():
 			return
@@ -301,8 +397,6 @@ func (m *Manager) Run(ctx context.Context) {
 				}
 				<-m.workers
 			}(signal)
-		default:
-			// fallthrough
 		}
 	}
 }
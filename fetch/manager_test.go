@@ -2,6 +2,7 @@ package fetch
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -200,6 +201,47 @@ func TestManager(t *testing.T) {
 	<-done
 }
 
+func TestManagerRunIdle(t *testing.T) {
+	mgr := setupManager(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		mgr.Run(ctx)
+		close(done)
+	}()
+
+	// Ensure a catch up signal sent once the manager is idle is handled.
+	time.Sleep(time.Millisecond * 50)
+	catchUp := shared.CatchUpSignal{
+		Market:    "^GSPC",
+		Timeframe: []shared.Timeframe{shared.FiveMinute},
+		Start:     time.Time{},
+		Status:    make(chan shared.StatusCode, 1),
+	}
+	mgr.SendCatchUpSignal(catchUp)
+	select {
+	case <-catchUp.Status:
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for the idle manager to handle the catch up signal")
+	}
+
+	// Ensure the idle manager blocks instead of spinning.
+	iterations := mgr.runIterations.Load()
+	time.Sleep(time.Millisecond * 50)
+	assert.True(t, mgr.runIterations.Load()-iterations <= 1)
+
+	// Ensure the idle manager returns promptly once cancelled.
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the idle manager to return")
+	}
+}
+
 func TestFillManagerChannels(t *testing.T) {
 	mgr := setupManager(t)
 
@@ -217,6 +259,9 @@ func TestFillManagerChannels(t *testing.T) {
 	}
 
 	assert.Equal(t, len(mgr.catchUpSignals), bufferSize)
+
+	// Ensure signals dropped at capacity are counted.
+	assert.Equal(t, mgr.Dropped(), map[string]uint64{"catchup signal": 1})
 }
 
 func TestHandleCatchUpSignal(t *testing.T) {
@@ -245,3 +290,65 @@ func TestHandleCatchUpSignal(t *testing.T) {
 	err = mgr.handleCatchUpSignal(catchUp)
 	assert.NoError(t, err)
 }
+
+func TestManagerConnected(t *testing.T) {
+	mgr := setupManager(t)
+	market := "^GSPC"
+
+	// Ensure the manager is not connected before market data is fetched.
+	assert.False(t, mgr.Connected())
+
+	// Ensure the manager is connected once market data is fetched.
+	err := mgr.fetchMarketData(market, shared.FiveMinute, time.Time{})
+	assert.NoError(t, err)
+	assert.True(t, mgr.Connected())
+
+	// Ensure the manager is disconnected while fetches fail.
+	mgr.cfg.ExchangeClient = &FMPMock{fetchIndexIntradayHistoricalErr: errors.New("connection refused")}
+	err = mgr.fetchMarketData(market, shared.FiveMinute, time.Time{})
+	assert.Error(t, err)
+	assert.False(t, mgr.Connected())
+}
+
+func TestManagerAddRemoveMarket(t *testing.T) {
+	mgr := setupManager(t)
+
+	ixic := "^IXIC"
+
+	// Ensure a market can be added at runtime.
+	err := mgr.AddMarket(ixic)
+	assert.NoError(t, err)
+
+	// Ensure an already tracked market cannot be added.
+	err = mgr.AddMarket(ixic)
+	assert.Error(t, err)
+
+	// Ensure the added market can be caught up and scheduled for periodic updates.
+	catchUp := shared.CatchUpSignal{
+		Market:    ixic,
+		Timeframe: []shared.Timeframe{shared.FiveMinute},
+		Start:     time.Time{},
+		Status:    make(chan shared.StatusCode, 1),
+	}
+
+	err = mgr.handleCatchUpSignal(catchUp)
+	assert.NoError(t, err)
+	assert.Equal(t, mgr.cfg.JobScheduler.Len(), 1)
+
+	// Ensure a market can be removed at runtime along with its scheduled jobs.
+	err = mgr.RemoveMarket(ixic)
+	assert.NoError(t, err)
+	assert.Equal(t, mgr.cfg.JobScheduler.Len(), 0)
+
+	// Ensure an untracked market cannot be removed.
+	err = mgr.RemoveMarket(ixic)
+	assert.Error(t, err)
+
+	// Ensure a removed market can no longer be caught up or updated.
+	catchUp.Status = make(chan shared.StatusCode, 1)
+	err = mgr.handleCatchUpSignal(catchUp)
+	assert.Error(t, err)
+
+	err = mgr.fetchMarketDataJob(ixic, shared.FiveMinute)
+	assert.Error(t, err)
+}
@@ -0,0 +1,151 @@
+package indicator
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+	"go.uber.org/atomic"
+)
+
+const (
+	// DefaultMovingAveragePeriod is the default number of candles covered by a moving average.
+	DefaultMovingAveragePeriod = 50
+)
+
+// MovingAverageKind represents the formula used to average closes of a moving average.
+type MovingAverageKind int
+
+const (
+	// SimpleMovingAverage weighs all closes of the period equally.
+	SimpleMovingAverage MovingAverageKind = iota
+	// ExponentialMovingAverage weighs recent closes more heavily.
+	ExponentialMovingAverage
+)
+
+// String stringifies the provided moving average kind.
+func (k MovingAverageKind) String() string {
+	switch k {
+	case SimpleMovingAverage:
+		return "sma"
+	case ExponentialMovingAverage:
+		return "ema"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseMovingAverageKind parses the moving average kind from the provided string. An empty
+// string defaults to SimpleMovingAverage.
+func ParseMovingAverageKind(kind string) (MovingAverageKind, error) {
+	switch kind {
+	case "", "sma":
+		return SimpleMovingAverage, nil
+	case "ema":
+		return ExponentialMovingAverage, nil
+	default:
+		return 0, fmt.Errorf("unknown moving average kind provided: %s", kind)
+	}
+}
+
+// MovingAverage represents the Moving Average Indicator over the closes of a timeframe.
+//
+// The exponential moving average is seeded with the simple moving average of its first
+// period number of closes.
+type MovingAverage struct {
+	Current   atomic.Pointer[shared.MovingAverage]
+	Market    string
+	Timeframe shared.Timeframe
+	Kind      MovingAverageKind
+	Period    int
+	closes    []float64
+	count     int
+	previous  float64
+	value     float64
+	lastDate  time.Time
+	mtx       sync.Mutex
+}
+
+// NewMovingAverage initializes a moving average indicator of the provided kind for the
+// provided market and timeframe. The default period is used if the provided period is zero.
+func NewMovingAverage(market string, timeframe shared.Timeframe, kind MovingAverageKind, period int) *MovingAverage {
+	if period == 0 {
+		period = DefaultMovingAveragePeriod
+	}
+
+	return &MovingAverage{
+		Market:    market,
+		Timeframe: timeframe,
+		Kind:      kind,
+		Period:    period,
+		closes:    make([]float64, 0, period+1),
+	}
+}
+
+// mean returns the mean of the tracked closes.
+func (a *MovingAverage) mean() float64 {
+	var sum float64
+	for idx := range a.closes {
+		sum += a.closes[idx]
+	}
+
+	return sum / float64(len(a.closes))
+}
+
+// Update updates the moving average with the provided candlestick data. A candle with the
+// same date as the last candle replaces its close as an update of the forming candle,
+// candles older than the last candle are rejected.
+//
+// No moving average is returned until period number of candles have been received.
+func (a *MovingAverage) Update(candle *shared.Candlestick) (*shared.MovingAverage, error) {
+	if candle.Timeframe != a.Timeframe {
+		return nil, fmt.Errorf("expected candles with timeframe %s, got %s",
+			a.Timeframe.String(), candle.Timeframe.String())
+	}
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	replace := !a.lastDate.IsZero() && candle.Date.Equal(a.lastDate)
+	if !a.lastDate.IsZero() && candle.Date.Before(a.lastDate) {
+		return nil, fmt.Errorf("cannot update moving average with candle at %s older than "+
+			"the last candle at %s", candle.Date, a.lastDate)
+	}
+	a.lastDate = candle.Date
+
+	switch {
+	case replace:
+		// Replacing the forming candle does not change the candles covered by the period.
+		a.closes[len(a.closes)-1] = candle.Close
+	default:
+		a.count++
+		a.previous = a.value
+		a.closes = append(a.closes, candle.Close)
+
+		// Evict the oldest close once the period is exceeded.
+		if len(a.closes) > a.Period {
+			a.closes = a.closes[1:]
+		}
+	}
+
+	if a.count < a.Period {
+		return nil, nil
+	}
+
+	switch {
+	case a.Kind == ExponentialMovingAverage && a.count > a.Period:
+		alpha := 2 / float64(a.Period+1)
+		a.value = alpha*candle.Close + (1-alpha)*a.previous
+	default:
+		a.value = a.mean()
+	}
+
+	ma := &shared.MovingAverage{
+		Value: a.value,
+		Date:  candle.Date,
+	}
+	a.Current.Store(ma)
+
+	return ma, nil
+}
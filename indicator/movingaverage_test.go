@@ -0,0 +1,111 @@
+package indicator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestParseMovingAverageKind(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    string
+		want    MovingAverageKind
+		wantErr bool
+	}{
+		{"empty defaults to sma", "", SimpleMovingAverage, false},
+		{"sma", "sma", SimpleMovingAverage, false},
+		{"ema", "ema", ExponentialMovingAverage, false},
+		{"unknown", "wma", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kind, err := ParseMovingAverageKind(test.kind)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, kind, test.want)
+		})
+	}
+}
+
+func TestMovingAverage(t *testing.T) {
+	market := "^GSPC"
+	timeframe := shared.FiveMinute
+	now := time.Date(2025, 5, 7, 14, 30, 0, 0, time.UTC)
+
+	candleAt := func(idx int, close float64) *shared.Candlestick {
+		return &shared.Candlestick{
+			Close:     close,
+			Date:      now.Add(time.Minute * 5 * time.Duration(idx)),
+			Market:    market,
+			Timeframe: timeframe,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		kind    MovingAverageKind
+		candles []*shared.Candlestick
+		want    []float64
+	}{
+		{
+			name:    "sma",
+			kind:    SimpleMovingAverage,
+			candles: []*shared.Candlestick{candleAt(0, 1), candleAt(1, 2), candleAt(2, 3), candleAt(3, 4), candleAt(3, 7)},
+			want:    []float64{0, 0, 2, 3, 4},
+		},
+		{
+			name:    "ema",
+			kind:    ExponentialMovingAverage,
+			candles: []*shared.Candlestick{candleAt(0, 1), candleAt(1, 2), candleAt(2, 3), candleAt(3, 4), candleAt(3, 6), candleAt(4, 8)},
+			want:    []float64{0, 0, 2, 3, 4, 6},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ma := NewMovingAverage(market, timeframe, test.kind, 3)
+
+			for idx := range test.candles {
+				value, err := ma.Update(test.candles[idx])
+				assert.NoError(t, err)
+
+				// Ensure no moving average is returned before the period is filled.
+				if test.want[idx] == 0 {
+					assert.Nil(t, value)
+					assert.Nil(t, ma.Current.Load())
+					continue
+				}
+
+				// Ensure updates of the forming candle replace its close.
+				assert.NotNil(t, value)
+				assert.Equal(t, value.Value, test.want[idx])
+				assert.Equal(t, value.Date, test.candles[idx].Date)
+				assert.Equal(t, ma.Current.Load(), value)
+			}
+		})
+	}
+
+	// Ensure the default period is used if none is provided.
+	ma := NewMovingAverage(market, timeframe, SimpleMovingAverage, 0)
+	assert.Equal(t, ma.Period, DefaultMovingAveragePeriod)
+
+	// Ensure candles of other timeframes are rejected.
+	ignoredCandle := candleAt(0, 1)
+	ignoredCandle.Timeframe = shared.OneMinute
+	_, err := ma.Update(ignoredCandle)
+	assert.Error(t, err)
+
+	// Ensure candles older than the last candle are rejected.
+	_, err = ma.Update(candleAt(1, 1))
+	assert.NoError(t, err)
+	_, err = ma.Update(candleAt(0, 1))
+	assert.Error(t, err)
+}
@@ -2,6 +2,8 @@ package indicator
 
 import (
 	"fmt"
+	"math"
+	"sync"
 	"time"
 
 	"github.com/dnldd/entry/shared"
@@ -13,53 +15,320 @@ const (
 	VwapResetTime = "17:00:10"
 )
 
+// TypicalPrice represents the formula used to derive the typical price of a candle.
+type TypicalPrice int
+
+const (
+	// HLC3 is the average of the high, low and close of a candle.
+	HLC3 TypicalPrice = iota
+	// OHLC4 is the average of the open, high, low and close of a candle.
+	OHLC4
+	// ClosePrice is the close of a candle.
+	ClosePrice
+)
+
+// String stringifies the provided typical price formula.
+func (p TypicalPrice) String() string {
+	switch p {
+	case HLC3:
+		return "hlc3"
+	case OHLC4:
+		return "ohlc4"
+	case ClosePrice:
+		return "close"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseTypicalPrice parses the typical price formula from the provided string. An empty string
+// defaults to HLC3.
+func ParseTypicalPrice(formula string) (TypicalPrice, error) {
+	switch formula {
+	case "", "hlc3":
+		return HLC3, nil
+	case "ohlc4":
+		return OHLC4, nil
+	case "close":
+		return ClosePrice, nil
+	default:
+		return 0, fmt.Errorf("unknown typical price formula provided: %s", formula)
+	}
+}
+
+// Calculate returns the typical price of the provided candle using the formula.
+func (p TypicalPrice) Calculate(candle *shared.Candlestick) float64 {
+	switch p {
+	case OHLC4:
+		return (candle.Open + candle.High + candle.Low + candle.Close) / 4
+	case ClosePrice:
+		return candle.Close
+	default:
+		return (candle.High + candle.Low + candle.Close) / 3
+	}
+}
+
+// VWAPAnchorMode represents when a vwap that is not rolling is reset.
+type VWAPAnchorMode int
+
+const (
+	// DailyAnchor resets the vwap daily at the vwap reset time.
+	DailyAnchor VWAPAnchorMode = iota
+	// SessionAnchor resets the vwap at the open of each session.
+	SessionAnchor
+	// TimestampAnchor accumulates the vwap from a timestamp and never resets it.
+	TimestampAnchor
+)
+
+// String stringifies the provided vwap anchor mode.
+func (m VWAPAnchorMode) String() string {
+	switch m {
+	case DailyAnchor:
+		return "daily"
+	case SessionAnchor:
+		return "session"
+	case TimestampAnchor:
+		return "anchored"
+	default:
+		return "unknown"
+	}
+}
+
+// VWAPAnchor represents the anchoring of a vwap.
+type VWAPAnchor struct {
+	// Mode is when the vwap is reset.
+	Mode VWAPAnchorMode
+	// At is the time the vwap accumulates from, only used by the timestamp anchor.
+	At time.Time
+	// Sessions are the sessions the vwap is reset on, only used by the session anchor. The
+	// default sessions are used if empty.
+	Sessions []shared.SessionDefinition
+}
+
+// Validate asserts the anchor sane inputs.
+func (a *VWAPAnchor) Validate() error {
+	if a.Mode == TimestampAnchor && a.At.IsZero() {
+		return fmt.Errorf("anchored vwap requires an anchor time")
+	}
+
+	return nil
+}
+
+// ParseVWAPAnchor parses the vwap anchor from the provided string, either daily, session or
+// an RFC3339 anchor time. An empty string defaults to the daily anchor.
+func ParseVWAPAnchor(anchor string) (VWAPAnchor, error) {
+	switch anchor {
+	case "", "daily":
+		return VWAPAnchor{Mode: DailyAnchor}, nil
+	case "session":
+		return VWAPAnchor{Mode: SessionAnchor}, nil
+	default:
+		at, err := time.Parse(time.RFC3339, anchor)
+		if err != nil {
+			return VWAPAnchor{}, fmt.Errorf("unknown vwap anchor provided: %s", anchor)
+		}
+
+		return VWAPAnchor{Mode: TimestampAnchor, At: at}, nil
+	}
+}
+
+// vwapContribution represents the contribution of a candle to a rolling vwap.
+type vwapContribution struct {
+	typicalPriceVolume        float64
+	typicalPriceSquaredVolume float64
+	volume                    float64
+}
+
 // VWAP represents the Volume Weighted Average Price Indicator.
+//
+// The indicator accumulates until reset as dictated by its anchor. When a rolling window is
+// set it instead tracks the vwap over the last window number of candles and is never reset.
+//
+// The volume weighted variance of the typical price is tracked alongside the vwap to derive
+// its standard deviation bands.
 type VWAP struct {
-	TypicalPriceVolume atomic.Float64
-	Volume             atomic.Float64
-	Current            atomic.Pointer[shared.VWAP]
-	Market             string
-	Timeframe          shared.Timeframe
-	LastUpdateTime     atomic.Pointer[time.Time]
+	TypicalPriceVolume        atomic.Float64
+	TypicalPriceSquaredVolume atomic.Float64
+	Volume                    atomic.Float64
+	Current                   atomic.Pointer[shared.VWAP]
+	Market                    string
+	Timeframe                 shared.Timeframe
+	TypicalPrice              TypicalPrice
+	Window                    int
+	Anchor                    VWAPAnchor
+	LastUpdateTime            atomic.Pointer[time.Time]
+	sessionOpen               atomic.Pointer[time.Time]
+	contributions             []vwapContribution
+	last                      *vwapContribution
+	lastDate                  time.Time
+	contributionsMtx          sync.Mutex
 }
 
-// NewVWAP initializes a VWAP indicator for the provided market and timeframe.
-func NewVWAP(market string, timeframe shared.Timeframe) *VWAP {
-	return &VWAP{
-		Market:    market,
-		Timeframe: timeframe,
+// NewVWAP initializes a VWAP indicator for the provided market and timeframe using the provided
+// typical price formula. A zero window creates a vwap reset per the provided anchor, a positive
+// window creates a rolling vwap over that number of candles.
+func NewVWAP(market string, timeframe shared.Timeframe, typicalPrice TypicalPrice, window int, anchor VWAPAnchor) *VWAP {
+	vwap := &VWAP{
+		Market:       market,
+		Timeframe:    timeframe,
+		TypicalPrice: typicalPrice,
+		Window:       window,
+		Anchor:       anchor,
+	}
+
+	if window > 0 {
+		vwap.contributions = make([]vwapContribution, 0, window+1)
 	}
+
+	return vwap
+}
+
+// IsRolling returns whether the vwap is computed over a rolling window.
+func (v *VWAP) IsRolling() bool {
+	return v.Window > 0
 }
 
-// Update cummulatively updates the VWAP indicator with the provided candlestick data.
+// anchor resets the vwap when the provided candle opens a new session under the session
+// anchor. It returns false if the candle precedes the anchor time under the timestamp anchor.
+func (v *VWAP) anchor(candle *shared.Candlestick) (bool, error) {
+	if v.IsRolling() {
+		return true, nil
+	}
+
+	switch v.Anchor.Mode {
+	case SessionAnchor:
+		_, session, err := shared.CurrentSession(candle.Date, v.Anchor.Sessions)
+		if err != nil {
+			return false, fmt.Errorf("fetching current session: %v", err)
+		}
+		if session == nil {
+			// Candles between sessions accumulate into the last session.
+			return true, nil
+		}
+
+		last := v.sessionOpen.Load()
+		if last != nil && !last.Equal(session.Open) {
+			v.reset()
+		}
+		v.sessionOpen.Store(&session.Open)
+	case TimestampAnchor:
+		if candle.Date.Before(v.Anchor.At) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// sub removes the provided contribution from the vwap.
+func (v *VWAP) sub(contribution *vwapContribution) {
+	v.TypicalPriceVolume.Sub(contribution.typicalPriceVolume)
+	v.TypicalPriceSquaredVolume.Sub(contribution.typicalPriceSquaredVolume)
+	v.Volume.Sub(contribution.volume)
+}
+
+// Update cummulatively updates the VWAP indicator with the provided candlestick data. A
+// candle with the same date as the last candle replaces its contribution as an update of the
+// forming candle, candles older than the last candle are rejected.
 func (v *VWAP) Update(candle *shared.Candlestick) (*shared.VWAP, error) {
 	if candle.Timeframe != v.Timeframe {
 		return nil, fmt.Errorf("expected candles with timeframe %s, got %s",
 			v.Timeframe.String(), candle.Timeframe.String())
 	}
 
-	typicalPrice := (candle.High + candle.Low + candle.Close) / 3
-	v.TypicalPriceVolume.Add(typicalPrice * candle.Volume)
-	v.Volume.Add(candle.Volume)
+	v.contributionsMtx.Lock()
+	defer v.contributionsMtx.Unlock()
+
+	replace := !v.lastDate.IsZero() && candle.Date.Equal(v.lastDate)
+	if !v.lastDate.IsZero() && candle.Date.Before(v.lastDate) {
+		return nil, fmt.Errorf("cannot update vwap with candle at %s older than the "+
+			"last candle at %s", candle.Date, v.lastDate)
+	}
+	v.lastDate = candle.Date
+
+	anchored, err := v.anchor(candle)
+	if err != nil {
+		return nil, err
+	}
+	if !anchored {
+		return &shared.VWAP{Date: candle.Date}, nil
+	}
+
+	// Remove the contribution of the candle being replaced.
+	if replace && v.last != nil {
+		v.sub(v.last)
+		if v.IsRolling() {
+			v.contributions = v.contributions[:len(v.contributions)-1]
+		}
+	}
+
+	typicalPrice := v.TypicalPrice.Calculate(candle)
+	typicalPriceVolume := typicalPrice * candle.Volume
+	contribution := vwapContribution{
+		typicalPriceVolume:        typicalPriceVolume,
+		typicalPriceSquaredVolume: typicalPrice * typicalPriceVolume,
+		volume:                    candle.Volume,
+	}
+	v.TypicalPriceVolume.Add(contribution.typicalPriceVolume)
+	v.TypicalPriceSquaredVolume.Add(contribution.typicalPriceSquaredVolume)
+	v.Volume.Add(contribution.volume)
+	v.last = &contribution
+
+	if v.IsRolling() {
+		v.contributions = append(v.contributions, contribution)
+
+		// Evict the oldest contribution once the window is exceeded.
+		if len(v.contributions) > v.Window {
+			oldest := v.contributions[0]
+			v.contributions = v.contributions[1:]
+			v.sub(&oldest)
+		}
+	}
 
 	vwap := &shared.VWAP{
 		Date: candle.Date,
 	}
 
-	if v.TypicalPriceVolume.Load() == 0 {
+	if v.TypicalPriceVolume.Load() == 0 || v.Volume.Load() == 0 {
 		return vwap, nil
 	}
 
-	val := v.TypicalPriceVolume.Load() / v.Volume.Load()
+	volume := v.Volume.Load()
+	val := v.TypicalPriceVolume.Load() / volume
 	vwap.Value = val
+
+	// Floating point error can leave a marginally negative variance when prices are flat.
+	variance := v.TypicalPriceSquaredVolume.Load()/volume - val*val
+	if variance > 0 {
+		vwap.StdDev = math.Sqrt(variance)
+	}
 	v.Current.Store(vwap)
 	v.LastUpdateTime.Store(&candle.Date)
 
 	return vwap, nil
 }
 
+// ResetsDaily returns whether the vwap is expected to be reset daily at the vwap reset time.
+func (v *VWAP) ResetsDaily() bool {
+	return !v.IsRolling() && v.Anchor.Mode == DailyAnchor
+}
+
 // Reset resets the VWAP indicator after a trading session.
 func (v *VWAP) Reset() {
+	v.contributionsMtx.Lock()
+	defer v.contributionsMtx.Unlock()
+
+	v.reset()
+}
+
+// reset resets the VWAP indicator, the caller must hold the contributions lock.
+func (v *VWAP) reset() {
+	if v.contributions != nil {
+		v.contributions = v.contributions[:0]
+	}
+	v.last = nil
+
 	v.TypicalPriceVolume.Store(0)
+	v.TypicalPriceSquaredVolume.Store(0)
 	v.Volume.Store(0)
 }
@@ -1,7 +1,9 @@
 package indicator
 
 import (
+	"math"
 	"testing"
+	"time"
 
 	"github.com/dnldd/entry/shared"
 	"github.com/peterldowns/testy/assert"
@@ -11,7 +13,7 @@ func TestVWAPGenerator(t *testing.T) {
 	// Ensure vwap can be created.
 	market := "^GSPC"
 	timeframe := shared.FiveMinute
-	vwap := NewVWAP(market, timeframe)
+	vwap := NewVWAP(market, timeframe, HLC3, 0, VWAPAnchor{})
 
 	// Ensure vwap generator ignores update candles that are not of the expected timeframe.
 	ignoredCandle := &shared.Candlestick{
@@ -67,3 +69,393 @@ func TestVWAPGenerator(t *testing.T) {
 	assert.Equal(t, vwap.Volume.Load(), 0)
 	assert.Equal(t, vwap.TypicalPriceVolume.Load(), 0)
 }
+
+func TestTypicalPriceString(t *testing.T) {
+	tests := []struct {
+		name         string
+		typicalPrice TypicalPrice
+		want         string
+	}{
+		{"hlc3", HLC3, "hlc3"},
+		{"ohlc4", OHLC4, "ohlc4"},
+		{"close", ClosePrice, "close"},
+		{"unknown", TypicalPrice(999), "unknown"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.typicalPrice.String(), test.want)
+		})
+	}
+}
+
+func TestParseTypicalPrice(t *testing.T) {
+	tests := []struct {
+		name    string
+		formula string
+		want    TypicalPrice
+		wantErr bool
+	}{
+		{"empty defaults to hlc3", "", HLC3, false},
+		{"hlc3", "hlc3", HLC3, false},
+		{"ohlc4", "ohlc4", OHLC4, false},
+		{"close", "close", ClosePrice, false},
+		{"unknown", "hl2", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			typicalPrice, err := ParseTypicalPrice(test.formula)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, typicalPrice, test.want)
+		})
+	}
+}
+
+func TestVWAPTypicalPrice(t *testing.T) {
+	market := "^GSPC"
+	timeframe := shared.FiveMinute
+
+	candles := []*shared.Candlestick{
+		{Open: 4, High: 10, Low: 2, Close: 6, Volume: 2, Market: market, Timeframe: timeframe},
+		{Open: 6, High: 12, Low: 5, Close: 11, Volume: 3, Market: market, Timeframe: timeframe},
+		{Open: 11, High: 14, Low: 8, Close: 9, Volume: 5, Market: market, Timeframe: timeframe},
+	}
+
+	tests := []struct {
+		name         string
+		typicalPrice TypicalPrice
+		want         float64
+	}{
+		{
+			// ((6*2) + (28/3*3) + (31/3*5)) / 10
+			name:         "hlc3",
+			typicalPrice: HLC3,
+			want:         (6*2 + 28.0/3*3 + 31.0/3*5) / 10,
+		},
+		{
+			// ((5.5*2) + (8.5*3) + (10.5*5)) / 10
+			name:         "ohlc4",
+			typicalPrice: OHLC4,
+			want:         (5.5*2 + 8.5*3 + 10.5*5) / 10,
+		},
+		{
+			// ((6*2) + (11*3) + (9*5)) / 10
+			name:         "close",
+			typicalPrice: ClosePrice,
+			want:         (6*2 + 11*3 + 9*5) / 10.0,
+		},
+	}
+
+	// Ensure the vwap value reflects the configured typical price formula for the same candle series.
+	values := make(map[TypicalPrice]float64)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vwap := NewVWAP(market, timeframe, test.typicalPrice, 0, VWAPAnchor{})
+
+			var vwp *shared.VWAP
+			var err error
+			for idx := range candles {
+				vwp, err = vwap.Update(candles[idx])
+				assert.NoError(t, err)
+			}
+
+			assert.True(t, math.Abs(vwp.Value-test.want) < 1e-9)
+			values[test.typicalPrice] = vwp.Value
+		})
+	}
+
+	assert.NotEqual(t, values[HLC3], values[OHLC4])
+	assert.NotEqual(t, values[HLC3], values[ClosePrice])
+	assert.NotEqual(t, values[OHLC4], values[ClosePrice])
+}
+
+func TestVWAPRollingWindow(t *testing.T) {
+	market := "^GSPC"
+	timeframe := shared.FiveMinute
+
+	// Ensure a zero window creates a session anchored vwap and a positive window a rolling one.
+	anchored := NewVWAP(market, timeframe, ClosePrice, 0, VWAPAnchor{})
+	assert.False(t, anchored.IsRolling())
+	rolling := NewVWAP(market, timeframe, ClosePrice, 3, VWAPAnchor{})
+	assert.True(t, rolling.IsRolling())
+
+	firstSession := []*shared.Candlestick{
+		{Open: 10, High: 10, Low: 10, Close: 10, Volume: 1, Market: market, Timeframe: timeframe},
+		{Open: 20, High: 20, Low: 20, Close: 20, Volume: 1, Market: market, Timeframe: timeframe},
+		{Open: 30, High: 30, Low: 30, Close: 30, Volume: 2, Market: market, Timeframe: timeframe},
+	}
+	secondSession := []*shared.Candlestick{
+		{Open: 40, High: 40, Low: 40, Close: 40, Volume: 1, Market: market, Timeframe: timeframe},
+		{Open: 50, High: 50, Low: 50, Close: 50, Volume: 1, Market: market, Timeframe: timeframe},
+	}
+
+	// Ensure both vwaps are identical while the series fits in the rolling window.
+	var anchoredVWAP, rollingVWAP *shared.VWAP
+	var err error
+	for idx := range firstSession {
+		anchoredVWAP, err = anchored.Update(firstSession[idx])
+		assert.NoError(t, err)
+		rollingVWAP, err = rolling.Update(firstSession[idx])
+		assert.NoError(t, err)
+		assert.Equal(t, anchoredVWAP.Value, rollingVWAP.Value)
+	}
+
+	// (10 + 20 + 60) / 4
+	assert.Equal(t, anchoredVWAP.Value, float64(22.5))
+
+	// Simulate the session close, only the anchored vwap is expected to be reset by the market.
+	anchored.Reset()
+
+	// Ensure the anchored vwap only reflects the new session.
+	anchoredVWAP, err = anchored.Update(secondSession[0])
+	assert.NoError(t, err)
+	assert.Equal(t, anchoredVWAP.Value, float64(40))
+
+	// Ensure the rolling vwap spans sessions and evicts contributions outside the window.
+	// (20 + 60 + 40) / 4
+	rollingVWAP, err = rolling.Update(secondSession[0])
+	assert.NoError(t, err)
+	assert.Equal(t, rollingVWAP.Value, float64(30))
+
+	// (40 + 50) / 2
+	anchoredVWAP, err = anchored.Update(secondSession[1])
+	assert.NoError(t, err)
+	assert.Equal(t, anchoredVWAP.Value, float64(45))
+
+	// (60 + 40 + 50) / 4
+	rollingVWAP, err = rolling.Update(secondSession[1])
+	assert.NoError(t, err)
+	assert.Equal(t, rollingVWAP.Value, float64(37.5))
+	assert.Equal(t, rolling.Volume.Load(), float64(4))
+
+	// Ensure a rolling vwap can still be explicitly reset.
+	rolling.Reset()
+	assert.Equal(t, rolling.Volume.Load(), float64(0))
+	assert.Equal(t, rolling.TypicalPriceVolume.Load(), float64(0))
+	assert.Equal(t, len(rolling.contributions), 0)
+}
+
+func TestVWAPCandleUpdates(t *testing.T) {
+	market := "^GSPC"
+	timeframe := shared.FiveMinute
+	now, _ := time.Parse(time.RFC3339, "2010-09-18T14:00:00Z")
+
+	for _, window := range []int{0, 3} {
+		vwap := NewVWAP(market, timeframe, ClosePrice, window, VWAPAnchor{})
+		first := &shared.Candlestick{Close: 10, Volume: 1, Market: market, Timeframe: timeframe, Date: now}
+		second := &shared.Candlestick{Close: 20, Volume: 1, Market: market, Timeframe: timeframe,
+			Date: now.Add(time.Minute * 5)}
+
+		_, err := vwap.Update(first)
+		assert.NoError(t, err)
+		value, err := vwap.Update(second)
+		assert.NoError(t, err)
+		assert.Equal(t, value.Value, float64(15))
+
+		// Ensure a repeated candle does not double count its volume.
+		repeat := *second
+		value, err = vwap.Update(&repeat)
+		assert.NoError(t, err)
+		assert.Equal(t, value.Value, float64(15))
+		assert.Equal(t, vwap.Volume.Load(), float64(2))
+
+		// Ensure an update of the forming candle replaces its contribution.
+		update := *second
+		update.Close = 40
+		update.Volume = 3
+		value, err = vwap.Update(&update)
+		assert.NoError(t, err)
+		assert.Equal(t, value.Value, float64(32.5))
+		assert.Equal(t, vwap.Volume.Load(), float64(4))
+		if vwap.IsRolling() {
+			assert.Equal(t, len(vwap.contributions), 2)
+		}
+
+		// Ensure an out of order candle is rejected.
+		stale := *first
+		stale.Date = now.Add(-time.Minute * 5)
+		_, err = vwap.Update(&stale)
+		assert.Error(t, err)
+		assert.Equal(t, vwap.Volume.Load(), float64(4))
+	}
+}
+
+func TestVWAPBands(t *testing.T) {
+	market := "^GSPC"
+	timeframe := shared.FiveMinute
+
+	candles := []*shared.Candlestick{
+		{Open: 10, High: 10, Low: 10, Close: 10, Volume: 1, Market: market, Timeframe: timeframe},
+		{Open: 30, High: 30, Low: 30, Close: 30, Volume: 1, Market: market, Timeframe: timeframe},
+		{Open: 20, High: 20, Low: 20, Close: 20, Volume: 2, Market: market, Timeframe: timeframe},
+	}
+
+	anchored := NewVWAP(market, timeframe, ClosePrice, 0, VWAPAnchor{})
+	rolling := NewVWAP(market, timeframe, ClosePrice, 2, VWAPAnchor{})
+
+	// Ensure a single price has no deviation, the bands collapse onto the vwap.
+	vwp, err := anchored.Update(candles[0])
+	assert.NoError(t, err)
+	assert.Equal(t, vwp.Value, float64(10))
+	assert.Equal(t, vwp.StdDev, float64(0))
+	assert.Equal(t, vwp.Band(shared.UpperBand2), float64(10))
+	_, err = rolling.Update(candles[0])
+	assert.NoError(t, err)
+
+	// vwap: (10 + 30) / 2 = 20, variance: (100 + 900) / 2 - 20^2 = 100.
+	vwp, err = anchored.Update(candles[1])
+	assert.NoError(t, err)
+	assert.Equal(t, vwp.Value, float64(20))
+	assert.Equal(t, vwp.StdDev, float64(10))
+	assert.Equal(t, vwp.Band(shared.VWAPLine), float64(20))
+	assert.Equal(t, vwp.Band(shared.UpperBand1), float64(30))
+	assert.Equal(t, vwp.Band(shared.LowerBand1), float64(10))
+	assert.Equal(t, vwp.Band(shared.UpperBand2), float64(40))
+	assert.Equal(t, vwp.Band(shared.LowerBand2), float64(0))
+	_, err = rolling.Update(candles[1])
+	assert.NoError(t, err)
+
+	// vwap: (10 + 30 + 40) / 4 = 20, variance: (100 + 900 + 800) / 4 - 20^2 = 50.
+	vwp, err = anchored.Update(candles[2])
+	assert.NoError(t, err)
+	assert.Equal(t, vwp.Value, float64(20))
+	assert.True(t, math.Abs(vwp.StdDev-math.Sqrt(50)) < 1e-9)
+
+	// Ensure the rolling variance evicts contributions outside the window.
+	// vwap: (30 + 40) / 3, variance: (900 + 800) / 3 - (70/3)^2 = 200/9.
+	vwp, err = rolling.Update(candles[2])
+	assert.NoError(t, err)
+	assert.True(t, math.Abs(vwp.Value-70.0/3) < 1e-9)
+	assert.True(t, math.Abs(vwp.StdDev-math.Sqrt(200)/3) < 1e-9)
+	assert.True(t, math.Abs(vwp.Band(shared.LowerBand1)-(70-math.Sqrt(200))/3) < 1e-9)
+
+	// Ensure the variance is cleared on reset.
+	anchored.Reset()
+	assert.Equal(t, anchored.TypicalPriceSquaredVolume.Load(), float64(0))
+}
+
+func TestParseVWAPAnchor(t *testing.T) {
+	at := time.Date(2024, 1, 3, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		anchor  string
+		want    VWAPAnchor
+		wantErr bool
+	}{
+		{"empty defaults to daily", "", VWAPAnchor{Mode: DailyAnchor}, false},
+		{"daily", "daily", VWAPAnchor{Mode: DailyAnchor}, false},
+		{"session", "session", VWAPAnchor{Mode: SessionAnchor}, false},
+		{"anchored", "2024-01-03T09:30:00Z", VWAPAnchor{Mode: TimestampAnchor, At: at}, false},
+		{"unknown", "weekly", VWAPAnchor{}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			anchor, err := ParseVWAPAnchor(test.anchor)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, anchor.Mode, test.want.Mode)
+			assert.True(t, anchor.At.Equal(test.want.At))
+			assert.NoError(t, anchor.Validate())
+		})
+	}
+
+	// Ensure a timestamp anchor requires an anchor time.
+	anchor := VWAPAnchor{Mode: TimestampAnchor}
+	assert.Error(t, anchor.Validate())
+}
+
+func TestVWAPAnchor(t *testing.T) {
+	market := "^GSPC"
+	timeframe := shared.FiveMinute
+
+	loc, err := time.LoadLocation(shared.NewYorkLocation)
+	assert.NoError(t, err)
+
+	// The first two candles close in the london session, the last opens the new york session.
+	londonClose := time.Date(2024, 1, 3, 11, 0, 0, 0, loc)
+	candles := []*shared.Candlestick{
+		{Open: 10, High: 10, Low: 10, Close: 10, Volume: 1, Date: londonClose.Add(-time.Minute * 10), Market: market, Timeframe: timeframe},
+		{Open: 30, High: 30, Low: 30, Close: 30, Volume: 1, Date: londonClose.Add(-time.Minute * 5), Market: market, Timeframe: timeframe},
+		{Open: 50, High: 50, Low: 50, Close: 50, Volume: 1, Date: londonClose.Add(time.Minute * 5), Market: market, Timeframe: timeframe},
+	}
+
+	tests := []struct {
+		name   string
+		anchor VWAPAnchor
+		want   []float64
+	}{
+		{
+			// (10 + 30 + 50) / 3
+			name:   "daily",
+			anchor: VWAPAnchor{Mode: DailyAnchor},
+			want:   []float64{10, 20, 30},
+		},
+		{
+			// The new york session resets the vwap to 50.
+			name:   "session",
+			anchor: VWAPAnchor{Mode: SessionAnchor},
+			want:   []float64{10, 20, 50},
+		},
+		{
+			// A custom session spanning the london close does not reset the vwap.
+			name: "custom session",
+			anchor: VWAPAnchor{Mode: SessionAnchor, Sessions: []shared.SessionDefinition{
+				{Name: "day", Open: "08:00", Close: "17:00"},
+			}},
+			want: []float64{10, 20, 30},
+		},
+		{
+			// The custom late session resets the vwap to 30, (30 + 50) / 2
+			name: "custom session boundary",
+			anchor: VWAPAnchor{Mode: SessionAnchor, Sessions: []shared.SessionDefinition{
+				{Name: "early", Open: "08:00", Close: "10:52"},
+				{Name: "late", Open: "10:52", Close: "17:00"},
+			}},
+			want: []float64{10, 30, 40},
+		},
+		{
+			// Candles before the anchor time are excluded, (30 + 50) / 2
+			name:   "anchored",
+			anchor: VWAPAnchor{Mode: TimestampAnchor, At: candles[1].Date},
+			want:   []float64{0, 30, 40},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vwap := NewVWAP(market, timeframe, ClosePrice, 0, test.anchor)
+
+			// Ensure only daily anchored vwaps expect scheduled resets.
+			assert.Equal(t, vwap.ResetsDaily(), test.anchor.Mode == DailyAnchor)
+
+			// Ensure the vwap only resets across the session boundary when session anchored.
+			for idx := range candles {
+				vwp, err := vwap.Update(candles[idx])
+				assert.NoError(t, err)
+				assert.Equal(t, vwp.Value, test.want[idx])
+			}
+		})
+	}
+
+	// Ensure a rolling vwap ignores its anchor.
+	rolling := NewVWAP(market, timeframe, ClosePrice, 3, VWAPAnchor{Mode: SessionAnchor})
+	assert.False(t, rolling.ResetsDaily())
+	var vwp *shared.VWAP
+	for idx := range candles {
+		vwp, err = rolling.Update(candles[idx])
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, vwp.Value, float64(30))
+}
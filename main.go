@@ -2,11 +2,21 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/dnldd/entry/engine"
+	"github.com/dnldd/entry/indicator"
+	"github.com/dnldd/entry/market"
+	"github.com/dnldd/entry/position"
+	"github.com/dnldd/entry/priceaction"
 	"github.com/dnldd/entry/service"
+	"github.com/dnldd/entry/shared"
 )
 
 // handleTermination processes context cancellation signals or interrupt signals from the OS.
@@ -28,6 +38,63 @@ func handleTermination(ctx context.Context, cancel context.CancelFunc) {
 	}
 }
 
+// handleReload reloads the tracked markets along with the engine thresholds and weights from
+// the .env file on hangup signals from the OS.
+func handleReload(ctx context.Context, entry *service.Entry) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-hangup:
+			reloadCfg, err := loadReloadConfig("")
+			if err != nil {
+				log.Printf("loading reload config: %v", err)
+				continue
+			}
+
+			err = entry.Reload(reloadCfg)
+			if err != nil {
+				log.Printf("reloading entry service: %v", err)
+			}
+		}
+	}
+}
+
+// restoreState restores the entry service state from the snapshot at the provided path.
+// A missing snapshot is not an error.
+func restoreState(entry *service.Entry, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("reading state snapshot: %v", err)
+	}
+
+	return entry.RestoreState(data)
+}
+
+// persistState writes the entry service state snapshot to the provided path.
+func persistState(entry *service.Entry, path string) error {
+	data, err := entry.SnapshotState()
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(path, data, 0600)
+	if err != nil {
+		return fmt.Errorf("writing state snapshot: %v", err)
+	}
+
+	return nil
+}
+
 func main() {
 	var cfg Config
 	err := loadConfig(&cfg, "")
@@ -36,21 +103,407 @@ func main() {
 		return
 	}
 
+	assetClasses, err := shared.ParseAssetClasses(cfg.AssetClasses)
+	if err != nil {
+		log.Printf("parsing asset classes: %v", err)
+		return
+	}
+
+	directionModes, err := engine.ParseDirectionModes(cfg.DirectionModes)
+	if err != nil {
+		log.Printf("parsing direction modes: %v", err)
+		return
+	}
+
+	rthWindows, err := engine.ParseRTHWindows(cfg.RTHWindows)
+	if err != nil {
+		log.Printf("parsing regular trading hours: %v", err)
+		return
+	}
+
+	var reasonPriority shared.ReasonPriority
+	if len(cfg.ReasonPriority) > 0 {
+		reasonPriority, err = shared.ParseReasonPriority(cfg.ReasonPriority)
+		if err != nil {
+			log.Printf("parsing reason priority: %v", err)
+			return
+		}
+	}
+
+	highVolumeWindows, err := shared.ParseSessionWindows(cfg.HighVolumeWindows)
+	if err != nil {
+		log.Printf("parsing high volume windows: %v", err)
+		return
+	}
+
+	sessions, err := shared.ParseSessionDefinitions(cfg.Sessions)
+	if err != nil {
+		log.Printf("parsing sessions: %v", err)
+		return
+	}
+
+	instruments, err := shared.ParseInstruments(cfg.Instruments)
+	if err != nil {
+		log.Printf("parsing instruments: %v", err)
+		return
+	}
+
+	typicalPrice, err := indicator.ParseTypicalPrice(cfg.VWAPTypicalPrice)
+	if err != nil {
+		log.Printf("parsing vwap typical price: %v", err)
+		return
+	}
+
+	vwapAnchors, err := market.ParseVWAPAnchors(cfg.VWAPAnchors)
+	if err != nil {
+		log.Printf("parsing vwap anchors: %v", err)
+		return
+	}
+
+	neutralSkewMode, err := engine.ParseNeutralSkewMode(cfg.NeutralSkewMode)
+	if err != nil {
+		log.Printf("parsing neutral skew mode: %v", err)
+		return
+	}
+
+	movingAverageKind, err := indicator.ParseMovingAverageKind(cfg.MovingAverage)
+	if err != nil {
+		log.Printf("parsing moving average kind: %v", err)
+		return
+	}
+
+	trendBias, err := engine.ParseTrendBiasMode(cfg.TrendBias)
+	if err != nil {
+		log.Printf("parsing trend bias mode: %v", err)
+		return
+	}
+
+	exitPolicy, err := engine.ParseExitPolicy(cfg.ExitPolicy)
+	if err != nil {
+		log.Printf("parsing exit policy: %v", err)
+		return
+	}
+
+	throttleMode, err := position.ParseThrottleMode(cfg.NotifyThrottle)
+	if err != nil {
+		log.Printf("parsing notify throttle: %v", err)
+		return
+	}
+	notifyThrottle := &position.ThrottleConfig{
+		Mode:     throttleMode,
+		Interval: time.Duration(cfg.NotifyThrottleInterval * float64(time.Second)),
+	}
+
+	timeouts := &shared.Timeouts{
+		PriceData:      time.Duration(cfg.PriceDataTimeout * float64(time.Second)),
+		VWAP:           time.Duration(cfg.VWAPTimeout * float64(time.Second)),
+		CandleMetadata: time.Duration(cfg.CandleMetadataTimeout * float64(time.Second)),
+		SignalStatus:   time.Duration(cfg.SignalStatusTimeout * float64(time.Second)),
+	}
+
+	lateBreakHandling, err := shared.ParseLateBreakHandling(cfg.LateBreakHandling)
+	if err != nil {
+		log.Printf("parsing late break handling: %v", err)
+		return
+	}
+
+	reactionEvaluation, err := priceaction.ParseReactionEvaluation(cfg.ReactionEvaluation)
+	if err != nil {
+		log.Printf("parsing reaction evaluation: %v", err)
+		return
+	}
+
+	capacityPolicy, err := engine.ParseCapacityPolicy(cfg.CapacityPolicy)
+	if err != nil {
+		log.Printf("parsing capacity policy: %v", err)
+		return
+	}
+
+	volumeNormalization, err := engine.ParseVolumeNormalization(cfg.VolumeNormalization)
+	if err != nil {
+		log.Printf("parsing volume normalization: %v", err)
+		return
+	}
+
+	volumeFloors, err := engine.ParseVolumeFloors(cfg.VolumeFloors)
+	if err != nil {
+		log.Printf("parsing volume floors: %v", err)
+		return
+	}
+
+	stopBuffers, err := engine.ParseStopBuffers(cfg.StopBuffers)
+	if err != nil {
+		log.Printf("parsing stop buffers: %v", err)
+		return
+	}
+
+	stopRangeMode, err := engine.ParseStopRangeMode(cfg.StopRangeMode)
+	if err != nil {
+		log.Printf("parsing stop range mode: %v", err)
+		return
+	}
+
+	enabledReactions, err := engine.ParseEnabledReactions(cfg.EnabledReactions)
+	if err != nil {
+		log.Printf("parsing enabled reactions: %v", err)
+		return
+	}
+
+	levelWeights, err := engine.ParseLevelWeights(cfg.LevelWeights)
+	if err != nil {
+		log.Printf("parsing level weights: %v", err)
+		return
+	}
+
+	thresholds, err := engine.ParseThresholds(cfg.Thresholds)
+	if err != nil {
+		log.Printf("parsing thresholds: %v", err)
+		return
+	}
+
+	mode := service.Live
+	switch {
+	case cfg.Backtest:
+		mode = service.Backtest
+	case cfg.Paper:
+		mode = service.Paper
+	}
+
+	statusTimeoutPolicy, err := market.ParseStatusTimeoutPolicy(cfg.StatusTimeoutPolicy)
+	if err != nil {
+		log.Printf("parsing status timeout policy: %v", err)
+		return
+	}
+
+	replayMode, err := shared.ParseReplayMode(cfg.ReplayMode)
+	if err != nil {
+		log.Printf("parsing replay mode: %v", err)
+		return
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	reactionFilter := &priceaction.ReactionFilter{
+		IgnoreChop:       cfg.IgnoreChopReactions,
+		MinPriceMovement: cfg.MinReactionMovement,
+	}
+
+	confidenceWeights := newConfidenceWeights(cfg.ConfluenceWeight, cfg.LevelQualityWeight,
+		cfg.TrendAlignmentWeight)
+
+	newsEvents, err := engine.ParseNewsEvents(cfg.NewsEvents)
+	if err != nil {
+		log.Printf("parsing news events: %v", err)
+		return
+	}
+
+	var newsBlackout *engine.NewsBlackout
+	if len(newsEvents) > 0 {
+		newsBlackout = &engine.NewsBlackout{
+			Events: newsEvents,
+			Window: time.Duration(cfg.NewsBlackoutWindow * float64(time.Minute)),
+		}
+	}
+
+	var regimeFilter *engine.RegimeFilter
+	if cfg.RegimeWindow > 0 {
+		regimeFilter = &engine.RegimeFilter{
+			Window:             uint32(cfg.RegimeWindow),
+			MinEfficiency:      cfg.RegimeEfficiency,
+			OverrideConfluence: uint32(cfg.RegimeOverrideConfluence),
+		}
+	}
+
+	var stopRange *engine.StopRange
+	if cfg.MinPointsRange > 0 || cfg.MaxPointsRange > 0 {
+		stopRange = &engine.StopRange{
+			MinPointsRange: cfg.MinPointsRange,
+			MaxPointsRange: cfg.MaxPointsRange,
+			Mode:           stopRangeMode,
+		}
+	}
+
+	var sizing *position.SizingConfig
+	if cfg.PositionSize > 0 {
+		sizing = &position.SizingConfig{
+			BaseSize:         cfg.PositionSize,
+			MaxSize:          max(cfg.MaxPositionSize, cfg.PositionSize),
+			AdjustForWinRate: cfg.AdjustSizeForWinRate,
+			WinRateWindow:    cfg.WinRateWindow,
+			MaxRisk:          cfg.MaxRisk,
+		}
+	}
+
+	var trailingStop *position.TrailingStopConfig
+	if cfg.TrailDistance > 0 {
+		trailMode, err := position.ParseTrailMode(cfg.TrailMode)
+		if err != nil {
+			log.Printf("parsing trail mode: %v", err)
+			return
+		}
+
+		trailingStop = &position.TrailingStopConfig{
+			Mode:       trailMode,
+			Activation: cfg.TrailActivation,
+			Distance:   cfg.TrailDistance,
+			ATRPeriod:  cfg.TrailATRPeriod,
+		}
+	}
+
+	var slippage *position.SlippageConfig
+	if cfg.EntrySlippage > 0 || cfg.ExitSlippage > 0 {
+		slippageMode, err := position.ParseSlippageMode(cfg.SlippageMode)
+		if err != nil {
+			log.Printf("parsing slippage mode: %v", err)
+			return
+		}
+
+		slippage = &position.SlippageConfig{
+			Mode:  slippageMode,
+			Entry: cfg.EntrySlippage,
+			Exit:  cfg.ExitSlippage,
+		}
+	}
+
+	correlationGroups, err := position.ParseCorrelationGroups(cfg.CorrelationGroups)
+	if err != nil {
+		log.Printf("parsing correlation groups: %v", err)
+		return
+	}
+
+	var correlation *position.CorrelationConfig
+	if len(correlationGroups) > 0 {
+		correlation = &position.CorrelationConfig{
+			Groups:           correlationGroups,
+			MaxSameDirection: cfg.MaxCorrelatedPositions,
+		}
+	}
+
+	imbalanceCriteria := shared.DefaultImbalanceCriteria()
+	if cfg.ImbalanceGapRatio > 0 {
+		imbalanceCriteria.MinGapRatio = cfg.ImbalanceGapRatio
+	}
+	imbalanceCriteria.MinGapSize = cfg.ImbalanceGapSize
+	if cfg.ImbalanceVolumeMultiple > 0 {
+		imbalanceCriteria.VolumeMultiple = cfg.ImbalanceVolumeMultiple
+	}
+	if cfg.IgnoreImbalanceVolume {
+		imbalanceCriteria.VolumeMultiple = 0
+	}
+
+	classification := cfg.ClassificationConfig()
+
 	entryCfg := service.EntryConfig{
-		Markets:              cfg.Markets,
-		FMPAPIKey:            cfg.FMPAPIKey,
-		Backtest:             cfg.Backtest,
-		BacktestDataFilepath: cfg.BacktestDataFilepath,
-		Cancel:               cancel,
+		Markets:                   cfg.Markets,
+		FMPAPIKey:                 cfg.FMPAPIKey,
+		Mode:                      mode,
+		BacktestDataFilepath:      cfg.BacktestDataFilepath,
+		SortBacktestData:          cfg.SortBacktestData,
+		ReplayMode:                replayMode,
+		ReplayDelay:               time.Duration(cfg.ReplayDelay * float64(time.Second)),
+		ReplaySpeed:               cfg.ReplaySpeed,
+		VWAPTypicalPrice:          typicalPrice,
+		VWAPRollingWindow:         cfg.VWAPRollingWindow,
+		VWAPAnchors:               vwapAnchors,
+		MovingAverageKind:         movingAverageKind,
+		MovingAveragePeriod:       cfg.MovingAveragePeriod,
+		AggregateCandles:          cfg.AggregateCandles,
+		VolumeProfileBinSize:      cfg.VolumeProfileBinSize,
+		Sessions:                  sessions,
+		EqualLevelTolerance:       cfg.EqualLevelTolerance,
+		ImbalanceCriteria:         &imbalanceCriteria,
+		Classification:            &classification,
+		StatusTimeout:             time.Duration(cfg.StatusTimeout * float64(time.Second)),
+		StatusTimeoutPolicy:       statusTimeoutPolicy,
+		WarmupCandles:             uint32(cfg.WarmupCandles),
+		PriceActionMarketWorkers:  cfg.PriceActionMarketWorkers,
+		PriceActionRequestWorkers: cfg.PriceActionRequestWorkers,
+		DrainGracePeriod:          time.Duration(cfg.DrainGracePeriod * float64(time.Second)),
+		DropReportInterval:        time.Duration(cfg.DropReportInterval * float64(time.Second)),
+		HealthAddress:             cfg.HealthAddress,
+		ReactionWindow:            uint32(cfg.ReactionWindow),
+		RequireFullWindow:         cfg.RequireFullWindow,
+		MinReactionCandles:        uint32(cfg.MinReactionCandles),
+		Timeouts:                  timeouts,
+		ReactionDebounce:          cfg.ReactionDebounce,
+		StaleDistance:             cfg.StaleDistance,
+		StaleCleanupInterval:      time.Duration(cfg.StaleCleanupInterval * float64(time.Second)),
+		VWAPBands:                 cfg.VWAPBands,
+		LateBreakHandling:         lateBreakHandling,
+		ReactionEvaluation:        reactionEvaluation,
+		VWAPMismatchTolerance:     uint32(cfg.VWAPMismatchTolerance),
+		RequireImbalancePurge:     cfg.RequireImbalancePurge,
+		NeutralSkewMode:           neutralSkewMode,
+		TrendBias:                 trendBias,
+		ExitPolicy:                exitPolicy,
+		CapacityPolicy:            capacityPolicy,
+		VolumeNormalization:       volumeNormalization,
+		VolumeFloors:              volumeFloors,
+		CapacityTimeout:           time.Duration(cfg.CapacityTimeout * float64(time.Second)),
+		ConfidenceWeights:         confidenceWeights,
+		NewsBlackout:              newsBlackout,
+		RegimeFilter:              regimeFilter,
+		BracketRewardRatio:        cfg.BracketRewardRatio,
+		StructureSkew:             cfg.StructureSkew,
+		TightestStop:              cfg.TightestStop,
+		WickStopBuffer:            cfg.WickStopBuffer,
+		StopBuffers:               stopBuffers,
+		StopOutBump:               uint32(cfg.StopOutBump),
+		StopOutWindow:             time.Duration(cfg.StopOutWindow * float64(time.Minute)),
+		VWAPStretch:               cfg.VWAPStretch,
+		LevelVWAPGap:              cfg.LevelVWAPGap,
+		AssetClasses:              assetClasses,
+		DirectionModes:            directionModes,
+		RTHWindows:                rthWindows,
+		ReasonPriority:            reasonPriority,
+		HighVolumeWindows:         highVolumeWindows,
+		MinDistinctReasons:        cfg.MinDistinctReasons,
+		BreakConfirmationCloses:   cfg.BreakConfirmationCloses,
+		MaxOpenPositions:          cfg.MaxOpenPositions,
+		Correlation:               correlation,
+		Instruments:               instruments,
+		StopRange:                 stopRange,
+		EnabledReactions:          enabledReactions,
+		LevelWeights:              levelWeights,
+		Thresholds:                thresholds,
+		Sizing:                    sizing,
+		NotifyThrottle:            notifyThrottle,
+		TrailingStop:              trailingStop,
+		Slippage:                  slippage,
+		ReactionFilter:            reactionFilter,
+		PositionsDBFilepath:       cfg.PositionsDBFilepath,
+		StateDBFilepath:           cfg.StateDBFilepath,
+		ExportFilepath:            cfg.ExportFilepath,
+		AuditLogFilepath:          cfg.AuditLogFilepath,
+		TradesCSVFilepath:         cfg.TradesCSVFilepath,
+		Cancel:                    cancel,
 	}
 	entry, err := service.NewEntry(&entryCfg)
 	if err != nil {
 		log.Printf("creating entry service: %v", err)
+		return
+	}
+
+	snapshot := !cfg.Backtest && cfg.SnapshotFilepath != ""
+	if snapshot {
+		err = restoreState(entry, cfg.SnapshotFilepath)
+		if err != nil {
+			log.Printf("restoring state: %v", err)
+			return
+		}
 	}
 
 	go handleTermination(ctx, cancel)
+	if !cfg.Backtest {
+		go handleReload(ctx, entry)
+	}
 	entry.Run(ctx)
+
+	if snapshot {
+		err = persistState(entry, cfg.SnapshotFilepath)
+		if err != nil {
+			log.Printf("persisting state: %v", err)
+		}
+	}
 }
@@ -0,0 +1,139 @@
+package market
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+)
+
+// timeframeDuration returns the duration covered by a candle of the provided timeframe.
+func timeframeDuration(timeframe shared.Timeframe) (time.Duration, error) {
+	switch timeframe {
+	case shared.OneMinute:
+		return time.Minute, nil
+	case shared.FiveMinute:
+		return time.Minute * 5, nil
+	case shared.OneHour:
+		return time.Hour, nil
+	default:
+		return 0, fmt.Errorf("unknown timeframe provided: %s", timeframe.String())
+	}
+}
+
+// Aggregator builds higher timeframe candles from one-minute candles of a market.
+//
+// One-minute candles are bucketed by date into the tracked higher timeframes, a bucket is
+// completed once a candle beyond its boundary is received.
+type Aggregator struct {
+	market     string
+	timeframes []shared.Timeframe
+	durations  map[shared.Timeframe]time.Duration
+	buckets    map[shared.Timeframe]*shared.Candlestick
+	bucketsMtx sync.Mutex
+}
+
+// NewAggregator initializes a new candle aggregator for the provided market and higher timeframes.
+func NewAggregator(market string, timeframes []shared.Timeframe) (*Aggregator, error) {
+	if market == "" {
+		return nil, fmt.Errorf("market cannot be an empty string")
+	}
+
+	durations := make(map[shared.Timeframe]time.Duration)
+	for idx := range timeframes {
+		timeframe := timeframes[idx]
+		if timeframe == shared.OneMinute {
+			return nil, fmt.Errorf("aggregated timeframes must be higher than %s", shared.OneMinute.String())
+		}
+
+		duration, err := timeframeDuration(timeframe)
+		if err != nil {
+			return nil, err
+		}
+
+		durations[timeframe] = duration
+	}
+
+	if len(durations) == 0 {
+		return nil, fmt.Errorf("no timeframes provided for aggregation")
+	}
+
+	return &Aggregator{
+		market:     market,
+		timeframes: timeframes,
+		durations:  durations,
+		buckets:    make(map[shared.Timeframe]*shared.Candlestick),
+	}, nil
+}
+
+// Aggregate adds the provided one-minute candle to the higher timeframe buckets. Candles of
+// buckets completed by the provided candle are returned.
+func (a *Aggregator) Aggregate(candle *shared.Candlestick) ([]*shared.Candlestick, error) {
+	if candle.Timeframe != shared.OneMinute {
+		return nil, fmt.Errorf("expected candles with timeframe %s, got %s",
+			shared.OneMinute.String(), candle.Timeframe.String())
+	}
+	if candle.Market != a.market {
+		return nil, fmt.Errorf("unexpected %s candle provided for %s aggregator", candle.Market, a.market)
+	}
+
+	a.bucketsMtx.Lock()
+	defer a.bucketsMtx.Unlock()
+
+	completed := make([]*shared.Candlestick, 0, len(a.timeframes))
+	for idx := range a.timeframes {
+		timeframe := a.timeframes[idx]
+		start := candle.Date.Truncate(a.durations[timeframe])
+
+		bucket, ok := a.buckets[timeframe]
+		if ok {
+			switch {
+			case start.Before(bucket.Date):
+				return nil, fmt.Errorf("out of order candle @ %v provided for %s bucket @ %v",
+					candle.Date, timeframe.String(), bucket.Date)
+			case start.After(bucket.Date):
+				// The candle crosses the bucket boundary, finalize the current bucket.
+				bucket.Closed = true
+				completed = append(completed, bucket)
+				ok = false
+			}
+		}
+
+		if !ok {
+			a.buckets[timeframe] = &shared.Candlestick{
+				Open:      candle.Open,
+				High:      candle.High,
+				Low:       candle.Low,
+				Close:     candle.Close,
+				Volume:    candle.Volume,
+				Date:      start,
+				Market:    candle.Market,
+				Timeframe: timeframe,
+				Status:    make(chan shared.StatusCode, 1),
+			}
+			continue
+		}
+
+		bucket.High = max(bucket.High, candle.High)
+		bucket.Low = min(bucket.Low, candle.Low)
+		bucket.Close = candle.Close
+		bucket.Volume += candle.Volume
+	}
+
+	return completed, nil
+}
+
+// Current returns a copy of the in-progress candle of the provided timeframe.
+func (a *Aggregator) Current(timeframe shared.Timeframe) (*shared.Candlestick, bool) {
+	a.bucketsMtx.Lock()
+	defer a.bucketsMtx.Unlock()
+
+	bucket, ok := a.buckets[timeframe]
+	if !ok {
+		return nil, false
+	}
+
+	candle := *bucket
+	return &candle, true
+}
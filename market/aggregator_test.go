@@ -0,0 +1,162 @@
+package market
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestNewAggregator(t *testing.T) {
+	tests := []struct {
+		name       string
+		market     string
+		timeframes []shared.Timeframe
+		wantErr    bool
+	}{
+		{
+			name:       "empty market",
+			market:     "",
+			timeframes: []shared.Timeframe{shared.FiveMinute},
+			wantErr:    true,
+		},
+		{
+			name:       "no timeframes",
+			market:     "^GSPC",
+			timeframes: []shared.Timeframe{},
+			wantErr:    true,
+		},
+		{
+			name:       "one minute timeframe",
+			market:     "^GSPC",
+			timeframes: []shared.Timeframe{shared.OneMinute, shared.FiveMinute},
+			wantErr:    true,
+		},
+		{
+			name:       "unknown timeframe",
+			market:     "^GSPC",
+			timeframes: []shared.Timeframe{shared.Timeframe(99)},
+			wantErr:    true,
+		},
+		{
+			name:       "valid aggregator",
+			market:     "^GSPC",
+			timeframes: []shared.Timeframe{shared.FiveMinute, shared.OneHour},
+			wantErr:    false,
+		},
+	}
+
+	for _, test := range tests {
+		_, err := NewAggregator(test.market, test.timeframes)
+		if test.wantErr {
+			assert.Error(t, err)
+			continue
+		}
+
+		assert.NoError(t, err)
+	}
+}
+
+func TestAggregator(t *testing.T) {
+	market := "^GSPC"
+	loc, err := time.LoadLocation(shared.NewYorkLocation)
+	assert.NoError(t, err)
+
+	start := time.Date(2025, 1, 2, 9, 30, 0, 0, loc)
+
+	aggregator, err := NewAggregator(market, []shared.Timeframe{shared.FiveMinute, shared.OneHour})
+	assert.NoError(t, err)
+
+	// Ensure aggregating a candle that is not a one-minute candle errors.
+	_, err = aggregator.Aggregate(&shared.Candlestick{
+		Date:      start,
+		Market:    market,
+		Timeframe: shared.FiveMinute,
+	})
+	assert.Error(t, err)
+
+	// Ensure aggregating a candle of a different market errors.
+	_, err = aggregator.Aggregate(&shared.Candlestick{
+		Date:      start,
+		Market:    "^AAPL",
+		Timeframe: shared.OneMinute,
+	})
+	assert.Error(t, err)
+
+	// Ensure aggregating twelve one-minute candles builds two complete five-minute candles.
+	completed := make([]*shared.Candlestick, 0)
+	for idx := range 12 {
+		candle := &shared.Candlestick{
+			Open:   float64(10 + idx),
+			Close:  float64(11 + idx),
+			High:   float64(12 + idx),
+			Low:    float64(9 + idx),
+			Volume: float64(1 + idx),
+			Date:   start.Add(time.Minute * time.Duration(idx)),
+
+			Market:    market,
+			Timeframe: shared.OneMinute,
+		}
+
+		candles, err := aggregator.Aggregate(candle)
+		assert.NoError(t, err)
+		completed = append(completed, candles...)
+	}
+
+	assert.Equal(t, len(completed), 2)
+
+	first := completed[0]
+	assert.Equal(t, first.Timeframe, shared.FiveMinute)
+	assert.Equal(t, first.Market, market)
+	assert.True(t, first.Date.Equal(start))
+	assert.Equal(t, first.Open, float64(10))
+	assert.Equal(t, first.High, float64(16))
+	assert.Equal(t, first.Low, float64(9))
+	assert.Equal(t, first.Close, float64(15))
+	assert.Equal(t, first.Volume, float64(15))
+	assert.True(t, first.Closed)
+
+	second := completed[1]
+	assert.Equal(t, second.Timeframe, shared.FiveMinute)
+	assert.True(t, second.Date.Equal(start.Add(time.Minute*5)))
+	assert.Equal(t, second.Open, float64(15))
+	assert.Equal(t, second.High, float64(21))
+	assert.Equal(t, second.Low, float64(14))
+	assert.Equal(t, second.Close, float64(20))
+	assert.Equal(t, second.Volume, float64(40))
+
+	// Ensure the in-progress five-minute candle carries the remaining candles.
+	partial, ok := aggregator.Current(shared.FiveMinute)
+	assert.True(t, ok)
+	assert.True(t, partial.Date.Equal(start.Add(time.Minute*10)))
+	assert.False(t, partial.Closed)
+	assert.Equal(t, partial.Open, float64(20))
+	assert.Equal(t, partial.High, float64(23))
+	assert.Equal(t, partial.Low, float64(19))
+	assert.Equal(t, partial.Close, float64(22))
+	assert.Equal(t, partial.Volume, float64(23))
+
+	// Ensure the in-progress one-hour candle carries all aggregated candles.
+	hour, ok := aggregator.Current(shared.OneHour)
+	assert.True(t, ok)
+	assert.Equal(t, hour.Timeframe, shared.OneHour)
+	assert.True(t, hour.Date.Equal(time.Date(2025, 1, 2, 9, 0, 0, 0, loc)))
+	assert.Equal(t, hour.Open, float64(10))
+	assert.Equal(t, hour.High, float64(23))
+	assert.Equal(t, hour.Low, float64(9))
+	assert.Equal(t, hour.Close, float64(22))
+	assert.Equal(t, hour.Volume, float64(78))
+
+	// Ensure aggregating an out of order candle errors.
+	_, err = aggregator.Aggregate(&shared.Candlestick{
+		Date:      start,
+		Market:    market,
+		Timeframe: shared.OneMinute,
+	})
+	assert.Error(t, err)
+
+	// Ensure fetching the in-progress candle of an untracked timeframe fails.
+	_, ok = aggregator.Current(shared.OneMinute)
+	assert.False(t, ok)
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/dnldd/entry/indicator"
 	"github.com/dnldd/entry/shared"
 	"github.com/go-co-op/gocron"
 	"github.com/rs/zerolog"
+	"go.uber.org/atomic"
 )
 
 const (
@@ -33,6 +36,48 @@ type ManagerConfig struct {
 	Markets []string
 	// Timeframes is the timeframes the market is expected to track.
 	Timeframes []shared.Timeframe
+	// VWAPTypicalPrice is the typical price formula used for vwap calculations.
+	VWAPTypicalPrice indicator.TypicalPrice
+	// VWAPRollingWindow is the number of candles covered by a rolling vwap. A zero window
+	// anchors the vwap to the session.
+	VWAPRollingWindow int
+	// VWAPAnchors is when the vwaps of markets that are not rolling are reset, keyed by
+	// market. Markets without an anchor are reset daily.
+	VWAPAnchors map[string]indicator.VWAPAnchor
+	// MovingAverageKind is the formula used for moving average calculations.
+	MovingAverageKind indicator.MovingAverageKind
+	// MovingAveragePeriod is the number of candles covered by the moving average. The default
+	// period is used if zero.
+	MovingAveragePeriod int
+	// AggregateCandles is the flag for building higher timeframe candles from one-minute
+	// candles instead of fetching them.
+	AggregateCandles bool
+	// VolumeProfileBinSize is the price range covered by a session volume profile bin. The
+	// default bin size is used if zero.
+	VolumeProfileBinSize float64
+	// Sessions are the daily sessions tracked, the last session closes the trading day. The
+	// default sessions are used if empty.
+	Sessions []shared.SessionDefinition
+	// EqualLevelTolerance is the percentage of price equal highs and lows can differ by to
+	// be signalled as liquidity levels. Equal highs and lows are not detected if zero.
+	EqualLevelTolerance float64
+	// ImbalanceCriteria is the criteria for detecting imbalances. The default criteria is
+	// used if nil.
+	ImbalanceCriteria *shared.ImbalanceCriteria
+	// Classification is the config candles are classified by. The default config is used if
+	// nil.
+	Classification *shared.ClassificationConfig
+	// StatusTimeout is the maximum time markets wait on the status of a relayed update or
+	// signal. The default timeout is used if zero.
+	StatusTimeout time.Duration
+	// StatusTimeoutPolicy is how markets handle status timeouts.
+	StatusTimeoutPolicy StatusTimeoutPolicy
+	// WarmupCandles is the number of candles of a timeframe a market needs before reactions
+	// are evaluated. The average volume range is used if zero.
+	WarmupCandles uint32
+	// DrainGracePeriod is the maximum time spent handling buffered and in-flight updates and
+	// requests on shutdown. The default grace period is used if zero.
+	DrainGracePeriod time.Duration
 	// Backtest is the backtesting flag.
 	Backtest bool
 	// Subscribe registers the provided subscriber for market updates.
@@ -40,18 +85,47 @@ type ManagerConfig struct {
 	// RelayMarketUpdate relays the provided market update to the price action
 	// manager for processing.
 	RelayMarketUpdate func(candle shared.Candlestick)
+	// RecordVWAP records the provided vwap of a market timeframe.
+	RecordVWAP func(market string, timeframe shared.Timeframe, vwap *shared.VWAP)
 	// CatchUp signals a catchup process for a market.
 	CatchUp func(signal shared.CatchUpSignal)
 	// SignalLevel relays the provided  level signal for  processing.
 	SignalLevel func(signal shared.LevelSignal)
 	// SignalImbalanace relays the provided imbalance signal for processing.
 	SignalImbalance func(signal shared.ImbalanceSignal)
+	// SignalOrderBlock relays the provided order block signal for processing. Order blocks
+	// are not signalled if nil.
+	SignalOrderBlock func(signal shared.OrderBlockSignal)
 	// JobScheduler represents the job scheduler.
 	JobScheduler *gocron.Scheduler
+	// ReplayClock is the clock driving daily market jobs during backtests. Jobs are
+	// scheduled on the job scheduler if nil.
+	ReplayClock *shared.ReplayClock
 	// Logger represents the application logger.
 	Logger *zerolog.Logger
 }
 
+// ParseVWAPAnchors parses per market vwap anchors from the provided market=anchor entries,
+// where the anchor is daily, session or an RFC3339 anchor time.
+func ParseVWAPAnchors(entries []string) (map[string]indicator.VWAPAnchor, error) {
+	anchors := make(map[string]indicator.VWAPAnchor, len(entries))
+	for idx := range entries {
+		market, value, ok := strings.Cut(entries[idx], "=")
+		if !ok || market == "" {
+			return nil, fmt.Errorf("invalid vwap anchor entry provided: %s", entries[idx])
+		}
+
+		anchor, err := indicator.ParseVWAPAnchor(value)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s vwap anchor: %v", market, err)
+		}
+
+		anchors[market] = anchor
+	}
+
+	return anchors, nil
+}
+
 // Validate asserts the config sane inputs.
 func (cfg *ManagerConfig) Validate() error {
 	var errs error
@@ -62,12 +136,59 @@ func (cfg *ManagerConfig) Validate() error {
 	if len(cfg.Timeframes) == 0 {
 		errs = errors.Join(errs, fmt.Errorf("no timeframes provided for market manager"))
 	}
+	if cfg.VWAPRollingWindow < 0 {
+		errs = errors.Join(errs, fmt.Errorf("vwap rolling window cannot be negative"))
+	}
+	for market, anchor := range cfg.VWAPAnchors {
+		err := anchor.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating %s vwap anchor: %v", market, err))
+		}
+	}
+	if cfg.MovingAveragePeriod < 0 {
+		errs = errors.Join(errs, fmt.Errorf("moving average period cannot be negative"))
+	}
+	if cfg.VolumeProfileBinSize < 0 {
+		errs = errors.Join(errs, fmt.Errorf("volume profile bin size cannot be negative"))
+	}
+	if cfg.VolumeProfileBinSize > 0 && cfg.VolumeProfileBinSize < shared.MinVolumeProfileBinSize {
+		errs = errors.Join(errs, fmt.Errorf("volume profile bin size cannot be below %v",
+			shared.MinVolumeProfileBinSize))
+	}
+	err := shared.ValidateSessionDefinitions(cfg.Sessions)
+	if err != nil {
+		errs = errors.Join(errs, fmt.Errorf("validating sessions: %v", err))
+	}
+	if cfg.EqualLevelTolerance < 0 {
+		errs = errors.Join(errs, fmt.Errorf("equal level tolerance cannot be negative"))
+	}
+	if cfg.ImbalanceCriteria != nil {
+		err := cfg.ImbalanceCriteria.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating imbalance criteria: %v", err))
+		}
+	}
+	if cfg.Classification != nil {
+		err := cfg.Classification.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating classification config: %v", err))
+		}
+	}
+	if cfg.StatusTimeout < 0 {
+		errs = errors.Join(errs, fmt.Errorf("status timeout cannot be negative"))
+	}
+	if cfg.DrainGracePeriod < 0 {
+		errs = errors.Join(errs, fmt.Errorf("drain grace period cannot be negative"))
+	}
 	if cfg.Subscribe == nil {
 		errs = errors.Join(errs, fmt.Errorf("subscribe function cannot be nil"))
 	}
 	if cfg.RelayMarketUpdate == nil {
 		errs = errors.Join(errs, fmt.Errorf("relay market update function cannot be nil"))
 	}
+	if cfg.RecordVWAP == nil {
+		errs = errors.Join(errs, fmt.Errorf("record vwap function cannot be nil"))
+	}
 	if cfg.CatchUp == nil {
 		errs = errors.Join(errs, fmt.Errorf("catch up function cannot be nil"))
 	}
@@ -90,6 +211,7 @@ func (cfg *ManagerConfig) Validate() error {
 // Manager manages the lifecycle processes of all tracked markets.
 type Manager struct {
 	cfg                   *ManagerConfig
+	drops                 *shared.DropCounter
 	markets               map[string]*Market
 	marketsMtx            sync.RWMutex
 	updateSignals         chan shared.Candlestick
@@ -98,8 +220,15 @@ type Manager struct {
 	averageVolumeRequests chan shared.AverageVolumeRequest
 	vwapDataRequests      chan shared.VWAPDataRequest
 	vwapRequests          chan shared.VWAPRequest
+	vwapBandsRequests     chan shared.VWAPBandsRequest
+	trendRequests         chan shared.TrendRequest
+	movingAverageRequests chan shared.MovingAverageRequest
 	workers               map[string]chan struct{}
 	requestWorkers        chan struct{}
+	inflight              sync.WaitGroup
+	// runIterations counts the iterations of the run loop, an idle run loop blocks without
+	// advancing it.
+	runIterations atomic.Uint64
 }
 
 // NewManager initializes a new market manager.
@@ -109,41 +238,156 @@ func NewManager(cfg *ManagerConfig, now time.Time) (*Manager, error) {
 		return nil, fmt.Errorf("validating market manager config: %v", err)
 	}
 
-	// initialize managed markets.
-	markets := make(map[string]*Market, 0)
-	workers := make(map[string]chan struct{})
-	for idx := range cfg.Markets {
-		workers[cfg.Markets[idx]] = make(chan struct{}, workerBufferSize)
-
-		mCfg := &MarketConfig{
-			Market:            cfg.Markets[idx],
-			Timeframes:        cfg.Timeframes,
-			SignalLevel:       cfg.SignalLevel,
-			SignalImbalance:   cfg.SignalImbalance,
-			RelayMarketUpdate: cfg.RelayMarketUpdate,
-			JobScheduler:      cfg.JobScheduler,
-			Logger:            cfg.Logger,
-		}
-		market, err := NewMarket(mCfg, now)
-		if err != nil {
-			return nil, fmt.Errorf("creating market: %w", err)
-		}
-
-		markets[cfg.Markets[idx]] = market
-	}
-
-	return &Manager{
+	mgr := &Manager{
 		cfg:                   cfg,
-		markets:               markets,
+		drops:                 shared.NewDropCounter(),
+		markets:               make(map[string]*Market, 0),
 		updateSignals:         make(chan shared.Candlestick, bufferSize),
 		priceDataRequests:     make(chan shared.PriceDataRequest, bufferSize),
 		averageVolumeRequests: make(chan shared.AverageVolumeRequest, bufferSize),
 		caughtUpSignals:       make(chan shared.CaughtUpSignal, bufferSize),
 		vwapDataRequests:      make(chan shared.VWAPDataRequest, bufferSize),
 		vwapRequests:          make(chan shared.VWAPRequest, bufferSize),
-		workers:               workers,
+		vwapBandsRequests:     make(chan shared.VWAPBandsRequest, bufferSize),
+		trendRequests:         make(chan shared.TrendRequest, bufferSize),
+		movingAverageRequests: make(chan shared.MovingAverageRequest, bufferSize),
+		workers:               make(map[string]chan struct{}),
 		requestWorkers:        make(chan struct{}, maxWorkers),
-	}, nil
+	}
+
+	// initialize managed markets.
+	for idx := range cfg.Markets {
+		market, err := mgr.newMarket(cfg.Markets[idx], now)
+		if err != nil {
+			return nil, err
+		}
+
+		mgr.markets[cfg.Markets[idx]] = market
+		mgr.workers[cfg.Markets[idx]] = make(chan struct{}, workerBufferSize)
+	}
+
+	return mgr, nil
+}
+
+// newMarket creates a market using the manager's market configuration.
+func (m *Manager) newMarket(market string, now time.Time) (*Market, error) {
+	mCfg := &MarketConfig{
+		Market:               market,
+		Timeframes:           m.cfg.Timeframes,
+		VWAPTypicalPrice:     m.cfg.VWAPTypicalPrice,
+		VWAPRollingWindow:    m.cfg.VWAPRollingWindow,
+		VWAPAnchor:           m.cfg.VWAPAnchors[market],
+		MovingAverageKind:    m.cfg.MovingAverageKind,
+		MovingAveragePeriod:  m.cfg.MovingAveragePeriod,
+		AggregateCandles:     m.cfg.AggregateCandles,
+		VolumeProfileBinSize: m.cfg.VolumeProfileBinSize,
+		Sessions:             m.cfg.Sessions,
+		EqualLevelTolerance:  m.cfg.EqualLevelTolerance,
+		ImbalanceCriteria:    m.cfg.ImbalanceCriteria,
+		Classification:       m.cfg.Classification,
+		StatusTimeout:        m.cfg.StatusTimeout,
+		StatusTimeoutPolicy:  m.cfg.StatusTimeoutPolicy,
+		WarmupCandles:        m.cfg.WarmupCandles,
+		SignalLevel:          m.cfg.SignalLevel,
+		SignalImbalance:      m.cfg.SignalImbalance,
+		SignalOrderBlock:     m.cfg.SignalOrderBlock,
+		RelayMarketUpdate:    m.cfg.RelayMarketUpdate,
+		RecordVWAP:           m.cfg.RecordVWAP,
+		JobScheduler:         m.cfg.JobScheduler,
+		ReplayClock:          m.cfg.ReplayClock,
+		Logger:               m.cfg.Logger,
+	}
+	mkt, err := NewMarket(mCfg, now)
+	if err != nil {
+		return nil, fmt.Errorf("creating market: %w", err)
+	}
+
+	return mkt, nil
+}
+
+// AddMarket starts tracking the provided market. A catch up is signalled for the market
+// in live execution environments.
+func (m *Manager) AddMarket(market string, now time.Time) error {
+	m.marketsMtx.Lock()
+	_, ok := m.markets[market]
+	if ok {
+		m.marketsMtx.Unlock()
+		return fmt.Errorf("market %s is already tracked", market)
+	}
+
+	mkt, err := m.newMarket(market, now)
+	if err != nil {
+		m.marketsMtx.Unlock()
+		return err
+	}
+
+	m.markets[market] = mkt
+	m.workers[market] = make(chan struct{}, workerBufferSize)
+	m.marketsMtx.Unlock()
+
+	if !m.cfg.Backtest {
+		err = m.catchUpMarket(mkt)
+		if err != nil {
+			return fmt.Errorf("catching up %s market: %v", market, err)
+		}
+	}
+
+	return nil
+}
+
+// RemoveMarket stops tracking the provided market and removes its scheduled jobs.
+func (m *Manager) RemoveMarket(market string) error {
+	m.marketsMtx.Lock()
+	mkt, ok := m.markets[market]
+	if !ok {
+		m.marketsMtx.Unlock()
+		return fmt.Errorf("no market found with name %s", market)
+	}
+
+	delete(m.markets, market)
+	delete(m.workers, market)
+	m.marketsMtx.Unlock()
+
+	return mkt.RemoveJobs()
+}
+
+// AddManualLevel injects a manually drawn level of the provided kind for the provided
+// market. Manual levels are exempt from eviction when the level snapshot is at capacity.
+func (m *Manager) AddManualLevel(market string, price float64, kind shared.LevelKind) error {
+	if price <= 0 {
+		return fmt.Errorf("manual level price must be positive, got %.2f", price)
+	}
+	if kind != shared.Support && kind != shared.Resistance {
+		return fmt.Errorf("unknown level kind provided: %d", kind)
+	}
+
+	m.marketsMtx.RLock()
+	mkt, ok := m.markets[market]
+	m.marketsMtx.RUnlock()
+	if !ok {
+		return fmt.Errorf("no market found with name %s", market)
+	}
+
+	m.cfg.Logger.Info().Msgf("adding manual %s level @ %.2f for %s", kind.String(), price, market)
+
+	signal := shared.NewManualLevelSignal(market, price, kind)
+	m.cfg.SignalLevel(signal)
+	return mkt.awaitStatus(signal.Status, "manual level signal")
+}
+
+// fetchWorker returns the dedicated worker of the provided market.
+func (m *Manager) fetchWorker(market string) (chan struct{}, bool) {
+	m.marketsMtx.RLock()
+	defer m.marketsMtx.RUnlock()
+
+	worker, ok := m.workers[market]
+	return worker, ok
+}
+
+// Dropped returns the number of signals dropped by the manager's channels at capacity,
+// keyed by channel.
+func (m *Manager) Dropped() map[string]uint64 {
+	return m.drops.Counts()
 }
 
 // SendMarketUpdate relays the provided candlestick for processing.
@@ -152,8 +396,7 @@ func (m *Manager) SendMarketUpdate(candle shared.Candlestick) {
 	case m.updateSignals <- candle:
 		// do nothing.
 	default:
-		m.cfg.Logger.Error().Msgf("market update channel at capacity: %d/%d",
-			len(m.updateSignals), bufferSize)
+		m.drops.Drop("market update")
 	}
 }
 
@@ -163,8 +406,7 @@ func (m *Manager) SendCaughtUpSignal(signal shared.CaughtUpSignal) {
 	case m.caughtUpSignals <- signal:
 		// do nothing.
 	default:
-		m.cfg.Logger.Error().Msgf("caught up signal update channel at capacity: %d/%d",
-			len(m.caughtUpSignals), bufferSize)
+		m.drops.Drop("caught up signal")
 	}
 }
 
@@ -174,8 +416,7 @@ func (m *Manager) SendPriceDataRequest(request shared.PriceDataRequest) {
 	case m.priceDataRequests <- request:
 		// do nothing.
 	default:
-		m.cfg.Logger.Error().Msgf("price data requests channel at capacity: %d/%d",
-			len(m.priceDataRequests), bufferSize)
+		m.drops.Drop("price data request")
 	}
 }
 
@@ -185,8 +426,7 @@ func (m *Manager) SendVWAPDataRequest(request shared.VWAPDataRequest) {
 	case m.vwapDataRequests <- request:
 		// do nothing.
 	default:
-		m.cfg.Logger.Error().Msgf("vwap data requests channel at capacity: %d/%d",
-			len(m.vwapDataRequests), bufferSize)
+		m.drops.Drop("vwap data request")
 	}
 }
 
@@ -196,8 +436,37 @@ func (m *Manager) SendVWAPRequest(request shared.VWAPRequest) {
 	case m.vwapRequests <- request:
 		// do nothing.
 	default:
-		m.cfg.Logger.Error().Msgf("current vwap requests channel at capacity: %d/%d",
-			len(m.vwapRequests), bufferSize)
+		m.drops.Drop("current vwap request")
+	}
+}
+
+// SendVWAPBandsRequest relays the provided vwap bands request for processing.
+func (m *Manager) SendVWAPBandsRequest(request shared.VWAPBandsRequest) {
+	select {
+	case m.vwapBandsRequests <- request:
+		// do nothing.
+	default:
+		m.drops.Drop("vwap bands request")
+	}
+}
+
+// SendTrendRequest relays the provided trend request for processing.
+func (m *Manager) SendTrendRequest(request shared.TrendRequest) {
+	select {
+	case m.trendRequests <- request:
+		// do nothing.
+	default:
+		m.drops.Drop("trend request")
+	}
+}
+
+// SendMovingAverageRequest relays the provided moving average request for processing.
+func (m *Manager) SendMovingAverageRequest(request shared.MovingAverageRequest) {
+	select {
+	case m.movingAverageRequests <- request:
+		// do nothing.
+	default:
+		m.drops.Drop("moving average request")
 	}
 }
 
@@ -207,8 +476,7 @@ func (m *Manager) SendAverageVolumeRequest(request shared.AverageVolumeRequest)
 	case m.averageVolumeRequests <- request:
 		// do nothing.
 	default:
-		m.cfg.Logger.Error().Msgf("average volume requests channel at capacity: %d/%d",
-			len(m.averageVolumeRequests), bufferSize)
+		m.drops.Drop("average volume request")
 	}
 }
 
@@ -225,6 +493,20 @@ func (m *Manager) FetchCaughtUpState(market string) (bool, error) {
 	return mkt.CaughtUp(), nil
 }
 
+// WarmedUp returns whether the provided market has enough candles of the provided timeframe
+// for reactions to be evaluated against reliable averages.
+func (m *Manager) WarmedUp(market string, timeframe shared.Timeframe) (bool, error) {
+	m.marketsMtx.RLock()
+	mkt, ok := m.markets[market]
+	m.marketsMtx.RUnlock()
+
+	if !ok {
+		return false, fmt.Errorf("no market found with name %s", market)
+	}
+
+	return mkt.WarmedUp(timeframe)
+}
+
 // handleUpdateSignal processes the provided market update candle.
 func (m *Manager) handleUpdateCandle(candle *shared.Candlestick) error {
 	defer func() {
@@ -239,9 +521,27 @@ func (m *Manager) handleUpdateCandle(candle *shared.Candlestick) error {
 		return fmt.Errorf("no market found with name %s for update", candle.Market)
 	}
 
-	err := mkt.Update(candle)
-	if err != nil {
-		return fmt.Errorf("updating %s market: %v", candle.Market, err)
+	candles := []*shared.Candlestick{candle}
+	if mkt.aggregator != nil && candle.Timeframe == shared.OneMinute {
+		completed, err := mkt.aggregator.Aggregate(candle)
+		if err != nil {
+			return fmt.Errorf("aggregating %s candle: %v", candle.Market, err)
+		}
+
+		// Untracked one-minute candles are only used for aggregation.
+		_, ok := mkt.candleSnapshots[shared.OneMinute]
+		if !ok {
+			candles = candles[:0]
+		}
+
+		candles = append(candles, completed...)
+	}
+
+	for idx := range candles {
+		err := mkt.Update(candles[idx])
+		if err != nil {
+			return fmt.Errorf("updating %s market: %v", candle.Market, err)
+		}
 	}
 
 	return nil
@@ -278,12 +578,17 @@ func (m *Manager) handleAverageVolumeRequest(req *shared.AverageVolumeRequest) e
 
 	candleSnapshot, ok := mkt.candleSnapshots[req.Timeframe]
 	if !ok {
-		return fmt.Errorf("no candle snapshot found for market %s with timeframe %s", req.Market, req.Timeframe)
+		return shared.FailRequest(req.Err, fmt.Errorf("%w: no candle snapshot found for market %s "+
+			"with timeframe %s", shared.ErrTimeframeNotTracked, req.Market, req.Timeframe))
 	}
 
 	avgVolume := candleSnapshot.AverageVolumeN(averageVolumeRange)
 	req.Response <- avgVolume
 
+	if req.Normalize {
+		req.Deviation <- candleSnapshot.VolumeStdDevN(averageVolumeRange)
+	}
+
 	return nil
 }
 
@@ -303,8 +608,8 @@ func (m *Manager) handlePriceDataRequest(req *shared.PriceDataRequest) error {
 
 	candleSnapshot, ok := mkt.candleSnapshots[req.Timeframe]
 	if !ok {
-		return fmt.Errorf("no candle snapshot for market %s found for timeframe %s",
-			req.Market, req.Timeframe)
+		return shared.FailRequest(req.Err, fmt.Errorf("%w: no candle snapshot for market %s found "+
+			"for timeframe %s", shared.ErrTimeframeNotTracked, req.Market, req.Timeframe))
 	}
 
 	data := candleSnapshot.LastN(int32(req.N))
@@ -329,11 +634,16 @@ func (m *Manager) handleVWAPDataRequest(req *shared.VWAPDataRequest) error {
 
 	vwapSnapshot, ok := mkt.vwapSnapshots[req.Timeframe]
 	if !ok {
-		return fmt.Errorf("no vwap snapshot for market %s found for timeframe %s",
-			req.Market, req.Timeframe)
+		return fmt.Errorf("%w: no vwap snapshot for market %s found for timeframe %s",
+			shared.ErrTimeframeNotTracked, req.Market, req.Timeframe)
+	}
+
+	n := int32(req.N)
+	if n == 0 {
+		n = shared.VWAPDataPayloadSize
 	}
 
-	data := vwapSnapshot.LastN(shared.VWAPDataPayloadSize)
+	data := vwapSnapshot.LastN(n)
 	req.Response <- data
 
 	return nil
@@ -355,8 +665,8 @@ func (m *Manager) handleVWAPRequest(req *shared.VWAPRequest) error {
 
 	vwapSnapshot, ok := mkt.vwapSnapshots[req.Timeframe]
 	if !ok {
-		return fmt.Errorf("no vwap snapshot for market %s found for timeframe %s",
-			req.Market, req.Timeframe)
+		return shared.FailRequest(req.Err, fmt.Errorf("%w: no vwap snapshot for market %s found "+
+			"for timeframe %s", shared.ErrTimeframeNotTracked, req.Market, req.Timeframe))
 	}
 
 	vwap := vwapSnapshot.At(req.At)
@@ -365,24 +675,227 @@ func (m *Manager) handleVWAPRequest(req *shared.VWAPRequest) error {
 	return nil
 }
 
+// handleVWAPBandsRequest processes the provided vwap bands request.
+func (m *Manager) handleVWAPBandsRequest(req *shared.VWAPBandsRequest) error {
+	m.marketsMtx.RLock()
+	mkt, ok := m.markets[req.Market]
+	m.marketsMtx.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("no market found with name %s", req.Market)
+	}
+
+	if !mkt.CaughtUp() {
+		return fmt.Errorf("%s is not caught up to current market data", req.Market)
+	}
+
+	vwapSnapshot, ok := mkt.vwapSnapshots[req.Timeframe]
+	if !ok {
+		return fmt.Errorf("%w: no vwap snapshot for market %s found for timeframe %s",
+			shared.ErrTimeframeNotTracked, req.Market, req.Timeframe)
+	}
+
+	n := int32(req.N)
+	if n == 0 {
+		n = shared.VWAPDataPayloadSize
+	}
+
+	data := vwapSnapshot.LastN(n)
+	bands := make([]*shared.VWAP, 0, len(data))
+	for idx := range data {
+		bands = append(bands, data[idx].AtBand(req.Band))
+	}
+	req.Response <- bands
+
+	return nil
+}
+
+// handleTrendRequest processes the provided trend request.
+func (m *Manager) handleTrendRequest(req *shared.TrendRequest) error {
+	m.marketsMtx.RLock()
+	mkt, ok := m.markets[req.Market]
+	m.marketsMtx.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("no market found with name %s", req.Market)
+	}
+
+	if !mkt.CaughtUp() {
+		return fmt.Errorf("%s is not caught up to current market data", req.Market)
+	}
+
+	vwapSnapshot, ok := mkt.vwapSnapshots[req.Timeframe]
+	if !ok {
+		return fmt.Errorf("%w: no vwap snapshot for market %s found for timeframe %s",
+			shared.ErrTimeframeNotTracked, req.Market, req.Timeframe)
+	}
+
+	trend, _, _ := vwapSnapshot.Trend(req.N)
+	req.Response <- trend
+
+	return nil
+}
+
+// handleMovingAverageRequest processes the provided moving average request.
+func (m *Manager) handleMovingAverageRequest(req *shared.MovingAverageRequest) error {
+	m.marketsMtx.RLock()
+	mkt, ok := m.markets[req.Market]
+	m.marketsMtx.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("no market found with name %s", req.Market)
+	}
+
+	if !mkt.CaughtUp() {
+		return fmt.Errorf("%s is not caught up to current market data", req.Market)
+	}
+
+	movingAverage, err := mkt.MovingAverage(req.Timeframe)
+	if err != nil {
+		return err
+	}
+
+	req.Response <- movingAverage
+
+	return nil
+}
+
+// catchUpMarket signals a catch up for the provided market.
+func (m *Manager) catchUpMarket(market *Market) error {
+	start, err := market.sessionSnapshot.FetchLastSessionOpen()
+	if err != nil {
+		return fmt.Errorf("fetching last session open: %v", err)
+	}
+
+	// Markets restored from a fresh state snapshot only catch up on data since the snapshot.
+	restoredAt := market.restoredAt.Load()
+	if restoredAt.After(start) {
+		start = restoredAt
+	}
+	market.restoredAt.Store(time.Time{})
+
+	timeframes := []shared.Timeframe{shared.OneMinute, shared.FiveMinute}
+	if m.cfg.AggregateCandles {
+		// Higher timeframe candles are built from one-minute candles.
+		timeframes = []shared.Timeframe{shared.OneMinute}
+	}
+
+	signal := shared.NewCatchUpSignal(market.cfg.Market, timeframes, start)
+	m.cfg.CatchUp(signal)
+
+	return nil
+}
+
 // catchup signals a catch up for all tracked markets.
 func (m *Manager) catchUp() error {
 	m.marketsMtx.RLock()
 	defer m.marketsMtx.RUnlock()
 
 	for idx := range m.markets {
-		market := m.markets[idx]
+		err := m.catchUpMarket(m.markets[idx])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dispatch hands the provided handler to the provided worker once it is available. It
+// returns false if the provided context is done before the worker becomes available.
+func (m *Manager) dispatch(ctx context.Context, worker chan struct{}, handler func() error) bool {
+	select {
+	case worker <- struct{}{}:
+	case <-ctx.Done():
+		return false
+	}
 
-		start, err := market.sessionSnapshot.FetchLastSessionOpen()
+	m.inflight.Add(1)
+	go func() {
+		defer func() {
+			<-worker
+			m.inflight.Done()
+		}()
+
+		err := handler()
 		if err != nil {
-			return fmt.Errorf("fetching last session open: %v", err)
+			m.cfg.Logger.Error().Err(err).Send()
 		}
+	}()
+
+	return true
+}
 
-		signal := shared.NewCatchUpSignal(market.cfg.Market, []shared.Timeframe{shared.OneMinute, shared.FiveMinute}, start)
-		m.cfg.CatchUp(signal)
+// dispatchMarket hands the provided handler to the dedicated worker of the provided market.
+// The provided status is signalled if the market has no worker.
+func (m *Manager) dispatchMarket(ctx context.Context, market string, kind string, status chan shared.StatusCode, handler func() error) bool {
+	worker, ok := m.fetchWorker(market)
+	if !ok {
+		m.cfg.Logger.Error().Msgf("no worker found for market %s %s", market, kind)
+		status <- shared.Processed
+		return true
 	}
 
-	return nil
+	return m.dispatch(ctx, worker, handler)
+}
+
+// drain dispatches the signals and requests buffered when the manager is shut down and waits
+// for in-flight handlers to complete, bounded by the drain grace period.
+func (m *Manager) drain(ctx context.Context) {
+	ctx, cancel := shared.NewDrainContext(ctx, m.cfg.DrainGracePeriod)
+	defer cancel()
+
+	for {
+		dispatched := true
+		select {
+		case candle := <-m.updateSignals:
+			dispatched = m.dispatchMarket(ctx, candle.Market, "update", candle.Status, func() error {
+				return m.handleUpdateCandle(&candle)
+			})
+		case signal := <-m.caughtUpSignals:
+			dispatched = m.dispatchMarket(ctx, signal.Market, "caught up signal", signal.Status, func() error {
+				return m.handleCaughtUpSignal(&signal)
+			})
+		case req := <-m.priceDataRequests:
+			dispatched = m.dispatch(ctx, m.requestWorkers, func() error {
+				return m.handlePriceDataRequest(&req)
+			})
+		case req := <-m.vwapDataRequests:
+			dispatched = m.dispatch(ctx, m.requestWorkers, func() error {
+				return m.handleVWAPDataRequest(&req)
+			})
+		case req := <-m.vwapRequests:
+			dispatched = m.dispatch(ctx, m.requestWorkers, func() error {
+				return m.handleVWAPRequest(&req)
+			})
+		case req := <-m.vwapBandsRequests:
+			dispatched = m.dispatch(ctx, m.requestWorkers, func() error {
+				return m.handleVWAPBandsRequest(&req)
+			})
+		case req := <-m.trendRequests:
+			dispatched = m.dispatch(ctx, m.requestWorkers, func() error {
+				return m.handleTrendRequest(&req)
+			})
+		case req := <-m.movingAverageRequests:
+			dispatched = m.dispatch(ctx, m.requestWorkers, func() error {
+				return m.handleMovingAverageRequest(&req)
+			})
+		case req := <-m.averageVolumeRequests:
+			dispatched = m.dispatch(ctx, m.requestWorkers, func() error {
+				return m.handleAverageVolumeRequest(&req)
+			})
+		default:
+			if !shared.Await(ctx, &m.inflight) {
+				m.cfg.Logger.Warn().Msg("drain grace period elapsed with in-flight signals")
+			}
+			return
+		}
+
+		if !dispatched {
+			m.cfg.Logger.Warn().Msg("drain grace period elapsed with buffered signals")
+			return
+		}
+	}
 }
 
 // Run manages the lifecycle processes of the position manager.
@@ -399,78 +912,62 @@ func (m *Manager) Run(ctx context.Context) {
 		}
 	}
 
+	// Handlers are dispatched without a deadline while running, the manager drains buffered
+	// updates and requests before shutting down.
+	runCtx := context.WithoutCancel(ctx)
+
 	for {
+		m.runIterations.Inc()
+
 		select {
 		case <-ctx.Done():
+			m.drain(ctx)
 			return
 		case candle := <-m.updateSignals:
 			// use the dedicated market worker to handle the update signal.
-			m.workers[candle.Market] <- struct{}{}
-			go func(candle shared.Candlestick) {
-				err := m.handleUpdateCandle(&candle)
-				if err != nil {
-					m.cfg.Logger.Error().Err(err).Send()
-					return
-				}
-				<-m.workers[candle.Market]
-			}(candle)
+			m.dispatchMarket(runCtx, candle.Market, "update", candle.Status, func() error {
+				return m.handleUpdateCandle(&candle)
+			})
 		case signal := <-m.caughtUpSignals:
 			// use the dedicated market worker to handle the caught up signal.
-			m.workers[signal.Market] <- struct{}{}
-			go func(signal shared.CaughtUpSignal) {
-				err := m.handleCaughtUpSignal(&signal)
-				if err != nil {
-					m.cfg.Logger.Error().Err(err).Send()
-					return
-				}
-				<-m.workers[signal.Market]
-			}(signal)
+			m.dispatchMarket(runCtx, signal.Market, "caught up signal", signal.Status, func() error {
+				return m.handleCaughtUpSignal(&signal)
+			})
 		case req := <-m.priceDataRequests:
 			// handle price data requests concurrently.
-			m.requestWorkers <- struct{}{}
-			go func(req shared.PriceDataRequest) {
-				err := m.handlePriceDataRequest(&req)
-				if err != nil {
-					m.cfg.Logger.Error().Err(err).Send()
-					return
-				}
-				<-m.requestWorkers
-			}(req)
+			m.dispatch(runCtx, m.requestWorkers, func() error {
+				return m.handlePriceDataRequest(&req)
+			})
 		case req := <-m.vwapDataRequests:
 			// handle vwap data requests concurrently.
-			m.requestWorkers <- struct{}{}
-			go func(req shared.VWAPDataRequest) {
-				err := m.handleVWAPDataRequest(&req)
-				if err != nil {
-					m.cfg.Logger.Error().Err(err).Send()
-					return
-				}
-				<-m.requestWorkers
-			}(req)
+			m.dispatch(runCtx, m.requestWorkers, func() error {
+				return m.handleVWAPDataRequest(&req)
+			})
 		case req := <-m.vwapRequests:
 			// handle vwap requests concurrently.
-			m.requestWorkers <- struct{}{}
-			go func(req shared.VWAPRequest) {
-				err := m.handleVWAPRequest(&req)
-				if err != nil {
-					m.cfg.Logger.Error().Err(err).Send()
-					return
-				}
-				<-m.requestWorkers
-			}(req)
+			m.dispatch(runCtx, m.requestWorkers, func() error {
+				return m.handleVWAPRequest(&req)
+			})
+		case req := <-m.vwapBandsRequests:
+			// handle vwap bands requests concurrently.
+			m.dispatch(runCtx, m.requestWorkers, func() error {
+				return m.handleVWAPBandsRequest(&req)
+			})
+		case req := <-m.trendRequests:
+			// handle trend requests concurrently.
+			m.dispatch(runCtx, m.requestWorkers, func() error {
+				return m.handleTrendRequest(&req)
+			})
+		case req := <-m.movingAverageRequests:
+			// handle moving average requests concurrently.
+			m.dispatch(runCtx, m.requestWorkers, func() error {
+				return m.handleMovingAverageRequest(&req)
+			})
 		case req := <-m.averageVolumeRequests:
 			// handle average volume data requests concurrently.
-			m.requestWorkers <- struct{}{}
-			go func(req shared.AverageVolumeRequest) {
-				err := m.handleAverageVolumeRequest(&req)
-				if err != nil {
-					m.cfg.Logger.Error().Err(err).Send()
-					return
-				}
-				<-m.requestWorkers
-			}(req)
-		default:
-			// fallthrough
+			m.dispatch(runCtx, m.requestWorkers, func() error {
+				return m.handleAverageVolumeRequest(&req)
+			})
 		}
 	}
 }
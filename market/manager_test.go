@@ -2,10 +2,12 @@ package market
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/dnldd/entry/indicator"
 	"github.com/dnldd/entry/shared"
 	"github.com/go-co-op/gocron"
 	"github.com/peterldowns/testy/assert"
@@ -44,6 +46,8 @@ func setupManager(t *testing.T, market string, now time.Time, backtest bool) (*M
 		signal.Status <- shared.Processed
 	}
 
+	recordVWAP := func(market string, timeframe shared.Timeframe, vwap *shared.VWAP) {}
+
 	loc, err := time.LoadLocation(shared.NewYorkLocation)
 	assert.NoError(t, err)
 
@@ -55,6 +59,7 @@ func setupManager(t *testing.T, market string, now time.Time, backtest bool) (*M
 		SignalLevel:       signalLevel,
 		SignalImbalance:   signalImbalance,
 		RelayMarketUpdate: relayMarketUpdate,
+		RecordVWAP:        recordVWAP,
 		Backtest:          backtest,
 		JobScheduler:      gocron.NewScheduler(loc),
 		Logger:            &log.Logger,
@@ -66,6 +71,15 @@ func setupManager(t *testing.T, market string, now time.Time, backtest bool) (*M
 	return mgr, catchUpSignals, signalLevelSignals
 }
 
+// testTime returns a fixed weekday new york time away from session boundaries, keeping
+// session dependent tests independent of the wall clock.
+func testTime(t *testing.T) time.Time {
+	loc, err := time.LoadLocation(shared.NewYorkLocation)
+	assert.NoError(t, err)
+
+	return time.Date(2025, 5, 7, 14, 30, 0, 0, loc)
+}
+
 func TestManager(t *testing.T) {
 	// Ensure the market manager can be started.
 	market := "^GSPC"
@@ -91,6 +105,7 @@ func TestManager(t *testing.T) {
 	// Ensure the manager can handle a catch up signal.
 	signal := shared.NewCaughtUpSignal(market)
 	mgr.SendCaughtUpSignal(signal)
+	<-signal.Status
 
 	// Ensure the manager can process a market update.
 	candle := shared.Candlestick{
@@ -107,6 +122,7 @@ func TestManager(t *testing.T) {
 	}
 
 	mgr.SendMarketUpdate(candle)
+	<-candle.Status
 
 	// Ensure the manager can process a price data request.
 	priceDataReq := shared.PriceDataRequest{
@@ -158,6 +174,89 @@ func TestManager(t *testing.T) {
 	<-done
 }
 
+func TestManagerAddRemoveMarket(t *testing.T) {
+	gspc := "^GSPC"
+	ixic := "^IXIC"
+
+	now, _, err := shared.NewYorkTime()
+	assert.NoError(t, err)
+
+	mgr, catchUpSignals, _ := setupManager(t, gspc, now, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		mgr.Run(ctx)
+		close(done)
+	}()
+
+	sig := <-catchUpSignals
+	assert.Equal(t, sig.Market, gspc)
+
+	jobs := mgr.cfg.JobScheduler.Len()
+
+	// Ensure a market can be added at runtime and is caught up.
+	err = mgr.AddMarket(ixic, now)
+	assert.NoError(t, err)
+
+	sig = <-catchUpSignals
+	assert.Equal(t, sig.Market, ixic)
+	assert.Equal(t, mgr.cfg.JobScheduler.Len(), jobs*2)
+
+	// Ensure an already tracked market cannot be added.
+	err = mgr.AddMarket(ixic, now)
+	assert.Error(t, err)
+
+	// Ensure the added market receives market updates.
+	candle := shared.Candlestick{
+		Open:   float64(5),
+		Close:  float64(8),
+		High:   float64(9),
+		Low:    float64(3),
+		Volume: float64(4),
+		Date:   now,
+
+		Market:    ixic,
+		Timeframe: shared.FiveMinute,
+		Status:    make(chan shared.StatusCode, 1),
+	}
+
+	mgr.SendMarketUpdate(candle)
+	<-candle.Status
+
+	avgVolumeReq := shared.AverageVolumeRequest{
+		Market:    ixic,
+		Timeframe: candle.Timeframe,
+		Response:  make(chan float64, 1),
+	}
+
+	mgr.SendAverageVolumeRequest(avgVolumeReq)
+	avgVol := <-avgVolumeReq.Response
+	assert.Equal(t, avgVol, float64(4))
+
+	// Ensure a market can be removed at runtime along with its scheduled jobs.
+	err = mgr.RemoveMarket(ixic)
+	assert.NoError(t, err)
+	assert.Equal(t, mgr.cfg.JobScheduler.Len(), jobs)
+
+	_, err = mgr.FetchCaughtUpState(ixic)
+	assert.Error(t, err)
+
+	// Ensure an untracked market cannot be removed.
+	err = mgr.RemoveMarket(ixic)
+	assert.Error(t, err)
+
+	// Ensure updates for a removed market are acknowledged without being processed.
+	candle.Status = make(chan shared.StatusCode, 1)
+	mgr.SendMarketUpdate(candle)
+	<-candle.Status
+
+	cancel()
+	<-done
+}
+
 func TestManagerConfigValidate(t *testing.T) {
 	// Helper functions for required fields
 	dummySubscribe := func(name string, sub chan shared.Candlestick) {}
@@ -165,6 +264,7 @@ func TestManagerConfigValidate(t *testing.T) {
 	dummyCatchUp := func(signal shared.CatchUpSignal) {}
 	dummySignalLevel := func(signal shared.LevelSignal) {}
 	dummySignalImbalance := func(signal shared.ImbalanceSignal) {}
+	dummyRecordVWAP := func(market string, timeframe shared.Timeframe, vwap *shared.VWAP) {}
 
 	// Use a real zerolog.Logger and gocron.Scheduler for testing
 	logger := zerolog.New(nil)
@@ -178,6 +278,7 @@ func TestManagerConfigValidate(t *testing.T) {
 		CatchUp:           dummyCatchUp,
 		SignalLevel:       dummySignalLevel,
 		SignalImbalance:   dummySignalImbalance,
+		RecordVWAP:        dummyRecordVWAP,
 		JobScheduler:      scheduler,
 		Logger:            &logger,
 	}
@@ -205,6 +306,30 @@ func TestManagerConfigValidate(t *testing.T) {
 			wantErr:     true,
 			errContains: []string{"no timeframes provided"},
 		},
+		{
+			name:        "negative VWAPRollingWindow",
+			modify:      func(cfg *ManagerConfig) { cfg.VWAPRollingWindow = -1 },
+			wantErr:     true,
+			errContains: []string{"vwap rolling window cannot be negative"},
+		},
+		{
+			name:        "negative VolumeProfileBinSize",
+			modify:      func(cfg *ManagerConfig) { cfg.VolumeProfileBinSize = -1 },
+			wantErr:     true,
+			errContains: []string{"volume profile bin size cannot be negative"},
+		},
+		{
+			name:        "VolumeProfileBinSize below minimum",
+			modify:      func(cfg *ManagerConfig) { cfg.VolumeProfileBinSize = shared.MinVolumeProfileBinSize / 2 },
+			wantErr:     true,
+			errContains: []string{"volume profile bin size cannot be below"},
+		},
+		{
+			name:        "negative DrainGracePeriod",
+			modify:      func(cfg *ManagerConfig) { cfg.DrainGracePeriod = -time.Second },
+			wantErr:     true,
+			errContains: []string{"drain grace period cannot be negative"},
+		},
 		{
 			name:        "missing Subscribe",
 			modify:      func(cfg *ManagerConfig) { cfg.Subscribe = nil },
@@ -235,6 +360,12 @@ func TestManagerConfigValidate(t *testing.T) {
 			wantErr:     true,
 			errContains: []string{"signal imbalance function cannot be nil"},
 		},
+		{
+			name:        "missing RecordVWAP",
+			modify:      func(cfg *ManagerConfig) { cfg.RecordVWAP = nil },
+			wantErr:     true,
+			errContains: []string{"record vwap function cannot be nil"},
+		},
 		{
 			name:        "missing JobScheduler",
 			modify:      func(cfg *ManagerConfig) { cfg.JobScheduler = nil },
@@ -261,6 +392,7 @@ func TestManagerConfigValidate(t *testing.T) {
 				"catch up function cannot be nil",
 				"signal level function cannot be nil",
 				"signal imbalance function cannot be nil",
+				"record vwap function cannot be nil",
 				"job scheduler cannot be nil",
 				"logger function cannot be nil",
 			},
@@ -284,6 +416,160 @@ func TestManagerConfigValidate(t *testing.T) {
 	}
 }
 
+func TestManagerRunIdle(t *testing.T) {
+	market := "^GSPC"
+	now, _, err := shared.NewYorkTime()
+	assert.NoError(t, err)
+
+	mgr, _, _ := setupManager(t, market, now, true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		mgr.Run(ctx)
+		close(done)
+	}()
+
+	// Ensure a caught up signal sent once the manager is idle is handled.
+	time.Sleep(time.Millisecond * 50)
+	signal := shared.NewCaughtUpSignal(market)
+	mgr.SendCaughtUpSignal(signal)
+	select {
+	case <-signal.Status:
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for the idle manager to handle the caught up signal")
+	}
+
+	// Ensure the idle manager blocks instead of spinning.
+	iterations := mgr.runIterations.Load()
+	time.Sleep(time.Millisecond * 50)
+	assert.True(t, mgr.runIterations.Load()-iterations <= 1)
+
+	// Ensure the idle manager returns promptly once cancelled.
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the idle manager to return")
+	}
+}
+
+func TestManagerDrain(t *testing.T) {
+	market := "^GSPC"
+	now := testTime(t)
+
+	mgr, _, _ := setupManager(t, market, now, true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	updates := 5
+	candles := make([]shared.Candlestick, 0, updates)
+	for idx := range updates {
+		candle := shared.Candlestick{
+			Open:   float64(5),
+			Close:  float64(8),
+			High:   float64(9),
+			Low:    float64(3),
+			Volume: float64(2),
+			Date:   now.Add(time.Minute * time.Duration(5*idx)),
+
+			Market:    market,
+			Timeframe: shared.FiveMinute,
+			Status:    make(chan shared.StatusCode, 1),
+		}
+		candles = append(candles, candle)
+		mgr.SendMarketUpdate(candle)
+	}
+
+	// Ensure market updates buffered at shutdown are processed before the manager returns.
+	mgr.Run(ctx)
+	for idx := range candles {
+		assert.Equal(t, len(candles[idx].Status), 1)
+	}
+}
+
+func TestManagerRunOutOfOrderCandles(t *testing.T) {
+	market := "^GSPC"
+	now := testTime(t)
+
+	mgr, _, _ := setupManager(t, market, now, true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		mgr.Run(ctx)
+		close(done)
+	}()
+
+	newCandle := func(date time.Time) shared.Candlestick {
+		return shared.Candlestick{
+			Open:   float64(5),
+			Close:  float64(8),
+			High:   float64(9),
+			Low:    float64(3),
+			Volume: float64(2),
+			Date:   date,
+
+			Market:    market,
+			Timeframe: shared.FiveMinute,
+			Status:    make(chan shared.StatusCode, 1),
+		}
+	}
+
+	awaitStatus := func(candle shared.Candlestick) {
+		select {
+		case <-candle.Status:
+		case <-time.After(time.Second * 5):
+			t.Fatalf("timed out waiting for the candle at %s to be processed", candle.Date)
+		}
+	}
+
+	candle := newCandle(now)
+	mgr.SendMarketUpdate(candle)
+	awaitStatus(candle)
+
+	// Ensure rejected out of order candles release their worker slot.
+	for idx := range workerBufferSize * 2 {
+		stale := newCandle(now.Add(-time.Minute * time.Duration(5*(idx+1))))
+		mgr.SendMarketUpdate(stale)
+		awaitStatus(stale)
+	}
+
+	// Ensure a valid candle is still processed after the rejected ones.
+	next := newCandle(now.Add(time.Minute * 5))
+	mgr.SendMarketUpdate(next)
+	awaitStatus(next)
+
+	signal := shared.NewCaughtUpSignal(market)
+	mgr.SendCaughtUpSignal(signal)
+	<-signal.Status
+
+	req := shared.PriceDataRequest{
+		Market:    market,
+		Timeframe: shared.FiveMinute,
+		N:         2,
+		Response:  make(chan []*shared.Candlestick, 1),
+	}
+	mgr.SendPriceDataRequest(req)
+
+	var data []*shared.Candlestick
+	select {
+	case data = <-req.Response:
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for the price data response")
+	}
+	assert.Equal(t, len(data), 2)
+	assert.Equal(t, data[1].Date, next.Date)
+
+	cancel()
+	<-done
+}
+
 func TestFillManagerChannels(t *testing.T) {
 	// Ensure the price action manager can be created.
 	market := "^GSPC"
@@ -335,6 +621,8 @@ func TestFillManagerChannels(t *testing.T) {
 		Response:  make(chan *shared.VWAP, 1),
 	}
 
+	trendReq := shared.NewTrendRequest(market, candle.Timeframe, shared.MacroTrend)
+
 	// Fill all the channels used by the manager.
 	for range bufferSize + 1 {
 		mgr.SendAverageVolumeRequest(avgVolumeReq)
@@ -343,6 +631,7 @@ func TestFillManagerChannels(t *testing.T) {
 		mgr.SendPriceDataRequest(priceDataReq)
 		mgr.SendVWAPDataRequest(vwapDataReq)
 		mgr.SendVWAPRequest(vwapReq)
+		mgr.SendTrendRequest(*trendReq)
 	}
 
 	assert.Equal(t, len(mgr.averageVolumeRequests), bufferSize)
@@ -351,6 +640,18 @@ func TestFillManagerChannels(t *testing.T) {
 	assert.Equal(t, len(mgr.priceDataRequests), bufferSize)
 	assert.Equal(t, len(mgr.vwapDataRequests), bufferSize)
 	assert.Equal(t, len(mgr.vwapRequests), bufferSize)
+	assert.Equal(t, len(mgr.trendRequests), bufferSize)
+
+	// Ensure signals dropped at capacity are counted.
+	assert.Equal(t, mgr.Dropped(), map[string]uint64{
+		"average volume request": 1,
+		"caught up signal":       1,
+		"market update":          1,
+		"price data request":     1,
+		"vwap data request":      1,
+		"current vwap request":   1,
+		"trend request":          1,
+	})
 }
 
 func TestHandleUpdateCandle(t *testing.T) {
@@ -396,6 +697,51 @@ func TestHandleUpdateCandle(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestHandleUpdateCandleAggregation(t *testing.T) {
+	market := "^GSPC"
+
+	now := testTime(t)
+
+	var err error
+	mgr, _, _ := setupManager(t, market, now, false)
+
+	mkt, ok := mgr.markets[market]
+	assert.True(t, ok)
+
+	mkt.aggregator, err = NewAggregator(market, []shared.Timeframe{shared.FiveMinute, shared.OneHour})
+	assert.NoError(t, err)
+
+	// Ensure one-minute candles are aggregated into five-minute market updates.
+	start := now.Truncate(time.Hour)
+	for idx := range 6 {
+		candle := shared.Candlestick{
+			Open:   float64(5),
+			Close:  float64(8),
+			High:   float64(9 + idx),
+			Low:    float64(3),
+			Volume: float64(2),
+			Date:   start.Add(time.Minute * time.Duration(idx)),
+
+			Market:    market,
+			Timeframe: shared.OneMinute,
+			Status:    make(chan shared.StatusCode, 1),
+		}
+
+		err = mgr.handleUpdateCandle(&candle)
+		assert.NoError(t, err)
+	}
+
+	oneMinute := mkt.candleSnapshots[shared.OneMinute].Last()
+	assert.NotNil(t, oneMinute)
+	assert.True(t, oneMinute.Date.Equal(start.Add(time.Minute*5)))
+
+	fiveMinute := mkt.candleSnapshots[shared.FiveMinute].Last()
+	assert.NotNil(t, fiveMinute)
+	assert.True(t, fiveMinute.Date.Equal(start))
+	assert.Equal(t, fiveMinute.High, float64(13))
+	assert.Equal(t, fiveMinute.Volume, float64(10))
+}
+
 func TestHandleCaughtUpSignal(t *testing.T) {
 	market := "^GSPC"
 
@@ -480,6 +826,16 @@ func TestHandleAverageVolumeSignal(t *testing.T) {
 	assert.NoError(t, err)
 	resp = <-avgVolumeReq.Response
 	assert.Equal(t, resp, candle.Volume)
+
+	// Ensure normalized average volume requests also respond with the volume deviation.
+	normalizedReq := shared.NewAverageVolumeRequest(market, candle.Timeframe)
+	normalizedReq.Normalize = true
+	err = mgr.handleAverageVolumeRequest(normalizedReq)
+	assert.NoError(t, err)
+	resp = <-normalizedReq.Response
+	assert.Equal(t, resp, candle.Volume)
+	deviation := <-normalizedReq.Deviation
+	assert.Equal(t, deviation, float64(0))
 }
 
 func TestHandlePriceDataRequest(t *testing.T) {
@@ -541,12 +897,54 @@ func TestHandlePriceDataRequest(t *testing.T) {
 	assert.GreaterThan(t, len(req), 0)
 }
 
+func TestUntrackedTimeframeRequests(t *testing.T) {
+	market := "^GSPC"
+	now := testTime(t)
+
+	mgr, _, _ := setupManager(t, market, now, false)
+
+	mgr.marketsMtx.RLock()
+	mkt := mgr.markets[market]
+	mgr.marketsMtx.RUnlock()
+
+	// Stop tracking the hourly timeframe for the market.
+	untracked := shared.OneHour
+	delete(mkt.candleSnapshots, untracked)
+	delete(mkt.vwapSnapshots, untracked)
+	mkt.caughtUp.Store(true)
+
+	// Ensure requests for an untracked timeframe fail with the typed error, relayed to the
+	// requester as well.
+	avgVolumeReq := shared.NewAverageVolumeRequest(market, untracked)
+	err := mgr.handleAverageVolumeRequest(avgVolumeReq)
+	assert.True(t, errors.Is(err, shared.ErrTimeframeNotTracked))
+	assert.True(t, errors.Is(<-avgVolumeReq.Err, shared.ErrTimeframeNotTracked))
+
+	priceDataReq := shared.NewPriceDataRequest(market, untracked, 4)
+	err = mgr.handlePriceDataRequest(priceDataReq)
+	assert.True(t, errors.Is(err, shared.ErrTimeframeNotTracked))
+	assert.True(t, errors.Is(<-priceDataReq.Err, shared.ErrTimeframeNotTracked))
+
+	vwapReq := shared.NewVWAPRequest(market, now, untracked)
+	err = mgr.handleVWAPRequest(vwapReq)
+	assert.True(t, errors.Is(err, shared.ErrTimeframeNotTracked))
+	assert.True(t, errors.Is(<-vwapReq.Err, shared.ErrTimeframeNotTracked))
+
+	// Ensure requests without an error channel still fail with the typed error.
+	err = mgr.handlePriceDataRequest(&shared.PriceDataRequest{
+		Market:    market,
+		Timeframe: untracked,
+		Response:  make(chan []*shared.Candlestick, 1),
+	})
+	assert.True(t, errors.Is(err, shared.ErrTimeframeNotTracked))
+}
+
 func TestHandleVWAPDataRequest(t *testing.T) {
 	market := "^GSPC"
 
-	now, _, err := shared.NewYorkTime()
-	assert.NoError(t, err)
+	now := testTime(t)
 
+	var err error
 	mgr, _, _ := setupManager(t, market, now, false)
 
 	// Update the market with candle data.
@@ -558,7 +956,7 @@ func TestHandleVWAPDataRequest(t *testing.T) {
 			High:   float64(idx),
 			Low:    float64(idx),
 			Volume: float64(idx),
-			Date:   now,
+			Date:   now.Add(time.Duration(idx-5) * time.Minute * 5),
 
 			Market:    market,
 			Timeframe: timeframe,
@@ -597,14 +995,31 @@ func TestHandleVWAPDataRequest(t *testing.T) {
 	assert.NoError(t, err)
 	req := <-vwapDataReq.Response
 	assert.GreaterThan(t, len(req), 0)
+
+	// Ensure a vwap bands request for an unknown market errors.
+	unknownVWAPBandsReq := shared.NewVWAPBandsRequest("^AAPL", timeframe, shared.UpperBand1, 0)
+	err = mgr.handleVWAPBandsRequest(unknownVWAPBandsReq)
+	assert.Error(t, err)
+
+	// Ensure a valid vwap bands request projects the vwap data onto the requested band.
+	vwapBandsReq := shared.NewVWAPBandsRequest(market, timeframe, shared.LowerBand2, 0)
+	err = mgr.handleVWAPBandsRequest(vwapBandsReq)
+	assert.NoError(t, err)
+	bands := <-vwapBandsReq.Response
+	assert.Equal(t, len(bands), len(req))
+	for idx := range bands {
+		assert.Equal(t, bands[idx].Value, req[idx].Value-2*req[idx].StdDev)
+		assert.Equal(t, bands[idx].Date, req[idx].Date)
+	}
+	assert.GreaterThan(t, bands[len(bands)-1].StdDev, 0)
 }
 
 func TestHandleVWAPRequest(t *testing.T) {
 	market := "^GSPC"
 
-	now, _, err := shared.NewYorkTime()
-	assert.NoError(t, err)
+	now := testTime(t)
 
+	var err error
 	mgr, _, _ := setupManager(t, market, now, false)
 
 	// Update the market with candle data.
@@ -616,7 +1031,7 @@ func TestHandleVWAPRequest(t *testing.T) {
 			High:   float64(idx),
 			Low:    float64(idx),
 			Volume: float64(idx),
-			Date:   now,
+			Date:   now.Add(time.Duration(idx-5) * time.Minute * 5),
 
 			Market:    market,
 			Timeframe: timeframe,
@@ -719,3 +1134,202 @@ func TestBacktestLevelGeneration(t *testing.T) {
 	<-runDone
 	<-mgrDone
 }
+
+func TestHandleTrendRequest(t *testing.T) {
+	market := "^GSPC"
+
+	now := testTime(t)
+
+	var err error
+	mgr, _, _ := setupManager(t, market, now, false)
+
+	// Update the market with steadily rising candle data.
+	timeframe := shared.FiveMinute
+	n := 6
+	for idx := range n {
+		price := float64(idx + 1)
+		candle := shared.Candlestick{
+			Open:   price,
+			Close:  price,
+			High:   price,
+			Low:    price,
+			Volume: price,
+			Date:   now.Add(time.Duration(idx-n+1) * time.Minute * 5),
+
+			Market:    market,
+			Timeframe: timeframe,
+			Status:    make(chan shared.StatusCode, 1),
+		}
+
+		err = mgr.handleUpdateCandle(&candle)
+		assert.NoError(t, err)
+	}
+
+	// Ensure a trend request for a market that is not caught up errors.
+	req := shared.NewTrendRequest(market, timeframe, int32(n))
+	err = mgr.handleTrendRequest(req)
+	assert.Error(t, err)
+
+	mgr.marketsMtx.RLock()
+	mkt := mgr.markets[market]
+	mgr.marketsMtx.RUnlock()
+
+	// Mark the market as caught up.
+	mkt.caughtUp.Store(true)
+
+	// Ensure a trend request for an unknown market errors.
+	unknownReq := shared.NewTrendRequest("^AAPL", timeframe, int32(n))
+	err = mgr.handleTrendRequest(unknownReq)
+	assert.Error(t, err)
+
+	// Ensure a valid trend request returns the trend of the market's vwap.
+	err = mgr.handleTrendRequest(req)
+	assert.NoError(t, err)
+	trend := <-req.Response
+	assert.Equal(t, trend, shared.StrongBullishTrend)
+}
+
+func TestHandleMovingAverageRequest(t *testing.T) {
+	market := "^GSPC"
+
+	now := testTime(t)
+
+	var err error
+	mgr, _, _ := setupManager(t, market, now, false)
+
+	mgr.marketsMtx.RLock()
+	mkt := mgr.markets[market]
+	mgr.marketsMtx.RUnlock()
+
+	// Track a short moving average to fill its period with few candles.
+	timeframe := shared.FiveMinute
+	period := 3
+	mkt.movingAverageIndicators[timeframe] = indicator.NewMovingAverage(market, timeframe,
+		indicator.SimpleMovingAverage, period)
+	mkt.caughtUp.Store(true)
+
+	updateMarket := func(from int, to int) {
+		for idx := from; idx < to; idx++ {
+			price := float64(idx + 1)
+			candle := shared.Candlestick{
+				Open:   price,
+				Close:  price,
+				High:   price,
+				Low:    price,
+				Volume: price,
+				Date:   now.Add(time.Duration(idx-period+1) * time.Minute * 5),
+
+				Market:    market,
+				Timeframe: timeframe,
+				Status:    make(chan shared.StatusCode, 1),
+			}
+
+			err = mgr.handleUpdateCandle(&candle)
+			assert.NoError(t, err)
+		}
+	}
+
+	// Ensure a moving average request for an unknown market errors.
+	unknownReq := shared.NewMovingAverageRequest("^AAPL", timeframe)
+	err = mgr.handleMovingAverageRequest(unknownReq)
+	assert.Error(t, err)
+
+	// Ensure no moving average is returned before its period is filled.
+	updateMarket(0, period-1)
+	req := shared.NewMovingAverageRequest(market, timeframe)
+	err = mgr.handleMovingAverageRequest(req)
+	assert.NoError(t, err)
+	movingAverage := <-req.Response
+	assert.Nil(t, movingAverage)
+
+	// Ensure a valid moving average request returns the current moving average of the market.
+	updateMarket(period-1, period)
+	req = shared.NewMovingAverageRequest(market, timeframe)
+	err = mgr.handleMovingAverageRequest(req)
+	assert.NoError(t, err)
+	movingAverage = <-req.Response
+	assert.NotNil(t, movingAverage)
+	assert.Equal(t, movingAverage.Value, float64(2))
+}
+
+func TestManagerAddManualLevel(t *testing.T) {
+	market := "^GSPC"
+
+	now, _, err := shared.NewYorkTime()
+	assert.NoError(t, err)
+
+	mgr, _, levelSignals := setupManager(t, market, now, false)
+
+	// Ensure manual levels for unknown markets, non-positive prices or unknown kinds error.
+	err = mgr.AddManualLevel("^AAPL", 100, shared.Resistance)
+	assert.Error(t, err)
+	err = mgr.AddManualLevel(market, 0, shared.Resistance)
+	assert.Error(t, err)
+	err = mgr.AddManualLevel(market, 100, shared.LevelKind(999))
+	assert.Error(t, err)
+	assert.Equal(t, len(levelSignals), 0)
+
+	// Ensure a manual level is signalled with its provided kind.
+	err = mgr.AddManualLevel(market, 100, shared.Resistance)
+	assert.NoError(t, err)
+	signal := <-levelSignals
+	assert.Equal(t, signal.Market, market)
+	assert.Equal(t, signal.Price, float64(100))
+	assert.Equal(t, signal.Kind, shared.Resistance)
+	assert.True(t, signal.Manual)
+}
+
+func TestManagerWarmedUp(t *testing.T) {
+	market := "^GSPC"
+
+	now := testTime(t)
+	mgr, _, _ := setupManager(t, market, now, false)
+
+	// Ensure the warmed up state of unknown markets errors.
+	_, err := mgr.WarmedUp("^AAPL", shared.FiveMinute)
+	assert.Error(t, err)
+
+	// Ensure the warmed up state of untracked timeframes errors.
+	_, err = mgr.WarmedUp(market, shared.Timeframe(999))
+	assert.Error(t, err)
+
+	snapshot := mgr.markets[market].candleSnapshots[shared.FiveMinute]
+	addCandles := func(start int, n int) {
+		for idx := start; idx < start+n; idx++ {
+			err := snapshot.Update(&shared.Candlestick{
+				Open:      10,
+				Close:     12,
+				High:      13,
+				Low:       9,
+				Volume:    100,
+				Market:    market,
+				Timeframe: shared.FiveMinute,
+				Date:      now.Add(time.Minute * 5 * time.Duration(idx)),
+			})
+			assert.NoError(t, err)
+		}
+	}
+
+	// Ensure markets are not warmed up with insufficient history.
+	addCandles(0, averageVolumeRange-1)
+	warmedUp, err := mgr.WarmedUp(market, shared.FiveMinute)
+	assert.NoError(t, err)
+	assert.False(t, warmedUp)
+
+	// Ensure markets are warmed up once the average volume range is met.
+	addCandles(averageVolumeRange-1, 1)
+	warmedUp, err = mgr.WarmedUp(market, shared.FiveMinute)
+	assert.NoError(t, err)
+	assert.True(t, warmedUp)
+
+	// Ensure the warmed up state is tracked per timeframe.
+	warmedUp, err = mgr.WarmedUp(market, shared.OneHour)
+	assert.NoError(t, err)
+	assert.False(t, warmedUp)
+
+	// Ensure the configured warmup candles take precedence over the average volume range.
+	mgr.markets[market].cfg.WarmupCandles = averageVolumeRange + 1
+	warmedUp, err = mgr.WarmedUp(market, shared.FiveMinute)
+	assert.NoError(t, err)
+	assert.False(t, warmedUp)
+}
@@ -15,22 +15,112 @@ import (
 const (
 	// updateTimeframe is the expected timeframe for candle updates.
 	updateTimeframe = shared.FiveMinute
+	// jobComponent is the component name used in tagging scheduled market jobs.
+	jobComponent = "market"
 )
 
+// StatusTimeoutPolicy represents how a market handles timing out while waiting on the status
+// of a relayed update or signal.
+type StatusTimeoutPolicy int
+
+const (
+	// ErrorOnTimeout fails the market update on a status timeout.
+	ErrorOnTimeout StatusTimeoutPolicy = iota
+	// RetryOnTimeout waits on the status again, up to the maximum retries, before failing
+	// the market update.
+	RetryOnTimeout
+	// ProceedOnTimeout logs the status timeout and proceeds with the market update.
+	ProceedOnTimeout
+)
+
+// String stringifies the provided status timeout policy.
+func (p StatusTimeoutPolicy) String() string {
+	switch p {
+	case ErrorOnTimeout:
+		return "error"
+	case RetryOnTimeout:
+		return "retry"
+	case ProceedOnTimeout:
+		return "proceed"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseStatusTimeoutPolicy parses the status timeout policy from the provided string. An empty
+// string defaults to ErrorOnTimeout.
+func ParseStatusTimeoutPolicy(policy string) (StatusTimeoutPolicy, error) {
+	switch policy {
+	case "", "error":
+		return ErrorOnTimeout, nil
+	case "retry":
+		return RetryOnTimeout, nil
+	case "proceed":
+		return ProceedOnTimeout, nil
+	default:
+		return 0, fmt.Errorf("unknown status timeout policy provided: %s", policy)
+	}
+}
+
 type MarketConfig struct {
 	// Market is the name of the tracked market.
 	Market string
 	// Timeframes is the timeframes the market is expected to track.
 	Timeframes []shared.Timeframe
+	// VWAPTypicalPrice is the typical price formula used for vwap calculations.
+	VWAPTypicalPrice indicator.TypicalPrice
+	// VWAPRollingWindow is the number of candles covered by a rolling vwap. A zero window
+	// anchors the vwap to the session.
+	VWAPRollingWindow int
+	// VWAPAnchor is when the vwap is reset if it is not rolling.
+	VWAPAnchor indicator.VWAPAnchor
+	// MovingAverageKind is the formula used for moving average calculations.
+	MovingAverageKind indicator.MovingAverageKind
+	// MovingAveragePeriod is the number of candles covered by the moving average. The default
+	// period is used if zero.
+	MovingAveragePeriod int
+	// AggregateCandles is the flag for building higher timeframe candles from one-minute candles.
+	AggregateCandles bool
+	// VolumeProfileBinSize is the price range covered by a session volume profile bin. The
+	// default bin size is used if zero.
+	VolumeProfileBinSize float64
+	// Sessions are the daily sessions tracked, the last session closes the trading day. The
+	// default sessions are used if empty.
+	Sessions []shared.SessionDefinition
+	// EqualLevelTolerance is the percentage of price equal highs and lows can differ by to
+	// be signalled as liquidity levels. Equal highs and lows are not detected if zero.
+	EqualLevelTolerance float64
+	// ImbalanceCriteria is the criteria for detecting imbalances. The default criteria is
+	// used if nil.
+	ImbalanceCriteria *shared.ImbalanceCriteria
+	// Classification is the config candles are classified by. The default config is used if
+	// nil.
+	Classification *shared.ClassificationConfig
+	// StatusTimeout is the maximum time to wait on the status of a relayed update or signal.
+	// The default timeout is used if zero.
+	StatusTimeout time.Duration
+	// StatusTimeoutPolicy is how status timeouts are handled.
+	StatusTimeoutPolicy StatusTimeoutPolicy
+	// WarmupCandles is the number of candles of a timeframe a market needs before reactions
+	// are evaluated. The average volume range is used if zero.
+	WarmupCandles uint32
 	// SignalLevel relays the provided level signal for processing.
 	SignalLevel func(signal shared.LevelSignal)
 	// SignalImbalanace relays the provided imbalance signal for processing.
 	SignalImbalance func(signal shared.ImbalanceSignal)
+	// SignalOrderBlock relays the provided order block signal for processing. Order blocks
+	// are not signalled if nil.
+	SignalOrderBlock func(signal shared.OrderBlockSignal)
 	// RelayMarketUpdate relays the provided market update to the price action
 	// manager for processing.
 	RelayMarketUpdate func(candle shared.Candlestick)
+	// RecordVWAP records the provided vwap of the market timeframe.
+	RecordVWAP func(market string, timeframe shared.Timeframe, vwap *shared.VWAP)
 	// JobScheduler represents the job scheduler.
 	JobScheduler *gocron.Scheduler
+	// ReplayClock is the clock driving daily market jobs during backtests. Jobs are
+	// scheduled on the job scheduler if nil.
+	ReplayClock *shared.ReplayClock
 	// Logger represents the application logger.
 	Logger *zerolog.Logger
 }
@@ -45,6 +135,45 @@ func (cfg *MarketConfig) Validate() error {
 	if len(cfg.Timeframes) == 0 {
 		errs = errors.Join(errs, fmt.Errorf("no timeframes provided for market"))
 	}
+	if cfg.VWAPRollingWindow < 0 {
+		errs = errors.Join(errs, fmt.Errorf("vwap rolling window cannot be negative"))
+	}
+	err := cfg.VWAPAnchor.Validate()
+	if err != nil {
+		errs = errors.Join(errs, fmt.Errorf("validating vwap anchor: %v", err))
+	}
+	if cfg.MovingAveragePeriod < 0 {
+		errs = errors.Join(errs, fmt.Errorf("moving average period cannot be negative"))
+	}
+	if cfg.VolumeProfileBinSize < 0 {
+		errs = errors.Join(errs, fmt.Errorf("volume profile bin size cannot be negative"))
+	}
+	if cfg.VolumeProfileBinSize > 0 && cfg.VolumeProfileBinSize < shared.MinVolumeProfileBinSize {
+		errs = errors.Join(errs, fmt.Errorf("volume profile bin size cannot be below %v",
+			shared.MinVolumeProfileBinSize))
+	}
+	err = shared.ValidateSessionDefinitions(cfg.Sessions)
+	if err != nil {
+		errs = errors.Join(errs, fmt.Errorf("validating sessions: %v", err))
+	}
+	if cfg.EqualLevelTolerance < 0 {
+		errs = errors.Join(errs, fmt.Errorf("equal level tolerance cannot be negative"))
+	}
+	if cfg.ImbalanceCriteria != nil {
+		err := cfg.ImbalanceCriteria.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating imbalance criteria: %v", err))
+		}
+	}
+	if cfg.Classification != nil {
+		err := cfg.Classification.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating classification config: %v", err))
+		}
+	}
+	if cfg.StatusTimeout < 0 {
+		errs = errors.Join(errs, fmt.Errorf("status timeout cannot be negative"))
+	}
 	if cfg.SignalLevel == nil {
 		errs = errors.Join(errs, fmt.Errorf("signal level function cannot be nil"))
 	}
@@ -54,6 +183,9 @@ func (cfg *MarketConfig) Validate() error {
 	if cfg.RelayMarketUpdate == nil {
 		errs = errors.Join(errs, fmt.Errorf("relay market update function cannot be nil"))
 	}
+	if cfg.RecordVWAP == nil {
+		errs = errors.Join(errs, fmt.Errorf("record vwap function cannot be nil"))
+	}
 	if cfg.JobScheduler == nil {
 		errs = errors.Join(errs, fmt.Errorf("job scheduler cannot be nil"))
 	}
@@ -67,14 +199,17 @@ func (cfg *MarketConfig) Validate() error {
 // Market tracks the metadata of a market.
 //
 // The market tracks candlestick data spanning multiple timeframes – 1m, 5m & 1H,
-// as well as their corresponding vwap indicators and vwap snapshots.
+// as well as their corresponding vwap indicators, vwap snapshots and moving average indicators.
 type Market struct {
-	cfg             *MarketConfig
-	sessionSnapshot *shared.SessionSnapshot
-	candleSnapshots map[shared.Timeframe]*shared.CandlestickSnapshot
-	vwapSnapshots   map[shared.Timeframe]*shared.VWAPSnapshot
-	vwapIndicators  map[shared.Timeframe]*indicator.VWAP
-	caughtUp        atomic.Bool
+	cfg                     *MarketConfig
+	sessionSnapshot         *shared.SessionSnapshot
+	candleSnapshots         map[shared.Timeframe]*shared.CandlestickSnapshot
+	vwapSnapshots           map[shared.Timeframe]*shared.VWAPSnapshot
+	vwapIndicators          map[shared.Timeframe]*indicator.VWAP
+	movingAverageIndicators map[shared.Timeframe]*indicator.MovingAverage
+	aggregator              *Aggregator
+	caughtUp                atomic.Bool
+	restoredAt              atomic.Time
 }
 
 // NewMarket initializes a new market.
@@ -84,7 +219,12 @@ func NewMarket(cfg *MarketConfig, now time.Time) (*Market, error) {
 		return nil, fmt.Errorf("validating market config: %v", err)
 	}
 
-	sessionsSnapshot, err := shared.NewSessionSnapshot(shared.SessionSnapshotSize, now)
+	binSize := cfg.VolumeProfileBinSize
+	if binSize == 0 {
+		binSize = shared.DefaultVolumeProfileBinSize
+	}
+
+	sessionsSnapshot, err := shared.NewSessionSnapshot(shared.SessionSnapshotSize, binSize, cfg.Sessions, now)
 	if err != nil {
 		return nil, err
 	}
@@ -149,38 +289,76 @@ func NewMarket(cfg *MarketConfig, now time.Time) (*Market, error) {
 		}
 	}
 
+	// Session anchored vwaps reset on the sessions tracked by the market.
+	anchor := cfg.VWAPAnchor
+	anchor.Sessions = cfg.Sessions
+
 	vwapIndicators := make(map[shared.Timeframe]*indicator.VWAP)
 	for idx := range cfg.Timeframes {
 		timeframe := cfg.Timeframes[idx]
 
 		switch timeframe {
 		case shared.OneMinute:
-			indicator := indicator.NewVWAP(cfg.Market, timeframe)
+			indicator := indicator.NewVWAP(cfg.Market, timeframe, cfg.VWAPTypicalPrice, cfg.VWAPRollingWindow,
+				anchor)
 			vwapIndicators[timeframe] = indicator
 		case shared.FiveMinute:
-			indicator := indicator.NewVWAP(cfg.Market, timeframe)
+			indicator := indicator.NewVWAP(cfg.Market, timeframe, cfg.VWAPTypicalPrice, cfg.VWAPRollingWindow,
+				anchor)
 			vwapIndicators[timeframe] = indicator
 		case shared.OneHour:
-			indicator := indicator.NewVWAP(cfg.Market, timeframe)
+			indicator := indicator.NewVWAP(cfg.Market, timeframe, cfg.VWAPTypicalPrice, cfg.VWAPRollingWindow,
+				anchor)
 			vwapIndicators[timeframe] = indicator
 		}
 	}
 
+	// Create moving average indicators for all tracked timeframes.
+	movingAverageIndicators := make(map[shared.Timeframe]*indicator.MovingAverage)
+	for idx := range cfg.Timeframes {
+		timeframe := cfg.Timeframes[idx]
+		movingAverageIndicators[timeframe] = indicator.NewMovingAverage(cfg.Market, timeframe,
+			cfg.MovingAverageKind, cfg.MovingAveragePeriod)
+	}
+
 	mkt := &Market{
-		cfg:             cfg,
-		sessionSnapshot: sessionsSnapshot,
-		candleSnapshots: candleSnapshots,
-		vwapSnapshots:   vwapSnapshots,
-		vwapIndicators:  vwapIndicators,
+		cfg:                     cfg,
+		sessionSnapshot:         sessionsSnapshot,
+		candleSnapshots:         candleSnapshots,
+		vwapSnapshots:           vwapSnapshots,
+		vwapIndicators:          vwapIndicators,
+		movingAverageIndicators: movingAverageIndicators,
 	}
 
+	if cfg.AggregateCandles {
+		// Aggregate one-minute candles into all tracked higher timeframes.
+		timeframes := make([]shared.Timeframe, 0, len(cfg.Timeframes))
+		for idx := range cfg.Timeframes {
+			if cfg.Timeframes[idx] != shared.OneMinute {
+				timeframes = append(timeframes, cfg.Timeframes[idx])
+			}
+		}
+
+		mkt.aggregator, err = NewAggregator(cfg.Market, timeframes)
+		if err != nil {
+			return nil, fmt.Errorf("creating %s candle aggregator: %v", cfg.Market, err)
+		}
+	}
+
+	jobTag := shared.MarketJobTag(jobComponent, cfg.Market)
+
 	// Periodically reset the market vwaps on all timeframes when the new york session closes.
 	for idx := range cfg.Timeframes {
 		timeframe := cfg.Timeframes[idx]
 
 		vwap := mkt.vwapIndicators[timeframe]
-		_, err = mkt.cfg.JobScheduler.Every(1).Day().At(indicator.VwapResetTime).WaitForSchedule().
-			Do(vwap.Reset)
+		if !vwap.ResetsDaily() {
+			// Rolling vwaps are never reset, session and timestamp anchored vwaps are reset
+			// by their updates.
+			continue
+		}
+
+		err = mkt.scheduleDaily(indicator.VwapResetTime, jobTag, vwap.Reset)
 		if err != nil {
 			return nil, fmt.Errorf("scheduling %s market vwap reset job for timefram %s: %w",
 				vwap.Market, vwap.Timeframe, err)
@@ -188,8 +366,14 @@ func NewMarket(cfg *MarketConfig, now time.Time) (*Market, error) {
 	}
 
 	// Periodically add sessions covering the day to the snapshot.
-	_, err = mkt.cfg.JobScheduler.Every(1).Day().At(shared.SessionGenerationTime).WaitForSchedule().
-		Do(mkt.sessionSnapshot.GenerateNewSessionsJob, cfg.Logger)
+	var clock shared.Clock = shared.WallClock{}
+	if cfg.ReplayClock != nil {
+		clock = cfg.ReplayClock
+	}
+
+	err = mkt.scheduleDaily(shared.SessionGenerationTime, jobTag, func() {
+		mkt.sessionSnapshot.GenerateNewSessionsJob(clock, cfg.Logger)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("scheduling %s market vwap reset job for %s: %w", mkt.cfg.Market,
 			shared.FiveMinute, err)
@@ -198,6 +382,83 @@ func NewMarket(cfg *MarketConfig, now time.Time) (*Market, error) {
 	return mkt, nil
 }
 
+// scheduleDaily schedules the provided job to run daily at the provided time of day, on the
+// replay clock if set and on the job scheduler otherwise.
+func (m *Market) scheduleDaily(at string, tag string, job func()) error {
+	if m.cfg.ReplayClock != nil {
+		return m.cfg.ReplayClock.ScheduleDaily(at, tag, job)
+	}
+
+	_, err := m.cfg.JobScheduler.Every(1).Day().At(at).Tag(tag).WaitForSchedule().Do(job)
+	return err
+}
+
+// imbalanceCriteria returns the criteria imbalances of the market are detected with.
+func (m *Market) imbalanceCriteria() shared.ImbalanceCriteria {
+	if m.cfg.ImbalanceCriteria == nil {
+		return shared.DefaultImbalanceCriteria()
+	}
+
+	return *m.cfg.ImbalanceCriteria
+}
+
+// classification returns the config candles of the market are classified by.
+func (m *Market) classification() shared.ClassificationConfig {
+	if m.cfg.Classification == nil {
+		return shared.DefaultClassificationConfig()
+	}
+
+	return *m.cfg.Classification
+}
+
+// signalEqualLevels sends equal highs and lows formed by the provided candle as liquidity levels.
+func (m *Market) signalEqualLevels(snapshot *shared.CandlestickSnapshot, candle *shared.Candlestick) error {
+	if m.cfg.EqualLevelTolerance == 0 {
+		return nil
+	}
+
+	signalLevel := func(kind string, price float64) error {
+		m.cfg.Logger.Info().Msgf("equal %s liquidity level @ %.2f detected for market %s",
+			kind, price, candle.Market)
+
+		signal := shared.NewLevelSignal(candle.Market, price, candle.Close)
+		m.cfg.SignalLevel(signal)
+		return m.awaitStatus(signal.Status, "level signal")
+	}
+
+	high, ok := snapshot.DetectEqualHighs(shared.EqualLevelLookback, m.cfg.EqualLevelTolerance)
+	if ok {
+		err := signalLevel("highs", high)
+		if err != nil {
+			return err
+		}
+	}
+
+	low, ok := snapshot.DetectEqualLows(shared.EqualLevelLookback, m.cfg.EqualLevelTolerance)
+	if ok {
+		err := signalLevel("lows", low)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemoveJobs removes all scheduled jobs of the market.
+func (m *Market) RemoveJobs() error {
+	if m.cfg.ReplayClock != nil {
+		m.cfg.ReplayClock.RemoveByTag(shared.MarketJobTag(jobComponent, m.cfg.Market))
+	}
+
+	err := m.cfg.JobScheduler.RemoveByTag(shared.MarketJobTag(jobComponent, m.cfg.Market))
+	if err != nil && !errors.Is(err, gocron.ErrJobNotFoundWithTag) {
+		return fmt.Errorf("removing %s market jobs: %w", m.cfg.Market, err)
+	}
+
+	return nil
+}
+
 // SetCaughtUpStatus updates the caught up status of the provided market.
 func (m *Market) SetCaughtUpStatus(status bool) {
 	m.caughtUp.Store(status)
@@ -208,6 +469,113 @@ func (m *Market) CaughtUp() bool {
 	return m.caughtUp.Load()
 }
 
+// WarmedUp returns whether the market has enough candles of the provided timeframe for
+// average volume calculations.
+func (m *Market) WarmedUp(timeframe shared.Timeframe) (bool, error) {
+	candleSnapshot, ok := m.candleSnapshots[timeframe]
+	if !ok {
+		return false, fmt.Errorf("no candle snapshot found for market %s with timeframe %s",
+			m.cfg.Market, timeframe.String())
+	}
+
+	warmup := int32(averageVolumeRange)
+	if m.cfg.WarmupCandles != 0 {
+		warmup = int32(m.cfg.WarmupCandles)
+	}
+
+	return candleSnapshot.Count() >= warmup, nil
+}
+
+// MovingAverage returns the current moving average of the provided timeframe, it is nil
+// until the market has a period of candles of the timeframe.
+func (m *Market) MovingAverage(timeframe shared.Timeframe) (*shared.MovingAverage, error) {
+	movingAverage, ok := m.movingAverageIndicators[timeframe]
+	if !ok {
+		return nil, fmt.Errorf("no moving average indicator found for market %s with timeframe %s",
+			m.cfg.Market, timeframe.String())
+	}
+
+	return movingAverage.Current.Load(), nil
+}
+
+// awaitStatus waits on the provided status, handling timeouts according to the configured
+// status timeout policy.
+func (m *Market) awaitStatus(status chan shared.StatusCode, kind string) error {
+	timeout := m.cfg.StatusTimeout
+	if timeout == 0 {
+		timeout = shared.TimeoutDuration
+	}
+
+	attempts := 1
+	if m.cfg.StatusTimeoutPolicy == RetryOnTimeout {
+		attempts += maxRetries
+	}
+
+	for range attempts {
+		select {
+		case <-status:
+			return nil
+		case <-time.After(timeout):
+		}
+	}
+
+	if m.cfg.StatusTimeoutPolicy == ProceedOnTimeout {
+		m.cfg.Logger.Warn().Msgf("timed out while waiting for %s %s status, proceeding", m.cfg.Market, kind)
+		return nil
+	}
+
+	return fmt.Errorf("timed out while waiting for %s status", kind)
+}
+
+// sessionLevel represents a level derived from a completed session.
+type sessionLevel struct {
+	price  float64
+	source shared.LevelSource
+}
+
+// signalSessionLevels sends the provided high and low of the last completed session as
+// levels tagged with their source session. The high and low of the trading day are sent as
+// daily levels once its closing session completes.
+func (m *Market) signalSessionLevels(candle *shared.Candlestick, high float64, low float64) error {
+	name, err := m.sessionSnapshot.FetchLastSessionName()
+	if err != nil {
+		return fmt.Errorf("fetching last session name: %w", err)
+	}
+
+	source := shared.SessionLevelSource(name)
+	levels := []sessionLevel{{high, source}, {low, source}}
+
+	if m.sessionSnapshot.ClosesDay(name) {
+		dailyHigh, dailyLow, err := m.sessionSnapshot.FetchLastDayHighLow()
+		if err != nil {
+			return fmt.Errorf("fetching last day high and low: %w", err)
+		}
+
+		// Session levels that are also the day's extremes are promoted to daily levels.
+		for idx, daily := range []float64{dailyHigh, dailyLow} {
+			switch {
+			case daily == 0:
+			case daily == levels[idx].price:
+				levels[idx].source = shared.DailyLevel
+			default:
+				levels = append(levels, sessionLevel{daily, shared.DailyLevel})
+			}
+		}
+	}
+
+	for _, level := range levels {
+		signal := shared.NewLevelSignal(candle.Market, level.price, candle.Close)
+		signal.Source = level.source
+		m.cfg.SignalLevel(signal)
+		err = m.awaitStatus(signal.Status, "level signal")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Update processes incoming market data for the provided market.
 func (m *Market) Update(candle *shared.Candlestick) error {
 	// Update the candle snapshot for the provided timeframe.
@@ -216,7 +584,14 @@ func (m *Market) Update(candle *shared.Candlestick) error {
 		return fmt.Errorf("no candle snapshot found for timeframe %s", candle.Timeframe.String())
 	}
 
-	candleSnapshot.Update(candle)
+	// A candle with the same date as the last candle is an update of the forming candle.
+	last := candleSnapshot.Last()
+	forming := last != nil && last.Date.Equal(candle.Date)
+
+	err := candleSnapshot.Update(candle)
+	if err != nil {
+		return fmt.Errorf("updating candle snapshot: %v", err)
+	}
 
 	// Generate the vwap for the provided timeframe.
 	indicator, ok := m.vwapIndicators[candle.Timeframe]
@@ -226,8 +601,8 @@ func (m *Market) Update(candle *shared.Candlestick) error {
 
 	vwap, err := indicator.Update(candle)
 	if err != nil {
-		return fmt.Errorf("updating vwap indicator for market %s at timeframe %s",
-			indicator.Market, indicator.Timeframe)
+		return fmt.Errorf("updating vwap indicator for market %s at timeframe %s: %v",
+			indicator.Market, indicator.Timeframe, err)
 	}
 
 	// Update the vwap snapshot for the provided timeframe.
@@ -236,39 +611,74 @@ func (m *Market) Update(candle *shared.Candlestick) error {
 		return fmt.Errorf("no vwap snapshot found for timeframe %s", candle.Timeframe.String())
 	}
 
-	vwapSnapshot.Update(vwap)
+	if forming {
+		vwapSnapshot.ReplaceLast(vwap)
+	} else {
+		vwapSnapshot.Update(vwap)
+	}
+	m.cfg.RecordVWAP(candle.Market, candle.Timeframe, vwap)
+
+	// Update the moving average for the provided timeframe.
+	movingAverage, ok := m.movingAverageIndicators[candle.Timeframe]
+	if !ok {
+		return fmt.Errorf("no moving average indicator found for timeframe %s", candle.Timeframe.String())
+	}
+
+	_, err = movingAverage.Update(candle)
+	if err != nil {
+		return fmt.Errorf("updating moving average indicator for market %s at timeframe %s: %v",
+			movingAverage.Market, movingAverage.Timeframe, err)
+	}
 
 	// Notify the price action manager of the received market update.
 	updateCandle := *candle
 	updateCandle.Status = make(chan shared.StatusCode, 1)
 
 	m.cfg.RelayMarketUpdate(updateCandle)
-	select {
-	case <-updateCandle.Status:
-	case <-time.After(shared.TimeoutDuration):
-		return fmt.Errorf("timed out while waiting for market update status")
+	err = m.awaitStatus(updateCandle.Status, "market update")
+	if err != nil {
+		return err
 	}
 
 	// Only generate level and imbalance signals on the 5m timeframe.
 	if candle.Timeframe == shared.FiveMinute {
 		// Detect and send imbalances.
-		imbalance, ok := candleSnapshot.DetectImbalance()
+		imbalance, ok := candleSnapshot.DetectImbalance(m.imbalanceCriteria(), m.classification())
 		if ok {
 			imbalanaceSignal := shared.NewImbalanceSignal(candle.Market, *imbalance)
 			m.cfg.SignalImbalance(imbalanaceSignal)
-			select {
-			case <-imbalanaceSignal.Status:
-			case <-time.After(shared.TimeoutDuration):
-				return fmt.Errorf("timed out while waiting for imbalance signal status")
+			err = m.awaitStatus(imbalanaceSignal.Status, "imbalance signal")
+			if err != nil {
+				return err
+			}
+
+			// Send the order block preceding the displacement as a level.
+			if imbalance.OrderBlock != nil && m.cfg.SignalOrderBlock != nil {
+				orderBlockSignal := shared.NewOrderBlockSignal(candle.Market, *imbalance.OrderBlock)
+				m.cfg.SignalOrderBlock(orderBlockSignal)
+				err = m.awaitStatus(orderBlockSignal.Status, "order block signal")
+				if err != nil {
+					return err
+				}
 			}
 		}
 
+		// Detect and send equal highs and lows as liquidity levels.
+		err = m.signalEqualLevels(candleSnapshot, candle)
+		if err != nil {
+			return err
+		}
+
 		changed, err := m.sessionSnapshot.SetCurrentSession(candle.Date)
 		if err != nil {
 			return fmt.Errorf("setting current session: %w", err)
 		}
 
-		m.sessionSnapshot.FetchCurrentSession().Update(candle)
+		err = m.sessionSnapshot.FetchCurrentSession().Update(candle)
+		if err != nil {
+			// The session high and low are still tracked when the profile update is rejected.
+			m.cfg.Logger.Warn().Msgf("skipping %s volume profile update: %v", m.cfg.Market, err)
+		}
 
 		if changed {
 			// Fetch and send new high and low from completed sessions.
@@ -283,20 +693,32 @@ func (m *Market) Update(candle *shared.Candlestick) error {
 				return nil
 			}
 
-			sessionHigh := shared.NewLevelSignal(candle.Market, high, candle.Close)
-			m.cfg.SignalLevel(sessionHigh)
-			select {
-			case <-sessionHigh.Status:
-			case <-time.After(shared.TimeoutDuration):
-				return fmt.Errorf("timed out while waiting for level signal status")
+			err = m.signalSessionLevels(candle, high, low)
+			if err != nil {
+				return err
 			}
 
-			sessionLow := shared.NewLevelSignal(candle.Market, low, candle.Close)
-			m.cfg.SignalLevel(sessionLow)
-			select {
-			case <-sessionLow.Status:
-			case <-time.After(shared.TimeoutDuration):
-				return fmt.Errorf("timed out while waiting for level signal status")
+			// Send the point of control of the completed session as a level.
+			profile, err := m.sessionSnapshot.FetchLastSessionVolumeProfile()
+			if err != nil {
+				return fmt.Errorf("fetching last session volume profile: %w", err)
+			}
+
+			if profile.TotalVolume() == 0 {
+				m.cfg.Logger.Info().Msgf("no volume traded in last session, skipping point of control level signal")
+				return nil
+			}
+
+			poc, err := profile.PointOfControl()
+			if err != nil {
+				return fmt.Errorf("fetching last session point of control: %w", err)
+			}
+
+			pocLevel := shared.NewLevelSignal(candle.Market, poc, candle.Close)
+			m.cfg.SignalLevel(pocLevel)
+			err = m.awaitStatus(pocLevel.Status, "level signal")
+			if err != nil {
+				return err
 			}
 		}
 	}
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dnldd/entry/indicator"
 	"github.com/dnldd/entry/shared"
 	"github.com/go-co-op/gocron"
 	"github.com/peterldowns/testy/assert"
@@ -15,7 +16,7 @@ func TestMarket(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Ensure a market can be created.
-	levelSignals := make(chan shared.LevelSignal, 2)
+	levelSignals := make(chan shared.LevelSignal, 3)
 	signalLevel := func(signal shared.LevelSignal) {
 		levelSignals <- signal
 		signal.Status <- shared.Processed
@@ -33,6 +34,11 @@ func TestMarket(t *testing.T) {
 		relayMarketUpdateSignals <- candle
 	}
 
+	vwaps := make(chan shared.VWAP, 20)
+	recordVWAP := func(market string, timeframe shared.Timeframe, vwap *shared.VWAP) {
+		vwaps <- *vwap
+	}
+
 	market := "^GSPC"
 	loc, err := time.LoadLocation(shared.NewYorkLocation)
 	assert.NoError(t, err)
@@ -43,6 +49,7 @@ func TestMarket(t *testing.T) {
 		SignalLevel:       signalLevel,
 		SignalImbalance:   signalImbalance,
 		RelayMarketUpdate: relayMarketUpdate,
+		RecordVWAP:        recordVWAP,
 		JobScheduler:      gocron.NewScheduler(loc),
 		Logger:            &log.Logger,
 	}
@@ -79,7 +86,12 @@ func TestMarket(t *testing.T) {
 	err = mkt.Update(firstCandle)
 	assert.NoError(t, err)
 
-	// Ensure a market can trigger session high/low signals.
+	// Ensure market updates record the generated vwap.
+	vwap := <-vwaps
+	assert.Equal(t, vwap.Date, firstCandle.Date)
+	assert.Equal(t, vwap.Value, float64((11+8+9))/3)
+
+	// Ensure a market can trigger session high/low and point of control signals.
 	earlyNewYorkSessionTime := asiaSessionCloseTime.Add(time.Minute * 5)
 	secondCandle := &shared.Candlestick{
 		Open:   float64(9),
@@ -99,10 +111,15 @@ func TestMarket(t *testing.T) {
 
 	levelHigh := <-levelSignals
 	levelLow := <-levelSignals
+	levelPOC := <-levelSignals
 
 	assert.Equal(t, levelHigh.Price, float64(11))
 	assert.Equal(t, levelLow.Price, float64(8))
 
+	// The first candle's volume is spread evenly across its range, the lowest bin is the
+	// point of control.
+	assert.Equal(t, levelPOC.Price, float64(8.125))
+
 	// Ensure the market can generate imbalance signals.
 	nextSessionTime := earlyNewYorkSessionTime.Add(time.Minute * 5)
 	thirdCandle := &shared.Candlestick{
@@ -125,3 +142,399 @@ func TestMarket(t *testing.T) {
 
 	assert.Equal(t, imb.Imbalance.Sentiment, shared.Bearish)
 }
+
+func TestMarketRollingVWAP(t *testing.T) {
+	now, _, err := shared.NewYorkTime()
+	assert.NoError(t, err)
+
+	loc, err := time.LoadLocation(shared.NewYorkLocation)
+	assert.NoError(t, err)
+
+	timeframes := []shared.Timeframe{shared.OneMinute, shared.FiveMinute, shared.OneHour}
+	newCfg := func(window int) *MarketConfig {
+		return &MarketConfig{
+			Market:            "^GSPC",
+			Timeframes:        timeframes,
+			VWAPRollingWindow: window,
+			SignalLevel:       func(signal shared.LevelSignal) {},
+			SignalImbalance:   func(signal shared.ImbalanceSignal) {},
+			RelayMarketUpdate: func(candle shared.Candlestick) {},
+			RecordVWAP:        func(market string, timeframe shared.Timeframe, vwap *shared.VWAP) {},
+			JobScheduler:      gocron.NewScheduler(loc),
+			Logger:            &log.Logger,
+		}
+	}
+
+	// Ensure a negative rolling window is rejected.
+	_, err = NewMarket(newCfg(-1), now)
+	assert.Error(t, err)
+
+	// Ensure session anchored vwaps are scheduled for resets alongside session generation.
+	anchoredCfg := newCfg(0)
+	anchored, err := NewMarket(anchoredCfg, now)
+	assert.NoError(t, err)
+	assert.Equal(t, anchoredCfg.JobScheduler.Len(), len(timeframes)+1)
+	for _, timeframe := range timeframes {
+		assert.False(t, anchored.vwapIndicators[timeframe].IsRolling())
+	}
+
+	// Ensure rolling vwaps are never scheduled for resets.
+	rollingCfg := newCfg(20)
+	rolling, err := NewMarket(rollingCfg, now)
+	assert.NoError(t, err)
+	assert.Equal(t, rollingCfg.JobScheduler.Len(), 1)
+	for _, timeframe := range timeframes {
+		assert.True(t, rolling.vwapIndicators[timeframe].IsRolling())
+		assert.Equal(t, rolling.vwapIndicators[timeframe].Window, 20)
+	}
+}
+
+func TestMarketVWAPAnchor(t *testing.T) {
+	now, _, err := shared.NewYorkTime()
+	assert.NoError(t, err)
+
+	loc, err := time.LoadLocation(shared.NewYorkLocation)
+	assert.NoError(t, err)
+
+	timeframes := []shared.Timeframe{shared.OneMinute, shared.FiveMinute, shared.OneHour}
+	tests := []struct {
+		name    string
+		anchor  indicator.VWAPAnchor
+		jobs    int
+		wantErr bool
+	}{
+		{"daily", indicator.VWAPAnchor{Mode: indicator.DailyAnchor}, len(timeframes) + 1, false},
+		{"session", indicator.VWAPAnchor{Mode: indicator.SessionAnchor}, 1, false},
+		{"anchored", indicator.VWAPAnchor{Mode: indicator.TimestampAnchor, At: now}, 1, false},
+		{"anchored without time", indicator.VWAPAnchor{Mode: indicator.TimestampAnchor}, 0, true},
+	}
+	sessions := []shared.SessionDefinition{{Name: "rth", Open: "09:30", Close: "16:00"}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &MarketConfig{
+				Market:            "^GSPC",
+				Timeframes:        timeframes,
+				VWAPAnchor:        test.anchor,
+				Sessions:          sessions,
+				SignalLevel:       func(signal shared.LevelSignal) {},
+				SignalImbalance:   func(signal shared.ImbalanceSignal) {},
+				RelayMarketUpdate: func(candle shared.Candlestick) {},
+				RecordVWAP:        func(market string, timeframe shared.Timeframe, vwap *shared.VWAP) {},
+				JobScheduler:      gocron.NewScheduler(loc),
+				Logger:            &log.Logger,
+			}
+
+			mkt, err := NewMarket(cfg, now)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			// Ensure only daily anchored vwaps are scheduled for resets.
+			assert.NoError(t, err)
+			assert.Equal(t, cfg.JobScheduler.Len(), test.jobs)
+			// Ensure vwaps reset on the sessions tracked by the market.
+			for _, timeframe := range timeframes {
+				assert.Equal(t, mkt.vwapIndicators[timeframe].Anchor.Mode, test.anchor.Mode)
+				assert.Equal(t, mkt.vwapIndicators[timeframe].Anchor.Sessions, sessions)
+			}
+		})
+	}
+}
+
+func TestParseVWAPAnchors(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		want    map[string]indicator.VWAPAnchorMode
+		wantErr bool
+	}{
+		{"no entries", nil, map[string]indicator.VWAPAnchorMode{}, false},
+		{
+			name:    "per market anchors",
+			entries: []string{"^GSPC=session", "^IXIC=daily", "^DJI=2024-01-03T09:30:00-05:00"},
+			want: map[string]indicator.VWAPAnchorMode{
+				"^GSPC": indicator.SessionAnchor,
+				"^IXIC": indicator.DailyAnchor,
+				"^DJI":  indicator.TimestampAnchor,
+			},
+		},
+		{"missing separator", []string{"^GSPC"}, nil, true},
+		{"missing market", []string{"=session"}, nil, true},
+		{"unknown anchor", []string{"^GSPC=weekly"}, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			anchors, err := ParseVWAPAnchors(test.entries)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, len(anchors), len(test.want))
+			for market, mode := range test.want {
+				assert.Equal(t, anchors[market].Mode, mode)
+			}
+		})
+	}
+}
+
+func TestParseStatusTimeoutPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		want    StatusTimeoutPolicy
+		wantErr bool
+	}{
+		{"default policy", "", ErrorOnTimeout, false},
+		{"error policy", "error", ErrorOnTimeout, false},
+		{"retry policy", "retry", RetryOnTimeout, false},
+		{"proceed policy", "proceed", ProceedOnTimeout, false},
+		{"unknown policy", "ignore", ErrorOnTimeout, true},
+	}
+
+	for _, test := range tests {
+		policy, err := ParseStatusTimeoutPolicy(test.policy)
+		if test.wantErr {
+			assert.Error(t, err)
+			continue
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, policy, test.want)
+		if test.policy != "" {
+			assert.Equal(t, policy.String(), test.policy)
+		}
+	}
+}
+
+func TestMarketStatusTimeoutPolicy(t *testing.T) {
+	now, _, err := shared.NewYorkTime()
+	assert.NoError(t, err)
+
+	loc, err := time.LoadLocation(shared.NewYorkLocation)
+	assert.NoError(t, err)
+
+	ts, err := time.Parse(shared.SessionTimeLayout, "03:00")
+	assert.NoError(t, err)
+	asiaSessionCloseTime := time.Date(now.Year(), now.Month(), now.Day(), ts.Hour(), ts.Minute(), 0, 0, loc)
+
+	market := "^GSPC"
+	statusTimeout := time.Millisecond * 10
+	newMarket := func(policy StatusTimeoutPolicy, relayMarketUpdate func(candle shared.Candlestick)) *Market {
+		cfg := &MarketConfig{
+			Market:              market,
+			Timeframes:          []shared.Timeframe{shared.FiveMinute},
+			StatusTimeout:       statusTimeout,
+			StatusTimeoutPolicy: policy,
+			SignalLevel: func(signal shared.LevelSignal) {
+				signal.Status <- shared.Processed
+			},
+			SignalImbalance: func(signal shared.ImbalanceSignal) {
+				signal.Status <- shared.Processed
+			},
+			RelayMarketUpdate: relayMarketUpdate,
+			RecordVWAP:        func(market string, timeframe shared.Timeframe, vwap *shared.VWAP) {},
+			JobScheduler:      gocron.NewScheduler(loc),
+			Logger:            &log.Logger,
+		}
+
+		mkt, err := NewMarket(cfg, asiaSessionCloseTime)
+		assert.NoError(t, err)
+		mkt.sessionSnapshot.GenerateNewSessions(asiaSessionCloseTime)
+
+		return mkt
+	}
+
+	newCandle := func() *shared.Candlestick {
+		return &shared.Candlestick{
+			Open:      float64(10),
+			Close:     float64(9),
+			High:      float64(11),
+			Low:       float64(8),
+			Volume:    float64(2),
+			Date:      asiaSessionCloseTime,
+			Market:    market,
+			Timeframe: shared.FiveMinute,
+			Status:    make(chan shared.StatusCode, 1),
+		}
+	}
+
+	// A price action manager that never acknowledges market updates.
+	unresponsive := func(candle shared.Candlestick) {}
+
+	// Ensure the error policy fails the update on a status timeout, leaving the session
+	// un-updated.
+	mkt := newMarket(ErrorOnTimeout, unresponsive)
+	err = mkt.Update(newCandle())
+	assert.Error(t, err)
+	assert.Equal(t, mkt.sessionSnapshot.FetchCurrentSession().High.Load(), float64(0))
+
+	// Ensure the proceed policy completes the update on a status timeout, keeping the
+	// candle snapshot and session consistent.
+	mkt = newMarket(ProceedOnTimeout, unresponsive)
+	candle := newCandle()
+	err = mkt.Update(candle)
+	assert.NoError(t, err)
+	assert.Equal(t, mkt.candleSnapshots[shared.FiveMinute].Last().Close, candle.Close)
+	assert.Equal(t, mkt.sessionSnapshot.FetchCurrentSession().High.Load(), candle.High)
+	assert.Equal(t, mkt.sessionSnapshot.FetchCurrentSession().Low.Load(), candle.Low)
+
+	// Ensure the retry policy waits on a slow status acknowledgement.
+	slow := func(candle shared.Candlestick) {
+		go func() {
+			time.Sleep(statusTimeout * 2)
+			candle.Status <- shared.Processed
+		}()
+	}
+	mkt = newMarket(RetryOnTimeout, slow)
+	err = mkt.Update(newCandle())
+	assert.NoError(t, err)
+
+	// Ensure the retry policy fails the update once retries are exhausted.
+	mkt = newMarket(RetryOnTimeout, unresponsive)
+	err = mkt.Update(newCandle())
+	assert.Error(t, err)
+}
+
+func TestMarketEqualLevels(t *testing.T) {
+	now, _, err := shared.NewYorkTime()
+	assert.NoError(t, err)
+
+	loc, err := time.LoadLocation(shared.NewYorkLocation)
+	assert.NoError(t, err)
+
+	ts, err := time.Parse(shared.SessionTimeLayout, "03:00")
+	assert.NoError(t, err)
+	asiaSessionCloseTime := time.Date(now.Year(), now.Month(), now.Day(), ts.Hour(), ts.Minute(), 0, 0, loc)
+
+	market := "^GSPC"
+	levels := func(tolerance float64) []float64 {
+		signalled := make([]float64, 0)
+		cfg := &MarketConfig{
+			Market:              market,
+			Timeframes:          []shared.Timeframe{shared.FiveMinute},
+			EqualLevelTolerance: tolerance,
+			SignalLevel: func(signal shared.LevelSignal) {
+				signalled = append(signalled, signal.Price)
+				signal.Status <- shared.Processed
+			},
+			SignalImbalance: func(signal shared.ImbalanceSignal) {
+				signal.Status <- shared.Processed
+			},
+			RelayMarketUpdate: func(candle shared.Candlestick) {
+				candle.Status <- shared.Processed
+			},
+			RecordVWAP:   func(market string, timeframe shared.Timeframe, vwap *shared.VWAP) {},
+			JobScheduler: gocron.NewScheduler(loc),
+			Logger:       &log.Logger,
+		}
+
+		mkt, err := NewMarket(cfg, asiaSessionCloseTime)
+		assert.NoError(t, err)
+		mkt.sessionSnapshot.GenerateNewSessions(asiaSessionCloseTime)
+
+		// A double top with equal highs at 102.
+		highs := []float64{100, 102, 99, 98, 101.98}
+		for idx := range highs {
+			err = mkt.Update(&shared.Candlestick{
+				Open:      highs[idx] - 2,
+				Close:     highs[idx] - 1,
+				High:      highs[idx],
+				Low:       highs[idx] - 3,
+				Volume:    float64(2),
+				Date:      asiaSessionCloseTime.Add(time.Minute * 5 * time.Duration(idx)),
+				Market:    market,
+				Timeframe: shared.FiveMinute,
+				Status:    make(chan shared.StatusCode, 1),
+			})
+			assert.NoError(t, err)
+		}
+
+		return signalled
+	}
+
+	// Ensure equal highs are signalled as a liquidity level when detection is enabled.
+	assert.In(t, float64(102), levels(0.05))
+
+	// Ensure equal highs are not signalled when detection is disabled.
+	assert.NotIn(t, float64(102), levels(0))
+}
+
+func TestMarketImbalanceCriteria(t *testing.T) {
+	now, _, err := shared.NewYorkTime()
+	assert.NoError(t, err)
+
+	loc, err := time.LoadLocation(shared.NewYorkLocation)
+	assert.NoError(t, err)
+
+	ts, err := time.Parse(shared.SessionTimeLayout, "03:00")
+	assert.NoError(t, err)
+	asiaSessionCloseTime := time.Date(now.Year(), now.Month(), now.Day(), ts.Hour(), ts.Minute(), 0, 0, loc)
+
+	market := "^GSPC"
+	imbalances := func(criteria *shared.ImbalanceCriteria) int {
+		var signalled int
+		cfg := &MarketConfig{
+			Market:            market,
+			Timeframes:        []shared.Timeframe{shared.FiveMinute},
+			ImbalanceCriteria: criteria,
+			SignalLevel: func(signal shared.LevelSignal) {
+				signal.Status <- shared.Processed
+			},
+			SignalImbalance: func(signal shared.ImbalanceSignal) {
+				signalled++
+				signal.Status <- shared.Processed
+			},
+			RelayMarketUpdate: func(candle shared.Candlestick) {
+				candle.Status <- shared.Processed
+			},
+			RecordVWAP:   func(market string, timeframe shared.Timeframe, vwap *shared.VWAP) {},
+			JobScheduler: gocron.NewScheduler(loc),
+			Logger:       &log.Logger,
+		}
+
+		mkt, err := NewMarket(cfg, asiaSessionCloseTime)
+		assert.NoError(t, err)
+		mkt.sessionSnapshot.GenerateNewSessions(asiaSessionCloseTime)
+
+		// A bullish displacement leaving a gap of a point.
+		candles := []shared.Candlestick{
+			{Open: 15, Close: 17, High: 18, Low: 10, Volume: 2},
+			{Open: 17, Close: 24, High: 25, Low: 16, Volume: 7},
+			{Open: 24, Close: 27, High: 28, Low: 19, Volume: 2},
+		}
+		for idx := range candles {
+			candle := candles[idx]
+			candle.Date = asiaSessionCloseTime.Add(time.Minute * 5 * time.Duration(idx))
+			candle.Market = market
+			candle.Timeframe = shared.FiveMinute
+			candle.Status = make(chan shared.StatusCode, 1)
+			err = mkt.Update(&candle)
+			assert.NoError(t, err)
+		}
+
+		return signalled
+	}
+
+	// Ensure the small gap is not signalled as an imbalance under the default criteria.
+	assert.Equal(t, imbalances(nil), 0)
+
+	// Ensure the small gap is signalled as an imbalance under a loose gap ratio.
+	assert.Equal(t, imbalances(&shared.ImbalanceCriteria{MinGapRatio: 0.1, VolumeMultiple: 1}), 1)
+
+	// Ensure the small gap is not signalled as an imbalance under a minimum gap size.
+	assert.Equal(t, imbalances(&shared.ImbalanceCriteria{MinGapRatio: 0.1, MinGapSize: 2, VolumeMultiple: 1}), 0)
+
+	// Ensure invalid criteria is rejected.
+	_, err = NewMarket(&MarketConfig{
+		Market:            market,
+		Timeframes:        []shared.Timeframe{shared.FiveMinute},
+		ImbalanceCriteria: &shared.ImbalanceCriteria{MinGapRatio: -1},
+		Logger:            &log.Logger,
+	}, asiaSessionCloseTime)
+	assert.Error(t, err)
+}
@@ -0,0 +1,79 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// MarketState is the serializable state of a market.
+type MarketState struct {
+	CaughtUp bool
+}
+
+// ManagerState is the serializable state of all tracked markets, keyed by market.
+type ManagerState struct {
+	Markets map[string]*MarketState
+	// SnapshotTime is the time the state was snapshotted.
+	SnapshotTime time.Time
+}
+
+// SnapshotState serializes the caught up status of all tracked markets to json.
+func (m *Manager) SnapshotState() ([]byte, error) {
+	m.marketsMtx.RLock()
+	state := &ManagerState{
+		Markets:      make(map[string]*MarketState, len(m.markets)),
+		SnapshotTime: time.Now().UTC(),
+	}
+	for market, mkt := range m.markets {
+		state.Markets[market] = &MarketState{CaughtUp: mkt.CaughtUp()}
+	}
+	m.marketsMtx.RUnlock()
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling market state: %v", err)
+	}
+
+	return data, nil
+}
+
+// RestoreState restores the caught up status of tracked markets from the provided json state.
+// The state of untracked markets is ignored. State snapshotted before the last session open
+// is stale and ignored, markets restored from fresh state catch up from the snapshot time
+// instead of the last session open.
+func (m *Manager) RestoreState(data []byte) error {
+	var state ManagerState
+	err := json.Unmarshal(data, &state)
+	if err != nil {
+		return fmt.Errorf("unmarshaling market state: %v", err)
+	}
+
+	m.marketsMtx.RLock()
+	defer m.marketsMtx.RUnlock()
+
+	for market, mktState := range state.Markets {
+		mkt, ok := m.markets[market]
+		if !ok {
+			m.cfg.Logger.Info().Msgf("skipping state of untracked market %s", market)
+			continue
+		}
+
+		open, err := mkt.sessionSnapshot.FetchLastSessionOpen()
+		if err != nil {
+			return fmt.Errorf("fetching last session open: %v", err)
+		}
+
+		if !state.SnapshotTime.After(open) {
+			m.cfg.Logger.Info().Msgf("skipping stale state of market %s", market)
+			continue
+		}
+
+		mkt.SetCaughtUpStatus(mktState.CaughtUp)
+		if mktState.CaughtUp {
+			mkt.restoredAt.Store(state.SnapshotTime)
+		}
+	}
+
+	return nil
+}
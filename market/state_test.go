@@ -0,0 +1,129 @@
+package market
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestManagerStateRoundTrip(t *testing.T) {
+	market := "^GSPC"
+	now := testTime(t)
+
+	mgr, _, _ := setupManager(t, market, now, false)
+	mkt, ok := mgr.markets[market]
+	assert.True(t, ok)
+	mkt.SetCaughtUpStatus(true)
+
+	data, err := mgr.SnapshotState()
+	assert.NoError(t, err)
+
+	// Ensure the caught up status restores into a fresh manager.
+	restored, _, _ := setupManager(t, market, now, false)
+	caughtUp, err := restored.FetchCaughtUpState(market)
+	assert.NoError(t, err)
+	assert.False(t, caughtUp)
+
+	err = restored.RestoreState(data)
+	assert.NoError(t, err)
+
+	caughtUp, err = restored.FetchCaughtUpState(market)
+	assert.NoError(t, err)
+	assert.True(t, caughtUp)
+
+	// Ensure the state of untracked markets is ignored.
+	err = restored.RestoreState([]byte(`{"Markets":{"^AAPL":{"CaughtUp":true}}}`))
+	assert.NoError(t, err)
+
+	// Ensure malformed state errors.
+	err = restored.RestoreState([]byte("{"))
+	assert.Error(t, err)
+}
+
+func TestRestoredStateCatchUp(t *testing.T) {
+	market := "^GSPC"
+	now := testTime(t)
+
+	tests := []struct {
+		name      string
+		offset    time.Duration
+		caughtUp  bool
+		restored  bool
+		wantStart func(open, snapshot time.Time) time.Time
+	}{
+		{
+			name:     "fresh caught up snapshot shortens the catch up",
+			offset:   time.Hour,
+			caughtUp: true,
+			restored: true,
+			wantStart: func(open, snapshot time.Time) time.Time {
+				return snapshot
+			},
+		},
+		{
+			name:     "fresh snapshot not caught up catches up from the session open",
+			offset:   time.Hour,
+			caughtUp: false,
+			restored: false,
+			wantStart: func(open, snapshot time.Time) time.Time {
+				return open
+			},
+		},
+		{
+			name:     "stale snapshot catches up from the session open",
+			offset:   -time.Minute,
+			caughtUp: true,
+			restored: false,
+			wantStart: func(open, snapshot time.Time) time.Time {
+				return open
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mgr, catchUpSignals, _ := setupManager(t, market, now, false)
+			open, err := mgr.markets[market].sessionSnapshot.FetchLastSessionOpen()
+			assert.NoError(t, err)
+
+			snapshot := open.Add(test.offset)
+			data, err := json.Marshal(&ManagerState{
+				Markets:      map[string]*MarketState{market: {CaughtUp: test.caughtUp}},
+				SnapshotTime: snapshot,
+			})
+			assert.NoError(t, err)
+
+			err = mgr.RestoreState(data)
+			assert.NoError(t, err)
+
+			// Ensure only fresh state restores the caught up status.
+			caughtUp, err := mgr.FetchCaughtUpState(market)
+			assert.NoError(t, err)
+			assert.Equal(t, caughtUp, test.restored)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			go func() {
+				mgr.Run(ctx)
+				close(done)
+			}()
+
+			// Ensure running the manager catches up from the expected start.
+			sig := <-catchUpSignals
+			assert.Equal(t, sig.Market, market)
+			assert.True(t, sig.Start.Equal(test.wantStart(open, snapshot)))
+
+			cancel()
+			<-done
+
+			// Ensure the restored snapshot time only shortens the first catch up.
+			err = mgr.catchUpMarket(mgr.markets[market])
+			assert.NoError(t, err)
+			sig = <-catchUpSignals
+			assert.True(t, sig.Start.Equal(open))
+		})
+	}
+}
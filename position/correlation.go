@@ -0,0 +1,67 @@
+package position
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CorrelationConfig represents the grouping of correlated markets and the same direction
+// exposure allowed within each group.
+type CorrelationConfig struct {
+	// Groups is the correlation group of markets, keyed by market. Markets without a group
+	// are not limited.
+	Groups map[string]string
+	// MaxSameDirection is the maximum number of positions in the same direction open across
+	// the markets of a group. One position is allowed if zero.
+	MaxSameDirection int
+}
+
+// Validate asserts the config sane inputs.
+func (cfg *CorrelationConfig) Validate() error {
+	var errs error
+
+	if len(cfg.Groups) == 0 {
+		errs = errors.Join(errs, fmt.Errorf("no correlation groups provided"))
+	}
+	for market, group := range cfg.Groups {
+		if group == "" {
+			errs = errors.Join(errs, fmt.Errorf("correlation group of %s cannot be empty", market))
+		}
+	}
+	if cfg.MaxSameDirection < 0 {
+		errs = errors.Join(errs, fmt.Errorf("maximum same direction positions cannot be negative"))
+	}
+
+	return errs
+}
+
+// maxSameDirection returns the maximum number of same direction positions of a group.
+func (cfg *CorrelationConfig) maxSameDirection() int {
+	if cfg.MaxSameDirection == 0 {
+		return 1
+	}
+
+	return cfg.MaxSameDirection
+}
+
+// ParseCorrelationGroups parses the correlation group of markets from the provided entries of
+// the form market=group.
+func ParseCorrelationGroups(entries []string) (map[string]string, error) {
+	groups := make(map[string]string, len(entries))
+	for idx := range entries {
+		market, group, ok := strings.Cut(entries[idx], "=")
+		if !ok || market == "" || group == "" {
+			return nil, fmt.Errorf("invalid correlation group entry provided: %s", entries[idx])
+		}
+
+		_, ok = groups[market]
+		if ok {
+			return nil, fmt.Errorf("multiple correlation groups provided for %s", market)
+		}
+
+		groups[market] = group
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,111 @@
+package position
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestParseCorrelationGroups(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{"no entries", nil, map[string]string{}, false},
+		{"grouped markets", []string{"^GSPC=indices", "^IXIC=indices", "EURUSD=fx"},
+			map[string]string{"^GSPC": "indices", "^IXIC": "indices", "EURUSD": "fx"}, false},
+		{"missing group", []string{"^GSPC="}, nil, true},
+		{"missing separator", []string{"^GSPC"}, nil, true},
+		{"multiple groups for a market", []string{"^GSPC=indices", "^GSPC=tech"}, nil, true},
+	}
+
+	for _, test := range tests {
+		groups, err := ParseCorrelationGroups(test.entries)
+		if test.wantErr {
+			assert.Error(t, err)
+			continue
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, groups, test.want)
+	}
+}
+
+func TestCorrelationConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *CorrelationConfig
+		wantErr bool
+	}{
+		{"valid config", &CorrelationConfig{Groups: map[string]string{"^GSPC": "indices"}, MaxSameDirection: 2}, false},
+		{"default maximum", &CorrelationConfig{Groups: map[string]string{"^GSPC": "indices"}}, false},
+		{"no groups", &CorrelationConfig{MaxSameDirection: 1}, true},
+		{"empty group", &CorrelationConfig{Groups: map[string]string{"^GSPC": ""}}, true},
+		{"negative maximum", &CorrelationConfig{Groups: map[string]string{"^GSPC": "indices"}, MaxSameDirection: -1}, true},
+	}
+
+	for _, test := range tests {
+		err := test.cfg.Validate()
+		if test.wantErr {
+			assert.Error(t, err)
+			continue
+		}
+
+		assert.NoError(t, err)
+	}
+}
+
+func TestHandleCorrelatedEntries(t *testing.T) {
+	gspc := "^GSPC"
+	mgr, notifyMsgs, _ := setupManager(t, gspc)
+
+	ixic := "^IXIC"
+	err := mgr.AddMarket(ixic)
+	assert.NoError(t, err)
+
+	mgr.cfg.Correlation = &CorrelationConfig{
+		Groups: map[string]string{gspc: "indices", ixic: "indices"},
+	}
+
+	entrySignal := func(market string, direction shared.Direction) *shared.EntrySignal {
+		stopLoss := float64(8)
+		if direction == shared.Short {
+			stopLoss = float64(12)
+		}
+
+		return &shared.EntrySignal{
+			Market:    market,
+			Timeframe: shared.FiveMinute,
+			Direction: direction,
+			Price:     float64(10),
+			Reasons:   []shared.Reason{shared.BullishEngulfing, shared.StrongVolume},
+			StopLoss:  stopLoss,
+			Status:    make(chan shared.StatusCode, 1),
+		}
+	}
+
+	// Ensure the first entry of the group is taken.
+	err = mgr.handleEntrySignal(entrySignal(gspc, shared.Long))
+	assert.NoError(t, err)
+	msg := <-notifyMsgs
+	assert.True(t, strings.Contains(msg, "Created new long position"))
+
+	// Ensure a second same direction entry within the group is blocked.
+	err = mgr.handleEntrySignal(entrySignal(ixic, shared.Long))
+	assert.NoError(t, err)
+	msg = <-notifyMsgs
+	assert.True(t, strings.Contains(msg, "Rejected long entry for ^IXIC"))
+	assert.True(t, strings.Contains(msg, "indices correlation group"))
+	assert.Equal(t, mgr.correlatedPositions("indices", shared.Long), 1)
+
+	// Ensure an opposite direction entry within the group is allowed.
+	err = mgr.handleEntrySignal(entrySignal(ixic, shared.Short))
+	assert.NoError(t, err)
+	msg = <-notifyMsgs
+	assert.True(t, strings.Contains(msg, "Created new short position"))
+	assert.Equal(t, mgr.correlatedPositions("indices", shared.Short), 1)
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/dnldd/entry/shared"
 	"github.com/go-co-op/gocron"
 	"github.com/rs/zerolog"
+	"go.uber.org/atomic"
 )
 
 const (
@@ -23,12 +26,46 @@ type ManagerConfig struct {
 	Markets []string
 	// Notify sends the provided message.
 	Notify func(message string)
+	// Throttle represents how notifications are throttled. Notifications are delivered
+	// immediately if nil.
+	Throttle *ThrottleConfig
 	// Backtest is the backtesting flag.
 	Backtest bool
+	// Sizing represents the position sizing configuration. Positions are not sized if nil.
+	Sizing *SizingConfig
+	// Instruments is the contract specification of markets, keyed by market. Markets
+	// without an instrument are valued one-to-one in dollars.
+	Instruments map[string]shared.Instrument
+	// DrainGracePeriod is the maximum time spent handling buffered and in-flight signals on
+	// shutdown. The default grace period is used if zero.
+	DrainGracePeriod time.Duration
+	// TrailingStop represents the trailing stop configuration. Stops are not trailed if nil.
+	TrailingStop *TrailingStopConfig
+	// MaxOpenPositions is the maximum number of positions open across all markets, entry
+	// signals are rejected once reached. Open positions are not limited if zero.
+	MaxOpenPositions int
+	// Correlation represents the correlation groups same direction exposure is limited
+	// within. Exposure is not limited by correlation if nil.
+	Correlation *CorrelationConfig
+	// Slippage represents the spread and slippage assumed on entry and exit fills. It is
+	// only intended for simulated fills, fills are taken at the signal price if nil.
+	Slippage *SlippageConfig
 	// PersistClosedPosition persists the provided closed position to the database.
 	PersistClosedPosition func(position *Position) error
+	// RecordOpenedPosition records the provided newly opened position. Opened positions are
+	// not recorded if nil.
+	RecordOpenedPosition func(position *Position)
+	// Store persists the open positions of markets as they change and restores them when
+	// markets are created. An in-memory store is used if nil.
+	Store Store
+	// RecordStopOut records the stop out of a position entered at the level of the provided
+	// market and price. Stop outs are not recorded if nil.
+	RecordStopOut func(market string, levelPrice float64, stoppedOn time.Time)
 	// JobScheduler represents the job scheduler.
 	JobScheduler *gocron.Scheduler
+	// Clock is the source of the current time, backtests supply the replay clock. The wall
+	// clock is used if nil.
+	Clock shared.Clock
 	// Logger represents the application logger.
 	Logger *zerolog.Logger
 }
@@ -43,6 +80,48 @@ func (cfg *ManagerConfig) Validate() error {
 	if cfg.Notify == nil {
 		errs = errors.Join(errs, fmt.Errorf("notify function cannot be nil"))
 	}
+	if cfg.Throttle != nil {
+		err := cfg.Throttle.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating throttle config: %v", err))
+		}
+	}
+	if cfg.Sizing != nil {
+		err := cfg.Sizing.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating sizing config: %v", err))
+		}
+	}
+	for market, instrument := range cfg.Instruments {
+		err := instrument.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating %s instrument: %v", market, err))
+		}
+	}
+	if cfg.DrainGracePeriod < 0 {
+		errs = errors.Join(errs, fmt.Errorf("drain grace period cannot be negative"))
+	}
+	if cfg.TrailingStop != nil {
+		err := cfg.TrailingStop.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating trailing stop config: %v", err))
+		}
+	}
+	if cfg.MaxOpenPositions < 0 {
+		errs = errors.Join(errs, fmt.Errorf("maximum open positions cannot be negative"))
+	}
+	if cfg.Correlation != nil {
+		err := cfg.Correlation.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating correlation config: %v", err))
+		}
+	}
+	if cfg.Slippage != nil {
+		err := cfg.Slippage.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating slippage config: %v", err))
+		}
+	}
 	if cfg.PersistClosedPosition == nil {
 		errs = errors.Join(errs, fmt.Errorf("persist closed position function cannot be nil"))
 	}
@@ -59,11 +138,23 @@ func (cfg *ManagerConfig) Validate() error {
 // Manager manages positions through their lifecycles.
 type Manager struct {
 	cfg                *ManagerConfig
+	drops              *shared.DropCounter
+	store              Store
 	markets            map[string]*Market
+	marketsMtx         sync.RWMutex
 	entrySignals       chan shared.EntrySignal
 	exitSignals        chan shared.ExitSignal
 	marketSkewRequests chan shared.MarketSkewRequest
+	updateSignals      chan shared.Candlestick
+	sizer              *Sizer
+	throttle           *Throttle
+	recent             *recentSignals
+	entryMtx           sync.Mutex
 	workers            chan struct{}
+	inflight           sync.WaitGroup
+	// runIterations counts the iterations of the run loop, an idle run loop blocks without
+	// advancing it.
+	runIterations atomic.Uint64
 }
 
 // NewPositionManager initializes a new position manager.
@@ -73,31 +164,165 @@ func NewPositionManager(cfg *ManagerConfig) (*Manager, error) {
 		return nil, fmt.Errorf("validating position manager config: %v", err)
 	}
 
+	mgr := &Manager{
+		cfg:                cfg,
+		drops:              shared.NewDropCounter(),
+		markets:            make(map[string]*Market),
+		entrySignals:       make(chan shared.EntrySignal, bufferSize),
+		exitSignals:        make(chan shared.ExitSignal, bufferSize),
+		marketSkewRequests: make(chan shared.MarketSkewRequest, bufferSize),
+		updateSignals:      make(chan shared.Candlestick, bufferSize),
+		workers:            make(chan struct{}, maxWorkers),
+		recent:             newRecentSignals(),
+		store:              cfg.Store,
+	}
+	if mgr.store == nil {
+		mgr.store = NewMemoryStore()
+	}
+
+	if cfg.Sizing != nil {
+		mgr.sizer, err = NewSizer(cfg.Sizing)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	throttleCfg := cfg.Throttle
+	if throttleCfg == nil {
+		throttleCfg = &ThrottleConfig{}
+	}
+	mgr.throttle, err = NewThrottle(throttleCfg, cfg.Notify, cfg.Clock)
+	if err != nil {
+		return nil, err
+	}
+
+	err = mgr.scheduleDigest()
+	if err != nil {
+		return nil, err
+	}
+
 	// Create markets for position tracking.
-	markets := make(map[string]*Market)
 	for idx := range cfg.Markets {
 		market := cfg.Markets[idx]
-		mCfg := &MarketConfig{
-			Market:       market,
-			JobScheduler: cfg.JobScheduler,
-			Logger:       cfg.Logger,
+		mkt, err := mgr.newMarket(market)
+		if err != nil {
+			return nil, err
 		}
-		mkt, err := NewMarket(mCfg)
+
+		mgr.markets[market] = mkt
+	}
+
+	return mgr, nil
+}
+
+// newMarket creates a positions market using the manager's configuration.
+func (m *Manager) newMarket(market string) (*Market, error) {
+	mCfg := &MarketConfig{
+		Market:       market,
+		Instrument:   m.cfg.Instruments[market],
+		TrailingStop: m.cfg.TrailingStop,
+		JobScheduler: m.cfg.JobScheduler,
+		Clock:        m.cfg.Clock,
+		Logger:       m.cfg.Logger,
+	}
+	mkt, err := NewMarket(mCfg)
+	if err != nil {
+		return nil, fmt.Errorf("creating new positions market %s: %v", market, err)
+	}
+
+	// Restore the open positions of the market so its skew holds across restarts.
+	positions, err := m.store.LoadOpenPositions(market)
+	if err != nil {
+		return nil, fmt.Errorf("loading %s open positions: %v", market, err)
+	}
+	if len(positions) > 0 {
+		err = mkt.restoreOpenPositions(positions)
 		if err != nil {
-			return nil, fmt.Errorf("creating new positions market %s: %v", market, err)
+			return nil, fmt.Errorf("restoring %s open positions: %v", market, err)
 		}
 
-		markets[market] = mkt
+		m.cfg.Logger.Info().Msgf("restored %d open positions for %s", len(positions), market)
 	}
 
-	return &Manager{
-		cfg:                cfg,
-		markets:            markets,
-		entrySignals:       make(chan shared.EntrySignal, bufferSize),
-		exitSignals:        make(chan shared.ExitSignal, bufferSize),
-		marketSkewRequests: make(chan shared.MarketSkewRequest, bufferSize),
-		workers:            make(chan struct{}, maxWorkers),
-	}, nil
+	return mkt, nil
+}
+
+// AddMarket starts tracking positions for the provided market.
+func (m *Manager) AddMarket(market string) error {
+	m.marketsMtx.Lock()
+	defer m.marketsMtx.Unlock()
+
+	_, ok := m.markets[market]
+	if ok {
+		return fmt.Errorf("market %s is already tracked", market)
+	}
+
+	mkt, err := m.newMarket(market)
+	if err != nil {
+		return err
+	}
+
+	m.markets[market] = mkt
+
+	return nil
+}
+
+// RemoveMarket stops tracking positions for the provided market. Markets with open
+// positions cannot be removed.
+func (m *Manager) RemoveMarket(market string) error {
+	m.marketsMtx.Lock()
+	defer m.marketsMtx.Unlock()
+
+	mkt, ok := m.markets[market]
+	if !ok {
+		return fmt.Errorf("no position market found with id %s", market)
+	}
+
+	if mkt.OpenPositions() > 0 {
+		return fmt.Errorf("%s market has open positions", market)
+	}
+
+	delete(m.markets, market)
+	m.recent.remove(market)
+
+	return mkt.RemoveJobs()
+}
+
+// HasOpenPositions returns whether the provided market has open positions.
+func (m *Manager) HasOpenPositions(market string) (bool, error) {
+	mkt, ok := m.fetchMarket(market)
+	if !ok {
+		return false, fmt.Errorf("no position market found with id %s", market)
+	}
+
+	return mkt.OpenPositions() > 0, nil
+}
+
+// LatestOpenLeg returns the direction of the most recently opened leg of the provided market
+// that is still open. It returns false if the market has no open legs.
+func (m *Manager) LatestOpenLeg(market string) (shared.Direction, bool, error) {
+	mkt, ok := m.fetchMarket(market)
+	if !ok {
+		return 0, false, fmt.Errorf("no position market found with id %s", market)
+	}
+
+	direction, ok := mkt.LatestLeg()
+	return direction, ok, nil
+}
+
+// fetchMarket returns the tracked positions market with the provided name.
+func (m *Manager) fetchMarket(market string) (*Market, bool) {
+	m.marketsMtx.RLock()
+	defer m.marketsMtx.RUnlock()
+
+	mkt, ok := m.markets[market]
+	return mkt, ok
+}
+
+// Dropped returns the number of signals dropped by the manager's channels at capacity,
+// keyed by channel.
+func (m *Manager) Dropped() map[string]uint64 {
+	return m.drops.Counts()
 }
 
 // SendEntrySignal relays the provided entry signal for processing.
@@ -106,8 +331,7 @@ func (m *Manager) SendEntrySignal(signal shared.EntrySignal) {
 	case m.entrySignals <- signal:
 		// do nothing.
 	default:
-		m.cfg.Logger.Error().Msgf("entry signal channel at capacity: %d/%d",
-			len(m.entrySignals), bufferSize)
+		m.drops.Drop("entry signal")
 	}
 }
 
@@ -117,8 +341,7 @@ func (m *Manager) SendExitSignal(signal shared.ExitSignal) {
 	case m.exitSignals <- signal:
 		// do nothing.
 	default:
-		m.cfg.Logger.Error().Msgf("exit signal channel at capacity: %d/%d",
-			len(m.exitSignals), bufferSize)
+		m.drops.Drop("exit signal")
 	}
 }
 
@@ -128,14 +351,35 @@ func (m *Manager) SendMarketSkewRequest(req shared.MarketSkewRequest) {
 	case m.marketSkewRequests <- req:
 		// do nothing.
 	default:
-		m.cfg.Logger.Error().Msgf("market skew request channel at capacity: %d/%d",
-			len(m.marketSkewRequests), bufferSize)
+		m.drops.Drop("market skew request")
+	}
+}
+
+// SendMarketUpdate relays the provided market update for processing.
+func (m *Manager) SendMarketUpdate(candle shared.Candlestick) {
+	select {
+	case m.updateSignals <- candle:
+		// do nothing.
+	default:
+		m.drops.Drop("market update")
 	}
 }
 
 // handleEntrySignal processes the provided entry signal.
 func (m *Manager) handleEntrySignal(signal *shared.EntrySignal) error {
+	outcome := SignalFailed
 	defer func() {
+		m.recent.record(SignalRecord{
+			CorrelationID: signal.CorrelationID,
+			Market:        signal.Market,
+			Kind:          EntryKind,
+			Direction:     signal.Direction,
+			Price:         signal.Price,
+			Reasons:       signal.Reasons,
+			Confluence:    signal.Confluence,
+			Outcome:       outcome,
+			CreatedOn:     signal.CreatedOn,
+		})
 		signal.Status <- shared.Processed
 	}()
 
@@ -144,54 +388,215 @@ func (m *Manager) handleEntrySignal(signal *shared.EntrySignal) error {
 		return fmt.Errorf("creating new position: %v", err)
 	}
 
-	mkt, ok := m.markets[position.Market]
+	if m.cfg.Slippage != nil {
+		position.EntryPrice = m.cfg.Slippage.EntryFill(position.Direction, position.EntryPrice)
+	}
+
+	mkt, ok := m.fetchMarket(position.Market)
 	if !ok {
 		return fmt.Errorf("no position market found with id %s", position.Market)
 	}
 
+	if m.sizer != nil {
+		instrument := m.cfg.Instruments[position.Market]
+		position.Size = m.sizer.SizeFor(position.StopLossPointsRange, &instrument)
+	}
+
+	// Entries are serialized so concurrent entries cannot exceed the exposure limits.
+	m.entryMtx.Lock()
+	defer m.entryMtx.Unlock()
+
+	if m.cfg.MaxOpenPositions > 0 {
+		open := m.openPositions()
+		if open >= m.cfg.MaxOpenPositions {
+			msg := fmt.Sprintf("Rejected %s entry for %s @ %.2f, %d of a maximum %d positions open",
+				position.Direction.String(), position.Market, position.EntryPrice, open,
+				m.cfg.MaxOpenPositions)
+			m.cfg.Logger.Info().Str(shared.CorrelationIDKey, signal.CorrelationID).Msg(msg)
+			m.throttle.Notify(position.Market, msg, false)
+			outcome = SignalRejected
+			return nil
+		}
+	}
+
+	if m.cfg.Correlation != nil {
+		group, ok := m.cfg.Correlation.Groups[position.Market]
+		if ok {
+			exposure := m.correlatedPositions(group, position.Direction)
+			if exposure >= m.cfg.Correlation.maxSameDirection() {
+				msg := fmt.Sprintf("Rejected %s entry for %s @ %.2f, %d %s positions of a maximum %d open in the %s correlation group",
+					position.Direction.String(), position.Market, position.EntryPrice, exposure,
+					position.Direction.String(), m.cfg.Correlation.maxSameDirection(), group)
+				m.cfg.Logger.Info().Str(shared.CorrelationIDKey, signal.CorrelationID).Msg(msg)
+				m.throttle.Notify(position.Market, msg, false)
+				outcome = SignalRejected
+				return nil
+			}
+		}
+	}
+
 	err = mkt.AddPosition(position)
 	if err != nil {
 		return fmt.Errorf("adding %s position: %v", position.Market, err)
 	}
+	m.persistOpenPositions(mkt)
+
+	outcome = SignalOpened
+	if m.cfg.RecordOpenedPosition != nil {
+		m.cfg.RecordOpenedPosition(position)
+	}
 
 	// Notify of the newly created position.
-	msg := fmt.Sprintf("Created new %s position (%s) for %s @ %.2f with stoploss @ %.2f (%.2f points)",
+	msg := fmt.Sprintf("Created new %s position (%s) for %s @ %.2f with stoploss @ %.2f (%.2f points), confidence %.0f/100, %s",
 		position.Direction.String(), position.ID, position.Market, position.EntryPrice,
-		position.StopLoss, signal.StopLossPointsRange)
-	m.cfg.Logger.Info().Msg(msg)
-	m.cfg.Notify(msg)
+		position.StopLoss, signal.StopLossPointsRange, signal.WeightedConfidence, signal.Explain())
+	if m.sizer != nil {
+		msg = fmt.Sprintf("%s, size %.2f", msg, position.Size)
+	}
+	if signal.Bracket != nil {
+		msg = fmt.Sprintf("%s, %s", msg, signal.Bracket.String())
+	}
+	m.cfg.Logger.Info().Str(shared.CorrelationIDKey, signal.CorrelationID).Msg(msg)
+	m.throttle.Notify(position.Market, msg, false)
 
 	return nil
 }
 
+// openPositions returns the number of positions open across all markets.
+func (m *Manager) openPositions() int {
+	m.marketsMtx.RLock()
+	defer m.marketsMtx.RUnlock()
+
+	var open int
+	for _, mkt := range m.markets {
+		open += mkt.OpenPositions()
+	}
+
+	return open
+}
+
+// correlatedPositions returns the number of positions in the provided direction open across
+// the markets of the provided correlation group.
+func (m *Manager) correlatedPositions(group string, direction shared.Direction) int {
+	m.marketsMtx.RLock()
+	defer m.marketsMtx.RUnlock()
+
+	var exposure int
+	for market, mkt := range m.markets {
+		if m.cfg.Correlation.Groups[market] != group {
+			continue
+		}
+
+		exposure += mkt.OpenLegs(direction)
+	}
+
+	return exposure
+}
+
 // handleExitSignal processes the provided exit signal.
 func (m *Manager) handleExitSignal(signal *shared.ExitSignal) error {
+	outcome := SignalFailed
 	defer func() {
+		m.recent.record(SignalRecord{
+			CorrelationID: signal.CorrelationID,
+			Market:        signal.Market,
+			Kind:          ExitKind,
+			Direction:     signal.Direction,
+			Price:         signal.Price,
+			Reasons:       signal.Reasons,
+			Confluence:    signal.Confluence,
+			Outcome:       outcome,
+			CreatedOn:     signal.CreatedOn,
+		})
 		signal.Status <- shared.Processed
 	}()
 
-	mkt, ok := m.markets[signal.Market]
+	mkt, ok := m.fetchMarket(signal.Market)
 	if !ok {
 		return fmt.Errorf("no position market found with id %s", signal.Market)
 	}
 
-	closedPositions, err := mkt.ClosePositions(signal)
+	exit := signal
+	if m.cfg.Slippage != nil {
+		slipped := *signal
+		slipped.Price = m.cfg.Slippage.ExitFill(signal.Direction, signal.Price)
+		exit = &slipped
+	}
+
+	closedPositions, err := mkt.ClosePositions(exit)
 	if err != nil {
 		return fmt.Errorf("closing %s position for %s: %v", signal.Direction.String(),
 			signal.Market, err)
 	}
 
+	outcome = SignalUnmatched
+	if len(closedPositions) > 0 {
+		outcome = SignalClosed
+		m.persistOpenPositions(mkt)
+	}
+
 	for idx := range closedPositions {
 		pos := closedPositions[idx]
 
-		m.cfg.PersistClosedPosition(pos)
+		if m.sizer != nil {
+			m.sizer.Record(pos.PNLPercent > 0)
+		}
+
+		err := m.cfg.PersistClosedPosition(pos)
+		if err != nil {
+			m.cfg.Logger.Error().Str(shared.CorrelationIDKey, signal.CorrelationID).Msgf("persisting closed position %s: %v", pos.ID, err)
+		}
+
+		if pos.Status == StoppedOut && pos.LevelPrice != 0 && m.cfg.RecordStopOut != nil {
+			m.cfg.RecordStopOut(pos.Market, pos.LevelPrice, pos.ClosedOn)
+		}
 
 		// Notify discord session about the closed position.
 		msg := fmt.Sprintf("Closed %s position (%s) for %s @ %.2f with stoploss @ %.2f (%.2f points), PNL %.2f",
 			pos.Direction.String(), pos.ID, pos.Market, pos.ExitPrice, pos.StopLoss,
 			pos.StopLossPointsRange, pos.PNLPercent)
+		m.cfg.Logger.Info().Str(shared.CorrelationIDKey, signal.CorrelationID).Msg(msg)
+
+		// Stopped out positions are critical and bypass the notification throttle.
+		m.throttle.Notify(pos.Market, msg, pos.Status == StoppedOut)
+	}
+
+	return nil
+}
+
+// handleUpdateSignal updates the open positions of the provided candle's market and trails
+// their stops.
+func (m *Manager) handleUpdateSignal(candle *shared.Candlestick) error {
+	defer func() {
+		candle.Status <- shared.Processed
+	}()
+
+	mkt, ok := m.fetchMarket(candle.Market)
+	if !ok {
+		return fmt.Errorf("no position market found with id %s", candle.Market)
+	}
+
+	err := mkt.Update(candle)
+	if err != nil {
+		return fmt.Errorf("updating %s positions: %v", candle.Market, err)
+	}
+
+	trailed, err := mkt.TrailStops(candle)
+	if err != nil {
+		return fmt.Errorf("trailing %s stops: %v", candle.Market, err)
+	}
+	if len(trailed) > 0 {
+		m.persistOpenPositions(mkt)
+	}
+
+	for idx := range trailed {
+		pos := trailed[idx].Position
+
+		msg := fmt.Sprintf("Trailed stoploss of %s position (%s) for %s from %.2f to %.2f @ %.2f",
+			pos.Direction.String(), pos.ID, pos.Market, trailed[idx].Previous, pos.StopLoss,
+			candle.Close)
 		m.cfg.Logger.Info().Msg(msg)
-		m.cfg.Notify(msg)
+		m.throttle.Notify(pos.Market, msg, false)
 	}
 
 	return nil
@@ -199,7 +604,7 @@ func (m *Manager) handleExitSignal(signal *shared.ExitSignal) error {
 
 // handleMarketSkewRequest processes the provided market skew request.
 func (m *Manager) handleMarketSkewRequest(req *shared.MarketSkewRequest) error {
-	mkt, ok := m.markets[req.Market]
+	mkt, ok := m.fetchMarket(req.Market)
 	if !ok {
 		return fmt.Errorf("no position market found with id %s", req.Market)
 	}
@@ -211,6 +616,9 @@ func (m *Manager) handleMarketSkewRequest(req *shared.MarketSkewRequest) error {
 
 // PersistPositionsCSV persists positions of all tracked markets to a csv file.
 func (m *Manager) PersistPositionsCSV() error {
+	m.marketsMtx.RLock()
+	defer m.marketsMtx.RUnlock()
+
 	for k := range m.markets {
 		mkt := m.markets[k]
 
@@ -224,53 +632,115 @@ func (m *Manager) PersistPositionsCSV() error {
 	return nil
 }
 
+// dispatch hands the provided handler to a worker once one is available. It returns false if
+// the provided context is done before a worker becomes available.
+func (m *Manager) dispatch(ctx context.Context, handler func() error) bool {
+	select {
+	case m.workers <- struct{}{}:
+	case <-ctx.Done():
+		return false
+	}
+
+	m.inflight.Add(1)
+	go func() {
+		defer func() {
+			<-m.workers
+			m.inflight.Done()
+		}()
+
+		err := handler()
+		if err != nil {
+			m.cfg.Logger.Error().Err(err).Send()
+		}
+	}()
+
+	return true
+}
+
+// drain dispatches the signals and requests buffered when the manager is shut down and waits
+// for in-flight handlers to complete, bounded by the drain grace period.
+func (m *Manager) drain(ctx context.Context) {
+	ctx, cancel := shared.NewDrainContext(ctx, m.cfg.DrainGracePeriod)
+	defer cancel()
+
+	for {
+		dispatched := true
+		select {
+		case signal := <-m.entrySignals:
+			dispatched = m.dispatch(ctx, func() error {
+				return m.handleEntrySignal(&signal)
+			})
+		case signal := <-m.exitSignals:
+			dispatched = m.dispatch(ctx, func() error {
+				return m.handleExitSignal(&signal)
+			})
+		case req := <-m.marketSkewRequests:
+			dispatched = m.dispatch(ctx, func() error {
+				return m.handleMarketSkewRequest(&req)
+			})
+		case candle := <-m.updateSignals:
+			dispatched = m.dispatch(ctx, func() error {
+				return m.handleUpdateSignal(&candle)
+			})
+		default:
+			if !shared.Await(ctx, &m.inflight) {
+				m.cfg.Logger.Warn().Msg("drain grace period elapsed with in-flight signals")
+			}
+			return
+		}
+
+		if !dispatched {
+			m.cfg.Logger.Warn().Msg("drain grace period elapsed with buffered signals")
+			return
+		}
+	}
+}
+
 // Run manages the lifecycle processes of the position manager.
 func (m *Manager) Run(ctx context.Context) {
+	// Handlers are dispatched without a deadline while running, the manager drains buffered
+	// signals before shutting down.
+	runCtx := context.WithoutCancel(ctx)
+
 	for {
+		m.runIterations.Inc()
+
 		select {
 		case <-ctx.Done():
+			m.drain(ctx)
+			m.throttle.Flush()
+
 			if !m.cfg.Backtest {
 				return
 			}
 
 			// Persist tracked positions for markets to csv files when backtesting.
+			m.marketsMtx.RLock()
 			for k := range m.markets {
 				_, err := m.markets[k].PersistPositionsCSV()
 				if err != nil {
 					m.cfg.Logger.Error().Err(err).Send()
 				}
 			}
+			m.marketsMtx.RUnlock()
 
 			return
 		case signal := <-m.entrySignals:
-			m.workers <- struct{}{}
-			go func(signal *shared.EntrySignal) {
-				err := m.handleEntrySignal(signal)
-				if err != nil {
-					m.cfg.Logger.Error().Err(err).Send()
-				}
-				<-m.workers
-			}(&signal)
+			m.dispatch(runCtx, func() error {
+				return m.handleEntrySignal(&signal)
+			})
 		case signal := <-m.exitSignals:
-			m.workers <- struct{}{}
-			go func(signal *shared.ExitSignal) {
-				err := m.handleExitSignal(signal)
-				if err != nil {
-					m.cfg.Logger.Error().Err(err).Send()
-				}
-				<-m.workers
-			}(&signal)
+			m.dispatch(runCtx, func() error {
+				return m.handleExitSignal(&signal)
+			})
 		case req := <-m.marketSkewRequests:
-			m.workers <- struct{}{}
-			go func(req *shared.MarketSkewRequest) {
-				err := m.handleMarketSkewRequest(req)
-				if err != nil {
-					m.cfg.Logger.Error().Err(err).Send()
-				}
-				<-m.workers
-			}(&req)
-		default:
-			// fallthrough
+			m.dispatch(runCtx, func() error {
+				return m.handleMarketSkewRequest(&req)
+			})
+		case candle := <-m.updateSignals:
+			m.dispatch(runCtx, func() error {
+				return m.handleUpdateSignal(&candle)
+			})
 		}
 	}
 }
@@ -72,6 +72,18 @@ func TestPositionManagerConfigValidate(t *testing.T) {
 			wantErr:     true,
 			errContains: []string{"logger cannot be nil"},
 		},
+		{
+			name:        "negative MaxOpenPositions",
+			modify:      func(cfg *ManagerConfig) { cfg.MaxOpenPositions = -1 },
+			wantErr:     true,
+			errContains: []string{"maximum open positions cannot be negative"},
+		},
+		{
+			name:        "negative DrainGracePeriod",
+			modify:      func(cfg *ManagerConfig) { cfg.DrainGracePeriod = -time.Second },
+			wantErr:     true,
+			errContains: []string{"drain grace period cannot be negative"},
+		},
 		{
 			name: "multiple missing fields",
 			modify: func(cfg *ManagerConfig) {
@@ -159,6 +171,16 @@ func TestManager(t *testing.T) {
 	mgr.SendEntrySignal(entrySignal)
 	msg := <-notifyMsgs
 	assert.True(t, strings.Contains(msg, "with stoploss"))
+	assert.False(t, strings.Contains(msg, "oco bracket"))
+
+	// Ensure the bracket of a bracketed entry signal is included in the notification.
+	bracket, err := shared.NewBracket(shared.Long, float64(10), float64(8), 2)
+	assert.NoError(t, err)
+	entrySignal.Bracket = bracket
+	entrySignal.Status = make(chan shared.StatusCode, 1)
+	mgr.SendEntrySignal(entrySignal)
+	msg = <-notifyMsgs
+	assert.True(t, strings.Contains(msg, "oco bracket: entry @ 10.00, stoploss @ 8.00, target @ 14.00"))
 
 	// Ensure the position manager can process exit signals.
 	exitSignal := shared.ExitSignal{
@@ -187,6 +209,142 @@ func TestManager(t *testing.T) {
 	<-done
 }
 
+func TestManagerDrain(t *testing.T) {
+	market := "^GSPC"
+	mgr, notifyMsgs, _ := setupManager(t, market)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	signals := 5
+	for range signals {
+		mgr.SendEntrySignal(shared.EntrySignal{
+			Market:    market,
+			Timeframe: shared.FiveMinute,
+			Direction: shared.Long,
+			Price:     float64(10),
+			Reasons:   []shared.Reason{shared.BullishEngulfing, shared.StrongVolume},
+			StopLoss:  float64(8),
+			Status:    make(chan shared.StatusCode, 1),
+		})
+	}
+
+	// Ensure entry signals buffered at shutdown are processed before the manager returns.
+	mgr.Run(ctx)
+	assert.Equal(t, len(notifyMsgs), signals)
+}
+
+func TestManagerAddRemoveMarket(t *testing.T) {
+	gspc := "^GSPC"
+	ixic := "^IXIC"
+	mgr, _, _ := setupManager(t, gspc)
+
+	jobs := mgr.cfg.JobScheduler.Len()
+
+	// Ensure a market can be added at runtime.
+	err := mgr.AddMarket(ixic)
+	assert.NoError(t, err)
+	assert.Equal(t, mgr.cfg.JobScheduler.Len(), jobs*2)
+
+	// Ensure an already tracked market cannot be added.
+	err = mgr.AddMarket(ixic)
+	assert.Error(t, err)
+
+	// Ensure the added market can track positions.
+	entrySignal := shared.EntrySignal{
+		Market:    ixic,
+		Timeframe: shared.FiveMinute,
+		Direction: shared.Long,
+		Price:     float64(10),
+		Reasons:   []shared.Reason{shared.BullishEngulfing, shared.StrongVolume},
+		StopLoss:  float64(8),
+		Status:    make(chan shared.StatusCode, 1),
+	}
+
+	err = mgr.handleEntrySignal(&entrySignal)
+	assert.NoError(t, err)
+
+	open, err := mgr.HasOpenPositions(ixic)
+	assert.NoError(t, err)
+	assert.True(t, open)
+
+	// Ensure a market with open positions cannot be removed.
+	err = mgr.RemoveMarket(ixic)
+	assert.Error(t, err)
+
+	now, _, err := shared.NewYorkTime()
+	assert.NoError(t, err)
+
+	exitSignal := shared.ExitSignal{
+		Market:    ixic,
+		Timeframe: shared.FiveMinute,
+		Direction: shared.Long,
+		Price:     float64(15),
+		Reasons:   []shared.Reason{shared.BearishEngulfing, shared.StrongVolume},
+		Status:    make(chan shared.StatusCode, 1),
+		CreatedOn: now,
+	}
+
+	err = mgr.handleExitSignal(&exitSignal)
+	assert.NoError(t, err)
+
+	open, err = mgr.HasOpenPositions(ixic)
+	assert.NoError(t, err)
+	assert.False(t, open)
+
+	// Ensure a market without open positions can be removed along with its scheduled jobs.
+	err = mgr.RemoveMarket(ixic)
+	assert.NoError(t, err)
+	assert.Equal(t, mgr.cfg.JobScheduler.Len(), jobs)
+
+	_, err = mgr.HasOpenPositions(ixic)
+	assert.Error(t, err)
+
+	// Ensure an untracked market cannot be removed.
+	err = mgr.RemoveMarket(ixic)
+	assert.Error(t, err)
+}
+
+func TestManagerRunIdle(t *testing.T) {
+	market := "^GSPC"
+	mgr, _, _ := setupManager(t, market)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		mgr.Run(ctx)
+		close(done)
+	}()
+
+	// Ensure a market skew request sent once the manager is idle is handled.
+	time.Sleep(time.Millisecond * 50)
+	req := shared.MarketSkewRequest{
+		Market:   market,
+		Response: make(chan shared.MarketSkew, 1),
+	}
+	mgr.SendMarketSkewRequest(req)
+	select {
+	case <-req.Response:
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for the idle manager to handle the market skew request")
+	}
+
+	// Ensure the idle manager blocks instead of spinning.
+	iterations := mgr.runIterations.Load()
+	time.Sleep(time.Millisecond * 50)
+	assert.True(t, mgr.runIterations.Load()-iterations <= 1)
+
+	// Ensure the idle manager returns promptly once cancelled.
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the idle manager to return")
+	}
+}
+
 func TestFillManagerChannels(t *testing.T) {
 	// Ensure the price action manager can be created.
 	market := "^GSPC"
@@ -227,6 +385,13 @@ func TestFillManagerChannels(t *testing.T) {
 	assert.Equal(t, len(mgr.entrySignals), bufferSize)
 	assert.Equal(t, len(mgr.exitSignals), bufferSize)
 	assert.Equal(t, len(mgr.marketSkewRequests), bufferSize)
+
+	// Ensure signals dropped at capacity are counted.
+	assert.Equal(t, mgr.Dropped(), map[string]uint64{
+		"entry signal":        1,
+		"exit signal":         1,
+		"market skew request": 1,
+	})
 }
 
 func TestHandleEntrySignals(t *testing.T) {
@@ -249,19 +414,29 @@ func TestHandleEntrySignals(t *testing.T) {
 
 	// Ensure a valid entry signal gets processed as expected.
 	entrySignal := shared.EntrySignal{
-		Market:    market,
-		Timeframe: shared.FiveMinute,
-		Direction: shared.Long,
-		Price:     float64(10),
-		Reasons:   []shared.Reason{shared.BullishEngulfing, shared.StrongVolume},
-		StopLoss:  float64(8),
-		Status:    make(chan shared.StatusCode, 1),
+		Market:             market,
+		Timeframe:          shared.FiveMinute,
+		Direction:          shared.Long,
+		Price:              float64(10),
+		Reasons:            []shared.Reason{shared.StrongVolume, shared.BullishEngulfing, shared.ReversalAtSupport},
+		Confluence:         uint32(7),
+		Confidence:         float64(0.724),
+		WeightedConfidence: float64(72.4),
+		StopLoss:           float64(8),
+		Status:             make(chan shared.StatusCode, 1),
 	}
 
 	err = mgr.handleEntrySignal(&entrySignal)
 	assert.NoError(t, err)
 	msg := <-notifyMsgs
 	assert.True(t, strings.Contains(msg, "Created new long position"))
+
+	// Ensure the signal confidence is included in the notification.
+	assert.True(t, strings.Contains(msg, "confidence 72/100"))
+
+	// Ensure the signal explanation is included in the notification.
+	assert.True(t, strings.Contains(msg, "confluence 7 (72% confluence confidence): "+
+		"bullish engulfing, price reversal at support, strong volume"))
 }
 
 func TestHandleExitSignals(t *testing.T) {
@@ -313,6 +488,131 @@ func TestHandleExitSignals(t *testing.T) {
 	assert.True(t, strings.Contains(msg, "Closed long position"))
 }
 
+func TestHandleMaxOpenPositions(t *testing.T) {
+	market := "^GSPC"
+	mgr, notifyMsgs, _ := setupManager(t, market)
+	mgr.cfg.MaxOpenPositions = 2
+
+	ixic := "^IXIC"
+	err := mgr.AddMarket(ixic)
+	assert.NoError(t, err)
+
+	entrySignal := func(market string) *shared.EntrySignal {
+		return &shared.EntrySignal{
+			Market:    market,
+			Timeframe: shared.FiveMinute,
+			Direction: shared.Long,
+			Price:     float64(10),
+			Reasons:   []shared.Reason{shared.BullishEngulfing, shared.StrongVolume},
+			StopLoss:  float64(8),
+			Status:    make(chan shared.StatusCode, 1),
+		}
+	}
+
+	// Ensure positions can be opened across markets up to the limit.
+	err = mgr.handleEntrySignal(entrySignal(market))
+	assert.NoError(t, err)
+	msg := <-notifyMsgs
+	assert.True(t, strings.Contains(msg, "Created new long position"))
+
+	err = mgr.handleEntrySignal(entrySignal(ixic))
+	assert.NoError(t, err)
+	msg = <-notifyMsgs
+	assert.True(t, strings.Contains(msg, "Created new long position"))
+
+	// Ensure entries beyond the limit are rejected with a notification.
+	err = mgr.handleEntrySignal(entrySignal(ixic))
+	assert.NoError(t, err)
+	msg = <-notifyMsgs
+	assert.True(t, strings.Contains(msg, "Rejected long entry for ^IXIC"))
+	assert.Equal(t, mgr.openPositions(), 2)
+
+	// Ensure an exit frees up a slot for a new entry.
+	exitSignal := shared.ExitSignal{
+		Market:    market,
+		Timeframe: shared.FiveMinute,
+		Direction: shared.Long,
+		Price:     float64(15),
+		Reasons:   []shared.Reason{shared.BearishEngulfing, shared.StrongVolume},
+		Status:    make(chan shared.StatusCode, 1),
+	}
+	err = mgr.handleExitSignal(&exitSignal)
+	assert.NoError(t, err)
+	msg = <-notifyMsgs
+	assert.True(t, strings.Contains(msg, "Closed long position"))
+
+	err = mgr.handleEntrySignal(entrySignal(ixic))
+	assert.NoError(t, err)
+	msg = <-notifyMsgs
+	assert.True(t, strings.Contains(msg, "Created new long position"))
+	assert.Equal(t, mgr.openPositions(), 2)
+}
+
+func TestHandleSizedSignals(t *testing.T) {
+	market := "^GSPC"
+	mgr, notifyMsgs, _ := setupManager(t, market)
+
+	sizer, err := NewSizer(&SizingConfig{BaseSize: 2, MaxSize: 3, AdjustForWinRate: true, WinRateWindow: 2})
+	assert.NoError(t, err)
+	mgr.sizer = sizer
+
+	trade := func(exitPrice float64) *Position {
+		entrySignal := shared.EntrySignal{
+			Market:    market,
+			Timeframe: shared.FiveMinute,
+			Direction: shared.Long,
+			Price:     float64(10),
+			Reasons:   []shared.Reason{shared.BullishEngulfing},
+			StopLoss:  float64(8),
+			Status:    make(chan shared.StatusCode, 1),
+		}
+
+		err := mgr.handleEntrySignal(&entrySignal)
+		assert.NoError(t, err)
+		<-notifyMsgs
+
+		mkt, ok := mgr.fetchMarket(market)
+		assert.True(t, ok)
+		var pos *Position
+		for _, p := range mkt.positions {
+			if p.ClosedOn.IsZero() {
+				pos = p
+			}
+		}
+
+		exitSignal := shared.ExitSignal{
+			Market:    market,
+			Timeframe: shared.FiveMinute,
+			Direction: shared.Long,
+			Price:     exitPrice,
+			CreatedOn: time.Now(),
+			Status:    make(chan shared.StatusCode, 1),
+		}
+
+		err = mgr.handleExitSignal(&exitSignal)
+		assert.NoError(t, err)
+		<-notifyMsgs
+
+		return pos
+	}
+
+	// Ensure positions are sized at the base size initially.
+	pos := trade(float64(7))
+	assert.Equal(t, pos.Size, float64(2))
+
+	// Ensure a losing streak reduces the next position's size.
+	pos = trade(float64(7))
+	assert.True(t, pos.Size < float64(2))
+	pos = trade(float64(12))
+	assert.Equal(t, pos.Size, float64(0.5))
+
+	// Ensure a winning streak restores the position size, bound by the max size.
+	pos = trade(float64(12))
+	assert.Equal(t, pos.Size, float64(2))
+	pos = trade(float64(12))
+	assert.Equal(t, pos.Size, float64(3))
+}
+
 func TestHandleMarketStatusRequest(t *testing.T) {
 	market := "^GSPC"
 	mgr, _, _ := setupManager(t, market)
@@ -338,3 +638,182 @@ func TestHandleMarketStatusRequest(t *testing.T) {
 	resp := <-skewReq.Response
 	assert.Equal(t, shared.NeutralSkew, resp)
 }
+
+func TestManagerBacktestReport(t *testing.T) {
+	market := "^GSPC"
+	mgr, notifyMsgs, _ := setupManager(t, market)
+
+	reporter := NewBacktestReporter(nil)
+	mgr.cfg.RecordOpenedPosition = reporter.RecordEntry
+	mgr.cfg.PersistClosedPosition = reporter.RecordExit
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		mgr.Run(ctx)
+		close(done)
+	}()
+
+	mgr.SendEntrySignal(shared.EntrySignal{
+		Market:              market,
+		Timeframe:           shared.FiveMinute,
+		Direction:           shared.Long,
+		Price:               float64(10),
+		Reasons:             []shared.Reason{shared.BullishEngulfing, shared.StrongVolume},
+		StopLoss:            float64(8),
+		StopLossPointsRange: float64(2),
+		Status:              make(chan shared.StatusCode, 1),
+	})
+	<-notifyMsgs
+
+	mgr.SendExitSignal(shared.ExitSignal{
+		Market:    market,
+		Timeframe: shared.FiveMinute,
+		Direction: shared.Long,
+		Price:     float64(15),
+		Reasons:   []shared.Reason{shared.BearishEngulfing, shared.StrongVolume},
+		Status:    make(chan shared.StatusCode, 1),
+	})
+	<-notifyMsgs
+
+	cancel()
+	<-done
+
+	// Ensure opened and closed positions are reported as trades.
+	report := reporter.Report()
+	assert.Equal(t, report.Trades, 1)
+	assert.Equal(t, report.Wins, 1)
+	assert.Equal(t, report.AverageWinR, 2.5)
+	assert.Equal(t, report.OpenPositions, 0)
+}
+
+func TestManagerTrailingStop(t *testing.T) {
+	market := "^GSPC"
+	mgr, notifyMsgs, _ := setupManager(t, market)
+	mgr.cfg.TrailingStop = &TrailingStopConfig{Mode: TrailPoints, Distance: 2}
+
+	// Recreate the market with the trailing stop config.
+	mkt, err := mgr.newMarket(market)
+	assert.NoError(t, err)
+	mgr.markets[market] = mkt
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		mgr.Run(ctx)
+		close(done)
+	}()
+
+	mgr.SendEntrySignal(shared.EntrySignal{
+		Market:    market,
+		Timeframe: shared.FiveMinute,
+		Direction: shared.Long,
+		Price:     float64(10),
+		Reasons:   []shared.Reason{shared.BullishEngulfing, shared.StrongVolume},
+		StopLoss:  float64(7),
+		Status:    make(chan shared.StatusCode, 1),
+	})
+	<-notifyMsgs
+
+	// Ensure market updates trail the stop and notify of the move.
+	update := shared.Candlestick{
+		Open:      float64(10),
+		High:      float64(13),
+		Low:       float64(10),
+		Close:     float64(12),
+		Market:    market,
+		Timeframe: shared.FiveMinute,
+		Status:    make(chan shared.StatusCode, 1),
+	}
+	mgr.SendMarketUpdate(update)
+	<-update.Status
+	msg := <-notifyMsgs
+	assert.True(t, strings.Contains(msg, "Trailed stoploss"))
+	assert.True(t, strings.Contains(msg, "from 7.00 to 10.00"))
+
+	// Ensure updates that do not move the stop are not notified.
+	update.Close = float64(11)
+	update.Status = make(chan shared.StatusCode, 1)
+	mgr.SendMarketUpdate(update)
+	<-update.Status
+	assert.Equal(t, len(notifyMsgs), 0)
+
+	cancel()
+	<-done
+}
+
+func TestManagerRestoresOpenPositions(t *testing.T) {
+	market := "^GSPC"
+	loc, err := time.LoadLocation(shared.NewYorkLocation)
+	assert.NoError(t, err)
+
+	store := NewMemoryStore()
+	newManager := func() *Manager {
+		mgr, err := NewPositionManager(&ManagerConfig{
+			Markets:               []string{market},
+			Notify:                func(string) {},
+			PersistClosedPosition: func(*Position) error { return nil },
+			Store:                 store,
+			JobScheduler:          gocron.NewScheduler(loc),
+			Logger:                &log.Logger,
+		})
+		assert.NoError(t, err)
+		return mgr
+	}
+
+	skew := func(mgr *Manager) shared.MarketSkew {
+		req := shared.MarketSkewRequest{
+			Market:   market,
+			Response: make(chan shared.MarketSkew, 1),
+		}
+		err := mgr.handleMarketSkewRequest(&req)
+		assert.NoError(t, err)
+		return <-req.Response
+	}
+
+	mgr := newManager()
+	entrySignal := shared.EntrySignal{
+		Market:    market,
+		Timeframe: shared.FiveMinute,
+		Direction: shared.Long,
+		Price:     float64(10),
+		Reasons:   []shared.Reason{shared.BullishEngulfing, shared.StrongVolume},
+		StopLoss:  float64(8),
+		Status:    make(chan shared.StatusCode, 1),
+	}
+	err = mgr.handleEntrySignal(&entrySignal)
+	assert.NoError(t, err)
+	assert.Equal(t, skew(mgr), shared.LongSkewed)
+
+	// Ensure open positions are persisted as they are opened.
+	stored, err := store.LoadOpenPositions(market)
+	assert.NoError(t, err)
+	assert.Equal(t, len(stored), 1)
+	assert.Equal(t, stored[0].Direction, shared.Long)
+	assert.Equal(t, stored[0].EntryPrice, float64(10))
+	assert.Equal(t, stored[0].StopLoss, float64(8))
+
+	// Ensure a restarted manager restores the open positions and the market skew.
+	restarted := newManager()
+	assert.Equal(t, skew(restarted), shared.LongSkewed)
+
+	// Ensure closing restored positions clears them from the store.
+	exitSignal := shared.ExitSignal{
+		Market:    market,
+		Timeframe: shared.FiveMinute,
+		Direction: shared.Long,
+		Price:     float64(12),
+		Reasons:   []shared.Reason{shared.BearishEngulfing, shared.StrongVolume},
+		Status:    make(chan shared.StatusCode, 1),
+	}
+	err = restarted.handleExitSignal(&exitSignal)
+	assert.NoError(t, err)
+	assert.Equal(t, skew(restarted), shared.NeutralSkew)
+	stored, err = store.LoadOpenPositions(market)
+	assert.NoError(t, err)
+	assert.Equal(t, len(stored), 0)
+}
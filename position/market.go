@@ -18,6 +18,8 @@ import (
 const (
 	// maxPositionsPurgeDuration is the maximum time closed position will be kept around for before being purged.
 	maxPositionsPurgeDuration = time.Hour * 48
+	// jobComponent is the component name used in tagging scheduled position jobs.
+	jobComponent = "position"
 )
 
 var (
@@ -30,8 +32,15 @@ var (
 type MarketConfig struct {
 	// The tracked market.
 	Market string
+	// Instrument is the contract specification of the market, the profit and loss of
+	// positions is valued with it.
+	Instrument shared.Instrument
+	// TrailingStop represents the trailing stop configuration. Stops are not trailed if nil.
+	TrailingStop *TrailingStopConfig
 	// JobScheduler represents the job scheduler.
 	JobScheduler *gocron.Scheduler
+	// Clock is the source of the current time. The wall clock is used if nil.
+	Clock shared.Clock
 	// Logger represents the application logger.
 	Logger *zerolog.Logger
 }
@@ -43,6 +52,12 @@ func (cfg *MarketConfig) Validate() error {
 	if cfg.Market == "" {
 		errs = errors.Join(errs, fmt.Errorf("markets cannot be an empty string"))
 	}
+	if cfg.TrailingStop != nil {
+		err := cfg.TrailingStop.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating trailing stop config: %v", err))
+		}
+	}
 	if cfg.JobScheduler == nil {
 		errs = errors.Join(errs, fmt.Errorf("job scheduler cannot be nil"))
 	}
@@ -53,12 +68,24 @@ func (cfg *MarketConfig) Validate() error {
 	return errs
 }
 
+// TrailedStop represents a stop loss moved by a trailing stop.
+type TrailedStop struct {
+	Position *Position
+	Previous float64
+}
+
 // Market tracks positions for the provided market.
+//
+// Multiple long and short positions can be open concurrently, each is a leg of the market's
+// exposure. The skew of the market is the net direction of its open legs.
 type Market struct {
 	cfg         *MarketConfig
 	positions   map[string]*Position
+	legs        []string
+	atrs        map[shared.Timeframe]*averageTrueRange
 	positionMtx sync.RWMutex
 	skew        atomic.Uint32
+	clock       shared.Clock
 }
 
 // NewMarket initializes a new market.
@@ -71,10 +98,16 @@ func NewMarket(cfg *MarketConfig) (*Market, error) {
 	mkt := &Market{
 		cfg:       cfg,
 		positions: make(map[string]*Position),
+		legs:      make([]string, 0),
+		atrs:      make(map[shared.Timeframe]*averageTrueRange),
+		clock:     cfg.Clock,
+	}
+	if mkt.clock == nil {
+		mkt.clock = shared.WallClock{}
 	}
 
 	// Schedule closed positions purge job.
-	_, err = cfg.JobScheduler.Every(6).Hours().
+	_, err = cfg.JobScheduler.Every(6).Hours().Tag(shared.MarketJobTag(jobComponent, cfg.Market)).
 		Do(func() {
 			err := mkt.PurgeClosedPositionsJob()
 			if err != nil {
@@ -88,6 +121,16 @@ func NewMarket(cfg *MarketConfig) (*Market, error) {
 	return mkt, nil
 }
 
+// RemoveJobs removes all scheduled jobs of the market.
+func (m *Market) RemoveJobs() error {
+	err := m.cfg.JobScheduler.RemoveByTag(shared.MarketJobTag(jobComponent, m.cfg.Market))
+	if err != nil && !errors.Is(err, gocron.ErrJobNotFoundWithTag) {
+		return fmt.Errorf("removing %s position market jobs: %w", m.cfg.Market, err)
+	}
+
+	return nil
+}
+
 // AddPosition adds the provided position to the market.
 func (m *Market) AddPosition(position *Position) error {
 	if position == nil {
@@ -97,117 +140,181 @@ func (m *Market) AddPosition(position *Position) error {
 		return fmt.Errorf("unexpected position market provided: %s", position.Market)
 	}
 
-	updatedSkew := shared.NeutralSkew
-	currentSkew := shared.MarketSkew(m.skew.Load())
-	switch currentSkew {
-	case shared.NeutralSkew:
-		// If the state of the market has neutral skew, the position to be tracked sets the skew
-		// of the market. Once set the skew has to be unwound fully back to neutral before a
-		// new skew can be set.
-		switch position.Direction {
-		case shared.Long:
-			updatedSkew = shared.LongSkewed
-		case shared.Short:
-			updatedSkew = shared.ShortSkewed
-		}
-
-	case shared.LongSkewed:
-		// If managing longs the market can only add more long positions, no short positions can be
-		// added until all long positions have been concluded.
-		switch position.Direction {
-		case shared.Short:
-			return fmt.Errorf("short position provided to market currently managing longs: %s", m.cfg.Market)
-		case shared.Long:
-			// do nothing.
-		}
-
-	case shared.ShortSkewed:
-		// If managing shorts the market can only add more short positions, no long positions can be
-		// added until all short positions have been concluded.
-		switch position.Direction {
-		case shared.Long:
-			return fmt.Errorf("long position provided to market currently managing shorts: %s", m.cfg.Market)
-		case shared.Short:
-			// do nothing.
-		}
-	}
+	m.positionMtx.Lock()
+	defer m.positionMtx.Unlock()
 
 	// Ensure the provided position is not already tracked.
-	m.positionMtx.RLock()
 	_, ok := m.positions[position.ID]
-	m.positionMtx.RUnlock()
-
 	if ok {
 		// do nothing if the position is already tracked.
 		return nil
 	}
 
-	m.positionMtx.Lock()
 	m.positions[position.ID] = position
-	m.positionMtx.Unlock()
+	m.legs = append(m.legs, position.ID)
+	m.skew.Store(uint32(m.netSkew()))
 
-	if updatedSkew != currentSkew {
-		m.skew.Store(uint32(updatedSkew))
+	return nil
+}
+
+// OpenPositions returns the number of open legs of the market.
+func (m *Market) OpenPositions() int {
+	m.positionMtx.RLock()
+	defer m.positionMtx.RUnlock()
+
+	return len(m.legs)
+}
+
+// OpenLegs returns the number of open legs of the market in the provided direction.
+func (m *Market) OpenLegs(direction shared.Direction) int {
+	m.positionMtx.RLock()
+	defer m.positionMtx.RUnlock()
+
+	var count int
+	for idx := range m.legs {
+		if m.positions[m.legs[idx]].Direction == direction {
+			count++
+		}
 	}
 
-	return nil
+	return count
 }
 
-// Update updates tracked positions with the market data.
-func (m *Market) Update(candle *shared.Candlestick) error {
+// LatestLeg returns the direction of the most recently opened leg of the market that is still
+// open. It returns false if the market has no open legs.
+func (m *Market) LatestLeg() (shared.Direction, bool) {
 	m.positionMtx.RLock()
 	defer m.positionMtx.RUnlock()
 
-	for k := range m.positions {
-		_, err := m.positions[k].UpdatePNLPercent(candle.Close)
+	if len(m.legs) == 0 {
+		return 0, false
+	}
+
+	return m.positions[m.legs[len(m.legs)-1]].Direction, true
+}
+
+// netSkew returns the skew of the market from the net direction of its open legs.
+//
+// This assumes the position mutex is held by the caller.
+func (m *Market) netSkew() shared.MarketSkew {
+	var net int
+	for idx := range m.legs {
+		switch m.positions[m.legs[idx]].Direction {
+		case shared.Long:
+			net++
+		case shared.Short:
+			net--
+		}
+	}
+
+	switch {
+	case net > 0:
+		return shared.LongSkewed
+	case net < 0:
+		return shared.ShortSkewed
+	default:
+		return shared.NeutralSkew
+	}
+}
+
+// Update updates open positions with the market data.
+func (m *Market) Update(candle *shared.Candlestick) error {
+	m.positionMtx.Lock()
+	defer m.positionMtx.Unlock()
+
+	for idx := range m.legs {
+		position := m.positions[m.legs[idx]]
+		_, err := position.UpdatePNLPercent(candle.Close)
 		if err != nil {
 			return fmt.Errorf("updating position PNL percents: %v", err)
 		}
+		position.UpdatePNL(candle.Close, &m.cfg.Instrument)
 	}
 
 	return nil
 }
 
-// ClosePositions closes
-func (m *Market) ClosePositions(signal *shared.ExitSignal) ([]*Position, error) {
-	if signal.Market != m.cfg.Market {
-		return nil, fmt.Errorf("unexpected %s exit signal provided for %s market", signal.Market, m.cfg.Market)
+// TrailStops ratchets the stops of open positions of the provided candle's timeframe towards
+// its close. The stops moved are returned.
+func (m *Market) TrailStops(candle *shared.Candlestick) ([]TrailedStop, error) {
+	cfg := m.cfg.TrailingStop
+	if cfg == nil {
+		return nil, nil
+	}
+	if candle.Market != m.cfg.Market {
+		return nil, fmt.Errorf("unexpected %s candle provided for %s market", candle.Market, m.cfg.Market)
 	}
 
 	m.positionMtx.Lock()
 	defer m.positionMtx.Unlock()
 
-	set := make([]*Position, 0, len(m.positions))
-	for k := range m.positions {
-		if m.positions[k].Direction != signal.Direction {
-			// do nothing.
+	distance := cfg.Distance
+	if cfg.Mode == TrailATR {
+		atr, ok := m.atrs[candle.Timeframe]
+		if !ok {
+			period := cfg.ATRPeriod
+			if period == 0 {
+				period = defaultATRPeriod
+			}
+
+			atr = newAverageTrueRange(period)
+			m.atrs[candle.Timeframe] = atr
+		}
+
+		atr.Update(candle)
+		value, ok := atr.Value()
+		if !ok {
+			// Stops are not trailed until the average true range is available.
+			return nil, nil
+		}
+
+		distance = value * cfg.Distance
+	}
+
+	trailed := make([]TrailedStop, 0)
+	for idx := range m.legs {
+		position := m.positions[m.legs[idx]]
+		if position.Timeframe != candle.Timeframe {
 			continue
 		}
 
-		m.positions[k].UpdatePNLPercent(signal.Price)
-		m.positions[k].ClosePosition(signal)
+		previous, ok := trailStop(position, candle.Close, cfg.Activation, distance)
+		if ok {
+			trailed = append(trailed, TrailedStop{Position: position, Previous: previous})
+		}
+	}
+
+	return trailed, nil
+}
 
-		set = append(set, m.positions[k])
+// ClosePositions closes the oldest open leg matching the direction of the provided exit signal.
+// The skew of the market is updated from the net direction of the remaining open legs.
+func (m *Market) ClosePositions(signal *shared.ExitSignal) ([]*Position, error) {
+	if signal.Market != m.cfg.Market {
+		return nil, fmt.Errorf("unexpected %s exit signal provided for %s market", signal.Market, m.cfg.Market)
 	}
 
-	// Update the market skew based on remaining open positions.
-	openPositionSkew := shared.NeutralSkew
-	for k := range m.positions {
-		position := m.positions[k]
-		if position.ClosedOn.IsZero() {
-			switch position.Direction {
-			case shared.Long:
-				openPositionSkew = shared.LongSkewed
-			case shared.Short:
-				openPositionSkew = shared.ShortSkewed
-			}
+	m.positionMtx.Lock()
+	defer m.positionMtx.Unlock()
 
-			break
+	set := make([]*Position, 0, 1)
+	for idx := range m.legs {
+		position := m.positions[m.legs[idx]]
+		if position.Direction != signal.Direction {
+			// do nothing.
+			continue
 		}
+
+		position.UpdatePNLPercent(signal.Price)
+		position.UpdatePNL(signal.Price, &m.cfg.Instrument)
+		position.ClosePosition(signal)
+		set = append(set, position)
+
+		m.legs = append(m.legs[:idx], m.legs[idx+1:]...)
+		break
 	}
 
-	// Reset the market status to neutral if all positions have been removed.
-	m.skew.Store(uint32(openPositionSkew))
+	m.skew.Store(uint32(m.netSkew()))
 
 	return set, nil
 }
@@ -216,7 +323,7 @@ func (m *Market) ClosePositions(signal *shared.ExitSignal) ([]*Position, error)
 //
 // This job should be run periodically.
 func (m *Market) PurgeClosedPositionsJob() error {
-	now, _, err := shared.NewYorkTime()
+	now, _, err := shared.NewYorkTimeFrom(m.clock)
 	if err != nil {
 		return fmt.Errorf("fetching new york time: %v", err)
 	}
@@ -240,12 +347,15 @@ func (m *Market) PurgeClosedPositionsJob() error {
 
 // PersistPositionsCSV writes the tracked positions of the provided market to file as csv.
 func (m *Market) PersistPositionsCSV() (string, error) {
+	m.positionMtx.RLock()
+	defer m.positionMtx.RUnlock()
+
 	if len(m.positions) == 0 {
 		// Do nothing.
 		return "", nil
 	}
 
-	now, _, err := shared.NewYorkTime()
+	now, _, err := shared.NewYorkTimeFrom(m.clock)
 	if err != nil {
 		return "", fmt.Errorf("fetching new york time: %v", err)
 	}
@@ -265,9 +375,6 @@ func (m *Market) PersistPositionsCSV() (string, error) {
 	writer.Write(positionsHeaderCSV)
 
 	// Write the position records to file.
-	m.positionMtx.RLock()
-	defer m.positionMtx.RUnlock()
-
 	record := make([]string, 14)
 	resetRecord := func() {
 		for i := range record {
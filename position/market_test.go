@@ -151,8 +151,6 @@ func TestMarket(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, shared.MarketSkew(mkt.skew.Load()), shared.LongSkewed)
 
-	// Ensure once a markets skew is set, tracking positions of the opposite.
-	// skew returns an error.
 	shortEntrySignal := &shared.EntrySignal{
 		Market:    market,
 		Timeframe: shared.FiveMinute,
@@ -164,12 +162,6 @@ func TestMarket(t *testing.T) {
 		Status:    make(chan shared.StatusCode, 1),
 	}
 
-	pos, err = NewPosition(shortEntrySignal)
-	assert.NoError(t, err)
-
-	err = mkt.AddPosition(pos)
-	assert.Error(t, err)
-
 	// Ensure the market can persist its positions to file.
 	filename, err := mkt.PersistPositionsCSV()
 	defer os.Remove(filename)
@@ -228,14 +220,6 @@ func TestMarket(t *testing.T) {
 	sizeAfter = len(mkt.positions)
 	mkt.positionMtx.RUnlock()
 
-	// Ensure once a markets skew is set, tracking positions of the opposite
-	// skew returns an error.
-	pos, err = NewPosition(longEntrySignal)
-	assert.NoError(t, err)
-
-	err = mkt.AddPosition(pos)
-	assert.Error(t, err)
-
 	// Ensure a tracked market positions can be closed.
 	shortExitSignal := &shared.ExitSignal{
 		Market:     market,
@@ -269,3 +253,155 @@ func TestMarket(t *testing.T) {
 	assert.NotEqual(t, sizeBefore, sizeAfter)
 
 }
+
+func TestMarketPurgeClock(t *testing.T) {
+	market := "^GSPC"
+
+	loc, err := time.LoadLocation(shared.NewYorkLocation)
+	assert.NoError(t, err)
+
+	start := time.Date(2025, 5, 7, 10, 30, 0, 0, loc)
+	clock := shared.NewReplayClock(start)
+	cfg := &MarketConfig{
+		Market:       market,
+		JobScheduler: gocron.NewScheduler(loc),
+		Clock:        clock,
+		Logger:       &log.Logger,
+	}
+	mkt, err := NewMarket(cfg)
+	assert.NoError(t, err)
+
+	entrySignal := &shared.EntrySignal{
+		Market:    market,
+		Timeframe: shared.FiveMinute,
+		Direction: shared.Long,
+		Price:     10,
+		Reasons:   []shared.Reason{shared.BullishEngulfing, shared.StrongVolume},
+		StopLoss:  8,
+		CreatedOn: start.Add(-time.Hour * 2),
+		Status:    make(chan shared.StatusCode, 1),
+	}
+	pos, err := NewPosition(entrySignal)
+	assert.NoError(t, err)
+	err = mkt.AddPosition(pos)
+	assert.NoError(t, err)
+
+	exitSignal := &shared.ExitSignal{
+		Market:    market,
+		Timeframe: shared.FiveMinute,
+		Direction: shared.Long,
+		Price:     12,
+		Reasons:   []shared.Reason{shared.TargetHit},
+		CreatedOn: start.Add(-time.Hour),
+		Status:    make(chan shared.StatusCode, 1),
+	}
+	_, err = mkt.ClosePositions(exitSignal)
+	assert.NoError(t, err)
+
+	// Ensure recently closed positions are kept relative to the market's clock.
+	err = mkt.PurgeClosedPositionsJob()
+	assert.NoError(t, err)
+	assert.Equal(t, len(mkt.positions), 1)
+
+	// Ensure closed positions are purged once the clock moves past the purge duration.
+	clock.Advance(start.Add(maxPositionsPurgeDuration))
+	err = mkt.PurgeClosedPositionsJob()
+	assert.NoError(t, err)
+	assert.Equal(t, len(mkt.positions), 0)
+}
+
+func TestMarketNetSkew(t *testing.T) {
+	market := "^GSPC"
+
+	loc, err := time.LoadLocation(shared.NewYorkLocation)
+	assert.NoError(t, err)
+
+	mkt, err := NewMarket(&MarketConfig{
+		Market:       market,
+		JobScheduler: gocron.NewScheduler(loc),
+		Logger:       &log.Logger,
+	})
+	assert.NoError(t, err)
+
+	now, _, err := shared.NewYorkTime()
+	assert.NoError(t, err)
+
+	open := func(direction shared.Direction, price float64, stopLoss float64) *Position {
+		pos, err := NewPosition(&shared.EntrySignal{
+			Market:    market,
+			Timeframe: shared.FiveMinute,
+			Direction: direction,
+			Price:     price,
+			StopLoss:  stopLoss,
+			CreatedOn: now,
+			Status:    make(chan shared.StatusCode, 1),
+		})
+		assert.NoError(t, err)
+
+		err = mkt.AddPosition(pos)
+		assert.NoError(t, err)
+
+		return pos
+	}
+	exit := func(direction shared.Direction, price float64) []*Position {
+		closed, err := mkt.ClosePositions(&shared.ExitSignal{
+			Market:    market,
+			Timeframe: shared.FiveMinute,
+			Direction: direction,
+			Price:     price,
+			CreatedOn: now,
+			Status:    make(chan shared.StatusCode, 1),
+		})
+		assert.NoError(t, err)
+
+		return closed
+	}
+	skew := func() shared.MarketSkew {
+		return shared.MarketSkew(mkt.skew.Load())
+	}
+
+	// Ensure opposing positions can be tracked concurrently with the skew following the
+	// net direction of the open legs.
+	firstLong := open(shared.Long, 10, 8)
+	assert.Equal(t, skew(), shared.LongSkewed)
+	short := open(shared.Short, 12, 14)
+	assert.Equal(t, skew(), shared.NeutralSkew)
+	secondLong := open(shared.Long, 11, 9)
+	assert.Equal(t, skew(), shared.LongSkewed)
+	assert.Equal(t, mkt.OpenPositions(), 3)
+	assert.Equal(t, mkt.OpenLegs(shared.Long), 2)
+	assert.Equal(t, mkt.OpenLegs(shared.Short), 1)
+	latest, ok := mkt.LatestLeg()
+	assert.True(t, ok)
+	assert.Equal(t, latest, shared.Long)
+
+	// Ensure an exit closes the oldest matching leg only.
+	closed := exit(shared.Long, 13)
+	assert.Equal(t, len(closed), 1)
+	assert.Equal(t, closed[0].ID, firstLong.ID)
+	assert.Equal(t, skew(), shared.NeutralSkew)
+	assert.Equal(t, mkt.OpenPositions(), 2)
+
+	closed = exit(shared.Long, 13)
+	assert.Equal(t, len(closed), 1)
+	assert.Equal(t, closed[0].ID, secondLong.ID)
+	assert.Equal(t, skew(), shared.ShortSkewed)
+	latest, ok = mkt.LatestLeg()
+	assert.True(t, ok)
+	assert.Equal(t, latest, shared.Short)
+
+	// Ensure exits without a matching open leg close nothing.
+	closed = exit(shared.Long, 13)
+	assert.Equal(t, len(closed), 0)
+	assert.Equal(t, skew(), shared.ShortSkewed)
+
+	// Ensure a stopped out leg is no longer the latest open leg.
+	closed = exit(shared.Short, 15)
+	assert.Equal(t, len(closed), 1)
+	assert.Equal(t, closed[0].ID, short.ID)
+	assert.Equal(t, closed[0].Status, StoppedOut)
+	assert.Equal(t, skew(), shared.NeutralSkew)
+	assert.Equal(t, mkt.OpenPositions(), 0)
+	_, ok = mkt.LatestLeg()
+	assert.False(t, ok)
+}
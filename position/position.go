@@ -40,14 +40,20 @@ type Position struct {
 	Direction           shared.Direction
 	StopLoss            float64
 	StopLossPointsRange float64
+	Size                float64
 	PNLPercent          float64
+	PNL                 float64
 	EntryPrice          float64
 	EntryReasons        string
+	Confluence          uint32
 	ExitPrice           float64
 	ExitReasons         string
 	Status              PositionStatus
-	CreatedOn           time.Time
-	ClosedOn            time.Time
+	// LevelPrice is the price of the level the position was entered at, zero for positions
+	// entered at dynamic focuses.
+	LevelPrice float64
+	CreatedOn  time.Time
+	ClosedOn   time.Time
 }
 
 // stringifyReasons stringifies the collection of reasons provided.
@@ -81,9 +87,11 @@ func NewPosition(entry *shared.EntrySignal) (*Position, error) {
 		CreatedOn:           entry.CreatedOn,
 		EntryPrice:          entry.Price,
 		EntryReasons:        stringifyReasons(entry.Reasons),
+		Confluence:          entry.Confluence,
 		StopLoss:            entry.StopLoss,
 		StopLossPointsRange: entry.StopLossPointsRange,
 		Status:              Active,
+		LevelPrice:          entry.LevelPrice,
 	}
 
 	return pos, nil
@@ -118,3 +126,24 @@ func (p *Position) UpdatePNLPercent(currentPrice float64) (float64, error) {
 
 	return p.PNLPercent, nil
 }
+
+// UpdatePNL updates the dollar profit and loss of the position on the provided instrument
+// given the current price. Unsized positions are valued as a single unit.
+func (p *Position) UpdatePNL(currentPrice float64, instrument *shared.Instrument) float64 {
+	var points float64
+	switch p.Direction {
+	case shared.Long:
+		points = currentPrice - p.EntryPrice
+	case shared.Short:
+		points = p.EntryPrice - currentPrice
+	}
+
+	size := p.Size
+	if size == 0 {
+		size = 1
+	}
+
+	p.PNL = instrument.Value(points, size)
+
+	return p.PNL
+}
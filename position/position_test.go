@@ -162,3 +162,27 @@ func TestPosition(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, status, StoppedOut)
 }
+
+func TestPositionUpdatePNL(t *testing.T) {
+	signal := &shared.EntrySignal{
+		Market:    "ES",
+		Timeframe: shared.FiveMinute,
+		Direction: shared.Long,
+		Price:     100,
+		Reasons:   []shared.Reason{shared.BullishEngulfing, shared.StrongVolume},
+		StopLoss:  98,
+		Status:    make(chan shared.StatusCode, 1),
+	}
+
+	position, err := NewPosition(signal)
+	assert.NoError(t, err)
+
+	// Ensure unsized positions are valued as a single unit.
+	assert.Equal(t, position.UpdatePNL(102, &shared.Instrument{}), float64(2))
+
+	// Ensure the dollar profit and loss scales with the point value and size.
+	position.Size = 2
+	assert.Equal(t, position.UpdatePNL(102, &shared.Instrument{PointValue: 50}), float64(200))
+	assert.Equal(t, position.UpdatePNL(99, &shared.Instrument{PointValue: 50}), float64(-100))
+	assert.Equal(t, position.PNL, float64(-100))
+}
@@ -0,0 +1,180 @@
+package position
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+)
+
+const (
+	// maxRecentSignals is the number of recent signals retained per market.
+	maxRecentSignals = 50
+)
+
+// SignalKind represents the kind of a recorded signal.
+type SignalKind int
+
+const (
+	EntryKind SignalKind = iota
+	ExitKind
+)
+
+// String stringifies the provided signal kind.
+func (k SignalKind) String() string {
+	switch k {
+	case EntryKind:
+		return "entry"
+	case ExitKind:
+		return "exit"
+	default:
+		return "unknown"
+	}
+}
+
+// SignalOutcome represents the outcome of handling a signal.
+type SignalOutcome int
+
+const (
+	// SignalFailed is the outcome of signals that errored while being handled.
+	SignalFailed SignalOutcome = iota
+	// SignalOpened is the outcome of entry signals that opened a position.
+	SignalOpened
+	// SignalRejected is the outcome of entry signals rejected by exposure limits.
+	SignalRejected
+	// SignalClosed is the outcome of exit signals that closed positions.
+	SignalClosed
+	// SignalUnmatched is the outcome of exit signals without positions to close.
+	SignalUnmatched
+)
+
+// String stringifies the provided signal outcome.
+func (o SignalOutcome) String() string {
+	switch o {
+	case SignalFailed:
+		return "failed"
+	case SignalOpened:
+		return "opened"
+	case SignalRejected:
+		return "rejected"
+	case SignalClosed:
+		return "closed"
+	case SignalUnmatched:
+		return "unmatched"
+	default:
+		return "unknown"
+	}
+}
+
+// SignalRecord represents a handled entry or exit signal.
+type SignalRecord struct {
+	CorrelationID string
+	Market        string
+	Kind          SignalKind
+	Direction     shared.Direction
+	Price         float64
+	Reasons       []shared.Reason
+	Confluence    uint32
+	Outcome       SignalOutcome
+	CreatedOn     time.Time
+}
+
+// signalRing is a bounded ring buffer of signal records, the oldest record is evicted once
+// full.
+type signalRing struct {
+	records []SignalRecord
+	next    int
+	full    bool
+}
+
+// newSignalRing initializes a new signal ring buffer of the provided capacity.
+func newSignalRing(capacity int) *signalRing {
+	return &signalRing{
+		records: make([]SignalRecord, capacity),
+	}
+}
+
+// add records the provided signal record, evicting the oldest record if full.
+func (r *signalRing) add(record SignalRecord) {
+	r.records[r.next] = record
+	r.next = (r.next + 1) % len(r.records)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// latest returns the provided number of most recent records, oldest first. All records are
+// returned if n is not positive or exceeds the number recorded.
+func (r *signalRing) latest(n int) []SignalRecord {
+	size := r.next
+	if r.full {
+		size = len(r.records)
+	}
+	if n <= 0 || n > size {
+		n = size
+	}
+
+	latest := make([]SignalRecord, 0, n)
+	for idx := range n {
+		pos := (r.next - n + idx + len(r.records)) % len(r.records)
+		latest = append(latest, r.records[pos])
+	}
+
+	return latest
+}
+
+// recentSignals tracks the recent signals of markets.
+type recentSignals struct {
+	markets map[string]*signalRing
+	mtx     sync.RWMutex
+}
+
+// newRecentSignals initializes a new recent signals tracker.
+func newRecentSignals() *recentSignals {
+	return &recentSignals{
+		markets: make(map[string]*signalRing),
+	}
+}
+
+// record adds the provided signal record to the recent signals of its market.
+func (s *recentSignals) record(record SignalRecord) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	ring, ok := s.markets[record.Market]
+	if !ok {
+		ring = newSignalRing(maxRecentSignals)
+		s.markets[record.Market] = ring
+	}
+
+	ring.add(record)
+}
+
+// remove drops the recent signals of the provided market.
+func (s *recentSignals) remove(market string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	delete(s.markets, market)
+}
+
+// latest returns the provided number of most recent signals of the provided market, oldest
+// first.
+func (s *recentSignals) latest(market string, n int) []SignalRecord {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	ring, ok := s.markets[market]
+	if !ok {
+		return nil
+	}
+
+	return ring.latest(n)
+}
+
+// RecentSignals returns the provided number of most recent entry and exit signals handled
+// for the provided market, oldest first. All retained signals are returned if n is not
+// positive.
+func (m *Manager) RecentSignals(market string, n int) []SignalRecord {
+	return m.recent.latest(market, n)
+}
@@ -0,0 +1,181 @@
+package position
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestSignalRing(t *testing.T) {
+	ring := newSignalRing(3)
+
+	// Ensure an empty ring returns no records.
+	assert.Equal(t, len(ring.latest(0)), 0)
+
+	for idx := range 5 {
+		ring.add(SignalRecord{Market: "^GSPC", Price: float64(idx)})
+	}
+
+	tests := []struct {
+		name string
+		n    int
+		want []float64
+	}{
+		{"all records", 0, []float64{2, 3, 4}},
+		{"more than retained", 10, []float64{2, 3, 4}},
+		{"most recent records", 2, []float64{3, 4}},
+		{"most recent record", 1, []float64{4}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Ensure only the most recent records are returned, oldest first.
+			latest := ring.latest(test.n)
+			prices := make([]float64, 0, len(latest))
+			for idx := range latest {
+				prices = append(prices, latest[idx].Price)
+			}
+			assert.Equal(t, prices, test.want)
+		})
+	}
+}
+
+func TestManagerRecentSignals(t *testing.T) {
+	market := "^GSPC"
+	mgr, notifyMsgs, _ := setupManager(t, market)
+
+	entrySignal := shared.EntrySignal{
+		Market:     market,
+		Timeframe:  shared.FiveMinute,
+		Direction:  shared.Long,
+		Price:      float64(10),
+		Reasons:    []shared.Reason{shared.BullishEngulfing, shared.StrongVolume},
+		Confluence: uint32(6),
+		StopLoss:   float64(8),
+		Status:     make(chan shared.StatusCode, 1),
+	}
+	err := mgr.handleEntrySignal(&entrySignal)
+	assert.NoError(t, err)
+	<-notifyMsgs
+
+	exitSignal := shared.ExitSignal{
+		Market:    market,
+		Timeframe: shared.FiveMinute,
+		Direction: shared.Long,
+		Price:     float64(15),
+		Reasons:   []shared.Reason{shared.TargetHit},
+		Status:    make(chan shared.StatusCode, 1),
+	}
+	err = mgr.handleExitSignal(&exitSignal)
+	assert.NoError(t, err)
+	<-notifyMsgs
+
+	// Ensure handled signals are recorded with their reasons and outcomes.
+	recent := mgr.RecentSignals(market, 0)
+	assert.Equal(t, len(recent), 2)
+	assert.Equal(t, recent[0].Kind, EntryKind)
+	assert.Equal(t, recent[0].Outcome, SignalOpened)
+	assert.Equal(t, recent[0].Reasons, entrySignal.Reasons)
+	assert.Equal(t, recent[0].Confluence, uint32(6))
+	assert.Equal(t, recent[1].Kind, ExitKind)
+	assert.Equal(t, recent[1].Outcome, SignalClosed)
+
+	// Ensure the oldest signals are evicted once more than the retained signals are recorded.
+	for idx := range maxRecentSignals {
+		exitSignal := shared.ExitSignal{
+			Market:    market,
+			Timeframe: shared.FiveMinute,
+			Direction: shared.Long,
+			Price:     float64(100 + idx),
+			Reasons:   []shared.Reason{shared.TargetHit},
+			Status:    make(chan shared.StatusCode, 1),
+		}
+		err := mgr.handleExitSignal(&exitSignal)
+		assert.NoError(t, err)
+	}
+
+	recent = mgr.RecentSignals(market, 0)
+	assert.Equal(t, len(recent), maxRecentSignals)
+	assert.Equal(t, recent[0].Price, float64(100))
+	assert.Equal(t, recent[0].Outcome, SignalUnmatched)
+	assert.Equal(t, recent[maxRecentSignals-1].Price, float64(100+maxRecentSignals-1))
+
+	// Ensure the requested number of most recent signals are returned in order.
+	recent = mgr.RecentSignals(market, 3)
+	assert.Equal(t, len(recent), 3)
+	for idx := range recent {
+		assert.Equal(t, recent[idx].Price, float64(100+maxRecentSignals-3+idx))
+	}
+
+	// Ensure markets without recorded signals return none.
+	assert.Equal(t, len(mgr.RecentSignals("^IXIC", 0)), 0)
+}
+
+func TestManagerRecordStopOut(t *testing.T) {
+	market := "^GSPC"
+	mgr, notifyMsgs, _ := setupManager(t, market)
+
+	type stopOut struct {
+		market     string
+		levelPrice float64
+	}
+	stopOuts := make(chan stopOut, 2)
+	mgr.cfg.RecordStopOut = func(market string, levelPrice float64, stoppedOn time.Time) {
+		stopOuts <- stopOut{market: market, levelPrice: levelPrice}
+	}
+
+	tests := []struct {
+		name        string
+		levelPrice  float64
+		exitPrice   float64
+		wantStopOut bool
+	}{
+		{"stopped out at a level", 9, 7, true},
+		{"closed in profit at a level", 9, 15, false},
+		{"stopped out at a dynamic level", 0, 7, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entrySignal := shared.EntrySignal{
+				Market:     market,
+				Timeframe:  shared.FiveMinute,
+				Direction:  shared.Long,
+				Price:      float64(10),
+				LevelPrice: test.levelPrice,
+				Reasons:    []shared.Reason{shared.BullishEngulfing},
+				Confluence: uint32(6),
+				StopLoss:   float64(8),
+				Status:     make(chan shared.StatusCode, 1),
+			}
+			err := mgr.handleEntrySignal(&entrySignal)
+			assert.NoError(t, err)
+			<-notifyMsgs
+
+			exitSignal := shared.ExitSignal{
+				Market:    market,
+				Timeframe: shared.FiveMinute,
+				Direction: shared.Long,
+				Price:     test.exitPrice,
+				Reasons:   []shared.Reason{shared.StopLossHit},
+				Status:    make(chan shared.StatusCode, 1),
+			}
+			err = mgr.handleExitSignal(&exitSignal)
+			assert.NoError(t, err)
+			<-notifyMsgs
+
+			// Ensure only stop outs of positions entered at levels are recorded.
+			if !test.wantStopOut {
+				assert.Equal(t, len(stopOuts), 0)
+				return
+			}
+
+			assert.Equal(t, len(stopOuts), 1)
+			got := <-stopOuts
+			assert.Equal(t, got.market, market)
+			assert.Equal(t, got.levelPrice, test.levelPrice)
+		})
+	}
+}
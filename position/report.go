@@ -0,0 +1,196 @@
+package position
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+)
+
+// Trade represents a backtested entry matched with its exit.
+type Trade struct {
+	Market     string
+	Direction  shared.Direction
+	EntryPrice float64
+	ExitPrice  float64
+	StopLoss   float64
+	// Reasons are the stringified reasons of the trade's entry.
+	Reasons    string
+	Confluence uint32
+	// R is the outcome of the trade in multiples of the risk taken at entry.
+	R         float64
+	CreatedOn time.Time
+	ClosedOn  time.Time
+}
+
+// BacktestReport represents the performance statistics of a backtest run.
+type BacktestReport struct {
+	Trades int
+	Wins   int
+	Losses int
+	// WinRate is the ratio of winning trades to all trades.
+	WinRate float64
+	// ProfitFactor is the ratio of the gross R won to the gross R lost. It is infinite if
+	// there are wins without losses.
+	ProfitFactor float64
+	// AverageWinR is the average R of winning trades.
+	AverageWinR float64
+	// AverageLossR is the average R of losing trades, it is negative.
+	AverageLossR float64
+	// MaxDrawdownR is the largest peak to trough decline of the cumulative R of trades.
+	MaxDrawdownR float64
+	// OpenPositions is the number of entries without an exit by the end of the run.
+	OpenPositions int
+}
+
+// String stringifies the backtest report.
+func (r BacktestReport) String() string {
+	return fmt.Sprintf("trades %d (wins %d, losses %d), win rate %.2f%%, profit factor %.2f, "+
+		"average win %.2fR, average loss %.2fR, max drawdown %.2fR, open positions %d",
+		r.Trades, r.Wins, r.Losses, r.WinRate*100, r.ProfitFactor, r.AverageWinR,
+		r.AverageLossR, r.MaxDrawdownR, r.OpenPositions)
+}
+
+// BacktestReporter collects the entries and exits of a backtest run and aggregates them
+// into a backtest report.
+//
+// Exits are matched with the oldest unmatched entry of the same market and direction.
+type BacktestReporter struct {
+	entries     map[string][]*Position
+	trades      []Trade
+	tradeWriter *TradeWriter
+	dataMtx     sync.Mutex
+}
+
+// NewBacktestReporter initializes a new backtest reporter. Recorded trades are written to the
+// provided trade writer, they are not exported if nil.
+func NewBacktestReporter(tradeWriter *TradeWriter) *BacktestReporter {
+	return &BacktestReporter{
+		entries:     make(map[string][]*Position),
+		trades:      make([]Trade, 0),
+		tradeWriter: tradeWriter,
+	}
+}
+
+// entryKey returns the key entries of the provided market and direction are tracked by.
+func entryKey(market string, direction shared.Direction) string {
+	return fmt.Sprintf("%s/%s", market, direction.String())
+}
+
+// riskPoints returns the points risked by the provided position.
+func riskPoints(pos *Position) float64 {
+	if pos.StopLossPointsRange > 0 {
+		return pos.StopLossPointsRange
+	}
+
+	return math.Abs(pos.EntryPrice - pos.StopLoss)
+}
+
+// RecordEntry records the provided newly opened position.
+func (r *BacktestReporter) RecordEntry(pos *Position) {
+	entry := *pos
+
+	r.dataMtx.Lock()
+	defer r.dataMtx.Unlock()
+
+	key := entryKey(entry.Market, entry.Direction)
+	r.entries[key] = append(r.entries[key], &entry)
+}
+
+// RecordExit matches the provided closed position with its entry and records the trade.
+func (r *BacktestReporter) RecordExit(pos *Position) error {
+	r.dataMtx.Lock()
+	defer r.dataMtx.Unlock()
+
+	key := entryKey(pos.Market, pos.Direction)
+	entries := r.entries[key]
+	if len(entries) == 0 {
+		return fmt.Errorf("no %s entry found for %s exit", pos.Direction.String(), pos.Market)
+	}
+
+	entry := entries[0]
+	r.entries[key] = entries[1:]
+
+	risk := riskPoints(entry)
+	if risk == 0 {
+		return fmt.Errorf("%s entry @ %.2f for %s has no risk", entry.Direction.String(),
+			entry.EntryPrice, entry.Market)
+	}
+
+	points := pos.ExitPrice - entry.EntryPrice
+	if entry.Direction == shared.Short {
+		points = -points
+	}
+
+	trade := Trade{
+		Market:     entry.Market,
+		Direction:  entry.Direction,
+		EntryPrice: entry.EntryPrice,
+		ExitPrice:  pos.ExitPrice,
+		StopLoss:   entry.StopLoss,
+		Reasons:    entry.EntryReasons,
+		Confluence: entry.Confluence,
+		R:          points / risk,
+		CreatedOn:  entry.CreatedOn,
+		ClosedOn:   pos.ClosedOn,
+	}
+	r.trades = append(r.trades, trade)
+
+	if r.tradeWriter != nil {
+		err := r.tradeWriter.Write(trade)
+		if err != nil {
+			return fmt.Errorf("exporting %s trade: %v", trade.Market, err)
+		}
+	}
+
+	return nil
+}
+
+// Report aggregates the recorded trades into a backtest report.
+func (r *BacktestReporter) Report() BacktestReport {
+	r.dataMtx.Lock()
+	defer r.dataMtx.Unlock()
+
+	report := BacktestReport{Trades: len(r.trades)}
+	for _, entries := range r.entries {
+		report.OpenPositions += len(entries)
+	}
+
+	var grossWin, grossLoss, equity, peak float64
+	for idx := range r.trades {
+		trade := r.trades[idx]
+		switch {
+		case trade.R > 0:
+			report.Wins++
+			grossWin += trade.R
+		case trade.R < 0:
+			report.Losses++
+			grossLoss -= trade.R
+		}
+
+		equity += trade.R
+		peak = max(peak, equity)
+		report.MaxDrawdownR = max(report.MaxDrawdownR, peak-equity)
+	}
+
+	if report.Trades > 0 {
+		report.WinRate = float64(report.Wins) / float64(report.Trades)
+	}
+	if report.Wins > 0 {
+		report.AverageWinR = grossWin / float64(report.Wins)
+	}
+	if report.Losses > 0 {
+		report.AverageLossR = -grossLoss / float64(report.Losses)
+	}
+
+	switch {
+	case grossLoss > 0:
+		report.ProfitFactor = grossWin / grossLoss
+	case grossWin > 0:
+		report.ProfitFactor = math.Inf(1)
+	}
+
+	return report
+}
@@ -0,0 +1,87 @@
+package position
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestBacktestReporter(t *testing.T) {
+	now := time.Now()
+	entry := func(market string, direction shared.Direction, price float64, stopLoss float64) *Position {
+		return &Position{
+			Market:              market,
+			Direction:           direction,
+			EntryPrice:          price,
+			StopLoss:            stopLoss,
+			StopLossPointsRange: math.Abs(price - stopLoss),
+			CreatedOn:           now,
+		}
+	}
+	exit := func(market string, direction shared.Direction, price float64) *Position {
+		return &Position{
+			Market:    market,
+			Direction: direction,
+			ExitPrice: price,
+			ClosedOn:  now.Add(time.Minute * 5),
+		}
+	}
+
+	reporter := NewBacktestReporter(nil)
+
+	// Ensure an empty run reports no trades.
+	report := reporter.Report()
+	assert.Equal(t, report, BacktestReport{})
+
+	// Ensure exits without a matching entry error.
+	err := reporter.RecordExit(exit("^GSPC", shared.Long, 10))
+	assert.Error(t, err)
+
+	reporter.RecordEntry(entry("^GSPC", shared.Long, 100, 98))
+	reporter.RecordEntry(entry("^GSPC", shared.Short, 50, 51))
+	reporter.RecordEntry(entry("^IXIC", shared.Long, 10, 9))
+	reporter.RecordEntry(entry("^GSPC", shared.Long, 101, 99))
+	reporter.RecordEntry(entry("^IXIC", shared.Short, 10, 11))
+
+	// Ensure exits are matched with the oldest entry of the same market and direction.
+	assert.NoError(t, reporter.RecordExit(exit("^GSPC", shared.Long, 104)))
+	assert.NoError(t, reporter.RecordExit(exit("^GSPC", shared.Short, 52)))
+	assert.NoError(t, reporter.RecordExit(exit("^IXIC", shared.Long, 9.5)))
+	assert.NoError(t, reporter.RecordExit(exit("^GSPC", shared.Long, 102)))
+
+	// Ensure exits of a direction without open entries error.
+	err = reporter.RecordExit(exit("^IXIC", shared.Long, 11))
+	assert.Error(t, err)
+
+	// Ensure the statistics of the recorded trades are aggregated.
+	report = reporter.Report()
+	assert.Equal(t, report.Trades, 4)
+	assert.Equal(t, report.Wins, 2)
+	assert.Equal(t, report.Losses, 2)
+	assert.Equal(t, report.WinRate, 0.5)
+	assert.Equal(t, report.ProfitFactor, float64(1))
+	assert.Equal(t, report.AverageWinR, 1.25)
+	assert.Equal(t, report.AverageLossR, -1.25)
+	assert.Equal(t, report.MaxDrawdownR, 2.5)
+	assert.Equal(t, report.OpenPositions, 1)
+	assert.NotEqual(t, report.String(), "")
+
+	// Ensure entries without risk cannot be evaluated.
+	reporter.RecordEntry(entry("^DJI", shared.Long, 10, 10))
+	err = reporter.RecordExit(exit("^DJI", shared.Long, 12))
+	assert.Error(t, err)
+
+	// Ensure a run without losses has an infinite profit factor.
+	reporter = NewBacktestReporter(nil)
+	reporter.RecordEntry(entry("^GSPC", shared.Short, 50, 52))
+	assert.NoError(t, reporter.RecordExit(exit("^GSPC", shared.Short, 47)))
+	report = reporter.Report()
+	assert.Equal(t, report.Trades, 1)
+	assert.Equal(t, report.WinRate, float64(1))
+	assert.Equal(t, report.AverageWinR, 1.5)
+	assert.True(t, math.IsInf(report.ProfitFactor, 1))
+	assert.Equal(t, report.MaxDrawdownR, float64(0))
+}
@@ -0,0 +1,146 @@
+package position
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dnldd/entry/shared"
+)
+
+const (
+	// defaultWinRateWindow is the default number of closed positions the rolling win rate
+	// is evaluated over.
+	defaultWinRateWindow = 10
+	// neutralWinRate is the win rate at which positions are sized at the base size.
+	neutralWinRate = 0.5
+	// minSizeScale is the smallest fraction of the base size a position can be scaled down to.
+	minSizeScale = 0.25
+)
+
+// SizingConfig represents the position sizing configuration.
+type SizingConfig struct {
+	// BaseSize is the size of a position before win rate adjustment.
+	BaseSize float64
+	// MaxSize is the maximum size of a position.
+	MaxSize float64
+	// AdjustForWinRate is the flag for scaling position sizes by the rolling win rate.
+	AdjustForWinRate bool
+	// WinRateWindow is the number of closed positions the rolling win rate is evaluated over.
+	// The default window is used if zero.
+	WinRateWindow int
+	// MaxRisk is the maximum dollar amount a position risks to its stop loss. Sizes are not
+	// capped by risk if zero.
+	MaxRisk float64
+}
+
+// Validate asserts the config sane inputs.
+func (cfg *SizingConfig) Validate() error {
+	var errs error
+
+	if cfg.BaseSize <= 0 {
+		errs = errors.Join(errs, fmt.Errorf("base position size must be greater than zero"))
+	}
+	if cfg.MaxSize < cfg.BaseSize {
+		errs = errors.Join(errs, fmt.Errorf("max position size cannot be less than the base size"))
+	}
+	if cfg.WinRateWindow < 0 {
+		errs = errors.Join(errs, fmt.Errorf("win rate window cannot be negative"))
+	}
+	if cfg.MaxRisk < 0 {
+		errs = errors.Join(errs, fmt.Errorf("max risk cannot be negative"))
+	}
+
+	return errs
+}
+
+// Sizer sizes positions from the base size, scaled by the rolling win rate of closed
+// positions when enabled.
+//
+// Sizes scale linearly with the win rate relative to a neutral win rate of 50 percent, a
+// losing streak scales sizes down to no less than a quarter of the base size and a
+// winning streak scales them back up to no more than the max size.
+type Sizer struct {
+	cfg        *SizingConfig
+	outcomes   []bool
+	start      int
+	count      int
+	outcomeMtx sync.Mutex
+}
+
+// NewSizer initializes a new position sizer.
+func NewSizer(cfg *SizingConfig) (*Sizer, error) {
+	err := cfg.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("validating sizing config: %v", err)
+	}
+
+	window := cfg.WinRateWindow
+	if window == 0 {
+		window = defaultWinRateWindow
+	}
+
+	return &Sizer{
+		cfg:      cfg,
+		outcomes: make([]bool, window),
+	}, nil
+}
+
+// Record adds the outcome of a closed position to the win/loss record. The oldest outcome
+// is evicted once the record spans the win rate window.
+func (s *Sizer) Record(win bool) {
+	s.outcomeMtx.Lock()
+	defer s.outcomeMtx.Unlock()
+
+	size := len(s.outcomes)
+	if s.count == size {
+		s.outcomes[s.start] = win
+		s.start = (s.start + 1) % size
+		return
+	}
+
+	s.outcomes[(s.start+s.count)%size] = win
+	s.count++
+}
+
+// WinRate returns the rolling win rate of the recorded outcomes. The neutral win rate is
+// returned if there are no recorded outcomes.
+func (s *Sizer) WinRate() float64 {
+	s.outcomeMtx.Lock()
+	defer s.outcomeMtx.Unlock()
+
+	if s.count == 0 {
+		return neutralWinRate
+	}
+
+	wins := 0
+	for idx := range s.count {
+		if s.outcomes[(s.start+idx)%len(s.outcomes)] {
+			wins++
+		}
+	}
+
+	return float64(wins) / float64(s.count)
+}
+
+// Size returns the size of the next position.
+func (s *Sizer) Size() float64 {
+	if !s.cfg.AdjustForWinRate {
+		return min(s.cfg.BaseSize, s.cfg.MaxSize)
+	}
+
+	scale := max(s.WinRate()/neutralWinRate, minSizeScale)
+	return min(s.cfg.BaseSize*scale, s.cfg.MaxSize)
+}
+
+// SizeFor returns the size of the next position with the provided stop loss points range on
+// the provided instrument, capped so the dollar amount risked does not exceed the max risk.
+func (s *Sizer) SizeFor(pointsRange float64, instrument *shared.Instrument) float64 {
+	size := s.Size()
+	if s.cfg.MaxRisk == 0 || pointsRange <= 0 {
+		return size
+	}
+
+	risk := instrument.Value(pointsRange, 1)
+	return min(size, s.cfg.MaxRisk/risk)
+}
@@ -0,0 +1,87 @@
+package position
+
+import (
+	"testing"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestSizingConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *SizingConfig
+		wantErr bool
+	}{
+		{"valid config", &SizingConfig{BaseSize: 1, MaxSize: 2, AdjustForWinRate: true, WinRateWindow: 5}, false},
+		{"default window", &SizingConfig{BaseSize: 1, MaxSize: 1}, false},
+		{"zero base size", &SizingConfig{BaseSize: 0, MaxSize: 2}, true},
+		{"max size below base size", &SizingConfig{BaseSize: 2, MaxSize: 1}, true},
+		{"negative win rate window", &SizingConfig{BaseSize: 1, MaxSize: 2, WinRateWindow: -1}, true},
+		{"negative max risk", &SizingConfig{BaseSize: 1, MaxSize: 2, MaxRisk: -1}, true},
+	}
+
+	for _, test := range tests {
+		err := test.cfg.Validate()
+		if test.wantErr {
+			assert.Error(t, err)
+			continue
+		}
+
+		assert.NoError(t, err)
+	}
+}
+
+func TestSizer(t *testing.T) {
+	// Ensure a sizer cannot be created with an invalid config.
+	_, err := NewSizer(&SizingConfig{})
+	assert.Error(t, err)
+
+	// Ensure positions are sized at the base size without a win/loss record.
+	sizer, err := NewSizer(&SizingConfig{BaseSize: 2, MaxSize: 3, AdjustForWinRate: true, WinRateWindow: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, sizer.WinRate(), neutralWinRate)
+	assert.Equal(t, sizer.Size(), float64(2))
+
+	// Ensure a losing streak reduces the size of the next position.
+	sizer.Record(false)
+	sizer.Record(false)
+	assert.Equal(t, sizer.WinRate(), float64(0))
+	assert.Equal(t, sizer.Size(), float64(0.5))
+
+	// Ensure the size is restored as wins bring the win rate back up.
+	sizer.Record(true)
+	sizer.Record(true)
+	assert.Equal(t, sizer.WinRate(), float64(0.5))
+	assert.Equal(t, sizer.Size(), float64(2))
+
+	// Ensure a winning streak evicts older losses and scales the size up, bound by the max size.
+	sizer.Record(true)
+	sizer.Record(true)
+	assert.Equal(t, sizer.WinRate(), float64(1))
+	assert.Equal(t, sizer.Size(), float64(3))
+
+	// Ensure sizes are not adjusted when win rate adjustment is disabled.
+	sizer, err = NewSizer(&SizingConfig{BaseSize: 2, MaxSize: 3})
+	assert.NoError(t, err)
+	sizer.Record(false)
+	sizer.Record(false)
+	assert.Equal(t, sizer.Size(), float64(2))
+}
+
+func TestSizerSizeFor(t *testing.T) {
+	sizer, err := NewSizer(&SizingConfig{BaseSize: 4, MaxSize: 4, MaxRisk: 500})
+	assert.NoError(t, err)
+
+	futures := &shared.Instrument{PointValue: 50, TickSize: 0.25}
+	unit := &shared.Instrument{}
+
+	// Ensure sizes are capped so the dollar amount risked does not exceed the max risk.
+	assert.Equal(t, sizer.SizeFor(5, futures), float64(2))
+
+	// Ensure sizes within the max risk are not capped.
+	assert.Equal(t, sizer.SizeFor(5, unit), float64(4))
+
+	// Ensure sizes are not capped by risk without a stop loss range.
+	assert.Equal(t, sizer.SizeFor(0, futures), float64(4))
+}
@@ -0,0 +1,108 @@
+package position
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dnldd/entry/shared"
+)
+
+// SlippageMode represents how the slippage of simulated fills is measured.
+type SlippageMode int
+
+const (
+	// SlippagePoints slips fills a fixed number of points.
+	SlippagePoints SlippageMode = iota
+	// SlippagePercent slips fills a percentage of the fill price.
+	SlippagePercent
+)
+
+// String stringifies the provided slippage mode.
+func (m SlippageMode) String() string {
+	switch m {
+	case SlippagePoints:
+		return "points"
+	case SlippagePercent:
+		return "percent"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseSlippageMode parses the provided slippage mode.
+func ParseSlippageMode(mode string) (SlippageMode, error) {
+	switch mode {
+	case "", "points":
+		return SlippagePoints, nil
+	case "percent":
+		return SlippagePercent, nil
+	default:
+		return 0, fmt.Errorf("unknown slippage mode provided: %s", mode)
+	}
+}
+
+// SlippageConfig represents the spread and slippage assumed on simulated fills. Fills are
+// always slipped against the position.
+type SlippageConfig struct {
+	// Mode is how slippage is measured.
+	Mode SlippageMode
+	// Entry is the slippage of entry fills, in points or as a percentage of the fill price
+	// depending on the mode.
+	Entry float64
+	// Exit is the slippage of exit fills, in points or as a percentage of the fill price
+	// depending on the mode. Exits use the entry slippage if zero.
+	Exit float64
+}
+
+// Validate asserts the config sane inputs.
+func (cfg *SlippageConfig) Validate() error {
+	var errs error
+
+	if cfg.Mode != SlippagePoints && cfg.Mode != SlippagePercent {
+		errs = errors.Join(errs, fmt.Errorf("unknown slippage mode provided: %s", cfg.Mode.String()))
+	}
+	if cfg.Entry < 0 {
+		errs = errors.Join(errs, fmt.Errorf("entry slippage cannot be negative"))
+	}
+	if cfg.Exit < 0 {
+		errs = errors.Join(errs, fmt.Errorf("exit slippage cannot be negative"))
+	}
+
+	return errs
+}
+
+// points returns the points the provided price is slipped by.
+func (cfg *SlippageConfig) points(price float64, slippage float64) float64 {
+	if cfg.Mode == SlippagePercent {
+		return price * slippage / 100
+	}
+
+	return slippage
+}
+
+// EntryFill returns the simulated fill of an entry in the provided direction at the provided
+// price. Long entries fill higher and short entries fill lower.
+func (cfg *SlippageConfig) EntryFill(direction shared.Direction, price float64) float64 {
+	points := cfg.points(price, cfg.Entry)
+	if direction == shared.Short {
+		return price - points
+	}
+
+	return price + points
+}
+
+// ExitFill returns the simulated fill of an exit of a position in the provided direction at
+// the provided price. Long exits fill lower and short exits fill higher.
+func (cfg *SlippageConfig) ExitFill(direction shared.Direction, price float64) float64 {
+	slippage := cfg.Exit
+	if slippage == 0 {
+		slippage = cfg.Entry
+	}
+
+	points := cfg.points(price, slippage)
+	if direction == shared.Short {
+		return price + points
+	}
+
+	return price - points
+}
@@ -0,0 +1,172 @@
+package position
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/go-co-op/gocron"
+	"github.com/peterldowns/testy/assert"
+	"github.com/rs/zerolog/log"
+)
+
+func TestParseSlippageMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		want    SlippageMode
+		wantErr bool
+	}{
+		{"empty defaults to points", "", SlippagePoints, false},
+		{"points", "points", SlippagePoints, false},
+		{"percent", "percent", SlippagePercent, false},
+		{"unknown", "ticks", 0, true},
+	}
+
+	for _, test := range tests {
+		mode, err := ParseSlippageMode(test.mode)
+		if test.wantErr {
+			assert.Error(t, err)
+			continue
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, mode, test.want)
+	}
+}
+
+func TestSlippageConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *SlippageConfig
+		wantErr bool
+	}{
+		{"valid points config", &SlippageConfig{Mode: SlippagePoints, Entry: 1, Exit: 0.5}, false},
+		{"valid percent config", &SlippageConfig{Mode: SlippagePercent, Entry: 0.01}, false},
+		{"unknown mode", &SlippageConfig{Mode: SlippageMode(9), Entry: 1}, true},
+		{"negative entry slippage", &SlippageConfig{Entry: -1}, true},
+		{"negative exit slippage", &SlippageConfig{Exit: -1}, true},
+	}
+
+	for _, test := range tests {
+		err := test.cfg.Validate()
+		if test.wantErr {
+			assert.Error(t, err)
+			continue
+		}
+
+		assert.NoError(t, err)
+	}
+}
+
+func TestSlippageFills(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       *SlippageConfig
+		direction shared.Direction
+		price     float64
+		wantEntry float64
+		wantExit  float64
+	}{
+		{
+			name:      "long points slippage",
+			cfg:       &SlippageConfig{Mode: SlippagePoints, Entry: 1, Exit: 0.5},
+			direction: shared.Long,
+			price:     100,
+			wantEntry: 101,
+			wantExit:  99.5,
+		},
+		{
+			name:      "short points slippage",
+			cfg:       &SlippageConfig{Mode: SlippagePoints, Entry: 1, Exit: 0.5},
+			direction: shared.Short,
+			price:     100,
+			wantEntry: 99,
+			wantExit:  100.5,
+		},
+		{
+			name:      "exits fall back to the entry slippage",
+			cfg:       &SlippageConfig{Mode: SlippagePoints, Entry: 1},
+			direction: shared.Long,
+			price:     100,
+			wantEntry: 101,
+			wantExit:  99,
+		},
+		{
+			name:      "percent slippage",
+			cfg:       &SlippageConfig{Mode: SlippagePercent, Entry: 2, Exit: 1},
+			direction: shared.Long,
+			price:     200,
+			wantEntry: 204,
+			wantExit:  198,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Ensure fills are slipped against the position.
+			assert.Equal(t, test.cfg.EntryFill(test.direction, test.price), test.wantEntry)
+			assert.Equal(t, test.cfg.ExitFill(test.direction, test.price), test.wantExit)
+		})
+	}
+}
+
+func TestManagerSlippage(t *testing.T) {
+	market := "^GSPC"
+	now := time.Date(2025, 5, 7, 14, 30, 0, 0, time.UTC)
+
+	trades := []struct {
+		direction shared.Direction
+		entry     float64
+		stopLoss  float64
+		exit      float64
+	}{
+		{shared.Long, 100, 95, 110},
+		{shared.Short, 110, 115, 100},
+	}
+
+	// tradePNL runs the scripted trades through a position manager with the provided slippage,
+	// returning the profit and loss of each closed position.
+	tradePNL := func(slippage *SlippageConfig) []float64 {
+		closed := make(chan *Position, len(trades))
+		mgr, err := NewPositionManager(&ManagerConfig{
+			Markets:  []string{market},
+			Notify:   func(message string) {},
+			Slippage: slippage,
+			PersistClosedPosition: func(position *Position) error {
+				closed <- position
+				return nil
+			},
+			JobScheduler: gocron.NewScheduler(time.UTC),
+			Logger:       &log.Logger,
+		})
+		assert.NoError(t, err)
+
+		pnl := make([]float64, 0, len(trades))
+		for idx := range trades {
+			trade := trades[idx]
+
+			entry := shared.NewEntrySignal(market, shared.FiveMinute, trade.direction, trade.entry,
+				[]shared.Reason{shared.StrongMove}, 6, 6, now, trade.stopLoss, 5)
+			err = mgr.handleEntrySignal(&entry)
+			assert.NoError(t, err)
+
+			exit := shared.NewExitSignal(market, shared.FiveMinute, trade.direction, trade.exit,
+				[]shared.Reason{shared.TargetHit}, 6, now.Add(time.Minute*5))
+			err = mgr.handleExitSignal(&exit)
+			assert.NoError(t, err)
+
+			pnl = append(pnl, (<-closed).PNL)
+		}
+
+		return pnl
+	}
+
+	// Ensure fills without slippage are taken at the signal price.
+	frictionless := tradePNL(nil)
+	assert.Equal(t, frictionless, []float64{10, 10})
+
+	// Ensure slipped entries and exits reduce the profit of the same trades.
+	slipped := tradePNL(&SlippageConfig{Mode: SlippagePoints, Entry: 1, Exit: 0.5})
+	assert.Equal(t, slipped, []float64{8.5, 8.5})
+}
@@ -0,0 +1,151 @@
+package position
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// MarketState is the serializable position state of a market.
+type MarketState struct {
+	// Positions are the tracked open and closed positions of the market, ordered by creation.
+	Positions []*Position
+	// Legs are the ids of the open positions of the market, ordered from the oldest.
+	Legs []string
+}
+
+// ManagerState is the serializable position state of all tracked markets, keyed by market.
+type ManagerState struct {
+	Markets map[string]*MarketState
+}
+
+// state returns the serializable state of the market.
+func (m *Market) state() *MarketState {
+	m.positionMtx.RLock()
+	defer m.positionMtx.RUnlock()
+
+	positions := make([]*Position, 0, len(m.positions))
+	for _, position := range m.positions {
+		copied := *position
+		positions = append(positions, &copied)
+	}
+	slices.SortFunc(positions, func(a, b *Position) int {
+		return a.CreatedOn.Compare(b.CreatedOn)
+	})
+
+	return &MarketState{
+		Positions: positions,
+		Legs:      slices.Clone(m.legs),
+	}
+}
+
+// restore replaces the positions of the market with the provided state.
+func (m *Market) restore(state *MarketState) error {
+	positions := make(map[string]*Position, len(state.Positions))
+	for idx := range state.Positions {
+		position := state.Positions[idx]
+		if position.Market != m.cfg.Market {
+			return fmt.Errorf("unexpected position market provided: %s", position.Market)
+		}
+
+		positions[position.ID] = position
+	}
+
+	for idx := range state.Legs {
+		_, ok := positions[state.Legs[idx]]
+		if !ok {
+			return fmt.Errorf("no position found for %s leg %s", m.cfg.Market, state.Legs[idx])
+		}
+	}
+
+	m.positionMtx.Lock()
+	defer m.positionMtx.Unlock()
+
+	m.positions = positions
+	m.legs = slices.Clone(state.Legs)
+	m.skew.Store(uint32(m.netSkew()))
+
+	return nil
+}
+
+// openLegs returns copies of the open positions of the market, ordered from the oldest.
+func (m *Market) openLegs() []*Position {
+	m.positionMtx.RLock()
+	defer m.positionMtx.RUnlock()
+
+	positions := make([]*Position, 0, len(m.legs))
+	for idx := range m.legs {
+		copied := *m.positions[m.legs[idx]]
+		positions = append(positions, &copied)
+	}
+
+	return positions
+}
+
+// restoreOpenPositions replaces the positions of the market with the provided open positions,
+// ordered from the oldest.
+func (m *Market) restoreOpenPositions(positions []*Position) error {
+	legs := make([]string, 0, len(positions))
+	for idx := range positions {
+		legs = append(legs, positions[idx].ID)
+	}
+
+	return m.restore(&MarketState{Positions: positions, Legs: legs})
+}
+
+// persistOpenPositions stores the open positions of the provided market.
+func (m *Manager) persistOpenPositions(mkt *Market) {
+	err := m.store.PersistOpenPositions(mkt.cfg.Market, mkt.openLegs())
+	if err != nil {
+		m.cfg.Logger.Error().Msgf("persisting %s open positions: %v", mkt.cfg.Market, err)
+	}
+}
+
+// SnapshotState serializes the positions of all tracked markets to json.
+func (m *Manager) SnapshotState() ([]byte, error) {
+	m.marketsMtx.RLock()
+	state := &ManagerState{Markets: make(map[string]*MarketState, len(m.markets))}
+	for market, mkt := range m.markets {
+		state.Markets[market] = mkt.state()
+	}
+	m.marketsMtx.RUnlock()
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling position state: %v", err)
+	}
+
+	return data, nil
+}
+
+// RestoreState replaces the positions of tracked markets with the provided json state. The
+// state of untracked markets is ignored.
+func (m *Manager) RestoreState(data []byte) error {
+	var state ManagerState
+	err := json.Unmarshal(data, &state)
+	if err != nil {
+		return fmt.Errorf("unmarshaling position state: %v", err)
+	}
+
+	m.marketsMtx.RLock()
+	defer m.marketsMtx.RUnlock()
+
+	for market, mktState := range state.Markets {
+		mkt, ok := m.markets[market]
+		if !ok {
+			m.cfg.Logger.Info().Msgf("skipping position state of untracked market %s", market)
+			continue
+		}
+
+		err := mkt.restore(mktState)
+		if err != nil {
+			return fmt.Errorf("restoring %s positions: %v", market, err)
+		}
+		m.persistOpenPositions(mkt)
+
+		m.cfg.Logger.Info().Msgf("restored %d positions with %d open legs for %s",
+			len(mktState.Positions), len(mktState.Legs), market)
+	}
+
+	return nil
+}
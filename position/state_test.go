@@ -0,0 +1,89 @@
+package position
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestManagerStateRoundTrip(t *testing.T) {
+	market := "^GSPC"
+	mgr, _, _ := setupManager(t, market)
+
+	mkt, ok := mgr.fetchMarket(market)
+	assert.True(t, ok)
+
+	createdOn := time.Date(2025, 3, 4, 14, 30, 0, 0, time.UTC)
+	directions := []shared.Direction{shared.Long, shared.Long, shared.Short, shared.Long}
+	for idx, direction := range directions {
+		pos, err := NewPosition(&shared.EntrySignal{
+			Market:    market,
+			Timeframe: shared.FiveMinute,
+			Direction: direction,
+			Price:     float64(10),
+			Reasons:   []shared.Reason{shared.ReversalAtSupport, shared.StrongVolume},
+			StopLoss:  float64(8),
+			CreatedOn: createdOn.Add(time.Minute * 5 * time.Duration(idx)),
+		})
+		assert.NoError(t, err)
+
+		err = mkt.AddPosition(pos)
+		assert.NoError(t, err)
+	}
+
+	// Close the oldest long leg so both open and closed positions are captured.
+	_, err := mkt.ClosePositions(&shared.ExitSignal{
+		Market:    market,
+		Timeframe: shared.FiveMinute,
+		Direction: shared.Long,
+		Price:     float64(12),
+		Reasons:   []shared.Reason{shared.TargetHit},
+		CreatedOn: createdOn.Add(time.Minute * 30),
+	})
+	assert.NoError(t, err)
+
+	data, err := mgr.SnapshotState()
+	assert.NoError(t, err)
+
+	// Ensure the state restores into a fresh manager.
+	restored, _, _ := setupManager(t, market)
+	err = restored.RestoreState(data)
+	assert.NoError(t, err)
+
+	restoredMkt, ok := restored.fetchMarket(market)
+	assert.True(t, ok)
+
+	// Ensure positions, open legs and the market skew are restored.
+	assert.Equal(t, len(restoredMkt.positions), len(mkt.positions))
+	for id, pos := range mkt.positions {
+		restoredPos, ok := restoredMkt.positions[id]
+		assert.True(t, ok)
+		assert.Equal(t, restoredPos.Direction, pos.Direction)
+		assert.Equal(t, restoredPos.Status, pos.Status)
+		assert.Equal(t, restoredPos.EntryPrice, pos.EntryPrice)
+		assert.Equal(t, restoredPos.ExitPrice, pos.ExitPrice)
+		assert.Equal(t, restoredPos.PNL, pos.PNL)
+		assert.Equal(t, restoredPos.EntryReasons, pos.EntryReasons)
+		assert.True(t, restoredPos.CreatedOn.Equal(pos.CreatedOn))
+		assert.True(t, restoredPos.ClosedOn.Equal(pos.ClosedOn))
+	}
+	assert.Equal(t, restoredMkt.legs, mkt.legs)
+	assert.Equal(t, restoredMkt.OpenLegs(shared.Long), 2)
+	assert.Equal(t, restoredMkt.OpenLegs(shared.Short), 1)
+	assert.Equal(t, shared.MarketSkew(restoredMkt.skew.Load()), shared.LongSkewed)
+
+	// Ensure state with legs missing their positions errors.
+	err = restored.RestoreState([]byte(`{"Markets":{"^GSPC":{"Positions":[],"Legs":["missing"]}}}`))
+	assert.Error(t, err)
+
+	// Ensure state with positions of another market errors.
+	err = restored.RestoreState([]byte(`{"Markets":{"^GSPC":{"Positions":[{"ID":"a","Market":"^IXIC"}]}}}`))
+	assert.Error(t, err)
+
+	// Ensure the state of untracked markets is ignored.
+	err = restored.RestoreState([]byte(`{"Markets":{"^IXIC":{}}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, restoredMkt.OpenPositions(), 3)
+}
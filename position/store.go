@@ -0,0 +1,66 @@
+package position
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Store defines the requirements for storing the open positions of markets.
+type Store interface {
+	// PersistOpenPositions replaces the stored open positions of the provided market.
+	PersistOpenPositions(market string, positions []*Position) error
+	// LoadOpenPositions returns the stored open positions of the provided market, ordered
+	// from the oldest. Markets without stored positions return none.
+	LoadOpenPositions(market string) ([]*Position, error)
+}
+
+// MemoryStore stores the open positions of markets in memory.
+type MemoryStore struct {
+	positions map[string][]byte
+	mtx       sync.RWMutex
+}
+
+// Ensure the memory store implements the Store interface.
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore initializes a new in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		positions: make(map[string][]byte),
+	}
+}
+
+// PersistOpenPositions replaces the stored open positions of the provided market. The
+// positions are stored serialized, later changes to them are not reflected.
+func (s *MemoryStore) PersistOpenPositions(market string, positions []*Position) error {
+	data, err := json.Marshal(positions)
+	if err != nil {
+		return fmt.Errorf("marshaling %s open positions: %v", market, err)
+	}
+
+	s.mtx.Lock()
+	s.positions[market] = data
+	s.mtx.Unlock()
+
+	return nil
+}
+
+// LoadOpenPositions returns the stored open positions of the provided market, ordered from
+// the oldest.
+func (s *MemoryStore) LoadOpenPositions(market string) ([]*Position, error) {
+	s.mtx.RLock()
+	data, ok := s.positions[market]
+	s.mtx.RUnlock()
+	if !ok {
+		return nil, nil
+	}
+
+	var positions []*Position
+	err := json.Unmarshal(data, &positions)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling %s open positions: %v", market, err)
+	}
+
+	return positions, nil
+}
@@ -0,0 +1,61 @@
+package position
+
+import (
+	"testing"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	market := "^GSPC"
+
+	// Ensure loading a market without stored positions returns none.
+	positions, err := store.LoadOpenPositions(market)
+	assert.NoError(t, err)
+	assert.Equal(t, len(positions), 0)
+
+	long := &Position{
+		ID:         "long",
+		Market:     market,
+		Timeframe:  shared.FiveMinute,
+		Direction:  shared.Long,
+		StopLoss:   8,
+		Size:       2,
+		EntryPrice: 10,
+		Status:     Active,
+	}
+	short := &Position{
+		ID:         "short",
+		Market:     market,
+		Timeframe:  shared.FiveMinute,
+		Direction:  shared.Short,
+		StopLoss:   12,
+		Size:       1,
+		EntryPrice: 10,
+		Status:     Active,
+	}
+
+	// Ensure stored positions are returned in order.
+	err = store.PersistOpenPositions(market, []*Position{long, short})
+	assert.NoError(t, err)
+	positions, err = store.LoadOpenPositions(market)
+	assert.NoError(t, err)
+	assert.Equal(t, len(positions), 2)
+	assert.Equal(t, *positions[0], *long)
+	assert.Equal(t, *positions[1], *short)
+
+	// Ensure later changes to persisted positions are not reflected.
+	long.StopLoss = 9
+	positions, err = store.LoadOpenPositions(market)
+	assert.NoError(t, err)
+	assert.Equal(t, positions[0].StopLoss, float64(8))
+
+	// Ensure persisting replaces the stored positions of the market.
+	err = store.PersistOpenPositions(market, nil)
+	assert.NoError(t, err)
+	positions, err = store.LoadOpenPositions(market)
+	assert.NoError(t, err)
+	assert.Equal(t, len(positions), 0)
+}
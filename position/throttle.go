@@ -0,0 +1,193 @@
+package position
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+)
+
+const (
+	// defaultThrottleInterval is the default rate limit window and digest interval of
+	// notifications.
+	defaultThrottleInterval = time.Minute * 5
+	// digestJobTag is the tag of the scheduled notification digest job.
+	digestJobTag = "position:digest"
+)
+
+// ThrottleMode represents how position notifications are delivered.
+type ThrottleMode int
+
+const (
+	// Immediate delivers every notification as it occurs.
+	Immediate ThrottleMode = iota
+	// RateLimited delivers at most one notification per market within the throttle interval,
+	// suppressed notifications are counted in the next one delivered for the market.
+	RateLimited
+	// Digest batches notifications into a single digest delivered every throttle interval.
+	Digest
+)
+
+// String stringifies the provided throttle mode.
+func (m ThrottleMode) String() string {
+	switch m {
+	case Immediate:
+		return "immediate"
+	case RateLimited:
+		return "ratelimit"
+	case Digest:
+		return "digest"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseThrottleMode parses the throttle mode from the provided string. An empty string
+// defaults to Immediate.
+func ParseThrottleMode(mode string) (ThrottleMode, error) {
+	switch mode {
+	case "", "immediate":
+		return Immediate, nil
+	case "ratelimit":
+		return RateLimited, nil
+	case "digest":
+		return Digest, nil
+	default:
+		return 0, fmt.Errorf("unknown throttle mode provided: %s", mode)
+	}
+}
+
+// ThrottleConfig represents the notification throttle configuration.
+type ThrottleConfig struct {
+	// Mode is how notifications are delivered.
+	Mode ThrottleMode
+	// Interval is the rate limit window of market notifications, or the interval digests are
+	// delivered at. The default interval is used if zero.
+	Interval time.Duration
+}
+
+// Validate asserts the config sane inputs.
+func (cfg *ThrottleConfig) Validate() error {
+	var errs error
+
+	if cfg.Mode < Immediate || cfg.Mode > Digest {
+		errs = errors.Join(errs, fmt.Errorf("unknown throttle mode provided: %d", cfg.Mode))
+	}
+	if cfg.Interval < 0 {
+		errs = errors.Join(errs, fmt.Errorf("throttle interval cannot be negative"))
+	}
+
+	return errs
+}
+
+// interval returns the rate limit window and digest interval of notifications.
+func (cfg *ThrottleConfig) interval() time.Duration {
+	if cfg.Interval == 0 {
+		return defaultThrottleInterval
+	}
+
+	return cfg.Interval
+}
+
+// Throttle throttles notifications by rate limiting them per market or batching them into
+// periodic digests. Critical notifications bypass the throttle.
+type Throttle struct {
+	cfg        *ThrottleConfig
+	notify     func(message string)
+	clock      shared.Clock
+	lastSent   map[string]time.Time
+	suppressed map[string]int
+	digest     []string
+	mtx        sync.Mutex
+}
+
+// NewThrottle initializes a new notification throttle delivering notifications through the
+// provided notify function. The wall clock is used if the provided clock is nil.
+func NewThrottle(cfg *ThrottleConfig, notify func(message string), clock shared.Clock) (*Throttle, error) {
+	err := cfg.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("validating throttle config: %v", err)
+	}
+
+	if clock == nil {
+		clock = shared.WallClock{}
+	}
+
+	return &Throttle{
+		cfg:        cfg,
+		notify:     notify,
+		clock:      clock,
+		lastSent:   make(map[string]time.Time),
+		suppressed: make(map[string]int),
+		digest:     make([]string, 0),
+	}, nil
+}
+
+// Notify delivers the provided notification of the provided market as dictated by the
+// throttle mode. Critical notifications are delivered immediately.
+func (t *Throttle) Notify(market string, message string, critical bool) {
+	if critical || t.cfg.Mode == Immediate {
+		t.notify(message)
+		return
+	}
+
+	t.mtx.Lock()
+	switch t.cfg.Mode {
+	case Digest:
+		t.digest = append(t.digest, message)
+		t.mtx.Unlock()
+		return
+	case RateLimited:
+		now := t.clock.Now()
+		last, ok := t.lastSent[market]
+		if ok && now.Sub(last) < t.cfg.interval() {
+			t.suppressed[market]++
+			t.mtx.Unlock()
+			return
+		}
+
+		if suppressed := t.suppressed[market]; suppressed > 0 {
+			message = fmt.Sprintf("%s (%d earlier %s notifications suppressed)", message,
+				suppressed, market)
+		}
+		delete(t.suppressed, market)
+		t.lastSent[market] = now
+	}
+	t.mtx.Unlock()
+
+	t.notify(message)
+}
+
+// Flush delivers the pending digest of notifications as a single notification.
+func (t *Throttle) Flush() {
+	t.mtx.Lock()
+	pending := t.digest
+	t.digest = make([]string, 0)
+	t.mtx.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	t.notify(fmt.Sprintf("Digest of %d notifications:\n%s", len(pending),
+		strings.Join(pending, "\n")))
+}
+
+// scheduleDigest registers the periodic notification digest job on the job scheduler when
+// notifications are delivered as digests.
+func (m *Manager) scheduleDigest() error {
+	if m.throttle.cfg.Mode != Digest {
+		return nil
+	}
+
+	_, err := m.cfg.JobScheduler.Every(m.throttle.cfg.interval()).Tag(digestJobTag).
+		WaitForSchedule().Do(m.throttle.Flush)
+	if err != nil {
+		return fmt.Errorf("scheduling notification digest job: %v", err)
+	}
+
+	return nil
+}
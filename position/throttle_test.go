@@ -0,0 +1,193 @@
+package position
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestParseThrottleMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		want    ThrottleMode
+		wantErr bool
+	}{
+		{"empty defaults to immediate", "", Immediate, false},
+		{"immediate", "immediate", Immediate, false},
+		{"rate limited", "ratelimit", RateLimited, false},
+		{"digest", "digest", Digest, false},
+		{"unknown", "hourly", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mode, err := ParseThrottleMode(test.mode)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, mode, test.want)
+
+			// Ensure the parsed mode round trips through its string form.
+			parsed, err := ParseThrottleMode(mode.String())
+			assert.NoError(t, err)
+			assert.Equal(t, parsed, mode)
+		})
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	start := time.Date(2025, 3, 4, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		mode ThrottleMode
+		// delivered is the number of notifications delivered before the digest is flushed.
+		delivered int
+		// flushed is the number of notifications delivered after the digest is flushed.
+		flushed int
+	}{
+		{"immediate delivers every notification", Immediate, 11, 11},
+		{"rate limited delivers one per market and the critical notification", RateLimited, 3, 3},
+		{"digest only delivers the critical notification until flushed", Digest, 1, 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			delivered := make([]string, 0)
+			notify := func(message string) {
+				delivered = append(delivered, message)
+			}
+
+			clock := shared.NewReplayClock(start)
+			throttle, err := NewThrottle(&ThrottleConfig{Mode: test.mode, Interval: time.Minute},
+				notify, clock)
+			assert.NoError(t, err)
+
+			// Fire a burst of notifications across two markets and a critical stop out.
+			for idx := range 5 {
+				throttle.Notify("^GSPC", fmt.Sprintf("^GSPC notification %d", idx), false)
+				throttle.Notify("^IXIC", fmt.Sprintf("^IXIC notification %d", idx), false)
+			}
+			throttle.Notify("^GSPC", "^GSPC stopped out", true)
+
+			// Ensure the delivered notifications differ by throttle mode.
+			assert.Equal(t, len(delivered), test.delivered)
+			assert.True(t, strings.Contains(delivered[len(delivered)-1], "stopped out"))
+
+			throttle.Flush()
+			assert.Equal(t, len(delivered), test.flushed)
+
+			switch test.mode {
+			case RateLimited:
+				// Ensure suppressed notifications are counted in the next delivered one once the
+				// rate limit window passes.
+				throttle.Notify("^GSPC", "^GSPC notification 5", false)
+				assert.Equal(t, len(delivered), test.flushed)
+
+				clock.Advance(start.Add(time.Minute))
+				throttle.Notify("^GSPC", "^GSPC notification 6", false)
+				assert.Equal(t, len(delivered), test.flushed+1)
+				assert.Equal(t, delivered[len(delivered)-1],
+					"^GSPC notification 6 (5 earlier ^GSPC notifications suppressed)")
+			case Digest:
+				// Ensure the digest batches all throttled notifications into one.
+				digest := delivered[len(delivered)-1]
+				assert.True(t, strings.HasPrefix(digest, "Digest of 10 notifications:"))
+				assert.Equal(t, len(strings.Split(digest, "\n")), 11)
+
+				// Ensure an empty digest is not delivered.
+				throttle.Flush()
+				assert.Equal(t, len(delivered), test.flushed)
+			}
+		})
+	}
+}
+
+func TestThrottleConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     ThrottleConfig
+		wantErr bool
+	}{
+		{"valid config", ThrottleConfig{Mode: Digest, Interval: time.Minute}, false},
+		{"default interval", ThrottleConfig{Mode: RateLimited}, false},
+		{"negative interval", ThrottleConfig{Mode: RateLimited, Interval: -time.Minute}, true},
+		{"unknown mode", ThrottleConfig{Mode: ThrottleMode(9)}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.cfg.Validate()
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestManagerNotificationDigest(t *testing.T) {
+	market := "^GSPC"
+	mgr, notifyMsgs, _ := setupManager(t, market)
+	mgr.throttle.cfg.Mode = Digest
+
+	// Ensure the digest job is registered on the job scheduler in digest mode.
+	err := mgr.scheduleDigest()
+	assert.NoError(t, err)
+	jobs, err := mgr.cfg.JobScheduler.FindJobsByTag(digestJobTag)
+	assert.NoError(t, err)
+	assert.Equal(t, len(jobs), 1)
+
+	for range 2 {
+		entrySignal := shared.EntrySignal{
+			Market:    market,
+			Timeframe: shared.FiveMinute,
+			Direction: shared.Long,
+			Price:     float64(10),
+			Reasons:   []shared.Reason{shared.BullishEngulfing, shared.StrongVolume},
+			StopLoss:  float64(8),
+			Status:    make(chan shared.StatusCode, 1),
+		}
+
+		err := mgr.handleEntrySignal(&entrySignal)
+		assert.NoError(t, err)
+	}
+
+	// Ensure entries are held for the digest.
+	assert.Equal(t, len(notifyMsgs), 0)
+
+	// Ensure a stop out bypasses the digest while a regular exit does not.
+	for _, price := range []float64{7, 15} {
+		exitSignal := shared.ExitSignal{
+			Market:    market,
+			Timeframe: shared.FiveMinute,
+			Direction: shared.Long,
+			Price:     price,
+			Reasons:   []shared.Reason{shared.StopLossHit},
+			Status:    make(chan shared.StatusCode, 1),
+		}
+
+		err := mgr.handleExitSignal(&exitSignal)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, len(notifyMsgs), 1)
+	msg := <-notifyMsgs
+	assert.True(t, strings.Contains(msg, "Closed long position"))
+	assert.True(t, strings.Contains(msg, "@ 7.00"))
+
+	mgr.throttle.Flush()
+	assert.Equal(t, len(notifyMsgs), 1)
+	msg = <-notifyMsgs
+	assert.True(t, strings.HasPrefix(msg, "Digest of 3 notifications:"))
+}
@@ -0,0 +1,97 @@
+package position
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	// tradesHeaderCSV is the header used for backtest trade csv files.
+	tradesHeaderCSV = []string{"market", "direction", "entrytime", "entryprice", "exittime",
+		"exitprice", "stoploss", "reasons", "confluence", "r"}
+)
+
+// TradeWriter writes backtest trades as csv rows to the underlying writer, a row per trade.
+// The header is written with the first trade.
+type TradeWriter struct {
+	writer        io.Writer
+	csv           *csv.Writer
+	headerWritten bool
+	mtx           sync.Mutex
+}
+
+// NewTradeWriter initializes a new trade writer.
+func NewTradeWriter(writer io.Writer) *TradeWriter {
+	return &TradeWriter{
+		writer: writer,
+		csv:    csv.NewWriter(writer),
+	}
+}
+
+// OpenTradeCSV creates the trade csv file at the provided filepath, truncating it if it
+// exists.
+func OpenTradeCSV(filepath string) (*TradeWriter, error) {
+	file, err := os.Create(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("creating trade csv file: %v", err)
+	}
+
+	return NewTradeWriter(file), nil
+}
+
+// Write writes the provided trade as a csv row and flushes it.
+func (w *TradeWriter) Write(trade Trade) error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if !w.headerWritten {
+		err := w.csv.Write(tradesHeaderCSV)
+		if err != nil {
+			return fmt.Errorf("writing trade csv header: %v", err)
+		}
+		w.headerWritten = true
+	}
+
+	record := []string{
+		trade.Market,
+		trade.Direction.String(),
+		trade.CreatedOn.Format(time.RFC3339),
+		strconv.FormatFloat(trade.EntryPrice, 'f', 3, 64),
+		trade.ClosedOn.Format(time.RFC3339),
+		strconv.FormatFloat(trade.ExitPrice, 'f', 3, 64),
+		strconv.FormatFloat(trade.StopLoss, 'f', 3, 64),
+		trade.Reasons,
+		strconv.FormatUint(uint64(trade.Confluence), 10),
+		strconv.FormatFloat(trade.R, 'f', 3, 64),
+	}
+	err := w.csv.Write(record)
+	if err != nil {
+		return fmt.Errorf("writing trade record: %v", err)
+	}
+
+	w.csv.Flush()
+	err = w.csv.Error()
+	if err != nil {
+		return fmt.Errorf("flushing trade record: %v", err)
+	}
+
+	return nil
+}
+
+// Close closes the underlying writer if it is closable.
+func (w *TradeWriter) Close() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	closer, ok := w.writer.(io.Closer)
+	if !ok {
+		return nil
+	}
+
+	return closer.Close()
+}
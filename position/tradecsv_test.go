@@ -0,0 +1,106 @@
+package position
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestTradeWriter(t *testing.T) {
+	market := "^GSPC"
+	mgr, notifyMsgs, _ := setupManager(t, market)
+
+	var buf bytes.Buffer
+	reporter := NewBacktestReporter(NewTradeWriter(&buf))
+	mgr.cfg.RecordOpenedPosition = reporter.RecordEntry
+	mgr.cfg.PersistClosedPosition = reporter.RecordExit
+
+	start := time.Date(2025, 5, 7, 10, 0, 0, 0, time.UTC)
+	trades := []struct {
+		direction  shared.Direction
+		entry      float64
+		stopLoss   float64
+		exit       float64
+		reasons    []shared.Reason
+		confluence uint32
+	}{
+		{shared.Long, 10, 8, 15, []shared.Reason{shared.BullishEngulfing, shared.StrongVolume}, 6},
+		{shared.Short, 20, 22, 23, []shared.Reason{shared.BearishEngulfing}, 7},
+	}
+
+	// Run a scripted backtest of trades opened and closed in sequence.
+	for idx, trade := range trades {
+		createdOn := start.Add(time.Hour * time.Duration(idx))
+		err := mgr.handleEntrySignal(&shared.EntrySignal{
+			Market:     market,
+			Timeframe:  shared.FiveMinute,
+			Direction:  trade.direction,
+			Price:      trade.entry,
+			Reasons:    trade.reasons,
+			Confluence: trade.confluence,
+			StopLoss:   trade.stopLoss,
+			Status:     make(chan shared.StatusCode, 1),
+			CreatedOn:  createdOn,
+		})
+		assert.NoError(t, err)
+		<-notifyMsgs
+
+		err = mgr.handleExitSignal(&shared.ExitSignal{
+			Market:    market,
+			Timeframe: shared.FiveMinute,
+			Direction: trade.direction,
+			Price:     trade.exit,
+			Reasons:   []shared.Reason{shared.TargetHit},
+			Status:    make(chan shared.StatusCode, 1),
+			CreatedOn: createdOn.Add(time.Minute * 30),
+		})
+		assert.NoError(t, err)
+		<-notifyMsgs
+	}
+
+	// Ensure the header is written once and every trade is flushed as a row.
+	records, err := csv.NewReader(&buf).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, records, [][]string{
+		tradesHeaderCSV,
+		{"^GSPC", "long", "2025-05-07T10:00:00Z", "10.000", "2025-05-07T10:30:00Z", "15.000",
+			"8.000", "bullish engulfing,strong volume", "6", "2.500"},
+		{"^GSPC", "short", "2025-05-07T11:00:00Z", "20.000", "2025-05-07T11:30:00Z", "23.000",
+			"22.000", "bearish engulfing", "7", "-1.500"},
+	})
+}
+
+func TestOpenTradeCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trades.csv")
+	writer, err := OpenTradeCSV(path)
+	assert.NoError(t, err)
+
+	trade := Trade{
+		Market:     "^GSPC",
+		Direction:  shared.Long,
+		EntryPrice: 10,
+		ExitPrice:  12,
+		StopLoss:   9,
+		Reasons:    "strong move",
+		Confluence: 5,
+		R:          2,
+	}
+	assert.NoError(t, writer.Write(trade))
+
+	// Ensure rows are flushed to file before the writer is closed.
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, len(records), 2)
+	assert.Equal(t, records[1][0], "^GSPC")
+	assert.Equal(t, records[1][9], "2.000")
+
+	assert.NoError(t, writer.Close())
+}
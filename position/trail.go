@@ -0,0 +1,167 @@
+package position
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/dnldd/entry/shared"
+)
+
+const (
+	// defaultATRPeriod is the default number of candles the average true range is evaluated over.
+	defaultATRPeriod = 14
+)
+
+// TrailMode represents how the distance of a trailing stop from price is measured.
+type TrailMode int
+
+const (
+	// TrailPoints trails the stop a fixed number of points from price.
+	TrailPoints TrailMode = iota
+	// TrailATR trails the stop a multiple of the average true range from price.
+	TrailATR
+)
+
+// String stringifies the provided trail mode.
+func (m TrailMode) String() string {
+	switch m {
+	case TrailPoints:
+		return "points"
+	case TrailATR:
+		return "atr"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseTrailMode parses the provided trail mode.
+func ParseTrailMode(mode string) (TrailMode, error) {
+	switch mode {
+	case "", "points":
+		return TrailPoints, nil
+	case "atr":
+		return TrailATR, nil
+	default:
+		return 0, fmt.Errorf("unknown trail mode provided: %s", mode)
+	}
+}
+
+// TrailingStopConfig represents the trailing stop configuration.
+type TrailingStopConfig struct {
+	// Mode is how the distance of the stop from price is measured.
+	Mode TrailMode
+	// Activation is the number of points a position has to be in profit by before its stop
+	// starts trailing.
+	Activation float64
+	// Distance is the distance of the stop from price, in points or as a multiple of the
+	// average true range depending on the mode.
+	Distance float64
+	// ATRPeriod is the number of candles the average true range is evaluated over. The
+	// default period is used if zero.
+	ATRPeriod int
+}
+
+// Validate asserts the config sane inputs.
+func (cfg *TrailingStopConfig) Validate() error {
+	var errs error
+
+	if cfg.Mode != TrailPoints && cfg.Mode != TrailATR {
+		errs = errors.Join(errs, fmt.Errorf("unknown trail mode provided: %s", cfg.Mode.String()))
+	}
+	if cfg.Activation < 0 {
+		errs = errors.Join(errs, fmt.Errorf("trail activation cannot be negative"))
+	}
+	if cfg.Distance <= 0 {
+		errs = errors.Join(errs, fmt.Errorf("trail distance must be greater than zero"))
+	}
+	if cfg.ATRPeriod < 0 {
+		errs = errors.Join(errs, fmt.Errorf("atr period cannot be negative"))
+	}
+
+	return errs
+}
+
+// averageTrueRange tracks the simple average of the true range over a rolling window of candles.
+type averageTrueRange struct {
+	ranges    []float64
+	start     int
+	count     int
+	sum       float64
+	prevClose float64
+}
+
+// newAverageTrueRange initializes a new average true range over the provided period.
+func newAverageTrueRange(period int) *averageTrueRange {
+	return &averageTrueRange{
+		ranges: make([]float64, period),
+	}
+}
+
+// Update adds the true range of the provided candle to the window.
+func (a *averageTrueRange) Update(candle *shared.Candlestick) {
+	trueRange := candle.High - candle.Low
+	if a.count > 0 {
+		trueRange = max(trueRange, math.Abs(candle.High-a.prevClose), math.Abs(candle.Low-a.prevClose))
+	}
+	a.prevClose = candle.Close
+
+	size := len(a.ranges)
+	if a.count == size {
+		a.sum -= a.ranges[a.start]
+		a.ranges[a.start] = trueRange
+		a.start = (a.start + 1) % size
+	} else {
+		a.ranges[(a.start+a.count)%size] = trueRange
+		a.count++
+	}
+
+	a.sum += trueRange
+}
+
+// Value returns the average true range, it is false until the window is filled.
+func (a *averageTrueRange) Value() (float64, bool) {
+	if a.count < len(a.ranges) {
+		return 0, false
+	}
+
+	return a.sum / float64(a.count), true
+}
+
+// trailStop ratchets the stop loss of the provided position towards price by the provided
+// distance once the position is in profit by the activation. It returns the previous stop
+// loss and whether the stop moved, stops are never loosened.
+func trailStop(pos *Position, price float64, activation float64, distance float64) (float64, bool) {
+	previous := pos.StopLoss
+
+	switch pos.Direction {
+	case shared.Long:
+		if price-pos.EntryPrice < activation {
+			return previous, false
+		}
+
+		stop := price - distance
+		if stop <= pos.StopLoss {
+			return previous, false
+		}
+
+		pos.StopLoss = stop
+
+	case shared.Short:
+		if pos.EntryPrice-price < activation {
+			return previous, false
+		}
+
+		stop := price + distance
+		if stop >= pos.StopLoss {
+			return previous, false
+		}
+
+		pos.StopLoss = stop
+
+	default:
+		return previous, false
+	}
+
+	return previous, true
+}
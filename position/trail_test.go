@@ -0,0 +1,201 @@
+package position
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/go-co-op/gocron"
+	"github.com/peterldowns/testy/assert"
+	"github.com/rs/zerolog/log"
+)
+
+func TestParseTrailMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		want    TrailMode
+		wantErr bool
+	}{
+		{"empty defaults to points", "", TrailPoints, false},
+		{"points", "points", TrailPoints, false},
+		{"atr", "atr", TrailATR, false},
+		{"unknown", "percent", 0, true},
+	}
+
+	for _, test := range tests {
+		mode, err := ParseTrailMode(test.mode)
+		if test.wantErr {
+			assert.Error(t, err)
+			continue
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, mode, test.want)
+	}
+}
+
+func TestTrailingStopConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *TrailingStopConfig
+		wantErr bool
+	}{
+		{"valid points config", &TrailingStopConfig{Mode: TrailPoints, Activation: 1, Distance: 2}, false},
+		{"valid atr config", &TrailingStopConfig{Mode: TrailATR, Distance: 1.5, ATRPeriod: 5}, false},
+		{"unknown mode", &TrailingStopConfig{Mode: TrailMode(9), Distance: 2}, true},
+		{"negative activation", &TrailingStopConfig{Activation: -1, Distance: 2}, true},
+		{"zero distance", &TrailingStopConfig{}, true},
+		{"negative atr period", &TrailingStopConfig{Mode: TrailATR, Distance: 1, ATRPeriod: -1}, true},
+	}
+
+	for _, test := range tests {
+		err := test.cfg.Validate()
+		if test.wantErr {
+			assert.Error(t, err)
+			continue
+		}
+
+		assert.NoError(t, err)
+	}
+}
+
+func TestAverageTrueRange(t *testing.T) {
+	atr := newAverageTrueRange(2)
+
+	// Ensure the average true range is unavailable until the window is filled.
+	atr.Update(&shared.Candlestick{High: 12, Low: 10, Close: 11})
+	_, ok := atr.Value()
+	assert.False(t, ok)
+
+	// Ensure gaps from the previous close are included in the true range.
+	atr.Update(&shared.Candlestick{High: 16, Low: 14, Close: 15})
+	value, ok := atr.Value()
+	assert.True(t, ok)
+	assert.Equal(t, value, float64(3.5))
+
+	// Ensure the oldest true range is evicted once the window is filled.
+	atr.Update(&shared.Candlestick{High: 16, Low: 15, Close: 15})
+	value, ok = atr.Value()
+	assert.True(t, ok)
+	assert.Equal(t, value, float64(3))
+}
+
+func TestMarketTrailStops(t *testing.T) {
+	market := "^GSPC"
+	loc, err := time.LoadLocation(shared.NewYorkLocation)
+	assert.NoError(t, err)
+
+	newMarket := func(cfg *TrailingStopConfig) *Market {
+		mkt, err := NewMarket(&MarketConfig{
+			Market:       market,
+			TrailingStop: cfg,
+			JobScheduler: gocron.NewScheduler(loc),
+			Logger:       &log.Logger,
+		})
+		assert.NoError(t, err)
+
+		return mkt
+	}
+	open := func(mkt *Market, direction shared.Direction, price float64, stopLoss float64) *Position {
+		pos, err := NewPosition(&shared.EntrySignal{
+			Market:    market,
+			Timeframe: shared.FiveMinute,
+			Direction: direction,
+			Price:     price,
+			StopLoss:  stopLoss,
+			Status:    make(chan shared.StatusCode, 1),
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, mkt.AddPosition(pos))
+
+		return pos
+	}
+	candle := func(timeframe shared.Timeframe, close float64) *shared.Candlestick {
+		return &shared.Candlestick{
+			Open:      close,
+			High:      close + 0.5,
+			Low:       close - 0.5,
+			Close:     close,
+			Market:    market,
+			Timeframe: timeframe,
+		}
+	}
+
+	// Ensure stops are not trailed without a trailing stop config.
+	mkt := newMarket(nil)
+	long := open(mkt, shared.Long, 100, 98)
+	trailed, err := mkt.TrailStops(candle(shared.FiveMinute, 110))
+	assert.NoError(t, err)
+	assert.Equal(t, len(trailed), 0)
+	assert.Equal(t, long.StopLoss, float64(98))
+
+	// Ensure a long stop ratchets up with price once activated and never retreats.
+	mkt = newMarket(&TrailingStopConfig{Mode: TrailPoints, Activation: 2, Distance: 3})
+	long = open(mkt, shared.Long, 100, 98)
+	steps := []struct {
+		close   float64
+		stop    float64
+		trailed bool
+	}{
+		{101, 98, false},  // below the activation.
+		{102, 99, true},   // activated, stop follows price.
+		{104, 101, true},  // stop ratchets up.
+		{103, 101, false}, // price pulls back, stop holds.
+		{100, 101, false}, // price falls below the activation, stop holds.
+		{107, 104, true},  // stop ratchets up again.
+	}
+	for _, step := range steps {
+		trailed, err = mkt.TrailStops(candle(shared.FiveMinute, step.close))
+		assert.NoError(t, err)
+		assert.Equal(t, len(trailed) == 1, step.trailed)
+		assert.Equal(t, long.StopLoss, step.stop)
+	}
+
+	// Ensure the previous stop of a trailed position is reported.
+	trailed, err = mkt.TrailStops(candle(shared.FiveMinute, 108))
+	assert.NoError(t, err)
+	assert.Equal(t, trailed[0].Position.ID, long.ID)
+	assert.Equal(t, trailed[0].Previous, float64(104))
+	assert.Equal(t, long.StopLoss, float64(105))
+
+	// Ensure positions are only trailed by candles of their timeframe.
+	trailed, err = mkt.TrailStops(candle(shared.OneHour, 120))
+	assert.NoError(t, err)
+	assert.Equal(t, len(trailed), 0)
+	assert.Equal(t, long.StopLoss, float64(105))
+
+	// Ensure candles of other markets are rejected.
+	other := candle(shared.FiveMinute, 120)
+	other.Market = "^AAPL"
+	_, err = mkt.TrailStops(other)
+	assert.Error(t, err)
+
+	// Ensure a short stop ratchets down with price and never retreats.
+	mkt = newMarket(&TrailingStopConfig{Mode: TrailPoints, Distance: 2})
+	short := open(mkt, shared.Short, 100, 103)
+	for _, step := range []struct{ close, stop float64 }{{99, 101}, {97, 99}, {98, 99}, {95, 97}} {
+		_, err = mkt.TrailStops(candle(shared.FiveMinute, step.close))
+		assert.NoError(t, err)
+		assert.Equal(t, short.StopLoss, step.stop)
+	}
+
+	// Ensure closed positions are no longer trailed.
+	_, err = mkt.ClosePositions(&shared.ExitSignal{Market: market, Direction: shared.Short, Price: 95})
+	assert.NoError(t, err)
+	_, err = mkt.TrailStops(candle(shared.FiveMinute, 90))
+	assert.NoError(t, err)
+	assert.Equal(t, short.StopLoss, float64(97))
+
+	// Ensure atr stops trail by a multiple of the average true range once it is available.
+	mkt = newMarket(&TrailingStopConfig{Mode: TrailATR, Distance: 2, ATRPeriod: 2})
+	long = open(mkt, shared.Long, 100, 98)
+	_, err = mkt.TrailStops(candle(shared.FiveMinute, 102))
+	assert.NoError(t, err)
+	assert.Equal(t, long.StopLoss, float64(98))
+
+	// The true ranges of the two candles are 1 and 2.5, an average true range of 1.75.
+	_, err = mkt.TrailStops(candle(shared.FiveMinute, 104))
+	assert.NoError(t, err)
+	assert.Equal(t, long.StopLoss, float64(100.5))
+}
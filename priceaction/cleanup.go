@@ -0,0 +1,101 @@
+package priceaction
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+)
+
+const (
+	// staleCleanupJobTag is the tag of the scheduled stale level and imbalance cleanup job.
+	staleCleanupJobTag = "priceaction:cleanup"
+	// defaultStaleCleanupInterval is the default interval stale levels and imbalances are
+	// swept at.
+	defaultStaleCleanupInterval = time.Minute * 15
+)
+
+// staleCleanupInterval returns the interval stale levels and imbalances are swept at.
+func (cfg *ManagerConfig) staleCleanupInterval() time.Duration {
+	if cfg.StaleCleanupInterval == 0 {
+		return defaultStaleCleanupInterval
+	}
+
+	return cfg.StaleCleanupInterval
+}
+
+// outOfRange checks whether the provided price is beyond the stale distance of the current
+// price of the market.
+func (m *Market) outOfRange(price float64) bool {
+	current := m.lastPrice.Load()
+	if m.cfg.StaleDistance == 0 || current == 0 {
+		return false
+	}
+
+	return math.Abs(price-current)/current*100 > m.cfg.StaleDistance
+}
+
+// staleLevel checks whether the provided level is invalidated or out of range. Manual levels
+// are only stale once invalidated.
+func (m *Market) staleLevel(level *shared.Level) bool {
+	if level.IsInvalidated() {
+		return true
+	}
+
+	return !level.Manual && m.outOfRange(level.Price)
+}
+
+// staleImbalance checks whether the provided imbalance is invalidated or out of range, the
+// nearest edge of the imbalance is measured against the current price.
+func (m *Market) staleImbalance(imb *shared.Imbalance) bool {
+	if imb.Invalidated.Load() {
+		return true
+	}
+
+	current := m.lastPrice.Load()
+	if current >= imb.Low && current <= imb.High {
+		return false
+	}
+
+	return m.outOfRange(imb.High) && m.outOfRange(imb.Low)
+}
+
+// SweepStale removes invalidated and out of range levels and imbalances from the market's
+// snapshots. It returns the number of levels and imbalances removed.
+func (m *Market) SweepStale() (int, int) {
+	levels := m.levelSnapshot.Remove(m.staleLevel)
+	imbalances := m.imbalanceSnapshot.Remove(m.staleImbalance)
+
+	return levels, imbalances
+}
+
+// sweepStaleEntries removes stale levels and imbalances from all tracked markets.
+func (m *Manager) sweepStaleEntries() {
+	m.marketsMtx.RLock()
+	defer m.marketsMtx.RUnlock()
+
+	for market, mkt := range m.markets {
+		levels, imbalances := mkt.SweepStale()
+		if levels > 0 || imbalances > 0 {
+			m.cfg.Logger.Info().Msgf("swept %d stale levels and %d stale imbalances for %s",
+				levels, imbalances, market)
+		}
+	}
+}
+
+// scheduleStaleCleanup registers the periodic stale level and imbalance cleanup job on the
+// job scheduler.
+func (m *Manager) scheduleStaleCleanup() error {
+	if m.cfg.JobScheduler == nil {
+		return nil
+	}
+
+	_, err := m.cfg.JobScheduler.Every(m.cfg.staleCleanupInterval()).Tag(staleCleanupJobTag).
+		WaitForSchedule().Do(m.sweepStaleEntries)
+	if err != nil {
+		return fmt.Errorf("scheduling stale cleanup job: %v", err)
+	}
+
+	return nil
+}
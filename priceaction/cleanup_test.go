@@ -0,0 +1,110 @@
+package priceaction
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dnldd/entry/database"
+	"github.com/dnldd/entry/shared"
+	"github.com/go-co-op/gocron"
+	"github.com/peterldowns/testy/assert"
+	"github.com/rs/zerolog/log"
+)
+
+func TestStaleCleanup(t *testing.T) {
+	market := "^GSPC"
+	mgr := setupManager(t, market)
+
+	mkt, ok := mgr.fetchMarket(market)
+	assert.True(t, ok)
+	mkt.cfg.StaleDistance = 10
+	mkt.lastPrice.Store(100)
+
+	liveLevel := shared.NewLevel(market, 95, 100)
+	invalidatedLevel := shared.NewLevel(market, 105, 100)
+	invalidatedLevel.Invalidated.Store(true)
+	distantLevel := shared.NewLevel(market, 150, 100)
+	manualLevel := shared.NewManualLevel(market, 150, shared.Resistance)
+	for _, level := range []*shared.Level{liveLevel, invalidatedLevel, distantLevel, manualLevel} {
+		mkt.AddLevel(level)
+	}
+
+	liveImbalance := shared.NewImbalance(market, shared.FiveMinute, 104, 98, 92, shared.Bullish, 0.7, time.Time{})
+	invalidatedImbalance := shared.NewImbalance(market, shared.FiveMinute, 98, 97, 96, shared.Bullish, 0.7, time.Time{})
+	invalidatedImbalance.Invalidated.Store(true)
+	distantImbalance := shared.NewImbalance(market, shared.FiveMinute, 60, 55, 50, shared.Bullish, 0.7, time.Time{})
+	for _, imb := range []*shared.Imbalance{liveImbalance, invalidatedImbalance, distantImbalance} {
+		mkt.AddImbalance(imb)
+	}
+
+	// Ensure the cleanup job is registered on the job scheduler.
+	scheduler := gocron.NewScheduler(time.UTC)
+	mgr.cfg.JobScheduler = scheduler
+	err := mgr.scheduleStaleCleanup()
+	assert.NoError(t, err)
+
+	jobs, err := scheduler.FindJobsByTag(staleCleanupJobTag)
+	assert.NoError(t, err)
+	assert.Equal(t, len(jobs), 1)
+
+	scheduler.StartAsync()
+	defer scheduler.Stop()
+
+	// Ensure triggering the job sweeps stale entries while live ones remain.
+	err = scheduler.RunByTag(staleCleanupJobTag)
+	assert.NoError(t, err)
+
+	deadline := time.Now().Add(time.Second * 2)
+	for len(mkt.Imbalances()) != 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	levels := mkt.Levels()
+	assert.Equal(t, len(levels), 2)
+	assert.True(t, levels[0] == liveLevel)
+	assert.True(t, levels[1] == manualLevel)
+
+	imbalances := mkt.Imbalances()
+	assert.Equal(t, len(imbalances), 1)
+	assert.True(t, imbalances[0] == liveImbalance)
+}
+
+func TestRestoredManualLevelSurvivesSweep(t *testing.T) {
+	market := "^GSPC"
+	db, err := database.NewSQLite(context.Background(), &database.SQLiteConfig{
+		Filepath: filepath.Join(t.TempDir(), "state.db"),
+		Logger:   &log.Logger,
+	})
+	assert.NoError(t, err)
+	defer db.Close()
+
+	store := &sqliteStore{db: db}
+	distantLevel := shared.NewLevel(market, 150, 100)
+	manualLevel := shared.NewManualLevel(market, 160, shared.Resistance)
+	err = store.PersistState(market, []*shared.Level{distantLevel, manualLevel}, nil)
+	assert.NoError(t, err)
+
+	// Ensure a restarted market restores the manual level from the store.
+	mgr := setupManager(t, market)
+	mgr.cfg.Store = store
+	mgr.store = store
+	mgr.cfg.StaleDistance = 10
+
+	mkt, err := mgr.newMarket(market)
+	assert.NoError(t, err)
+	levels := mkt.Levels()
+	assert.Equal(t, len(levels), 2)
+	assert.True(t, levels[1].Manual)
+
+	// Ensure the restored manual level is exempt from the sweep of out of range levels.
+	mkt.lastPrice.Store(100)
+	swept, _ := mkt.SweepStale()
+	assert.Equal(t, swept, 1)
+
+	levels = mkt.Levels()
+	assert.Equal(t, len(levels), 1)
+	assert.Equal(t, levels[0].Price, manualLevel.Price)
+	assert.True(t, levels[0].Manual)
+}
@@ -0,0 +1,117 @@
+package priceaction
+
+import (
+	"math"
+
+	"github.com/dnldd/entry/shared"
+)
+
+// pendingReaction represents a generated reaction awaiting to be relayed.
+type pendingReaction struct {
+	// focus is the type of focus price reacted to.
+	focus shared.FocusKind
+	// price is the price of the focus price reacted to.
+	price float64
+	// reaction is the reaction to relay.
+	reaction *shared.ReactionAtFocus
+	// signal relays the reaction for processing.
+	signal func()
+}
+
+// reactionBatch collects the reactions generated by a market update.
+type reactionBatch struct {
+	reactions []*pendingReaction
+}
+
+// add adds the provided reaction to the batch.
+func (b *reactionBatch) add(focus shared.FocusKind, price float64, reaction *shared.ReactionAtFocus, signal func()) {
+	b.reactions = append(b.reactions, &pendingReaction{
+		focus:    focus,
+		price:    price,
+		reaction: reaction,
+		signal:   signal,
+	})
+}
+
+// annotateNearbyFoci records the foci of other types reacted to on the same candle as nearby
+// foci of each of the provided reactions.
+func annotateNearbyFoci(reactions []*pendingReaction) {
+	for _, pending := range reactions {
+		for _, other := range reactions {
+			if other.focus == pending.focus || other.reaction.Market != pending.reaction.Market {
+				continue
+			}
+
+			if other.reaction.TaggingCandle == nil || pending.reaction.TaggingCandle == nil ||
+				!other.reaction.TaggingCandle.Date.Equal(pending.reaction.TaggingCandle.Date) {
+				continue
+			}
+
+			pending.reaction.NearbyFoci = append(pending.reaction.NearbyFoci, shared.NearbyFocus{
+				Focus:       other.focus,
+				LevelSource: other.reaction.LevelSource,
+				Distance:    math.Abs(other.price - pending.price),
+			})
+		}
+	}
+}
+
+// coincident checks whether the provided reactions are the same reaction on the same candle
+// at focuses within the provided price tolerance of each other.
+func coincident(a *pendingReaction, b *pendingReaction, tolerance float64) bool {
+	if a.focus == b.focus || a.reaction.Market != b.reaction.Market ||
+		a.reaction.Reaction != b.reaction.Reaction || a.reaction.LevelKind != b.reaction.LevelKind {
+		return false
+	}
+
+	if a.reaction.TaggingCandle == nil || b.reaction.TaggingCandle == nil ||
+		!a.reaction.TaggingCandle.Date.Equal(b.reaction.TaggingCandle.Date) {
+		return false
+	}
+
+	return math.Abs(a.price-b.price) <= tolerance
+}
+
+// debounceReactions collapses coincident reactions to different focus types into the first of
+// them, recording the collapsed focus types as coincident focuses of the kept reaction. The
+// kept reactions are returned in the order they were generated.
+func debounceReactions(reactions []*pendingReaction, tolerance float64) []*pendingReaction {
+	kept := make([]*pendingReaction, 0, len(reactions))
+
+	for _, pending := range reactions {
+		var into *pendingReaction
+		for _, candidate := range kept {
+			if !coincident(candidate, pending, tolerance) {
+				continue
+			}
+
+			// A reaction carries each focus type once.
+			duplicate := false
+			for _, focus := range candidate.reaction.CoincidentFocuses {
+				if focus == pending.focus {
+					duplicate = true
+					break
+				}
+			}
+			if duplicate {
+				continue
+			}
+
+			into = candidate
+			break
+		}
+
+		if into == nil {
+			kept = append(kept, pending)
+			continue
+		}
+
+		into.reaction.CoincidentFocuses = append(into.reaction.CoincidentFocuses, pending.focus)
+		if into.reaction.ImbalanceFill == nil {
+			// Carry the fill of a collapsed imbalance reaction for scoring.
+			into.reaction.ImbalanceFill = pending.reaction.ImbalanceFill
+		}
+	}
+
+	return kept
+}
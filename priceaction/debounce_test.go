@@ -0,0 +1,195 @@
+package priceaction
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestDebounceReactions(t *testing.T) {
+	market := "^GSPC"
+	now := time.Now()
+	tagging := &shared.Candlestick{Market: market, Date: now}
+	later := &shared.Candlestick{Market: market, Date: now.Add(time.Minute * 5)}
+
+	// pending creates a pending reaction at the provided focus and price.
+	pending := func(focus shared.FocusKind, price float64, reaction shared.PriceReaction, kind shared.LevelKind, candle *shared.Candlestick) *pendingReaction {
+		return &pendingReaction{
+			focus: focus,
+			price: price,
+			reaction: &shared.ReactionAtFocus{
+				Market:        market,
+				LevelKind:     kind,
+				Reaction:      reaction,
+				TaggingCandle: candle,
+			},
+			signal: func() {},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		reactions  []*pendingReaction
+		tolerance  float64
+		kept       []shared.FocusKind
+		coincident [][]shared.FocusKind
+	}{
+		{
+			name: "coincident focuses collapse into one reaction",
+			reactions: []*pendingReaction{
+				pending(shared.LevelFocus, 100, shared.Reversal, shared.Support, tagging),
+				pending(shared.VWAPFocus, 100.5, shared.Reversal, shared.Support, tagging),
+				pending(shared.ImbalanceFocus, 99.5, shared.Reversal, shared.Support, tagging),
+			},
+			tolerance:  1,
+			kept:       []shared.FocusKind{shared.LevelFocus},
+			coincident: [][]shared.FocusKind{{shared.VWAPFocus, shared.ImbalanceFocus}},
+		},
+		{
+			name: "distant focuses stay separate",
+			reactions: []*pendingReaction{
+				pending(shared.LevelFocus, 100, shared.Reversal, shared.Support, tagging),
+				pending(shared.VWAPFocus, 105, shared.Reversal, shared.Support, tagging),
+			},
+			tolerance:  1,
+			kept:       []shared.FocusKind{shared.LevelFocus, shared.VWAPFocus},
+			coincident: [][]shared.FocusKind{nil, nil},
+		},
+		{
+			name: "focuses tagged by different candles stay separate",
+			reactions: []*pendingReaction{
+				pending(shared.LevelFocus, 100, shared.Reversal, shared.Support, tagging),
+				pending(shared.VWAPFocus, 100, shared.Reversal, shared.Support, later),
+			},
+			tolerance:  1,
+			kept:       []shared.FocusKind{shared.LevelFocus, shared.VWAPFocus},
+			coincident: [][]shared.FocusKind{nil, nil},
+		},
+		{
+			name: "different reactions stay separate",
+			reactions: []*pendingReaction{
+				pending(shared.LevelFocus, 100, shared.Reversal, shared.Support, tagging),
+				pending(shared.VWAPFocus, 100, shared.Break, shared.Support, tagging),
+				pending(shared.ImbalanceFocus, 100, shared.Reversal, shared.Resistance, tagging),
+			},
+			tolerance:  1,
+			kept:       []shared.FocusKind{shared.LevelFocus, shared.VWAPFocus, shared.ImbalanceFocus},
+			coincident: [][]shared.FocusKind{nil, nil, nil},
+		},
+		{
+			name: "reactions at the same focus type stay separate",
+			reactions: []*pendingReaction{
+				pending(shared.LevelFocus, 100, shared.Reversal, shared.Support, tagging),
+				pending(shared.LevelFocus, 100.5, shared.Reversal, shared.Support, tagging),
+				pending(shared.VWAPFocus, 100.2, shared.Reversal, shared.Support, tagging),
+			},
+			tolerance:  1,
+			kept:       []shared.FocusKind{shared.LevelFocus, shared.LevelFocus},
+			coincident: [][]shared.FocusKind{{shared.VWAPFocus}, nil},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kept := debounceReactions(test.reactions, test.tolerance)
+			assert.Equal(t, len(kept), len(test.kept))
+			for idx := range kept {
+				assert.Equal(t, kept[idx].focus, test.kept[idx])
+				assert.Equal(t, kept[idx].reaction.CoincidentFocuses, test.coincident[idx])
+			}
+		})
+	}
+}
+
+func TestAnnotateNearbyFoci(t *testing.T) {
+	market := "^GSPC"
+	now := time.Now()
+	tagging := &shared.Candlestick{Market: market, Date: now}
+	later := &shared.Candlestick{Market: market, Date: now.Add(time.Minute * 5)}
+
+	level := &pendingReaction{
+		focus: shared.LevelFocus,
+		price: 100,
+		reaction: &shared.ReactionAtFocus{
+			Market:        market,
+			LevelSource:   shared.DailyLevel,
+			TaggingCandle: tagging,
+		},
+	}
+	vwap := &pendingReaction{
+		focus: shared.VWAPFocus,
+		price: 100.25,
+		reaction: &shared.ReactionAtFocus{
+			Market:        market,
+			TaggingCandle: tagging,
+		},
+	}
+	imbalance := &pendingReaction{
+		focus: shared.ImbalanceFocus,
+		price: 100,
+		reaction: &shared.ReactionAtFocus{
+			Market:        market,
+			TaggingCandle: later,
+		},
+	}
+
+	annotateNearbyFoci([]*pendingReaction{level, vwap, imbalance})
+
+	// Ensure reactions on the same candle record each other as nearby foci.
+	assert.Equal(t, level.reaction.NearbyFoci, []shared.NearbyFocus{
+		{Focus: shared.VWAPFocus, Distance: 0.25},
+	})
+	assert.Equal(t, vwap.reaction.NearbyFoci, []shared.NearbyFocus{
+		{Focus: shared.LevelFocus, LevelSource: shared.DailyLevel, Distance: 0.25},
+	})
+
+	// Ensure reactions on other candles are not recorded as nearby foci.
+	assert.Equal(t, len(imbalance.reaction.NearbyFoci), 0)
+}
+
+func TestManagerRelayReactions(t *testing.T) {
+	market := "^GSPC"
+	now := time.Now()
+	tagging := &shared.Candlestick{Market: market, Date: now}
+
+	relay := func(debounce float64) []*shared.ReactionAtFocus {
+		mgr := setupManager(t, market)
+		mgr.cfg.ReactionDebounce = debounce
+
+		relayed := make([]*shared.ReactionAtFocus, 0)
+		batch := &reactionBatch{}
+		for _, focus := range []shared.FocusKind{shared.LevelFocus, shared.VWAPFocus, shared.ImbalanceFocus} {
+			reaction := &shared.ReactionAtFocus{
+				Market:        market,
+				LevelKind:     shared.Support,
+				Reaction:      shared.Reversal,
+				TaggingCandle: tagging,
+				Status:        make(chan shared.StatusCode, 1),
+			}
+			batch.add(focus, 100, reaction, func() {
+				relayed = append(relayed, reaction)
+				reaction.Status <- shared.Processed
+			})
+		}
+
+		err := mgr.relayReactions(batch)
+		assert.NoError(t, err)
+
+		return relayed
+	}
+
+	// Ensure coincident reactions are relayed separately when reactions are not debounced.
+	relayed := relay(0)
+	assert.Equal(t, len(relayed), 3)
+	for idx := range relayed {
+		assert.Equal(t, len(relayed[idx].CoincidentFocuses), 0)
+	}
+
+	// Ensure coincident reactions are relayed as a single reaction carrying all focus types
+	// when reactions are debounced.
+	relayed = relay(0.5)
+	assert.Equal(t, len(relayed), 1)
+	assert.Equal(t, relayed[0].CoincidentFocuses, []shared.FocusKind{shared.VWAPFocus, shared.ImbalanceFocus})
+}
@@ -0,0 +1,47 @@
+package priceaction
+
+import (
+	"fmt"
+)
+
+// ReactionEvaluation represents when market updates are evaluated for reactions.
+type ReactionEvaluation int
+
+const (
+	// EvaluateOnClose only evaluates closed candles for reactions, candles are closed at
+	// their timeframe boundaries.
+	EvaluateOnClose ReactionEvaluation = iota
+	// EvaluateOnUpdate evaluates every market update for reactions, including updates of
+	// forming candles.
+	EvaluateOnUpdate
+)
+
+// String stringifies the provided reaction evaluation.
+func (e ReactionEvaluation) String() string {
+	switch e {
+	case EvaluateOnClose:
+		return "close"
+	case EvaluateOnUpdate:
+		return "update"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseReactionEvaluation parses the reaction evaluation from the provided string. An empty
+// string defaults to EvaluateOnClose.
+func ParseReactionEvaluation(evaluation string) (ReactionEvaluation, error) {
+	switch evaluation {
+	case "", "close":
+		return EvaluateOnClose, nil
+	case "update":
+		return EvaluateOnUpdate, nil
+	default:
+		return 0, fmt.Errorf("unknown reaction evaluation provided: %s", evaluation)
+	}
+}
+
+// evaluates checks whether the provided candle is evaluated for reactions.
+func (e ReactionEvaluation) evaluates(closed bool) bool {
+	return closed || e == EvaluateOnUpdate
+}
@@ -0,0 +1,38 @@
+package priceaction
+
+import (
+	"testing"
+
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestParseReactionEvaluation(t *testing.T) {
+	tests := []struct {
+		name       string
+		evaluation string
+		want       ReactionEvaluation
+		wantErr    bool
+	}{
+		{"empty defaults to close", "", EvaluateOnClose, false},
+		{"close", "close", EvaluateOnClose, false},
+		{"update", "update", EvaluateOnUpdate, false},
+		{"unknown evaluation", "tick", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			evaluation, err := ParseReactionEvaluation(test.evaluation)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			// Ensure the parsed evaluation round trips through its string.
+			assert.NoError(t, err)
+			assert.Equal(t, evaluation, test.want)
+			if test.evaluation != "" {
+				assert.Equal(t, evaluation.String(), test.evaluation)
+			}
+		})
+	}
+}
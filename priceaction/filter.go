@@ -0,0 +1,47 @@
+package priceaction
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/dnldd/entry/shared"
+)
+
+// ReactionFilter represents the minimum quality bar a reaction has to meet to be relayed
+// for evaluation.
+type ReactionFilter struct {
+	// IgnoreChop is the flag for filtering out chop reactions.
+	IgnoreChop bool
+	// MinPriceMovement is the minimum distance between the current price and the reaction
+	// focus, as a percentage of the focus price.
+	MinPriceMovement float64
+}
+
+// Validate asserts the filter sane inputs.
+func (f *ReactionFilter) Validate() error {
+	if f.MinPriceMovement < 0 {
+		return fmt.Errorf("minimum price movement cannot be negative")
+	}
+
+	return nil
+}
+
+// Allows checks whether the provided reaction at the provided focus price meets the filter.
+func (f *ReactionFilter) Allows(reaction *shared.ReactionAtFocus, focusPrice float64) bool {
+	if f.IgnoreChop && reaction.Reaction == shared.Chop {
+		return false
+	}
+
+	if f.MinPriceMovement > 0 {
+		if focusPrice == 0 {
+			return false
+		}
+
+		movement := math.Abs(reaction.CurrentPrice-focusPrice) / math.Abs(focusPrice) * 100
+		if movement < f.MinPriceMovement {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,90 @@
+package priceaction
+
+import (
+	"testing"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestReactionFilterValidate(t *testing.T) {
+	// Ensure a negative minimum price movement errors.
+	filter := &ReactionFilter{MinPriceMovement: -0.5}
+	assert.Error(t, filter.Validate())
+
+	// Ensure a valid filter passes validation.
+	filter = &ReactionFilter{IgnoreChop: true, MinPriceMovement: 0.5}
+	assert.NoError(t, filter.Validate())
+}
+
+func TestReactionFilterAllows(t *testing.T) {
+	tests := []struct {
+		name       string
+		filter     ReactionFilter
+		reaction   shared.PriceReaction
+		current    float64
+		focusPrice float64
+		want       bool
+	}{
+		{
+			name:       "empty filter allows chop",
+			filter:     ReactionFilter{},
+			reaction:   shared.Chop,
+			current:    100,
+			focusPrice: 100,
+			want:       true,
+		},
+		{
+			name:       "chop filtered",
+			filter:     ReactionFilter{IgnoreChop: true},
+			reaction:   shared.Chop,
+			current:    105,
+			focusPrice: 100,
+			want:       false,
+		},
+		{
+			name:       "reversal allowed when ignoring chop",
+			filter:     ReactionFilter{IgnoreChop: true},
+			reaction:   shared.Reversal,
+			current:    100.1,
+			focusPrice: 100,
+			want:       true,
+		},
+		{
+			name:       "marginal movement filtered",
+			filter:     ReactionFilter{MinPriceMovement: 1},
+			reaction:   shared.Reversal,
+			current:    100.5,
+			focusPrice: 100,
+			want:       false,
+		},
+		{
+			name:       "clear movement below focus allowed",
+			filter:     ReactionFilter{MinPriceMovement: 1},
+			reaction:   shared.Break,
+			current:    98,
+			focusPrice: 100,
+			want:       true,
+		},
+		{
+			name:       "zero focus price filtered",
+			filter:     ReactionFilter{MinPriceMovement: 1},
+			reaction:   shared.Break,
+			current:    98,
+			focusPrice: 0,
+			want:       false,
+		},
+	}
+
+	for _, test := range tests {
+		reaction := &shared.ReactionAtFocus{
+			Reaction:     test.reaction,
+			CurrentPrice: test.current,
+		}
+
+		allowed := test.filter.Allows(reaction, test.focusPrice)
+		if allowed != test.want {
+			t.Errorf("%s: expected %v, got %v", test.name, test.want, allowed)
+		}
+	}
+}
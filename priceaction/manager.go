@@ -4,18 +4,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/dnldd/entry/shared"
+	"github.com/go-co-op/gocron"
 	"github.com/rs/zerolog"
+	"go.uber.org/atomic"
 )
 
 const (
 	// bufferSize is the default buffer size for channels.
 	bufferSize = 64
-	// workerBufferSize is the default buffer size for workers.
+	// workerBufferSize is the default number of concurrent workers of a market.
 	workerBufferSize = 4
-	// maxWorkers is the maximum number of concurrent workers.
+	// maxWorkers is the default maximum number of concurrent request workers.
 	maxWorkers = 8
 	// candleMetadataSize is the required elements for fetching candle metadata.
 	candleMetadataSize = 4
@@ -33,6 +37,9 @@ type ManagerConfig struct {
 	RequestVWAPData func(request shared.VWAPDataRequest)
 	// RequestVWAP relays the provided vwap request for processing.
 	RequestVWAP func(request shared.VWAPRequest)
+	// RequestVWAPBands relays the provided vwap bands request for processing. Required when
+	// vwap bands are enabled.
+	RequestVWAPBands func(request shared.VWAPBandsRequest)
 	// SignalReactionAtLevel relays a reaction at a level for processing.
 	SignalReactionAtLevel func(signal shared.ReactionAtLevel)
 	// SignalVWAPReaction relays a vwap reaction for processing.
@@ -41,10 +48,107 @@ type ManagerConfig struct {
 	SignalReactionAtImbalance func(signal shared.ReactionAtImbalance)
 	// FetchCaughtUpState returns the caught up statis of the provided market.
 	FetchCaughtUpState func(market string) (bool, error)
+	// FetchWarmedUpState returns whether the provided market has enough candles of the
+	// provided timeframe to evaluate reactions. Optional, reactions are generated once
+	// markets are caught up if nil.
+	FetchWarmedUpState func(market string, timeframe shared.Timeframe) (bool, error)
+	// ReactionFilter is the minimum quality bar for relaying reactions. All reactions are
+	// relayed if nil.
+	ReactionFilter *ReactionFilter
+	// Classification is the config the candles of reactions are classified by. The default
+	// config is used if nil.
+	Classification *shared.ClassificationConfig
+	// ReactionWindow is the number of candles reactions are evaluated over. The default
+	// window of shared.PriceDataPayloadSize is used if zero.
+	ReactionWindow uint32
+	// RequireFullWindow is the flag for only evaluating reactions once the full reaction
+	// window has formed, partial windows are deferred until enough candles close.
+	RequireFullWindow bool
+	// MinReactionCandles is the minimum number of candles reactions are evaluated with,
+	// reactions with fewer candles are deferred until enough candles close. The minimum of
+	// shared.MinReactionWindow is used if zero.
+	MinReactionCandles uint32
+	// Timeouts represents the timeouts of price data, vwap, candle metadata and reaction
+	// status waits. The default timeouts are used if nil.
+	Timeouts *shared.Timeouts
+	// ReactionDebounce is the price distance within which reactions to different focus types
+	// on the same candle are collapsed into a single reaction. Reactions are not debounced
+	// if zero.
+	ReactionDebounce float64
+	// VWAPMismatchTolerance is the number of entries vwap and price data of a vwap reaction
+	// can differ in length by. Mismatched data is truncated to the shorter of the two.
+	VWAPMismatchTolerance uint32
+	// RequireImbalancePurge is the flag for only reacting to imbalances after price has purged them.
+	RequireImbalancePurge bool
+	// VWAPBands is the flag for evaluating reactions at the standard deviation bands of the
+	// vwap along with the vwap line.
+	VWAPBands bool
+	// LateBreakHandling is how breaks only confirmed by the last close of the reaction window
+	// are classified.
+	LateBreakHandling shared.LateBreakHandling
+	// ReactionEvaluation is when market updates are evaluated for reactions.
+	ReactionEvaluation ReactionEvaluation
+	// MarketWorkers is the number of signals of a market handled concurrently. The default
+	// of workerBufferSize is used if zero.
+	MarketWorkers int
+	// RequestWorkers is the number of requests handled concurrently across all markets. The
+	// default of maxWorkers is used if zero.
+	RequestWorkers int
+	// ErrorSink receives processing errors in addition to them being logged. Errors are
+	// dropped if the sink is at capacity, they are only logged if nil.
+	ErrorSink chan error
+	// DrainGracePeriod is the maximum time spent handling buffered and in-flight signals on
+	// shutdown. The default grace period is used if zero.
+	DrainGracePeriod time.Duration
+	// Store persists the levels and imbalances of markets as they change and restores them
+	// when markets are created. An in-memory store is used if nil.
+	Store Store
+	// PauseReactions stops reactions of the provided market from being evaluated downstream.
+	// Optional, paused markets only stop generating reactions if nil.
+	PauseReactions func(market string) error
+	// ResumeReactions resumes evaluating reactions of the provided market downstream.
+	// Optional, paused markets only resume generating reactions if nil.
+	ResumeReactions func(market string) error
+	// StaleDistance is the distance from the current price, as a percentage of it, beyond
+	// which levels and imbalances are swept as stale. Distant entries are kept if zero.
+	StaleDistance float64
+	// StaleCleanupInterval is the interval stale levels and imbalances are swept at. The
+	// default interval is used if zero.
+	StaleCleanupInterval time.Duration
+	// JobScheduler represents the job scheduler the stale cleanup job is registered on.
+	// Optional, stale levels and imbalances are not swept if nil.
+	JobScheduler *gocron.Scheduler
 	// Logger represents the application logger.
 	Logger *zerolog.Logger
 }
 
+// classification returns the config the candles of reactions are classified by.
+func (cfg *ManagerConfig) classification() shared.ClassificationConfig {
+	if cfg.Classification == nil {
+		return shared.DefaultClassificationConfig()
+	}
+
+	return *cfg.Classification
+}
+
+// marketWorkers returns the number of signals of a market handled concurrently.
+func (cfg *ManagerConfig) marketWorkers() int {
+	if cfg.MarketWorkers == 0 {
+		return workerBufferSize
+	}
+
+	return cfg.MarketWorkers
+}
+
+// requestWorkers returns the number of requests handled concurrently.
+func (cfg *ManagerConfig) requestWorkers() int {
+	if cfg.RequestWorkers == 0 {
+		return maxWorkers
+	}
+
+	return cfg.RequestWorkers
+}
+
 // Validate asserts the config sane inputs.
 func (cfg *ManagerConfig) Validate() error {
 	var errs error
@@ -61,6 +165,10 @@ func (cfg *ManagerConfig) Validate() error {
 	if cfg.RequestVWAPData == nil {
 		errs = errors.Join(errs, fmt.Errorf("request vwap data function cannot be nil"))
 	}
+	if cfg.VWAPBands && cfg.RequestVWAPBands == nil {
+		errs = errors.Join(errs, fmt.Errorf("request vwap bands function cannot be nil "+
+			"when vwap bands are enabled"))
+	}
 	if cfg.SignalReactionAtLevel == nil {
 		errs = errors.Join(errs, fmt.Errorf("signal reaction at level function cannot be nil"))
 	}
@@ -73,6 +181,50 @@ func (cfg *ManagerConfig) Validate() error {
 	if cfg.FetchCaughtUpState == nil {
 		errs = errors.Join(errs, fmt.Errorf("fetch caught up state function cannot be nil"))
 	}
+	if cfg.ReactionFilter != nil {
+		err := cfg.ReactionFilter.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating reaction filter: %v", err))
+		}
+	}
+	if cfg.Classification != nil {
+		err := cfg.Classification.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating classification config: %v", err))
+		}
+	}
+	if cfg.ReactionWindow != 0 && cfg.ReactionWindow < shared.MinReactionWindow {
+		errs = errors.Join(errs, fmt.Errorf("reaction window must be at least %d candles",
+			shared.MinReactionWindow))
+	}
+	err := validateMinReactionCandles(cfg.MinReactionCandles, cfg.ReactionWindow)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if cfg.Timeouts != nil {
+		err := cfg.Timeouts.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating timeouts: %v", err))
+		}
+	}
+	if cfg.ReactionDebounce < 0 {
+		errs = errors.Join(errs, fmt.Errorf("reaction debounce cannot be negative"))
+	}
+	if cfg.MarketWorkers < 0 {
+		errs = errors.Join(errs, fmt.Errorf("market workers must be positive"))
+	}
+	if cfg.RequestWorkers < 0 {
+		errs = errors.Join(errs, fmt.Errorf("request workers must be positive"))
+	}
+	if cfg.DrainGracePeriod < 0 {
+		errs = errors.Join(errs, fmt.Errorf("drain grace period cannot be negative"))
+	}
+	if cfg.StaleDistance < 0 {
+		errs = errors.Join(errs, fmt.Errorf("stale distance cannot be negative"))
+	}
+	if cfg.StaleCleanupInterval < 0 {
+		errs = errors.Join(errs, fmt.Errorf("stale cleanup interval cannot be negative"))
+	}
 	if cfg.Logger == nil {
 		errs = errors.Join(errs, fmt.Errorf("logger cannot be nil"))
 	}
@@ -80,16 +232,43 @@ func (cfg *ManagerConfig) Validate() error {
 	return errs
 }
 
+// marketWorker represents the dedicated worker of a market.
+type marketWorker struct {
+	slots chan struct{}
+	// waiting is the number of signals waiting on the worker's slots.
+	waiting atomic.Int32
+}
+
+// newMarketWorker initializes a market worker with the provided number of slots.
+func newMarketWorker(size int) *marketWorker {
+	return &marketWorker{
+		slots: make(chan struct{}, size),
+	}
+}
+
+// depth returns the number of signals being handled and waiting on the worker.
+func (w *marketWorker) depth() int {
+	return len(w.slots) + int(w.waiting.Load())
+}
+
 // Manager represents the price action manager.
 type Manager struct {
-	cfg              *ManagerConfig
-	markets          map[string]*Market
-	levelSignals     chan shared.LevelSignal
-	imbalanceSignals chan shared.ImbalanceSignal
-	updateSignals    chan shared.Candlestick
-	metaSignals      chan shared.CandleMetadataRequest
-	workers          map[string]chan struct{}
-	requestWorkers   chan struct{}
+	cfg               *ManagerConfig
+	drops             *shared.DropCounter
+	markets           map[string]*Market
+	marketsMtx        sync.RWMutex
+	levelSignals      chan shared.LevelSignal
+	imbalanceSignals  chan shared.ImbalanceSignal
+	orderBlockSignals chan shared.OrderBlockSignal
+	updateSignals     chan shared.Candlestick
+	metaSignals       chan shared.CandleMetadataRequest
+	workers           map[string]*marketWorker
+	requestWorkers    chan struct{}
+	store             Store
+	inflight          sync.WaitGroup
+	// runIterations counts the iterations of the run loop, an idle run loop blocks without
+	// advancing it.
+	runIterations atomic.Uint64
 }
 
 // NewManager initializes a new price action manager.
@@ -99,37 +278,259 @@ func NewManager(cfg *ManagerConfig) (*Manager, error) {
 		return nil, fmt.Errorf("validating price action manager config: %v", err)
 	}
 
-	markets := make(map[string]*Market)
-	workers := make(map[string]chan struct{})
+	store := cfg.Store
+	if store == nil {
+		store = NewMemoryStore()
+	}
+
+	mgr := &Manager{
+		cfg:               cfg,
+		drops:             shared.NewDropCounter(),
+		markets:           make(map[string]*Market),
+		levelSignals:      make(chan shared.LevelSignal, bufferSize),
+		imbalanceSignals:  make(chan shared.ImbalanceSignal, bufferSize),
+		orderBlockSignals: make(chan shared.OrderBlockSignal, bufferSize),
+		updateSignals:     make(chan shared.Candlestick, bufferSize),
+		metaSignals:       make(chan shared.CandleMetadataRequest, bufferSize),
+		requestWorkers:    make(chan struct{}, cfg.requestWorkers()),
+		workers:           make(map[string]*marketWorker),
+		store:             store,
+	}
+
 	for idx := range cfg.Markets {
 		market := cfg.Markets[idx]
 
-		workers[market] = make(chan struct{}, workerBufferSize)
+		mkt, err := mgr.newMarket(market)
+		if err != nil {
+			return nil, err
+		}
+
+		mgr.markets[market] = mkt
+		mgr.workers[market] = newMarketWorker(cfg.marketWorkers())
+	}
+
+	err = mgr.scheduleStaleCleanup()
+	if err != nil {
+		return nil, err
+	}
+
+	return mgr, nil
+}
+
+// newMarket creates a market using the manager's market configuration.
+func (m *Manager) newMarket(market string) (*Market, error) {
+	cfg := &MarketConfig{
+		Market:                market,
+		RequestVWAPData:       m.cfg.RequestVWAPData,
+		RequestVWAP:           m.cfg.RequestVWAP,
+		FetchCaughtUpState:    m.cfg.FetchCaughtUpState,
+		FetchWarmedUpState:    m.cfg.FetchWarmedUpState,
+		ReactionFilter:        m.cfg.ReactionFilter,
+		ReactionWindow:        m.cfg.ReactionWindow,
+		RequireFullWindow:     m.cfg.RequireFullWindow,
+		MinReactionCandles:    m.cfg.MinReactionCandles,
+		Timeouts:              m.cfg.Timeouts,
+		RequireImbalancePurge: m.cfg.RequireImbalancePurge,
+		VWAPBands:             m.cfg.VWAPBands,
+		LateBreakHandling:     m.cfg.LateBreakHandling,
+		ReactionEvaluation:    m.cfg.ReactionEvaluation,
+		StaleDistance:         m.cfg.StaleDistance,
+		Logger:                m.cfg.Logger,
+	}
+	mkt, err := NewMarket(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("creating %s market: %v", market, err)
+	}
+
+	levels, imbalances, err := m.store.LoadState(market)
+	if err != nil {
+		return nil, fmt.Errorf("loading %s state: %v", market, err)
+	}
+
+	for idx := range levels {
+		mkt.AddLevel(levels[idx])
+	}
+	for idx := range imbalances {
+		mkt.AddImbalance(imbalances[idx])
+	}
+
+	if len(levels) > 0 || len(imbalances) > 0 {
+		m.cfg.Logger.Info().Msgf("restored %d levels and %d imbalances for %s", len(levels),
+			len(imbalances), market)
+	}
+
+	return mkt, nil
+}
+
+// persistState stores the levels and imbalances of the provided market.
+func (m *Manager) persistState(market string, mkt *Market) error {
+	err := m.store.PersistState(market, mkt.Levels(), mkt.Imbalances())
+	if err != nil {
+		return fmt.Errorf("persisting %s state: %v", market, err)
+	}
 
-		cfg := &MarketConfig{
-			Market:             market,
-			RequestVWAPData:    cfg.RequestVWAPData,
-			RequestVWAP:        cfg.RequestVWAP,
-			FetchCaughtUpState: cfg.FetchCaughtUpState,
-			Logger:             cfg.Logger,
+	return nil
+}
+
+// persistAllState stores the levels and imbalances of all tracked markets.
+func (m *Manager) persistAllState() {
+	m.marketsMtx.RLock()
+	defer m.marketsMtx.RUnlock()
+
+	for market, mkt := range m.markets {
+		err := m.persistState(market, mkt)
+		if err != nil {
+			m.cfg.Logger.Error().Msg(err.Error())
 		}
-		mkt, err := NewMarket(cfg)
+	}
+}
+
+// AddMarket starts tracking price action for the provided market.
+func (m *Manager) AddMarket(market string) error {
+	m.marketsMtx.Lock()
+	defer m.marketsMtx.Unlock()
+
+	_, ok := m.markets[market]
+	if ok {
+		return fmt.Errorf("market %s is already tracked", market)
+	}
+
+	mkt, err := m.newMarket(market)
+	if err != nil {
+		return err
+	}
+
+	m.markets[market] = mkt
+	m.workers[market] = newMarketWorker(m.cfg.marketWorkers())
+
+	return nil
+}
+
+// RemoveMarket stops tracking price action for the provided market.
+func (m *Manager) RemoveMarket(market string) error {
+	m.marketsMtx.Lock()
+	defer m.marketsMtx.Unlock()
+
+	mkt, ok := m.markets[market]
+	if !ok {
+		return fmt.Errorf("no market found with name %s", market)
+	}
+
+	err := m.persistState(market, mkt)
+	if err != nil {
+		m.cfg.Logger.Error().Msg(err.Error())
+	}
+
+	delete(m.markets, market)
+	delete(m.workers, market)
+
+	return nil
+}
+
+// Pause stops generating reactions for the provided market until it is resumed. Updates of
+// the market are still applied while paused.
+func (m *Manager) Pause(market string) error {
+	mkt, ok := m.fetchMarket(market)
+	if !ok {
+		return fmt.Errorf("no market found with name %s", market)
+	}
+
+	if m.cfg.PauseReactions != nil {
+		err := m.cfg.PauseReactions(market)
+		if err != nil {
+			return fmt.Errorf("pausing %s reactions: %v", market, err)
+		}
+	}
+
+	mkt.paused.Store(true)
+	m.cfg.Logger.Info().Msgf("paused reactions for %s", market)
+
+	return nil
+}
+
+// Resume resumes generating reactions for the provided paused market.
+func (m *Manager) Resume(market string) error {
+	mkt, ok := m.fetchMarket(market)
+	if !ok {
+		return fmt.Errorf("no market found with name %s", market)
+	}
+
+	if m.cfg.ResumeReactions != nil {
+		err := m.cfg.ResumeReactions(market)
 		if err != nil {
-			return nil, fmt.Errorf("creating %s market: %v", market, err)
+			return fmt.Errorf("resuming %s reactions: %v", market, err)
 		}
+	}
+
+	mkt.paused.Store(false)
+	m.cfg.Logger.Info().Msgf("resumed reactions for %s", market)
+
+	return nil
+}
+
+// Paused returns whether reactions for the provided market are paused.
+func (m *Manager) Paused(market string) bool {
+	mkt, ok := m.fetchMarket(market)
+	if !ok {
+		return false
+	}
+
+	return mkt.paused.Load()
+}
+
+// RecordStopOut records the stop out of a position entered at the level of the provided
+// market and price, raising the bar for re-entries at the level.
+func (m *Manager) RecordStopOut(market string, levelPrice float64, stoppedOn time.Time) {
+	mkt, ok := m.fetchMarket(market)
+	if !ok {
+		m.cfg.Logger.Error().Msgf("no market found with name %s for stop out", market)
+		return
+	}
+
+	if !mkt.RecordStopOut(levelPrice, stoppedOn) {
+		m.cfg.Logger.Info().Msgf("no %s level found @ %.2f for stop out", market, levelPrice)
+		return
+	}
+
+	m.cfg.Logger.Info().Msgf("recorded stop out at %s level @ %.2f", market, levelPrice)
+}
+
+// fetchMarket returns the tracked market with the provided name.
+func (m *Manager) fetchMarket(market string) (*Market, bool) {
+	m.marketsMtx.RLock()
+	defer m.marketsMtx.RUnlock()
+
+	mkt, ok := m.markets[market]
+	return mkt, ok
+}
+
+// fetchWorker returns the dedicated worker of the provided market.
+func (m *Manager) fetchWorker(market string) (*marketWorker, bool) {
+	m.marketsMtx.RLock()
+	defer m.marketsMtx.RUnlock()
+
+	worker, ok := m.workers[market]
+	return worker, ok
+}
+
+// QueueDepths returns the number of signals being handled and waiting to be handled by each
+// market's worker, keyed by market.
+func (m *Manager) QueueDepths() map[string]int {
+	m.marketsMtx.RLock()
+	defer m.marketsMtx.RUnlock()
 
-		markets[market] = mkt
+	depths := make(map[string]int, len(m.workers))
+	for market, worker := range m.workers {
+		depths[market] = worker.depth()
 	}
-	return &Manager{
-		cfg:              cfg,
-		markets:          markets,
-		levelSignals:     make(chan shared.LevelSignal, bufferSize),
-		imbalanceSignals: make(chan shared.ImbalanceSignal, bufferSize),
-		updateSignals:    make(chan shared.Candlestick, bufferSize),
-		metaSignals:      make(chan shared.CandleMetadataRequest, bufferSize),
-		requestWorkers:   make(chan struct{}, maxWorkers),
-		workers:          workers,
-	}, nil
+
+	return depths
+}
+
+// Dropped returns the number of signals dropped by the manager's channels at capacity,
+// keyed by channel.
+func (m *Manager) Dropped() map[string]uint64 {
+	return m.drops.Counts()
 }
 
 // SendLevel relays the provided level signal for processing.
@@ -138,8 +539,7 @@ func (m *Manager) SendLevelSignal(level shared.LevelSignal) {
 	case m.levelSignals <- level:
 		// do nothing.
 	default:
-		m.cfg.Logger.Error().Msgf("level channel at capacity: %d/%d",
-			len(m.levelSignals), bufferSize)
+		m.drops.Drop("level")
 	}
 }
 
@@ -149,8 +549,17 @@ func (m *Manager) SendImbalanceSignal(imbalance shared.ImbalanceSignal) {
 	case m.imbalanceSignals <- imbalance:
 		// do nothing.
 	default:
-		m.cfg.Logger.Error().Msgf("imbalance channel at capacity: %d/%d",
-			len(m.imbalanceSignals), bufferSize)
+		m.drops.Drop("imbalance")
+	}
+}
+
+// SendOrderBlockSignal relays the provided order block signal for processing.
+func (m *Manager) SendOrderBlockSignal(orderBlock shared.OrderBlockSignal) {
+	select {
+	case m.orderBlockSignals <- orderBlock:
+		// do nothing.
+	default:
+		m.drops.Drop("order block")
 	}
 }
 
@@ -160,8 +569,7 @@ func (m *Manager) SendMarketUpdate(candle shared.Candlestick) {
 	case m.updateSignals <- candle:
 		// do nothing.
 	default:
-		m.cfg.Logger.Error().Msgf("market update channel at capacity: %d/%d",
-			len(m.updateSignals), bufferSize)
+		m.drops.Drop("market update")
 	}
 }
 
@@ -171,29 +579,35 @@ func (m *Manager) SendCandleMetadataRequest(req shared.CandleMetadataRequest) {
 	case m.metaSignals <- req:
 		// do nothing.
 	default:
-		m.cfg.Logger.Error().Msgf("candle metadata request channel at capacity: %d/%d",
-			len(m.metaSignals), bufferSize)
+		m.drops.Drop("candle metadata request")
 	}
 }
 
 // evaluateReactionAtLevelSignal determines whether a reaction at level signal should be generated for
 // the provided market.
-func (m *Manager) evaluateReactionAtLevelSignal(mkt *Market, timeframe shared.Timeframe) error {
+func (m *Manager) evaluateReactionAtLevelSignal(mkt *Market, timeframe shared.Timeframe, batch *reactionBatch) error {
 	if !mkt.RequestingPriceData() {
 		// Do nothing.
 		return nil
 	}
 
 	// Request price data and generate price reactions from them.
-	req := shared.NewPriceDataRequest(mkt.cfg.Market, timeframe, shared.PriceDataPayloadSize)
+	req := shared.NewPriceDataRequest(mkt.cfg.Market, timeframe, mkt.ReactionWindow())
 	m.cfg.RequestPriceData(*req)
 	var data []*shared.Candlestick
 	select {
 	case data = <-req.Response:
-	case <-time.After(shared.TimeoutDuration):
+	case <-time.After(m.cfg.Timeouts.PriceDataTimeout()):
 		return fmt.Errorf("timed out waiting for price data response")
 	}
 
+	if !mkt.windowFormed(len(data)) {
+		// Keep the request pending until the reaction window has formed.
+		m.cfg.Logger.Info().Msgf("deferring %s level reactions, %d of %d window candles formed",
+			mkt.cfg.Market, len(data), mkt.ReactionWindow())
+		return nil
+	}
+
 	reactions, err := mkt.GenerateReactionsAtTaggedLevels(data)
 	if err != nil {
 		return fmt.Errorf("generating level reactions: %v", err)
@@ -201,12 +615,15 @@ func (m *Manager) evaluateReactionAtLevelSignal(mkt *Market, timeframe shared.Ti
 
 	for idx := range reactions {
 		reaction := reactions[idx]
-		m.cfg.SignalReactionAtLevel(*reaction)
-		select {
-		case <-reaction.Status:
-		case <-time.After(shared.TimeoutDuration):
-			return fmt.Errorf("timed out waiting for reaction at level status")
+		if !mkt.TrackReaction(&reaction.ReactionAtFocus, reaction.Level.Price) {
+			m.cfg.Logger.Info().Msgf("filtered %s level reaction @ %.2f for market %s",
+				reaction.Reaction.String(), reaction.Level.Price, reaction.Market)
+			continue
 		}
+
+		batch.add(reaction.Focus, reaction.Level.Price, &reaction.ReactionAtFocus, func() {
+			m.cfg.SignalReactionAtLevel(*reaction)
+		})
 	}
 
 	mkt.ResetPriceDataState()
@@ -216,22 +633,29 @@ func (m *Manager) evaluateReactionAtLevelSignal(mkt *Market, timeframe shared.Ti
 
 // evaluateReactionAtImbalanceSignal determines whether a reaction at imbalance signal should be
 // generated for the provided market.
-func (m *Manager) evaluateReactionAtImbalanceSignal(mkt *Market, timeframe shared.Timeframe) error {
+func (m *Manager) evaluateReactionAtImbalanceSignal(mkt *Market, timeframe shared.Timeframe, batch *reactionBatch) error {
 	if !mkt.RequestingImbalanceData() {
 		// Do nothing.
 		return nil
 	}
 
 	// Request price data and generate price reactions from them.
-	req := shared.NewPriceDataRequest(mkt.cfg.Market, timeframe, shared.PriceDataPayloadSize)
+	req := shared.NewPriceDataRequest(mkt.cfg.Market, timeframe, mkt.ReactionWindow())
 	m.cfg.RequestPriceData(*req)
 	var data []*shared.Candlestick
 	select {
 	case data = <-req.Response:
-	case <-time.After(shared.TimeoutDuration):
+	case <-time.After(m.cfg.Timeouts.PriceDataTimeout()):
 		return fmt.Errorf("timed out waiting for price data response")
 	}
 
+	if !mkt.windowFormed(len(data)) {
+		// Keep the request pending until the reaction window has formed.
+		m.cfg.Logger.Info().Msgf("deferring %s imbalance reactions, %d of %d window candles formed",
+			mkt.cfg.Market, len(data), mkt.ReactionWindow())
+		return nil
+	}
+
 	reactions, err := mkt.GenerateReactionsAtTaggedImbalances(data)
 	if err != nil {
 		return fmt.Errorf("generating level reactions: %v", err)
@@ -239,12 +663,15 @@ func (m *Manager) evaluateReactionAtImbalanceSignal(mkt *Market, timeframe share
 
 	for idx := range reactions {
 		reaction := reactions[idx]
-		m.cfg.SignalReactionAtImbalance(*reaction)
-		select {
-		case <-reaction.Status:
-		case <-time.After(shared.TimeoutDuration):
-			return fmt.Errorf("timed out waiting for reaction at imbalance status")
+		if !mkt.TrackReaction(&reaction.ReactionAtFocus, reaction.Imbalance.Midpoint) {
+			m.cfg.Logger.Info().Msgf("filtered %s imbalance reaction @ %.2f for market %s",
+				reaction.Reaction.String(), reaction.Imbalance.Midpoint, reaction.Market)
+			continue
 		}
+
+		batch.add(shared.ImbalanceFocus, reaction.Imbalance.Midpoint, &reaction.ReactionAtFocus, func() {
+			m.cfg.SignalReactionAtImbalance(*reaction)
+		})
 	}
 
 	mkt.ResetImbalanceDataState()
@@ -254,43 +681,74 @@ func (m *Manager) evaluateReactionAtImbalanceSignal(mkt *Market, timeframe share
 
 // evaluateReactionAtVWAPSignal determines whether a reaction at vwap signal should be generated for
 // the provided market.
-func (m *Manager) evaluateReactionAtVWAPSignal(mkt *Market, timeframe shared.Timeframe) error {
+func (m *Manager) evaluateReactionAtVWAPSignal(mkt *Market, timeframe shared.Timeframe, batch *reactionBatch) error {
 	if !mkt.RequestingVWAPData() {
 		// Do nothing.
 		return nil
 	}
 
 	// Request price data and vwap data and generate price reactions from them.
-	priceReq := shared.NewPriceDataRequest(mkt.cfg.Market, timeframe, shared.PriceDataPayloadSize)
+	priceReq := shared.NewPriceDataRequest(mkt.cfg.Market, timeframe, mkt.ReactionWindow())
 	m.cfg.RequestPriceData(*priceReq)
 	var priceData []*shared.Candlestick
 	select {
 	case priceData = <-priceReq.Response:
-	case <-time.After(shared.TimeoutDuration):
+	case <-time.After(m.cfg.Timeouts.PriceDataTimeout()):
 		return fmt.Errorf("timed out waiting for price data response")
 	}
 
-	vwapReq := shared.NewVWAPDataRequest(mkt.cfg.Market, timeframe)
-	m.cfg.RequestVWAPData(*vwapReq)
+	// Bands are evaluated against the vwap data projected onto the tagged band.
+	band := mkt.TaggedVWAPBand()
 	var vwapData []*shared.VWAP
-	select {
-	case vwapData = <-vwapReq.Response:
-	case <-time.After(shared.TimeoutDuration):
-		return fmt.Errorf("timed out waiting for vwap data response")
+	if band == shared.VWAPLine {
+		vwapReq := shared.NewVWAPDataRequest(mkt.cfg.Market, timeframe, mkt.ReactionWindow())
+		m.cfg.RequestVWAPData(*vwapReq)
+		select {
+		case vwapData = <-vwapReq.Response:
+		case <-time.After(m.cfg.Timeouts.VWAPTimeout()):
+			return fmt.Errorf("timed out waiting for vwap data response")
+		}
+	} else {
+		bandsReq := shared.NewVWAPBandsRequest(mkt.cfg.Market, timeframe, band, mkt.ReactionWindow())
+		m.cfg.RequestVWAPBands(*bandsReq)
+		select {
+		case vwapData = <-bandsReq.Response:
+		case <-time.After(m.cfg.Timeouts.VWAPTimeout()):
+			return fmt.Errorf("timed out waiting for vwap bands response")
+		}
 	}
 
-	reaction, err := shared.NewReactionAtVWAP(mkt.cfg.Market, vwapData, priceData)
+	if !mkt.windowFormed(min(len(priceData), len(vwapData))) {
+		// Keep the request pending until the reaction window has formed.
+		m.cfg.Logger.Info().Msgf("deferring %s vwap reactions, %d of %d window candles formed",
+			mkt.cfg.Market, min(len(priceData), len(vwapData)), mkt.ReactionWindow())
+		return nil
+	}
+
+	reaction, err := shared.NewReactionAtVWAP(mkt.cfg.Market, vwapData, priceData, m.cfg.VWAPMismatchTolerance)
 	if err != nil {
 		return fmt.Errorf("creating vwap reaction: %v", err)
 	}
+	reaction.Band = band
+	reaction.VWAP = reaction.Line()
+	reaction.ReclassifyLateBreak(mkt.cfg.LateBreakHandling)
 
-	m.cfg.SignalReactionAtVWAP(*reaction)
-	select {
-	case <-reaction.Status:
-	case <-time.After(shared.TimeoutDuration):
-		return fmt.Errorf("timed out waiting for reaction at vwap status")
+	var vwap float64
+	if len(reaction.VWAPData) > 0 {
+		vwap = reaction.VWAPData[len(reaction.VWAPData)-1].Value
 	}
 
+	if !mkt.TrackReaction(&reaction.ReactionAtFocus, vwap) {
+		m.cfg.Logger.Info().Msgf("filtered %s %s reaction @ %.2f for market %s",
+			reaction.Reaction.String(), band.String(), vwap, reaction.Market)
+		mkt.ResetVWAPDataState()
+		return nil
+	}
+
+	batch.add(shared.VWAPFocus, vwap, &reaction.ReactionAtFocus, func() {
+		m.cfg.SignalReactionAtVWAP(*reaction)
+	})
+
 	mkt.ResetVWAPDataState()
 
 	return nil
@@ -302,7 +760,7 @@ func (m *Manager) handleUpdateSignal(candle *shared.Candlestick) error {
 		candle.Status <- shared.Processed
 	}()
 
-	mkt, ok := m.markets[candle.Market]
+	mkt, ok := m.fetchMarket(candle.Market)
 	if !ok {
 		return fmt.Errorf("no market found with name: %s", candle.Market)
 	}
@@ -310,21 +768,70 @@ func (m *Manager) handleUpdateSignal(candle *shared.Candlestick) error {
 	// Update price action concepts related to the market.
 	mkt.Update(candle)
 
-	err := m.evaluateReactionAtLevelSignal(mkt, candle.Timeframe)
+	// Paused markets keep their snapshots current without generating reactions.
+	if mkt.paused.Load() {
+		return nil
+	}
+
+	// Reactions are only generated on closed candles when evaluating on close, signals
+	// acting on a forming candle can vanish once it closes.
+	if !m.cfg.ReactionEvaluation.evaluates(candle.Closed) {
+		return nil
+	}
+
+	// Reactions are not generated until the market has enough history for reliable averages.
+	if !mkt.warmedUp(candle.Timeframe) {
+		return nil
+	}
+
+	batch := &reactionBatch{}
+	err := m.evaluateReactionAtLevelSignal(mkt, candle.Timeframe, batch)
 	if err != nil {
 		return fmt.Errorf("evaluating reaction at level signal: %v", err)
 	}
 
-	err = m.evaluateReactionAtVWAPSignal(mkt, candle.Timeframe)
+	err = m.evaluateReactionAtVWAPSignal(mkt, candle.Timeframe, batch)
 	if err != nil {
 		return fmt.Errorf("evaluating reaction at vwap signal: %v", err)
 	}
 
-	err = m.evaluateReactionAtImbalanceSignal(mkt, candle.Timeframe)
+	err = m.evaluateReactionAtImbalanceSignal(mkt, candle.Timeframe, batch)
 	if err != nil {
 		return fmt.Errorf("evaluating reaction at imbalance signal: %v", err)
 	}
 
+	return m.relayReactions(batch)
+}
+
+// relayReactions relays the reactions of the provided batch for processing, collapsing
+// coincident reactions if reactions are debounced.
+func (m *Manager) relayReactions(batch *reactionBatch) error {
+	reactions := batch.reactions
+	annotateNearbyFoci(reactions)
+	if m.cfg.ReactionDebounce > 0 {
+		reactions = debounceReactions(reactions, m.cfg.ReactionDebounce)
+	}
+
+	for idx := range reactions {
+		pending := reactions[idx]
+		if len(pending.reaction.CoincidentFocuses) > 0 {
+			focuses := make([]string, 0, len(pending.reaction.CoincidentFocuses))
+			for _, focus := range pending.reaction.CoincidentFocuses {
+				focuses = append(focuses, focus.String())
+			}
+			m.cfg.Logger.Info().Msgf("collapsed coincident %s reactions into %s %s reaction @ %.2f for market %s",
+				strings.Join(focuses, ","), pending.focus.String(), pending.reaction.Reaction.String(),
+				pending.price, pending.reaction.Market)
+		}
+
+		pending.signal()
+		select {
+		case <-pending.reaction.Status:
+		case <-time.After(m.cfg.Timeouts.SignalStatusTimeout()):
+			return fmt.Errorf("timed out waiting for reaction at %s status", pending.focus.String())
+		}
+	}
+
 	return nil
 }
 
@@ -334,16 +841,20 @@ func (m *Manager) handleLevelSignal(signal shared.LevelSignal) error {
 		signal.Status <- shared.Processed
 	}()
 
-	mkt, ok := m.markets[signal.Market]
+	mkt, ok := m.fetchMarket(signal.Market)
 	if !ok {
 		return fmt.Errorf("no market found with name %s", signal.Market)
 	}
 
 	level := shared.NewLevel(signal.Market, signal.Price, signal.Close)
+	if signal.Manual {
+		level = shared.NewManualLevel(signal.Market, signal.Price, signal.Kind)
+	}
+	level.Source = signal.Source
 	mkt.AddLevel(level)
 	m.cfg.Logger.Info().Msgf("added new %s level @ %.2f for %s", level.Kind.String(), level.Price, level.Market)
 
-	return nil
+	return m.persistState(signal.Market, mkt)
 }
 
 // handleImbalanceSignal processes the provided imbalance signal.
@@ -352,7 +863,7 @@ func (m *Manager) handleImbalanceSignal(signal shared.ImbalanceSignal) error {
 		signal.Status <- shared.Processed
 	}()
 
-	mkt, ok := m.markets[signal.Market]
+	mkt, ok := m.fetchMarket(signal.Market)
 	if !ok {
 		return fmt.Errorf("no market found with name %s", signal.Market)
 	}
@@ -362,36 +873,77 @@ func (m *Manager) handleImbalanceSignal(signal shared.ImbalanceSignal) error {
 	m.cfg.Logger.Info().Msgf("added new %s imbalance with gap ratio %.2f covering %.2f - %.2f for %s",
 		imb.Sentiment.String(), imb.GapRatio, imb.High, imb.Low, imb.Market)
 
-	return nil
+	return m.persistState(signal.Market, mkt)
+}
+
+// handleOrderBlockSignal processes the provided order block signal.
+func (m *Manager) handleOrderBlockSignal(signal shared.OrderBlockSignal) error {
+	defer func() {
+		signal.Status <- shared.Processed
+	}()
+
+	mkt, ok := m.fetchMarket(signal.Market)
+	if !ok {
+		return fmt.Errorf("no market found with name %s", signal.Market)
+	}
+
+	level := shared.NewOrderBlockLevel(&signal.OrderBlock)
+	mkt.AddLevel(level)
+	m.cfg.Logger.Info().Msgf("added new %s order block %s level @ %.2f covering %.2f - %.2f for %s",
+		signal.OrderBlock.Sentiment.String(), level.Kind.String(), level.Price,
+		signal.OrderBlock.High, signal.OrderBlock.Low, level.Market)
+
+	return m.persistState(signal.Market, mkt)
 }
 
 // handleCandleMetadataRequest processes the provided candle metadata request.
 func (m *Manager) handleCandleMetadataRequest(req *shared.CandleMetadataRequest) error {
-	_, ok := m.markets[req.Market]
+	mkt, ok := m.fetchMarket(req.Market)
 	if !ok {
 		return fmt.Errorf("no market found with name: %s", req.Market)
 	}
 
-	// Request price data and generate price reactions from them.
-	priceDataReq := shared.NewPriceDataRequest(req.Market, req.Timeframe, shared.PriceDataPayloadSize+1)
+	// Metadata is reused until a new candle of the timeframe arrives.
+	metadataSet, ok := mkt.metadataCache.fetch(req.Timeframe, req.Lookback)
+	if ok {
+		select {
+		case req.Response <- metadataSet:
+		case <-time.After(m.cfg.Timeouts.CandleMetadataTimeout()):
+			return fmt.Errorf("timed out waiting for candle metadata response")
+		}
+
+		return nil
+	}
+
+	// Request enough price data to cover the larger of the reaction window and the requested
+	// lookback, the first candle only serves as the reference of the second.
+	window := mkt.ReactionWindow()
+	n := max(window+1, req.Lookback+2)
+	priceDataReq := shared.NewPriceDataRequest(req.Market, req.Timeframe, n)
 	m.cfg.RequestPriceData(*priceDataReq)
 	var data []*shared.Candlestick
 	select {
 	case data = <-priceDataReq.Response:
-	case <-time.After(shared.TimeoutDuration):
+	case <-time.After(m.cfg.Timeouts.PriceDataTimeout()):
 		return fmt.Errorf("timed out waiting for price data response")
 	}
 
 	// Generate metadata for all candles in the range being evaluated.
-	metadataSet := make([]*shared.CandleMetadata, 0, shared.PriceDataPayloadSize)
+	metadataSet = make([]*shared.CandleMetadata, 0, n)
+	classification := m.cfg.classification()
 	for idx := 1; idx < len(data)-1; idx++ {
 		currentCandle := data[idx]
 		previousCandle := data[idx-1]
 
-		kind := currentCandle.FetchKind()
+		// Only closed candles are evaluated.
+		if !currentCandle.Closed {
+			continue
+		}
+
+		kind := currentCandle.FetchKind(classification)
 		sentiment := currentCandle.FetchSentiment()
 		momentum := shared.GenerateMomentum(currentCandle, previousCandle)
-		isEngulfing := shared.IsEngulfing(currentCandle, previousCandle)
+		isEngulfing := shared.IsEngulfing(currentCandle, previousCandle, classification)
 
 		meta := &shared.CandleMetadata{
 			Kind:      kind,
@@ -399,68 +951,179 @@ func (m *Manager) handleCandleMetadataRequest(req *shared.CandleMetadataRequest)
 			Momentum:  momentum,
 			Volume:    currentCandle.Volume,
 			Engulfing: isEngulfing,
+			Open:      currentCandle.Open,
 			High:      currentCandle.High,
 			Low:       currentCandle.Low,
+			Close:     currentCandle.Close,
 			Date:      currentCandle.Date,
 		}
 
 		metadataSet = append(metadataSet, meta)
 	}
 
+	if len(data) > 0 {
+		mkt.metadataCache.add(req.Timeframe, req.Lookback, data[len(data)-1].Date, metadataSet)
+	}
+
 	select {
 	case req.Response <- metadataSet:
-	case <-time.After(shared.TimeoutDuration):
+	case <-time.After(m.cfg.Timeouts.CandleMetadataTimeout()):
 		return fmt.Errorf("timed out waiting for candle metadata response")
 	}
 
 	return nil
 }
 
+// dispatch hands the provided handler to the provided worker once it is available. It returns
+// false if the provided context is done before the worker becomes available.
+func (m *Manager) dispatch(ctx context.Context, worker chan struct{}, handler func() error) bool {
+	select {
+	case worker <- struct{}{}:
+	case <-ctx.Done():
+		return false
+	}
+
+	m.handle(worker, handler)
+
+	return true
+}
+
+// reportError logs the provided processing error and relays it to the error sink.
+func (m *Manager) reportError(err error) {
+	m.cfg.Logger.Error().Err(err).Send()
+	shared.ReportError(m.cfg.ErrorSink, err, m.drops)
+}
+
+// handle runs the provided handler on an acquired slot of the provided worker.
+func (m *Manager) handle(worker chan struct{}, handler func() error) {
+	m.inflight.Add(1)
+	go func() {
+		defer func() {
+			<-worker
+			m.inflight.Done()
+		}()
+
+		err := handler()
+		if err != nil {
+			m.reportError(err)
+		}
+	}()
+}
+
+// dispatchMarket hands the provided handler to the dedicated worker of the provided market.
+// The provided status is signalled if the market has no worker.
+//
+// Signals of a market whose worker is at capacity wait for it in the background so a busy
+// market does not hold up the signals of other markets.
+func (m *Manager) dispatchMarket(ctx context.Context, market string, kind string, status chan shared.StatusCode, handler func() error) bool {
+	worker, ok := m.fetchWorker(market)
+	if !ok {
+		m.cfg.Logger.Error().Msgf("no worker found for market %s %s", market, kind)
+		status <- shared.Processed
+		return true
+	}
+
+	select {
+	case worker.slots <- struct{}{}:
+		m.handle(worker.slots, handler)
+		return true
+	default:
+	}
+
+	worker.waiting.Inc()
+	m.inflight.Add(1)
+	go func() {
+		defer m.inflight.Done()
+
+		dispatched := m.dispatch(ctx, worker.slots, handler)
+		worker.waiting.Dec()
+		if !dispatched {
+			m.cfg.Logger.Warn().Msgf("%s %s was not handled before shutdown", market, kind)
+		}
+	}()
+
+	return true
+}
+
+// drain dispatches the signals and requests buffered when the manager is shut down and waits
+// for in-flight handlers to complete, bounded by the drain grace period.
+func (m *Manager) drain(ctx context.Context) {
+	ctx, cancel := shared.NewDrainContext(ctx, m.cfg.DrainGracePeriod)
+	defer cancel()
+
+	for {
+		dispatched := true
+		select {
+		case signal := <-m.levelSignals:
+			dispatched = m.dispatchMarket(ctx, signal.Market, "level signal", signal.Status, func() error {
+				return m.handleLevelSignal(signal)
+			})
+		case signal := <-m.imbalanceSignals:
+			dispatched = m.dispatchMarket(ctx, signal.Market, "imbalance signal", signal.Status, func() error {
+				return m.handleImbalanceSignal(signal)
+			})
+		case signal := <-m.orderBlockSignals:
+			dispatched = m.dispatchMarket(ctx, signal.Market, "order block signal", signal.Status, func() error {
+				return m.handleOrderBlockSignal(signal)
+			})
+		case candle := <-m.updateSignals:
+			dispatched = m.dispatchMarket(ctx, candle.Market, "update", candle.Status, func() error {
+				return m.handleUpdateSignal(&candle)
+			})
+		case req := <-m.metaSignals:
+			dispatched = m.dispatch(ctx, m.requestWorkers, func() error {
+				return m.handleCandleMetadataRequest(&req)
+			})
+		default:
+			if !shared.Await(ctx, &m.inflight) {
+				m.cfg.Logger.Warn().Msg("drain grace period elapsed with in-flight signals")
+			}
+			return
+		}
+
+		if !dispatched {
+			m.cfg.Logger.Warn().Msg("drain grace period elapsed with buffered signals")
+			return
+		}
+	}
+}
+
 // Run manages the lifecycle processes of the price action manager.
 func (m *Manager) Run(ctx context.Context) {
+	// Handlers are dispatched without a deadline while running, the manager drains buffered
+	// signals before shutting down.
+	runCtx := context.WithoutCancel(ctx)
+
 	for {
+		m.runIterations.Inc()
+
 		select {
 		case <-ctx.Done():
+			m.drain(ctx)
+			// Persist the latest level and imbalance state, including reversals and purges
+			// applied by market updates, for the next startup.
+			m.persistAllState()
 			return
 		case signal := <-m.levelSignals:
-			m.workers[signal.Market] <- struct{}{}
-			go func(signal shared.LevelSignal) {
-				err := m.handleLevelSignal(signal)
-				if err != nil {
-					m.cfg.Logger.Error().Err(err).Send()
-				}
-				<-m.workers[signal.Market]
-			}(signal)
+			m.dispatchMarket(runCtx, signal.Market, "level signal", signal.Status, func() error {
+				return m.handleLevelSignal(signal)
+			})
 		case signal := <-m.imbalanceSignals:
-			m.workers[signal.Market] <- struct{}{}
-			go func(signal shared.ImbalanceSignal) {
-				err := m.handleImbalanceSignal(signal)
-				if err != nil {
-					m.cfg.Logger.Error().Err(err).Send()
-				}
-				<-m.workers[signal.Market]
-			}(signal)
+			m.dispatchMarket(runCtx, signal.Market, "imbalance signal", signal.Status, func() error {
+				return m.handleImbalanceSignal(signal)
+			})
+		case signal := <-m.orderBlockSignals:
+			m.dispatchMarket(runCtx, signal.Market, "order block signal", signal.Status, func() error {
+				return m.handleOrderBlockSignal(signal)
+			})
 		case candle := <-m.updateSignals:
-			m.workers[candle.Market] <- struct{}{}
-			go func(candle shared.Candlestick) {
-				err := m.handleUpdateSignal(&candle)
-				if err != nil {
-					m.cfg.Logger.Error().Err(err).Send()
-				}
-				<-m.workers[candle.Market]
-			}(candle)
+			m.dispatchMarket(runCtx, candle.Market, "update", candle.Status, func() error {
+				return m.handleUpdateSignal(&candle)
+			})
 		case req := <-m.metaSignals:
-			m.requestWorkers <- struct{}{}
-			go func(req shared.CandleMetadataRequest) {
-				err := m.handleCandleMetadataRequest(&req)
-				if err != nil {
-					m.cfg.Logger.Error().Err(err).Send()
-				}
-				<-m.requestWorkers
-			}(req)
-
-		default:
-			// fallthrough
+			m.dispatch(runCtx, m.requestWorkers, func() error {
+				return m.handleCandleMetadataRequest(&req)
+			})
 		}
 	}
 }
@@ -2,10 +2,15 @@ package priceaction
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/dnldd/entry/database"
 	"github.com/dnldd/entry/shared"
 	"github.com/peterldowns/testy/assert"
 	"github.com/rs/zerolog"
@@ -31,6 +36,7 @@ func setupManager(t *testing.T, market string) *Manager {
 				Market:    req.Market,
 				Timeframe: shared.FiveMinute,
 				Status:    make(chan shared.StatusCode, 1),
+				Closed:    true,
 			}
 
 			data = append(data, &candle)
@@ -167,6 +173,27 @@ func TestPriceActionManagerConfigValidate(t *testing.T) {
 			wantErr:     true,
 			errContains: []string{"request vwap data function cannot be nil"},
 		},
+		{
+			name: "vwap bands without RequestVWAPBands",
+			modify: func(cfg *ManagerConfig) {
+				cfg.VWAPBands = true
+				cfg.Logger = &logger
+			},
+			wantErr:     true,
+			errContains: []string{"request vwap bands function cannot be nil"},
+		},
+		{
+			name:        "negative MarketWorkers",
+			modify:      func(cfg *ManagerConfig) { cfg.MarketWorkers = -1; cfg.Logger = &logger },
+			wantErr:     true,
+			errContains: []string{"market workers must be positive"},
+		},
+		{
+			name:        "negative RequestWorkers",
+			modify:      func(cfg *ManagerConfig) { cfg.RequestWorkers = -1; cfg.Logger = &logger },
+			wantErr:     true,
+			errContains: []string{"request workers must be positive"},
+		},
 		{
 			name:        "missing SignalReactionAtLevel",
 			modify:      func(cfg *ManagerConfig) { cfg.SignalReactionAtLevel = nil; cfg.Logger = &logger },
@@ -197,6 +224,35 @@ func TestPriceActionManagerConfigValidate(t *testing.T) {
 			wantErr:     true,
 			errContains: []string{"logger cannot be nil"},
 		},
+		{
+			name:        "invalid ReactionFilter",
+			modify:      func(cfg *ManagerConfig) { cfg.ReactionFilter = &ReactionFilter{MinPriceMovement: -1} },
+			wantErr:     true,
+			errContains: []string{"minimum price movement cannot be negative"},
+		},
+		{
+			name:        "negative DrainGracePeriod",
+			modify:      func(cfg *ManagerConfig) { cfg.DrainGracePeriod = -time.Second },
+			wantErr:     true,
+			errContains: []string{"drain grace period cannot be negative"},
+		},
+		{
+			name:        "MinReactionCandles below classification minimum",
+			modify:      func(cfg *ManagerConfig) { cfg.MinReactionCandles = 2 },
+			wantErr:     true,
+			errContains: []string{"minimum reaction candles must be at least 3 candles"},
+		},
+		{
+			name:        "MinReactionCandles above reaction window",
+			modify:      func(cfg *ManagerConfig) { cfg.MinReactionCandles = 5 },
+			wantErr:     true,
+			errContains: []string{"minimum reaction candles cannot exceed the reaction window of 4 candles"},
+		},
+		{
+			name:    "MinReactionCandles within custom reaction window",
+			modify:  func(cfg *ManagerConfig) { cfg.ReactionWindow = 6; cfg.MinReactionCandles = 5 },
+			wantErr: false,
+		},
 		{
 			name: "multiple missing fields",
 			modify: func(cfg *ManagerConfig) {
@@ -257,128 +313,1181 @@ func TestManager(t *testing.T) {
 		Low:    float64(3),
 		Volume: float64(2),
 
-		Market:    market,
-		Timeframe: shared.FiveMinute,
-		Status:    make(chan shared.StatusCode, 1),
+		Market:    market,
+		Timeframe: shared.FiveMinute,
+		Status:    make(chan shared.StatusCode, 1),
+		Closed:    true,
+	}
+
+	mgr.SendMarketUpdate(firstCandle)
+
+	// Ensure the price action manager can receive level signals.
+	levelSignal := shared.LevelSignal{
+		Market: market,
+		Price:  20,
+		Status: make(chan shared.StatusCode, 1),
+	}
+
+	mgr.SendLevelSignal(levelSignal)
+
+	// Ensure the price action manager can process candle metadata requests.
+	candleMetaReq := shared.CandleMetadataRequest{
+		Market:   market,
+		Response: make(chan []*shared.CandleMetadata, 1),
+	}
+
+	mgr.SendCandleMetadataRequest(candleMetaReq)
+	<-candleMetaReq.Response
+
+	now, _, err := shared.NewYorkTime()
+	assert.NoError(t, err)
+
+	// Ensure the price action manage can process imbalance signals.
+	imbalanceSignal := shared.ImbalanceSignal{
+		Market: market,
+		Imbalance: *shared.NewImbalance(market, shared.FiveMinute, float64(15), float64(10),
+			float64(5), shared.Bullish, float64(0.5), now),
+		Status: make(chan shared.StatusCode, 1),
+	}
+
+	mgr.SendImbalanceSignal(imbalanceSignal)
+	<-imbalanceSignal.Status
+
+	// Ensure the price action manager can be gracefully shutdown.
+	cancel()
+	<-done
+}
+
+func TestManagerRunIdle(t *testing.T) {
+	market := "^GSPC"
+	mgr := setupManager(t, market)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		mgr.Run(ctx)
+		close(done)
+	}()
+
+	// Ensure a level signal sent once the manager is idle is handled.
+	time.Sleep(time.Millisecond * 50)
+	signal := shared.LevelSignal{
+		Market: market,
+		Price:  20,
+		Status: make(chan shared.StatusCode, 1),
+	}
+	mgr.SendLevelSignal(signal)
+	select {
+	case <-signal.Status:
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for the idle manager to handle the level signal")
+	}
+
+	// Ensure the idle manager blocks instead of spinning.
+	iterations := mgr.runIterations.Load()
+	time.Sleep(time.Millisecond * 50)
+	assert.True(t, mgr.runIterations.Load()-iterations <= 1)
+
+	// Ensure the idle manager returns promptly once cancelled.
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the idle manager to return")
+	}
+}
+
+func TestManagerOrderBlockSignal(t *testing.T) {
+	market := "^GSPC"
+	mgr := setupManager(t, market)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		mgr.Run(ctx)
+		close(done)
+	}()
+
+	// Ensure order block signals are tracked as order block levels.
+	signal := shared.NewOrderBlockSignal(market, shared.OrderBlockZone{
+		Market:    market,
+		Timeframe: shared.FiveMinute,
+		High:      34,
+		Low:       29,
+		Sentiment: shared.Bearish,
+	})
+	mgr.SendOrderBlockSignal(signal)
+	<-signal.Status
+
+	mkt, ok := mgr.fetchMarket(market)
+	assert.True(t, ok)
+
+	levels := mkt.Levels()
+	assert.Equal(t, len(levels), 1)
+	assert.True(t, levels[0].OrderBlock)
+	assert.Equal(t, levels[0].Price, float64(29))
+	assert.Equal(t, levels[0].Kind, shared.Resistance)
+
+	cancel()
+	<-done
+}
+
+func TestManagerDrain(t *testing.T) {
+	market := "^GSPC"
+	mgr := setupManager(t, market)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	signals := make([]shared.LevelSignal, 0, 4)
+	for idx := range 4 {
+		signal := shared.LevelSignal{
+			Market: market,
+			Price:  float64(20 + idx),
+			Status: make(chan shared.StatusCode, 1),
+		}
+		signals = append(signals, signal)
+		mgr.SendLevelSignal(signal)
+	}
+
+	// Ensure signals buffered at shutdown are processed before the manager returns.
+	mgr.Run(ctx)
+	for _, signal := range signals {
+		assert.Equal(t, len(signal.Status), 1)
+	}
+}
+
+func TestManagerErrorSink(t *testing.T) {
+	market := "^GSPC"
+	mgr := setupManager(t, market)
+
+	sink := make(chan error, 1)
+	mgr.cfg.ErrorSink = sink
+
+	// Ensure a failed update signal handler delivers its error to the error sink.
+	candle := shared.Candlestick{
+		Market:    "^AAPL",
+		Timeframe: shared.FiveMinute,
+		Status:    make(chan shared.StatusCode, 1),
+		Closed:    true,
+	}
+	ok := mgr.dispatch(context.Background(), make(chan struct{}, 1), func() error {
+		return mgr.handleUpdateSignal(&candle)
+	})
+	assert.True(t, ok)
+
+	select {
+	case err := <-sink:
+		assert.Error(t, err)
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for update signal error")
+	}
+
+	// Ensure errors are still logged without blocking when the sink is at capacity.
+	mgr.reportError(errors.New("first"))
+	mgr.reportError(errors.New("second"))
+	assert.Equal(t, len(sink), 1)
+	assert.Equal(t, mgr.drops.Count("error sink"), uint64(1))
+}
+
+func TestManagerMarketWorkers(t *testing.T) {
+	gspc := "^GSPC"
+	ixic := "^IXIC"
+	mgr := setupManager(t, gspc)
+
+	// Ensure markets use the configured worker and request worker counts.
+	assert.Equal(t, cap(mgr.workers[gspc].slots), workerBufferSize)
+	assert.Equal(t, cap(mgr.requestWorkers), maxWorkers)
+	mgr.cfg.MarketWorkers = 1
+	err := mgr.AddMarket(ixic)
+	assert.NoError(t, err)
+	assert.Equal(t, cap(mgr.workers[ixic].slots), 1)
+
+	// Occupy the only worker slot of a busy market.
+	busy := newMarketWorker(1)
+	busy.slots <- struct{}{}
+	mgr.workers[gspc] = busy
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		mgr.Run(ctx)
+		close(done)
+	}()
+
+	busySignals := make([]shared.LevelSignal, 0, 2)
+	for idx := range 2 {
+		signal := shared.LevelSignal{
+			Market: gspc,
+			Price:  float64(20 + idx),
+			Status: make(chan shared.StatusCode, 1),
+		}
+		busySignals = append(busySignals, signal)
+		mgr.SendLevelSignal(signal)
+	}
+
+	quietSignal := shared.LevelSignal{
+		Market: ixic,
+		Price:  float64(30),
+		Status: make(chan shared.StatusCode, 1),
+	}
+	mgr.SendLevelSignal(quietSignal)
+
+	// Ensure a market with an available worker is processed while the busy market's
+	// signals wait on its worker.
+	select {
+	case <-quietSignal.Status:
+	case <-time.After(time.Second):
+		t.Fatal("expected the quiet market to be processed promptly")
+	}
+	assert.Equal(t, len(busySignals[0].Status), 0)
+	assert.Equal(t, mgr.QueueDepths()[gspc], 3)
+
+	// Ensure the busy market's signals are processed once its worker frees up.
+	<-busy.slots
+	for _, signal := range busySignals {
+		select {
+		case <-signal.Status:
+		case <-time.After(time.Second):
+			t.Fatal("expected the busy market signal to be processed")
+		}
+	}
+
+	cancel()
+	<-done
+
+	depths := mgr.QueueDepths()
+	assert.Equal(t, depths[gspc], 0)
+	assert.Equal(t, depths[ixic], 0)
+}
+
+func TestManagerAddRemoveMarket(t *testing.T) {
+	gspc := "^GSPC"
+	ixic := "^IXIC"
+	mgr := setupManager(t, gspc)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		mgr.Run(ctx)
+		close(done)
+	}()
+
+	// Ensure a market can be added at runtime.
+	err := mgr.AddMarket(ixic)
+	assert.NoError(t, err)
+
+	// Ensure an already tracked market cannot be added.
+	err = mgr.AddMarket(ixic)
+	assert.Error(t, err)
+
+	// Ensure the added market receives market updates and level signals.
+	candle := shared.Candlestick{
+		Open:   float64(5),
+		Close:  float64(8),
+		High:   float64(9),
+		Low:    float64(3),
+		Volume: float64(2),
+
+		Market:    ixic,
+		Timeframe: shared.FiveMinute,
+		Status:    make(chan shared.StatusCode, 1),
+		Closed:    true,
+	}
+
+	mgr.SendMarketUpdate(candle)
+	<-candle.Status
+
+	levelSignal := shared.LevelSignal{
+		Market: ixic,
+		Price:  20,
+		Status: make(chan shared.StatusCode, 1),
+	}
+
+	mgr.SendLevelSignal(levelSignal)
+	<-levelSignal.Status
+
+	_, ok := mgr.fetchMarket(ixic)
+	assert.True(t, ok)
+
+	// Ensure a market can be removed at runtime.
+	err = mgr.RemoveMarket(ixic)
+	assert.NoError(t, err)
+
+	_, ok = mgr.fetchMarket(ixic)
+	assert.False(t, ok)
+
+	// Ensure an untracked market cannot be removed.
+	err = mgr.RemoveMarket(ixic)
+	assert.Error(t, err)
+
+	// Ensure signals for a removed market are acknowledged without being processed.
+	levelSignal.Status = make(chan shared.StatusCode, 1)
+	mgr.SendLevelSignal(levelSignal)
+	<-levelSignal.Status
+
+	err = mgr.handleLevelSignal(levelSignal)
+	assert.Error(t, err)
+
+	cancel()
+	<-done
+}
+
+func TestManagerConcurrentMarketAccess(t *testing.T) {
+	gspc := "^GSPC"
+	ixic := "^IXIC"
+	mgr := setupManager(t, gspc)
+	mgr.cfg.SignalReactionAtLevel = func(reaction shared.ReactionAtLevel) {
+		reaction.Status <- shared.Processed
+	}
+	mgr.cfg.SignalReactionAtVWAP = func(reaction shared.ReactionAtVWAP) {
+		reaction.Status <- shared.Processed
+	}
+	mgr.cfg.SignalReactionAtImbalance = func(reaction shared.ReactionAtImbalance) {
+		reaction.Status <- shared.Processed
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		mgr.Run(ctx)
+		close(done)
+	}()
+
+	// Ensure markets can be read by signal handlers while markets are added and removed,
+	// run with the race detector to surface unguarded access.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for range 50 {
+			err := mgr.AddMarket(ixic)
+			assert.NoError(t, err)
+			err = mgr.RemoveMarket(ixic)
+			assert.NoError(t, err)
+		}
+	}()
+
+	for _, market := range []string{gspc, ixic} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range 50 {
+				candle := shared.Candlestick{
+					Open:      float64(5),
+					Close:     float64(8),
+					High:      float64(9),
+					Low:       float64(3),
+					Volume:    float64(2),
+					Market:    market,
+					Timeframe: shared.FiveMinute,
+					Status:    make(chan shared.StatusCode, 1),
+					Closed:    true,
+				}
+				mgr.SendMarketUpdate(candle)
+				<-candle.Status
+
+				levelSignal := shared.LevelSignal{
+					Market: market,
+					Price:  float64(20 + idx),
+					Close:  float64(8),
+					Status: make(chan shared.StatusCode, 1),
+				}
+				mgr.SendLevelSignal(levelSignal)
+				<-levelSignal.Status
+
+				mgr.fetchMarket(market)
+				_, err := mgr.SnapshotState()
+				assert.NoError(t, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// Ensure the tracked markets are consistent once concurrent access ends.
+	_, ok := mgr.fetchMarket(gspc)
+	assert.True(t, ok)
+	_, ok = mgr.fetchMarket(ixic)
+	assert.False(t, ok)
+
+	cancel()
+	<-done
+}
+
+func TestManagerHandleUpdateSignal(t *testing.T) {
+	// Ensure the price action manager can be created.
+	market := "^GSPC"
+	mgr := setupManager(t, market)
+
+	// Ensure handling update signals for an unknown market errors.
+	wrongMarketCandle := shared.Candlestick{
+		Open:   float64(5),
+		Close:  float64(8),
+		High:   float64(9),
+		Low:    float64(3),
+		Volume: float64(2),
+
+		Market:    "^AAPL",
+		Timeframe: shared.FiveMinute,
+		Status:    make(chan shared.StatusCode, 1),
+		Closed:    true,
+	}
+
+	err := mgr.handleUpdateSignal(&wrongMarketCandle)
+	assert.Error(t, err)
+
+	// Ensure handling update signals for a valid market succeeds.
+	firstCandle := shared.Candlestick{
+		Open:   float64(5),
+		Close:  float64(8),
+		High:   float64(9),
+		Low:    float64(3),
+		Volume: float64(2),
+
+		Market:    market,
+		Timeframe: shared.FiveMinute,
+		Status:    make(chan shared.StatusCode, 1),
+		Closed:    true,
+	}
+
+	err = mgr.handleUpdateSignal(&firstCandle)
+	assert.NoError(t, err)
+
+	// Add a level for reaction tests.
+	levelSignal := shared.LevelSignal{
+		Market: market,
+		Price:  3,
+		Status: make(chan shared.StatusCode, 1),
+	}
+	err = mgr.handleLevelSignal(levelSignal)
+	assert.NoError(t, err)
+
+	// Trigger a price request for the market.
+	mgr.markets[market].requestingPriceData.Store(true)
+
+	// Ensure handling update signals when a price request is flagged processes the price request.
+	secondCandle := shared.Candlestick{
+		Open:   float64(10),
+		Close:  float64(15),
+		High:   float64(20),
+		Low:    float64(9),
+		Volume: float64(2),
+
+		Market:    market,
+		Timeframe: shared.FiveMinute,
+		Status:    make(chan shared.StatusCode, 1),
+		Closed:    true,
+	}
+
+	err = mgr.handleUpdateSignal(&secondCandle)
+	assert.NoError(t, err)
+
+	// Ensure price request flag is reset after the request is processed.
+	assert.False(t, mgr.markets[market].requestingPriceData.Load())
+
+	// Trigger a vwap and imbalance request for the market.
+	mgr.markets[market].requestingVWAPData.Store(true)
+	mgr.markets[market].requestingImbalanceData.Store(true)
+
+	secondCandle.Status = make(chan shared.StatusCode, 1)
+	err = mgr.handleUpdateSignal(&secondCandle)
+	assert.NoError(t, err)
+
+	// Ensure vwap request flag is reset after the request is processed.
+	assert.False(t, mgr.markets[market].requestingVWAPData.Load())
+}
+
+func TestManagerPauseResume(t *testing.T) {
+	market := "^GSPC"
+	mgr := setupManager(t, market)
+
+	// Serve price data tagging the level at 3 before moving above it.
+	mgr.cfg.RequestPriceData = func(req shared.PriceDataRequest) {
+		data := make([]*shared.Candlestick, 0, 4)
+		for idx := range 4 {
+			price := float64(4 + idx)
+			data = append(data, &shared.Candlestick{
+				Open:      price,
+				Close:     price,
+				High:      price + 1,
+				Low:       price - 2,
+				Market:    req.Market,
+				Timeframe: shared.FiveMinute,
+				Closed:    true,
+			})
+		}
+
+		go func() { req.Response <- data }()
+	}
+
+	reactions := make(chan shared.ReactionAtLevel, 5)
+	mgr.cfg.SignalReactionAtLevel = func(reaction shared.ReactionAtLevel) {
+		reactions <- reaction
+		reaction.Status <- shared.Processed
+	}
+
+	downstream := make(map[string]bool)
+	mgr.cfg.PauseReactions = func(market string) error {
+		downstream[market] = true
+		return nil
+	}
+	mgr.cfg.ResumeReactions = func(market string) error {
+		downstream[market] = false
+		return nil
+	}
+
+	// Ensure unknown markets cannot be paused or resumed.
+	err := mgr.Pause("^AAPL")
+	assert.Error(t, err)
+	err = mgr.Resume("^AAPL")
+	assert.Error(t, err)
+	assert.False(t, mgr.Paused("^AAPL"))
+
+	levelSignal := shared.LevelSignal{
+		Market: market,
+		Price:  3,
+		Status: make(chan shared.StatusCode, 1),
+	}
+	err = mgr.handleLevelSignal(levelSignal)
+	assert.NoError(t, err)
+
+	candle := func() *shared.Candlestick {
+		return &shared.Candlestick{
+			Open:      float64(10),
+			Close:     float64(15),
+			High:      float64(20),
+			Low:       float64(9),
+			Volume:    float64(2),
+			Market:    market,
+			Timeframe: shared.FiveMinute,
+			Status:    make(chan shared.StatusCode, 1),
+			Closed:    true,
+		}
+	}
+
+	// Ensure paused markets are updated without generating reactions.
+	err = mgr.Pause(market)
+	assert.NoError(t, err)
+	assert.True(t, mgr.Paused(market))
+	assert.True(t, downstream[market])
+
+	mkt := mgr.markets[market]
+	mkt.requestingPriceData.Store(true)
+	err = mgr.handleUpdateSignal(candle())
+	assert.NoError(t, err)
+	assert.Equal(t, len(reactions), 0)
+	assert.True(t, mkt.RequestingPriceData())
+
+	// Ensure resumed markets generate reactions.
+	err = mgr.Resume(market)
+	assert.NoError(t, err)
+	assert.False(t, mgr.Paused(market))
+	assert.False(t, downstream[market])
+
+	err = mgr.handleUpdateSignal(candle())
+	assert.NoError(t, err)
+	assert.Equal(t, len(reactions), 1)
+	assert.False(t, mkt.RequestingPriceData())
+}
+
+func TestManagerFormingCandles(t *testing.T) {
+	market := "^GSPC"
+	mgr := setupManager(t, market)
+	now, _ := time.Parse(time.RFC3339, "2010-09-18T14:00:00Z")
+
+	// Serve price data tagging the level at 3 before moving above it.
+	mgr.cfg.RequestPriceData = func(req shared.PriceDataRequest) {
+		data := make([]*shared.Candlestick, 0, 4)
+		for idx := range 4 {
+			price := float64(4 + idx)
+			data = append(data, &shared.Candlestick{
+				Open:      price,
+				Close:     price,
+				High:      price + 1,
+				Low:       price - 2,
+				Market:    req.Market,
+				Timeframe: shared.FiveMinute,
+				Closed:    true,
+			})
+		}
+
+		go func() { req.Response <- data }()
+	}
+
+	reactions := make(chan shared.ReactionAtLevel, 5)
+	mgr.cfg.SignalReactionAtLevel = func(reaction shared.ReactionAtLevel) {
+		reactions <- reaction
+		reaction.Status <- shared.Processed
+	}
+
+	levelSignal := shared.LevelSignal{
+		Market: market,
+		Price:  3,
+		Status: make(chan shared.StatusCode, 1),
+	}
+	err := mgr.handleLevelSignal(levelSignal)
+	assert.NoError(t, err)
+
+	candle := func(close float64, closed bool) *shared.Candlestick {
+		return &shared.Candlestick{
+			Open:      float64(10),
+			Close:     close,
+			High:      float64(20),
+			Low:       float64(9),
+			Volume:    float64(2),
+			Market:    market,
+			Timeframe: shared.FiveMinute,
+			Date:      now,
+			Status:    make(chan shared.StatusCode, 1),
+			Closed:    closed,
+		}
+	}
+
+	mkt := mgr.markets[market]
+	mkt.requestingPriceData.Store(true)
+
+	// Ensure forming candle updates do not generate reactions.
+	for _, close := range []float64{11, 13, 12} {
+		err = mgr.handleUpdateSignal(candle(close, false))
+		assert.NoError(t, err)
+		assert.Equal(t, len(reactions), 0)
+		assert.True(t, mkt.RequestingPriceData())
+	}
+
+	// Ensure the reaction is generated once the candle closes.
+	err = mgr.handleUpdateSignal(candle(15, true))
+	assert.NoError(t, err)
+	assert.Equal(t, len(reactions), 1)
+	assert.False(t, mkt.RequestingPriceData())
+}
+
+func TestManagerReactionEvaluation(t *testing.T) {
+	market := "^GSPC"
+	now, _ := time.Parse(time.RFC3339, "2010-09-18T14:00:00Z")
+
+	tests := []struct {
+		name       string
+		evaluation ReactionEvaluation
+		// reactions is the number of reactions generated after each forming candle update.
+		reactions []int
+	}{
+		{
+			name:       "close only evaluation",
+			evaluation: EvaluateOnClose,
+			reactions:  []int{0, 0, 0},
+		},
+		{
+			name:       "every update evaluation",
+			evaluation: EvaluateOnUpdate,
+			reactions:  []int{1, 1, 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mgr := setupManager(t, market)
+			mgr.cfg.ReactionEvaluation = test.evaluation
+
+			// Serve price data tagging the level at 3 before moving above it.
+			mgr.cfg.RequestPriceData = func(req shared.PriceDataRequest) {
+				data := make([]*shared.Candlestick, 0, 4)
+				for idx := range 4 {
+					price := float64(4 + idx)
+					data = append(data, &shared.Candlestick{
+						Open:      price,
+						Close:     price,
+						High:      price + 1,
+						Low:       price - 2,
+						Market:    req.Market,
+						Timeframe: shared.FiveMinute,
+						Closed:    true,
+					})
+				}
+
+				go func() { req.Response <- data }()
+			}
+
+			reactions := make(chan shared.ReactionAtLevel, 5)
+			mgr.cfg.SignalReactionAtLevel = func(reaction shared.ReactionAtLevel) {
+				reactions <- reaction
+				reaction.Status <- shared.Processed
+			}
+
+			levelSignal := shared.LevelSignal{
+				Market: market,
+				Price:  3,
+				Status: make(chan shared.StatusCode, 1),
+			}
+			err := mgr.handleLevelSignal(levelSignal)
+			assert.NoError(t, err)
+
+			candle := func(close float64, closed bool) *shared.Candlestick {
+				return &shared.Candlestick{
+					Open:      float64(10),
+					Close:     close,
+					High:      float64(20),
+					Low:       float64(9),
+					Volume:    float64(2),
+					Market:    market,
+					Timeframe: shared.FiveMinute,
+					Date:      now,
+					Status:    make(chan shared.StatusCode, 1),
+					Closed:    closed,
+				}
+			}
+
+			mkt := mgr.markets[market]
+			mkt.requestingPriceData.Store(true)
+
+			// Ensure sub-close updates only generate reactions when evaluating every update.
+			for idx, close := range []float64{11, 13, 12} {
+				err = mgr.handleUpdateSignal(candle(close, false))
+				assert.NoError(t, err)
+				assert.Equal(t, len(reactions), test.reactions[idx])
+			}
+
+			// Ensure a pending reaction is generated at the candle boundary.
+			mkt.requestingPriceData.Store(true)
+			err = mgr.handleUpdateSignal(candle(15, true))
+			assert.NoError(t, err)
+			assert.Equal(t, len(reactions), test.reactions[len(test.reactions)-1]+1)
+			assert.False(t, mkt.RequestingPriceData())
+		})
+	}
+}
+
+func TestManagerWarmup(t *testing.T) {
+	market := "^GSPC"
+	mgr := setupManager(t, market)
+	now, _ := time.Parse(time.RFC3339, "2010-09-18T14:00:00Z")
+
+	// Serve price data tagging the level at 3 before moving above it.
+	mgr.cfg.RequestPriceData = func(req shared.PriceDataRequest) {
+		data := make([]*shared.Candlestick, 0, 4)
+		for idx := range 4 {
+			price := float64(4 + idx)
+			data = append(data, &shared.Candlestick{
+				Open:      price,
+				Close:     price,
+				High:      price + 1,
+				Low:       price - 2,
+				Market:    req.Market,
+				Timeframe: shared.FiveMinute,
+				Closed:    true,
+			})
+		}
+
+		go func() { req.Response <- data }()
+	}
+
+	reactions := make(chan shared.ReactionAtLevel, 5)
+	mgr.cfg.SignalReactionAtLevel = func(reaction shared.ReactionAtLevel) {
+		reactions <- reaction
+		reaction.Status <- shared.Processed
+	}
+
+	// Report the market warmed up once enough candles have been seen.
+	history := 0
+	mgr.markets[market].cfg.FetchWarmedUpState = func(market string, timeframe shared.Timeframe) (bool, error) {
+		return history >= 30, nil
+	}
+
+	levelSignal := shared.LevelSignal{
+		Market: market,
+		Price:  3,
+		Status: make(chan shared.StatusCode, 1),
+	}
+	err := mgr.handleLevelSignal(levelSignal)
+	assert.NoError(t, err)
+
+	mkt := mgr.markets[market]
+	mkt.requestingPriceData.Store(true)
+
+	candle := &shared.Candlestick{
+		Open:      float64(10),
+		Close:     float64(15),
+		High:      float64(20),
+		Low:       float64(9),
+		Volume:    float64(2),
+		Market:    market,
+		Timeframe: shared.FiveMinute,
+		Date:      now,
+		Status:    make(chan shared.StatusCode, 1),
+		Closed:    true,
+	}
+
+	// Ensure no reactions are generated with insufficient history.
+	history = 29
+	err = mgr.handleUpdateSignal(candle)
+	assert.NoError(t, err)
+	assert.Equal(t, len(reactions), 0)
+	assert.True(t, mkt.RequestingPriceData())
+
+	// Ensure reactions are generated once the history threshold is met.
+	history = 30
+	candle.Status = make(chan shared.StatusCode, 1)
+	err = mgr.handleUpdateSignal(candle)
+	assert.NoError(t, err)
+	assert.Equal(t, len(reactions), 1)
+	assert.False(t, mkt.RequestingPriceData())
+}
+
+func TestManagerManualLevelReaction(t *testing.T) {
+	market := "^GSPC"
+	mgr := setupManager(t, market)
+
+	// Serve price data tagging the level at 3 before moving above it.
+	mgr.cfg.RequestPriceData = func(req shared.PriceDataRequest) {
+		data := make([]*shared.Candlestick, 0, 4)
+		for idx := range 4 {
+			price := float64(4 + idx)
+			data = append(data, &shared.Candlestick{
+				Open:      price,
+				Close:     price,
+				High:      price + 1,
+				Low:       price - 2,
+				Market:    req.Market,
+				Timeframe: shared.FiveMinute,
+				Closed:    true,
+			})
+		}
+
+		go func() { req.Response <- data }()
+	}
+
+	reactions := make(chan shared.ReactionAtLevel, 5)
+	mgr.cfg.SignalReactionAtLevel = func(reaction shared.ReactionAtLevel) {
+		reactions <- reaction
+		reaction.Status <- shared.Processed
+	}
+
+	// Ensure a manual level is added with its provided kind regardless of the close.
+	err := mgr.handleLevelSignal(shared.NewManualLevelSignal(market, 3, shared.Support))
+	assert.NoError(t, err)
+
+	mkt := mgr.markets[market]
+	levels := mkt.Levels()
+	assert.Equal(t, len(levels), 1)
+	assert.True(t, levels[0].Manual)
+	assert.Equal(t, levels[0].Kind, shared.Support)
+
+	// Ensure a reaction is generated against the manual level.
+	mkt.requestingPriceData.Store(true)
+	err = mgr.handleUpdateSignal(&shared.Candlestick{
+		Open:      float64(10),
+		Close:     float64(15),
+		High:      float64(20),
+		Low:       float64(9),
+		Volume:    float64(2),
+		Market:    market,
+		Timeframe: shared.FiveMinute,
+		Status:    make(chan shared.StatusCode, 1),
+		Closed:    true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, len(reactions), 1)
+	reaction := <-reactions
+	assert.True(t, reaction.Level.Manual)
+	assert.Equal(t, reaction.Level.Price, float64(3))
+}
+
+func TestManagerVWAPBandReaction(t *testing.T) {
+	market := "^GSPC"
+	mgr := setupManager(t, market)
+
+	bandsRequests := make(chan shared.VWAPBandsRequest, 1)
+	mgr.cfg.VWAPBands = true
+	mgr.cfg.RequestVWAPBands = func(request shared.VWAPBandsRequest) {
+		bandsRequests <- request
+		data := make([]*shared.VWAP, 0, 4)
+		for range 4 {
+			vwap := &shared.VWAP{Value: 3, StdDev: 1}
+			data = append(data, vwap.AtBand(request.Band))
+		}
+		request.Response <- data
 	}
 
-	mgr.SendMarketUpdate(firstCandle)
-
-	// Ensure the price action manager can receive level signals.
-	levelSignal := shared.LevelSignal{
-		Market: market,
-		Price:  20,
-		Status: make(chan shared.StatusCode, 1),
+	var signalled shared.ReactionAtVWAP
+	mgr.cfg.SignalReactionAtVWAP = func(reaction shared.ReactionAtVWAP) {
+		signalled = reaction
 	}
 
-	mgr.SendLevelSignal(levelSignal)
+	// Ensure a reaction at a tagged band is evaluated against the band's data.
+	mkt := mgr.markets[market]
+	mkt.taggedVWAP.Store(true)
+	mkt.taggedVWAPBand.Store(int32(shared.LowerBand1))
+	mkt.requestingVWAPData.Store(true)
 
-	// Ensure the price action manager can process candle metadata requests.
-	candleMetaReq := shared.CandleMetadataRequest{
-		Market:   market,
-		Response: make(chan []*shared.CandleMetadata, 1),
+	batch := &reactionBatch{}
+	err := mgr.evaluateReactionAtVWAPSignal(mkt, shared.FiveMinute, batch)
+	assert.NoError(t, err)
+
+	req := <-bandsRequests
+	assert.Equal(t, req.Band, shared.LowerBand1)
+	assert.Equal(t, len(batch.reactions), 1)
+	assert.Equal(t, batch.reactions[0].price, float64(2))
+
+	batch.reactions[0].signal()
+	assert.Equal(t, signalled.Band, shared.LowerBand1)
+
+	// Ensure the tagged band is reset along with the vwap data state.
+	assert.False(t, mkt.RequestingVWAPData())
+	assert.Equal(t, mkt.TaggedVWAPBand(), shared.VWAPLine)
+}
+
+func TestManagerReactionFilter(t *testing.T) {
+	market := "^GSPC"
+
+	data := make([]*shared.Candlestick, 0, shared.PriceDataPayloadSize)
+	requestPriceData := func(req shared.PriceDataRequest) {
+		go func() { req.Response <- data }()
 	}
 
-	mgr.SendCandleMetadataRequest(candleMetaReq)
-	<-candleMetaReq.Response
+	levelReactions := make(chan shared.ReactionAtLevel, 5)
+	signalReactionAtLevel := func(reaction shared.ReactionAtLevel) {
+		levelReactions <- reaction
+		reaction.Status <- shared.Processed
+	}
 
-	now, _, err := shared.NewYorkTime()
+	cfg := &ManagerConfig{
+		Markets:                   []string{market},
+		Subscribe:                 func(name string, sub chan shared.Candlestick) {},
+		RequestPriceData:          requestPriceData,
+		RequestVWAPData:           func(request shared.VWAPDataRequest) {},
+		RequestVWAP:               func(request shared.VWAPRequest) {},
+		SignalReactionAtLevel:     signalReactionAtLevel,
+		SignalReactionAtVWAP:      func(signal shared.ReactionAtVWAP) {},
+		SignalReactionAtImbalance: func(signal shared.ReactionAtImbalance) {},
+		FetchCaughtUpState: func(market string) (bool, error) {
+			return false, nil
+		},
+		ReactionFilter: &ReactionFilter{
+			IgnoreChop:       true,
+			MinPriceMovement: 1,
+		},
+		Logger: &log.Logger,
+	}
+
+	mgr, err := NewManager(cfg)
 	assert.NoError(t, err)
 
-	// Ensure the price action manage can process imbalance signals.
-	imbalanceSignal := shared.ImbalanceSignal{
+	// Add a support level for the reaction tests.
+	levelSignal := shared.LevelSignal{
 		Market: market,
-		Imbalance: *shared.NewImbalance(market, shared.FiveMinute, float64(15), float64(10),
-			float64(5), shared.Bullish, float64(0.5), now),
+		Price:  100,
+		Close:  101,
 		Status: make(chan shared.StatusCode, 1),
 	}
+	err = mgr.handleLevelSignal(levelSignal)
+	assert.NoError(t, err)
 
-	mgr.SendImbalanceSignal(imbalanceSignal)
-	<-imbalanceSignal.Status
+	newPriceData := func(closes []float64) []*shared.Candlestick {
+		candles := make([]*shared.Candlestick, 0, len(closes))
+		for idx := range closes {
+			candles = append(candles, &shared.Candlestick{
+				Open:  closes[idx],
+				Close: closes[idx],
+				High:  closes[idx] + 1,
+				Low:   closes[idx] - 2,
 
-	// Ensure the price action manager can be gracefully shutdown.
-	cancel()
-	<-done
-}
+				Market:    market,
+				Timeframe: shared.FiveMinute,
+				Status:    make(chan shared.StatusCode, 1),
+				Closed:    true,
+			})
+		}
 
-func TestManagerHandleUpdateSignal(t *testing.T) {
-	// Ensure the price action manager can be created.
-	market := "^GSPC"
-	mgr := setupManager(t, market)
+		return candles
+	}
 
-	// Ensure handling update signals for an unknown market errors.
-	wrongMarketCandle := shared.Candlestick{
-		Open:   float64(5),
-		Close:  float64(8),
-		High:   float64(9),
-		Low:    float64(3),
+	candle := shared.Candlestick{
+		Open:   float64(101),
+		Close:  float64(101),
+		High:   float64(102),
+		Low:    float64(99),
 		Volume: float64(2),
 
-		Market:    "^AAPL",
+		Market:    market,
 		Timeframe: shared.FiveMinute,
 		Status:    make(chan shared.StatusCode, 1),
+		Closed:    true,
 	}
 
-	err := mgr.handleUpdateSignal(&wrongMarketCandle)
-	assert.Error(t, err)
+	// Ensure a marginal reversal at the level is filtered at the price action stage.
+	data = newPriceData([]float64{101, 100.5, 100.8, 100.6})
+	mgr.markets[market].requestingPriceData.Store(true)
 
-	// Ensure handling update signals for a valid market succeeds.
-	firstCandle := shared.Candlestick{
-		Open:   float64(5),
-		Close:  float64(8),
-		High:   float64(9),
-		Low:    float64(3),
-		Volume: float64(2),
+	err = mgr.handleUpdateSignal(&candle)
+	assert.NoError(t, err)
+	assert.Equal(t, len(levelReactions), 0)
+	assert.False(t, mgr.markets[market].requestingPriceData.Load())
 
-		Market:    market,
-		Timeframe: shared.FiveMinute,
-		Status:    make(chan shared.StatusCode, 1),
+	// Ensure a clear reversal at the level is forwarded for evaluation.
+	data = newPriceData([]float64{101, 103, 104, 105})
+	mgr.markets[market].requestingPriceData.Store(true)
+
+	candle.Status = make(chan shared.StatusCode, 1)
+	err = mgr.handleUpdateSignal(&candle)
+	assert.NoError(t, err)
+	assert.Equal(t, len(levelReactions), 1)
+
+	reaction := <-levelReactions
+	assert.Equal(t, reaction.Reaction, shared.Reversal)
+	assert.Equal(t, reaction.CurrentPrice, float64(105))
+}
+
+func TestManagerRequireFullWindow(t *testing.T) {
+	market := "^GSPC"
+
+	var data []*shared.Candlestick
+	requestPriceData := func(req shared.PriceDataRequest) {
+		go func() { req.Response <- data }()
 	}
 
-	err = mgr.handleUpdateSignal(&firstCandle)
+	levelReactions := make(chan shared.ReactionAtLevel, 5)
+	signalReactionAtLevel := func(reaction shared.ReactionAtLevel) {
+		levelReactions <- reaction
+		reaction.Status <- shared.Processed
+	}
+
+	cfg := &ManagerConfig{
+		Markets:                   []string{market},
+		Subscribe:                 func(name string, sub chan shared.Candlestick) {},
+		RequestPriceData:          requestPriceData,
+		RequestVWAPData:           func(request shared.VWAPDataRequest) {},
+		RequestVWAP:               func(request shared.VWAPRequest) {},
+		SignalReactionAtLevel:     signalReactionAtLevel,
+		SignalReactionAtVWAP:      func(signal shared.ReactionAtVWAP) {},
+		SignalReactionAtImbalance: func(signal shared.ReactionAtImbalance) {},
+		FetchCaughtUpState: func(market string) (bool, error) {
+			return false, nil
+		},
+		RequireFullWindow: true,
+		Logger:            &log.Logger,
+	}
+
+	mgr, err := NewManager(cfg)
 	assert.NoError(t, err)
 
-	// Add a level for reaction tests.
+	// Add a support level for the reaction tests.
 	levelSignal := shared.LevelSignal{
 		Market: market,
-		Price:  3,
+		Price:  100,
+		Close:  101,
 		Status: make(chan shared.StatusCode, 1),
 	}
 	err = mgr.handleLevelSignal(levelSignal)
 	assert.NoError(t, err)
 
-	// Trigger a price request for the market.
-	mgr.markets[market].requestingPriceData.Store(true)
+	newPriceData := func(closes []float64) []*shared.Candlestick {
+		candles := make([]*shared.Candlestick, 0, len(closes))
+		for idx := range closes {
+			candles = append(candles, &shared.Candlestick{
+				Open:  closes[idx],
+				Close: closes[idx],
+				High:  closes[idx] + 1,
+				Low:   closes[idx] - 2,
 
-	// Ensure handling update signals when a price request is flagged processes the price request.
-	secondCandle := shared.Candlestick{
-		Open:   float64(10),
-		Close:  float64(15),
-		High:   float64(20),
-		Low:    float64(9),
+				Market:    market,
+				Timeframe: shared.FiveMinute,
+				Status:    make(chan shared.StatusCode, 1),
+				Closed:    true,
+			})
+		}
+
+		return candles
+	}
+
+	candle := shared.Candlestick{
+		Open:   float64(101),
+		Close:  float64(101),
+		High:   float64(102),
+		Low:    float64(99),
 		Volume: float64(2),
 
 		Market:    market,
 		Timeframe: shared.FiveMinute,
 		Status:    make(chan shared.StatusCode, 1),
+		Closed:    true,
 	}
 
-	err = mgr.handleUpdateSignal(&secondCandle)
+	// Ensure no reaction is emitted while the reaction window is partially formed and the
+	// price data request is kept pending.
+	data = newPriceData([]float64{101, 103, 104})
+	mgr.markets[market].requestingPriceData.Store(true)
+
+	err = mgr.handleUpdateSignal(&candle)
 	assert.NoError(t, err)
+	assert.Equal(t, len(levelReactions), 0)
+	assert.True(t, mgr.markets[market].requestingPriceData.Load())
 
-	// Ensure price request flag is reset after the request is processed.
+	// Ensure the reaction is emitted and classified once the reaction window has formed.
+	data = newPriceData([]float64{101, 103, 104, 105})
+
+	candle.Status = make(chan shared.StatusCode, 1)
+	err = mgr.handleUpdateSignal(&candle)
+	assert.NoError(t, err)
+	assert.Equal(t, len(levelReactions), 1)
 	assert.False(t, mgr.markets[market].requestingPriceData.Load())
 
-	// Trigger a vwap and imbalance request for the market.
-	mgr.markets[market].requestingVWAPData.Store(true)
-	mgr.markets[market].requestingImbalanceData.Store(true)
+	reaction := <-levelReactions
+	assert.Equal(t, reaction.Reaction, shared.Reversal)
+	assert.Equal(t, reaction.CurrentPrice, float64(105))
 
-	secondCandle.Status = make(chan shared.StatusCode, 1)
-	err = mgr.handleUpdateSignal(&secondCandle)
+	// Ensure partial windows are evaluated when full windows are not required.
+	mgr.markets[market].cfg.RequireFullWindow = false
+	data = newPriceData([]float64{101, 103, 104})
+	mgr.markets[market].requestingPriceData.Store(true)
+
+	candle.Status = make(chan shared.StatusCode, 1)
+	err = mgr.handleUpdateSignal(&candle)
 	assert.NoError(t, err)
+	assert.Equal(t, len(levelReactions), 1)
+	assert.False(t, mgr.markets[market].requestingPriceData.Load())
 
-	// Ensure vwap request flag is reset after the request is processed.
-	assert.False(t, mgr.markets[market].requestingVWAPData.Load())
+	// Ensure windows below the minimum reaction candles are cleanly deferred instead of
+	// erroring on every update, right after catch up for instance.
+	mgr.markets[market].cfg.MinReactionCandles = 4
+	data = newPriceData([]float64{101, 103, 104})
+	mgr.markets[market].requestingPriceData.Store(true)
+
+	for range 3 {
+		candle.Status = make(chan shared.StatusCode, 1)
+		err = mgr.handleUpdateSignal(&candle)
+		assert.NoError(t, err)
+		assert.Equal(t, len(levelReactions), 1)
+		assert.True(t, mgr.markets[market].requestingPriceData.Load())
+	}
+
+	// Ensure windows below the classification minimum are deferred by default.
+	mgr.markets[market].cfg.MinReactionCandles = 0
+	data = newPriceData([]float64{101, 103})
+
+	candle.Status = make(chan shared.StatusCode, 1)
+	err = mgr.handleUpdateSignal(&candle)
+	assert.NoError(t, err)
+	assert.Equal(t, len(levelReactions), 1)
+	assert.True(t, mgr.markets[market].requestingPriceData.Load())
+
+	// Ensure the deferred reaction is emitted once enough candles close.
+	data = newPriceData([]float64{101, 103, 104, 105})
+
+	candle.Status = make(chan shared.StatusCode, 1)
+	err = mgr.handleUpdateSignal(&candle)
+	assert.NoError(t, err)
+	assert.Equal(t, len(levelReactions), 2)
+	assert.False(t, mgr.markets[market].requestingPriceData.Load())
 }
 
 func TestFillManagerChannels(t *testing.T) {
@@ -395,6 +1504,7 @@ func TestFillManagerChannels(t *testing.T) {
 
 		Market:    market,
 		Timeframe: shared.FiveMinute,
+		Closed:    true,
 	}
 
 	levelSignal := shared.LevelSignal{
@@ -429,6 +1539,14 @@ func TestFillManagerChannels(t *testing.T) {
 	assert.Equal(t, len(mgr.updateSignals), bufferSize)
 	assert.Equal(t, len(mgr.levelSignals), bufferSize)
 	assert.Equal(t, len(mgr.imbalanceSignals), bufferSize)
+
+	// Ensure signals dropped at capacity are counted.
+	assert.Equal(t, mgr.Dropped(), map[string]uint64{
+		"level":                   1,
+		"market update":           1,
+		"candle metadata request": 1,
+		"imbalance":               1,
+	})
 }
 
 func TestManagerHandleLevelSignal(t *testing.T) {
@@ -447,6 +1565,7 @@ func TestManagerHandleLevelSignal(t *testing.T) {
 		Market:    market,
 		Timeframe: shared.FiveMinute,
 		Status:    make(chan shared.StatusCode, 1),
+		Closed:    true,
 	}
 
 	err := mgr.handleUpdateSignal(&firstCandle)
@@ -497,6 +1616,7 @@ func TestManagerHandleCandleMetadataSignal(t *testing.T) {
 			Market:    market,
 			Timeframe: shared.FiveMinute,
 			Status:    make(chan shared.StatusCode, 1),
+			Closed:    true,
 		}
 
 		err := mgr.handleUpdateSignal(&candle)
@@ -518,6 +1638,154 @@ func TestManagerHandleCandleMetadataSignal(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestManagerCandleMetadataCache(t *testing.T) {
+	market := "^GSPC"
+	mgr := setupManager(t, market)
+
+	now, _, err := shared.NewYorkTime()
+	assert.NoError(t, err)
+
+	// Price data always ends with the latest candle of the market.
+	latest := now
+	var priceDataRequests int
+	mgr.cfg.RequestPriceData = func(req shared.PriceDataRequest) {
+		priceDataRequests++
+		data := make([]*shared.Candlestick, 0, req.N)
+		for idx := range int(req.N) {
+			data = append(data, &shared.Candlestick{
+				Open:      float64(idx),
+				Close:     float64(idx + 1),
+				High:      float64(idx + 2),
+				Low:       float64(idx),
+				Volume:    float64(idx),
+				Market:    req.Market,
+				Timeframe: req.Timeframe,
+				Date:      latest.Add(-time.Minute * 5 * time.Duration(int(req.N)-1-idx)),
+				Closed:    true,
+			})
+		}
+
+		go func() { req.Response <- data }()
+	}
+
+	update := func(date time.Time) {
+		latest = date
+		candle := &shared.Candlestick{
+			Open:      float64(5),
+			Close:     float64(8),
+			High:      float64(9),
+			Low:       float64(3),
+			Volume:    float64(2),
+			Market:    market,
+			Timeframe: shared.FiveMinute,
+			Date:      date,
+			Status:    make(chan shared.StatusCode, 1),
+			Closed:    true,
+		}
+
+		err := mgr.handleUpdateSignal(candle)
+		assert.NoError(t, err)
+	}
+
+	requestMetadata := func(lookback uint32) []*shared.CandleMetadata {
+		req := shared.NewCandleMetadataRequest(market, shared.FiveMinute, lookback)
+		result := make(chan []*shared.CandleMetadata, 1)
+		go func() { result <- <-req.Response }()
+
+		err := mgr.handleCandleMetadataRequest(req)
+		assert.NoError(t, err)
+
+		return <-result
+	}
+
+	update(now)
+
+	// Ensure the first request within a candle generates metadata from price data.
+	first := requestMetadata(0)
+	assert.Equal(t, priceDataRequests, 1)
+	assert.NotEqual(t, len(first), 0)
+
+	// Ensure a second request within the same candle is served from the cache.
+	second := requestMetadata(0)
+	assert.Equal(t, priceDataRequests, 1)
+	assert.Equal(t, len(second), len(first))
+	for idx := range first {
+		assert.True(t, first[idx] == second[idx])
+	}
+
+	// Ensure a new candle invalidates the cached metadata.
+	update(now.Add(time.Minute * 5))
+	third := requestMetadata(0)
+	assert.Equal(t, priceDataRequests, 2)
+	assert.Equal(t, third[len(third)-1].Date, now)
+
+	// Ensure a lookback larger than the reaction window is served with enough candles and
+	// cached apart from the reaction window's metadata.
+	lookback := mgr.markets[market].ReactionWindow() * 3
+	extended := requestMetadata(lookback)
+	assert.Equal(t, priceDataRequests, 3)
+	assert.Equal(t, len(extended), int(lookback))
+	assert.Equal(t, extended[len(extended)-1].Date, now)
+
+	extended = requestMetadata(lookback)
+	assert.Equal(t, priceDataRequests, 3)
+	assert.Equal(t, len(extended), int(lookback))
+
+	// Ensure a lookback smaller than the reaction window is served the reaction window.
+	short := requestMetadata(1)
+	assert.Equal(t, len(short), len(third))
+}
+
+func TestManagerRequestTimeouts(t *testing.T) {
+	market := "^GSPC"
+
+	tests := []struct {
+		name     string
+		timeouts shared.Timeouts
+		// respond is the flag for responding to price data requests.
+		respond bool
+		wantErr string
+	}{
+		{"short metadata timeout", shared.Timeouts{PriceData: time.Minute, CandleMetadata: time.Millisecond * 20},
+			true, "timed out waiting for candle metadata response"},
+		{"short price data timeout", shared.Timeouts{PriceData: time.Millisecond * 20, CandleMetadata: time.Minute},
+			false, "timed out waiting for price data response"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mgr := setupManager(t, market)
+			mgr.cfg.Timeouts = &test.timeouts
+
+			// Price data is delayed past the metadata timeout when responded to.
+			requestPriceData := mgr.cfg.RequestPriceData
+			mgr.cfg.RequestPriceData = func(req shared.PriceDataRequest) {
+				if !test.respond {
+					return
+				}
+
+				go func() {
+					time.Sleep(time.Millisecond * 40)
+					requestPriceData(req)
+				}()
+			}
+
+			// Ensure the short timeout fires independently of the longer timeout, the
+			// metadata response is never received.
+			req := &shared.CandleMetadataRequest{
+				Market:    market,
+				Timeframe: shared.FiveMinute,
+				Response:  make(chan []*shared.CandleMetadata),
+			}
+			start := time.Now()
+			err := mgr.handleCandleMetadataRequest(req)
+			assert.Error(t, err)
+			assert.Equal(t, err.Error(), test.wantErr)
+			assert.True(t, time.Since(start) < time.Second*2)
+		})
+	}
+}
+
 func TestManagerHandleImbalanceSignal(t *testing.T) {
 	// Ensure the price action manager can be created.
 	market := "^GSPC"
@@ -534,6 +1802,7 @@ func TestManagerHandleImbalanceSignal(t *testing.T) {
 		Market:    market,
 		Timeframe: shared.FiveMinute,
 		Status:    make(chan shared.StatusCode, 1),
+		Closed:    true,
 	}
 
 	err := mgr.handleUpdateSignal(&firstCandle)
@@ -562,3 +1831,147 @@ func TestManagerHandleImbalanceSignal(t *testing.T) {
 	err = mgr.handleImbalanceSignal(imbalanceSignal)
 	assert.NoError(t, err)
 }
+
+// sqliteStore stores price action state in a sqlite database.
+type sqliteStore struct {
+	db *database.SQLite
+}
+
+func (s *sqliteStore) PersistState(market string, levels []*shared.Level, imbalances []*shared.Imbalance) error {
+	return s.db.PersistPriceActionState(context.Background(), market, levels, imbalances)
+}
+
+func (s *sqliteStore) LoadState(market string) ([]*shared.Level, []*shared.Imbalance, error) {
+	return s.db.QueryPriceActionState(market)
+}
+
+// failingStore fails to persist and load price action state.
+type failingStore struct{}
+
+func (s *failingStore) PersistState(market string, levels []*shared.Level, imbalances []*shared.Imbalance) error {
+	return fmt.Errorf("unexpected error")
+}
+
+func (s *failingStore) LoadState(market string) ([]*shared.Level, []*shared.Imbalance, error) {
+	return nil, nil, fmt.Errorf("unexpected error")
+}
+
+func TestManagerPersistState(t *testing.T) {
+	market := "^GSPC"
+	db, err := database.NewSQLite(context.Background(), &database.SQLiteConfig{
+		Filepath: filepath.Join(t.TempDir(), "state.db"),
+		Logger:   &log.Logger,
+	})
+	assert.NoError(t, err)
+	defer db.Close()
+
+	store := &sqliteStore{db: db}
+	mgr := setupManager(t, market)
+	mgr.cfg.Store = store
+	mgr.store = store
+
+	now, _, err := shared.NewYorkTime()
+	assert.NoError(t, err)
+
+	// Ensure added levels and imbalances are persisted.
+	err = mgr.handleLevelSignal(shared.LevelSignal{
+		Market: market,
+		Price:  10,
+		Close:  12,
+		Status: make(chan shared.StatusCode, 1),
+	})
+	assert.NoError(t, err)
+	err = mgr.handleLevelSignal(shared.LevelSignal{
+		Market: market,
+		Price:  30,
+		Close:  25,
+		Status: make(chan shared.StatusCode, 1),
+	})
+	assert.NoError(t, err)
+	err = mgr.handleImbalanceSignal(shared.ImbalanceSignal{
+		Market: market,
+		Imbalance: *shared.NewImbalance(market, shared.FiveMinute, float64(15), float64(10),
+			float64(5), shared.Bullish, float64(0.5), now),
+		Status: make(chan shared.StatusCode, 1),
+	})
+	assert.NoError(t, err)
+
+	levels, imbalances, err := db.QueryPriceActionState(market)
+	assert.NoError(t, err)
+	assert.Equal(t, len(levels), 2)
+	assert.Equal(t, len(imbalances), 1)
+
+	// Apply reversals to the levels and purge the imbalance with a market update.
+	mkt, ok := mgr.fetchMarket(market)
+	assert.True(t, ok)
+	mkt.Levels()[0].ApplyPriceReaction(shared.Reversal)
+	mkt.Levels()[1].ApplyPriceReaction(shared.Reversal)
+	mkt.Levels()[1].ApplyPriceReaction(shared.Reversal)
+
+	candle := shared.Candlestick{
+		Open:      float64(6),
+		Close:     float64(4),
+		High:      float64(7),
+		Low:       float64(3),
+		Volume:    float64(2),
+		Market:    market,
+		Timeframe: shared.FiveMinute,
+		Date:      now,
+		Status:    make(chan shared.StatusCode, 1),
+		Closed:    true,
+	}
+	err = mgr.handleUpdateSignal(&candle)
+	assert.NoError(t, err)
+	assert.True(t, mkt.Imbalances()[0].Purged.Load())
+
+	// Ensure the latest state is persisted when the manager shuts down.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	mgr.Run(ctx)
+
+	// Ensure a restarted manager reloads the levels and imbalances with their state.
+	cfg := *mgr.cfg
+	restarted, err := NewManager(&cfg)
+	assert.NoError(t, err)
+
+	mkt, ok = restarted.fetchMarket(market)
+	assert.True(t, ok)
+
+	levels = mkt.Levels()
+	assert.Equal(t, len(levels), 2)
+	assert.Equal(t, levels[0].Price, float64(10))
+	assert.Equal(t, levels[0].Kind, shared.Support)
+	assert.Equal(t, levels[0].Reversals.Load(), uint32(1))
+	assert.Equal(t, levels[1].Price, float64(30))
+	assert.Equal(t, levels[1].Kind, shared.Resistance)
+	assert.Equal(t, levels[1].Reversals.Load(), uint32(2))
+
+	imbalances = mkt.Imbalances()
+	assert.Equal(t, len(imbalances), 1)
+	assert.Equal(t, imbalances[0].High, float64(15))
+	assert.Equal(t, imbalances[0].Sentiment, shared.Bullish)
+	assert.True(t, imbalances[0].Purged.Load())
+	assert.False(t, imbalances[0].Invalidated.Load())
+
+	// Ensure restored levels are tracked for market updates.
+	levels[0].ApplyPriceReaction(shared.Break)
+	err = restarted.handleUpdateSignal(&shared.Candlestick{
+		Open:      float64(6),
+		Close:     float64(4),
+		High:      float64(7),
+		Low:       float64(3),
+		Volume:    float64(2),
+		Market:    market,
+		Timeframe: shared.FiveMinute,
+		Date:      now,
+		Status:    make(chan shared.StatusCode, 1),
+		Closed:    true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, levels[0].Breaks.Load(), uint32(1))
+
+	// Ensure a failing state load fails market creation.
+	cfg.Store = &failingStore{}
+	_, err = NewManager(&cfg)
+	assert.Error(t, err)
+}
@@ -24,6 +24,37 @@ type MarketConfig struct {
 	RequestVWAP func(request shared.VWAPRequest)
 	// FetchCaughtUpState returns the caught up status of the provided market.
 	FetchCaughtUpState func(market string) (bool, error)
+	// FetchWarmedUpState returns whether the provided market has enough candles of the
+	// provided timeframe to evaluate reactions. Optional, markets are warmed up if nil.
+	FetchWarmedUpState func(market string, timeframe shared.Timeframe) (bool, error)
+	// ReactionFilter is the minimum quality bar for relaying reactions. All reactions are
+	// relayed if nil.
+	ReactionFilter *ReactionFilter
+	// ReactionWindow is the number of candles reactions are evaluated over. The default
+	// window of shared.PriceDataPayloadSize is used if zero.
+	ReactionWindow uint32
+	// RequireFullWindow is the flag for only evaluating reactions once the full reaction
+	// window has formed, partial windows are deferred until enough candles close.
+	RequireFullWindow bool
+	// MinReactionCandles is the minimum number of candles reactions are evaluated with,
+	// reactions with fewer candles are deferred until enough candles close. The minimum of
+	// shared.MinReactionWindow is used if zero.
+	MinReactionCandles uint32
+	// Timeouts represents the timeouts of vwap waits. The default timeouts are used if nil.
+	Timeouts *shared.Timeouts
+	// RequireImbalancePurge is the flag for only reacting to imbalances after price has purged them.
+	RequireImbalancePurge bool
+	// VWAPBands is the flag for tagging the standard deviation bands of the vwap along with
+	// the vwap line.
+	VWAPBands bool
+	// LateBreakHandling is how breaks only confirmed by the last close of the reaction window
+	// are classified.
+	LateBreakHandling shared.LateBreakHandling
+	// ReactionEvaluation is when market updates are evaluated for reactions.
+	ReactionEvaluation ReactionEvaluation
+	// StaleDistance is the distance from the current price, as a percentage of it, beyond
+	// which levels and imbalances are swept as stale. Distant entries are kept if zero.
+	StaleDistance float64
 	// Logger represents the application logger.
 	Logger *zerolog.Logger
 }
@@ -44,6 +75,29 @@ func (cfg *MarketConfig) Validate() error {
 	if cfg.FetchCaughtUpState == nil {
 		errs = errors.Join(errs, fmt.Errorf("fetch caught up state function cannot be nil"))
 	}
+	if cfg.ReactionFilter != nil {
+		err := cfg.ReactionFilter.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating reaction filter: %v", err))
+		}
+	}
+	if cfg.ReactionWindow != 0 && cfg.ReactionWindow < shared.MinReactionWindow {
+		errs = errors.Join(errs, fmt.Errorf("reaction window must be at least %d candles",
+			shared.MinReactionWindow))
+	}
+	err := validateMinReactionCandles(cfg.MinReactionCandles, cfg.ReactionWindow)
+	if err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if cfg.Timeouts != nil {
+		err := cfg.Timeouts.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating timeouts: %v", err))
+		}
+	}
+	if cfg.StaleDistance < 0 {
+		errs = errors.Join(errs, fmt.Errorf("stale distance cannot be negative"))
+	}
 	if cfg.Logger == nil {
 		errs = errors.Join(errs, fmt.Errorf("logger cannot be nil"))
 	}
@@ -65,6 +119,10 @@ type Market struct {
 	requestingPriceData     atomic.Bool
 	requestingVWAPData      atomic.Bool
 	requestingImbalanceData atomic.Bool
+	taggedVWAPBand          atomic.Int32
+	paused                  atomic.Bool
+	lastPrice               atomic.Float64
+	metadataCache           *candleMetadataCache
 }
 
 // NewMarket initializes a new market.
@@ -88,11 +146,70 @@ func NewMarket(cfg *MarketConfig) (*Market, error) {
 		cfg:               cfg,
 		levelSnapshot:     levelSnapshot,
 		imbalanceSnapshot: imbalanceSnapshot,
+		metadataCache:     newCandleMetadataCache(),
 	}
 
 	return mgr, nil
 }
 
+// ReactionWindow returns the number of candles reactions are evaluated over.
+func (m *Market) ReactionWindow() uint32 {
+	if m.cfg.ReactionWindow == 0 {
+		return shared.PriceDataPayloadSize
+	}
+
+	return m.cfg.ReactionWindow
+}
+
+// validateMinReactionCandles checks the provided minimum reaction candles are within the
+// classification minimum and the provided reaction window.
+func validateMinReactionCandles(minCandles uint32, window uint32) error {
+	if minCandles == 0 {
+		return nil
+	}
+
+	if window == 0 {
+		window = shared.PriceDataPayloadSize
+	}
+
+	switch {
+	case minCandles < shared.MinReactionWindow:
+		return fmt.Errorf("minimum reaction candles must be at least %d candles",
+			shared.MinReactionWindow)
+	case minCandles > window:
+		return fmt.Errorf("minimum reaction candles cannot exceed the reaction window of %d "+
+			"candles", window)
+	}
+
+	return nil
+}
+
+// minReactionCandles returns the minimum number of candles reactions are evaluated with.
+func (m *Market) minReactionCandles() int {
+	if m.cfg.MinReactionCandles == 0 {
+		return shared.MinReactionWindow
+	}
+
+	return int(m.cfg.MinReactionCandles)
+}
+
+// windowFormed checks whether the provided number of candles can be evaluated for reactions.
+// Windows below the minimum reaction candles are never evaluated, partial windows are only
+// evaluated when full windows are not required.
+func (m *Market) windowFormed(n int) bool {
+	if n < m.minReactionCandles() {
+		return false
+	}
+
+	return !m.cfg.RequireFullWindow || n >= int(m.ReactionWindow())
+}
+
+// requestInterval returns the number of updates to wait after a tag before requesting data,
+// the tagging candle and the updates after it make up the reaction window.
+func (m *Market) requestInterval() uint32 {
+	return m.ReactionWindow() - 1
+}
+
 // evaluateTaggedLevels checks whether levels have been tagged by current price action. If confirmed
 // a price data request is signalled after a brief interval of updates.
 func (m *Market) evaluateTaggedLevels(candle *shared.Candlestick) {
@@ -100,19 +217,20 @@ func (m *Market) evaluateTaggedLevels(candle *shared.Candlestick) {
 	taggedLevels := m.taggedLevels.Load()
 	levelUpdateCounter := m.levelUpdateCounter.Load()
 	requestingPriceData := m.requestingPriceData.Load()
+	interval := m.requestInterval()
 
 	switch {
 	case len(filteredLevels) > 0 && !taggedLevels && levelUpdateCounter == 0:
 		// Set the tagged levels flag to true if there is no pending price data request.
 		m.taggedLevels.Store(true)
 
-	case taggedLevels && levelUpdateCounter < shared.MaxPriceDataRequestInterval:
+	case taggedLevels && levelUpdateCounter < interval:
 		// Increment the update counter while its below the price data request interval and set
 		// the price data request flag to true once the data request interval is reached.
 		counter := m.levelUpdateCounter.Add(1)
-		if counter == shared.MaxPriceDataRequestInterval && !requestingPriceData {
-			// NB: once a level is tagged it will take MaxPriceDataRequestInterval worth (3) of
-			// market updates before the market signals requesting for price data.
+		if counter == interval && !requestingPriceData {
+			// NB: once a level is tagged it will take the request interval worth (3 by default)
+			// of market updates before the market signals requesting for price data.
 			m.requestingPriceData.Store(true)
 		}
 	}
@@ -121,23 +239,25 @@ func (m *Market) evaluateTaggedLevels(candle *shared.Candlestick) {
 // evaluateTaggedVWAP checks whether the current vwap is tagged by current price action. If confirmed
 // a vwap data request is signalled after a brief interval of updates.
 func (m *Market) evaluateTaggedVWAP(candle *shared.Candlestick, vwap *shared.VWAP) {
-	vwapTagged := m.vwapTagged(vwap, candle)
+	band, vwapTagged := m.fetchTaggedVWAPBand(vwap, candle)
 	taggedVWAP := m.taggedVWAP.Load()
 	requestingPriceData := m.requestingPriceData.Load()
 	vwapUpdateCounter := m.vwapUpdateCounter.Load()
+	interval := m.requestInterval()
 
 	switch {
 	case vwapTagged && !taggedVWAP && vwapUpdateCounter == 0:
 		// Set the tagged vwap flag to true if there is no pending vwap data request.
 		m.taggedVWAP.Store(true)
+		m.taggedVWAPBand.Store(int32(band))
 
-	case taggedVWAP && vwapUpdateCounter < shared.MaxVWAPDataRequestInterval:
+	case taggedVWAP && vwapUpdateCounter < interval:
 		// Increment the update counter while its below the vwap data request interval and set
 		// the price data request flag to true once the data request interval is reached.
 		counter := m.vwapUpdateCounter.Add(1)
-		if counter == shared.MaxVWAPDataRequestInterval && !requestingPriceData {
-			// NB: once the vwap is tagged it will take MaxVWAPDataRequestInterval worth (3) of
-			// market updates before the market signals requesting for vwap data.
+		if counter == interval && !requestingPriceData {
+			// NB: once the vwap is tagged it will take the request interval worth (3 by default)
+			// of market updates before the market signals requesting for vwap data.
 			m.requestingVWAPData.Store(true)
 		}
 	}
@@ -150,19 +270,20 @@ func (m *Market) evaluateTaggedImbalances(candle *shared.Candlestick) {
 	taggedImbalance := m.taggedImbalance.Load()
 	imbalanceUpdateCounter := m.imbalanceUpdateCounter.Load()
 	requestingImbalanceData := m.requestingImbalanceData.Load()
+	interval := m.requestInterval()
 
 	switch {
 	case len(filteredImbalances) > 0 && !taggedImbalance && imbalanceUpdateCounter == 0:
 		// Set the tagged imbalance flag to true if there is no pending imbalance data request.
 		m.taggedImbalance.Store(true)
 
-	case taggedImbalance && imbalanceUpdateCounter < shared.MaxVWAPDataRequestInterval:
+	case taggedImbalance && imbalanceUpdateCounter < interval:
 		// Increment the update counter while its below the imbalance data request interval and set
 		// the price data request flag to true once the data request interval is reached.
 		counter := m.imbalanceUpdateCounter.Add(1)
-		if counter == shared.MaxImbalanceDataRequestInterval && !requestingImbalanceData {
-			// NB: once the imbalance is tagged it will take MaxImbalanceDataRequestInterval worth (3) of
-			// market updates before the market signals requesting for imbalance data.
+		if counter == interval && !requestingImbalanceData {
+			// NB: once the imbalance is tagged it will take the request interval worth (3 by default)
+			// of market updates before the market signals requesting for imbalance data.
 			m.requestingImbalanceData.Store(true)
 		}
 	}
@@ -170,6 +291,8 @@ func (m *Market) evaluateTaggedImbalances(candle *shared.Candlestick) {
 
 // Update processes the provided market candlestick data.
 func (m *Market) Update(candle *shared.Candlestick) {
+	m.metadataCache.update(candle)
+	m.lastPrice.Store(candle.Close)
 	m.levelSnapshot.Update(candle)
 	m.imbalanceSnapshot.Update(candle)
 
@@ -178,8 +301,10 @@ func (m *Market) Update(candle *shared.Candlestick) {
 		m.cfg.Logger.Error().Msgf("fetching %s caught up state: %v", m.cfg.Market, err)
 	}
 
-	// Only evaluate vwap and imbalance tags when the market is confirmed to be caught up.
-	if caughtUp {
+	// Only evaluate vwap and imbalance tags when the market is confirmed to be caught up and
+	// warmed up, forming candles keep the snapshots current without being evaluated for tags
+	// when evaluating on close.
+	if caughtUp && m.cfg.ReactionEvaluation.evaluates(candle.Closed) && m.warmedUp(candle.Timeframe) {
 		m.evaluateTaggedLevels(candle)
 		m.evaluateTaggedImbalances(candle)
 
@@ -189,7 +314,7 @@ func (m *Market) Update(candle *shared.Candlestick) {
 		m.cfg.RequestVWAP(*req)
 		select {
 		case vwap = <-req.Response:
-		case <-time.After(shared.TimeoutDuration * 4):
+		case <-time.After(m.cfg.Timeouts.VWAPTimeout() * 4):
 			m.cfg.Logger.Error().Msgf("timed out waiting for current vwap response")
 			return
 		}
@@ -198,6 +323,22 @@ func (m *Market) Update(candle *shared.Candlestick) {
 	}
 }
 
+// warmedUp checks whether the market has enough candles of the provided timeframe to
+// evaluate reactions.
+func (m *Market) warmedUp(timeframe shared.Timeframe) bool {
+	if m.cfg.FetchWarmedUpState == nil {
+		return true
+	}
+
+	warmedUp, err := m.cfg.FetchWarmedUpState(m.cfg.Market, timeframe)
+	if err != nil {
+		m.cfg.Logger.Error().Msgf("fetching %s warmed up state: %v", m.cfg.Market, err)
+		return false
+	}
+
+	return warmedUp
+}
+
 // RequestingPriceData indicates whether the provided market is requesting price data.
 func (m *Market) RequestingPriceData() bool {
 	return m.requestingPriceData.Load()
@@ -208,11 +349,26 @@ func (m *Market) RequestingVWAPData() bool {
 	return m.requestingVWAPData.Load()
 }
 
+// TaggedVWAPBand returns the vwap line or band tagged by price action.
+func (m *Market) TaggedVWAPBand() shared.VWAPBand {
+	return shared.VWAPBand(m.taggedVWAPBand.Load())
+}
+
 // RequestingVWAPData indicates whether the provided market is requesting imbalance data.
 func (m *Market) RequestingImbalanceData() bool {
 	return m.requestingImbalanceData.Load()
 }
 
+// TrackReaction checks whether the provided reaction at the provided focus price meets the
+// market's reaction filter.
+func (m *Market) TrackReaction(reaction *shared.ReactionAtFocus, focusPrice float64) bool {
+	if m.cfg.ReactionFilter == nil {
+		return true
+	}
+
+	return m.cfg.ReactionFilter.Allows(reaction, focusPrice)
+}
+
 // AddLevel adds the provided level to the market's level snapshot.
 func (m *Market) AddLevel(level *shared.Level) {
 	m.levelSnapshot.Add(level)
@@ -223,23 +379,68 @@ func (m *Market) AddImbalance(imb *shared.Imbalance) {
 	m.imbalanceSnapshot.Add(imb)
 }
 
-// vwaptagged checks whether the provided vwap was tagged by the provided candlestick.
-func (m *Market) vwapTagged(vwap *shared.VWAP, candle *shared.Candlestick) bool {
+// RecordStopOut records the stop out of a position entered at the tracked level with the
+// provided price. It returns false if no level with the price is tracked.
+func (m *Market) RecordStopOut(price float64, stoppedOn time.Time) bool {
+	levels := m.levelSnapshot.Levels()
+	for idx := range levels {
+		if levels[idx].Price == price {
+			levels[idx].RecordStopOut(stoppedOn)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Levels returns the levels tracked for the market, ordered from the oldest to the newest.
+func (m *Market) Levels() []*shared.Level {
+	return m.levelSnapshot.Levels()
+}
+
+// Imbalances returns the imbalances tracked for the market, ordered from the oldest to the newest.
+func (m *Market) Imbalances() []*shared.Imbalance {
+	return m.imbalanceSnapshot.Imbalances()
+}
+
+// fetchTaggedVWAPBand returns the vwap line or band tagged by the provided candlestick. The
+// vwap line takes precedence, followed by the nearest bands when band tagging is enabled.
+func (m *Market) fetchTaggedVWAPBand(vwap *shared.VWAP, candle *shared.Candlestick) (shared.VWAPBand, bool) {
+	if m.vwapTagged(vwap.Value, candle) {
+		return shared.VWAPLine, true
+	}
+
+	// Bands collapse onto the vwap line when there is no deviation.
+	if !m.cfg.VWAPBands || vwap.StdDev == 0 {
+		return shared.VWAPLine, false
+	}
+
+	for _, band := range shared.VWAPBands {
+		if m.vwapTagged(vwap.Band(band), candle) {
+			return band, true
+		}
+	}
+
+	return shared.VWAPLine, false
+}
+
+// vwaptagged checks whether the provided vwap value was tagged by the provided candlestick.
+func (m *Market) vwapTagged(value float64, candle *shared.Candlestick) bool {
 	var kind shared.LevelKind
 	switch {
-	case vwap.Value > candle.Close:
+	case value > candle.Close:
 		kind = shared.Resistance
-	case vwap.Value < candle.Close:
+	case value < candle.Close:
 		kind = shared.Support
 	}
 
 	switch kind {
 	case shared.Support:
-		if candle.Low <= vwap.Value {
+		if candle.Low <= value {
 			return true
 		}
 	case shared.Resistance:
-		if candle.High >= vwap.Value {
+		if candle.High >= value {
 			return true
 		}
 	}
@@ -275,6 +476,11 @@ func (m *Market) imbalanceTagged(imb *shared.Imbalance, candle *shared.Candlesti
 		return false
 	}
 
+	// Unpurged imbalances are not considered tagged if reactions require a purge.
+	if m.cfg.RequireImbalancePurge && !imb.Purged.Load() {
+		return false
+	}
+
 	switch imb.Sentiment {
 	case shared.Bullish:
 		if candle.Low <= imb.High {
@@ -321,6 +527,7 @@ func (m *Market) GenerateReactionsAtTaggedLevels(data []*shared.Candlestick) ([]
 		if err != nil {
 			return nil, err
 		}
+		reaction.ReclassifyLateBreak(m.cfg.LateBreakHandling)
 		reactions[idx] = reaction
 	}
 
@@ -347,6 +554,15 @@ func (m *Market) GenerateReactionsAtTaggedImbalances(data []*shared.Candlestick)
 		if err != nil {
 			return nil, err
 		}
+		reaction.ReclassifyLateBreak(m.cfg.LateBreakHandling)
+
+		// Tagged imbalances overlapping the reacted to imbalance stack at its price region.
+		for _, imb := range taggedSet {
+			if taggedImbalance.Overlaps(imb) {
+				reaction.ImbalanceStack++
+			}
+		}
+
 		reactions[idx] = reaction
 	}
 
@@ -363,6 +579,7 @@ func (m *Market) ResetPriceDataState() {
 // ResetVWAPDataState resets the flags and counters associated with vwap data state for the market.
 func (m *Market) ResetVWAPDataState() {
 	m.taggedVWAP.Store(false)
+	m.taggedVWAPBand.Store(int32(shared.VWAPLine))
 	m.vwapUpdateCounter.Store(0)
 	m.requestingVWAPData.Store(false)
 }
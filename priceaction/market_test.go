@@ -3,6 +3,7 @@ package priceaction
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/dnldd/entry/shared"
 	"github.com/peterldowns/testy/assert"
@@ -18,6 +19,7 @@ func TestMarketConfigValidate(t *testing.T) {
 		RequestVWAPData    func(request shared.VWAPDataRequest)
 		RequestVWAP        func(request shared.VWAPRequest)
 		FetchCaughtUpState func(market string) (bool, error)
+		ReactionWindow     uint32
 		Logger             *zerolog.Logger
 	}
 
@@ -98,6 +100,19 @@ func TestMarketConfigValidate(t *testing.T) {
 			wantErr:     true,
 			errContains: []string{"logger cannot be nil"},
 		},
+		{
+			name: "reaction window below minimum",
+			fields: fields{
+				Market:             "TEST",
+				RequestVWAPData:    func(request shared.VWAPDataRequest) {},
+				RequestVWAP:        func(request shared.VWAPRequest) {},
+				FetchCaughtUpState: func(market string) (bool, error) { return true, nil },
+				ReactionWindow:     2,
+				Logger:             &logger,
+			},
+			wantErr:     true,
+			errContains: []string{"reaction window must be at least 3 candles"},
+		},
 		{
 			name:    "multiple missing fields",
 			fields:  fields{},
@@ -119,6 +134,7 @@ func TestMarketConfigValidate(t *testing.T) {
 				RequestVWAPData:    tt.fields.RequestVWAPData,
 				RequestVWAP:        tt.fields.RequestVWAP,
 				FetchCaughtUpState: tt.fields.FetchCaughtUpState,
+				ReactionWindow:     tt.fields.ReactionWindow,
 				Logger:             tt.fields.Logger,
 			}
 			err := cfg.Validate()
@@ -166,6 +182,7 @@ func TestMarket(t *testing.T) {
 		Low:    float64(3),
 		Volume: float64(2),
 		Status: make(chan shared.StatusCode, 1),
+		Closed: true,
 	}
 
 	resistanceClose := float64(9)
@@ -201,6 +218,7 @@ func TestMarket(t *testing.T) {
 		Low:    float64(1),
 		Volume: float64(1),
 		Status: make(chan shared.StatusCode, 1),
+		Closed: true,
 	}
 
 	// Ensure the market can check whether a candle tags a level.
@@ -229,6 +247,7 @@ func TestMarket(t *testing.T) {
 			Low:    float64(4 + idx),
 			Volume: float64(2 + idx),
 			Status: make(chan shared.StatusCode, 1),
+			Closed: true,
 		}
 		mkt.Update(candle)
 	}
@@ -340,3 +359,296 @@ func TestMarket(t *testing.T) {
 	assert.Equal(t, mkt.taggedImbalance.Load(), false)
 	assert.Equal(t, mkt.imbalanceUpdateCounter.Load(), uint32(0))
 }
+
+func TestMarketReactionWindow(t *testing.T) {
+	market := "^GSPC"
+	vwap := shared.VWAP{Value: 100}
+	cfg := &MarketConfig{
+		Market: market,
+		RequestVWAP: func(request shared.VWAPRequest) {
+			request.Response <- &vwap
+		},
+		RequestVWAPData: func(request shared.VWAPDataRequest) {
+			request.Response <- []*shared.VWAP{}
+		},
+		FetchCaughtUpState: func(market string) (bool, error) {
+			return true, nil
+		},
+		Logger: &log.Logger,
+	}
+
+	// Ensure the default reaction window is used if none is configured.
+	mkt, err := NewMarket(cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, mkt.ReactionWindow(), uint32(shared.PriceDataPayloadSize))
+
+	// Ensure a configured reaction window delays the price data request until the window is filled.
+	window := uint32(6)
+	cfg.ReactionWindow = window
+	mkt, err = NewMarket(cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, mkt.ReactionWindow(), window)
+
+	supportPrice := float64(2)
+	mkt.AddLevel(shared.NewLevel(market, supportPrice, float64(8)))
+
+	tagCandle := &shared.Candlestick{
+		Open:   float64(3),
+		Close:  float64(4),
+		High:   float64(5),
+		Low:    float64(1),
+		Volume: float64(1),
+		Status: make(chan shared.StatusCode, 1),
+		Closed: true,
+	}
+	mkt.Update(tagCandle)
+	assert.True(t, mkt.taggedLevels.Load())
+
+	for idx := range window - 1 {
+		assert.False(t, mkt.RequestingPriceData())
+		candle := &shared.Candlestick{
+			Open:   float64(4 + idx),
+			Close:  float64(6 + idx),
+			High:   float64(8 + idx),
+			Low:    float64(4 + idx),
+			Volume: float64(2 + idx),
+			Status: make(chan shared.StatusCode, 1),
+			Closed: true,
+		}
+		mkt.Update(candle)
+	}
+
+	assert.True(t, mkt.RequestingPriceData())
+}
+
+func TestMarketRequireImbalancePurge(t *testing.T) {
+	market := "^GSPC"
+	vwap := shared.VWAP{Value: 100}
+	cfg := &MarketConfig{
+		Market: market,
+		RequestVWAP: func(request shared.VWAPRequest) {
+			request.Response <- &vwap
+		},
+		RequestVWAPData: func(request shared.VWAPDataRequest) {
+			request.Response <- []*shared.VWAP{}
+		},
+		FetchCaughtUpState: func(market string) (bool, error) {
+			return true, nil
+		},
+		RequireImbalancePurge: true,
+		Logger:                &log.Logger,
+	}
+
+	mkt, err := NewMarket(cfg)
+	assert.NoError(t, err)
+
+	imb := shared.NewImbalance(market, shared.FiveMinute, float64(8), float64(7), float64(6),
+		shared.Bullish, float64(0.5), time.Time{})
+	mkt.AddImbalance(imb)
+
+	data := []*shared.Candlestick{
+		{Open: float64(9), Close: float64(8.5), High: float64(9), Low: float64(7.5), Volume: float64(1)},
+		{Open: float64(8.5), Close: float64(9), High: float64(9.5), Low: float64(8), Volume: float64(1)},
+		{Open: float64(9), Close: float64(10), High: float64(10.5), Low: float64(9), Volume: float64(1)},
+		{Open: float64(10), Close: float64(11), High: float64(11.5), Low: float64(10), Volume: float64(1)},
+	}
+
+	// Ensure an unpurged imbalance does not produce a reaction.
+	reactions, err := mkt.GenerateReactionsAtTaggedImbalances(data)
+	assert.NoError(t, err)
+	assert.Equal(t, len(reactions), 0)
+
+	// Ensure an unpurged imbalance is not tagged by market updates.
+	mkt.Update(&shared.Candlestick{
+		Open: float64(9), Close: float64(8.5), High: float64(9), Low: float64(7.5), Volume: float64(1),
+		Status: make(chan shared.StatusCode, 1),
+		Closed: true,
+	})
+	assert.False(t, mkt.taggedImbalance.Load())
+
+	// Ensure a purged imbalance produces a reaction.
+	imb.Purged.Store(true)
+	reactions, err = mkt.GenerateReactionsAtTaggedImbalances(data)
+	assert.NoError(t, err)
+	assert.Equal(t, len(reactions), 1)
+	assert.Equal(t, reactions[0].Imbalance.Midpoint, imb.Midpoint)
+}
+
+func TestMarketStackedImbalances(t *testing.T) {
+	market := "^GSPC"
+	vwap := shared.VWAP{}
+	cfg := &MarketConfig{
+		Market: market,
+		RequestVWAP: func(request shared.VWAPRequest) {
+			request.Response <- &vwap
+		},
+		RequestVWAPData: func(request shared.VWAPDataRequest) {
+			request.Response <- []*shared.VWAP{}
+		},
+		FetchCaughtUpState: func(market string) (bool, error) {
+			return true, nil
+		},
+		Logger: &log.Logger,
+	}
+
+	mkt, err := NewMarket(cfg)
+	assert.NoError(t, err)
+
+	lower := shared.NewImbalance(market, shared.FiveMinute, float64(8), float64(7), float64(6),
+		shared.Bullish, float64(0.5), time.Time{})
+	upper := shared.NewImbalance(market, shared.FiveMinute, float64(8.5), float64(7.75), float64(7),
+		shared.Bullish, float64(0.5), time.Time{})
+	lone := shared.NewImbalance(market, shared.FiveMinute, float64(10), float64(9.5), float64(9),
+		shared.Bullish, float64(0.5), time.Time{})
+	mkt.AddImbalance(lower)
+	mkt.AddImbalance(upper)
+	mkt.AddImbalance(lone)
+
+	data := []*shared.Candlestick{
+		{Open: float64(9), Close: float64(8.5), High: float64(9), Low: float64(7.5), Volume: float64(1)},
+		{Open: float64(8.5), Close: float64(9), High: float64(9.5), Low: float64(8), Volume: float64(1)},
+		{Open: float64(9), Close: float64(10), High: float64(10.5), Low: float64(9), Volume: float64(1)},
+		{Open: float64(10), Close: float64(11), High: float64(11.5), Low: float64(10), Volume: float64(1)},
+	}
+
+	reactions, err := mkt.GenerateReactionsAtTaggedImbalances(data)
+	assert.NoError(t, err)
+	assert.Equal(t, len(reactions), 3)
+
+	// Ensure overlapping tagged imbalances carry their stack count, lone imbalances do not stack.
+	want := map[*shared.Imbalance]uint32{lower: 2, upper: 2, lone: 1}
+	for _, reaction := range reactions {
+		assert.Equal(t, reaction.ImbalanceStack, want[reaction.Imbalance])
+	}
+}
+
+func TestMarketVWAPBands(t *testing.T) {
+	market := "^GSPC"
+	vwap := &shared.VWAP{Value: 10, StdDev: 2}
+
+	tests := []struct {
+		name      string
+		vwapBands bool
+		vwap      *shared.VWAP
+		candle    *shared.Candlestick
+		wantBand  shared.VWAPBand
+		wantTag   bool
+	}{
+		{
+			name:      "vwap line tagged",
+			vwapBands: true,
+			vwap:      vwap,
+			candle:    &shared.Candlestick{Open: 11, High: 12, Low: 9.5, Close: 11},
+			wantBand:  shared.VWAPLine,
+			wantTag:   true,
+		},
+		{
+			name:      "upper band 1 tagged",
+			vwapBands: true,
+			vwap:      vwap,
+			candle:    &shared.Candlestick{Open: 13, High: 13.5, Low: 11.5, Close: 13},
+			wantBand:  shared.UpperBand1,
+			wantTag:   true,
+		},
+		{
+			name:      "lower band 2 tagged",
+			vwapBands: true,
+			vwap:      vwap,
+			candle:    &shared.Candlestick{Open: 5, High: 6.5, Low: 5, Close: 5.5},
+			wantBand:  shared.LowerBand2,
+			wantTag:   true,
+		},
+		{
+			name:      "bands disabled",
+			vwapBands: false,
+			vwap:      vwap,
+			candle:    &shared.Candlestick{Open: 13, High: 13.5, Low: 11.5, Close: 13},
+			wantBand:  shared.VWAPLine,
+			wantTag:   false,
+		},
+		{
+			name:      "no deviation",
+			vwapBands: true,
+			vwap:      &shared.VWAP{Value: 10},
+			candle:    &shared.Candlestick{Open: 13, High: 13.5, Low: 11.5, Close: 13},
+			wantBand:  shared.VWAPLine,
+			wantTag:   false,
+		},
+		{
+			name:      "nothing tagged",
+			vwapBands: true,
+			vwap:      vwap,
+			candle:    &shared.Candlestick{Open: 16, High: 17, Low: 15, Close: 16},
+			wantBand:  shared.VWAPLine,
+			wantTag:   false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mkt, err := NewMarket(&MarketConfig{
+				Market:             market,
+				RequestVWAP:        func(request shared.VWAPRequest) {},
+				RequestVWAPData:    func(request shared.VWAPDataRequest) {},
+				FetchCaughtUpState: func(market string) (bool, error) { return true, nil },
+				VWAPBands:          test.vwapBands,
+				Logger:             &log.Logger,
+			})
+			assert.NoError(t, err)
+
+			// Ensure the expected vwap line or band is tagged.
+			band, tagged := mkt.fetchTaggedVWAPBand(test.vwap, test.candle)
+			assert.Equal(t, band, test.wantBand)
+			assert.Equal(t, tagged, test.wantTag)
+
+			// Ensure the tagged band is tracked by the market.
+			mkt.evaluateTaggedVWAP(test.candle, test.vwap)
+			assert.Equal(t, mkt.taggedVWAP.Load(), test.wantTag)
+			assert.Equal(t, mkt.TaggedVWAPBand(), test.wantBand)
+		})
+	}
+}
+
+func TestMarketLateBreakHandling(t *testing.T) {
+	market := "^GSPC"
+
+	// The support level holds for all but the last close of the window.
+	data := []*shared.Candlestick{
+		{Open: float64(103), Close: float64(102), High: float64(104), Low: float64(99), Volume: float64(1)},
+		{Open: float64(102), Close: float64(103), High: float64(104), Low: float64(101), Volume: float64(1)},
+		{Open: float64(103), Close: float64(101), High: float64(103), Low: float64(100.5), Volume: float64(1)},
+		{Open: float64(101), Close: float64(98), High: float64(101), Low: float64(97), Volume: float64(1)},
+	}
+
+	tests := []struct {
+		name     string
+		handling shared.LateBreakHandling
+		want     shared.PriceReaction
+	}{
+		{"break", shared.LateBreakAsBreak, shared.Break},
+		{"reversal", shared.LateBreakAsReversal, shared.Reversal},
+		{"chop", shared.LateBreakAsChop, shared.Chop},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mkt, err := NewMarket(&MarketConfig{
+				Market:             market,
+				RequestVWAP:        func(request shared.VWAPRequest) {},
+				RequestVWAPData:    func(request shared.VWAPDataRequest) {},
+				FetchCaughtUpState: func(market string) (bool, error) { return true, nil },
+				LateBreakHandling:  test.handling,
+				Logger:             &log.Logger,
+			})
+			assert.NoError(t, err)
+
+			mkt.AddLevel(shared.NewLevel(market, float64(100), float64(102)))
+
+			// Ensure late breaks at tagged levels are classified per the configured handling.
+			reactions, err := mkt.GenerateReactionsAtTaggedLevels(data)
+			assert.NoError(t, err)
+			assert.Equal(t, len(reactions), 1)
+			assert.Equal(t, reactions[0].Reaction, test.want)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package priceaction
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+)
+
+// cachedCandleMetadata represents candle metadata of the provided lookback generated from price
+// data ending with the candle of the provided date.
+type cachedCandleMetadata struct {
+	lookback uint32
+	date     time.Time
+	meta     []*shared.CandleMetadata
+}
+
+// candleMetadataCache caches the candle metadata of a market per timeframe until a new
+// candle of the timeframe arrives.
+type candleMetadataCache struct {
+	latest  map[shared.Timeframe]time.Time
+	entries map[shared.Timeframe]cachedCandleMetadata
+	mtx     sync.Mutex
+}
+
+// newCandleMetadataCache initializes a new candle metadata cache.
+func newCandleMetadataCache() *candleMetadataCache {
+	return &candleMetadataCache{
+		latest:  make(map[shared.Timeframe]time.Time),
+		entries: make(map[shared.Timeframe]cachedCandleMetadata),
+	}
+}
+
+// update records the provided candle as the latest of its timeframe, invalidating cached
+// metadata generated before it.
+func (c *candleMetadataCache) update(candle *shared.Candlestick) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.latest[candle.Timeframe] = candle.Date
+	entry, ok := c.entries[candle.Timeframe]
+	if ok && !entry.date.Equal(candle.Date) {
+		delete(c.entries, candle.Timeframe)
+	}
+}
+
+// fetch returns the cached metadata of the provided timeframe and lookback if it was generated
+// from price data ending with the latest candle.
+func (c *candleMetadataCache) fetch(timeframe shared.Timeframe, lookback uint32) ([]*shared.CandleMetadata, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	entry, ok := c.entries[timeframe]
+	if !ok || entry.lookback != lookback || !entry.date.Equal(c.latest[timeframe]) {
+		return nil, false
+	}
+
+	meta := make([]*shared.CandleMetadata, len(entry.meta))
+	copy(meta, entry.meta)
+
+	return meta, true
+}
+
+// add caches the provided metadata of the provided lookback generated from price data ending
+// with the candle of the provided date.
+func (c *candleMetadataCache) add(timeframe shared.Timeframe, lookback uint32, date time.Time, meta []*shared.CandleMetadata) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.entries[timeframe] = cachedCandleMetadata{
+		lookback: lookback,
+		date:     date,
+		meta:     meta,
+	}
+}
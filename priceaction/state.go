@@ -0,0 +1,88 @@
+package priceaction
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/dnldd/entry/shared"
+)
+
+// MarketState is the serializable price action state of a market.
+type MarketState struct {
+	Levels     []*shared.Level
+	Imbalances []*shared.Imbalance
+	LastPrice  float64
+}
+
+// ManagerState is the serializable price action state of all tracked markets, keyed by market.
+type ManagerState struct {
+	Markets map[string]*MarketState
+}
+
+// state returns the serializable state of the market.
+func (m *Market) state() *MarketState {
+	return &MarketState{
+		Levels:     m.Levels(),
+		Imbalances: m.Imbalances(),
+		LastPrice:  m.lastPrice.Load(),
+	}
+}
+
+// restore replaces the levels and imbalances of the market with the provided state.
+func (m *Market) restore(state *MarketState) {
+	m.levelSnapshot.Remove(func(*shared.Level) bool { return true })
+	m.imbalanceSnapshot.Remove(func(*shared.Imbalance) bool { return true })
+
+	for idx := range state.Levels {
+		m.AddLevel(state.Levels[idx])
+	}
+	for idx := range state.Imbalances {
+		m.AddImbalance(state.Imbalances[idx])
+	}
+
+	m.lastPrice.Store(state.LastPrice)
+}
+
+// SnapshotState serializes the levels and imbalances of all tracked markets to json.
+func (m *Manager) SnapshotState() ([]byte, error) {
+	m.marketsMtx.RLock()
+	state := &ManagerState{Markets: make(map[string]*MarketState, len(m.markets))}
+	for market, mkt := range m.markets {
+		state.Markets[market] = mkt.state()
+	}
+	m.marketsMtx.RUnlock()
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling price action state: %v", err)
+	}
+
+	return data, nil
+}
+
+// RestoreState replaces the levels and imbalances of tracked markets with the provided json
+// state. The state of untracked markets is ignored.
+func (m *Manager) RestoreState(data []byte) error {
+	var state ManagerState
+	err := json.Unmarshal(data, &state)
+	if err != nil {
+		return fmt.Errorf("unmarshaling price action state: %v", err)
+	}
+
+	m.marketsMtx.RLock()
+	defer m.marketsMtx.RUnlock()
+
+	for market, mktState := range state.Markets {
+		mkt, ok := m.markets[market]
+		if !ok {
+			m.cfg.Logger.Info().Msgf("skipping price action state of untracked market %s", market)
+			continue
+		}
+
+		mkt.restore(mktState)
+		m.cfg.Logger.Info().Msgf("restored %d levels and %d imbalances for %s",
+			len(mktState.Levels), len(mktState.Imbalances), market)
+	}
+
+	return nil
+}
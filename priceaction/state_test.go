@@ -0,0 +1,76 @@
+package priceaction
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestManagerStateRoundTrip(t *testing.T) {
+	market := "^GSPC"
+	mgr := setupManager(t, market)
+
+	mkt, ok := mgr.fetchMarket(market)
+	assert.True(t, ok)
+	mkt.lastPrice.Store(100)
+
+	level := shared.NewLevel(market, 95, 100)
+	level.Reversals.Store(2)
+	level.Breaking.Store(true)
+	level.Source = shared.LondonLevel
+	manualLevel := shared.NewManualLevel(market, 110, shared.Resistance)
+	mkt.AddLevel(level)
+	mkt.AddLevel(manualLevel)
+
+	date := time.Date(2025, 3, 4, 14, 30, 0, 0, time.UTC)
+	imb := shared.NewImbalance(market, shared.FiveMinute, 104, 98, 92, shared.Bullish, 0.7, date)
+	imb.Fill.Store(35)
+	imb.OrderBlock = &shared.OrderBlockZone{Market: market, Timeframe: shared.FiveMinute,
+		High: 92, Low: 90, Sentiment: shared.Bullish, Date: date}
+	mkt.AddImbalance(imb)
+
+	data, err := mgr.SnapshotState()
+	assert.NoError(t, err)
+
+	// Ensure the state restores into a fresh manager.
+	restored := setupManager(t, market)
+	err = restored.RestoreState(data)
+	assert.NoError(t, err)
+
+	restoredMkt, ok := restored.fetchMarket(market)
+	assert.True(t, ok)
+	assert.Equal(t, restoredMkt.lastPrice.Load(), float64(100))
+
+	// Ensure levels are restored in order with their counters and flags.
+	levels := restoredMkt.Levels()
+	assert.Equal(t, len(levels), 2)
+	assert.Equal(t, levels[0].Price, level.Price)
+	assert.Equal(t, levels[0].Kind, level.Kind)
+	assert.Equal(t, levels[0].Reversals.Load(), uint32(2))
+	assert.True(t, levels[0].Breaking.Load())
+	assert.Equal(t, levels[0].Source, shared.LondonLevel)
+	assert.Equal(t, levels[1].Price, manualLevel.Price)
+	assert.True(t, levels[1].Manual)
+
+	// Ensure imbalances are restored with their fill and order block.
+	imbalances := restoredMkt.Imbalances()
+	assert.Equal(t, len(imbalances), 1)
+	assert.Equal(t, imbalances[0].High, imb.High)
+	assert.Equal(t, imbalances[0].Low, imb.Low)
+	assert.Equal(t, imbalances[0].Fill.Load(), float64(35))
+	assert.True(t, imbalances[0].Date.Equal(date))
+	assert.NotEqual(t, imbalances[0].OrderBlock, nil)
+	assert.Equal(t, imbalances[0].OrderBlock.High, float64(92))
+
+	// Ensure restoring replaces existing state and ignores untracked markets.
+	err = restored.RestoreState([]byte(`{"Markets":{"^GSPC":{"Levels":[]},"^AAPL":{}}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, len(restoredMkt.Levels()), 0)
+	assert.Equal(t, len(restoredMkt.Imbalances()), 0)
+
+	// Ensure malformed state errors.
+	err = restored.RestoreState([]byte("{"))
+	assert.Error(t, err)
+}
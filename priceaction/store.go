@@ -0,0 +1,68 @@
+package priceaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/dnldd/entry/shared"
+)
+
+// Store defines the requirements for storing the levels and imbalances of markets.
+type Store interface {
+	// PersistState replaces the stored levels and imbalances of the provided market.
+	PersistState(market string, levels []*shared.Level, imbalances []*shared.Imbalance) error
+	// LoadState returns the stored levels and imbalances of the provided market, ordered from
+	// the oldest to the newest. Markets without stored state return none.
+	LoadState(market string) ([]*shared.Level, []*shared.Imbalance, error)
+}
+
+// MemoryStore stores the levels and imbalances of markets in memory.
+type MemoryStore struct {
+	states map[string][]byte
+	mtx    sync.RWMutex
+}
+
+// Ensure the memory store implements the Store interface.
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore initializes a new in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		states: make(map[string][]byte),
+	}
+}
+
+// PersistState replaces the stored levels and imbalances of the provided market. The state is
+// stored serialized, later changes to the provided levels and imbalances are not reflected.
+func (s *MemoryStore) PersistState(market string, levels []*shared.Level, imbalances []*shared.Imbalance) error {
+	data, err := json.Marshal(&MarketState{Levels: levels, Imbalances: imbalances})
+	if err != nil {
+		return fmt.Errorf("marshaling %s state: %v", market, err)
+	}
+
+	s.mtx.Lock()
+	s.states[market] = data
+	s.mtx.Unlock()
+
+	return nil
+}
+
+// LoadState returns the stored levels and imbalances of the provided market, ordered from the
+// oldest to the newest.
+func (s *MemoryStore) LoadState(market string) ([]*shared.Level, []*shared.Imbalance, error) {
+	s.mtx.RLock()
+	data, ok := s.states[market]
+	s.mtx.RUnlock()
+	if !ok {
+		return nil, nil, nil
+	}
+
+	var state MarketState
+	err := json.Unmarshal(data, &state)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unmarshaling %s state: %v", market, err)
+	}
+
+	return state.Levels, state.Imbalances, nil
+}
@@ -0,0 +1,77 @@
+package priceaction
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dnldd/entry/shared"
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	market := "^GSPC"
+	store := NewMemoryStore()
+
+	// Ensure markets without stored state load none.
+	levels, imbalances, err := store.LoadState(market)
+	assert.NoError(t, err)
+	assert.Equal(t, len(levels), 0)
+	assert.Equal(t, len(imbalances), 0)
+
+	mgr := setupManager(t, market)
+	mgr.cfg.Store = store
+	mgr.store = store
+
+	// Populate the market with levels and imbalances, persisting them as they are added.
+	for _, signal := range []shared.LevelSignal{
+		{Market: market, Price: 10, Close: 12, Status: make(chan shared.StatusCode, 1)},
+		{Market: market, Price: 30, Close: 25, Status: make(chan shared.StatusCode, 1)},
+	} {
+		err := mgr.handleLevelSignal(signal)
+		assert.NoError(t, err)
+	}
+
+	date := time.Date(2025, 5, 7, 10, 30, 0, 0, time.UTC)
+	err = mgr.handleImbalanceSignal(shared.ImbalanceSignal{
+		Market: market,
+		Imbalance: *shared.NewImbalance(market, shared.FiveMinute, float64(15), float64(10),
+			float64(5), shared.Bullish, float64(0.5), date),
+		Status: make(chan shared.StatusCode, 1),
+	})
+	assert.NoError(t, err)
+
+	mkt, ok := mgr.fetchMarket(market)
+	assert.True(t, ok)
+	mkt.Levels()[1].ApplyPriceReaction(shared.Reversal)
+	mkt.Imbalances()[0].Purged.Store(true)
+	err = mgr.persistState(market, mkt)
+	assert.NoError(t, err)
+
+	// Ensure changes after persisting are not reflected in the stored state.
+	mkt.Levels()[0].ApplyPriceReaction(shared.Reversal)
+
+	// Ensure a fresh manager reloads the persisted levels and imbalances.
+	cfg := *mgr.cfg
+	restarted, err := NewManager(&cfg)
+	assert.NoError(t, err)
+
+	restored, ok := restarted.fetchMarket(market)
+	assert.True(t, ok)
+
+	levels = restored.Levels()
+	assert.Equal(t, len(levels), 2)
+	assert.Equal(t, levels[0].Price, float64(10))
+	assert.Equal(t, levels[0].Kind, shared.Support)
+	assert.Equal(t, levels[0].Reversals.Load(), uint32(0))
+	assert.Equal(t, levels[1].Price, float64(30))
+	assert.Equal(t, levels[1].Kind, shared.Resistance)
+	assert.Equal(t, levels[1].Reversals.Load(), uint32(1))
+
+	imbalances = restored.Imbalances()
+	assert.Equal(t, len(imbalances), 1)
+	assert.Equal(t, imbalances[0].High, float64(15))
+	assert.Equal(t, imbalances[0].Low, float64(5))
+	assert.Equal(t, imbalances[0].Sentiment, shared.Bullish)
+	assert.True(t, imbalances[0].Date.Equal(date))
+	assert.True(t, imbalances[0].Purged.Load())
+}
@@ -2,13 +2,19 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/dnldd/entry/database"
 	"github.com/dnldd/entry/engine"
+	"github.com/dnldd/entry/export"
 	"github.com/dnldd/entry/fetch"
+	"github.com/dnldd/entry/indicator"
 	"github.com/dnldd/entry/market"
 	"github.com/dnldd/entry/position"
 	"github.com/dnldd/entry/priceaction"
@@ -19,16 +25,260 @@ import (
 	"github.com/rs/zerolog/pkgerrors"
 )
 
+const (
+	// defaultDropReportInterval is the default interval dropped signals are reported at.
+	defaultDropReportInterval = time.Minute
+)
+
 // EntryConfig represents the configuration struct for the entry service.
 type EntryConfig struct {
 	// Markets represents the tracked markets.
 	Markets []string
 	// FMPAPIkey is the FMP service API Key.
 	FMPAPIKey string
-	// Backtest is the backtesting flag.
-	Backtest bool
+	// Mode is the execution mode of the service.
+	Mode Mode
+	// Notify relays position notifications to the notification integration in live mode.
+	// Notifications are logged if nil.
+	Notify func(message string)
 	// BacktestDataFilepath is the filepath to the backtest data.
 	BacktestDataFilepath string
+	// SortBacktestData is the flag for sorting backtest data with out of order candles
+	// instead of rejecting it.
+	SortBacktestData bool
+	// VWAPTypicalPrice is the typical price formula used for vwap calculations.
+	VWAPTypicalPrice indicator.TypicalPrice
+	// VWAPRollingWindow is the number of candles covered by a rolling vwap. A zero window
+	// anchors the vwap to the session.
+	VWAPRollingWindow int
+	// VWAPAnchors is when the vwaps of markets that are not rolling are reset, keyed by
+	// market. Markets without an anchor are reset daily.
+	VWAPAnchors map[string]indicator.VWAPAnchor
+	// MovingAverageKind is the formula used for moving average calculations.
+	MovingAverageKind indicator.MovingAverageKind
+	// MovingAveragePeriod is the number of candles covered by the moving average. The default
+	// period is used if zero.
+	MovingAveragePeriod int
+	// ReplayMode is how backtest candles are paced when replayed.
+	ReplayMode shared.ReplayMode
+	// ReplayDelay is the fixed delay between backtest candles for delayed replays.
+	ReplayDelay time.Duration
+	// ReplaySpeed is the multiple of wall-clock session timing scaled replays run at.
+	ReplaySpeed float64
+	// AggregateCandles is the flag for building higher timeframe candles from one-minute
+	// candles instead of fetching them.
+	AggregateCandles bool
+	// VolumeProfileBinSize is the price range covered by a session volume profile bin. The
+	// default bin size is used if zero.
+	VolumeProfileBinSize float64
+	// Sessions are the daily sessions tracked, the last session closes the trading day. The
+	// default sessions are used if empty.
+	Sessions []shared.SessionDefinition
+	// EqualLevelTolerance is the percentage of price equal highs and lows can differ by to
+	// be signalled as liquidity levels. Equal highs and lows are not detected if zero.
+	EqualLevelTolerance float64
+	// ImbalanceCriteria is the criteria for detecting imbalances. The default criteria is
+	// used if nil.
+	ImbalanceCriteria *shared.ImbalanceCriteria
+	// Classification is the config candles are classified by. The default config is used if
+	// nil.
+	Classification *shared.ClassificationConfig
+	// WarmupCandles is the number of candles of a timeframe a market needs before reactions
+	// are evaluated. The default is used if zero.
+	WarmupCandles uint32
+	// StatusTimeout is the maximum time markets wait on the status of a relayed update or
+	// signal. The default timeout is used if zero.
+	StatusTimeout time.Duration
+	// StatusTimeoutPolicy is how markets handle status timeouts.
+	StatusTimeoutPolicy market.StatusTimeoutPolicy
+	// ReactionWindow is the number of candles price reactions are evaluated over. The default
+	// window is used if zero.
+	ReactionWindow uint32
+	// RequireFullWindow is the flag for only evaluating reactions once the full reaction
+	// window has formed.
+	RequireFullWindow bool
+	// MinReactionCandles is the minimum number of candles price reactions are evaluated with,
+	// reactions with fewer candles are deferred. The classification minimum is used if zero.
+	MinReactionCandles uint32
+	// Timeouts represents the per-operation timeouts of cross-manager requests. The default
+	// timeouts are used if nil.
+	Timeouts *shared.Timeouts
+	// ReactionDebounce is the price distance within which reactions to different focus types
+	// on the same candle are collapsed into one.
+	ReactionDebounce float64
+	// StaleDistance is the distance from the current price, as a percentage of it, beyond
+	// which tracked levels and imbalances are swept as stale. Distant entries are kept if zero.
+	StaleDistance float64
+	// StaleCleanupInterval is the interval stale levels and imbalances are swept at. The
+	// default interval is used if zero.
+	StaleCleanupInterval time.Duration
+	// VWAPBands is the flag for evaluating reactions at the standard deviation bands of the
+	// vwap along with the vwap line.
+	VWAPBands bool
+	// LateBreakHandling is how breaks only confirmed by the last close of the reaction window
+	// are classified.
+	LateBreakHandling shared.LateBreakHandling
+	// ReactionEvaluation is when market updates are evaluated for reactions.
+	ReactionEvaluation priceaction.ReactionEvaluation
+	// VWAPMismatchTolerance is the number of entries vwap and price data of a vwap reaction
+	// can differ in length by.
+	VWAPMismatchTolerance uint32
+	// RequireImbalancePurge is the flag for only reacting to imbalances after price has
+	// purged them.
+	RequireImbalancePurge bool
+	// PriceActionMarketWorkers is the number of signals of a market the price action manager
+	// handles concurrently. The default is used if zero.
+	PriceActionMarketWorkers int
+	// PriceActionRequestWorkers is the number of requests the price action manager handles
+	// concurrently across all markets. The default is used if zero.
+	PriceActionRequestWorkers int
+	// DrainGracePeriod is the maximum time the engine, market, price action and position
+	// managers spend handling buffered and in-flight signals on shutdown. The default grace
+	// period is used if zero.
+	DrainGracePeriod time.Duration
+	// DropReportInterval is the interval signals dropped by channels at capacity are
+	// reported at. The default interval is used if zero.
+	DropReportInterval time.Duration
+	// HealthAddress is the address the /healthz liveness and /readyz readiness probes are
+	// served on. The probes are not served if empty.
+	HealthAddress string
+	// PositionsDBFilepath is the filepath to the sqlite database for closed positions. Closed
+	// positions are not persisted if empty.
+	PositionsDBFilepath string
+	// StateDBFilepath is the filepath to the sqlite database for level and imbalance state.
+	// The state is reloaded on startup, it is not persisted if empty.
+	StateDBFilepath string
+	// ExportFilepath is the filepath to the json export of levels, imbalances, vwaps and
+	// reactions for charting. Chart data is not exported if empty.
+	ExportFilepath string
+	// AuditLogFilepath is the filepath to the json lines audit log of the confluence
+	// breakdown and outcome of every evaluated reaction. Reactions are not audited if empty.
+	AuditLogFilepath string
+	// TradesCSVFilepath is the filepath to the csv export of every backtest and paper trade.
+	// Trades are not exported if empty.
+	TradesCSVFilepath string
+	// Thresholds represents the confluence thresholds of the engine. The default thresholds
+	// are used if not provided.
+	Thresholds *engine.Thresholds
+	// ExitThresholds represents the confluence thresholds of the engine for exits. Exits use
+	// the entry thresholds if not provided.
+	ExitThresholds *engine.ExitThresholds
+	// NeutralSkewMode is how the engine takes entries for markets with neutral skew.
+	NeutralSkewMode engine.NeutralSkewMode
+	// TrendBias is how the engine biases reversals by the moving average trend.
+	TrendBias engine.TrendBiasMode
+	// ExitPolicy is how the engine exits open positions on opposing reactions.
+	ExitPolicy engine.ExitPolicy
+	// CapacityPolicy is how reactions are relayed when the engine's reaction signals are
+	// at capacity.
+	CapacityPolicy engine.CapacityPolicy
+	// VolumeNormalization is how the engine measures reaction volume against the average
+	// volume threshold.
+	VolumeNormalization engine.VolumeNormalization
+	// VolumeFloors is the minimum average volume of markets, keyed by market. The engine does
+	// not signal reactions of markets below their floor, markets without one are not checked.
+	VolumeFloors map[string]float64
+	// CapacityTimeout is the maximum time spent waiting on engine capacity under the block
+	// on capacity policy. The default timeout is used if zero.
+	CapacityTimeout time.Duration
+	// ConfidenceWeights represents the weighting of the factors combined into signal
+	// confidence. The default weights are used if not provided.
+	ConfidenceWeights *engine.ConfidenceWeights
+	// NewsBlackout represents the scheduled news releases the engine suppresses entries
+	// around. Entries are not suppressed if nil.
+	NewsBlackout *engine.NewsBlackout
+	// RegimeFilter represents the criteria the engine suppresses entries in ranging markets
+	// by. Entries are not suppressed by market regime if nil.
+	RegimeFilter *engine.RegimeFilter
+	// TrailingStop represents the trailing stop configuration. Stops are not trailed if nil.
+	TrailingStop *position.TrailingStopConfig
+	// Slippage represents the spread and slippage assumed on simulated fills. It is ignored
+	// in live mode, fills are taken at the signal price if nil.
+	Slippage *position.SlippageConfig
+	// BracketRewardRatio is the multiple of the points risked to the stop loss the target of
+	// bracketed entries is placed at. Entries are not bracketed if zero.
+	BracketRewardRatio float64
+	// StructureSkew is the flag for biasing entries of neutral skewed markets towards the
+	// direction of confirmed level breaks against the prevailing trend.
+	StructureSkew bool
+	// TightestStop is the flag for placing stops at the tighter of the signal candle and
+	// reaction window stops that still sits beyond the reacted level.
+	TightestStop bool
+	// WickStopBuffer is the multiple of the average wick length of the reaction's candles
+	// the engine buffers stops by. The fixed points buffer is used if zero.
+	WickStopBuffer float64
+	// StopBuffers is the distance the engine places stops beyond the reaction's candles,
+	// keyed by market. Markets without a stop buffer use the default points buffer.
+	StopBuffers map[string]engine.StopBuffer
+	// StopOutBump is the confluence the engine raises the entry threshold of the next reversal
+	// at a level by after a position entered at it is stopped out. Thresholds are not raised
+	// if zero.
+	StopOutBump uint32
+	// StopOutWindow is the time after a stop out the next reversal at its level is held to the
+	// raised entry threshold. The default window is used if zero.
+	StopOutWindow time.Duration
+	// VWAPStretch is the number of standard deviations price must be stretched from vwap by
+	// for the engine to award reversals at vwap mean reversion confluence. The default
+	// stretch is used if zero.
+	VWAPStretch float64
+	// LevelVWAPGap is the price distance within which the engine treats a level and vwap
+	// reacted to on the same candle as one focus. Levels and vwap are not checked for overlap
+	// if zero.
+	LevelVWAPGap float64
+	// AssetClasses is the asset class of markets, keyed by market. It determines the sessions
+	// and high volume window of the market, markets without an asset class are equity indices.
+	AssetClasses map[string]shared.AssetClass
+	// DirectionModes is the direction mode of markets, keyed by market. Markets without a
+	// direction mode take entries in both directions.
+	DirectionModes map[string]engine.DirectionMode
+	// RTHWindows are the regular trading hours of markets, keyed by market. The engine
+	// suppresses entries outside them, markets without regular trading hours are not restricted.
+	RTHWindows map[string]shared.SessionWindow
+	// ReasonPriority is the priority reasons of entry signals and audit records are ordered
+	// by. The default priority is used if nil.
+	ReasonPriority shared.ReasonPriority
+	// HighVolumeWindows are the daily windows the engine awards high volume session
+	// confluence in for all markets. The asset class windows are used if not provided.
+	HighVolumeWindows []shared.SessionWindow
+	// MinDistinctReasons is the minimum number of distinct reasons entry signals must be
+	// backed by. Signals are not checked for breadth if zero.
+	MinDistinctReasons int
+	// BreakConfirmationCloses is the number of trailing closes beyond the broken focus the
+	// engine requires to confirm breaks. Only the last close is required if zero.
+	BreakConfirmationCloses int
+	// Instruments is the contract specification of markets, keyed by market. Stops are
+	// rounded and widened, and positions sized and valued with it. Markets without an
+	// instrument are priced one-to-one in dollars.
+	Instruments map[string]shared.Instrument
+	// StopRange represents the bounds of the points range of entry stops. Stops are not
+	// bounded if nil.
+	StopRange *engine.StopRange
+	// EnabledReactions is the set of reaction kinds the engine takes entries on. Entries are
+	// taken on all reaction kinds if nil.
+	EnabledReactions map[engine.ReactionKind]struct{}
+	// LevelWeights is the confluence awarded to reversals at levels, keyed by the session the
+	// level was derived from. The default weights are used if nil.
+	LevelWeights map[shared.LevelSource]uint32
+	// Sizing represents the position sizing configuration. Positions are not sized if nil.
+	Sizing *position.SizingConfig
+	// NotifyThrottle represents how position notifications are throttled. Notifications are
+	// delivered immediately if nil.
+	NotifyThrottle *position.ThrottleConfig
+	// MaxOpenPositions is the maximum number of positions open across all markets. Open
+	// positions are not limited if zero.
+	MaxOpenPositions int
+	// Correlation represents the correlation groups same direction exposure is limited
+	// within. Exposure is not limited by correlation if nil.
+	Correlation *position.CorrelationConfig
+	// ReactionFilter is the minimum quality bar for relaying reactions to the engine. All
+	// reactions are relayed if nil.
+	ReactionFilter *priceaction.ReactionFilter
+	// ErrorSink receives the processing errors of the engine and price action manager in
+	// addition to them being logged. Errors are only logged if nil.
+	ErrorSink chan error
+	// Clock is the source of the current time for live and paper trading. The wall clock is
+	// used if nil, backtests are always driven by the replay clock of the historic data.
+	Clock shared.Clock
 	// Cancel is the context cancellation function.
 	Cancel context.CancelFunc
 }
@@ -40,13 +290,181 @@ func (cfg *EntryConfig) Validate() error {
 	if cfg.Cancel == nil {
 		errs = errors.Join(errs, fmt.Errorf("context cancellation function cannot be nil"))
 	}
+	if cfg.PriceActionMarketWorkers < 0 {
+		errs = errors.Join(errs, fmt.Errorf("price action market workers must be positive"))
+	}
+	if cfg.PriceActionRequestWorkers < 0 {
+		errs = errors.Join(errs, fmt.Errorf("price action request workers must be positive"))
+	}
+	if cfg.DrainGracePeriod < 0 {
+		errs = errors.Join(errs, fmt.Errorf("drain grace period cannot be negative"))
+	}
+	if cfg.StaleDistance < 0 {
+		errs = errors.Join(errs, fmt.Errorf("stale distance cannot be negative"))
+	}
+	if cfg.StaleCleanupInterval < 0 {
+		errs = errors.Join(errs, fmt.Errorf("stale cleanup interval cannot be negative"))
+	}
+	if cfg.DropReportInterval < 0 {
+		errs = errors.Join(errs, fmt.Errorf("drop report interval cannot be negative"))
+	}
+	if cfg.CapacityTimeout < 0 {
+		errs = errors.Join(errs, fmt.Errorf("capacity timeout cannot be negative"))
+	}
+	for market, floor := range cfg.VolumeFloors {
+		if floor < 0 {
+			errs = errors.Join(errs, fmt.Errorf("%s volume floor cannot be negative", market))
+		}
+	}
+	if cfg.Thresholds != nil {
+		err := cfg.Thresholds.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating thresholds: %v", err))
+		}
+	}
+	if cfg.ConfidenceWeights != nil {
+		err := cfg.ConfidenceWeights.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating confidence weights: %v", err))
+		}
+	}
+	if cfg.NewsBlackout != nil {
+		err := cfg.NewsBlackout.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating news blackout: %v", err))
+		}
+	}
+	if cfg.Timeouts != nil {
+		err := cfg.Timeouts.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating timeouts: %v", err))
+		}
+	}
+	if cfg.ReasonPriority != nil {
+		err := cfg.ReasonPriority.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating reason priority: %v", err))
+		}
+	}
+	for market, window := range cfg.RTHWindows {
+		err := window.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating %s regular trading hours: %v", market, err))
+		}
+	}
+	if cfg.RegimeFilter != nil {
+		err := cfg.RegimeFilter.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating regime filter: %v", err))
+		}
+	}
+	if cfg.ImbalanceCriteria != nil {
+		err := cfg.ImbalanceCriteria.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating imbalance criteria: %v", err))
+		}
+	}
+	if cfg.Classification != nil {
+		err := cfg.Classification.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating classification config: %v", err))
+		}
+	}
+	if cfg.TrailingStop != nil {
+		err := cfg.TrailingStop.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating trailing stop config: %v", err))
+		}
+	}
+	if cfg.Slippage != nil {
+		err := cfg.Slippage.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating slippage config: %v", err))
+		}
+	}
+	if cfg.BracketRewardRatio < 0 {
+		errs = errors.Join(errs, fmt.Errorf("bracket reward ratio cannot be negative"))
+	}
+	if cfg.WickStopBuffer < 0 {
+		errs = errors.Join(errs, fmt.Errorf("wick stop buffer cannot be negative"))
+	}
+	if cfg.StopOutWindow < 0 {
+		errs = errors.Join(errs, fmt.Errorf("stop out window cannot be negative"))
+	}
+	for market, buffer := range cfg.StopBuffers {
+		err := buffer.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating %s stop buffer: %v", market, err))
+		}
+	}
+	if cfg.VWAPStretch < 0 {
+		errs = errors.Join(errs, fmt.Errorf("vwap stretch cannot be negative"))
+	}
+	if cfg.LevelVWAPGap < 0 {
+		errs = errors.Join(errs, fmt.Errorf("level vwap gap cannot be negative"))
+	}
+	for idx := range cfg.HighVolumeWindows {
+		err := cfg.HighVolumeWindows[idx].Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating high volume window: %v", err))
+		}
+	}
+	err := shared.ValidateSessionDefinitions(cfg.Sessions)
+	if err != nil {
+		errs = errors.Join(errs, fmt.Errorf("validating sessions: %v", err))
+	}
+	if cfg.MinDistinctReasons < 0 {
+		errs = errors.Join(errs, fmt.Errorf("minimum distinct reasons cannot be negative"))
+	}
+	if cfg.BreakConfirmationCloses < 0 {
+		errs = errors.Join(errs, fmt.Errorf("break confirmation closes cannot be negative"))
+	}
+	for market, instrument := range cfg.Instruments {
+		err := instrument.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating %s instrument: %v", market, err))
+		}
+	}
+	if cfg.StopRange != nil {
+		err := cfg.StopRange.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating stop range: %v", err))
+		}
+	}
+	if cfg.MaxOpenPositions < 0 {
+		errs = errors.Join(errs, fmt.Errorf("maximum open positions cannot be negative"))
+	}
+	if cfg.Correlation != nil {
+		err := cfg.Correlation.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating correlation config: %v", err))
+		}
+	}
+	if cfg.Sizing != nil {
+		err := cfg.Sizing.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating sizing config: %v", err))
+		}
+	}
+	if cfg.NotifyThrottle != nil {
+		err := cfg.NotifyThrottle.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating notify throttle config: %v", err))
+		}
+	}
+	if cfg.ReactionFilter != nil {
+		err := cfg.ReactionFilter.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating reaction filter: %v", err))
+		}
+	}
 
-	switch cfg.Backtest {
-	case true:
+	switch cfg.Mode {
+	case Backtest:
 		if cfg.BacktestDataFilepath == "" {
 			errs = errors.Join(errs, fmt.Errorf("backtest data filepath cannot be an empty string"))
 		}
-	case false:
+	case Live, Paper:
 		if len(cfg.Markets) == 0 {
 			errs = errors.Join(errs, fmt.Errorf("no markets provided for entry service"))
 		}
@@ -58,6 +476,44 @@ func (cfg *EntryConfig) Validate() error {
 	return errs
 }
 
+// ReloadConfig represents the reloadable configuration of the entry service.
+type ReloadConfig struct {
+	// Markets represents the tracked markets.
+	Markets []string
+	// Thresholds represents the confluence thresholds of the engine. The current thresholds
+	// are kept if not provided.
+	Thresholds *engine.Thresholds
+	// ConfidenceWeights represents the weighting of the factors combined into signal
+	// confidence. The current weights are kept if not provided.
+	ConfidenceWeights *engine.ConfidenceWeights
+	// LevelWeights is the confluence awarded to reversals at levels, keyed by the session the
+	// level was derived from. The current weights are kept if nil.
+	LevelWeights map[shared.LevelSource]uint32
+}
+
+// Validate asserts the config sane inputs.
+func (cfg *ReloadConfig) Validate() error {
+	var errs error
+
+	if len(cfg.Markets) == 0 {
+		errs = errors.Join(errs, fmt.Errorf("no markets provided for reload"))
+	}
+	if cfg.Thresholds != nil {
+		err := cfg.Thresholds.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating thresholds: %v", err))
+		}
+	}
+	if cfg.ConfidenceWeights != nil {
+		err := cfg.ConfidenceWeights.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating confidence weights: %v", err))
+		}
+	}
+
+	return errs
+}
+
 // Entry represents a market entry finding service.
 type Entry struct {
 	cfg                *EntryConfig
@@ -67,6 +523,15 @@ type Entry struct {
 	priceActionManager *priceaction.Manager
 	historicData       *shared.HistoricData
 	entryEngine        *engine.Engine
+	positionsDB        *database.SQLite
+	stateDB            *database.SQLite
+	exporter           *export.Exporter
+	audit              *engine.AuditWriter
+	reporter           *position.BacktestReporter
+	tradeWriter        *position.TradeWriter
+	markets            []string
+	clock              shared.Clock
+	reloadMtx          sync.Mutex
 	logger             *zerolog.Logger
 	wg                 sync.WaitGroup
 }
@@ -84,6 +549,10 @@ func NewEntry(cfg *EntryConfig) (*Entry, error) {
 	var priceActionMgr *priceaction.Manager
 	var historicData *shared.HistoricData
 	var entryEngine *engine.Engine
+	var positionsDB *database.SQLite
+	var stateDB *database.SQLite
+	var exporter *export.Exporter
+	var audit *engine.AuditWriter
 
 	zerolog.ErrorStackMarshaler = pkgerrors.MarshalStack
 
@@ -103,12 +572,18 @@ func NewEntry(cfg *EntryConfig) (*Entry, error) {
 		return nil
 	}
 
-	now, loc, err := shared.NewYorkTime()
+	clock := cfg.Clock
+	if clock == nil {
+		clock = shared.WallClock{}
+	}
+
+	now, loc, err := shared.NewYorkTimeFrom(clock)
 	if err != nil {
 		return nil, fmt.Errorf("fetching new york time: %v", err)
 	}
 
-	if cfg.Backtest {
+	var replayClock *shared.ReplayClock
+	if !cfg.Mode.usesLiveData() {
 		// Ensure the service starts at the time denoted by the historical data
 		// supplied for backtests.
 		historicDataLogger := logger.With().Str("component", "historicdata").Logger()
@@ -116,6 +591,11 @@ func NewEntry(cfg *EntryConfig) (*Entry, error) {
 			FilePath:          cfg.BacktestDataFilepath,
 			SignalCaughtUp:    caughtUpFunc,
 			NotifySubscribers: notifySubcribersFunc,
+			ReplayMode:        cfg.ReplayMode,
+			ReplayDelay:       cfg.ReplayDelay,
+			ReplaySpeed:       cfg.ReplaySpeed,
+			SortUnordered:     cfg.SortBacktestData,
+			Sessions:          cfg.Sessions,
 			Logger:            &historicDataLogger,
 		})
 		if err != nil {
@@ -123,10 +603,23 @@ func NewEntry(cfg *EntryConfig) (*Entry, error) {
 		}
 
 		now = historicData.FetchStartTime()
+		replayClock = historicData.FetchClock()
+		clock = replayClock
 	}
 
 	jobScheduler := gocron.NewScheduler(loc)
 
+	if cfg.ExportFilepath != "" {
+		exporterLogger := logger.With().Str("component", "exporter").Logger()
+		exporter, err = export.NewExporter(&export.ExporterConfig{
+			Filepath: cfg.ExportFilepath,
+			Logger:   &exporterLogger,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating exporter: %v", err)
+		}
+	}
+
 	fmp, err := fetch.NewFMPClient(&fetch.FMPConfig{APIKey: cfg.FMPAPIKey, BaseURL: fetch.BaseURL})
 	if err != nil {
 		return nil, fmt.Errorf("creating fmp client: %v", err)
@@ -137,7 +630,10 @@ func NewEntry(cfg *EntryConfig) (*Entry, error) {
 		Markets:        cfg.Markets,
 		ExchangeClient: fmp,
 		SignalCaughtUp: caughtUpFunc,
+		AssetClasses:   cfg.AssetClasses,
+		Sessions:       cfg.Sessions,
 		JobScheduler:   jobScheduler,
+		Clock:          clock,
 		Logger:         &fetchMgrLogger,
 	})
 	if err != nil {
@@ -145,73 +641,228 @@ func NewEntry(cfg *EntryConfig) (*Entry, error) {
 	}
 
 	signalLevelFunc := func(signal shared.LevelSignal) {
+		if exporter != nil {
+			exporter.RecordLevel(signal)
+		}
 		if priceActionMgr != nil {
 			priceActionMgr.SendLevelSignal(signal)
 		}
 	}
 
 	signalImbalanceFunc := func(signal shared.ImbalanceSignal) {
-		if signalLevelFunc != nil {
+		if exporter != nil {
+			exporter.RecordImbalance(&signal.Imbalance)
+		}
+		if priceActionMgr != nil {
 			priceActionMgr.SendImbalanceSignal(signal)
 		}
 	}
 
+	signalOrderBlockFunc := func(signal shared.OrderBlockSignal) {
+		if priceActionMgr != nil {
+			priceActionMgr.SendOrderBlockSignal(signal)
+		}
+	}
+
+	recordVWAPFunc := func(market string, timeframe shared.Timeframe, vwap *shared.VWAP) {
+		if exporter != nil {
+			exporter.RecordVWAP(market, timeframe, vwap)
+		}
+	}
+
 	relayMarketUpdateFunc := func(candle shared.Candlestick) {
 		if priceActionMgr != nil {
 			priceActionMgr.SendMarketUpdate(candle)
 		}
+
+		// The market only awaits the price action manager's status, positions are updated
+		// with a copy of the candle.
+		if positionMgr != nil {
+			positionCandle := candle
+			positionCandle.Status = make(chan shared.StatusCode, 1)
+			positionMgr.SendMarketUpdate(positionCandle)
+		}
 	}
 
 	marketMgrLogger := logger.With().Str("component", "marketmanager").Logger()
 	marketMgr, err = market.NewManager(&market.ManagerConfig{
-		Markets:           cfg.Markets,
-		Timeframes:        []shared.Timeframe{shared.FiveMinute, shared.OneHour},
-		Backtest:          cfg.Backtest,
-		Subscribe:         fetchMgr.Subscribe,
-		RelayMarketUpdate: relayMarketUpdateFunc,
-		CatchUp:           fetchMgr.SendCatchUpSignal,
-		SignalLevel:       signalLevelFunc,
-		SignalImbalance:   signalImbalanceFunc,
+		Markets:              cfg.Markets,
+		Timeframes:           []shared.Timeframe{shared.FiveMinute, shared.OneHour},
+		VWAPTypicalPrice:     cfg.VWAPTypicalPrice,
+		VWAPRollingWindow:    cfg.VWAPRollingWindow,
+		VWAPAnchors:          cfg.VWAPAnchors,
+		MovingAverageKind:    cfg.MovingAverageKind,
+		MovingAveragePeriod:  cfg.MovingAveragePeriod,
+		AggregateCandles:     cfg.AggregateCandles,
+		VolumeProfileBinSize: cfg.VolumeProfileBinSize,
+		Sessions:             cfg.Sessions,
+		EqualLevelTolerance:  cfg.EqualLevelTolerance,
+		ImbalanceCriteria:    cfg.ImbalanceCriteria,
+		Classification:       cfg.Classification,
+		StatusTimeout:        cfg.StatusTimeout,
+		StatusTimeoutPolicy:  cfg.StatusTimeoutPolicy,
+		WarmupCandles:        cfg.WarmupCandles,
+		DrainGracePeriod:     cfg.DrainGracePeriod,
+		Backtest:             !cfg.Mode.usesLiveData(),
+		Subscribe:            fetchMgr.Subscribe,
+		RelayMarketUpdate:    relayMarketUpdateFunc,
+		RecordVWAP:           recordVWAPFunc,
+		CatchUp:              fetchMgr.SendCatchUpSignal,
+		SignalLevel:          signalLevelFunc,
+		SignalImbalance:      signalImbalanceFunc,
+		SignalOrderBlock:     signalOrderBlockFunc,
 
 		JobScheduler: jobScheduler,
+		ReplayClock:  replayClock,
 		Logger:       &marketMgrLogger,
 	}, now)
 	if err != nil {
 		return nil, fmt.Errorf("creating market manager: %v", err)
 	}
 
+	if cfg.PositionsDBFilepath != "" {
+		positionsDBLogger := logger.With().Str("component", "positionsdb").Logger()
+		positionsDB, err = database.NewSQLite(context.Background(), &database.SQLiteConfig{
+			Filepath: cfg.PositionsDBFilepath,
+			Logger:   &positionsDBLogger,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating positions database: %v", err)
+		}
+	}
+
+	// Price action state is kept in memory unless a state database is configured.
+	var stateStore priceaction.Store
+	if cfg.StateDBFilepath != "" {
+		stateDBLogger := logger.With().Str("component", "statedb").Logger()
+		stateDB, err = database.NewSQLite(context.Background(), &database.SQLiteConfig{
+			Filepath: cfg.StateDBFilepath,
+			Logger:   &stateDBLogger,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating state database: %v", err)
+		}
+
+		stateStore = &priceActionStore{db: stateDB}
+	}
+
+	var reporter *position.BacktestReporter
+	var tradeWriter *position.TradeWriter
+	var recordOpenedPositionFunc func(pos *position.Position)
+	if cfg.Mode.simulatesFills() {
+		if cfg.TradesCSVFilepath != "" {
+			tradeWriter, err = position.OpenTradeCSV(cfg.TradesCSVFilepath)
+			if err != nil {
+				return nil, fmt.Errorf("creating trade writer: %v", err)
+			}
+		}
+
+		reporter = position.NewBacktestReporter(tradeWriter)
+		recordOpenedPositionFunc = reporter.RecordEntry
+	}
+
+	persistClosedPositionFunc := func(pos *position.Position) error {
+		if reporter != nil {
+			err := reporter.RecordExit(pos)
+			if err != nil {
+				logger.Error().Msgf("recording %s exit: %v", cfg.Mode, err)
+			}
+		}
+
+		// Simulated paper positions are kept out of the positions database.
+		if positionsDB != nil && cfg.Mode != Paper {
+			return positionsDB.PersistClosedPosition(context.Background(), pos)
+		}
+
+		return nil
+	}
+
+	// Stop outs raise the bar for re-entries at the level the position was entered at.
+	recordStopOutFunc := func(market string, levelPrice float64, stoppedOn time.Time) {
+		if priceActionMgr != nil {
+			priceActionMgr.RecordStopOut(market, levelPrice, stoppedOn)
+		}
+	}
+
+	// Slippage is only assumed on simulated fills.
+	var slippage *position.SlippageConfig
+	if cfg.Mode.simulatesFills() {
+		slippage = cfg.Slippage
+	}
+
 	positionMgrLogger := logger.With().Str("component", "positionmanager").Logger()
+	// Only live positions are restored, simulated positions do not outlive the process.
+	var positionStore position.Store
+	if positionsDB != nil && cfg.Mode == Live {
+		positionStore = &livePositionStore{db: positionsDB}
+	}
+
 	positionMgr, err = position.NewPositionManager(&position.ManagerConfig{
-		Markets: cfg.Markets,
-		Notify: func(message string) {
-			// todo.
-		},
-		PersistClosedPosition: func(position *position.Position) error {
-			// todo.
-			return nil
-		},
-		JobScheduler: jobScheduler,
-		Logger:       &positionMgrLogger,
+		Markets:               cfg.Markets,
+		Notify:                notifier(cfg.Mode, cfg.Notify, &positionMgrLogger),
+		Throttle:              cfg.NotifyThrottle,
+		Sizing:                cfg.Sizing,
+		Instruments:           cfg.Instruments,
+		TrailingStop:          cfg.TrailingStop,
+		MaxOpenPositions:      cfg.MaxOpenPositions,
+		Correlation:           cfg.Correlation,
+		Slippage:              slippage,
+		DrainGracePeriod:      cfg.DrainGracePeriod,
+		PersistClosedPosition: persistClosedPositionFunc,
+		RecordOpenedPosition:  recordOpenedPositionFunc,
+		RecordStopOut:         recordStopOutFunc,
+		Store:                 positionStore,
+		JobScheduler:          jobScheduler,
+		Clock:                 clock,
+		Logger:                &positionMgrLogger,
 	})
+	if err != nil {
+		return nil, fmt.Errorf("creating position manager: %v", err)
+	}
 
 	levelReactionFunc := func(signal shared.ReactionAtLevel) {
+		if exporter != nil {
+			exporter.RecordReactionAtLevel(&signal)
+		}
 		if entryEngine != nil {
 			entryEngine.SignalReactionAtLevel(signal)
 		}
 	}
 
 	vwapReactionFunc := func(signal shared.ReactionAtVWAP) {
+		if exporter != nil {
+			exporter.RecordReactionAtVWAP(&signal)
+		}
 		if entryEngine != nil {
 			entryEngine.SignalReactionAtVWAP(signal)
 		}
 	}
 
 	imbalanceReactionFunc := func(signal shared.ReactionAtImbalance) {
+		if exporter != nil {
+			exporter.RecordReactionAtImbalance(&signal)
+		}
 		if entryEngine != nil {
 			entryEngine.SignalReactionAtImbalance(signal)
 		}
 	}
 
+	pauseReactionsFunc := func(market string) error {
+		if entryEngine == nil {
+			return nil
+		}
+
+		return entryEngine.Pause(market)
+	}
+
+	resumeReactionsFunc := func(market string) error {
+		if entryEngine == nil {
+			return nil
+		}
+
+		return entryEngine.Resume(market)
+	}
+
 	priceActionMgrLogger := logger.With().Str("component", "priceactionmanager").Logger()
 	priceActionMgr, err = priceaction.NewManager(&priceaction.ManagerConfig{
 		Markets:                   cfg.Markets,
@@ -219,24 +870,97 @@ func NewEntry(cfg *EntryConfig) (*Entry, error) {
 		RequestPriceData:          marketMgr.SendPriceDataRequest,
 		RequestVWAPData:           marketMgr.SendVWAPDataRequest,
 		RequestVWAP:               marketMgr.SendVWAPRequest,
+		RequestVWAPBands:          marketMgr.SendVWAPBandsRequest,
 		SignalReactionAtLevel:     levelReactionFunc,
 		SignalReactionAtVWAP:      vwapReactionFunc,
 		SignalReactionAtImbalance: imbalanceReactionFunc,
 		FetchCaughtUpState:        marketMgr.FetchCaughtUpState,
+		FetchWarmedUpState:        marketMgr.WarmedUp,
+		ReactionFilter:            cfg.ReactionFilter,
+		Classification:            cfg.Classification,
+		ReactionWindow:            cfg.ReactionWindow,
+		RequireFullWindow:         cfg.RequireFullWindow,
+		MinReactionCandles:        cfg.MinReactionCandles,
+		Timeouts:                  cfg.Timeouts,
+		ReactionDebounce:          cfg.ReactionDebounce,
+		VWAPBands:                 cfg.VWAPBands,
+		LateBreakHandling:         cfg.LateBreakHandling,
+		ReactionEvaluation:        cfg.ReactionEvaluation,
+		VWAPMismatchTolerance:     cfg.VWAPMismatchTolerance,
+		RequireImbalancePurge:     cfg.RequireImbalancePurge,
+		MarketWorkers:             cfg.PriceActionMarketWorkers,
+		RequestWorkers:            cfg.PriceActionRequestWorkers,
+		DrainGracePeriod:          cfg.DrainGracePeriod,
+		ErrorSink:                 cfg.ErrorSink,
+		Store:                     stateStore,
+		PauseReactions:            pauseReactionsFunc,
+		ResumeReactions:           resumeReactionsFunc,
+		StaleDistance:             cfg.StaleDistance,
+		StaleCleanupInterval:      cfg.StaleCleanupInterval,
+		JobScheduler:              jobScheduler,
 		Logger:                    &priceActionMgrLogger,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("creating price action manager: %v", err)
 	}
 
+	if cfg.AuditLogFilepath != "" {
+		audit, err = engine.OpenAuditLog(cfg.AuditLogFilepath)
+		if err != nil {
+			return nil, fmt.Errorf("creating audit writer: %v", err)
+		}
+	}
+
 	engineLogger := logger.With().Str("component", "engine").Logger()
 	entryEngine = engine.NewEngine(&engine.EngineConfig{
-		RequestCandleMetadata: priceActionMgr.SendCandleMetadataRequest,
-		RequestAverageVolume:  marketMgr.SendAverageVolumeRequest,
-		SendEntrySignal:       positionMgr.SendEntrySignal,
-		SendExitSignal:        positionMgr.SendExitSignal,
-		RequestMarketSkew:     positionMgr.SendMarketSkewRequest,
-		Logger:                engineLogger,
+		Markets:                 cfg.Markets,
+		Thresholds:              cfg.Thresholds,
+		ExitThresholds:          cfg.ExitThresholds,
+		NeutralSkewMode:         cfg.NeutralSkewMode,
+		TrendBias:               cfg.TrendBias,
+		ExitPolicy:              cfg.ExitPolicy,
+		ConfidenceWeights:       cfg.ConfidenceWeights,
+		NewsBlackout:            cfg.NewsBlackout,
+		RegimeFilter:            cfg.RegimeFilter,
+		RequestPriceData:        marketMgr.SendPriceDataRequest,
+		BracketRewardRatio:      cfg.BracketRewardRatio,
+		StructureSkew:           cfg.StructureSkew,
+		TightestStop:            cfg.TightestStop,
+		WickStopBuffer:          cfg.WickStopBuffer,
+		StopBuffers:             cfg.StopBuffers,
+		StopOutBump:             cfg.StopOutBump,
+		StopOutWindow:           cfg.StopOutWindow,
+		VWAPStretch:             cfg.VWAPStretch,
+		LevelVWAPGap:            cfg.LevelVWAPGap,
+		AssetClasses:            cfg.AssetClasses,
+		DirectionModes:          cfg.DirectionModes,
+		RTHWindows:              cfg.RTHWindows,
+		ReasonPriority:          cfg.ReasonPriority,
+		Timeouts:                cfg.Timeouts,
+		HighVolumeWindows:       cfg.HighVolumeWindows,
+		MinDistinctReasons:      cfg.MinDistinctReasons,
+		BreakConfirmationCloses: cfg.BreakConfirmationCloses,
+		Instruments:             cfg.Instruments,
+		StopRange:               cfg.StopRange,
+		EnabledReactions:        cfg.EnabledReactions,
+		LevelWeights:            cfg.LevelWeights,
+		Classification:          cfg.Classification,
+		DrainGracePeriod:        cfg.DrainGracePeriod,
+		ErrorSink:               cfg.ErrorSink,
+		CapacityPolicy:          cfg.CapacityPolicy,
+		VolumeNormalization:     cfg.VolumeNormalization,
+		VolumeFloors:            cfg.VolumeFloors,
+		CapacityTimeout:         cfg.CapacityTimeout,
+		Audit:                   audit,
+		RequestCandleMetadata:   priceActionMgr.SendCandleMetadataRequest,
+		RequestAverageVolume:    marketMgr.SendAverageVolumeRequest,
+		SendEntrySignal:         positionMgr.SendEntrySignal,
+		SendExitSignal:          positionMgr.SendExitSignal,
+		RequestMarketSkew:       positionMgr.SendMarketSkewRequest,
+		LatestOpenLeg:           positionMgr.LatestOpenLeg,
+		RequestTrend:            marketMgr.SendTrendRequest,
+		RequestMovingAverage:    marketMgr.SendMovingAverageRequest,
+		Logger:                  engineLogger,
 	})
 
 	service := &Entry{
@@ -247,15 +971,350 @@ func NewEntry(cfg *EntryConfig) (*Entry, error) {
 		priceActionManager: priceActionMgr,
 		historicData:       historicData,
 		entryEngine:        entryEngine,
+		positionsDB:        positionsDB,
+		stateDB:            stateDB,
+		exporter:           exporter,
+		audit:              audit,
+		reporter:           reporter,
+		tradeWriter:        tradeWriter,
+		markets:            append([]string{}, cfg.Markets...),
+		clock:              clock,
 		logger:             &logger,
 	}
 
 	return service, nil
 }
 
+// marketComponent represents a component of the service markets are tracked by.
+type marketComponent struct {
+	name   string
+	add    func(market string) error
+	remove func(market string) error
+}
+
+// marketComponents returns the components markets are tracked by, in the order markets are
+// added to them. Markets are removed from them in reverse.
+//
+// The engine is added to before the market manager since adding a market to the market
+// manager triggers its catch up, and removed from after the market manager stops relaying
+// updates for the market.
+func (e *Entry) marketComponents(now time.Time) []marketComponent {
+	return []marketComponent{
+		{"fetch manager", e.fetchManager.AddMarket, e.fetchManager.RemoveMarket},
+		{"position manager", e.positionManager.AddMarket, e.positionManager.RemoveMarket},
+		{"price action manager", e.priceActionManager.AddMarket, e.priceActionManager.RemoveMarket},
+		{"engine", e.entryEngine.AddMarket, e.entryEngine.RemoveMarket},
+		{"market manager", func(market string) error {
+			return e.marketManager.AddMarket(market, now)
+		}, e.marketManager.RemoveMarket},
+	}
+}
+
+// Reload applies the provided markets, engine thresholds and weights to the running service.
+//
+// The reload config is validated in full before any change is applied, a reload failing part
+// way is rolled back so the service keeps running with its previous config. Added markets get
+// tracked by all components and caught up, removed markets stop being tracked by all
+// components. Markets with open positions cannot be removed.
+func (e *Entry) Reload(cfg *ReloadConfig) error {
+	if e.cfg.Mode == Backtest {
+		return fmt.Errorf("reloading is not supported when backtesting")
+	}
+
+	err := cfg.Validate()
+	if err != nil {
+		return fmt.Errorf("validating reload config: %v", err)
+	}
+
+	e.reloadMtx.Lock()
+	defer e.reloadMtx.Unlock()
+
+	current := make(map[string]struct{}, len(e.markets))
+	for idx := range e.markets {
+		current[e.markets[idx]] = struct{}{}
+	}
+
+	updated := make(map[string]struct{}, len(cfg.Markets))
+	markets := make([]string, 0, len(cfg.Markets))
+	added := make([]string, 0, len(cfg.Markets))
+	for idx := range cfg.Markets {
+		market := cfg.Markets[idx]
+		if _, ok := updated[market]; ok {
+			continue
+		}
+
+		updated[market] = struct{}{}
+		markets = append(markets, market)
+		if _, ok := current[market]; !ok {
+			added = append(added, market)
+		}
+	}
+
+	removed := make([]string, 0, len(e.markets))
+	for idx := range e.markets {
+		market := e.markets[idx]
+		if _, ok := updated[market]; !ok {
+			removed = append(removed, market)
+		}
+	}
+
+	// Ensure removed markets have no open positions before changing any state.
+	for idx := range removed {
+		open, err := e.positionManager.HasOpenPositions(removed[idx])
+		if err != nil {
+			return fmt.Errorf("checking open positions: %v", err)
+		}
+
+		if open {
+			return fmt.Errorf("cannot remove %s market with open positions", removed[idx])
+		}
+	}
+
+	now, _, err := shared.NewYorkTimeFrom(e.clock)
+	if err != nil {
+		return fmt.Errorf("fetching new york time: %v", err)
+	}
+
+	// The inverse of every applied change is recorded, they are applied in reverse to roll
+	// back a failed reload.
+	var undo []func() error
+	rollback := func() {
+		for idx := len(undo) - 1; idx >= 0; idx-- {
+			err := undo[idx]()
+			if err != nil {
+				e.logger.Error().Msgf("rolling back reload: %v", err)
+			}
+		}
+	}
+
+	components := e.marketComponents(now)
+	for idx := range removed {
+		market := removed[idx]
+		for i := len(components) - 1; i >= 0; i-- {
+			component := components[i]
+			err := component.remove(market)
+			if err != nil {
+				rollback()
+				return fmt.Errorf("removing %s market from %s: %v", market, component.name, err)
+			}
+
+			undo = append(undo, func() error { return component.add(market) })
+		}
+
+		e.logger.Info().Msgf("removed %s market", market)
+	}
+
+	for idx := range added {
+		market := added[idx]
+		for i := range components {
+			component := components[i]
+			err := component.add(market)
+			if err != nil {
+				rollback()
+				return fmt.Errorf("adding %s market to %s: %v", market, component.name, err)
+			}
+
+			undo = append(undo, func() error { return component.remove(market) })
+		}
+
+		e.logger.Info().Msgf("added %s market", market)
+	}
+
+	if cfg.Thresholds != nil {
+		previous := e.entryEngine.Thresholds()
+		err := e.entryEngine.SetThresholds(cfg.Thresholds)
+		if err != nil {
+			rollback()
+			return fmt.Errorf("setting engine thresholds: %v", err)
+		}
+
+		undo = append(undo, func() error { return e.entryEngine.SetThresholds(&previous) })
+	}
+
+	if cfg.ConfidenceWeights != nil {
+		previous := e.entryEngine.ConfidenceWeights()
+		err := e.entryEngine.SetConfidenceWeights(cfg.ConfidenceWeights)
+		if err != nil {
+			rollback()
+			return fmt.Errorf("setting engine confidence weights: %v", err)
+		}
+
+		undo = append(undo, func() error { return e.entryEngine.SetConfidenceWeights(&previous) })
+	}
+
+	if cfg.LevelWeights != nil {
+		err := e.entryEngine.SetLevelWeights(cfg.LevelWeights)
+		if err != nil {
+			rollback()
+			return fmt.Errorf("setting engine level weights: %v", err)
+		}
+	}
+
+	e.markets = markets
+
+	return nil
+}
+
+// BacktestReport returns the performance statistics of the backtest or paper run. The
+// report is only available when fills are simulated.
+func (e *Entry) BacktestReport() (position.BacktestReport, bool) {
+	if e.reporter == nil {
+		return position.BacktestReport{}, false
+	}
+
+	return e.reporter.Report(), true
+}
+
+// AddManualLevel injects a manually drawn level of the provided kind for the provided
+// market, such as a weekly high. Manual levels are evaluated for reactions like any other
+// level and are exempt from eviction.
+func (e *Entry) AddManualLevel(market string, price float64, kind shared.LevelKind) error {
+	return e.marketManager.AddManualLevel(market, price, kind)
+}
+
+// ReplayLevelReaction evaluates the reaction of the provided candles at the provided level
+// without firing signals, returning its confluence breakdown and the entry decision it would
+// lead to. It is used to tune strategies against arbitrary historical windows.
+func (e *Entry) ReplayLevelReaction(market string, timeframe shared.Timeframe, level *shared.Level, candles []*shared.Candlestick) (*engine.AuditRecord, error) {
+	return e.entryEngine.ReplayLevelReaction(market, timeframe, level, candles)
+}
+
+// entryState is the serializable state of the managers of the service.
+type entryState struct {
+	Markets     json.RawMessage
+	PriceAction json.RawMessage
+	Positions   json.RawMessage
+}
+
+// SnapshotState serializes the caught up status, levels, imbalances and positions of all
+// tracked markets to json.
+func (e *Entry) SnapshotState() ([]byte, error) {
+	var state entryState
+	var err error
+
+	state.Markets, err = e.marketManager.SnapshotState()
+	if err != nil {
+		return nil, err
+	}
+	state.PriceAction, err = e.priceActionManager.SnapshotState()
+	if err != nil {
+		return nil, err
+	}
+	state.Positions, err = e.positionManager.SnapshotState()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling state: %v", err)
+	}
+
+	return data, nil
+}
+
+// RestoreState restores the caught up status, levels, imbalances and positions of tracked
+// markets from the provided json state. Markets restored as caught up are processed without
+// awaiting a catch up signal.
+func (e *Entry) RestoreState(data []byte) error {
+	var state entryState
+	err := json.Unmarshal(data, &state)
+	if err != nil {
+		return fmt.Errorf("unmarshaling state: %v", err)
+	}
+
+	err = e.priceActionManager.RestoreState(state.PriceAction)
+	if err != nil {
+		return err
+	}
+	err = e.positionManager.RestoreState(state.Positions)
+	if err != nil {
+		return err
+	}
+
+	return e.marketManager.RestoreState(state.Markets)
+}
+
+// DroppedSignals returns the number of signals dropped by channels at capacity, keyed by
+// component and channel.
+func (e *Entry) DroppedSignals() map[string]uint64 {
+	dropped := make(map[string]uint64)
+	components := []struct {
+		name  string
+		drops map[string]uint64
+	}{
+		{"fetch", e.fetchManager.Dropped()},
+		{"market", e.marketManager.Dropped()},
+		{"priceaction", e.priceActionManager.Dropped()},
+		{"engine", e.entryEngine.Dropped()},
+		{"position", e.positionManager.Dropped()},
+	}
+	for _, component := range components {
+		for channel, count := range component.drops {
+			dropped[component.name+"/"+channel] = count
+		}
+	}
+
+	return dropped
+}
+
+// reportDroppedSignals periodically logs the signals dropped by channels at capacity.
+// Nothing is logged for intervals without new drops.
+func (e *Entry) reportDroppedSignals(ctx context.Context) {
+	interval := e.cfg.DropReportInterval
+	if interval == 0 {
+		interval = defaultDropReportInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var reported uint64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			dropped := e.DroppedSignals()
+
+			var total uint64
+			channels := make([]string, 0, len(dropped))
+			for channel, count := range dropped {
+				total += count
+				channels = append(channels, channel)
+			}
+			if total == reported {
+				continue
+			}
+			reported = total
+
+			slices.Sort(channels)
+			counts := make([]string, 0, len(channels))
+			for _, channel := range channels {
+				counts = append(counts, fmt.Sprintf("%s=%d", channel, dropped[channel]))
+			}
+
+			e.logger.Warn().Msgf("signals dropped at capacity: %s", strings.Join(counts, ", "))
+		}
+	}
+}
+
 // Run handles the lifecycle processes of the entry service.
 func (e *Entry) Run(ctx context.Context) {
-	e.wg.Add(5)
+	e.wg.Add(6)
+
+	if e.cfg.HealthAddress != "" {
+		e.wg.Add(1)
+		go func() {
+			e.serveHealth(ctx)
+			e.wg.Done()
+		}()
+	}
+
+	go func() {
+		e.reportDroppedSignals(ctx)
+		e.wg.Done()
+	}()
 
 	go func() {
 		e.positionManager.Run(ctx)
@@ -282,7 +1341,7 @@ func (e *Entry) Run(ctx context.Context) {
 		e.wg.Done()
 	}()
 
-	if e.cfg.Backtest {
+	if e.cfg.Mode == Backtest {
 		go func() {
 			// wait briefly for initialization.
 			time.Sleep(time.Second * 1)
@@ -299,4 +1358,43 @@ func (e *Entry) Run(ctx context.Context) {
 	}
 
 	e.wg.Wait()
+
+	if e.reporter != nil {
+		e.logger.Info().Msgf("%s report: %s", e.cfg.Mode, e.reporter.Report().String())
+	}
+
+	if e.exporter != nil {
+		err := e.exporter.Persist()
+		if err != nil {
+			e.logger.Error().Msgf("exporting chart data: %v", err)
+		}
+	}
+
+	if e.positionsDB != nil {
+		err := e.positionsDB.Close()
+		if err != nil {
+			e.logger.Error().Msgf("closing positions database: %v", err)
+		}
+	}
+
+	if e.stateDB != nil {
+		err := e.stateDB.Close()
+		if err != nil {
+			e.logger.Error().Msgf("closing state database: %v", err)
+		}
+	}
+
+	if e.audit != nil {
+		err := e.audit.Close()
+		if err != nil {
+			e.logger.Error().Msgf("closing audit log: %v", err)
+		}
+	}
+
+	if e.tradeWriter != nil {
+		err := e.tradeWriter.Close()
+		if err != nil {
+			e.logger.Error().Msgf("closing trade csv: %v", err)
+		}
+	}
 }
@@ -2,8 +2,15 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/dnldd/entry/engine"
+	"github.com/dnldd/entry/export"
+	"github.com/dnldd/entry/position"
+	"github.com/dnldd/entry/shared"
 	"github.com/peterldowns/testy/assert"
 )
 
@@ -15,7 +22,6 @@ func TestEntryRun(t *testing.T) {
 	cfg := EntryConfig{
 		Markets:   []string{market},
 		FMPAPIKey: "key",
-		Backtest:  false,
 		Cancel:    cancel,
 	}
 	entry, err := NewEntry(&cfg)
@@ -29,6 +35,42 @@ func TestEntryRun(t *testing.T) {
 
 	cancel()
 	<-done
+
+	// Ensure backtest reports are only available when backtesting.
+	_, ok := entry.BacktestReport()
+	assert.False(t, ok)
+
+	// Ensure no dropped signals are reported without channels reaching capacity.
+	assert.Equal(t, len(entry.DroppedSignals()), 0)
+}
+
+func TestEntryPaper(t *testing.T) {
+	// Ensure the entry service can be created and run in paper mode.
+	market := "^GSPC"
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg := EntryConfig{
+		Markets:   []string{market},
+		FMPAPIKey: "key",
+		Mode:      Paper,
+		Cancel:    cancel,
+	}
+	entry, err := NewEntry(&cfg)
+	assert.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		entry.Run(ctx)
+		close(done)
+	}()
+
+	cancel()
+	<-done
+
+	// Ensure simulated fills are reported in paper mode.
+	report, ok := entry.BacktestReport()
+	assert.True(t, ok)
+	assert.Equal(t, report.Trades, 0)
 }
 
 func TestEntryBacktest(t *testing.T) {
@@ -39,8 +81,39 @@ func TestEntryBacktest(t *testing.T) {
 	cfg := EntryConfig{
 		Markets:              []string{market},
 		FMPAPIKey:            "key",
-		Backtest:             true,
+		Mode:                 Backtest,
+		BacktestDataFilepath: "../testdata/historicdata.json",
+		Cancel:               cancel,
+	}
+	entry, err := NewEntry(&cfg)
+	assert.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		entry.Run(ctx)
+		close(done)
+	}()
+
+	<-done
+
+	// Ensure a backtest report is available once the backtest completes.
+	_, ok := entry.BacktestReport()
+	assert.True(t, ok)
+}
+
+func TestEntryBacktestExport(t *testing.T) {
+	// Ensure a backtest over the bundled historical data exports chart data.
+	market := "^GSPC"
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	path := filepath.Join(t.TempDir(), "export.json")
+	cfg := EntryConfig{
+		Markets:              []string{market},
+		FMPAPIKey:            "key",
+		Mode:                 Backtest,
 		BacktestDataFilepath: "../testdata/historicdata.json",
+		ExportFilepath:       path,
+		WarmupCandles:        5,
 		Cancel:               cancel,
 	}
 	entry, err := NewEntry(&cfg)
@@ -53,4 +126,332 @@ func TestEntryBacktest(t *testing.T) {
 	}()
 
 	<-done
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	var chart export.Export
+	err = json.Unmarshal(data, &chart)
+	assert.NoError(t, err)
+
+	mkt, ok := chart.Markets[market]
+	assert.True(t, ok)
+	assert.True(t, len(mkt.Levels) > 0)
+
+	fiveMinute, ok := mkt.Timeframes[shared.FiveMinute.String()]
+	assert.True(t, ok)
+	assert.True(t, len(fiveMinute.VWAP) > 0)
+	assert.True(t, len(fiveMinute.Reactions) > 0)
+}
+
+func TestEntryBacktestDeterminism(t *testing.T) {
+	// runBacktest runs a backtest over the bundled historical data and returns its exported
+	// chart data and report.
+	runBacktest := func(path string) (export.Export, position.BacktestReport) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		cfg := EntryConfig{
+			Markets:              []string{"^GSPC"},
+			FMPAPIKey:            "key",
+			Mode:                 Backtest,
+			BacktestDataFilepath: "../testdata/historicdata.json",
+			ExportFilepath:       path,
+			WarmupCandles:        5,
+			Cancel:               cancel,
+		}
+		entry, err := NewEntry(&cfg)
+		assert.NoError(t, err)
+
+		done := make(chan struct{})
+		go func() {
+			entry.Run(ctx)
+			close(done)
+		}()
+
+		<-done
+
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+
+		var chart export.Export
+		err = json.Unmarshal(data, &chart)
+		assert.NoError(t, err)
+
+		report, ok := entry.BacktestReport()
+		assert.True(t, ok)
+
+		return chart, report
+	}
+
+	// Ensure backtests driven by the replay clock produce identical signals across runs.
+	first, firstReport := runBacktest(filepath.Join(t.TempDir(), "first.json"))
+	second, secondReport := runBacktest(filepath.Join(t.TempDir(), "second.json"))
+	assert.Equal(t, second, first)
+	assert.Equal(t, secondReport, firstReport)
+}
+
+func TestEntryPositionsDB(t *testing.T) {
+	// Ensure the entry service can be created with a closed positions database.
+	market := "^GSPC"
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	path := filepath.Join(t.TempDir(), "positions.db")
+	cfg := EntryConfig{
+		Markets:             []string{market},
+		FMPAPIKey:           "key",
+		PositionsDBFilepath: path,
+		Cancel:              cancel,
+	}
+	entry, err := NewEntry(&cfg)
+	assert.NoError(t, err)
+	assert.NotEqual(t, entry.positionsDB, nil)
+
+	_, err = os.Stat(path)
+	assert.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		entry.Run(ctx)
+		close(done)
+	}()
+
+	cancel()
+	<-done
+}
+
+func TestEntryStateDB(t *testing.T) {
+	// Ensure the entry service can be created with a level and imbalance state database.
+	market := "^GSPC"
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	path := filepath.Join(t.TempDir(), "state.db")
+	cfg := EntryConfig{
+		Markets:         []string{market},
+		FMPAPIKey:       "key",
+		StateDBFilepath: path,
+		Cancel:          cancel,
+	}
+	entry, err := NewEntry(&cfg)
+	assert.NoError(t, err)
+	assert.NotEqual(t, entry.stateDB, nil)
+
+	_, err = os.Stat(path)
+	assert.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		entry.Run(ctx)
+		close(done)
+	}()
+
+	cancel()
+	<-done
+}
+
+func TestEntryReload(t *testing.T) {
+	gspc := "^GSPC"
+	ixic := "^IXIC"
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg := EntryConfig{
+		Markets:   []string{gspc},
+		FMPAPIKey: "key",
+		Cancel:    cancel,
+	}
+	entry, err := NewEntry(&cfg)
+	assert.NoError(t, err)
+
+	// Run the components processing market updates, the fetch manager is left out to avoid
+	// catch ups querying the exchange.
+	done := make(chan struct{}, 4)
+	for _, run := range []func(context.Context){entry.marketManager.Run,
+		entry.priceActionManager.Run, entry.entryEngine.Run, entry.positionManager.Run} {
+		go func() {
+			run(ctx)
+			done <- struct{}{}
+		}()
+	}
+
+	// Ensure an invalid reload config is rejected.
+	err = entry.Reload(&ReloadConfig{})
+	assert.Error(t, err)
+
+	err = entry.Reload(&ReloadConfig{Markets: []string{gspc}, Thresholds: &engine.Thresholds{}})
+	assert.Error(t, err)
+
+	// Ensure a market can be added at runtime along with updated thresholds.
+	thresholds := engine.DefaultThresholds()
+	thresholds.LevelBreak = 8
+	err = entry.Reload(&ReloadConfig{Markets: []string{gspc, ixic}, Thresholds: thresholds})
+	assert.NoError(t, err)
+	assert.Equal(t, entry.markets, []string{gspc, ixic})
+	assert.Equal(t, entry.entryEngine.Thresholds(), *thresholds)
+
+	// Ensure the added market starts receiving market updates.
+	now, _, err := shared.NewYorkTime()
+	assert.NoError(t, err)
+
+	candle := shared.Candlestick{
+		Open:   float64(5),
+		Close:  float64(8),
+		High:   float64(9),
+		Low:    float64(3),
+		Volume: float64(4),
+		Date:   now,
+
+		Market:    ixic,
+		Timeframe: shared.FiveMinute,
+		Status:    make(chan shared.StatusCode, 1),
+	}
+
+	entry.marketManager.SendMarketUpdate(candle)
+	<-candle.Status
+
+	avgVolumeReq := shared.AverageVolumeRequest{
+		Market:    ixic,
+		Timeframe: shared.FiveMinute,
+		Response:  make(chan float64, 1),
+	}
+
+	entry.marketManager.SendAverageVolumeRequest(avgVolumeReq)
+	avgVol := <-avgVolumeReq.Response
+	assert.Equal(t, avgVol, float64(4))
+
+	// Ensure a market with open positions cannot be removed.
+	entrySignal := shared.EntrySignal{
+		Market:    ixic,
+		Timeframe: shared.FiveMinute,
+		Direction: shared.Long,
+		Price:     float64(10),
+		Reasons:   []shared.Reason{shared.BullishEngulfing, shared.StrongVolume},
+		StopLoss:  float64(8),
+		Status:    make(chan shared.StatusCode, 1),
+	}
+
+	entry.positionManager.SendEntrySignal(entrySignal)
+	<-entrySignal.Status
+
+	err = entry.Reload(&ReloadConfig{Markets: []string{gspc}})
+	assert.Error(t, err)
+	assert.Equal(t, entry.markets, []string{gspc, ixic})
+
+	// Ensure a market without open positions can be removed at runtime.
+	exitSignal := shared.ExitSignal{
+		Market:    ixic,
+		Timeframe: shared.FiveMinute,
+		Direction: shared.Long,
+		Price:     float64(15),
+		Reasons:   []shared.Reason{shared.BearishEngulfing, shared.StrongVolume},
+		Status:    make(chan shared.StatusCode, 1),
+		CreatedOn: now,
+	}
+
+	entry.positionManager.SendExitSignal(exitSignal)
+	<-exitSignal.Status
+
+	err = entry.Reload(&ReloadConfig{Markets: []string{gspc}})
+	assert.NoError(t, err)
+	assert.Equal(t, entry.markets, []string{gspc})
+
+	_, err = entry.marketManager.FetchCaughtUpState(ixic)
+	assert.Error(t, err)
+
+	cancel()
+	for range 4 {
+		<-done
+	}
+}
+
+func TestEntryReloadRollback(t *testing.T) {
+	gspc := "^GSPC"
+	ixic := "^IXIC"
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg := EntryConfig{
+		Markets:   []string{gspc},
+		FMPAPIKey: "key",
+		Cancel:    cancel,
+	}
+	entry, err := NewEntry(&cfg)
+	assert.NoError(t, err)
+
+	done := make(chan struct{}, 4)
+	for _, run := range []func(context.Context){entry.marketManager.Run,
+		entry.priceActionManager.Run, entry.entryEngine.Run, entry.positionManager.Run} {
+		go func() {
+			run(ctx)
+			done <- struct{}{}
+		}()
+	}
+
+	// Tracking the added market in the engine beforehand fails the reload part way.
+	err = entry.entryEngine.AddMarket(ixic)
+	assert.NoError(t, err)
+
+	thresholds := engine.DefaultThresholds()
+	thresholds.LevelBreak = 8
+	reloadCfg := &ReloadConfig{
+		Markets:           []string{gspc, ixic},
+		Thresholds:        thresholds,
+		ConfidenceWeights: &engine.ConfidenceWeights{Confluence: 1},
+		LevelWeights:      map[shared.LevelSource]uint32{shared.DailyLevel: 3},
+	}
+
+	// Ensure a partially applied market addition is rolled back along with the thresholds
+	// and weights.
+	err = entry.Reload(reloadCfg)
+	assert.Error(t, err)
+	assert.Equal(t, entry.markets, []string{gspc})
+	assert.Equal(t, entry.entryEngine.Thresholds(), *engine.DefaultThresholds())
+	assert.Equal(t, entry.entryEngine.ConfidenceWeights(), *engine.DefaultConfidenceWeights())
+	assert.Equal(t, entry.entryEngine.LevelWeights(), engine.DefaultLevelWeights())
+	_, err = entry.positionManager.HasOpenPositions(ixic)
+	assert.Error(t, err)
+	_, err = entry.marketManager.FetchCaughtUpState(ixic)
+	assert.Error(t, err)
+
+	// Ensure a market removal is rolled back when a subsequent addition fails.
+	err = entry.Reload(&ReloadConfig{Markets: []string{ixic}})
+	assert.Error(t, err)
+	assert.Equal(t, entry.markets, []string{gspc})
+	_, err = entry.positionManager.HasOpenPositions(gspc)
+	assert.NoError(t, err)
+	_, err = entry.marketManager.FetchCaughtUpState(gspc)
+	assert.NoError(t, err)
+	_, err = entry.positionManager.HasOpenPositions(ixic)
+	assert.Error(t, err)
+
+	// Ensure the reload applies in full once nothing fails.
+	err = entry.entryEngine.RemoveMarket(ixic)
+	assert.NoError(t, err)
+	err = entry.Reload(reloadCfg)
+	assert.NoError(t, err)
+	assert.Equal(t, entry.markets, []string{gspc, ixic})
+	assert.Equal(t, entry.entryEngine.Thresholds(), *thresholds)
+	assert.Equal(t, entry.entryEngine.ConfidenceWeights(), *reloadCfg.ConfidenceWeights)
+	assert.Equal(t, entry.entryEngine.LevelWeights(), reloadCfg.LevelWeights)
+
+	cancel()
+	for range 4 {
+		<-done
+	}
+}
+
+func TestEntryReloadBacktest(t *testing.T) {
+	// Ensure reloading is not supported when backtesting.
+	market := "^GSPC"
+	_, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg := EntryConfig{
+		Markets:              []string{market},
+		FMPAPIKey:            "key",
+		Mode:                 Backtest,
+		BacktestDataFilepath: "../testdata/historicdata.json",
+		Cancel:               cancel,
+	}
+	entry, err := NewEntry(&cfg)
+	assert.NoError(t, err)
+
+	err = entry.Reload(&ReloadConfig{Markets: []string{market, "^IXIC"}})
+	assert.Error(t, err)
 }
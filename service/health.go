@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// healthReadTimeout is the maximum time spent reading health probe request headers.
+	healthReadTimeout = time.Second * 5
+	// healthShutdownTimeout is the maximum time spent shutting down the health server.
+	healthShutdownTimeout = time.Second * 5
+)
+
+// checkReady returns an error describing why the entry service is not ready. The service is
+// ready once all tracked markets are caught up and the exchange feed is connected.
+func (e *Entry) checkReady() error {
+	e.reloadMtx.Lock()
+	markets := append([]string(nil), e.markets...)
+	e.reloadMtx.Unlock()
+
+	// Backtests are fed historic data, not the exchange feed.
+	if e.cfg.Mode != Backtest && !e.fetchManager.Connected() {
+		return fmt.Errorf("exchange feed not connected")
+	}
+
+	for idx := range markets {
+		caughtUp, err := e.marketManager.FetchCaughtUpState(markets[idx])
+		if err != nil {
+			return fmt.Errorf("fetching %s caught up state: %v", markets[idx], err)
+		}
+		if !caughtUp {
+			return fmt.Errorf("%s not caught up", markets[idx])
+		}
+	}
+
+	return nil
+}
+
+// healthHandler returns the handler serving the liveness and readiness probes of the entry
+// service.
+func (e *Entry) healthHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		err := e.checkReady()
+		if err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintln(w, err.Error())
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ready")
+	})
+
+	return mux
+}
+
+// serveHealth serves the liveness and readiness probes on the health address until the
+// provided context is cancelled.
+func (e *Entry) serveHealth(ctx context.Context) {
+	server := &http.Server{
+		Addr:              e.cfg.HealthAddress,
+		Handler:           e.healthHandler(),
+		ReadHeaderTimeout: healthReadTimeout,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), healthShutdownTimeout)
+		defer cancel()
+		err := server.Shutdown(shutdownCtx)
+		if err != nil {
+			e.logger.Error().Msgf("shutting down health server: %v", err)
+		}
+	}()
+
+	err := server.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		e.logger.Error().Msgf("serving health probes: %v", err)
+	}
+}
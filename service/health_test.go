@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dnldd/entry/fetch"
+	"github.com/dnldd/entry/shared"
+	"github.com/go-co-op/gocron"
+	"github.com/peterldowns/testy/assert"
+	"github.com/rs/zerolog/log"
+	"github.com/tidwall/gjson"
+)
+
+type fetcherMock struct {
+	data []gjson.Result
+}
+
+func (m *fetcherMock) FetchIndexIntradayHistorical(ctx context.Context, market string,
+	timeframe shared.Timeframe, start time.Time, end time.Time) ([]gjson.Result, error) {
+	return m.data, nil
+}
+
+func TestEntryHealthProbes(t *testing.T) {
+	market := "^GSPC"
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg := EntryConfig{
+		Markets:   []string{market},
+		FMPAPIKey: "key",
+		Cancel:    cancel,
+	}
+	entry, err := NewEntry(&cfg)
+	assert.NoError(t, err)
+
+	// Catch up through a fetch manager backed by a mock exchange client to avoid querying the
+	// exchange.
+	loc, err := time.LoadLocation(shared.NewYorkLocation)
+	assert.NoError(t, err)
+	data := `[{"open":10,"close":12,"high":15,"low":8, "volume":5,"date":"2025-02-04 15:05:00"}]`
+	entry.fetchManager, err = fetch.NewManager(&fetch.ManagerConfig{
+		Markets:        []string{market},
+		ExchangeClient: &fetcherMock{data: gjson.Parse(data).Array()},
+		SignalCaughtUp: entry.marketManager.SendCaughtUpSignal,
+		JobScheduler:   gocron.NewScheduler(loc),
+		Logger:         &log.Logger,
+	})
+	assert.NoError(t, err)
+
+	done := make(chan struct{}, 2)
+	for _, run := range []func(context.Context){entry.marketManager.Run, entry.fetchManager.Run} {
+		go func() {
+			run(ctx)
+			done <- struct{}{}
+		}()
+	}
+
+	server := httptest.NewServer(entry.healthHandler())
+	defer server.Close()
+
+	probe := func(path string) int {
+		resp, err := http.Get(server.URL + path)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// Ensure the service is alive but not ready before markets are caught up.
+	assert.Equal(t, probe("/healthz"), http.StatusOK)
+	assert.Equal(t, probe("/readyz"), http.StatusServiceUnavailable)
+
+	// Ensure the service is ready once markets report caught up.
+	catchUp := shared.NewCatchUpSignal(market, []shared.Timeframe{shared.FiveMinute}, time.Time{})
+	entry.fetchManager.SendCatchUpSignal(catchUp)
+
+	status := http.StatusServiceUnavailable
+	for range 50 {
+		status = probe("/readyz")
+		if status == http.StatusOK {
+			break
+		}
+		time.Sleep(time.Millisecond * 20)
+	}
+	assert.Equal(t, status, http.StatusOK)
+	assert.Equal(t, probe("/healthz"), http.StatusOK)
+
+	cancel()
+	<-done
+	<-done
+}
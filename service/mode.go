@@ -0,0 +1,64 @@
+package service
+
+import "github.com/rs/zerolog"
+
+// Mode represents the execution mode of the entry service.
+type Mode int
+
+const (
+	// Live evaluates live market data and relays notifications to the notification
+	// integration.
+	Live Mode = iota
+	// Paper evaluates live market data with simulated fills. Notifications are logged
+	// and closed positions are recorded to the paper report instead of the positions database.
+	Paper
+	// Backtest replays historic market data with simulated fills.
+	Backtest
+)
+
+// String stringifies the provided mode.
+func (m Mode) String() string {
+	switch m {
+	case Live:
+		return "live"
+	case Paper:
+		return "paper"
+	case Backtest:
+		return "backtest"
+	default:
+		return "unknown"
+	}
+}
+
+// usesLiveData returns whether the mode evaluates live market data. Markets are caught up
+// on startup when evaluating live market data.
+func (m Mode) usesLiveData() bool {
+	return m != Backtest
+}
+
+// simulatesFills returns whether positions taken in the mode are simulated.
+func (m Mode) simulatesFills() bool {
+	return m != Live
+}
+
+// notifier returns the notification function for the provided mode. Live notifications are
+// relayed to the provided notify function, or logged if it is nil. Paper notifications are
+// always logged and backtest notifications are discarded.
+func notifier(mode Mode, notify func(message string), logger *zerolog.Logger) func(message string) {
+	switch mode {
+	case Live:
+		if notify != nil {
+			return notify
+		}
+
+		return func(message string) {
+			logger.Info().Msg(message)
+		}
+	case Paper:
+		return func(message string) {
+			logger.Info().Msgf("paper: %s", message)
+		}
+	default:
+		return func(message string) {}
+	}
+}
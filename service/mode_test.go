@@ -0,0 +1,95 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/peterldowns/testy/assert"
+	"github.com/rs/zerolog"
+)
+
+func TestModeBehaviour(t *testing.T) {
+	tests := []struct {
+		name           string
+		mode           Mode
+		usesLiveData   bool
+		simulatesFills bool
+	}{
+		{"live", Live, true, false},
+		{"paper", Paper, true, true},
+		{"backtest", Backtest, false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.mode.String(), test.name)
+			// Ensure only modes evaluating live data catch up markets.
+			assert.Equal(t, test.mode.usesLiveData(), test.usesLiveData)
+			assert.Equal(t, test.mode.simulatesFills(), test.simulatesFills)
+		})
+	}
+}
+
+func TestNotifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     Mode
+		notify   bool
+		notified int
+		logged   string
+	}{
+		{
+			name:     "live relays to the notification integration",
+			mode:     Live,
+			notify:   true,
+			notified: 1,
+			logged:   "",
+		},
+		{
+			name:     "live without a notification integration logs",
+			mode:     Live,
+			notify:   false,
+			notified: 0,
+			logged:   "position opened",
+		},
+		{
+			name:     "paper logs instead of relaying to the notification integration",
+			mode:     Paper,
+			notify:   true,
+			notified: 0,
+			logged:   "paper: position opened",
+		},
+		{
+			name:     "backtest discards notifications",
+			mode:     Backtest,
+			notify:   true,
+			notified: 0,
+			logged:   "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := zerolog.New(&buf)
+
+			notified := 0
+			var notify func(message string)
+			if test.notify {
+				notify = func(message string) {
+					notified++
+				}
+			}
+
+			notifier(test.mode, notify, &logger)("position opened")
+			assert.Equal(t, notified, test.notified)
+			if test.logged == "" {
+				assert.Equal(t, buf.Len(), 0)
+				return
+			}
+
+			assert.True(t, strings.Contains(buf.String(), test.logged))
+		})
+	}
+}
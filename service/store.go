@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+
+	"github.com/dnldd/entry/database"
+	"github.com/dnldd/entry/position"
+	"github.com/dnldd/entry/priceaction"
+	"github.com/dnldd/entry/shared"
+)
+
+// livePositionStore stores the open positions of markets in the positions database.
+type livePositionStore struct {
+	db *database.SQLite
+}
+
+// Ensure the position store implements the position Store interface.
+var _ position.Store = (*livePositionStore)(nil)
+
+// PersistOpenPositions replaces the stored open positions of the provided market.
+func (s *livePositionStore) PersistOpenPositions(market string, positions []*position.Position) error {
+	return s.db.PersistOpenPositions(context.Background(), market, positions)
+}
+
+// LoadOpenPositions returns the stored open positions of the provided market.
+func (s *livePositionStore) LoadOpenPositions(market string) ([]*position.Position, error) {
+	return s.db.QueryOpenPositions(market)
+}
+
+// priceActionStore stores the price action state of markets in the state database.
+type priceActionStore struct {
+	db *database.SQLite
+}
+
+// Ensure the price action store implements the price action Store interface.
+var _ priceaction.Store = (*priceActionStore)(nil)
+
+// PersistState replaces the stored levels and imbalances of the provided market.
+func (s *priceActionStore) PersistState(market string, levels []*shared.Level, imbalances []*shared.Imbalance) error {
+	return s.db.PersistPriceActionState(context.Background(), market, levels, imbalances)
+}
+
+// LoadState returns the stored levels and imbalances of the provided market.
+func (s *priceActionStore) LoadState(market string) ([]*shared.Level, []*shared.Imbalance, error) {
+	return s.db.QueryPriceActionState(market)
+}
@@ -0,0 +1,110 @@
+package shared
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// High volume window (fx) in new york time (ET), the london and new york overlap.
+	FXHighVolumeWindowOpen  = "8:00"
+	FXHighVolumeWindowClose = "11:00"
+)
+
+// AssetClass represents the class of a market, it determines the sessions and high volume
+// window that apply to the market.
+type AssetClass int
+
+const (
+	// EquityIndex markets trade futures sessions and have the futures high volume window.
+	EquityIndex AssetClass = iota
+	// FX markets trade futures sessions and have the london and new york overlap as their
+	// high volume window.
+	FX
+	// Crypto markets trade around the clock and have no high volume window.
+	Crypto
+)
+
+// String stringifies the provided asset class.
+func (c AssetClass) String() string {
+	switch c {
+	case EquityIndex:
+		return "index"
+	case FX:
+		return "fx"
+	case Crypto:
+		return "crypto"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseAssetClass parses the asset class from the provided string. An empty string defaults
+// to EquityIndex.
+func ParseAssetClass(class string) (AssetClass, error) {
+	switch class {
+	case "", "index":
+		return EquityIndex, nil
+	case "fx":
+		return FX, nil
+	case "crypto":
+		return Crypto, nil
+	default:
+		return 0, fmt.Errorf("unknown asset class provided: %s", class)
+	}
+}
+
+// ParseAssetClasses parses per market asset classes from the provided market=class entries.
+func ParseAssetClasses(entries []string) (map[string]AssetClass, error) {
+	classes := make(map[string]AssetClass, len(entries))
+	for idx := range entries {
+		market, value, ok := strings.Cut(entries[idx], "=")
+		if !ok || market == "" {
+			return nil, fmt.Errorf("invalid asset class entry provided: %s", entries[idx])
+		}
+
+		class, err := ParseAssetClass(value)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s asset class: %v", market, err)
+		}
+
+		classes[market] = class
+	}
+
+	return classes, nil
+}
+
+// IsMarketOpen checks whether markets of the asset class are open at the provided time within
+// the provided sessions, the default session definitions are used if none are provided. Crypto
+// markets are always open.
+func (c AssetClass) IsMarketOpen(now time.Time, definitions []SessionDefinition) (bool, string, error) {
+	if c == Crypto {
+		name, _, err := CurrentSession(now, definitions)
+		if err != nil {
+			return false, name, fmt.Errorf("fetching current market session: %v", err)
+		}
+
+		return true, name, nil
+	}
+
+	return IsMarketOpen(now, definitions)
+}
+
+// InHighVolumeWindow checks whether the provided time is within the high volume window of
+// the asset class for the day.
+func (c AssetClass) InHighVolumeWindow(now time.Time) (bool, error) {
+	switch c {
+	case Crypto:
+		return false, nil
+	case FX:
+		window, err := NewSession("hvw", FXHighVolumeWindowOpen, FXHighVolumeWindowClose, now)
+		if err != nil {
+			return false, fmt.Errorf("creating fx high volume window session: %v", err)
+		}
+
+		return !now.Before(window.Open) && !now.After(window.Close), nil
+	default:
+		return InHighVolumeWindow(now, nil)
+	}
+}
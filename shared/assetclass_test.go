@@ -0,0 +1,96 @@
+package shared
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestParseAssetClass(t *testing.T) {
+	tests := []struct {
+		name    string
+		class   string
+		want    AssetClass
+		wantErr bool
+	}{
+		{"empty defaults to index", "", EquityIndex, false},
+		{"index", "index", EquityIndex, false},
+		{"fx", "fx", FX, false},
+		{"crypto", "crypto", Crypto, false},
+		{"unknown", "coin", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			class, err := ParseAssetClass(test.class)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, class, test.want)
+		})
+	}
+}
+
+func TestParseAssetClasses(t *testing.T) {
+	// Ensure market=class entries are parsed per market.
+	classes, err := ParseAssetClasses([]string{"^GSPC=index", "EURUSD=fx", "BTCUSD=crypto"})
+	assert.NoError(t, err)
+	assert.Equal(t, len(classes), 3)
+	assert.Equal(t, classes["^GSPC"], EquityIndex)
+	assert.Equal(t, classes["EURUSD"], FX)
+	assert.Equal(t, classes["BTCUSD"], Crypto)
+
+	// Ensure unclassified markets default to equity indices.
+	assert.Equal(t, classes["^IXIC"], EquityIndex)
+
+	// Ensure malformed entries and unknown classes error.
+	_, err = ParseAssetClasses([]string{"BTCUSD"})
+	assert.Error(t, err)
+	_, err = ParseAssetClasses([]string{"=crypto"})
+	assert.Error(t, err)
+	_, err = ParseAssetClasses([]string{"BTCUSD=coin"})
+	assert.Error(t, err)
+}
+
+func TestAssetClassSessions(t *testing.T) {
+	loc, err := time.LoadLocation(NewYorkLocation)
+	assert.NoError(t, err)
+
+	// Wednesday between the new york close and the asia open, the equity maintenance break.
+	maintenance := time.Date(2024, 1, 3, 17, 30, 0, 0, loc)
+	// Wednesday within the equity high volume window.
+	morning := time.Date(2024, 1, 3, 9, 0, 0, 0, loc)
+	// Wednesday within the london and new york overlap, before the equity high volume window.
+	overlap := time.Date(2024, 1, 3, 8, 15, 0, 0, loc)
+
+	tests := []struct {
+		name     string
+		class    AssetClass
+		now      time.Time
+		wantOpen bool
+		wantHVW  bool
+	}{
+		{"index closed during maintenance break", EquityIndex, maintenance, false, false},
+		{"index open in high volume window", EquityIndex, morning, true, true},
+		{"index outside high volume window in overlap", EquityIndex, overlap, true, false},
+		{"fx high volume window covers overlap", FX, overlap, true, true},
+		{"crypto open during maintenance break", Crypto, maintenance, true, false},
+		{"crypto has no high volume window", Crypto, morning, true, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			open, _, err := test.class.IsMarketOpen(test.now, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, open, test.wantOpen)
+
+			hvw, err := test.class.InHighVolumeWindow(test.now)
+			assert.NoError(t, err)
+			assert.Equal(t, hvw, test.wantHVW)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package shared
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"time"
@@ -13,16 +14,76 @@ const (
 	MinimumVolumeSpikePercent = 0.35
 	// pointsRangeLimit is the number of points from entry reasonable for a stop loss.
 	PointsRangeLimit = 12
-	// MinimumPinbarLongestWickPercent is the minimum percentage the longest wick of a pinbar can be.
+	// MinimumPinbarLongestWickPercent is the default minimum percentage the longest wick of a
+	// pinbar can be.
 	MinimumPinbarLongestWickPercent = 0.5
-	// MaximumDojiBodyPercent is the maximum body percentage for a doji.
+	// MaximumDojiBodyPercent is the default maximum body percentage for a doji.
 	MaximumDojiBodyPercent = 0.3
-	// MinimumDojiWickPercent is the minimum wick percent for a doji.
+	// MinimumDojiWickPercent is the default minimum wick percent for a doji.
 	MinimumDojiWickPercent = 0.3
-	// MinimumMarubozuBodyPercent is the minimum body percentage for a marubozu.
+	// MinimumMarubozuBodyPercent is the default minimum body percentage for a marubozu.
 	MinimumMarubozuBodyPercent = 0.7
+	// MinimumEngulfingBodyPercent is the default minimum body percentage for an engulfing
+	// candle.
+	MinimumEngulfingBodyPercent = 0.5
 )
 
+// ClassificationConfig represents the proportions of the candle range candle kinds are
+// classified by.
+type ClassificationConfig struct {
+	// MinPinbarLongestWickPercent is the minimum percentage of the range the longest wick of
+	// a pinbar covers.
+	MinPinbarLongestWickPercent float64
+	// MaxDojiBodyPercent is the maximum percentage of the range the body of a doji covers.
+	MaxDojiBodyPercent float64
+	// MinDojiWickPercent is the minimum percentage of the range both wicks of a doji cover.
+	MinDojiWickPercent float64
+	// MinMarubozuBodyPercent is the minimum percentage of the range the body of a marubozu
+	// covers.
+	MinMarubozuBodyPercent float64
+	// MinEngulfingBodyPercent is the minimum percentage of the range the body of an engulfing
+	// candle covers, weaker bodied engulfing setups are disqualified.
+	MinEngulfingBodyPercent float64
+}
+
+// DefaultClassificationConfig returns the default candle classification config.
+func DefaultClassificationConfig() ClassificationConfig {
+	return ClassificationConfig{
+		MinPinbarLongestWickPercent: MinimumPinbarLongestWickPercent,
+		MaxDojiBodyPercent:          MaximumDojiBodyPercent,
+		MinDojiWickPercent:          MinimumDojiWickPercent,
+		MinMarubozuBodyPercent:      MinimumMarubozuBodyPercent,
+		MinEngulfingBodyPercent:     MinimumEngulfingBodyPercent,
+	}
+}
+
+// Validate asserts the config sane inputs.
+func (c *ClassificationConfig) Validate() error {
+	var errs error
+
+	percents := []struct {
+		name  string
+		value float64
+	}{
+		{"pinbar longest wick", c.MinPinbarLongestWickPercent},
+		{"doji body", c.MaxDojiBodyPercent},
+		{"doji wick", c.MinDojiWickPercent},
+		{"marubozu body", c.MinMarubozuBodyPercent},
+		{"engulfing body", c.MinEngulfingBodyPercent},
+	}
+	for _, percent := range percents {
+		if percent.value <= 0 || percent.value > 1 {
+			errs = errors.Join(errs, fmt.Errorf("%s percent must be within (0, 1], got %.2f",
+				percent.name, percent.value))
+		}
+	}
+	if c.MaxDojiBodyPercent >= c.MinMarubozuBodyPercent {
+		errs = errors.Join(errs, fmt.Errorf("doji body percent must be below the marubozu body percent"))
+	}
+
+	return errs
+}
+
 // Momentum represents the momentum of a candlestick.
 type Momentum int
 
@@ -49,7 +110,8 @@ type Kind int
 
 const (
 	Marubozu Kind = iota
-	Pinbar
+	Hammer
+	ShootingStar
 	Doji
 	Unknown
 )
@@ -59,8 +121,10 @@ func (k Kind) String() string {
 	switch k {
 	case Marubozu:
 		return "marubozu"
-	case Pinbar:
-		return "pinbar"
+	case Hammer:
+		return "hammer"
+	case ShootingStar:
+		return "shooting star"
 	case Doji:
 		return "doji"
 	default:
@@ -68,6 +132,24 @@ func (k Kind) String() string {
 	}
 }
 
+// IsPinbar returns whether the candlestick kind is a pin bar.
+func (k Kind) IsPinbar() bool {
+	return k == Hammer || k == ShootingStar
+}
+
+// PinbarSentiment returns the sentiment a pin bar kind rejects price in favour of. A hammer
+// rejects lower prices and a shooting star rejects higher prices, all other kinds are neutral.
+func (k Kind) PinbarSentiment() Sentiment {
+	switch k {
+	case Hammer:
+		return Bullish
+	case ShootingStar:
+		return Bearish
+	default:
+		return Neutral
+	}
+}
+
 // Sentiment represents the candlestick sentiment.
 type Sentiment int
 
@@ -102,6 +184,9 @@ type Candlestick struct {
 	Market    string
 	Timeframe Timeframe
 	Status    chan StatusCode
+	// Closed indicates the candle is final. Candles that are not closed are updates of the
+	// forming bar and are replaced until it closes.
+	Closed bool
 }
 
 // FetchSentiment returns the provided candlestick's sentiment.
@@ -120,8 +205,8 @@ func (c *Candlestick) FetchSentiment() Sentiment {
 // FetchKind returns the candlestick type.
 //
 // Classifies the candle based on the closest match to the expected candle type
-// not a perfect textbook definition.
-func (c *Candlestick) FetchKind() Kind {
+// not a perfect textbook definition, using the proportions of the provided config.
+func (c *Candlestick) FetchKind(cfg ClassificationConfig) Kind {
 	if c.High == 0 || c.Low == 0 {
 		return Unknown
 	}
@@ -140,17 +225,21 @@ func (c *Candlestick) FetchKind() Kind {
 	lowerWickPercent := lowerWickRange / candleRange
 
 	switch {
-	case (upperWickPercent >= MinimumPinbarLongestWickPercent && upperWickPercent >= 2*lowerWickPercent) ||
-		(lowerWickPercent >= MinimumPinbarLongestWickPercent && lowerWickPercent >= 2*upperWickPercent):
-		// If the candle body has one of its wicks being at least 50 percent of the candle and it is
-		// at least twice the length of the opposite wick, it's a pin bar.
-		return Pinbar
-	case bodyPercent <= MaximumDojiBodyPercent && upperWickPercent >= MinimumDojiWickPercent && lowerWickPercent >= MinimumDojiWickPercent:
-		// If the candle body is not more than 30 percent of the candle and has almost
+	case lowerWickPercent >= cfg.MinPinbarLongestWickPercent && lowerWickPercent >= 2*upperWickPercent:
+		// If the candle's lower wick is at least 50 percent (by default) of the candle and twice the
+		// length of the upper wick, the body sits at the top of the range. It's a hammer.
+		return Hammer
+	case upperWickPercent >= cfg.MinPinbarLongestWickPercent && upperWickPercent >= 2*lowerWickPercent:
+		// If the candle's upper wick is at least 50 percent (by default) of the candle and twice the
+		// length of the lower wick, the body sits at the bottom of the range. It's a shooting star.
+		return ShootingStar
+	case bodyPercent <= cfg.MaxDojiBodyPercent && upperWickPercent >= cfg.MinDojiWickPercent && lowerWickPercent >= cfg.MinDojiWickPercent:
+		// If the candle body is not more than 30 percent (by default) of the candle and has almost
 		// identical wicks on both sides of it, it's a doji candle.
 		return Doji
-	case bodyPercent >= MinimumMarubozuBodyPercent:
-		// If the candle body accounts for over 70 percent of the candle, It is a marubozu candle.
+	case bodyPercent >= cfg.MinMarubozuBodyPercent:
+		// If the candle body accounts for over 70 percent (by default) of the candle, It is a
+		// marubozu candle.
 		return Marubozu
 	default:
 		return Unknown
@@ -173,6 +262,7 @@ func ParseCandlesticks(data []gjson.Result, market string, timeframe Timeframe,
 		candle.Market = market
 		candle.Timeframe = timeframe
 		candle.Status = make(chan StatusCode, 1)
+		candle.Closed = true
 
 		dt, err := time.ParseInLocation(DateLayout, data[idx].Get("date").String(), loc)
 		if err != nil {
@@ -202,6 +292,14 @@ func MarketDataKey(market string, timeframe string) string {
 	return fmt.Sprintf("%s-%s", market, timeframe)
 }
 
+// MarketJobTag creates a scheduled job tag from the provided component and market name.
+//
+// The job scheduler is shared across components, tagging jobs by component and market
+// allows a component to remove only the jobs it scheduled for a market.
+func MarketJobTag(component string, market string) string {
+	return fmt.Sprintf("%s:%s", component, market)
+}
+
 // GenerateMomentum returns the current candles momentum.
 func GenerateMomentum(current *Candlestick, prev *Candlestick) Momentum {
 	if current.Volume < 0 || prev.Volume < 0 || prev.Volume == 0 || current.Volume == 0 {
@@ -218,10 +316,11 @@ func GenerateMomentum(current *Candlestick, prev *Candlestick) Momentum {
 	}
 }
 
-// IsEngulfing detects whether the current candle engulfs the previous candle.
-func IsEngulfing(current *Candlestick, prev *Candlestick) bool {
-	currentKind := current.FetchKind()
-	prevKind := prev.FetchKind()
+// IsEngulfing detects whether the current candle engulfs the previous candle, classifying
+// them with the provided config.
+func IsEngulfing(current *Candlestick, prev *Candlestick, cfg ClassificationConfig) bool {
+	currentKind := current.FetchKind(cfg)
+	prevKind := prev.FetchKind(cfg)
 
 	if currentKind == Doji || prevKind == Doji {
 		// Exclude dojis from detecting engulfing candles.
@@ -238,7 +337,7 @@ func IsEngulfing(current *Candlestick, prev *Candlestick) bool {
 
 	if isBearishEngulf || isBullishEngulf {
 		bodyPercent := math.Abs(current.Close-current.Open) / (current.High - current.Low)
-		if bodyPercent < 0.5 {
+		if bodyPercent < cfg.MinEngulfingBodyPercent {
 			// Disqualify weak bodied engulfing setups.
 			return false
 		}
@@ -256,8 +355,10 @@ type CandleMetadata struct {
 	Momentum  Momentum
 	Volume    float64
 	Engulfing bool
+	Open      float64
 	High      float64
 	Low       float64
+	Close     float64
 	Date      time.Time
 }
 
@@ -274,7 +375,8 @@ func (m *CandleMetadata) Strength() uint32 {
 			score += 2
 		}
 	}
-	if m.Kind == Pinbar {
+	// Pin bars only signify strength in the direction they reject price towards.
+	if m.Kind.IsPinbar() && m.Kind.PinbarSentiment() == m.Sentiment {
 		switch m.Momentum {
 		case High:
 			score += 4
@@ -340,6 +442,47 @@ func CandleMetaRangeHighAndLow(meta []*CandleMetadata) (float64, float64) {
 	return high, low
 }
 
+// CandleMetaAverageWicks determines the average upper and lower wick lengths of the provided
+// range of candle metadata.
+func CandleMetaAverageWicks(meta []*CandleMetadata) (float64, float64) {
+	if len(meta) == 0 {
+		return 0, 0
+	}
+
+	var upper, lower float64
+	for idx := range meta {
+		candleMeta := meta[idx]
+		upper += candleMeta.High - math.Max(candleMeta.Open, candleMeta.Close)
+		lower += math.Min(candleMeta.Open, candleMeta.Close) - candleMeta.Low
+	}
+
+	count := float64(len(meta))
+	return upper / count, lower / count
+}
+
+// CandleMetaAverageTrueRange determines the average true range of the provided range of candle
+// metadata, the first candle's true range is its high to low range.
+func CandleMetaAverageTrueRange(meta []*CandleMetadata) float64 {
+	if len(meta) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for idx := range meta {
+		candleMeta := meta[idx]
+		trueRange := candleMeta.High - candleMeta.Low
+		if idx > 0 {
+			prevClose := meta[idx-1].Close
+			trueRange = max(trueRange, math.Abs(candleMeta.High-prevClose),
+				math.Abs(candleMeta.Low-prevClose))
+		}
+
+		sum += trueRange
+	}
+
+	return sum / float64(len(meta))
+}
+
 // AverageVolumeEntry represents an average volume entry.
 type AverageVolumeEntry struct {
 	Average   float64
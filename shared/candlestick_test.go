@@ -82,24 +82,34 @@ func TestFetchKind(t *testing.T) {
 			want: Marubozu,
 		},
 		{
-			name: "pinbar (bullish)",
+			name: "hammer (bullish)",
 			candle: Candlestick{
 				Open:  10,
 				Close: 15,
 				High:  17,
 				Low:   1,
 			},
-			want: Pinbar,
+			want: Hammer,
 		},
 		{
-			name: "pinbar (bearish)",
+			name: "hammer (bearish)",
+			candle: Candlestick{
+				Open:  15,
+				Close: 14,
+				High:  16,
+				Low:   6,
+			},
+			want: Hammer,
+		},
+		{
+			name: "shooting star (bearish)",
 			candle: Candlestick{
 				Open:  10,
 				Close: 7,
 				High:  17,
 				Low:   6,
 			},
-			want: Pinbar,
+			want: ShootingStar,
 		},
 		{
 			name: "unknown",
@@ -122,19 +132,19 @@ func TestFetchKind(t *testing.T) {
 			want: Unknown,
 		},
 		{
-			name: "pinbar (bullish)",
+			name: "shooting star (bullish)",
 			candle: Candlestick{
 				Open:  100,
 				Close: 110,
 				High:  140,
 				Low:   95,
 			},
-			want: Pinbar,
+			want: ShootingStar,
 		},
 	}
 
 	for _, test := range tests {
-		kind := test.candle.FetchKind()
+		kind := test.candle.FetchKind(DefaultClassificationConfig())
 		if kind != test.want {
 			t.Errorf("%s: expected %s kind, got %s",
 				test.name, kind.String(), test.want.String())
@@ -142,6 +152,66 @@ func TestFetchKind(t *testing.T) {
 	}
 }
 
+func TestClassificationConfig(t *testing.T) {
+	strict := DefaultClassificationConfig()
+	strict.MinPinbarLongestWickPercent = 0.7
+	loose := DefaultClassificationConfig()
+	loose.MinMarubozuBodyPercent = 0.6
+
+	tests := []struct {
+		name   string
+		candle Candlestick
+		cfg    ClassificationConfig
+		want   Kind
+	}{
+		{
+			name:   "hammer under the default config",
+			candle: Candlestick{Open: 100, Close: 102, High: 103, Low: 95},
+			cfg:    DefaultClassificationConfig(),
+			want:   Hammer,
+		},
+		{
+			name:   "hammer rejected under a strict pinbar config",
+			candle: Candlestick{Open: 100, Close: 102, High: 103, Low: 95},
+			cfg:    strict,
+			want:   Unknown,
+		},
+		{
+			name:   "short bodied candle under the default config",
+			candle: Candlestick{Open: 100, Close: 106.5, High: 108, Low: 98},
+			cfg:    DefaultClassificationConfig(),
+			want:   Unknown,
+		},
+		{
+			name:   "short bodied marubozu under a loose marubozu config",
+			candle: Candlestick{Open: 100, Close: 106.5, High: 108, Low: 98},
+			cfg:    loose,
+			want:   Marubozu,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Ensure the same candle is classified by the provided config.
+			assert.Equal(t, test.candle.FetchKind(test.cfg), test.want)
+		})
+	}
+
+	// Ensure the default config is valid.
+	cfg := DefaultClassificationConfig()
+	assert.NoError(t, cfg.Validate())
+
+	// Ensure percentages outside of the candle range are rejected.
+	cfg.MinPinbarLongestWickPercent = 0
+	cfg.MinDojiWickPercent = 1.2
+	assert.Error(t, cfg.Validate())
+
+	// Ensure doji bodies cannot overlap marubozu bodies.
+	cfg = DefaultClassificationConfig()
+	cfg.MaxDojiBodyPercent = 0.8
+	assert.Error(t, cfg.Validate())
+}
+
 func TestIsVolumeSpike(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -348,13 +418,46 @@ func TestIsEngulfing(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		engulfing := IsEngulfing(test.current, test.prev)
+		engulfing := IsEngulfing(test.current, test.prev, DefaultClassificationConfig())
 		if engulfing != test.want {
 			t.Errorf("%s: expected %v, got %v", test.name, test.want, engulfing)
 		}
 	}
 }
 
+func TestIsEngulfingBodyPercent(t *testing.T) {
+	// The current candle's body covers 55% of its range.
+	current := &Candlestick{Open: 4.75, Close: 7.5, Low: 3, High: 8}
+	prev := &Candlestick{Open: 7, Close: 5, Low: 4.5, High: 7.5}
+
+	strict := DefaultClassificationConfig()
+	strict.MinEngulfingBodyPercent = 0.6
+	loose := DefaultClassificationConfig()
+	loose.MinEngulfingBodyPercent = 0.4
+
+	tests := []struct {
+		name string
+		cfg  ClassificationConfig
+		want bool
+	}{
+		{"default threshold", DefaultClassificationConfig(), true},
+		{"loose threshold", loose, true},
+		{"strict threshold", strict, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Ensure borderline engulfing candles are only counted under thresholds they meet.
+			assert.Equal(t, IsEngulfing(current, prev, test.cfg), test.want)
+		})
+	}
+
+	// Ensure the engulfing body percent is validated.
+	invalid := DefaultClassificationConfig()
+	invalid.MinEngulfingBodyPercent = 0
+	assert.Error(t, invalid.Validate())
+}
+
 func TestMomentumString(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -403,9 +506,14 @@ func TestKindString(t *testing.T) {
 			"marubozu",
 		},
 		{
-			"pinbar kind",
-			Pinbar,
-			"pinbar",
+			"hammer kind",
+			Hammer,
+			"hammer",
+		},
+		{
+			"shooting star kind",
+			ShootingStar,
+			"shooting star",
 		},
 		{
 			"doji kind",
@@ -427,6 +535,26 @@ func TestKindString(t *testing.T) {
 	}
 }
 
+func TestKindPinbarSentiment(t *testing.T) {
+	tests := []struct {
+		name      string
+		kind      Kind
+		pinbar    bool
+		sentiment Sentiment
+	}{
+		{"hammer", Hammer, true, Bullish},
+		{"shooting star", ShootingStar, true, Bearish},
+		{"marubozu", Marubozu, false, Neutral},
+		{"doji", Doji, false, Neutral},
+		{"unknown", Unknown, false, Neutral},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.kind.IsPinbar(), test.pinbar)
+		assert.Equal(t, test.kind.PinbarSentiment(), test.sentiment)
+	}
+}
+
 func TestSentimentString(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -498,9 +626,9 @@ func TestCandlestickStrength(t *testing.T) {
 			0,
 		},
 		{
-			"pinbar candle - low strength",
+			"hammer candle - low strength",
 			CandleMetadata{
-				Kind:      Pinbar,
+				Kind:      Hammer,
 				Sentiment: Bullish,
 				Momentum:  Low,
 				Volume:    6,
@@ -512,9 +640,9 @@ func TestCandlestickStrength(t *testing.T) {
 			0,
 		},
 		{
-			"pinbar candle - medium strength",
+			"hammer candle - medium strength",
 			CandleMetadata{
-				Kind:      Pinbar,
+				Kind:      Hammer,
 				Sentiment: Bullish,
 				Momentum:  Medium,
 				Volume:    6,
@@ -526,9 +654,51 @@ func TestCandlestickStrength(t *testing.T) {
 			3,
 		},
 		{
-			"pinbar candle - high strength",
+			"hammer candle - high strength",
 			CandleMetadata{
-				Kind:      Pinbar,
+				Kind:      Hammer,
+				Sentiment: Bullish,
+				Momentum:  High,
+				Volume:    6,
+				Engulfing: false,
+				High:      9,
+				Low:       1,
+				Date:      time.Time{},
+			},
+			4,
+		},
+		{
+			"hammer candle - bearish sentiment",
+			CandleMetadata{
+				Kind:      Hammer,
+				Sentiment: Bearish,
+				Momentum:  High,
+				Volume:    6,
+				Engulfing: false,
+				High:      9,
+				Low:       1,
+				Date:      time.Time{},
+			},
+			0,
+		},
+		{
+			"shooting star candle - high strength",
+			CandleMetadata{
+				Kind:      ShootingStar,
+				Sentiment: Bearish,
+				Momentum:  High,
+				Volume:    6,
+				Engulfing: false,
+				High:      9,
+				Low:       1,
+				Date:      time.Time{},
+			},
+			4,
+		},
+		{
+			"shooting star candle - bullish sentiment",
+			CandleMetadata{
+				Kind:      ShootingStar,
 				Sentiment: Bullish,
 				Momentum:  Medium,
 				Volume:    6,
@@ -537,7 +707,7 @@ func TestCandlestickStrength(t *testing.T) {
 				Low:       1,
 				Date:      time.Time{},
 			},
-			3,
+			0,
 		},
 		{
 			"marubozu candle - low strength",
@@ -628,7 +798,7 @@ func TestFetchSignalCandle(t *testing.T) {
 			Date:      time.Time{},
 		},
 		{
-			Kind:      Pinbar,
+			Kind:      Hammer,
 			Sentiment: Bullish,
 			Momentum:  Medium,
 			Volume:    float64(4),
@@ -638,7 +808,7 @@ func TestFetchSignalCandle(t *testing.T) {
 			Date:      time.Time{},
 		},
 		{
-			Kind:      Pinbar,
+			Kind:      Hammer,
 			Sentiment: Bullish,
 			Momentum:  Medium,
 			Volume:    float64(4),
@@ -671,7 +841,7 @@ func TestFetchSignalCandle(t *testing.T) {
 			Date:      time.Time{},
 		},
 		{
-			Kind:      Pinbar,
+			Kind:      ShootingStar,
 			Sentiment: Bearish,
 			Momentum:  Medium,
 			Volume:    float64(4),
@@ -681,7 +851,7 @@ func TestFetchSignalCandle(t *testing.T) {
 			Date:      time.Time{},
 		},
 		{
-			Kind:      Pinbar,
+			Kind:      ShootingStar,
 			Sentiment: Bearish,
 			Momentum:  Medium,
 			Volume:    float64(4),
@@ -723,7 +893,7 @@ func TestCandleMetaRangeHighAndLow(t *testing.T) {
 			Date:      time.Time{},
 		},
 		{
-			Kind:      Pinbar,
+			Kind:      Hammer,
 			Sentiment: Bullish,
 			Momentum:  Medium,
 			Volume:    float64(4),
@@ -733,7 +903,7 @@ func TestCandleMetaRangeHighAndLow(t *testing.T) {
 			Date:      time.Time{},
 		},
 		{
-			Kind:      Pinbar,
+			Kind:      Hammer,
 			Sentiment: Bullish,
 			Momentum:  Medium,
 			Volume:    float64(4),
@@ -750,6 +920,38 @@ func TestCandleMetaRangeHighAndLow(t *testing.T) {
 	assert.Equal(t, low, float64(2))
 }
 
+func TestCandleMetaAverageWicks(t *testing.T) {
+	// Ensure an empty range has no wicks.
+	upper, lower := CandleMetaAverageWicks(nil)
+	assert.Equal(t, upper, float64(0))
+	assert.Equal(t, lower, float64(0))
+
+	meta := []*CandleMetadata{
+		{Open: 5, High: 8, Low: 2, Close: 6},
+		{Open: 6, High: 7, Low: 5, Close: 5.5},
+	}
+
+	// Ensure the average upper and lower wicks are measured from the candle bodies.
+	// upper: ((8 - 6) + (7 - 6)) / 2, lower: ((5 - 2) + (5.5 - 5)) / 2
+	upper, lower = CandleMetaAverageWicks(meta)
+	assert.Equal(t, upper, float64(1.5))
+	assert.Equal(t, lower, float64(1.75))
+}
+
+func TestCandleMetaAverageTrueRange(t *testing.T) {
+	// Ensure an empty range has no true range.
+	assert.Equal(t, CandleMetaAverageTrueRange(nil), float64(0))
+
+	meta := []*CandleMetadata{
+		{Open: 5, High: 8, Low: 2, Close: 6},
+		{Open: 9, High: 12, Low: 9, Close: 11},
+	}
+
+	// Ensure gaps from the previous close widen the true range.
+	// first: 8 - 2, second: max(12 - 9, |12 - 6|, |9 - 6|)
+	assert.Equal(t, CandleMetaAverageTrueRange(meta), float64(6))
+}
+
 func TestParseCandlesticks(t *testing.T) {
 	market := "^GSPC"
 	timeframe := FiveMinute
@@ -767,6 +969,7 @@ func TestParseCandlesticks(t *testing.T) {
 	assert.Equal(t, candles[0].High, float64(15))
 	assert.Equal(t, candles[0].Low, float64(8))
 	assert.Equal(t, candles[0].Volume, float64(5))
+	assert.True(t, candles[0].Closed)
 	assert.Equal(t, candles[0].Date.Year(), 2025)
 	assert.Equal(t, candles[0].Date.Month(), 2)
 	assert.Equal(t, candles[0].Date.Day(), 4)
@@ -3,6 +3,7 @@ package shared
 import (
 	"errors"
 	"fmt"
+	"math"
 	"sync"
 
 	"go.uber.org/atomic"
@@ -17,6 +18,8 @@ const (
 	OneHourSnapshotSize = 72
 	// minImbalanceRatioThreshold is the minimum imbalance ratio to be considered substantive
 	minImbalanceRatioThreshold = 0.24
+	// EqualLevelLookback is the number of candles searched for equal highs and lows.
+	EqualLevelLookback = 20
 )
 
 // CandlestickSnapshot represents a snapshot of candlestick data.
@@ -47,7 +50,9 @@ func NewCandlestickSnapshot(size int32, timeframe Timeframe) (*CandlestickSnapsh
 	return snapshot, nil
 }
 
-// Update adds the provided candlestick to the snapshot.
+// Update adds the provided candlestick to the snapshot. A candlestick with the same date as
+// the last entry replaces it as an update of the forming candle, candlesticks older than the
+// last entry are rejected.
 func (s *CandlestickSnapshot) Update(candle *Candlestick) error {
 	if candle.Timeframe != s.timeframe {
 		return fmt.Errorf("cannot update candlestick snapshot of timeframe %s "+
@@ -60,6 +65,20 @@ func (s *CandlestickSnapshot) Update(candle *Candlestick) error {
 	start := s.start.Load()
 	count := s.count.Load()
 	size := s.size.Load()
+
+	if count > 0 {
+		lastIdx := (start + count - 1) % size
+		last := s.data[lastIdx]
+		switch {
+		case candle.Date.Equal(last.Date):
+			s.data[lastIdx] = candle
+			return nil
+		case candle.Date.Before(last.Date):
+			return fmt.Errorf("cannot update candlestick snapshot with candle at %s "+
+				"older than the last candle at %s", candle.Date, last.Date)
+		}
+	}
+
 	end := (start + count) % size
 	s.data[end] = candle
 
@@ -89,6 +108,11 @@ func (s *CandlestickSnapshot) Last() *Candlestick {
 	return s.data[end]
 }
 
+// Count returns the number of candles in the snapshot.
+func (s *CandlestickSnapshot) Count() int32 {
+	return s.count.Load()
+}
+
 // LastN fetches the last n number of elements from the snapshot.
 func (s *CandlestickSnapshot) LastN(n int32) []*Candlestick {
 	s.dataMtx.RLock()
@@ -137,24 +161,56 @@ func (s *CandlestickSnapshot) AverageVolumeN(n int32) float64 {
 	return average
 }
 
-// DetectImbalance detects an imbalance through from the provided snapshot.
-func (s *CandlestickSnapshot) DetectImbalance() (*Imbalance, bool) {
-	// Three candles are needed to detect an imbalance.
-	candles := s.LastN(3)
-	if len(candles) < 3 {
+// VolumeStdDevN returns the standard deviation of volume for last n candles besides the most
+// recent one.
+func (s *CandlestickSnapshot) VolumeStdDevN(n int32) float64 {
+	candles := s.LastN(n + 1)
+
+	// Clamp the number of elements excpected if it is greater than the snapshot count.
+	count := s.count.Load()
+	if n > count {
+		n = count
+	}
+	if n == 0 {
+		return 0
+	}
+
+	average := s.AverageVolumeN(n)
+
+	var squaredSum float64
+	for idx := range candles[:n] {
+		diff := candles[idx].Volume - average
+		squaredSum += diff * diff
+	}
+
+	return math.Sqrt(squaredSum / float64(n))
+}
+
+// DetectImbalance detects an imbalance through from the provided snapshot using the provided
+// criteria, classifying displacement candles with the provided config.
+func (s *CandlestickSnapshot) DetectImbalance(criteria ImbalanceCriteria, classification ClassificationConfig) (*Imbalance, bool) {
+	// Three candles are needed to detect an imbalance, the candles before them are searched
+	// for the order block of the displacement.
+	set := s.LastN(3 + orderBlockLookback)
+	if len(set) < 3 {
 		return nil, false
 	}
 
+	// The candles up to and including the first of the three precede the displacement.
+	preceding := set[:len(set)-2]
+	candles := set[len(set)-3:]
+
 	avgVolume := s.AverageVolumeN(10)
 
 	firstCandle := candles[0]
 	secondCandle := candles[1]
 	thirdCandle := candles[2]
 
-	// An imbalance requires a displacement candle with above-average volume,
+	// An imbalance requires a displacement candle with sufficient volume,
 	// and the candle must be either a marubozu or a pinbar.
-	if (secondCandle.FetchKind() != Marubozu && secondCandle.FetchKind() != Pinbar) ||
-		secondCandle.Volume < avgVolume {
+	kind := secondCandle.FetchKind(classification)
+	if (kind != Marubozu && !kind.IsPinbar()) ||
+		secondCandle.Volume < avgVolume*criteria.VolumeMultiple {
 		return nil, false
 	}
 
@@ -169,9 +225,9 @@ func (s *CandlestickSnapshot) DetectImbalance() (*Imbalance, bool) {
 
 		gap := thirdCandle.Low - firstCandle.High
 
-		// A prominent imbalance should be at least 24% of the displacement candle.
+		// A prominent imbalance should be a substantive part of the displacement candle.
 		gapRatio := gap / displacementSize
-		if gapRatio < minImbalanceRatioThreshold {
+		if gapRatio < criteria.MinGapRatio || gap <= 0 || gap < criteria.MinGapSize {
 			return nil, false
 		}
 
@@ -181,6 +237,7 @@ func (s *CandlestickSnapshot) DetectImbalance() (*Imbalance, bool) {
 
 		imbalance := NewImbalance(firstCandle.Market, firstCandle.Timeframe, high, midpoint, low,
 			sentiment, gapRatio, thirdCandle.Date)
+		imbalance.OrderBlock = findOrderBlock(preceding, sentiment)
 
 		return imbalance, true
 
@@ -192,9 +249,9 @@ func (s *CandlestickSnapshot) DetectImbalance() (*Imbalance, bool) {
 
 		gap := firstCandle.Low - thirdCandle.High
 
-		// A prominent imbalance should be at least 24% of the displacement candle.
+		// A prominent imbalance should be a substantive part of the displacement candle.
 		gapRatio := gap / displacementSize
-		if gapRatio < minImbalanceRatioThreshold {
+		if gapRatio < criteria.MinGapRatio || gap <= 0 || gap < criteria.MinGapSize {
 			return nil, false
 		}
 
@@ -204,9 +261,60 @@ func (s *CandlestickSnapshot) DetectImbalance() (*Imbalance, bool) {
 
 		imbalance := NewImbalance(firstCandle.Market, firstCandle.Timeframe, high, midpoint,
 			low, sentiment, gapRatio, thirdCandle.Date)
+		imbalance.OrderBlock = findOrderBlock(preceding, sentiment)
 
 		return imbalance, true
 	}
 
 	return nil, false
 }
+
+// detectEqualExtreme detects whether the extreme of the most recent of the last n candles
+// is equal, within the provided tolerance percentage, to the extreme of an earlier candle.
+// Candles between the two must not exceed either extreme, and at least one candle must
+// separate them.
+//
+// The outermost of the two extremes is returned as the liquidity level.
+func (s *CandlestickSnapshot) detectEqualExtreme(n int32, tolerance float64, extreme func(c *Candlestick) float64, beyond func(a float64, b float64) bool) (float64, bool) {
+	candles := s.LastN(n)
+	if len(candles) < 3 || tolerance <= 0 {
+		return 0, false
+	}
+
+	last := extreme(candles[len(candles)-1])
+	between := extreme(candles[len(candles)-2])
+	for idx := len(candles) - 3; idx >= 0; idx-- {
+		current := extreme(candles[idx])
+		if beyond(current, between) && beyond(last, between) &&
+			math.Abs(current-last) <= math.Abs(last)*tolerance/100 {
+			if beyond(current, last) {
+				return current, true
+			}
+
+			return last, true
+		}
+
+		// Track the most extreme price of the candles between the compared candles.
+		if beyond(current, between) {
+			between = current
+		}
+	}
+
+	return 0, false
+}
+
+// DetectEqualHighs detects whether the high of the most recent of the last n candles
+// forms equal highs, within the provided tolerance percentage, with an earlier candle.
+func (s *CandlestickSnapshot) DetectEqualHighs(n int32, tolerance float64) (float64, bool) {
+	return s.detectEqualExtreme(n, tolerance,
+		func(c *Candlestick) float64 { return c.High },
+		func(a float64, b float64) bool { return a > b })
+}
+
+// DetectEqualLows detects whether the low of the most recent of the last n candles
+// forms equal lows, within the provided tolerance percentage, with an earlier candle.
+func (s *CandlestickSnapshot) DetectEqualLows(n int32, tolerance float64) (float64, bool) {
+	return s.detectEqualExtreme(n, tolerance,
+		func(c *Candlestick) float64 { return c.Low },
+		func(a float64, b float64) bool { return a < b })
+}
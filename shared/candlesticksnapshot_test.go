@@ -2,6 +2,7 @@ package shared
 
 import (
 	"testing"
+	"time"
 
 	"github.com/peterldowns/testy/assert"
 )
@@ -33,6 +34,7 @@ func TestCandlestickSnapshot(t *testing.T) {
 	assert.Nil(t, lastN)
 
 	// Ensure the snapshot can be updated with candles.
+	now, _ := time.Parse(time.RFC3339, "2010-09-18T14:00:00Z")
 	for idx := range size {
 		candle := &Candlestick{
 			Open:      float64(idx + 1),
@@ -42,6 +44,7 @@ func TestCandlestickSnapshot(t *testing.T) {
 			Volume:    float64(idx),
 			Status:    make(chan StatusCode, 1),
 			Timeframe: timeframe,
+			Date:      now.Add(time.Duration(idx) * time.Minute * 5),
 		}
 		err = candleSnapshot.Update(candle)
 		assert.NoError(t, err)
@@ -69,6 +72,7 @@ func TestCandlestickSnapshot(t *testing.T) {
 		Volume:    float64(2),
 		Status:    make(chan StatusCode, 1),
 		Timeframe: timeframe,
+		Date:      now.Add(time.Minute * 20),
 	}
 
 	err = candleSnapshot.Update(candle)
@@ -106,6 +110,10 @@ func TestCandlestickSnapshot(t *testing.T) {
 	average = candleSnapshot.AverageVolumeN(6)
 	assert.Equal(t, average, 2)
 
+	// Ensure the volume standard deviation n can be fetched from the snapshot.
+	deviation := candleSnapshot.VolumeStdDevN(2)
+	assert.Equal(t, deviation, 0.5)
+
 	// Ensure candle updates after capacity advances the start index for the next addition.
 	next := &Candlestick{
 		Open:      float64(6),
@@ -115,6 +123,7 @@ func TestCandlestickSnapshot(t *testing.T) {
 		Volume:    float64(3),
 		Status:    make(chan StatusCode, 1),
 		Timeframe: timeframe,
+		Date:      now.Add(time.Minute * 25),
 	}
 
 	err = candleSnapshot.Update(next)
@@ -137,9 +146,34 @@ func TestCandlestickSnapshot(t *testing.T) {
 
 	err = candleSnapshot.Update(wrongTimeframeCandle)
 	assert.Error(t, err)
+
+	// Ensure a repeated candle replaces the last entry instead of being appended.
+	repeat := *next
+	err = candleSnapshot.Update(&repeat)
+	assert.NoError(t, err)
+	assert.Equal(t, candleSnapshot.start.Load(), 2)
+	assert.Equal(t, candleSnapshot.AverageVolumeN(size), 2.5)
+
+	// Ensure an update of the forming candle replaces the last entry in place.
+	update := *next
+	update.Close = 11
+	update.Volume = 7
+	err = candleSnapshot.Update(&update)
+	assert.NoError(t, err)
+	assert.Equal(t, candleSnapshot.start.Load(), 2)
+	assert.Equal(t, candleSnapshot.Last().Close, float64(11))
+	assert.Equal(t, candleSnapshot.AverageVolumeN(size), 3.5)
+
+	// Ensure an out of order candle is rejected.
+	stale := *next
+	stale.Date = now.Add(time.Minute * 15)
+	err = candleSnapshot.Update(&stale)
+	assert.Error(t, err)
+	assert.Equal(t, candleSnapshot.Last().Close, float64(11))
 }
 
 func TestDetectImbalance(t *testing.T) {
+	now, _ := time.Parse(time.RFC3339, "2010-09-18T14:00:00Z")
 	size := int32(8)
 	timeframe := FiveMinute
 	market := "^GSPC"
@@ -321,10 +355,11 @@ func TestDetectImbalance(t *testing.T) {
 
 		for idx := range test.candles {
 			candle := test.candles[idx]
+			candle.Date = now.Add(time.Duration(idx) * time.Minute * 5)
 			snapshot.Update(&candle)
 		}
 
-		imbalance, ok := snapshot.DetectImbalance()
+		imbalance, ok := snapshot.DetectImbalance(DefaultImbalanceCriteria(), DefaultClassificationConfig())
 
 		if (!test.wantImbalance && ok) || (test.wantImbalance && !ok) {
 			t.Errorf("%s: expected %v, got %v", test.name, test.wantImbalance, ok)
@@ -349,3 +384,280 @@ func TestDetectImbalance(t *testing.T) {
 		}
 	}
 }
+
+func TestDetectImbalanceCriteria(t *testing.T) {
+	now, _ := time.Parse(time.RFC3339, "2010-09-18T14:00:00Z")
+	size := int32(8)
+	timeframe := FiveMinute
+	market := "^GSPC"
+
+	// bullishCandles creates a bullish displacement with the provided gap to the third candle
+	// and displacement candle volume.
+	bullishCandles := func(gap float64, volume float64) []Candlestick {
+		return []Candlestick{
+			{Market: market, Open: 15, Close: 17, High: 18, Low: 10, Volume: 2, Timeframe: timeframe},
+			{Market: market, Open: 17, Close: 24, High: 25, Low: 16, Volume: volume, Timeframe: timeframe},
+			{Market: market, Open: 24, Close: 27, High: 28, Low: 18 + gap, Volume: 2, Timeframe: timeframe},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		candles       []Candlestick
+		criteria      ImbalanceCriteria
+		wantImbalance bool
+	}{
+		{
+			name:          "small gap under default criteria",
+			candles:       bullishCandles(1, 7),
+			criteria:      DefaultImbalanceCriteria(),
+			wantImbalance: false,
+		},
+		{
+			name:          "small gap under loose gap ratio",
+			candles:       bullishCandles(1, 7),
+			criteria:      ImbalanceCriteria{MinGapRatio: 0.1, VolumeMultiple: 1},
+			wantImbalance: true,
+		},
+		{
+			name:          "gap under strict gap ratio",
+			candles:       bullishCandles(5, 7),
+			criteria:      ImbalanceCriteria{MinGapRatio: 0.8, VolumeMultiple: 1},
+			wantImbalance: false,
+		},
+		{
+			name:          "gap above minimum gap size",
+			candles:       bullishCandles(5, 7),
+			criteria:      ImbalanceCriteria{MinGapRatio: 0.24, MinGapSize: 5, VolumeMultiple: 1},
+			wantImbalance: true,
+		},
+		{
+			name:          "gap below minimum gap size",
+			candles:       bullishCandles(5, 7),
+			criteria:      ImbalanceCriteria{MinGapRatio: 0.24, MinGapSize: 6, VolumeMultiple: 1},
+			wantImbalance: false,
+		},
+		{
+			name:          "displacement volume below strict volume multiple",
+			candles:       bullishCandles(5, 7),
+			criteria:      ImbalanceCriteria{MinGapRatio: 0.24, VolumeMultiple: 2},
+			wantImbalance: false,
+		},
+		{
+			name:          "low volume displacement without a volume requirement",
+			candles:       bullishCandles(5, 1),
+			criteria:      ImbalanceCriteria{MinGapRatio: 0.24},
+			wantImbalance: true,
+		},
+		{
+			name:          "overlapping candles without a gap ratio",
+			candles:       bullishCandles(-1, 7),
+			criteria:      ImbalanceCriteria{VolumeMultiple: 1},
+			wantImbalance: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			snapshot, err := NewCandlestickSnapshot(size, timeframe)
+			assert.NoError(t, err)
+
+			for idx := range test.candles {
+				candle := test.candles[idx]
+				candle.Status = make(chan StatusCode, 1)
+				candle.Date = now.Add(time.Duration(idx) * time.Minute * 5)
+				snapshot.Update(&candle)
+			}
+
+			_, ok := snapshot.DetectImbalance(test.criteria, DefaultClassificationConfig())
+			assert.Equal(t, ok, test.wantImbalance)
+		})
+	}
+}
+
+func TestDetectEqualHighsLows(t *testing.T) {
+	now, _ := time.Parse(time.RFC3339, "2010-09-18T14:00:00Z")
+	type hl struct{ high, low float64 }
+
+	tests := []struct {
+		name      string
+		candles   []hl
+		tolerance float64
+		high      float64
+		highOk    bool
+		low       float64
+		lowOk     bool
+	}{
+		{
+			"double top equal highs",
+			[]hl{{100, 95}, {102, 97}, {99, 96}, {98, 94}, {101.98, 97}},
+			0.05,
+			102,
+			true,
+			0,
+			false,
+		},
+		{
+			"double bottom equal lows",
+			[]hl{{100, 92}, {99, 90}, {101, 93}, {100, 94}, {98, 90.02}},
+			0.05,
+			0,
+			false,
+			90,
+			true,
+		},
+		{
+			"non equal highs and lows",
+			[]hl{{100, 95}, {102, 97}, {99, 96}, {98, 94}, {101, 96}},
+			0.05,
+			0,
+			false,
+			0,
+			false,
+		},
+		{
+			"equal highs broken by a higher candle between",
+			[]hl{{102, 95}, {104, 97}, {101.99, 96}},
+			0.05,
+			0,
+			false,
+			0,
+			false,
+		},
+		{
+			"adjacent equal highs",
+			[]hl{{98, 95}, {102, 97}, {102, 96}},
+			0.05,
+			0,
+			false,
+			0,
+			false,
+		},
+		{
+			"detection disabled",
+			[]hl{{100, 95}, {102, 97}, {99, 96}, {98, 94}, {102, 97}},
+			0,
+			0,
+			false,
+			0,
+			false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			snapshot, err := NewCandlestickSnapshot(EqualLevelLookback, FiveMinute)
+			assert.NoError(t, err)
+
+			for idx := range test.candles {
+				err = snapshot.Update(&Candlestick{
+					Open:      test.candles[idx].low,
+					Close:     test.candles[idx].high,
+					High:      test.candles[idx].high,
+					Low:       test.candles[idx].low,
+					Market:    "^GSPC",
+					Timeframe: FiveMinute,
+					Date:      now.Add(time.Duration(idx) * time.Minute * 5),
+				})
+				assert.NoError(t, err)
+			}
+
+			high, ok := snapshot.DetectEqualHighs(EqualLevelLookback, test.tolerance)
+			assert.Equal(t, ok, test.highOk)
+			assert.Equal(t, high, test.high)
+
+			low, ok := snapshot.DetectEqualLows(EqualLevelLookback, test.tolerance)
+			assert.Equal(t, ok, test.lowOk)
+			assert.Equal(t, low, test.low)
+		})
+	}
+}
+
+func TestDetectOrderBlock(t *testing.T) {
+	now, _ := time.Parse(time.RFC3339, "2010-09-18T14:00:00Z")
+	size := int32(8)
+	timeframe := FiveMinute
+	market := "^GSPC"
+	criteria := ImbalanceCriteria{MinGapRatio: 0.24}
+
+	tests := []struct {
+		name           string
+		candles        []Candlestick
+		sentiment      Sentiment
+		wantOrderBlock bool
+		high           float64
+		low            float64
+		price          float64
+	}{
+		{
+			name: "bullish order block",
+			candles: []Candlestick{
+				{Market: market, Open: 16, Close: 13, High: 17, Low: 12, Volume: 2, Timeframe: timeframe},
+				{Market: market, Open: 13, Close: 15, High: 16, Low: 12, Volume: 2, Timeframe: timeframe},
+				{Market: market, Open: 15, Close: 17, High: 18, Low: 10, Volume: 2, Timeframe: timeframe},
+				{Market: market, Open: 17, Close: 24, High: 25, Low: 16, Volume: 7, Timeframe: timeframe},
+				{Market: market, Open: 24, Close: 27, High: 28, Low: 23, Volume: 2, Timeframe: timeframe},
+			},
+			sentiment:      Bullish,
+			wantOrderBlock: true,
+			high:           17,
+			low:            12,
+			price:          17,
+		},
+		{
+			name: "bearish order block",
+			candles: []Candlestick{
+				{Market: market, Open: 30, Close: 33, High: 34, Low: 29, Volume: 2, Timeframe: timeframe},
+				{Market: market, Open: 33, Close: 31, High: 35, Low: 30, Volume: 2, Timeframe: timeframe},
+				{Market: market, Open: 31, Close: 24, High: 32, Low: 23, Volume: 7, Timeframe: timeframe},
+				{Market: market, Open: 24, Close: 21, High: 25, Low: 20, Volume: 2, Timeframe: timeframe},
+			},
+			sentiment:      Bearish,
+			wantOrderBlock: true,
+			high:           34,
+			low:            29,
+			price:          29,
+		},
+		{
+			name: "no opposing candle before the displacement",
+			candles: []Candlestick{
+				{Market: market, Open: 13, Close: 15, High: 16, Low: 12, Volume: 2, Timeframe: timeframe},
+				{Market: market, Open: 15, Close: 17, High: 18, Low: 10, Volume: 2, Timeframe: timeframe},
+				{Market: market, Open: 17, Close: 24, High: 25, Low: 16, Volume: 7, Timeframe: timeframe},
+				{Market: market, Open: 24, Close: 27, High: 28, Low: 23, Volume: 2, Timeframe: timeframe},
+			},
+			sentiment:      Bullish,
+			wantOrderBlock: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			snapshot, err := NewCandlestickSnapshot(size, timeframe)
+			assert.NoError(t, err)
+
+			for idx := range test.candles {
+				candle := test.candles[idx]
+				candle.Status = make(chan StatusCode, 1)
+				candle.Date = now.Add(time.Duration(idx) * time.Minute * 5)
+				snapshot.Update(&candle)
+			}
+
+			// Ensure the order block of the displacement is detected with the imbalance.
+			imbalance, ok := snapshot.DetectImbalance(criteria, DefaultClassificationConfig())
+			assert.True(t, ok)
+			assert.Equal(t, imbalance.Sentiment, test.sentiment)
+			assert.Equal(t, imbalance.OrderBlock != nil, test.wantOrderBlock)
+			if !test.wantOrderBlock {
+				return
+			}
+
+			orderBlock := imbalance.OrderBlock
+			assert.Equal(t, orderBlock.Market, market)
+			assert.Equal(t, orderBlock.Sentiment, test.sentiment)
+			assert.Equal(t, orderBlock.High, test.high)
+			assert.Equal(t, orderBlock.Low, test.low)
+			assert.Equal(t, orderBlock.Price(), test.price)
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package shared
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Clock represents a source of the current time.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// WallClock is a clock reading the system time.
+type WallClock struct{}
+
+// Now returns the current system time.
+func (WallClock) Now() time.Time {
+	return time.Now()
+}
+
+// replayJob represents a daily job driven by a replay clock.
+type replayJob struct {
+	at   time.Duration
+	tag  string
+	job  func()
+	next time.Time
+}
+
+// ReplayClock is a clock advanced by replayed market data.
+//
+// Daily jobs scheduled against a replay clock run as replayed time crosses their time of
+// day, keeping backtests independent of wall-clock time.
+type ReplayClock struct {
+	now  time.Time
+	jobs []*replayJob
+	mtx  sync.Mutex
+}
+
+// NewReplayClock initializes a new replay clock starting at the provided time.
+func NewReplayClock(start time.Time) *ReplayClock {
+	return &ReplayClock{
+		now:  start,
+		jobs: make([]*replayJob, 0),
+	}
+}
+
+// Now returns the current replayed time.
+func (c *ReplayClock) Now() time.Time {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.now
+}
+
+// parseTimeOfDay parses the offset into the day of the provided HH:MM or HH:MM:SS time.
+func parseTimeOfDay(at string) (time.Duration, error) {
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		t, err := time.Parse(layout, at)
+		if err == nil {
+			return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+				time.Duration(t.Second())*time.Second, nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid time of day provided: %s", at)
+}
+
+// nextOccurrence returns the first occurrence of the provided time of day after the
+// provided time.
+func nextOccurrence(at time.Duration, after time.Time) time.Time {
+	year, month, day := after.Date()
+	next := time.Date(year, month, day, 0, 0, 0, 0, after.Location()).Add(at)
+	if !next.After(after) {
+		next = time.Date(year, month, day+1, 0, 0, 0, 0, after.Location()).Add(at)
+	}
+
+	return next
+}
+
+// ScheduleDaily schedules the provided job to run daily at the provided HH:MM or HH:MM:SS
+// time of day. The job first runs when the clock crosses its next scheduled time.
+func (c *ReplayClock) ScheduleDaily(at string, tag string, job func()) error {
+	offset, err := parseTimeOfDay(at)
+	if err != nil {
+		return err
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.jobs = append(c.jobs, &replayJob{
+		at:   offset,
+		tag:  tag,
+		job:  job,
+		next: nextOccurrence(offset, c.now),
+	})
+
+	return nil
+}
+
+// RemoveByTag removes all daily jobs with the provided tag.
+func (c *ReplayClock) RemoveByTag(tag string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	jobs := make([]*replayJob, 0, len(c.jobs))
+	for idx := range c.jobs {
+		if c.jobs[idx].tag != tag {
+			jobs = append(jobs, c.jobs[idx])
+		}
+	}
+
+	c.jobs = jobs
+}
+
+// Advance moves the clock forward to the provided time and runs the daily jobs scheduled
+// at or before it. Times before the current replayed time are ignored.
+func (c *ReplayClock) Advance(t time.Time) {
+	c.mtx.Lock()
+	if !t.After(c.now) {
+		c.mtx.Unlock()
+		return
+	}
+
+	c.now = t
+	due := make([]func(), 0)
+	for idx := range c.jobs {
+		job := c.jobs[idx]
+		if job.next.After(t) {
+			continue
+		}
+
+		due = append(due, job.job)
+		job.next = nextOccurrence(job.at, t)
+	}
+	c.mtx.Unlock()
+
+	// Jobs are run without holding the lock since they may read the clock.
+	for idx := range due {
+		due[idx]()
+	}
+}
+
+// ReplayMode represents how replayed historic candles are paced.
+type ReplayMode int
+
+const (
+	// InstantReplay emits historic candles as fast as they are processed.
+	InstantReplay ReplayMode = iota
+	// DelayedReplay emits historic candles at a fixed delay.
+	DelayedReplay
+	// ScaledReplay emits historic candles at their session timing scaled by the replay speed.
+	ScaledReplay
+)
+
+// String stringifies the provided replay mode.
+func (m ReplayMode) String() string {
+	switch m {
+	case InstantReplay:
+		return "instant"
+	case DelayedReplay:
+		return "delayed"
+	case ScaledReplay:
+		return "scaled"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseReplayMode parses the replay mode from the provided string. An empty string defaults
+// to InstantReplay.
+func ParseReplayMode(mode string) (ReplayMode, error) {
+	switch mode {
+	case "", "instant":
+		return InstantReplay, nil
+	case "delayed":
+		return DelayedReplay, nil
+	case "scaled":
+		return ScaledReplay, nil
+	default:
+		return 0, fmt.Errorf("unknown replay mode provided: %s", mode)
+	}
+}
@@ -0,0 +1,81 @@
+package shared
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestReplayClock(t *testing.T) {
+	loc, err := time.LoadLocation(NewYorkLocation)
+	assert.NoError(t, err)
+
+	start := time.Date(2025, 5, 1, 23, 50, 0, 0, loc)
+	clock := NewReplayClock(start)
+	assert.Equal(t, clock.Now(), start)
+
+	// Ensure invalid times of day cannot be scheduled.
+	err = clock.ScheduleDaily("25:00", "job", func() {})
+	assert.Error(t, err)
+
+	runs := make([]time.Time, 0)
+	err = clock.ScheduleDaily(SessionGenerationTime, "job", func() {
+		runs = append(runs, clock.Now())
+	})
+	assert.NoError(t, err)
+
+	// Ensure advancing the clock before the scheduled time does not run the job.
+	clock.Advance(start.Add(time.Minute * 5))
+	assert.Equal(t, len(runs), 0)
+
+	// Ensure the clock cannot be moved backwards.
+	clock.Advance(start)
+	assert.Equal(t, clock.Now(), start.Add(time.Minute*5))
+
+	// Ensure the job runs once the clock crosses the scheduled time.
+	crossed := time.Date(2025, 5, 2, 0, 5, 0, 0, loc)
+	clock.Advance(crossed)
+	assert.Equal(t, len(runs), 1)
+	assert.Equal(t, runs[0], crossed)
+
+	// Ensure the job does not run again until the next day.
+	clock.Advance(crossed.Add(time.Hour * 12))
+	assert.Equal(t, len(runs), 1)
+	clock.Advance(crossed.Add(time.Hour * 24))
+	assert.Equal(t, len(runs), 2)
+
+	// Ensure removed jobs no longer run.
+	clock.RemoveByTag("job")
+	clock.Advance(crossed.Add(time.Hour * 48))
+	assert.Equal(t, len(runs), 2)
+}
+
+func TestParseReplayMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		want    ReplayMode
+		wantErr bool
+	}{
+		{"empty defaults to instant", "", InstantReplay, false},
+		{"instant", "instant", InstantReplay, false},
+		{"delayed", "delayed", DelayedReplay, false},
+		{"scaled", "scaled", ScaledReplay, false},
+		{"unknown", "slow", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mode, err := ParseReplayMode(test.mode)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, mode, test.want)
+			assert.Equal(t, mode.String(), test.mode+map[bool]string{true: "instant"}[test.mode == ""])
+		})
+	}
+}
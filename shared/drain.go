@@ -0,0 +1,40 @@
+package shared
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultDrainGracePeriod is the default time components spend draining buffered signals
+	// and in-flight work on shutdown.
+	DefaultDrainGracePeriod = TimeoutDuration * 2
+)
+
+// NewDrainContext returns a context bounded by the provided grace period, detached from the
+// cancellation of the provided context. The default grace period is used if zero.
+func NewDrainContext(ctx context.Context, grace time.Duration) (context.Context, context.CancelFunc) {
+	if grace == 0 {
+		grace = DefaultDrainGracePeriod
+	}
+
+	return context.WithTimeout(context.WithoutCancel(ctx), grace)
+}
+
+// Await waits for the provided wait group to complete. It returns false if the provided
+// context is done first.
+func Await(ctx context.Context, wg *sync.WaitGroup) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
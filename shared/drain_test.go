@@ -0,0 +1,51 @@
+package shared
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestNewDrainContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Ensure the drain context is detached from the cancellation of its parent.
+	drainCtx, drainCancel := NewDrainContext(ctx, time.Millisecond*20)
+	defer drainCancel()
+	assert.Equal(t, drainCtx.Err(), nil)
+
+	// Ensure the drain context is done once the grace period elapses.
+	<-drainCtx.Done()
+	assert.Equal(t, drainCtx.Err(), context.DeadlineExceeded)
+
+	// Ensure the default grace period is used if zero.
+	defaultCtx, defaultCancel := NewDrainContext(ctx, 0)
+	defer defaultCancel()
+	deadline, ok := defaultCtx.Deadline()
+	assert.True(t, ok)
+	assert.True(t, time.Until(deadline) > DefaultDrainGracePeriod-time.Second)
+}
+
+func TestAwait(t *testing.T) {
+	var wg sync.WaitGroup
+
+	// Ensure awaiting a completed wait group returns true.
+	assert.True(t, Await(context.Background(), &wg))
+
+	// Ensure awaiting a pending wait group returns false once the context is done.
+	wg.Add(1)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+	assert.False(t, Await(ctx, &wg))
+
+	// Ensure awaiting a wait group returns true once it completes.
+	go func() {
+		time.Sleep(time.Millisecond * 10)
+		wg.Done()
+	}()
+	assert.True(t, Await(context.Background(), &wg))
+}
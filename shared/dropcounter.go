@@ -0,0 +1,44 @@
+package shared
+
+import "sync"
+
+// DropCounter counts the signals dropped by channels at capacity, keyed by channel.
+type DropCounter struct {
+	counts map[string]uint64
+	mtx    sync.Mutex
+}
+
+// NewDropCounter initializes a new drop counter.
+func NewDropCounter() *DropCounter {
+	return &DropCounter{
+		counts: make(map[string]uint64),
+	}
+}
+
+// Drop records a signal dropped by the provided channel.
+func (c *DropCounter) Drop(channel string) {
+	c.mtx.Lock()
+	c.counts[channel]++
+	c.mtx.Unlock()
+}
+
+// Count returns the number of signals dropped by the provided channel.
+func (c *DropCounter) Count(channel string) uint64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.counts[channel]
+}
+
+// Counts returns a copy of the dropped signal counts keyed by channel.
+func (c *DropCounter) Counts() map[string]uint64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	counts := make(map[string]uint64, len(c.counts))
+	for channel, count := range c.counts {
+		counts[channel] = count
+	}
+
+	return counts
+}
@@ -0,0 +1,29 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestDropCounter(t *testing.T) {
+	counter := NewDropCounter()
+
+	// Ensure channels without drops report none.
+	assert.Equal(t, counter.Count("level"), uint64(0))
+	assert.Equal(t, len(counter.Counts()), 0)
+
+	// Ensure drops are counted per channel.
+	for range 3 {
+		counter.Drop("level")
+	}
+	counter.Drop("market update")
+	assert.Equal(t, counter.Count("level"), uint64(3))
+	assert.Equal(t, counter.Count("market update"), uint64(1))
+
+	// Ensure the returned counts are a copy.
+	counts := counter.Counts()
+	assert.Equal(t, counts, map[string]uint64{"level": 3, "market update": 1})
+	counts["level"] = 0
+	assert.Equal(t, counter.Count("level"), uint64(3))
+}
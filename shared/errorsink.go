@@ -0,0 +1,21 @@
+package shared
+
+// errorSinkDropKey is the drop counter key of errors dropped by a full error sink.
+const errorSinkDropKey = "error sink"
+
+// ReportError relays the provided error to the provided sink without blocking. Errors are
+// counted as dropped by the provided counter if the sink is at capacity. A nil sink is
+// ignored.
+func ReportError(sink chan<- error, err error, drops *DropCounter) {
+	if sink == nil || err == nil {
+		return
+	}
+
+	select {
+	case sink <- err:
+	default:
+		if drops != nil {
+			drops.Drop(errorSinkDropKey)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package shared
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestReportError(t *testing.T) {
+	drops := NewDropCounter()
+
+	// Ensure reporting to a nil sink is a no-op.
+	ReportError(nil, errors.New("ignored"), drops)
+	assert.Equal(t, drops.Count(errorSinkDropKey), uint64(0))
+
+	// Ensure errors are relayed to a sink with capacity.
+	sink := make(chan error, 1)
+	err := errors.New("handler failed")
+	ReportError(sink, err, drops)
+	assert.Equal(t, len(sink), 1)
+
+	// Ensure nil errors are not relayed.
+	ReportError(sink, nil, drops)
+	assert.Equal(t, len(sink), 1)
+
+	// Ensure errors are dropped without blocking when the sink is at capacity.
+	ReportError(sink, errors.New("dropped"), drops)
+	assert.Equal(t, drops.Count(errorSinkDropKey), uint64(1))
+	assert.True(t, <-sink == err)
+}
@@ -1,6 +1,7 @@
 package shared
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"slices"
@@ -12,6 +13,14 @@ import (
 	"github.com/tidwall/gjson"
 )
 
+const (
+	// maxReportedDataIssues is the maximum number of malformed historic data rows reported.
+	maxReportedDataIssues = 5
+)
+
+// historicCandleFields are the fields required of each historic data candle.
+var historicCandleFields = []string{"open", "high", "low", "close", "volume", "date"}
+
 // HistoricDataConfig represents the historic data source configuration.
 type HistoricDataConfig struct {
 	// FilePath is the filepath to the historic market data.
@@ -20,10 +29,43 @@ type HistoricDataConfig struct {
 	SignalCaughtUp func(signal CaughtUpSignal)
 	// SendMarketUpdate relays the provided market update to all subscribers.
 	NotifySubscribers func(candle Candlestick) error
+	// ReplayMode is how replayed candles are paced.
+	ReplayMode ReplayMode
+	// ReplayDelay is the fixed delay between replayed candles for delayed replays.
+	ReplayDelay time.Duration
+	// ReplaySpeed is the multiple of wall-clock session timing scaled replays run at.
+	ReplaySpeed float64
+	// SortUnordered is the flag for sorting historic data with out of order candles instead
+	// of rejecting it.
+	SortUnordered bool
+	// Sessions are the daily sessions replayed, the close of the session replay starts in
+	// signals markets have caught up. The default sessions are used if empty.
+	Sessions []SessionDefinition
 	// Logger represents the application logger.
 	Logger *zerolog.Logger
 }
 
+// Validate asserts the config sane inputs.
+func (cfg *HistoricDataConfig) Validate() error {
+	var errs error
+
+	switch cfg.ReplayMode {
+	case InstantReplay:
+	case DelayedReplay:
+		if cfg.ReplayDelay <= 0 {
+			errs = errors.Join(errs, fmt.Errorf("replay delay must be greater than zero for delayed replays"))
+		}
+	case ScaledReplay:
+		if cfg.ReplaySpeed <= 0 {
+			errs = errors.Join(errs, fmt.Errorf("replay speed must be greater than zero for scaled replays"))
+		}
+	default:
+		errs = errors.Join(errs, fmt.Errorf("unknown replay mode provided: %s", cfg.ReplayMode.String()))
+	}
+
+	return errs
+}
+
 // HistoricData represents historic market data.
 type HistoricData struct {
 	cfg        *HistoricDataConfig
@@ -34,6 +76,7 @@ type HistoricData struct {
 	timeframes []string
 	startTime  time.Time
 	endTime    time.Time
+	clock      *ReplayClock
 }
 
 // loadHistoricData loads the historic data bytes from the provided file path.
@@ -48,14 +91,109 @@ func loadHistoricData(filepath string) (*gjson.Result, error) {
 	return &b, nil
 }
 
+// validateHistoricRows asserts the provided historic data rows have all required fields and
+// are consistent with the market and timeframe they are loaded for.
+func validateHistoricRows(rows []gjson.Result, market string, timeframe Timeframe) []string {
+	issues := make([]string, 0)
+
+	for idx := range rows {
+		row := rows[idx]
+
+		missing := make([]string, 0)
+		for _, field := range historicCandleFields {
+			if !row.Get(field).Exists() {
+				missing = append(missing, field)
+			}
+		}
+		if len(missing) > 0 {
+			issues = append(issues, fmt.Sprintf("%s row %d: missing fields %s", timeframe.String(),
+				idx, strings.Join(missing, ",")))
+			continue
+		}
+
+		if rowMarket := row.Get("market"); rowMarket.Exists() && rowMarket.String() != market {
+			issues = append(issues, fmt.Sprintf("%s row %d: market %s does not match %s",
+				timeframe.String(), idx, rowMarket.String(), market))
+		}
+		if rowTimeframe := row.Get("timeframe"); rowTimeframe.Exists() && rowTimeframe.String() != timeframe.String() {
+			issues = append(issues, fmt.Sprintf("%s row %d: timeframe %s does not match %s",
+				timeframe.String(), idx, rowTimeframe.String(), timeframe.String()))
+		}
+	}
+
+	return issues
+}
+
+// validateHistoricCandles asserts the provided parsed historic data candles are well-formed,
+// returning the issues found and the number of out of order candles. Out of order candles are
+// only reported as issues if they are not to be sorted.
+func validateHistoricCandles(candles []Candlestick, timeframe Timeframe, allowUnordered bool) ([]string, int) {
+	issues := make([]string, 0)
+	var unordered int
+
+	for idx := range candles {
+		candle := candles[idx]
+
+		if candle.High <= 0 || candle.High < candle.Low {
+			issues = append(issues, fmt.Sprintf("%s row %d: high %.2f must be positive and not below low %.2f",
+				timeframe.String(), idx, candle.High, candle.Low))
+		}
+		if candle.Volume < 0 {
+			issues = append(issues, fmt.Sprintf("%s row %d: volume %.2f cannot be negative",
+				timeframe.String(), idx, candle.Volume))
+		}
+		if idx == 0 {
+			continue
+		}
+
+		prev := candles[idx-1].Date
+		switch {
+		case candle.Date.Equal(prev):
+			issues = append(issues, fmt.Sprintf("%s row %d: date %s duplicates the previous row",
+				timeframe.String(), idx, candle.Date.Format(DateLayout)))
+		case candle.Date.Before(prev):
+			unordered++
+			if !allowUnordered {
+				issues = append(issues, fmt.Sprintf("%s row %d: date %s is before the previous row date %s",
+					timeframe.String(), idx, candle.Date.Format(DateLayout), prev.Format(DateLayout)))
+			}
+		}
+	}
+
+	return issues, unordered
+}
+
+// historicDataIssuesError returns an error listing the first few of the provided historic data issues.
+func historicDataIssuesError(issues []string) error {
+	reported := issues
+	if len(reported) > maxReportedDataIssues {
+		reported = reported[:maxReportedDataIssues]
+	}
+
+	msg := strings.Join(reported, "; ")
+	if len(issues) > len(reported) {
+		msg = fmt.Sprintf("%s; and %d more", msg, len(issues)-len(reported))
+	}
+
+	return fmt.Errorf("malformed historic data: %s", msg)
+}
+
 // NewhistoricData initializes a new historic data source.
 func NewHistoricData(cfg *HistoricDataConfig) (*HistoricData, error) {
+	err := cfg.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("validating historic data config: %v", err)
+	}
+
 	b, err := loadHistoricData(cfg.FilePath)
 	if err != nil {
 		return nil, fmt.Errorf("loading historic data: %v", err)
 	}
 
 	market := b.Get("market").String()
+	if market == "" {
+		return nil, fmt.Errorf("no market found in historic data")
+	}
 
 	loc, err := time.LoadLocation(NewYorkLocation)
 	if err != nil {
@@ -68,6 +206,7 @@ func NewHistoricData(cfg *HistoricDataConfig) (*HistoricData, error) {
 		location: loc,
 	}
 
+	issues := make([]string, 0)
 	timeframes := []Timeframe{OneMinute, FiveMinute, OneHour}
 	for idx := range timeframes {
 		timeframe := timeframes[idx]
@@ -77,15 +216,35 @@ func NewHistoricData(cfg *HistoricDataConfig) (*HistoricData, error) {
 			continue
 		}
 
+		rowIssues := validateHistoricRows(data, market, timeframe)
+		if len(rowIssues) > 0 {
+			issues = append(issues, rowIssues...)
+			continue
+		}
+
 		candles, err := ParseCandlesticks(data, market, timeframe, loc)
 		if err != nil {
 			return nil, fmt.Errorf("parsing candlesticks: %v", err)
 		}
 
+		tfIssues, unordered := validateHistoricCandles(candles, timeframe, cfg.SortUnordered)
+		issues = append(issues, tfIssues...)
+		if unordered > 0 && cfg.SortUnordered {
+			cfg.Logger.Warn().Msgf("sorting %d out of order %s candles for %s historic data",
+				unordered, timeframe.String(), market)
+		}
+
 		historicData.timeframes = append(historicData.timeframes, timeframe.String())
 		historicData.candles = append(historicData.candles, candles...)
 	}
 
+	if len(issues) > 0 {
+		return nil, historicDataIssuesError(issues)
+	}
+	if len(historicData.candles) == 0 {
+		return nil, fmt.Errorf("no candles found in historic data")
+	}
+
 	// Sort the multi timeframe dats by the timestamp and timeframe.
 	slices.SortFunc(historicData.candles, func(a, b Candlestick) int {
 		switch {
@@ -113,6 +272,7 @@ func NewHistoricData(cfg *HistoricDataConfig) (*HistoricData, error) {
 
 	historicData.startTime = historicData.candles[0].Date
 	historicData.endTime = historicData.candles[len(historicData.candles)-1].Date
+	historicData.clock = NewReplayClock(historicData.startTime)
 
 	return &historicData, nil
 }
@@ -131,14 +291,23 @@ func (h *HistoricData) ProcessHistoricalData() error {
 		tfs, timeDiffInHours, first.Format(time.RFC1123), last.Format(time.RFC1123))
 
 	// Find the current session and use its close to determine when to signal the market has caught up.
-	_, currentSession, err := CurrentSession(first)
+	_, currentSession, err := CurrentSession(first, h.cfg.Sessions)
 	if err != nil {
 		return fmt.Errorf("fetching current session: %v", err)
 	}
+	if currentSession == nil {
+		return fmt.Errorf("no session found for the first candle @ %s", first.Format(time.RFC1123))
+	}
 
 	var caughtUp bool
 	for idx := range h.candles {
 		candle := h.candles[idx]
+		if idx > 0 {
+			h.pace(h.candles[idx-1].Date, candle.Date)
+		}
+
+		h.clock.Advance(candle.Date)
+
 		if candle.Date.After(currentSession.Close) && !caughtUp {
 			// Send a caught up signal immediately the current session closes.
 			sig := NewCaughtUpSignal(h.market)
@@ -158,6 +327,20 @@ func (h *HistoricData) ProcessHistoricalData() error {
 	return nil
 }
 
+// pace waits between replaying candles dated at the provided previous and next times
+// according to the replay mode.
+func (h *HistoricData) pace(prev time.Time, next time.Time) {
+	switch h.cfg.ReplayMode {
+	case DelayedReplay:
+		time.Sleep(h.cfg.ReplayDelay)
+	case ScaledReplay:
+		gap := next.Sub(prev)
+		if gap > 0 {
+			time.Sleep(time.Duration(float64(gap) / h.cfg.ReplaySpeed))
+		}
+	}
+}
+
 // FetchStartTime returns the start time of the loaded historical data.
 func (h *HistoricData) FetchStartTime() time.Time {
 	return h.startTime
@@ -168,6 +351,11 @@ func (h *HistoricData) FetchEndTime() time.Time {
 	return h.endTime
 }
 
+// FetchClock returns the replay clock advanced to the date of each replayed candle.
+func (h *HistoricData) FetchClock() *ReplayClock {
+	return h.clock
+}
+
 // FetchMarket returns the backtest market.
 func (h *HistoricData) FetchMarket() string {
 	return h.market
@@ -2,7 +2,12 @@ package shared
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/peterldowns/testy/assert"
 	"github.com/rs/zerolog/log"
@@ -71,3 +76,297 @@ func TestHistoricalData(t *testing.T) {
 	assert.Equal(t, candleCount.Load(), 12)
 	assert.Equal(t, caughUpCount.Load(), 1)
 }
+
+func TestHistoricDataSessions(t *testing.T) {
+	tests := []struct {
+		name     string
+		sessions []SessionDefinition
+		// wantCandles is the number of candles replayed before the caught up signal.
+		wantCandles int
+		wantErr     bool
+	}{
+		{"default sessions", nil, 6, false},
+		{"custom sessions", []SessionDefinition{{Name: "early", Open: "02:00", Close: "03:20"}}, 10, false},
+		{"uncovered first candle", []SessionDefinition{{Name: "late", Open: "04:00", Close: "05:00"}}, 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var candles int
+			caughtUpAt := -1
+			historicData, err := NewHistoricData(&HistoricDataConfig{
+				FilePath: "../testdata/historicdata.json",
+				SignalCaughtUp: func(signal CaughtUpSignal) {
+					caughtUpAt = candles
+					signal.Status <- Processed
+				},
+				NotifySubscribers: func(candle Candlestick) error {
+					candles++
+					return nil
+				},
+				Sessions: test.sessions,
+				Logger:   &log.Logger,
+			})
+			assert.NoError(t, err)
+
+			// Ensure markets are caught up once the session replay starts in closes.
+			err = historicData.ProcessHistoricalData()
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, caughtUpAt, test.wantCandles)
+		})
+	}
+}
+
+func TestHistoricDataConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     HistoricDataConfig
+		wantErr bool
+	}{
+		{"instant replay", HistoricDataConfig{}, false},
+		{"delayed replay", HistoricDataConfig{ReplayMode: DelayedReplay, ReplayDelay: time.Millisecond}, false},
+		{"delayed replay without delay", HistoricDataConfig{ReplayMode: DelayedReplay}, true},
+		{"scaled replay", HistoricDataConfig{ReplayMode: ScaledReplay, ReplaySpeed: 60}, false},
+		{"scaled replay without speed", HistoricDataConfig{ReplayMode: ScaledReplay}, true},
+		{"unknown replay mode", HistoricDataConfig{ReplayMode: ReplayMode(9)}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.cfg.Validate()
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestHistoricDataReplay(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       ReplayMode
+		delay      time.Duration
+		speed      float64
+		minElapsed time.Duration
+	}{
+		// 12 candles replayed with a fixed delay between them.
+		{"delayed", DelayedReplay, time.Millisecond * 2, 0, time.Millisecond * 22},
+		// 45 minutes of candles replayed at a minute per millisecond.
+		{"scaled", ScaledReplay, 0, float64(time.Minute / time.Millisecond), time.Millisecond * 45},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var historicData *HistoricData
+			replayed := make([]Candlestick, 0)
+			clockTimes := make([]time.Time, 0)
+			rollovers := make([]time.Time, 0)
+			caughtUp := make([]time.Time, 0)
+
+			cfg := &HistoricDataConfig{
+				FilePath: "../testdata/historicdata.json",
+				SignalCaughtUp: func(signal CaughtUpSignal) {
+					caughtUp = append(caughtUp, historicData.FetchClock().Now())
+					signal.Status <- Processed
+				},
+				NotifySubscribers: func(candle Candlestick) error {
+					replayed = append(replayed, candle)
+					clockTimes = append(clockTimes, historicData.FetchClock().Now())
+					return nil
+				},
+				ReplayMode:  test.mode,
+				ReplayDelay: test.delay,
+				ReplaySpeed: test.speed,
+				Logger:      &log.Logger,
+			}
+
+			var err error
+			historicData, err = NewHistoricData(cfg)
+			assert.NoError(t, err)
+
+			// Track the asia to london session rollover against the replay clock.
+			clock := historicData.FetchClock()
+			assert.Equal(t, clock.Now(), historicData.FetchStartTime())
+			err = clock.ScheduleDaily(LondonOpen, "rollover", func() {
+				rollovers = append(rollovers, clock.Now())
+			})
+			assert.NoError(t, err)
+
+			started := time.Now()
+			err = historicData.ProcessHistoricalData()
+			assert.NoError(t, err)
+
+			// Ensure the replay was paced by the replay mode.
+			assert.True(t, time.Since(started) >= test.minElapsed)
+
+			// Ensure candles are replayed in order with the clock advanced to each candle.
+			assert.Equal(t, len(replayed), 12)
+			for idx := range replayed {
+				assert.True(t, clockTimes[idx].Equal(replayed[idx].Date))
+				if idx > 0 {
+					assert.False(t, replayed[idx].Date.Before(replayed[idx-1].Date))
+				}
+			}
+			assert.Equal(t, clock.Now(), historicData.FetchEndTime())
+
+			// Ensure the session rollover job runs once, when replayed time reaches the
+			// london open, and the market is caught up once the asia session closes.
+			assert.Equal(t, len(rollovers), 1)
+			assert.Equal(t, rollovers[0].Format("15:04"), LondonOpen)
+			assert.Equal(t, len(caughtUp), 1)
+			assert.True(t, caughtUp[0].After(rollovers[0]))
+		})
+	}
+}
+
+// writeHistoricData writes the provided historic data json to a temporary file.
+func writeHistoricData(t *testing.T, data string) string {
+	path := filepath.Join(t.TempDir(), "historicdata.json")
+	err := os.WriteFile(path, []byte(data), 0600)
+	assert.NoError(t, err)
+	return path
+}
+
+func TestNewHistoricDataValidation(t *testing.T) {
+	outOfOrder := `{
+		"market": "^GSPC",
+		"5m": [
+			{"open": 30, "low": 26, "high": 36, "close": 28, "volume": 10, "date": "2025-05-01 02:50:00"},
+			{"open": 28, "low": 24, "high": 29, "close": 25, "volume": 12, "date": "2025-05-01 02:45:00"},
+			{"open": 25, "low": 22, "high": 27, "close": 26, "volume": 8, "date": "2025-05-01 02:55:00"}
+		]
+	}`
+	negativeVolume := `{
+		"market": "^GSPC",
+		"5m": [
+			{"open": 30, "low": 26, "high": 36, "close": 28, "volume": -10, "date": "2025-05-01 02:45:00"},
+			{"open": 28, "low": 24, "high": 29, "close": 25, "volume": 12, "date": "2025-05-01 02:50:00"}
+		]
+	}`
+	malformed := `{
+		"market": "^GSPC",
+		"5m": [
+			{"open": 30, "low": 26, "high": 36, "close": 28, "date": "2025-05-01 02:45:00"},
+			{"open": 28, "low": 30, "high": 29, "close": 25, "volume": 12, "date": "2025-05-01 02:50:00"},
+			{"open": 28, "low": 24, "high": 29, "close": 25, "volume": 12, "date": "2025-05-01 02:55:00", "market": "^IXIC"}
+		]
+	}`
+	duplicate := `{
+		"market": "^GSPC",
+		"5m": [
+			{"open": 30, "low": 26, "high": 36, "close": 28, "volume": 10, "date": "2025-05-01 02:45:00"},
+			{"open": 30, "low": 26, "high": 36, "close": 28, "volume": 10, "date": "2025-05-01 02:45:00"}
+		]
+	}`
+
+	var rows strings.Builder
+	for idx := range 8 {
+		if idx > 0 {
+			rows.WriteString(",")
+		}
+		rows.WriteString(fmt.Sprintf(`{"open": 30, "low": 26, "high": 36, "close": 28, "volume": -1, "date": "2025-05-01 02:%02d:00"}`, idx))
+	}
+	manyIssues := `{"market": "^GSPC", "5m": [` + rows.String() + `]}`
+
+	tests := []struct {
+		name     string
+		data     string
+		path     string
+		sort     bool
+		wantErr  []string
+		excluded []string
+	}{
+		{
+			name: "clean file",
+			path: "../testdata/historicdata.json",
+		},
+		{
+			name:    "out of order file",
+			data:    outOfOrder,
+			wantErr: []string{"5m row 1: date 2025-05-01 02:45:00 is before the previous row date 2025-05-01 02:50:00"},
+		},
+		{
+			name: "out of order file sorted",
+			data: outOfOrder,
+			sort: true,
+		},
+		{
+			name:    "negative volume file",
+			data:    negativeVolume,
+			sort:    true,
+			wantErr: []string{"5m row 0: volume -10.00 cannot be negative"},
+		},
+		{
+			name: "missing fields and inconsistent rows",
+			data: malformed,
+			wantErr: []string{
+				"5m row 0: missing fields volume",
+				"5m row 2: market ^IXIC does not match ^GSPC",
+			},
+		},
+		{
+			name:    "duplicate dates",
+			data:    duplicate,
+			sort:    true,
+			wantErr: []string{"5m row 1: date 2025-05-01 02:45:00 duplicates the previous row"},
+		},
+		{
+			name:     "many issues",
+			data:     manyIssues,
+			wantErr:  []string{"5m row 4: volume -1.00 cannot be negative", "and 3 more"},
+			excluded: []string{"5m row 5"},
+		},
+		{
+			name:    "missing market",
+			data:    `{"5m": []}`,
+			wantErr: []string{"no market found in historic data"},
+		},
+		{
+			name:    "no candles",
+			data:    `{"market": "^GSPC"}`,
+			wantErr: []string{"no candles found in historic data"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := test.path
+			if path == "" {
+				path = writeHistoricData(t, test.data)
+			}
+
+			historicData, err := NewHistoricData(&HistoricDataConfig{
+				FilePath:          path,
+				SignalCaughtUp:    func(signal CaughtUpSignal) {},
+				NotifySubscribers: func(candle Candlestick) error { return nil },
+				SortUnordered:     test.sort,
+				Logger:            &log.Logger,
+			})
+			if len(test.wantErr) > 0 {
+				assert.Error(t, err)
+				for _, want := range test.wantErr {
+					assert.True(t, strings.Contains(err.Error(), want))
+				}
+				for _, excluded := range test.excluded {
+					assert.False(t, strings.Contains(err.Error(), excluded))
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+
+			// Ensure loaded candles are chronologically ordered.
+			for idx := 1; idx < len(historicData.candles); idx++ {
+				assert.False(t, historicData.candles[idx].Date.Before(historicData.candles[idx-1].Date))
+			}
+		})
+	}
+}
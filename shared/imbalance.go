@@ -1,12 +1,50 @@
 package shared
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"go.uber.org/atomic"
 )
 
+// ImbalanceCriteria represents the criteria for detecting imbalances.
+type ImbalanceCriteria struct {
+	// MinGapRatio is the minimum ratio of the gap to the displacement candle's body.
+	MinGapRatio float64
+	// MinGapSize is the minimum absolute size of the gap. Gaps of any size qualify if zero.
+	MinGapSize float64
+	// VolumeMultiple is the multiple of the average volume the displacement candle's volume
+	// must reach. The volume of the displacement candle is not checked if zero.
+	VolumeMultiple float64
+}
+
+// DefaultImbalanceCriteria returns the default imbalance detection criteria.
+func DefaultImbalanceCriteria() ImbalanceCriteria {
+	return ImbalanceCriteria{
+		MinGapRatio:    minImbalanceRatioThreshold,
+		VolumeMultiple: 1,
+	}
+}
+
+// Validate asserts the criteria sane inputs.
+func (c *ImbalanceCriteria) Validate() error {
+	var errs error
+
+	if c.MinGapRatio < 0 {
+		errs = errors.Join(errs, fmt.Errorf("imbalance minimum gap ratio cannot be negative"))
+	}
+	if c.MinGapSize < 0 {
+		errs = errors.Join(errs, fmt.Errorf("imbalance minimum gap size cannot be negative"))
+	}
+	if c.VolumeMultiple < 0 {
+		errs = errors.Join(errs, fmt.Errorf("imbalance volume multiple cannot be negative"))
+	}
+
+	return errs
+}
+
 // Imbalance represents market inefficiencies created by displacement. These act as high
 // probability reaction levels for price.
 type Imbalance struct {
@@ -19,7 +57,11 @@ type Imbalance struct {
 	GapRatio    float64
 	Purged      atomic.Bool
 	Invalidated atomic.Bool
-	Date        time.Time
+	// Fill is the percentage of the imbalance's range price has penetrated.
+	Fill atomic.Float64
+	// OrderBlock is the order block preceding the displacement, nil if none was found.
+	OrderBlock *OrderBlockZone
+	Date       time.Time
 }
 
 // NewImbalance initializes a new imbalance.
@@ -37,6 +79,42 @@ func NewImbalance(market string, timeframe Timeframe, high float64, midpoint flo
 	}
 }
 
+// FillPercent returns the percentage of the imbalance's range price has penetrated. An
+// imbalance price has not traded into is fully open at 0, a purged imbalance is filled at 100.
+func (imb *Imbalance) FillPercent() float64 {
+	return imb.Fill.Load()
+}
+
+// updateFill updates the fill percentage of the imbalance with how far the provided candlestick
+// penetrated its range.
+func (imb *Imbalance) updateFill(candle *Candlestick) {
+	size := imb.High - imb.Low
+	if size <= 0 {
+		return
+	}
+
+	var penetration float64
+	switch imb.Sentiment {
+	case Bullish:
+		// Bullish imbalances are filled from their high down.
+		penetration = imb.High - candle.Low
+	case Bearish:
+		// Bearish imbalances are filled from their low up.
+		penetration = candle.High - imb.Low
+	}
+
+	fill := math.Min(math.Max(penetration/size, 0), 1) * 100
+	if fill > imb.Fill.Load() {
+		imb.Fill.Store(fill)
+	}
+}
+
+// Overlaps checks whether the provided imbalance shares sentiment with the imbalance and
+// their ranges overlap.
+func (imb *Imbalance) Overlaps(other *Imbalance) bool {
+	return imb.Sentiment == other.Sentiment && imb.Low <= other.High && other.Low <= imb.High
+}
+
 // Update updates the imbalance with the provided candstick.
 func (imb *Imbalance) Update(candle *Candlestick) {
 	purged := imb.Purged.Load()
@@ -46,6 +124,8 @@ func (imb *Imbalance) Update(candle *Candlestick) {
 		return
 	}
 
+	imb.updateFill(candle)
+
 	switch imb.Sentiment {
 	case Bullish:
 		// If the imbalance is bullish then price closing below the low
@@ -79,9 +159,9 @@ type ReactionAtImbalance struct {
 
 // NewReactionAtImbalance initializes a new reaction from the provided imbalance and candlestick data.
 func NewReactionAtImbalance(market string, imbalance *Imbalance, priceData []*Candlestick) (*ReactionAtImbalance, error) {
-	if len(priceData) != PriceDataPayloadSize {
-		return nil, fmt.Errorf("price data is not the expected size: %d != expected(%d)",
-			len(priceData), PriceDataPayloadSize)
+	if len(priceData) < MinReactionWindow {
+		return nil, fmt.Errorf("price data is below the minimum reaction window: %d < minimum(%d)",
+			len(priceData), MinReactionWindow)
 	}
 
 	var levelKind LevelKind
@@ -92,15 +172,20 @@ func NewReactionAtImbalance(market string, imbalance *Imbalance, priceData []*Ca
 		levelKind = Resistance
 	}
 
+	fill := imbalance.FillPercent()
 	ir := &ReactionAtImbalance{
 		ReactionAtFocus: ReactionAtFocus{
+			CorrelationID: NewCorrelationID(),
 			Market:        market,
+			Focus:         ImbalanceFocus,
 			LevelKind:     levelKind,
 			Timeframe:     priceData[len(priceData)-1].Timeframe,
 			PriceMovement: make([]PriceMovement, 0, len(priceData)),
+			TaggingCandle: priceData[0],
 			Status:        make(chan StatusCode, 1),
 			CurrentPrice:  priceData[len(priceData)-1].Close,
 			CreatedOn:     priceData[len(priceData)-1].Date,
+			ImbalanceFill: &fill,
 		},
 		Imbalance: imbalance,
 	}
@@ -137,24 +222,13 @@ func NewReactionAtImbalance(market string, imbalance *Imbalance, priceData []*Ca
 	}
 
 	// Generate a price reaction based on the price movement data.
-	var above, below uint32
-	for idx := range ir.PriceMovement {
-		switch {
-		case ir.PriceMovement[idx] == Above:
-			above++
-		case ir.PriceMovement[idx] == Below:
-			below++
-		}
-	}
+	above, below := countPriceMovement(ir.PriceMovement)
 
-	// The imbalance reaction is currently rooted in being able to make a decision
-	// on a reaction using 4 5-minute candles. Changing the data size would
-	// require reworking the logic here.
+	// The classification only relies on the window's first and last closes and its overall
+	// shape, it is not tied to a specific window length.
 
 	first := ir.PriceMovement[0]
-	second := ir.PriceMovement[1]
-	third := ir.PriceMovement[2]
-	fourth := ir.PriceMovement[3]
+	last := ir.PriceMovement[len(ir.PriceMovement)-1]
 
 	switch levelKind {
 	case Support:
@@ -166,22 +240,20 @@ func NewReactionAtImbalance(market string, imbalance *Imbalance, priceData []*Ca
 			// If price consistently stayed below a support imbalance it tagged then it
 			// it is likely reversing at the vwap.
 			ir.Reaction = Reversal
-		case first == Above && third == Below && fourth == Below:
+		case first == Above && closedConsistently(ir.PriceMovement, Below):
 			// If price was above an imbalance acting as support but starts to consistently close below it
 			// then it is likely breaking the imbalance.
 			ir.Reaction = Break
-		case first == Above && second == Above && third == Above && fourth == Below:
+		case turnedSharply(ir.PriceMovement, Above, Below):
 			// If price was above an imbalance acting as support but turns sharply to close below it then
 			// it is likely breaking the imbalance.
 			ir.Reaction = Break
-		case first == Above && below > 0 && fourth == Above:
+		case first == Above && below > 0 && last == Above:
 			// If price was above an imbalance acting as support level but closed below it briefly and
 			// pushed back above it then it is likely reversing at the imbalance.
 			ir.Reaction = Reversal
-		case first == Above && second == Below && third == Above && fourth == Below:
-			// If price is consistently closing aimlessly above and below an imbalance it is chopping.
-			ir.Reaction = Chop
 		default:
+			// If price is consistently closing aimlessly above and below an imbalance it is chopping.
 			ir.Reaction = Chop
 		}
 	case Resistance:
@@ -193,22 +265,20 @@ func NewReactionAtImbalance(market string, imbalance *Imbalance, priceData []*Ca
 			// If price consistently stayed below an imabalance acting as resistance it tagged then
 			// it is likely reversing at the imbalance.
 			ir.Reaction = Reversal
-		case first == Below && third == Above && fourth == Above:
+		case first == Below && closedConsistently(ir.PriceMovement, Above):
 			// If price was below an imbalance acting as resistance but starts to consistently close
 			// above it then it is likely breaking the imabalance.
 			ir.Reaction = Break
-		case first == Below && second == Below && third == Below && fourth == Above:
+		case turnedSharply(ir.PriceMovement, Below, Above):
 			// If price was below an imbalance acting as resistance but turns sharply to close above it
 			// then it is likely breaking the imbalance.
 			ir.Reaction = Break
-		case first == Below && above > 0 && fourth == Below:
+		case first == Below && above > 0 && last == Below:
 			// If price was below an imbalance acting as resistance but closed above it briefly and pushed
 			// back below it then it is likely breaking the imbalance.
 			ir.Reaction = Reversal
-		case first == Below && second == Above && third == Below && fourth == Above:
-			// If price is consistently closing aimlessly above and below an imbalance it is chopping.
-			ir.Reaction = Chop
 		default:
+			// If price is consistently closing aimlessly above and below an imbalance it is chopping.
 			ir.Reaction = Chop
 		}
 	}
@@ -8,6 +8,31 @@ import (
 	"github.com/peterldowns/testy/assert"
 )
 
+func TestImbalanceCriteriaValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		criteria ImbalanceCriteria
+		wantErr  bool
+	}{
+		{"default criteria", DefaultImbalanceCriteria(), false},
+		{"zero criteria", ImbalanceCriteria{}, false},
+		{"negative gap ratio", ImbalanceCriteria{MinGapRatio: -1}, true},
+		{"negative gap size", ImbalanceCriteria{MinGapSize: -1}, true},
+		{"negative volume multiple", ImbalanceCriteria{VolumeMultiple: -1}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.criteria.Validate()
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestImbalanceUpdate(t *testing.T) {
 	// Ensure an imbalance can be created.
 	market := "^GSPC"
@@ -57,6 +82,78 @@ func TestImbalanceUpdate(t *testing.T) {
 	assert.True(t, bullishImbalance.Invalidated.Load())
 }
 
+func TestImbalanceFillPercent(t *testing.T) {
+	market := "^GSPC"
+	timeframe := FiveMinute
+
+	// candle creates a candlestick with the provided high and low.
+	candle := func(high float64, low float64) *Candlestick {
+		return &Candlestick{
+			Market:    market,
+			Open:      high,
+			Close:     high,
+			High:      high,
+			Low:       low,
+			Timeframe: timeframe,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		sentiment Sentiment
+		candles   []*Candlestick
+		fill      float64
+	}{
+		{
+			name:      "untouched bullish imbalance",
+			sentiment: Bullish,
+			candles:   []*Candlestick{candle(30, 26)},
+			fill:      0,
+		},
+		{
+			name:      "partially filled bullish imbalance",
+			sentiment: Bullish,
+			candles:   []*Candlestick{candle(30, 23)},
+			fill:      25,
+		},
+		{
+			name:      "fully filled bullish imbalance",
+			sentiment: Bullish,
+			candles:   []*Candlestick{candle(30, 23), candle(28, 19)},
+			fill:      100,
+		},
+		{
+			name:      "partially filled bearish imbalance",
+			sentiment: Bearish,
+			candles:   []*Candlestick{candle(22, 15)},
+			fill:      50,
+		},
+		{
+			name:      "fully filled bearish imbalance",
+			sentiment: Bearish,
+			candles:   []*Candlestick{candle(26, 15)},
+			fill:      100,
+		},
+		{
+			name:      "fill is not reduced by shallower penetration",
+			sentiment: Bearish,
+			candles:   []*Candlestick{candle(22, 15), candle(21, 15)},
+			fill:      50,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			imb := NewImbalance(market, timeframe, 24, 22, 20, test.sentiment, 0.5, time.Time{})
+			for _, candle := range test.candles {
+				imb.Update(candle)
+			}
+
+			assert.Equal(t, imb.FillPercent(), test.fill)
+		})
+	}
+}
+
 func TestNewReactionAtImbalance(t *testing.T) {
 	price := float64(12)
 	market := "^GSPC"
@@ -596,6 +693,9 @@ func TestNewReactionAtImbalance(t *testing.T) {
 		}
 
 		if err == nil {
+			if reaction.CorrelationID == "" {
+				t.Errorf("%s: expected a correlation id", test.name)
+			}
 			if !cmp.Equal(test.wantPriceMovement, reaction.PriceMovement) {
 				t.Errorf("%s: expected movement %v, got %v", test.name, test.wantPriceMovement, reaction.PriceMovement)
 			}
@@ -605,3 +705,29 @@ func TestNewReactionAtImbalance(t *testing.T) {
 		}
 	}
 }
+
+func TestNewReactionAtImbalanceWindows(t *testing.T) {
+	price := float64(12)
+	market := "^GSPC"
+	timeframe := FiveMinute
+	gapRatio := float64(0.75)
+	bullishImbalance := NewImbalance(market, timeframe, price+4, price+2, price, Bullish, gapRatio, time.Time{})
+	bearishImbalance := NewImbalance(market, timeframe, price, price-2, price-4, Bearish, gapRatio, time.Time{})
+
+	// Ensure the same scenarios classify consistently across reaction windows.
+	for _, window := range []int{PriceDataPayloadSize, 6} {
+		for _, scenario := range reactionScenarios() {
+			movement := scenario.movement(window)
+
+			reaction, err := NewReactionAtImbalance(market, bullishImbalance, movementCandles(movement, bullishImbalance.Low))
+			assert.NoError(t, err)
+			assert.Equal(t, len(reaction.PriceMovement), window)
+			assert.Equal(t, reaction.Reaction, scenario.want)
+
+			reaction, err = NewReactionAtImbalance(market, bearishImbalance,
+				movementCandles(mirrorMovement(movement), bearishImbalance.High))
+			assert.NoError(t, err)
+			assert.Equal(t, reaction.Reaction, scenario.want)
+		}
+	}
+}
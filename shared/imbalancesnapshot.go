@@ -61,6 +61,9 @@ func (s *ImbalanceSnapshot) Add(imb *Imbalance) error {
 
 // Update applies the provided market update to all tracked levels.
 func (s *ImbalanceSnapshot) Update(candle *Candlestick) {
+	s.dataMtx.RLock()
+	defer s.dataMtx.RUnlock()
+
 	start := s.start.Load()
 	count := s.count.Load()
 	size := s.size.Load()
@@ -134,3 +137,34 @@ func (s *ImbalanceSnapshot) Filter(candle *Candlestick, fn func(*Imbalance, *Can
 
 	return levels
 }
+
+// Remove removes the imbalances matching the provided function from the snapshot, preserving
+// the order of the remaining imbalances. It returns the number of imbalances removed.
+func (s *ImbalanceSnapshot) Remove(fn func(*Imbalance) bool) int {
+	s.dataMtx.Lock()
+	defer s.dataMtx.Unlock()
+
+	start := s.start.Load()
+	count := s.count.Load()
+	size := s.size.Load()
+	kept := make([]*Imbalance, 0, count)
+	for i := range count {
+		imbalance := s.data[(start+i)%size]
+		if !fn(imbalance) {
+			kept = append(kept, imbalance)
+		}
+	}
+
+	// Compact the remaining imbalances to the start of the buffer.
+	clear(s.data)
+	copy(s.data, kept)
+	s.start.Store(0)
+	s.count.Store(int32(len(kept)))
+
+	return int(count) - len(kept)
+}
+
+// Imbalances returns the tracked imbalances, ordered from the oldest to the newest.
+func (s *ImbalanceSnapshot) Imbalances() []*Imbalance {
+	return s.LastN(s.count.Load())
+}
@@ -65,6 +65,11 @@ func TestImbalanceSnapshot(t *testing.T) {
 	lastN = imbalanceSnapshot.LastN(size + 1)
 	assert.Equal(t, len(lastN), int(size))
 
+	// Ensure all tracked imbalances can be fetched.
+	imbalances := imbalanceSnapshot.Imbalances()
+	assert.Equal(t, len(imbalances), int(size))
+	assert.True(t, imbalances[len(imbalances)-1] == imbalanceSnapshot.Last())
+
 	// Ensure the snapshot can  process market updates.
 	candle := &Candlestick{
 		Market:    market,
@@ -92,3 +97,29 @@ func TestImbalanceSnapshot(t *testing.T) {
 	imbalanceSet := imbalanceSnapshot.Filter(candle, filterFunc)
 	assert.GreaterThan(t, len(imbalanceSet), 0)
 }
+
+func TestImbalanceSnapshotRemove(t *testing.T) {
+	market := "^GSPC"
+	size := int32(3)
+	imbalanceSnapshot, err := NewImbalanceSnapshot(size)
+	assert.NoError(t, err)
+
+	// Wrap the snapshot so the start of the buffer is not at its first slot.
+	for _, low := range []float64{10, 20, 30, 40} {
+		imb := NewImbalance(market, FiveMinute, low+4, low+2, low, Bullish, 0.7, time.Time{})
+		err := imbalanceSnapshot.Add(imb)
+		assert.NoError(t, err)
+	}
+
+	// Ensure matching imbalances are removed while the rest keep their order.
+	removed := imbalanceSnapshot.Remove(func(imb *Imbalance) bool {
+		return imb.Low == 30
+	})
+	assert.Equal(t, removed, 1)
+
+	imbalances := imbalanceSnapshot.Imbalances()
+	assert.Equal(t, len(imbalances), 2)
+	assert.Equal(t, imbalances[0].Low, float64(20))
+	assert.Equal(t, imbalances[1].Low, float64(40))
+	assert.Equal(t, imbalanceSnapshot.Last().Low, float64(40))
+}
@@ -0,0 +1,121 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Instrument represents the contract specification of a market. The zero value describes a
+// market priced one-to-one in dollars with no tick size or minimum stop distance.
+type Instrument struct {
+	// PointValue is the dollar value of a one point move for a single unit. A one-to-one
+	// point value is used if zero.
+	PointValue float64
+	// TickSize is the minimum price increment of the market. Prices are not rounded if zero.
+	TickSize float64
+	// MinStopDistance is the minimum distance in points between the entry price and the
+	// stop loss. Stops are not widened if zero.
+	MinStopDistance float64
+}
+
+// Validate asserts the instrument is sane.
+func (i *Instrument) Validate() error {
+	var errs error
+
+	if i.PointValue < 0 {
+		errs = errors.Join(errs, fmt.Errorf("point value cannot be negative"))
+	}
+	if i.TickSize < 0 {
+		errs = errors.Join(errs, fmt.Errorf("tick size cannot be negative"))
+	}
+	if i.MinStopDistance < 0 {
+		errs = errors.Join(errs, fmt.Errorf("minimum stop distance cannot be negative"))
+	}
+
+	return errs
+}
+
+// Value returns the dollar value of the provided points moved for the provided size.
+func (i *Instrument) Value(points float64, size float64) float64 {
+	pointValue := i.PointValue
+	if pointValue == 0 {
+		pointValue = 1
+	}
+
+	return points * pointValue * size
+}
+
+// FitStop widens the provided stop loss to the minimum stop distance from the provided price
+// and rounds it to the tick size away from the price, so the stop is never tightened. A
+// bullish sentiment places the stop below the price, a bearish sentiment above it.
+func (i *Instrument) FitStop(sentiment Sentiment, price float64, stopLoss float64) float64 {
+	switch sentiment {
+	case Bullish:
+		if i.MinStopDistance > 0 {
+			stopLoss = math.Min(stopLoss, price-i.MinStopDistance)
+		}
+		if i.TickSize > 0 {
+			stopLoss = math.Floor(roundTicks(stopLoss/i.TickSize)) * i.TickSize
+		}
+	case Bearish:
+		if i.MinStopDistance > 0 {
+			stopLoss = math.Max(stopLoss, price+i.MinStopDistance)
+		}
+		if i.TickSize > 0 {
+			stopLoss = math.Ceil(roundTicks(stopLoss/i.TickSize)) * i.TickSize
+		}
+	}
+
+	return stopLoss
+}
+
+// roundTicks rounds the provided tick count to remove floating point noise, so a stop
+// already on a tick is not moved to the next one.
+func roundTicks(ticks float64) float64 {
+	const precision = 1e9
+	return math.Round(ticks*precision) / precision
+}
+
+// ParseInstruments parses per market instruments from the provided entries of the form
+// market=pointvalue:ticksize:minstopdistance.
+func ParseInstruments(entries []string) (map[string]Instrument, error) {
+	instruments := make(map[string]Instrument, len(entries))
+	for idx := range entries {
+		market, value, ok := strings.Cut(entries[idx], "=")
+		if !ok || market == "" {
+			return nil, fmt.Errorf("invalid instrument entry provided: %s", entries[idx])
+		}
+
+		parts := strings.Split(value, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("expected pointvalue:ticksize:minstopdistance for %s instrument, got %s",
+				market, value)
+		}
+
+		specs := make([]float64, len(parts))
+		for i := range parts {
+			spec, err := strconv.ParseFloat(parts[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("parsing %s instrument: %v", market, err)
+			}
+			specs[i] = spec
+		}
+
+		instrument := Instrument{
+			PointValue:      specs[0],
+			TickSize:        specs[1],
+			MinStopDistance: specs[2],
+		}
+		err := instrument.Validate()
+		if err != nil {
+			return nil, fmt.Errorf("validating %s instrument: %v", market, err)
+		}
+
+		instruments[market] = instrument
+	}
+
+	return instruments, nil
+}
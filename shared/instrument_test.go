@@ -0,0 +1,73 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestInstrumentFitStop(t *testing.T) {
+	tests := []struct {
+		name       string
+		instrument Instrument
+		sentiment  Sentiment
+		price      float64
+		stopLoss   float64
+		want       float64
+	}{
+		{"zero value leaves stop", Instrument{}, Bullish, 100, 98.3, 98.3},
+		{"bullish coarse tick", Instrument{TickSize: 0.5}, Bullish, 100, 98.3, 98},
+		{"bullish fine tick", Instrument{TickSize: 0.25}, Bullish, 100, 98.3, 98.25},
+		{"bearish coarse tick", Instrument{TickSize: 0.5}, Bearish, 100, 101.7, 102},
+		{"bearish fine tick", Instrument{TickSize: 0.25}, Bearish, 100, 101.7, 101.75},
+		{"stop on tick is kept", Instrument{TickSize: 0.1}, Bullish, 100, 98.3, 98.3},
+		{"bullish widened to minimum", Instrument{TickSize: 0.25, MinStopDistance: 2}, Bullish, 100, 99.5, 98},
+		{"bearish widened to minimum", Instrument{TickSize: 0.25, MinStopDistance: 2}, Bearish, 100, 100.5, 102},
+		{"wider stop kept", Instrument{MinStopDistance: 2}, Bearish, 100, 103, 103},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Ensure stops are widened to the minimum distance and rounded away from the price.
+			stop := test.instrument.FitStop(test.sentiment, test.price, test.stopLoss)
+			assert.Equal(t, roundTicks(stop), test.want)
+		})
+	}
+}
+
+func TestInstrumentValue(t *testing.T) {
+	// Ensure a zero point value is treated as one-to-one.
+	unit := &Instrument{}
+	assert.Equal(t, unit.Value(4, 2), float64(8))
+
+	// Ensure the point value scales the value of points moved.
+	futures := &Instrument{PointValue: 50}
+	assert.Equal(t, futures.Value(4, 2), float64(400))
+}
+
+func TestParseInstruments(t *testing.T) {
+	// Ensure market=pointvalue:ticksize:minstopdistance entries are parsed per market.
+	instruments, err := ParseInstruments([]string{"ES=50:0.25:2", "^GSPC=1:0.01:0"})
+	assert.NoError(t, err)
+	assert.Equal(t, instruments["ES"], Instrument{PointValue: 50, TickSize: 0.25, MinStopDistance: 2})
+	assert.Equal(t, instruments["^GSPC"], Instrument{PointValue: 1, TickSize: 0.01})
+
+	tests := []struct {
+		name  string
+		entry string
+	}{
+		{"missing separator", "ES"},
+		{"missing market", "=50:0.25:2"},
+		{"missing specs", "ES=50:0.25"},
+		{"non-numeric spec", "ES=fifty:0.25:2"},
+		{"negative spec", "ES=50:-0.25:2"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Ensure malformed entries are rejected.
+			_, err := ParseInstruments([]string{test.entry})
+			assert.Error(t, err)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package shared
 
 import (
 	"fmt"
+	"time"
 
 	"go.uber.org/atomic"
 )
@@ -40,6 +41,32 @@ type Level struct {
 	Breaks      atomic.Uint32
 	Breaking    atomic.Bool
 	Invalidated atomic.Bool
+	// OrderBlock indicates whether the level marks an order block.
+	OrderBlock bool
+	// Manual indicates whether the level was manually drawn, manual levels are exempt from
+	// eviction when the level snapshot is at capacity.
+	Manual bool
+	// Source is the session the level was derived from.
+	Source LevelSource
+	// StopOut is the unix nano time a position entered at the level was last stopped out,
+	// zero if no stop out is pending.
+	StopOut atomic.Int64
+}
+
+// RecordStopOut records the stop out of a position entered at the level at the provided time.
+func (l *Level) RecordStopOut(stoppedOn time.Time) {
+	l.StopOut.Store(stoppedOn.UnixNano())
+}
+
+// TakeStopOut returns and clears the time of the level's pending stop out, it returns false
+// if no stop out is pending.
+func (l *Level) TakeStopOut() (time.Time, bool) {
+	nanos := l.StopOut.Swap(0)
+	if nanos == 0 {
+		return time.Time{}, false
+	}
+
+	return time.Unix(0, nanos), true
 }
 
 // NewLevel initializes a new level.
@@ -59,12 +86,38 @@ func NewLevel(market string, price float64, close float64) *Level {
 	return lvl
 }
 
+// NewManualLevel initializes a new manually drawn level of the provided kind.
+func NewManualLevel(market string, price float64, kind LevelKind) *Level {
+	return &Level{
+		Market: market,
+		Price:  price,
+		Kind:   kind,
+		Manual: true,
+	}
+}
+
+// NewOrderBlockLevel initializes a new level from the provided order block. Bullish order
+// blocks are support and bearish order blocks are resistance.
+func NewOrderBlockLevel(orderBlock *OrderBlockZone) *Level {
+	lvl := &Level{
+		Market:     orderBlock.Market,
+		Price:      orderBlock.Price(),
+		Kind:       Support,
+		OrderBlock: true,
+	}
+	if orderBlock.Sentiment == Bearish {
+		lvl.Kind = Resistance
+	}
+
+	return lvl
+}
+
 // ApplyReaction applies the price reaction to the provided level.
 func (l *Level) ApplyPriceReaction(reaction PriceReaction) {
 	switch reaction {
 	case Chop:
 		// do nothing.
-	case Reversal:
+	case Reversal, Sweep:
 		l.Reversals.Add(1)
 	case Break:
 		if !l.Breaking.Load() {
@@ -109,20 +162,51 @@ type ReactionAtLevel struct {
 	Level *Level
 }
 
+// sweptLevel checks whether a single candle after the tagging candle of the provided price
+// data wicked beyond the provided level, below a support or above a resistance. A tagging
+// candle wicking beyond the level or repeated wicks beyond it are the level being tested
+// rather than a run on the stops beyond it.
+func sweptLevel(level *Level, data []*Candlestick) bool {
+	beyond := func(candle *Candlestick) bool {
+		if level.Kind == Support {
+			return candle.Low < level.Price
+		}
+
+		return candle.High > level.Price
+	}
+
+	if beyond(data[0]) {
+		return false
+	}
+
+	var wicks int
+	for idx := 1; idx < len(data); idx++ {
+		if beyond(data[idx]) {
+			wicks++
+		}
+	}
+
+	return wicks == 1
+}
+
 // NewReactionAtLevel initializes a new reaction from the provided level and
 // candlestick data.
 func NewReactionAtLevel(market string, level *Level, data []*Candlestick) (*ReactionAtLevel, error) {
-	if len(data) != PriceDataPayloadSize {
-		return nil, fmt.Errorf("price data is not the expected size: %d != expected(%d)",
-			len(data), PriceDataPayloadSize)
+	if len(data) < MinReactionWindow {
+		return nil, fmt.Errorf("price data is below the minimum reaction window: %d < minimum(%d)",
+			len(data), MinReactionWindow)
 	}
 
 	plr := &ReactionAtLevel{
 		ReactionAtFocus: ReactionAtFocus{
+			CorrelationID: NewCorrelationID(),
 			Market:        market,
+			Focus:         LevelFocus,
 			LevelKind:     level.Kind,
+			LevelSource:   level.Source,
 			Timeframe:     data[len(data)-1].Timeframe,
 			PriceMovement: make([]PriceMovement, 0, len(data)),
+			TaggingCandle: data[0],
 			Status:        make(chan StatusCode, 1),
 			CurrentPrice:  data[len(data)-1].Close,
 			CreatedOn:     data[len(data)-1].Date,
@@ -130,6 +214,10 @@ func NewReactionAtLevel(market string, level *Level, data []*Candlestick) (*Reac
 		Level: level,
 	}
 
+	if level.OrderBlock {
+		plr.Focus = OrderBlockFocus
+	}
+
 	// Generate price movement data from the level and provided price data.
 	for idx := range data {
 		candle := data[idx]
@@ -145,24 +233,13 @@ func NewReactionAtLevel(market string, level *Level, data []*Candlestick) (*Reac
 	}
 
 	// Generate a price reaction based on the price movement data.
-	var above, below uint32
-	for idx := range plr.PriceMovement {
-		switch {
-		case plr.PriceMovement[idx] == Above:
-			above++
-		case plr.PriceMovement[idx] == Below:
-			below++
-		}
-	}
+	above, below := countPriceMovement(plr.PriceMovement)
 
-	// The level reaction is currently rooted in being able to make a decision
-	// on a reaction using 4 5-minute candles. Changing the data size would
-	// require reworking the logic here.
+	// The reaction is classified from the shape of the price movement over the evaluation
+	// window, which generalizes to any window of at least MinReactionWindow candles.
 
 	first := plr.PriceMovement[0]
-	second := plr.PriceMovement[1]
-	third := plr.PriceMovement[2]
-	fourth := plr.PriceMovement[3]
+	last := plr.PriceMovement[len(plr.PriceMovement)-1]
 
 	switch level.Kind {
 	case Support:
@@ -170,26 +247,29 @@ func NewReactionAtLevel(market string, level *Level, data []*Candlestick) (*Reac
 		case above == 0 && below == 0:
 			// If price is not closing above or below the level it is chopping.
 			plr.Reaction = Chop
+		case below == 0 && sweptLevel(level, data):
+			// If price held above a support level on every close but a single candle after
+			// the tag wicked below it then the stops below the level were run, it is likely a
+			// sweep of the liquidity below the level.
+			plr.Reaction = Sweep
 		case below == 0:
 			// If price consistently stayed below a support level it tagged then it
 			// it is likely reversing at the level.
 			plr.Reaction = Reversal
-		case first == Above && third == Below && fourth == Below:
+		case first == Above && closedConsistently(plr.PriceMovement, Below):
 			// If price was above a support level but starts to consistently close below it
 			// then it is likely breaking the level.
 			plr.Reaction = Break
-		case first == Above && second == Above && third == Above && fourth == Below:
+		case turnedSharply(plr.PriceMovement, Above, Below):
 			// If price was above a support but turns sharply to close below it then
 			// it is likely breaking the level.
 			plr.Reaction = Break
-		case first == Above && below > 0 && fourth == Above:
+		case first == Above && below > 0 && last == Above:
 			// If price was above a support level but closed below it briefly and pushed back
 			// above it then it is likely reversing at the level.
 			plr.Reaction = Reversal
-		case first == Above && second == Below && third == Above && fourth == Below:
-			// If price is consistently closing aimlessly above and below a level it is chopping.
-			plr.Reaction = Chop
 		default:
+			// If price is consistently closing aimlessly above and below a level it is chopping.
 			plr.Reaction = Chop
 		}
 	case Resistance:
@@ -197,26 +277,29 @@ func NewReactionAtLevel(market string, level *Level, data []*Candlestick) (*Reac
 		case above == 0 && below == 0:
 			// If price is not closing above or below the level it is chopping.
 			plr.Reaction = Chop
+		case above == 0 && sweptLevel(level, data):
+			// If price held below a resistance level on every close but a single candle after
+			// the tag wicked above it then the stops above the level were run, it is likely a
+			// sweep of the liquidity above the level.
+			plr.Reaction = Sweep
 		case above == 0:
 			// If price consistently stayed below a resistance level it tagged then
 			// it is likely reversing at the level.
 			plr.Reaction = Reversal
-		case first == Below && third == Above && fourth == Above:
+		case first == Below && closedConsistently(plr.PriceMovement, Above):
 			// If price was below a resistance level but starts to consistently close above it
 			// then it is likely breaking the level.
 			plr.Reaction = Break
-		case first == Below && second == Below && third == Below && fourth == Above:
+		case turnedSharply(plr.PriceMovement, Below, Above):
 			// If price was below a resistance but turns sharply to close above it then it is
 			// likely breaking the level.
 			plr.Reaction = Break
-		case first == Below && above > 0 && fourth == Below:
+		case first == Below && above > 0 && last == Below:
 			// If price was below a resistance level but closed above it briefly and pushed
-			// back below it then it is likely breaking the level.
+			// back below it then it is likely reversing at the level.
 			plr.Reaction = Reversal
-		case first == Below && second == Above && third == Below && fourth == Above:
-			// If price is consistently closing aimlessly above and below a level it is chopping.
-			plr.Reaction = Chop
 		default:
+			// If price is consistently closing aimlessly above and below a level it is chopping.
 			plr.Reaction = Chop
 		}
 	}
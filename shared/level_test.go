@@ -2,6 +2,7 @@ package shared
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/peterldowns/testy/assert"
@@ -57,6 +58,11 @@ func TestLevel(t *testing.T) {
 	lvl.ApplyPriceReaction(reversalReaction)
 	assert.Equal(t, lvl.Reversals.Load(), uint32(1))
 
+	// Ensure a sweep is counted as a reversal of the level.
+	sweepReaction := Sweep
+	lvl.ApplyPriceReaction(sweepReaction)
+	assert.Equal(t, lvl.Reversals.Load(), uint32(2))
+
 	breakReaction := Break
 	lvl.ApplyPriceReaction(breakReaction)
 	assert.True(t, lvl.Breaking.Load())
@@ -89,6 +95,57 @@ func TestLevel(t *testing.T) {
 	assert.True(t, lvl.IsInvalidated())
 }
 
+func TestLevelStopOut(t *testing.T) {
+	lvl := NewLevel("^GSPC", float64(10), float64(12))
+
+	// Ensure levels without a recorded stop out have none to take.
+	_, ok := lvl.TakeStopOut()
+	assert.False(t, ok)
+
+	// Ensure a recorded stop out can only be taken once.
+	stoppedOn := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	lvl.RecordStopOut(stoppedOn)
+	taken, ok := lvl.TakeStopOut()
+	assert.True(t, ok)
+	assert.True(t, taken.Equal(stoppedOn))
+
+	_, ok = lvl.TakeStopOut()
+	assert.False(t, ok)
+}
+
+func TestNewOrderBlockLevel(t *testing.T) {
+	market := "^GSPC"
+
+	// Ensure bullish order blocks are support at their high.
+	bullish := &OrderBlockZone{Market: market, High: 17, Low: 12, Sentiment: Bullish}
+	lvl := NewOrderBlockLevel(bullish)
+	assert.Equal(t, lvl.Market, market)
+	assert.Equal(t, lvl.Price, float64(17))
+	assert.Equal(t, lvl.Kind, Support)
+	assert.True(t, lvl.OrderBlock)
+
+	// Ensure bearish order blocks are resistance at their low.
+	bearish := &OrderBlockZone{Market: market, High: 34, Low: 29, Sentiment: Bearish}
+	lvl = NewOrderBlockLevel(bearish)
+	assert.Equal(t, lvl.Price, float64(29))
+	assert.Equal(t, lvl.Kind, Resistance)
+
+	// Ensure reactions at order block levels are tagged with the order block focus.
+	data := []*Candlestick{
+		{Market: market, Open: 31, Close: 30, High: 31, Low: 29, Timeframe: FiveMinute},
+		{Market: market, Open: 30, Close: 27, High: 30, Low: 26, Timeframe: FiveMinute},
+		{Market: market, Open: 27, Close: 25, High: 28, Low: 24, Timeframe: FiveMinute},
+		{Market: market, Open: 25, Close: 24, High: 26, Low: 23, Timeframe: FiveMinute},
+	}
+	reaction, err := NewReactionAtLevel(market, lvl, data)
+	assert.NoError(t, err)
+	assert.Equal(t, reaction.Focus, OrderBlockFocus)
+
+	reaction, err = NewReactionAtLevel(market, NewLevel(market, 29, 31), data)
+	assert.NoError(t, err)
+	assert.Equal(t, reaction.Focus, LevelFocus)
+}
+
 func TestNewReactionAtLevel(t *testing.T) {
 	price := float64(12)
 	market := "^GSPC"
@@ -570,6 +627,80 @@ func TestNewReactionAtLevel(t *testing.T) {
 			wantPriceMovement: []PriceMovement{Below, Above, Above, Below},
 			wantErr:           false,
 		},
+		{
+			name:  "sweep at support - single wick below level",
+			level: NewLevel(market, price, supportClose),
+			data: []*Candlestick{
+				{
+					Open:   15,
+					High:   16,
+					Low:    13,
+					Close:  14,
+					Status: make(chan StatusCode, 1),
+				},
+				{
+					Open:   14,
+					High:   15,
+					Low:    10,
+					Close:  14,
+					Status: make(chan StatusCode, 1),
+				},
+				{
+					Open:   14,
+					High:   17,
+					Low:    13,
+					Close:  16,
+					Status: make(chan StatusCode, 1),
+				},
+				{
+					Open:   16,
+					High:   19,
+					Low:    15,
+					Close:  18,
+					Status: make(chan StatusCode, 1),
+				},
+			},
+			wantReaction:      Sweep,
+			wantPriceMovement: []PriceMovement{Above, Above, Above, Above},
+			wantErr:           false,
+		},
+		{
+			name:  "sweep at resistance - single wick above level",
+			level: NewLevel(market, price, resistanceClose),
+			data: []*Candlestick{
+				{
+					Open:   9,
+					High:   11,
+					Low:    8,
+					Close:  10,
+					Status: make(chan StatusCode, 1),
+				},
+				{
+					Open:   10,
+					High:   14,
+					Low:    9,
+					Close:  10,
+					Status: make(chan StatusCode, 1),
+				},
+				{
+					Open:   10,
+					High:   11,
+					Low:    7,
+					Close:  8,
+					Status: make(chan StatusCode, 1),
+				},
+				{
+					Open:   8,
+					High:   9,
+					Low:    5,
+					Close:  6,
+					Status: make(chan StatusCode, 1),
+				},
+			},
+			wantReaction:      Sweep,
+			wantPriceMovement: []PriceMovement{Below, Below, Below, Below},
+			wantErr:           false,
+		},
 		{
 			name:  "chop reaction at resistance",
 			level: NewLevel(market, price, resistanceClose),
@@ -626,6 +757,10 @@ func TestNewReactionAtLevel(t *testing.T) {
 		}
 
 		if err == nil {
+			if reaction.CorrelationID == "" {
+				t.Errorf("%s: expected a correlation id", test.name)
+			}
+
 			reaction.ApplyPriceReaction()
 
 			if !cmp.Equal(test.wantPriceMovement, reaction.PriceMovement) {
@@ -637,3 +772,31 @@ func TestNewReactionAtLevel(t *testing.T) {
 		}
 	}
 }
+
+func TestNewReactionAtLevelWindows(t *testing.T) {
+	price := float64(12)
+	market := "^GSPC"
+
+	// Ensure the same scenarios classify consistently across reaction windows.
+	for _, window := range []int{PriceDataPayloadSize, 6} {
+		for _, scenario := range reactionScenarios() {
+			movement := scenario.movement(window)
+
+			support := NewLevel(market, price, price+2)
+			reaction, err := NewReactionAtLevel(market, support, movementCandles(movement, price))
+			assert.NoError(t, err)
+			assert.Equal(t, len(reaction.PriceMovement), window)
+			assert.Equal(t, reaction.Reaction, scenario.want)
+
+			resistance := NewLevel(market, price, price-2)
+			reaction, err = NewReactionAtLevel(market, resistance, movementCandles(mirrorMovement(movement), price))
+			assert.NoError(t, err)
+			assert.Equal(t, reaction.Reaction, scenario.want)
+		}
+	}
+
+	// Ensure windows below the minimum reaction window error.
+	_, err := NewReactionAtLevel(market, NewLevel(market, price, price+2),
+		movementCandles([]PriceMovement{Above, Above}, price))
+	assert.Error(t, err)
+}
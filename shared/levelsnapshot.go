@@ -39,7 +39,8 @@ func NewLevelSnapshot(size int32) (*LevelSnapshot, error) {
 	return snapshot, nil
 }
 
-// Adds adds the provided session to the snapshot.
+// Adds adds the provided level to the snapshot. The oldest level that is not manually drawn
+// is evicted when the snapshot is at capacity, the oldest level is evicted if all are manual.
 func (s *LevelSnapshot) Add(level *Level) {
 	s.dataMtx.Lock()
 	defer s.dataMtx.Unlock()
@@ -47,19 +48,37 @@ func (s *LevelSnapshot) Add(level *Level) {
 	start := s.start.Load()
 	count := s.count.Load()
 	size := s.size.Load()
-	end := (start + count) % size
-	s.data[end] = level
 
 	if count == size {
-		// Overwrite the oldest entry when the snapshot is at capacity.
+		evict := int32(0)
+		for i := range count {
+			if !s.data[(start+i)%size].Manual {
+				evict = i
+				break
+			}
+		}
+
+		// Shift the levels preceding the evicted level forward to preserve ordering.
+		for i := evict; i > 0; i-- {
+			s.data[(start+i)%size] = s.data[(start+i-1)%size]
+		}
+
+		// The freed slot at the start holds the newest level once the start advances.
+		s.data[start] = level
 		s.start.Store((start + 1) % size)
-	} else {
-		s.count.Add(1)
+		return
 	}
+
+	end := (start + count) % size
+	s.data[end] = level
+	s.count.Add(1)
 }
 
 // Update applies the provided market update to all tracked levels.
 func (s *LevelSnapshot) Update(candle *Candlestick) {
+	s.dataMtx.RLock()
+	defer s.dataMtx.RUnlock()
+
 	start := s.start.Load()
 	count := s.count.Load()
 	size := s.size.Load()
@@ -88,3 +107,45 @@ func (s *LevelSnapshot) Filter(candle *Candlestick, fn func(*Level, *Candlestick
 
 	return levels
 }
+
+// Remove removes the levels matching the provided function from the snapshot, preserving the
+// order of the remaining levels. It returns the number of levels removed.
+func (s *LevelSnapshot) Remove(fn func(*Level) bool) int {
+	s.dataMtx.Lock()
+	defer s.dataMtx.Unlock()
+
+	start := s.start.Load()
+	count := s.count.Load()
+	size := s.size.Load()
+	kept := make([]*Level, 0, count)
+	for i := range count {
+		level := s.data[(start+i)%size]
+		if !fn(level) {
+			kept = append(kept, level)
+		}
+	}
+
+	// Compact the remaining levels to the start of the buffer.
+	clear(s.data)
+	copy(s.data, kept)
+	s.start.Store(0)
+	s.count.Store(int32(len(kept)))
+
+	return int(count) - len(kept)
+}
+
+// Levels returns the tracked levels, ordered from the oldest to the newest.
+func (s *LevelSnapshot) Levels() []*Level {
+	s.dataMtx.RLock()
+	defer s.dataMtx.RUnlock()
+
+	start := s.start.Load()
+	count := s.count.Load()
+	size := s.size.Load()
+	levels := make([]*Level, count)
+	for i := range count {
+		levels[i] = s.data[(start+i)%size]
+	}
+
+	return levels
+}
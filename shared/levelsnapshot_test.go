@@ -57,6 +57,12 @@ func TestLevelSnapshot(t *testing.T) {
 	assert.Equal(t, levelSnapshot.start.Load(), 1)
 	assert.Equal(t, len(levelSnapshot.data), int(size))
 
+	// Ensure tracked levels are returned from the oldest to the newest.
+	levels := levelSnapshot.Levels()
+	assert.Equal(t, len(levels), int(size))
+	assert.True(t, levels[0] == levelSnapshot.data[1])
+	assert.True(t, levels[len(levels)-1] == level)
+
 	// Ensure the snapshot can be filtered.
 	filter := func(level *Level, candle *Candlestick) bool {
 		return level.Price > candle.Close
@@ -65,3 +71,70 @@ func TestLevelSnapshot(t *testing.T) {
 	filteredLevels := levelSnapshot.Filter(resistanceCandle, filter)
 	assert.GreaterThan(t, len(filteredLevels), 0)
 }
+
+func TestLevelSnapshotManualLevels(t *testing.T) {
+	market := "^GSPC"
+	size := int32(3)
+	levelSnapshot, err := NewLevelSnapshot(size)
+	assert.NoError(t, err)
+
+	manual := NewManualLevel(market, 100, Resistance)
+	assert.True(t, manual.Manual)
+	assert.Equal(t, manual.Kind, Resistance)
+
+	levelSnapshot.Add(manual)
+	levelSnapshot.Add(NewLevel(market, 10, 12))
+	levelSnapshot.Add(NewLevel(market, 20, 22))
+
+	// Ensure the oldest level that is not manual is evicted at capacity.
+	levelSnapshot.Add(NewLevel(market, 30, 32))
+	levels := levelSnapshot.Levels()
+	assert.Equal(t, len(levels), int(size))
+	assert.Equal(t, levels[0].Price, float64(100))
+	assert.Equal(t, levels[1].Price, float64(20))
+	assert.Equal(t, levels[2].Price, float64(30))
+
+	levelSnapshot.Add(NewLevel(market, 40, 42))
+	levels = levelSnapshot.Levels()
+	assert.Equal(t, levels[0].Price, float64(100))
+	assert.Equal(t, levels[1].Price, float64(30))
+	assert.Equal(t, levels[2].Price, float64(40))
+
+	// Ensure the oldest level is evicted when all levels are manual.
+	levelSnapshot.Add(NewManualLevel(market, 200, Support))
+	levelSnapshot.Add(NewManualLevel(market, 300, Support))
+	levelSnapshot.Add(NewManualLevel(market, 400, Support))
+	levels = levelSnapshot.Levels()
+	assert.Equal(t, levels[0].Price, float64(200))
+	assert.Equal(t, levels[1].Price, float64(300))
+	assert.Equal(t, levels[2].Price, float64(400))
+}
+
+func TestLevelSnapshotRemove(t *testing.T) {
+	market := "^GSPC"
+	size := int32(3)
+	levelSnapshot, err := NewLevelSnapshot(size)
+	assert.NoError(t, err)
+
+	// Wrap the snapshot so the start of the buffer is not at its first slot.
+	for _, price := range []float64{10, 20, 30, 40} {
+		levelSnapshot.Add(NewLevel(market, price, price+2))
+	}
+
+	// Ensure matching levels are removed while the rest keep their order.
+	removed := levelSnapshot.Remove(func(level *Level) bool {
+		return level.Price == 30
+	})
+	assert.Equal(t, removed, 1)
+
+	levels := levelSnapshot.Levels()
+	assert.Equal(t, len(levels), 2)
+	assert.Equal(t, levels[0].Price, float64(20))
+	assert.Equal(t, levels[1].Price, float64(40))
+
+	// Ensure levels added after a removal are appended as the newest.
+	levelSnapshot.Add(NewLevel(market, 50, 52))
+	levels = levelSnapshot.Levels()
+	assert.Equal(t, len(levels), int(size))
+	assert.Equal(t, levels[2].Price, float64(50))
+}
@@ -0,0 +1,63 @@
+package shared
+
+import "fmt"
+
+// LevelSource represents the session a level was derived from, it determines the
+// significance tier of the level.
+type LevelSource int
+
+const (
+	// UnsourcedLevel is a level not derived from a session high or low.
+	UnsourcedLevel LevelSource = iota
+	// AsiaLevel is a high or low of the asia session.
+	AsiaLevel
+	// LondonLevel is a high or low of the london session.
+	LondonLevel
+	// NewYorkLevel is a high or low of the new york session.
+	NewYorkLevel
+	// DailyLevel is a high or low of the trading day.
+	DailyLevel
+)
+
+// String stringifies the provided level source.
+func (s LevelSource) String() string {
+	switch s {
+	case UnsourcedLevel:
+		return "unsourced"
+	case AsiaLevel:
+		return Asia
+	case LondonLevel:
+		return London
+	case NewYorkLevel:
+		return NewYork
+	case DailyLevel:
+		return "daily"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseLevelSource parses the level source from the provided string.
+func ParseLevelSource(source string) (LevelSource, error) {
+	for _, kind := range []LevelSource{UnsourcedLevel, AsiaLevel, LondonLevel, NewYorkLevel, DailyLevel} {
+		if kind.String() == source {
+			return kind, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown level source provided: %s", source)
+}
+
+// SessionLevelSource returns the level source of the provided session name.
+func SessionLevelSource(session string) LevelSource {
+	switch session {
+	case Asia:
+		return AsiaLevel
+	case London:
+		return LondonLevel
+	case NewYork:
+		return NewYorkLevel
+	default:
+		return UnsourcedLevel
+	}
+}
@@ -0,0 +1,43 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestParseLevelSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		want    LevelSource
+		wantErr bool
+	}{
+		{"unsourced", "unsourced", UnsourcedLevel, false},
+		{"asia", Asia, AsiaLevel, false},
+		{"london", London, LondonLevel, false},
+		{"new york", NewYork, NewYorkLevel, false},
+		{"daily", "daily", DailyLevel, false},
+		{"unknown", "weekly", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			source, err := ParseLevelSource(test.source)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, source, test.want)
+			assert.Equal(t, source.String(), test.source)
+		})
+	}
+
+	// Ensure session names map to their level sources.
+	assert.Equal(t, SessionLevelSource(Asia), AsiaLevel)
+	assert.Equal(t, SessionLevelSource(London), LondonLevel)
+	assert.Equal(t, SessionLevelSource(NewYork), NewYorkLevel)
+	assert.Equal(t, SessionLevelSource("window"), UnsourcedLevel)
+}
@@ -0,0 +1,62 @@
+package shared
+
+import "time"
+
+// orderBlockLookback is the maximum number of candles preceding a displacement candle
+// searched for its order block.
+const orderBlockLookback = 5
+
+// OrderBlockZone represents the last opposing candle before a displacement that created an
+// imbalance. These act as reaction levels for price when revisited.
+type OrderBlockZone struct {
+	Market    string
+	Timeframe Timeframe
+	High      float64
+	Low       float64
+	// Sentiment is the sentiment of the displacement the order block preceded.
+	Sentiment Sentiment
+	Date      time.Time
+}
+
+// Price returns the reaction price of the order block, the high of bullish order blocks
+// and the low of bearish order blocks.
+func (o *OrderBlockZone) Price() float64 {
+	if o.Sentiment == Bearish {
+		return o.Low
+	}
+
+	return o.High
+}
+
+// findOrderBlock finds the order block of a displacement with the provided sentiment from the
+// candles preceding it, ordered oldest first. The most recent opposing candle within the
+// lookback is the order block.
+func findOrderBlock(preceding []*Candlestick, sentiment Sentiment) *OrderBlockZone {
+	var opposing Sentiment
+	switch sentiment {
+	case Bullish:
+		opposing = Bearish
+	case Bearish:
+		opposing = Bullish
+	default:
+		return nil
+	}
+
+	for idx := len(preceding) - 1; idx >= 0 && idx >= len(preceding)-orderBlockLookback; idx-- {
+		candle := preceding[idx]
+		if candle.FetchSentiment() != opposing {
+			continue
+		}
+
+		return &OrderBlockZone{
+			Market:    candle.Market,
+			Timeframe: candle.Timeframe,
+			High:      candle.High,
+			Low:       candle.Low,
+			Sentiment: sentiment,
+			Date:      candle.Date,
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,11 @@
 package shared
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // PriceMovement represents price movement relative to a point of interest.
 type PriceMovement int
@@ -25,6 +30,47 @@ func (m PriceMovement) String() string {
 	}
 }
 
+// countPriceMovement returns the number of closes above and below the focus in the provided
+// price movement.
+func countPriceMovement(movement []PriceMovement) (uint32, uint32) {
+	var above, below uint32
+	for idx := range movement {
+		switch movement[idx] {
+		case Above:
+			above++
+		case Below:
+			below++
+		}
+	}
+
+	return above, below
+}
+
+// closedConsistently checks whether the latter half of the provided price movement closed on
+// the provided side of the focus.
+func closedConsistently(movement []PriceMovement, side PriceMovement) bool {
+	for idx := len(movement) - len(movement)/2; idx < len(movement); idx++ {
+		if movement[idx] != side {
+			return false
+		}
+	}
+
+	return true
+}
+
+// turnedSharply checks whether the provided price movement held the provided side of the focus
+// for all but the last close, which closed on the opposing side.
+func turnedSharply(movement []PriceMovement, side PriceMovement, opposing PriceMovement) bool {
+	last := len(movement) - 1
+	for idx := range last {
+		if movement[idx] != side {
+			return false
+		}
+	}
+
+	return movement[last] == opposing
+}
+
 // PriceReaction represents price reaction relative to a point of interest.
 type PriceReaction int
 
@@ -32,6 +78,7 @@ const (
 	Chop PriceReaction = iota
 	Reversal
 	Break
+	Sweep
 )
 
 // String stringifies the provided reaction.
@@ -43,19 +90,169 @@ func (m PriceReaction) String() string {
 		return "reversal"
 	case Break:
 		return "break"
+	case Sweep:
+		return "sweep"
 	default:
 		return "unknown"
 	}
 }
 
+// LateBreakHandling represents how breaks only confirmed by the last close of a reaction
+// window are classified.
+type LateBreakHandling int
+
+const (
+	// LateBreakAsBreak keeps late breaks classified as breaks.
+	LateBreakAsBreak LateBreakHandling = iota
+	// LateBreakAsReversal reclassifies late breaks as reversals, treating them as failed
+	// pushes through the focus.
+	LateBreakAsReversal
+	// LateBreakAsChop reclassifies late breaks as chop so they are not acted on.
+	LateBreakAsChop
+)
+
+// String stringifies the provided late break handling.
+func (h LateBreakHandling) String() string {
+	switch h {
+	case LateBreakAsBreak:
+		return "break"
+	case LateBreakAsReversal:
+		return "reversal"
+	case LateBreakAsChop:
+		return "chop"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseLateBreakHandling parses the late break handling from the provided string. An empty
+// string defaults to LateBreakAsBreak.
+func ParseLateBreakHandling(handling string) (LateBreakHandling, error) {
+	switch handling {
+	case "", "break":
+		return LateBreakAsBreak, nil
+	case "reversal":
+		return LateBreakAsReversal, nil
+	case "chop":
+		return LateBreakAsChop, nil
+	default:
+		return 0, fmt.Errorf("unknown late break handling provided: %s", handling)
+	}
+}
+
+// FocusKind represents the type of focus price reacts to.
+type FocusKind int
+
+const (
+	LevelFocus FocusKind = iota
+	VWAPFocus
+	ImbalanceFocus
+	OrderBlockFocus
+)
+
+// String stringifies the provided focus kind.
+func (f FocusKind) String() string {
+	switch f {
+	case LevelFocus:
+		return "level"
+	case VWAPFocus:
+		return "vwap"
+	case ImbalanceFocus:
+		return "imbalance"
+	case OrderBlockFocus:
+		return "orderblock"
+	default:
+		return "unknown"
+	}
+}
+
+// NearbyFocus represents a focus of another type price reacted to on the same candle as a
+// reaction.
+type NearbyFocus struct {
+	// Focus is the type of the nearby focus.
+	Focus FocusKind
+	// LevelSource is the session the nearby level was derived from, it is unsourced for
+	// other focuses.
+	LevelSource LevelSource
+	// Distance is the price distance between the nearby focus and the reaction's focus.
+	Distance float64
+}
+
+// CorrelationIDKey is the log field key of the correlation id tagging the log lines of a
+// reaction's evaluation.
+const CorrelationIDKey = "correlationID"
+
+// NewCorrelationID generates a correlation id for a reaction.
+func NewCorrelationID() string {
+	return uuid.New().String()
+}
+
 // ReactionAtFocus describes the base struct for a reaction of price relative to a key focus – a static or dynamic level.
 type ReactionAtFocus struct {
+	// CorrelationID identifies the reaction across the log lines of its evaluation and the
+	// signals it produces.
+	CorrelationID string
 	Market        string
 	Timeframe     Timeframe
-	LevelKind     LevelKind
+	// Focus is the type of focus price reacted to.
+	Focus     FocusKind
+	LevelKind LevelKind
+	// LevelSource is the session the reacted to level was derived from, it is unsourced for
+	// reactions at other focuses.
+	LevelSource   LevelSource
 	CurrentPrice  float64
 	Reaction      PriceReaction
 	PriceMovement []PriceMovement
-	Status        chan StatusCode
-	CreatedOn     time.Time
+	// TaggingCandle is the candle that tagged the reaction's focus, the first of the
+	// reaction's price data.
+	TaggingCandle *Candlestick
+	// CoincidentFocuses are the focus types of coincident reactions on the same candle
+	// collapsed into this reaction.
+	CoincidentFocuses []FocusKind
+	// NearbyFoci are the foci of other types price reacted to on the same candle as the
+	// reaction.
+	NearbyFoci []NearbyFocus
+	// ImbalanceFill is the fill percentage of the reacted to imbalance when the reaction
+	// was created, it is nil for reactions at other focuses.
+	ImbalanceFill *float64
+	// ImbalanceStack is the number of tagged imbalances stacked at the reacted to imbalance's
+	// price region, including it. It is zero for reactions at other focuses.
+	ImbalanceStack uint32
+	// VWAP is the vwap line at the close of the reaction, it is nil for reactions at other
+	// focuses.
+	VWAP      *VWAP
+	Status    chan StatusCode
+	CreatedOn time.Time
+}
+
+// IsLateBreak checks whether the reaction is a break where price held its side of the focus
+// for all but the last close of the reaction window.
+func (r *ReactionAtFocus) IsLateBreak() bool {
+	if r.Reaction != Break || len(r.PriceMovement) == 0 {
+		return false
+	}
+
+	switch r.LevelKind {
+	case Support:
+		return turnedSharply(r.PriceMovement, Above, Below)
+	case Resistance:
+		return turnedSharply(r.PriceMovement, Below, Above)
+	default:
+		return false
+	}
+}
+
+// ReclassifyLateBreak reclassifies the reaction using the provided handling if it is a late
+// break.
+func (r *ReactionAtFocus) ReclassifyLateBreak(handling LateBreakHandling) {
+	if !r.IsLateBreak() {
+		return
+	}
+
+	switch handling {
+	case LateBreakAsReversal:
+		r.Reaction = Reversal
+	case LateBreakAsChop:
+		r.Reaction = Chop
+	}
 }
@@ -1,6 +1,152 @@
 package shared
 
-import "testing"
+import (
+	"testing"
+	"time"
+
+	"github.com/peterldowns/testy/assert"
+)
+
+// reactionScenario describes price movement over a reaction window relative to a support focus.
+// Resistance scenarios mirror the movement.
+type reactionScenario struct {
+	name     string
+	movement func(window int) []PriceMovement
+	want     PriceReaction
+}
+
+// reactionScenarios returns the reaction scenarios evaluated across reaction windows.
+func reactionScenarios() []reactionScenario {
+	fill := func(window int, fn func(idx int) PriceMovement) []PriceMovement {
+		movement := make([]PriceMovement, window)
+		for idx := range movement {
+			movement[idx] = fn(idx)
+		}
+		return movement
+	}
+
+	return []reactionScenario{
+		{
+			name: "reversal",
+			movement: func(window int) []PriceMovement {
+				return fill(window, func(int) PriceMovement { return Above })
+			},
+			want: Reversal,
+		},
+		{
+			name: "break",
+			movement: func(window int) []PriceMovement {
+				return fill(window, func(idx int) PriceMovement {
+					if idx < window-window/2 {
+						return Above
+					}
+					return Below
+				})
+			},
+			want: Break,
+		},
+		{
+			name: "sharp break",
+			movement: func(window int) []PriceMovement {
+				return fill(window, func(idx int) PriceMovement {
+					if idx < window-1 {
+						return Above
+					}
+					return Below
+				})
+			},
+			want: Break,
+		},
+		{
+			name: "reclaimed break",
+			movement: func(window int) []PriceMovement {
+				return fill(window, func(idx int) PriceMovement {
+					if idx == 1 {
+						return Below
+					}
+					return Above
+				})
+			},
+			want: Reversal,
+		},
+		{
+			name: "chop",
+			movement: func(window int) []PriceMovement {
+				return fill(window, func(idx int) PriceMovement {
+					if idx%2 == 0 {
+						return Above
+					}
+					return Below
+				})
+			},
+			want: Chop,
+		},
+	}
+}
+
+// mirrorMovement flips the provided price movement to the opposite side of the focus.
+func mirrorMovement(movement []PriceMovement) []PriceMovement {
+	mirrored := make([]PriceMovement, len(movement))
+	for idx := range movement {
+		switch movement[idx] {
+		case Above:
+			mirrored[idx] = Below
+		case Below:
+			mirrored[idx] = Above
+		default:
+			mirrored[idx] = movement[idx]
+		}
+	}
+
+	return mirrored
+}
+
+// movementCandles generates candles closing relative to the provided focus as described by the
+// provided price movement.
+func movementCandles(movement []PriceMovement, focus float64) []*Candlestick {
+	candles := make([]*Candlestick, 0, len(movement))
+	for idx := range movement {
+		var close float64
+		switch movement[idx] {
+		case Above:
+			close = focus + 2
+		case Below:
+			close = focus - 2
+		default:
+			close = focus
+		}
+
+		candles = append(candles, &Candlestick{
+			Open:      close,
+			High:      close + 1,
+			Low:       close - 1,
+			Close:     close,
+			Timeframe: FiveMinute,
+			Status:    make(chan StatusCode, 1),
+		})
+	}
+
+	return candles
+}
+
+func TestPriceMovementShape(t *testing.T) {
+	movement := []PriceMovement{Above, Below, Equal, Below, Below, Below}
+
+	// Ensure closes above and below the focus are counted.
+	above, below := countPriceMovement(movement)
+	assert.Equal(t, above, uint32(1))
+	assert.Equal(t, below, uint32(4))
+
+	// Ensure the latter half of the window is evaluated for consistent closes.
+	assert.True(t, closedConsistently(movement, Below))
+	assert.False(t, closedConsistently(movement, Above))
+	assert.False(t, closedConsistently([]PriceMovement{Above, Below, Below, Above}, Below))
+
+	// Ensure sharp turns require the side to hold for all but the last close.
+	assert.True(t, turnedSharply([]PriceMovement{Above, Above, Above, Above, Above, Below}, Above, Below))
+	assert.False(t, turnedSharply([]PriceMovement{Above, Below, Above, Above, Above, Below}, Above, Below))
+	assert.False(t, turnedSharply([]PriceMovement{Above, Above, Above, Above}, Above, Below))
+}
 
 func TestPriceMovementString(t *testing.T) {
 	tests := []struct {
@@ -59,6 +205,11 @@ func TestPriceReactionString(t *testing.T) {
 			Break,
 			"break",
 		},
+		{
+			"sweep price reaction",
+			Sweep,
+			"sweep",
+		},
 		{
 			"unknown price reaction",
 			PriceReaction(999),
@@ -73,3 +224,173 @@ func TestPriceReactionString(t *testing.T) {
 		}
 	}
 }
+
+func TestFocusKindString(t *testing.T) {
+	tests := []struct {
+		name  string
+		focus FocusKind
+		want  string
+	}{
+		{
+			"level focus",
+			LevelFocus,
+			"level",
+		},
+		{
+			"vwap focus",
+			VWAPFocus,
+			"vwap",
+		},
+		{
+			"imbalance focus",
+			ImbalanceFocus,
+			"imbalance",
+		},
+		{
+			"order block focus",
+			OrderBlockFocus,
+			"orderblock",
+		},
+		{
+			"unknown focus",
+			FocusKind(999),
+			"unknown",
+		},
+	}
+
+	for _, test := range tests {
+		str := test.focus.String()
+		if str != test.want {
+			t.Errorf("%s: expected %v, got %v", test.name, test.want, str)
+		}
+	}
+}
+
+func TestReactionTaggingCandle(t *testing.T) {
+	market := "^GSPC"
+	focus := float64(12)
+	now, _, _ := NewYorkTime()
+
+	data := movementCandles([]PriceMovement{Above, Above, Above, Above}, focus)
+	for idx := range data {
+		data[idx].Volume = float64(idx + 1)
+		data[idx].Date = now.Add(time.Minute * 5 * time.Duration(idx))
+	}
+
+	assertTaggingCandle := func(candle *Candlestick) {
+		assert.NotNil(t, candle)
+		assert.True(t, candle == data[0])
+		assert.Equal(t, candle.Close, focus+2)
+		assert.Equal(t, candle.Volume, float64(1))
+		assert.Equal(t, candle.Date, now)
+	}
+
+	// Ensure level reactions are annotated with the tagging candle.
+	levelReaction, err := NewReactionAtLevel(market, NewLevel(market, focus, focus+2), data)
+	assert.NoError(t, err)
+	assertTaggingCandle(levelReaction.TaggingCandle)
+
+	// Ensure vwap reactions are annotated with the tagging candle.
+	vwapData := make([]*VWAP, len(data))
+	for idx := range data {
+		vwapData[idx] = &VWAP{Value: focus, Date: data[idx].Date}
+	}
+	vwapReaction, err := NewReactionAtVWAP(market, vwapData, data, 0)
+	assert.NoError(t, err)
+	assertTaggingCandle(vwapReaction.TaggingCandle)
+
+	// Ensure imbalance reactions are annotated with the tagging candle.
+	imb := NewImbalance(market, FiveMinute, focus+1, focus, focus-1, Bullish, float64(0.5), now)
+	imbalanceReaction, err := NewReactionAtImbalance(market, imb, data)
+	assert.NoError(t, err)
+	assertTaggingCandle(imbalanceReaction.TaggingCandle)
+}
+
+func TestParseLateBreakHandling(t *testing.T) {
+	tests := []struct {
+		name     string
+		handling string
+		want     LateBreakHandling
+		wantErr  bool
+	}{
+		{"empty defaults to break", "", LateBreakAsBreak, false},
+		{"break", "break", LateBreakAsBreak, false},
+		{"reversal", "reversal", LateBreakAsReversal, false},
+		{"chop", "chop", LateBreakAsChop, false},
+		{"unknown", "fade", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handling, err := ParseLateBreakHandling(test.handling)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, handling, test.want)
+			assert.Equal(t, handling.String(), test.want.String())
+		})
+	}
+}
+
+func TestReclassifyLateBreak(t *testing.T) {
+	market := "^GSPC"
+	focus := float64(100)
+
+	tests := []struct {
+		name     string
+		movement []PriceMovement
+		want     map[LateBreakHandling]PriceReaction
+	}{
+		{
+			name:     "late break",
+			movement: []PriceMovement{Above, Above, Above, Below},
+			want: map[LateBreakHandling]PriceReaction{
+				LateBreakAsBreak:    Break,
+				LateBreakAsReversal: Reversal,
+				LateBreakAsChop:     Chop,
+			},
+		},
+		{
+			name:     "consistent break",
+			movement: []PriceMovement{Above, Above, Below, Below},
+			want: map[LateBreakHandling]PriceReaction{
+				LateBreakAsBreak:    Break,
+				LateBreakAsReversal: Break,
+				LateBreakAsChop:     Break,
+			},
+		},
+		{
+			name:     "reversal",
+			movement: []PriceMovement{Above, Above, Above, Above},
+			want: map[LateBreakHandling]PriceReaction{
+				LateBreakAsBreak:    Reversal,
+				LateBreakAsReversal: Reversal,
+				LateBreakAsChop:     Reversal,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		for handling, want := range test.want {
+			// Ensure support and resistance reactions are reclassified alike.
+			for _, movement := range [][]PriceMovement{test.movement, mirrorMovement(test.movement)} {
+				t.Run(test.name+"/"+handling.String(), func(t *testing.T) {
+					candles := movementCandles(movement, focus)
+					vwapData := make([]*VWAP, len(candles))
+					for idx := range candles {
+						vwapData[idx] = &VWAP{Value: focus}
+					}
+
+					reaction, err := NewReactionAtVWAP(market, vwapData, candles, 0)
+					assert.NoError(t, err)
+
+					reaction.ReclassifyLateBreak(handling)
+					assert.Equal(t, reaction.Reaction, want)
+				})
+			}
+		}
+	}
+}
@@ -1,5 +1,11 @@
 package shared
 
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
 // Reason represents an entry or exit reason.
 type Reason int
 
@@ -15,8 +21,133 @@ const (
 	StrongVolume
 	StrongMove
 	HighVolumeSession
+	LiquiditySweep
+	CoincidentFocus
+	FreshImbalance
+	OrderBlock
+	SignificantLevel
+	TrendAligned
+	VWAPStretch
+	StackedImbalance
 )
 
+// ReasonPriority orders reasons by significance, earlier reasons are more significant.
+// Reasons not in the priority are least significant.
+type ReasonPriority []Reason
+
+// DefaultReasonPriority is the default reason priority, confirmation reasons lead structural,
+// volume and session reasons.
+var DefaultReasonPriority = ReasonPriority{
+	TargetHit,
+	StopLossHit,
+	BullishEngulfing,
+	BearishEngulfing,
+	StrongMove,
+	LiquiditySweep,
+	ReversalAtSupport,
+	ReversalAtResistance,
+	BreakBelowSupport,
+	BreakAboveResistance,
+	SignificantLevel,
+	TrendAligned,
+	CoincidentFocus,
+	FreshImbalance,
+	StackedImbalance,
+	OrderBlock,
+	VWAPStretch,
+	StrongVolume,
+	HighVolumeSession,
+}
+
+// ParseReasonPriority parses the reason priority from the provided reason names, most
+// significant first.
+func ParseReasonPriority(entries []string) (ReasonPriority, error) {
+	priority := make(ReasonPriority, 0, len(entries))
+	for idx := range entries {
+		reason, err := ParseReason(entries[idx])
+		if err != nil {
+			return nil, fmt.Errorf("parsing reason priority: %v", err)
+		}
+
+		priority = append(priority, reason)
+	}
+
+	err := priority.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return priority, nil
+}
+
+// Validate asserts the reason priority only ranks known reasons once.
+func (p ReasonPriority) Validate() error {
+	var errs error
+
+	seen := make(map[Reason]struct{}, len(p))
+	for _, reason := range p {
+		if reason.String() == "unknown" {
+			errs = errors.Join(errs, fmt.Errorf("unknown reason provided in priority: %d", reason))
+			continue
+		}
+
+		if _, ok := seen[reason]; ok {
+			errs = errors.Join(errs, fmt.Errorf("reason %s ranked more than once in priority", reason))
+			continue
+		}
+		seen[reason] = struct{}{}
+	}
+
+	return errs
+}
+
+// Rank returns the rank of the provided reason, lower ranks are more significant. The default
+// priority is used if the priority is empty.
+func (p ReasonPriority) Rank(reason Reason) int {
+	if len(p) == 0 {
+		p = DefaultReasonPriority
+	}
+
+	idx := slices.Index(p, reason)
+	if idx == -1 {
+		return len(p)
+	}
+
+	return idx
+}
+
+// Sort returns a copy of the provided reasons sorted by priority.
+func (p ReasonPriority) Sort(reasons []Reason) []Reason {
+	sorted := slices.Clone(reasons)
+	slices.SortStableFunc(sorted, func(a, b Reason) int {
+		return p.Rank(a) - p.Rank(b)
+	})
+
+	return sorted
+}
+
+// Priority returns the default priority of the reason, lower values are more significant.
+// Unknown reasons are least significant.
+func (r Reason) Priority() int {
+	return DefaultReasonPriority.Rank(r)
+}
+
+// SortReasons returns a copy of the provided reasons sorted by the default priority.
+func SortReasons(reasons []Reason) []Reason {
+	return DefaultReasonPriority.Sort(reasons)
+}
+
+// ParseReason parses the reason from the provided reason name.
+func ParseReason(name string) (Reason, error) {
+	for _, reason := range DefaultReasonPriority {
+		if reason.String() == name {
+			return reason, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown reason provided: %s", name)
+}
+
 // String stringifies the provided reason.
 func (r Reason) String() string {
 	switch r {
@@ -42,6 +173,22 @@ func (r Reason) String() string {
 		return "strong move"
 	case HighVolumeSession:
 		return "high volume session"
+	case LiquiditySweep:
+		return "liquidity sweep"
+	case CoincidentFocus:
+		return "coincident focus"
+	case FreshImbalance:
+		return "fresh imbalance"
+	case OrderBlock:
+		return "order block"
+	case SignificantLevel:
+		return "significant level"
+	case TrendAligned:
+		return "trend aligned"
+	case VWAPStretch:
+		return "vwap stretch"
+	case StackedImbalance:
+		return "stacked imbalance"
 	default:
 		return "unknown"
 	}
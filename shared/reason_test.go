@@ -1,6 +1,10 @@
 package shared
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/peterldowns/testy/assert"
+)
 
 func TestEntryReasonString(t *testing.T) {
 	tests := []struct {
@@ -48,6 +52,16 @@ func TestEntryReasonString(t *testing.T) {
 			BreakAboveResistance,
 			"price break above resistance",
 		},
+		{
+			"significant level",
+			SignificantLevel,
+			"significant level",
+		},
+		{
+			"trend aligned",
+			TrendAligned,
+			"trend aligned",
+		},
 		{
 			"strong volume",
 			StrongVolume,
@@ -62,6 +76,36 @@ func TestEntryReasonString(t *testing.T) {
 			HighVolumeSession,
 			"high volume session",
 		},
+		{
+			"liquidity sweep",
+			LiquiditySweep,
+			"liquidity sweep",
+		},
+		{
+			"coincident focus",
+			CoincidentFocus,
+			"coincident focus",
+		},
+		{
+			"fresh imbalance",
+			FreshImbalance,
+			"fresh imbalance",
+		},
+		{
+			"order block",
+			OrderBlock,
+			"order block",
+		},
+		{
+			"vwap stretch",
+			VWAPStretch,
+			"vwap stretch",
+		},
+		{
+			"stacked imbalance",
+			StackedImbalance,
+			"stacked imbalance",
+		},
 		{
 			"unknown reason",
 			Reason(999),
@@ -77,6 +121,74 @@ func TestEntryReasonString(t *testing.T) {
 	}
 }
 
+func TestParseReasonPriority(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		want    ReasonPriority
+		wantErr bool
+	}{
+		{"no entries", nil, ReasonPriority{}, false},
+		{"valid entries", []string{"strong volume", "price reversal at support"},
+			ReasonPriority{StrongVolume, ReversalAtSupport}, false},
+		{"unknown reason", []string{"strong volume", "gut feeling"}, nil, true},
+		{"duplicate reason", []string{"strong volume", "strong volume"}, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			priority, err := ParseReasonPriority(test.entries)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, priority, test.want)
+		})
+	}
+
+	// Ensure every reason round trips through its name.
+	for _, reason := range DefaultReasonPriority {
+		parsed, err := ParseReason(reason.String())
+		assert.NoError(t, err)
+		assert.Equal(t, parsed, reason)
+	}
+}
+
+func TestReasonPrioritySort(t *testing.T) {
+	reasons := []Reason{HighVolumeSession, StrongVolume, ReversalAtSupport, BullishEngulfing, OrderBlock}
+
+	tests := []struct {
+		name     string
+		priority ReasonPriority
+		want     []Reason
+	}{
+		{"default priority", nil,
+			[]Reason{BullishEngulfing, ReversalAtSupport, OrderBlock, StrongVolume, HighVolumeSession}},
+		{"configured priority", ReasonPriority{StrongVolume, HighVolumeSession, OrderBlock, ReversalAtSupport, BullishEngulfing},
+			[]Reason{StrongVolume, HighVolumeSession, OrderBlock, ReversalAtSupport, BullishEngulfing}},
+		{"partial priority", ReasonPriority{OrderBlock},
+			[]Reason{OrderBlock, HighVolumeSession, StrongVolume, ReversalAtSupport, BullishEngulfing}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Ensure reasons are sorted by the priority, unranked reasons keep their order last.
+			assert.Equal(t, test.priority.Sort(reasons), test.want)
+		})
+	}
+
+	// Ensure sorting leaves the provided reasons unchanged.
+	assert.Equal(t, reasons[0], HighVolumeSession)
+
+	// Ensure the default priority ranks every reason.
+	assert.NoError(t, DefaultReasonPriority.Validate())
+	for reason := TargetHit; reason <= StackedImbalance; reason++ {
+		assert.True(t, reason.Priority() < len(DefaultReasonPriority))
+	}
+}
+
 func TestDirectionString(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -1,41 +1,135 @@
 package shared
 
 import (
+	"errors"
+	"fmt"
 	"time"
 )
 
 const (
 	// PriceDataPayloadSize is the number of candles expected as payload for a price data range request.
+	// It is the default reaction window.
 	PriceDataPayloadSize = 4
+	// MinReactionWindow is the minimum number of candles required to classify a reaction.
+	MinReactionWindow = 3
 	// VWAPDataPayloadSize is the number of vwap data expected as payload for a vwap data request.
 	VWAPDataPayloadSize = 4
 	// ImbalanceDataPayloadSize is the number of imbalance data expected as payload for a imbalance data request.
 	ImbalanceDataPayloadSize = 4
-	// MaxPriceDataRequestInterval is the maximum update intervals to wait before triggering a
-	// price data request.
-	MaxPriceDataRequestInterval = 3
-	// MaxVWAPDataRequestInterval is the maximum update intervals to wait before triggering a
-	// vwap data request.
-	MaxVWAPDataRequestInterval = 3
-	// MaxImbalanceDataRequestInterval is the maximum update intervals to wait before triggering a
-	// imbalance data request.
-	MaxImbalanceDataRequestInterval = 3
 	// TimeoutDuration is the maximum time to wait before timing out.
 	TimeoutDuration = time.Second * 4
 )
 
+// Timeouts represents the per-operation timeouts of cross-manager requests. TimeoutDuration is
+// used for timeouts that are zero or if nil.
+type Timeouts struct {
+	// PriceData is the maximum time to wait for price data responses, along with average
+	// volume, moving average, trend and market skew responses.
+	PriceData time.Duration
+	// VWAP is the maximum time to wait for vwap data, vwap bands and vwap responses.
+	VWAP time.Duration
+	// CandleMetadata is the maximum time to wait for candle metadata responses to be received.
+	CandleMetadata time.Duration
+	// SignalStatus is the maximum time to wait for relayed signals to be processed.
+	SignalStatus time.Duration
+}
+
+// Validate asserts the timeouts sane inputs.
+func (t *Timeouts) Validate() error {
+	var errs error
+
+	if t.PriceData < 0 {
+		errs = errors.Join(errs, fmt.Errorf("price data timeout cannot be negative"))
+	}
+	if t.VWAP < 0 {
+		errs = errors.Join(errs, fmt.Errorf("vwap timeout cannot be negative"))
+	}
+	if t.CandleMetadata < 0 {
+		errs = errors.Join(errs, fmt.Errorf("candle metadata timeout cannot be negative"))
+	}
+	if t.SignalStatus < 0 {
+		errs = errors.Join(errs, fmt.Errorf("signal status timeout cannot be negative"))
+	}
+
+	return errs
+}
+
+// timeoutOrDefault returns the provided timeout, or TimeoutDuration if it is zero.
+func timeoutOrDefault(timeout time.Duration) time.Duration {
+	if timeout == 0 {
+		return TimeoutDuration
+	}
+
+	return timeout
+}
+
+// PriceDataTimeout returns the maximum time to wait for price data responses.
+func (t *Timeouts) PriceDataTimeout() time.Duration {
+	if t == nil {
+		return TimeoutDuration
+	}
+
+	return timeoutOrDefault(t.PriceData)
+}
+
+// VWAPTimeout returns the maximum time to wait for vwap responses.
+func (t *Timeouts) VWAPTimeout() time.Duration {
+	if t == nil {
+		return TimeoutDuration
+	}
+
+	return timeoutOrDefault(t.VWAP)
+}
+
+// CandleMetadataTimeout returns the maximum time to wait for candle metadata responses to be
+// received.
+func (t *Timeouts) CandleMetadataTimeout() time.Duration {
+	if t == nil {
+		return TimeoutDuration
+	}
+
+	return timeoutOrDefault(t.CandleMetadata)
+}
+
+// SignalStatusTimeout returns the maximum time to wait for relayed signals to be processed.
+func (t *Timeouts) SignalStatusTimeout() time.Duration {
+	if t == nil {
+		return TimeoutDuration
+	}
+
+	return timeoutOrDefault(t.SignalStatus)
+}
+
+// ErrTimeframeNotTracked is returned for requests of a timeframe the market does not track.
+var ErrTimeframeNotTracked = errors.New("timeframe not tracked")
+
+// FailRequest relays the provided error on the provided request error channel if it is set
+// and ready, returning the error.
+func FailRequest(errs chan error, err error) error {
+	select {
+	case errs <- err:
+	default:
+	}
+
+	return err
+}
+
 // CandleMetadataRequest represents a request to fetch the current candle's metadata.
 type CandleMetadataRequest struct {
 	Market    string
 	Timeframe Timeframe
-	Response  chan []*CandleMetadata
+	// Lookback is the number of candles metadata is requested for. Metadata spanning the
+	// reaction window is returned if the lookback is zero or covers fewer candles.
+	Lookback uint32
+	Response chan []*CandleMetadata
 }
 
 // NewCandleMetadataRequest initializes a new candle metadata request.
-func NewCandleMetadataRequest(market string, timeframe Timeframe) *CandleMetadataRequest {
+func NewCandleMetadataRequest(market string, timeframe Timeframe, lookback uint32) *CandleMetadataRequest {
 	return &CandleMetadataRequest{
 		Market:    market,
 		Timeframe: timeframe,
+		Lookback:  lookback,
 		Response:  make(chan []*CandleMetadata, 1),
 	}
 }
@@ -46,6 +140,8 @@ type PriceDataRequest struct {
 	Timeframe Timeframe
 	N         uint32
 	Response  chan []*Candlestick
+	// Err receives the error of a request that could not be fulfilled.
+	Err chan error
 }
 
 // NewPriceDataRequest initializes a new price data request.
@@ -54,6 +150,7 @@ func NewPriceDataRequest(market string, timeframe Timeframe, n uint32) *PriceDat
 		Market:    market,
 		N:         n,
 		Response:  make(chan []*Candlestick, 1),
+		Err:       make(chan error, 1),
 		Timeframe: timeframe,
 	}
 }
@@ -64,13 +161,22 @@ type AverageVolumeRequest struct {
 	Market    string
 	Timeframe Timeframe
 	Response  chan float64
+	// Normalize is the flag for also responding with the standard deviation of volume over
+	// the same range on the deviation channel.
+	Normalize bool
+	Deviation chan float64
+	// Err receives the error of a request that could not be fulfilled.
+	Err chan error
 }
 
 // NewAverageVolumeRequest initializes a new average volume request.
 func NewAverageVolumeRequest(market string, timeframe Timeframe) *AverageVolumeRequest {
 	return &AverageVolumeRequest{
-		Market:   market,
-		Response: make(chan float64, 1),
+		Market:    market,
+		Timeframe: timeframe,
+		Response:  make(chan float64, 1),
+		Deviation: make(chan float64, 1),
+		Err:       make(chan error, 1),
 	}
 }
 
@@ -95,6 +201,8 @@ type VWAPRequest struct {
 	Timeframe Timeframe
 	At        time.Time
 	Response  chan *VWAP
+	// Err receives the error of a request that could not be fulfilled.
+	Err chan error
 }
 
 // NewVWAPRequest initializes a new VWAP request.
@@ -104,6 +212,7 @@ func NewVWAPRequest(market string, time time.Time, timeframe Timeframe) *VWAPReq
 		Timeframe: timeframe,
 		At:        time,
 		Response:  make(chan *VWAP, 1),
+		Err:       make(chan error, 1),
 	}
 }
 
@@ -111,14 +220,72 @@ func NewVWAPRequest(market string, time time.Time, timeframe Timeframe) *VWAPReq
 type VWAPDataRequest struct {
 	Market    string
 	Timeframe Timeframe
+	N         uint32
 	Response  chan []*VWAP
 }
 
 // NewVWAPDataRequest initializes a new VWAP data request.
-func NewVWAPDataRequest(market string, timeframe Timeframe) *VWAPDataRequest {
+func NewVWAPDataRequest(market string, timeframe Timeframe, n uint32) *VWAPDataRequest {
 	return &VWAPDataRequest{
 		Market:    market,
 		Timeframe: timeframe,
+		N:         n,
+		Response:  make(chan []*VWAP, 1),
+	}
+}
+
+// VWAPBandsRequest represents a request for the vwap data of a market projected onto one of
+// its standard deviation bands.
+type VWAPBandsRequest struct {
+	Market    string
+	Timeframe Timeframe
+	Band      VWAPBand
+	N         uint32
+	Response  chan []*VWAP
+}
+
+// NewVWAPBandsRequest initializes a new VWAP bands request.
+func NewVWAPBandsRequest(market string, timeframe Timeframe, band VWAPBand, n uint32) *VWAPBandsRequest {
+	return &VWAPBandsRequest{
+		Market:    market,
+		Timeframe: timeframe,
+		Band:      band,
+		N:         n,
 		Response:  make(chan []*VWAP, 1),
 	}
 }
+
+// TrendRequest represents a trend request for a market.
+type TrendRequest struct {
+	Market    string
+	Timeframe Timeframe
+	N         int32
+	Response  chan Trend
+}
+
+// NewTrendRequest initializes a new trend request covering the last n vwap entries of the
+// provided timeframe.
+func NewTrendRequest(market string, timeframe Timeframe, n int32) *TrendRequest {
+	return &TrendRequest{
+		Market:    market,
+		Timeframe: timeframe,
+		N:         n,
+		Response:  make(chan Trend, 1),
+	}
+}
+
+// MovingAverageRequest represents a request for the current moving average of a market.
+type MovingAverageRequest struct {
+	Market    string
+	Timeframe Timeframe
+	Response  chan *MovingAverage
+}
+
+// NewMovingAverageRequest initializes a new moving average request.
+func NewMovingAverageRequest(market string, timeframe Timeframe) *MovingAverageRequest {
+	return &MovingAverageRequest{
+		Market:    market,
+		Timeframe: timeframe,
+		Response:  make(chan *MovingAverage, 1),
+	}
+}
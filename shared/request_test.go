@@ -11,7 +11,7 @@ func TestRequestResponse(t *testing.T) {
 	// Ensure requests can be created and can receive their responses on theit corresponding channels.
 	market := "^GSPC"
 	timeframe := FiveMinute
-	candleMetaReq := NewCandleMetadataRequest(market, timeframe)
+	candleMetaReq := NewCandleMetadataRequest(market, timeframe, 0)
 	assert.NotNil(t, candleMetaReq)
 	go func() { candleMetaReq.Response <- []*CandleMetadata{} }()
 	candleMetaResp := <-candleMetaReq.Response
@@ -43,7 +43,7 @@ func TestRequestResponse(t *testing.T) {
 	vwapResp := <-vwapReq.Response
 	assert.Equal(t, vwapResp, &VWAP{Value: float64(3), Date: now})
 
-	vwapDataReq := NewVWAPDataRequest(market, timeframe)
+	vwapDataReq := NewVWAPDataRequest(market, timeframe, VWAPDataPayloadSize)
 	assert.NotNil(t, vwapDataReq)
 	go func() {
 		vwapDataReq.Response <- []*VWAP{
@@ -54,4 +54,49 @@ func TestRequestResponse(t *testing.T) {
 	assert.Equal(t, vwapDataResp, []*VWAP{
 		{Value: float64(3), Date: now},
 		{Value: float64(4), Date: now.Add(time.Minute * 5)}})
+
+	trendReq := NewTrendRequest(market, timeframe, MacroTrend)
+	assert.NotNil(t, trendReq)
+	assert.Equal(t, trendReq.N, int32(MacroTrend))
+	go func() { trendReq.Response <- StrongBullishTrend }()
+	trendResp := <-trendReq.Response
+	assert.Equal(t, trendResp, StrongBullishTrend)
+
+	movingAverageReq := NewMovingAverageRequest(market, timeframe)
+	assert.NotNil(t, movingAverageReq)
+	go func() { movingAverageReq.Response <- &MovingAverage{Value: float64(3), Date: now} }()
+	movingAverageResp := <-movingAverageReq.Response
+	assert.Equal(t, movingAverageResp, &MovingAverage{Value: float64(3), Date: now})
+}
+
+func TestTimeouts(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeouts *Timeouts
+		want     [4]time.Duration
+	}{
+		{"nil timeouts", nil,
+			[4]time.Duration{TimeoutDuration, TimeoutDuration, TimeoutDuration, TimeoutDuration}},
+		{"zero timeouts", &Timeouts{},
+			[4]time.Duration{TimeoutDuration, TimeoutDuration, TimeoutDuration, TimeoutDuration}},
+		{"configured timeouts", &Timeouts{PriceData: time.Second, VWAP: time.Second * 2,
+			CandleMetadata: time.Millisecond * 500, SignalStatus: time.Second * 8},
+			[4]time.Duration{time.Second, time.Second * 2, time.Millisecond * 500, time.Second * 8}},
+		{"partial timeouts", &Timeouts{CandleMetadata: time.Second},
+			[4]time.Duration{TimeoutDuration, TimeoutDuration, time.Second, TimeoutDuration}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Ensure each operation resolves its own timeout, defaulting unset timeouts.
+			got := [4]time.Duration{test.timeouts.PriceDataTimeout(), test.timeouts.VWAPTimeout(),
+				test.timeouts.CandleMetadataTimeout(), test.timeouts.SignalStatusTimeout()}
+			assert.Equal(t, got, test.want)
+		})
+	}
+
+	// Ensure negative timeouts are rejected.
+	timeouts := &Timeouts{PriceData: -time.Second, SignalStatus: -time.Second}
+	assert.Error(t, timeouts.Validate())
+	assert.NoError(t, (&Timeouts{}).Validate())
 }
@@ -34,11 +34,12 @@ const (
 
 // Session represents a market session.
 type Session struct {
-	Name  string
-	High  atomic.Float64
-	Low   atomic.Float64
-	Open  time.Time
-	Close time.Time
+	Name    string
+	High    atomic.Float64
+	Low     atomic.Float64
+	Open    time.Time
+	Close   time.Time
+	Profile *VolumeProfile
 }
 
 // NewSession initializes new market session.
@@ -73,8 +74,8 @@ func NewSession(name string, open string, close string, now time.Time) (*Session
 	return session, nil
 }
 
-// Update updates the provided session's high and low.
-func (s *Session) Update(candle *Candlestick) {
+// Update updates the provided session's high, low and volume profile.
+func (s *Session) Update(candle *Candlestick) error {
 	low := s.Low.Load()
 	high := s.High.Load()
 
@@ -84,6 +85,15 @@ func (s *Session) Update(candle *Candlestick) {
 	if high == 0 || candle.High > high {
 		s.High.Store(candle.High)
 	}
+
+	if s.Profile != nil {
+		err := s.Profile.Update(candle)
+		if err != nil {
+			return fmt.Errorf("updating %s volume profile: %w", s.Name, err)
+		}
+	}
+
+	return nil
 }
 
 // IsCurrentSession checks whether the provided session is the current session.
@@ -91,46 +101,38 @@ func (s *Session) IsCurrentSession(current time.Time) bool {
 	return current.After(s.Open) && (current.Before(s.Close) || current.Equal(s.Close))
 }
 
-// CurrentSession returns the current active session name.
-func CurrentSession(now time.Time) (string, *Session, error) {
-	yesterday := now.AddDate(0, 0, -1)
-
-	sessions := []struct {
-		name  string
-		open  string
-		close string
-		time  time.Time
-	}{
-		{Asia, AsiaOpen, AsiaClose, yesterday},
-		{London, LondonOpen, LondonClose, now},
-		{NewYork, NewYorkOpen, NewYorkClose, now},
-		{Asia, AsiaOpen, AsiaClose, now},
+// CurrentSession returns the current active session of the provided session definitions, the
+// default definitions are used if none are provided. Overlapping sessions resolve to the first
+// defined.
+func CurrentSession(now time.Time, definitions []SessionDefinition) (string, *Session, error) {
+	if len(definitions) == 0 {
+		definitions = DefaultSessionDefinitions
 	}
 
-	var currentSession *Session
-	for _, sess := range sessions {
-		session, err := NewSession(sess.name, sess.open, sess.close, sess.time)
-		if err != nil {
-			return "", nil, fmt.Errorf("creating %s session: %w", sess.name, err)
-		}
-
-		if (now.Equal(session.Open) || now.After(session.Open)) && now.Before(session.Close) {
-			currentSession = session
-			break
+	// Sessions of the previous day can run into the provided day.
+	yesterday := now.AddDate(0, 0, -1)
+	for _, day := range []time.Time{yesterday, now} {
+		for idx := range definitions {
+			definition := &definitions[idx]
+			session, err := definition.NewSession(day)
+			if err != nil {
+				return "", nil, fmt.Errorf("creating %s session: %w", definition.Name, err)
+			}
+
+			if (now.Equal(session.Open) || now.After(session.Open)) && now.Before(session.Close) {
+				return session.Name, session, nil
+			}
 		}
 	}
 
-	if currentSession != nil {
-		return currentSession.Name, currentSession, nil
-	}
-
 	return "", nil, nil
 }
 
 // IsMarketOpen checks whether the markets (only futures) are open by checking if the current
-// time is within one of the market sessions.
-func IsMarketOpen(now time.Time) (bool, string, error) {
-	name, _, err := CurrentSession(now)
+// time is within one of the provided market sessions, the default session definitions are used
+// if none are provided.
+func IsMarketOpen(now time.Time, definitions []SessionDefinition) (bool, string, error) {
+	name, _, err := CurrentSession(now, definitions)
 	if err != nil {
 		return false, name, fmt.Errorf("fetching current market session: %v", err)
 	}
@@ -143,16 +145,21 @@ func IsMarketOpen(now time.Time) (bool, string, error) {
 	return open, name, nil
 }
 
-// InHighVolumeWindow check whether the provided time is within the high volume window for the day.
-func InHighVolumeWindow(now time.Time) (bool, error) {
-	highVolumeWindow, err := NewSession("hvw", HighVolumeWindowOpen, HighVolumeWindowClose, now)
-	if err != nil {
-		return false, fmt.Errorf("creating high volume window session: %v", err)
+// InHighVolumeWindow check whether the provided time is within any of the provided high volume
+// windows for the day. The default high volume windows are used if none are provided.
+func InHighVolumeWindow(now time.Time, windows []SessionWindow) (bool, error) {
+	if len(windows) == 0 {
+		windows = DefaultHighVolumeWindows
 	}
 
-	if (now.Equal(highVolumeWindow.Open) || now.After(highVolumeWindow.Open)) &&
-		(now.Equal(highVolumeWindow.Close) || now.Before(highVolumeWindow.Close)) {
-		return true, nil
+	for idx := range windows {
+		in, err := windows[idx].Contains(now)
+		if err != nil {
+			return false, fmt.Errorf("checking high volume window: %v", err)
+		}
+		if in {
+			return true, nil
+		}
 	}
 
 	return false, nil
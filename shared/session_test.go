@@ -54,7 +54,7 @@ func TestSession(t *testing.T) {
 	assert.NotNil(t, current)
 
 	// Ensure it can be checked if the market is open.
-	open, _, err := IsMarketOpen(newYork.Open)
+	open, _, err := IsMarketOpen(newYork.Open, nil)
 	assert.NoError(t, err)
 	assert.True(t, open)
 
@@ -73,7 +73,7 @@ func TestSession(t *testing.T) {
 	noSessionTime := time.Date(now.Year(), now.Month(), now.Day(), noSession.Hour(), noSession.Minute(), 0, 0, loc)
 
 	// Ensure the any provided time can be checked to be within the high volume window.
-	hwv, err := InHighVolumeWindow(noSessionTime)
+	hwv, err := InHighVolumeWindow(noSessionTime, nil)
 	assert.NoError(t, err)
 	assert.False(t, hwv)
 
@@ -83,12 +83,34 @@ func TestSession(t *testing.T) {
 
 	highVolumeWindowTime := time.Date(now.Year(), now.Month(), now.Day(), highVolumeWindow.Hour(), highVolumeWindow.Minute(), 0, 0, loc)
 
-	hwv, err = InHighVolumeWindow(highVolumeWindowTime)
+	hwv, err = InHighVolumeWindow(highVolumeWindowTime, nil)
 	assert.NoError(t, err)
 	assert.True(t, hwv)
 
-	name, session, err := CurrentSession(noSessionTime)
+	name, session, err := CurrentSession(noSessionTime, nil)
 	assert.NoError(t, err)
 	assert.Nil(t, session)
 	assert.Equal(t, name, "")
+
+	// Ensure overlapping default sessions resolve to the first defined.
+	name, _, err = CurrentSession(highVolumeWindowTime, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, name, London)
+
+	// Ensure the current session and market open status follow the provided sessions.
+	definitions := []SessionDefinition{
+		{Name: "rth", Open: "09:30", Close: "16:00"},
+		{Name: "evening", Open: "17:00", Close: "20:00"},
+	}
+	name, session, err = CurrentSession(noSessionTime, definitions)
+	assert.NoError(t, err)
+	assert.NotNil(t, session)
+	assert.Equal(t, name, "evening")
+	open, name, err = IsMarketOpen(noSessionTime, definitions)
+	assert.NoError(t, err)
+	assert.True(t, open)
+	assert.Equal(t, name, "evening")
+	open, _, err = IsMarketOpen(highVolumeWindowTime, definitions)
+	assert.NoError(t, err)
+	assert.False(t, open)
 }
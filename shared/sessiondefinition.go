@@ -0,0 +1,154 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SessionDefinition represents a daily market session.
+type SessionDefinition struct {
+	// Name is the name of the session.
+	Name string
+	// Open is the HH:MM time the session opens at.
+	Open string
+	// Close is the HH:MM time the session closes at. Sessions closing before they open
+	// close on the following day.
+	Close string
+	// Location is the timezone the session times are in. New york time is used if empty.
+	Location string
+}
+
+// DefaultSessionDefinitions are the sessions tracked if none are configured, the asia, london
+// and new york futures sessions. The new york session closes the trading day.
+var DefaultSessionDefinitions = []SessionDefinition{
+	{Name: Asia, Open: AsiaOpen, Close: AsiaClose},
+	{Name: London, Open: LondonOpen, Close: LondonClose},
+	{Name: NewYork, Open: NewYorkOpen, Close: NewYorkClose},
+}
+
+// Validate asserts the session definition sane inputs.
+func (d *SessionDefinition) Validate() error {
+	var errs error
+	if d.Name == "" {
+		errs = errors.Join(errs, fmt.Errorf("session name cannot be empty"))
+	}
+	_, err := time.Parse(SessionTimeLayout, d.Open)
+	if err != nil {
+		errs = errors.Join(errs, fmt.Errorf("parsing session open: %v", err))
+	}
+	_, err = time.Parse(SessionTimeLayout, d.Close)
+	if err != nil {
+		errs = errors.Join(errs, fmt.Errorf("parsing session close: %v", err))
+	}
+	if d.Open == d.Close {
+		errs = errors.Join(errs, fmt.Errorf("session open and close cannot be the same"))
+	}
+	if d.Location != "" {
+		_, err = time.LoadLocation(d.Location)
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("loading session location: %v", err))
+		}
+	}
+
+	return errs
+}
+
+// NewSession initializes the session opening on the provided day. The provided time and
+// the session's open and close are in new york time.
+func (d *SessionDefinition) NewSession(day time.Time) (*Session, error) {
+	if d.Location == "" || d.Location == NewYorkLocation {
+		return NewSession(d.Name, d.Open, d.Close, day)
+	}
+
+	ny := day.Location()
+	if ny.String() != NewYorkLocation {
+		return nil, fmt.Errorf("expected new york location for provided time, got %v", ny.String())
+	}
+
+	loc, err := time.LoadLocation(d.Location)
+	if err != nil {
+		return nil, fmt.Errorf("loading %s location: %w", d.Location, err)
+	}
+
+	sessionOpen, err := time.Parse(SessionTimeLayout, d.Open)
+	if err != nil {
+		return nil, fmt.Errorf("parsing session open: %w", err)
+	}
+
+	sessionClose, err := time.Parse(SessionTimeLayout, d.Close)
+	if err != nil {
+		return nil, fmt.Errorf("parsing session close: %w", err)
+	}
+
+	// The session opens on the new york day at its time in the session location.
+	sOpen := time.Date(day.Year(), day.Month(), day.Day(), sessionOpen.Hour(), sessionOpen.Minute(), 0, 0, loc)
+	sClose := time.Date(day.Year(), day.Month(), day.Day(), sessionClose.Hour(), sessionClose.Minute(), 0, 0, loc)
+	if sClose.Before(sOpen) {
+		sClose = sClose.Add(time.Hour * 24)
+	}
+
+	session := &Session{
+		Name:  d.Name,
+		Open:  sOpen.In(ny),
+		Close: sClose.In(ny),
+	}
+
+	return session, nil
+}
+
+// ValidateSessionDefinitions asserts the provided session definitions are valid and uniquely
+// named.
+func ValidateSessionDefinitions(definitions []SessionDefinition) error {
+	var errs error
+	names := make(map[string]struct{}, len(definitions))
+	for idx := range definitions {
+		definition := definitions[idx]
+		err := definition.Validate()
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("validating %s session: %v", definition.Name, err))
+		}
+
+		_, ok := names[definition.Name]
+		if ok {
+			errs = errors.Join(errs, fmt.Errorf("duplicate %s session provided", definition.Name))
+		}
+		names[definition.Name] = struct{}{}
+	}
+
+	return errs
+}
+
+// ParseSessionDefinitions parses session definitions from the provided entries of the form
+// name=HH:MM-HH:MM with an optional @location suffix, e.g. rth=09:30-16:00@America/New_York.
+// The last session closes the trading day.
+func ParseSessionDefinitions(entries []string) ([]SessionDefinition, error) {
+	definitions := make([]SessionDefinition, 0, len(entries))
+	for idx := range entries {
+		name, times, ok := strings.Cut(entries[idx], "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid session entry provided: %s", entries[idx])
+		}
+
+		times, location, _ := strings.Cut(times, "@")
+		open, close, ok := strings.Cut(times, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid session entry provided: %s", entries[idx])
+		}
+
+		definitions = append(definitions, SessionDefinition{
+			Name:     strings.TrimSpace(name),
+			Open:     open,
+			Close:    close,
+			Location: location,
+		})
+	}
+
+	err := ValidateSessionDefinitions(definitions)
+	if err != nil {
+		return nil, err
+	}
+
+	return definitions, nil
+}
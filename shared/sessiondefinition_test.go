@@ -0,0 +1,77 @@
+package shared
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestParseSessionDefinitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		want    []SessionDefinition
+		wantErr bool
+	}{
+		{"no entries", nil, []SessionDefinition{}, false},
+		{"valid entries", []string{"rth=09:30-16:00", "london=08:00-16:30@Europe/London"},
+			[]SessionDefinition{
+				{Name: "rth", Open: "09:30", Close: "16:00"},
+				{Name: "london", Open: "08:00", Close: "16:30", Location: "Europe/London"},
+			}, false},
+		{"missing separator", []string{"rth"}, nil, true},
+		{"missing session close", []string{"rth=09:30"}, nil, true},
+		{"missing name", []string{"=09:30-16:00"}, nil, true},
+		{"invalid time", []string{"rth=09:30-25:00"}, nil, true},
+		{"invalid location", []string{"rth=09:30-16:00@Nowhere/Land"}, nil, true},
+		{"duplicate sessions", []string{"rth=09:30-16:00", "rth=10:00-15:00"}, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			definitions, err := ParseSessionDefinitions(test.entries)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, definitions, test.want)
+		})
+	}
+}
+
+func TestSessionDefinitionNewSession(t *testing.T) {
+	now, loc, err := NewYorkTimeFrom(testClock(t))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		definition SessionDefinition
+		wantOpen   time.Time
+		wantClose  time.Time
+	}{
+		{"new york session", SessionDefinition{Name: "rth", Open: "09:30", Close: "16:00"},
+			time.Date(now.Year(), now.Month(), now.Day(), 9, 30, 0, 0, loc),
+			time.Date(now.Year(), now.Month(), now.Day(), 16, 0, 0, 0, loc)},
+		{"localized session", SessionDefinition{Name: "london", Open: "08:00", Close: "16:30", Location: "Europe/London"},
+			time.Date(now.Year(), now.Month(), now.Day(), 3, 0, 0, 0, loc),
+			time.Date(now.Year(), now.Month(), now.Day(), 11, 30, 0, 0, loc)},
+		{"session closing the following day", SessionDefinition{Name: "overnight", Open: "16:00", Close: "09:30"},
+			time.Date(now.Year(), now.Month(), now.Day(), 16, 0, 0, 0, loc),
+			time.Date(now.Year(), now.Month(), now.Day()+1, 9, 30, 0, 0, loc)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Ensure sessions are created in new york time from their localized definition.
+			session, err := test.definition.NewSession(now)
+			assert.NoError(t, err)
+			assert.Equal(t, session.Name, test.definition.Name)
+			assert.True(t, session.Open.Equal(test.wantOpen))
+			assert.True(t, session.Close.Equal(test.wantClose))
+			assert.Equal(t, session.Open.Location().String(), NewYorkLocation)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package shared
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -18,29 +19,45 @@ const (
 
 // CandlestickSnapshot represents a snapshot of session data.
 type SessionSnapshot struct {
-	data    []*Session
-	start   atomic.Int32
-	current atomic.Int32
-	count   atomic.Int32
-	size    atomic.Int32
+	data        []*Session
+	binSize     float64
+	definitions []SessionDefinition
+	start       atomic.Int32
+	current     atomic.Int32
+	count       atomic.Int32
+	size        atomic.Int32
 }
 
-// NewSessionSnapshot initializes a new session snapshot.
-func NewSessionSnapshot(size int32, now time.Time) (*SessionSnapshot, error) {
+// NewSessionSnapshot initializes a new session snapshot of the provided session definitions,
+// the default definitions are used if none are provided. Sessions of the snapshot build
+// volume profiles binned by the provided bin size.
+func NewSessionSnapshot(size int32, binSize float64, definitions []SessionDefinition, now time.Time) (*SessionSnapshot, error) {
 	if size < 0 {
 		return nil, errors.New("snapshot size cannot be negative")
 	}
 	if size == 0 {
 		return nil, errors.New("snapshot size cannot be zero")
 	}
+	if binSize <= 0 {
+		return nil, errors.New("volume profile bin size must be greater than zero")
+	}
+	if len(definitions) == 0 {
+		definitions = DefaultSessionDefinitions
+	}
+	err := ValidateSessionDefinitions(definitions)
+	if err != nil {
+		return nil, fmt.Errorf("validating session definitions: %v", err)
+	}
 
 	snapshot := &SessionSnapshot{
-		data: make([]*Session, size),
+		data:        make([]*Session, size),
+		binSize:     binSize,
+		definitions: definitions,
 	}
 
 	snapshot.size.Store(size)
 
-	err := snapshot.GenerateNewSessions(now)
+	err = snapshot.GenerateNewSessions(now)
 	if err != nil {
 		return nil, fmt.Errorf("adding sessions to snapshot: %v", err)
 	}
@@ -86,29 +103,41 @@ func (s *SessionSnapshot) Exists(name string, open time.Time) bool {
 	return false
 }
 
-// GenerateNewSessions generate a new set of sessions for the snapshot.
+// GenerateNewSessions generate a new set of sessions for the snapshot, the sessions of the
+// day and the sessions of the previous day running into it, ordered by their open.
 func (s *SessionSnapshot) GenerateNewSessions(now time.Time) error {
 	yesterday := now.AddDate(0, 0, -1)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	sessions := make([]*Session, 0, len(s.definitions)*2)
+	for _, day := range []time.Time{yesterday, now} {
+		for idx := range s.definitions {
+			definition := &s.definitions[idx]
+			session, err := definition.NewSession(day)
+			if err != nil {
+				return fmt.Errorf("creating %s session: %w", definition.Name, err)
+			}
+
+			if day.Equal(yesterday) && !session.Close.After(today) {
+				continue
+			}
 
-	sessions := []struct {
-		name  string
-		open  string
-		close string
-		time  time.Time
-	}{
-		{Asia, AsiaOpen, AsiaClose, yesterday},
-		{London, LondonOpen, LondonClose, now},
-		{NewYork, NewYorkOpen, NewYorkClose, now},
-		{Asia, AsiaOpen, AsiaClose, now},
-	}
-
-	for _, sess := range sessions {
-		session, err := NewSession(sess.name, sess.open, sess.close, sess.time)
-		if err != nil {
-			return fmt.Errorf("creating %s session: %w", sess.name, err)
+			sessions = append(sessions, session)
 		}
+	}
+
+	slices.SortStableFunc(sessions, func(a, b *Session) int {
+		return a.Open.Compare(b.Open)
+	})
 
+	for _, session := range sessions {
 		if !s.Exists(session.Name, session.Open) {
+			var err error
+			session.Profile, err = NewVolumeProfile(s.binSize)
+			if err != nil {
+				return fmt.Errorf("creating %s session volume profile: %w", session.Name, err)
+			}
+
 			s.Add(session)
 		}
 	}
@@ -116,17 +145,24 @@ func (s *SessionSnapshot) GenerateNewSessions(now time.Time) error {
 	return nil
 }
 
-// GenerateNewSessionJob is a job used to generate new sessions.
+// ClosesDay checks whether the provided session closes the trading day, the last session of
+// the snapshot's definitions.
+func (s *SessionSnapshot) ClosesDay(name string) bool {
+	return s.definitions[len(s.definitions)-1].Name == name
+}
+
+// GenerateNewSessionJob is a job used to generate new sessions for the current time of the
+// provided clock.
 //
 // This job should be scheduled for periodic execution.
-func (s *SessionSnapshot) GenerateNewSessionsJob(logger *zerolog.Logger) {
-	now, _, err := NewYorkTime()
+func (s *SessionSnapshot) GenerateNewSessionsJob(clock Clock, logger *zerolog.Logger) {
+	loc, err := time.LoadLocation(NewYorkLocation)
 	if err != nil {
-		logger.Error().Msgf("fetching new york time: %v", err)
+		logger.Error().Msgf("loading new york location: %v", err)
 		return
 	}
 
-	err = s.GenerateNewSessions(now)
+	err = s.GenerateNewSessions(clock.Now().In(loc))
 	if err != nil {
 		logger.Error().Msgf("generating new sessions: %v", err)
 		return
@@ -157,15 +193,15 @@ func (s *SessionSnapshot) SetCurrentSession(now time.Time) (bool, error) {
 	}
 
 	// If the current session is not set then the market is closed and current time is
-	// approaching the asian session. Preemptively set the asian session.
+	// approaching the next session. Preemptively set the next session.
 	if !set {
 		start := s.start.Load()
 		count := s.count.Load()
 		size := s.size.Load()
 		for i := range count {
-			idx := (start + count - 1 - i + size) % size
+			idx := (start + i) % size
 			session := s.data[idx]
-			if session.Name == Asia && now.Before(session.Open) {
+			if now.Before(session.Open) {
 				if prev != idx {
 					// The changed flag indicates there has been a session change.
 					changed = true
@@ -228,3 +264,81 @@ func (s *SessionSnapshot) FetchLastSessionHighLow() (float64, float64, error) {
 
 	return 0, 0, fmt.Errorf("session snapshot has no elements")
 }
+
+// FetchLastSessionName returns the name of the previously completed session.
+func (s *SessionSnapshot) FetchLastSessionName() (string, error) {
+	count := s.count.Load()
+	if count == 0 {
+		return "", fmt.Errorf("session snapshot has no elements")
+	}
+
+	current := s.current.Load()
+	start := s.start.Load()
+	size := s.size.Load()
+	if current == start {
+		return "", fmt.Errorf("no completed previous session available")
+	}
+
+	previous := (current - 1 + size) % size
+	return s.data[previous].Name, nil
+}
+
+// FetchLastDayHighLow returns the high and low of the trading day closed by the previously
+// completed session, one session per definition preceding the current session. Sessions
+// without price data are ignored.
+func (s *SessionSnapshot) FetchLastDayHighLow() (float64, float64, error) {
+	count := s.count.Load()
+	if count == 0 {
+		return 0, 0, fmt.Errorf("session snapshot has no elements")
+	}
+
+	current := s.current.Load()
+	start := s.start.Load()
+	size := s.size.Load()
+
+	var high, low float64
+	idx := current
+	for range len(s.definitions) {
+		if idx == start {
+			return 0, 0, fmt.Errorf("no completed trading day available")
+		}
+
+		idx = (idx - 1 + size) % size
+		sessionHigh := s.data[idx].High.Load()
+		sessionLow := s.data[idx].Low.Load()
+		if sessionHigh == 0 || sessionLow == 0 {
+			continue
+		}
+
+		high = max(high, sessionHigh)
+		if low == 0 || sessionLow < low {
+			low = sessionLow
+		}
+	}
+
+	return high, low, nil
+}
+
+// FetchLastSessionVolumeProfile returns the volume profile of the previously completed session.
+func (s *SessionSnapshot) FetchLastSessionVolumeProfile() (*VolumeProfile, error) {
+	count := s.count.Load()
+	if count == 0 {
+		return nil, fmt.Errorf("session snapshot has no elements")
+	}
+
+	current := s.current.Load()
+	start := s.start.Load()
+	size := s.size.Load()
+	if current == start {
+		// There is no previous completed session.
+		return nil, fmt.Errorf("no completed previous session available")
+	}
+
+	previous := (current - 1 + size) % size
+	profile := s.data[previous].Profile
+	if profile == nil {
+		return nil, fmt.Errorf("no volume profile found for the previous session")
+	}
+
+	return profile, nil
+}
@@ -8,20 +8,34 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// testClock returns a replay clock fixed at a weekday new york session time, keeping
+// session tests independent of the wall clock.
+func testClock(t *testing.T) *ReplayClock {
+	loc, err := time.LoadLocation(NewYorkLocation)
+	assert.NoError(t, err)
+
+	return NewReplayClock(time.Date(2025, 5, 7, 10, 30, 0, 0, loc))
+}
+
 func TestSessionSnapshot(t *testing.T) {
-	now, loc, err := NewYorkTime()
+	clock := testClock(t)
+	now, loc, err := NewYorkTimeFrom(clock)
 	assert.NoError(t, err)
 
 	// Ensure session snapshot size cannot be negaitve or zero.
-	sessionSnapshot, err := NewSessionSnapshot(-1, now)
+	sessionSnapshot, err := NewSessionSnapshot(-1, DefaultVolumeProfileBinSize, nil, now)
 	assert.Error(t, err)
 
-	sessionSnapshot, err = NewSessionSnapshot(0, now)
+	sessionSnapshot, err = NewSessionSnapshot(0, DefaultVolumeProfileBinSize, nil, now)
+	assert.Error(t, err)
+
+	// Ensure the volume profile bin size cannot be zero.
+	sessionSnapshot, err = NewSessionSnapshot(4, 0, nil, now)
 	assert.Error(t, err)
 
 	// Ensure a session snapshot can be created.
 	size := int32(4)
-	sessionSnapshot, err = NewSessionSnapshot(size, now)
+	sessionSnapshot, err = NewSessionSnapshot(size, DefaultVolumeProfileBinSize, nil, now)
 	assert.NoError(t, err)
 
 	assert.Equal(t, sessionSnapshot.count.Load(), size)
@@ -44,6 +58,11 @@ func TestSessionSnapshot(t *testing.T) {
 	assert.Equal(t, high, 0)
 	assert.Equal(t, low, 0)
 
+	// Ensure the last session volume profile can be fetched.
+	profile, err := sessionSnapshot.FetchLastSessionVolumeProfile()
+	assert.NoError(t, err)
+	assert.Equal(t, profile.TotalVolume(), float64(0))
+
 	tomorrow := now.AddDate(0, 0, 1)
 
 	// Ensure adding a session at capacity advances the start index for the next addition.
@@ -70,7 +89,7 @@ func TestSessionSnapshot(t *testing.T) {
 	assert.Equal(t, sessionSnapshot.FetchCurrentSession().Name, Asia)
 
 	// Ensure sessions jobs can be executed.
-	sessionSnapshot.GenerateNewSessionsJob(&zerolog.Logger{})
+	sessionSnapshot.GenerateNewSessionsJob(clock, &zerolog.Logger{})
 
 	// Fake the current session being the session beginning the snapshot.
 	sessionSnapshot.current.Store(sessionSnapshot.start.Load())
@@ -84,17 +103,21 @@ func TestSessionSnapshot(t *testing.T) {
 	// Ensure fetching the last session high and low returns an error if there are no past sessions.
 	_, _, err = sessionSnapshot.FetchLastSessionHighLow()
 	assert.Error(t, err)
+
+	// Ensure fetching the last session volume profile returns an error if there are no past sessions.
+	_, err = sessionSnapshot.FetchLastSessionVolumeProfile()
+	assert.Error(t, err)
 }
 
 func TestGenerateNewSessions(t *testing.T) {
-	now, _, err := NewYorkTime()
+	now, _, err := NewYorkTimeFrom(testClock(t))
 	assert.NoError(t, err)
 
 	yesterday := now.AddDate(0, 0, -1)
 	tomorrow := now.AddDate(0, 0, 1)
 	tomorrowNext := tomorrow.AddDate(0, 0, 1)
 
-	sessionSnapshot, err := NewSessionSnapshot(SessionSnapshotSize, now)
+	sessionSnapshot, err := NewSessionSnapshot(SessionSnapshotSize, DefaultVolumeProfileBinSize, nil, now)
 	assert.NoError(t, err)
 
 	// Asia -> London -> New York -> Asia (today-tomorrow)
@@ -128,3 +151,122 @@ func TestGenerateNewSessions(t *testing.T) {
 	assert.Equal(t, sessionSnapshot.data[6].Open.Day(), tomorrow.Day())
 	assert.Equal(t, sessionSnapshot.data[6].Close.Day(), tomorrowNext.Day())
 }
+
+func TestFetchLastDayHighLow(t *testing.T) {
+	clock := testClock(t)
+	now, _, err := NewYorkTimeFrom(clock)
+	assert.NoError(t, err)
+
+	sessionSnapshot, err := NewSessionSnapshot(4, DefaultVolumeProfileBinSize, nil, now)
+	assert.NoError(t, err)
+
+	prices := []struct {
+		high float64
+		low  float64
+	}{{12, 8}, {15, 10}, {0, 0}}
+	for idx, price := range prices {
+		sessionSnapshot.data[idx].High.Store(price.high)
+		sessionSnapshot.data[idx].Low.Store(price.low)
+	}
+
+	// Ensure the day high and low cannot be fetched without a completed trading day.
+	sessionSnapshot.current.Store(2)
+	_, _, err = sessionSnapshot.FetchLastDayHighLow()
+	assert.Error(t, err)
+
+	// Ensure the day high and low span the sessions of the trading day, ignoring sessions
+	// without price data.
+	sessionSnapshot.current.Store(3)
+	high, low, err := sessionSnapshot.FetchLastDayHighLow()
+	assert.NoError(t, err)
+	assert.Equal(t, high, float64(15))
+	assert.Equal(t, low, float64(8))
+
+	// Ensure the last session name can be fetched.
+	name, err := sessionSnapshot.FetchLastSessionName()
+	assert.NoError(t, err)
+	assert.Equal(t, name, sessionSnapshot.data[2].Name)
+
+	// Ensure the last session name cannot be fetched without a completed session.
+	sessionSnapshot.current.Store(sessionSnapshot.start.Load())
+	_, err = sessionSnapshot.FetchLastSessionName()
+	assert.Error(t, err)
+}
+
+func TestCustomSessions(t *testing.T) {
+	now, loc, err := NewYorkTimeFrom(testClock(t))
+	assert.NoError(t, err)
+
+	at := func(hour int, minute int) time.Time {
+		return time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, loc)
+	}
+
+	definitions := []SessionDefinition{
+		{Name: "overnight", Open: "16:00", Close: "09:30"},
+		{Name: "rth", Open: "09:30", Close: "16:00"},
+	}
+
+	// Ensure invalid session definitions are rejected.
+	_, err = NewSessionSnapshot(4, DefaultVolumeProfileBinSize,
+		[]SessionDefinition{{Name: "rth", Open: "09:30", Close: "09:30"}}, now)
+	assert.Error(t, err)
+
+	sessionSnapshot, err := NewSessionSnapshot(SessionSnapshotSize, DefaultVolumeProfileBinSize, definitions, now)
+	assert.NoError(t, err)
+
+	// Overnight (yesterday-today) -> RTH -> Overnight (today-tomorrow)
+	assert.Equal(t, sessionSnapshot.count.Load(), 3)
+	assert.Equal(t, sessionSnapshot.data[0].Name, "overnight")
+	assert.Equal(t, sessionSnapshot.data[0].Close, at(9, 30))
+	assert.Equal(t, sessionSnapshot.data[1].Name, "rth")
+	assert.Equal(t, sessionSnapshot.data[1].Open, at(9, 30))
+	assert.Equal(t, sessionSnapshot.data[2].Name, "overnight")
+	assert.Equal(t, sessionSnapshot.data[2].Open, at(16, 0))
+
+	candles := []struct {
+		date time.Time
+		high float64
+		low  float64
+	}{
+		{at(8, 0), 10, 5},
+		{at(9, 0), 11, 7},
+		{at(10, 0), 14, 9},
+		{at(15, 0), 12, 8},
+	}
+	for _, candle := range candles {
+		_, err := sessionSnapshot.SetCurrentSession(candle.date)
+		assert.NoError(t, err)
+		sessionSnapshot.FetchCurrentSession().Update(&Candlestick{High: candle.high, Low: candle.low})
+	}
+
+	// Ensure the last session high and low come from the overnight session window.
+	high, low, err := sessionSnapshot.FetchLastSessionHighLow()
+	assert.NoError(t, err)
+	assert.Equal(t, high, float64(11))
+	assert.Equal(t, low, float64(5))
+
+	name, err := sessionSnapshot.FetchLastSessionName()
+	assert.NoError(t, err)
+	assert.Equal(t, name, "overnight")
+	assert.False(t, sessionSnapshot.ClosesDay(name))
+
+	// Ensure the rth session high and low are derived from its window once it completes.
+	_, err = sessionSnapshot.SetCurrentSession(at(17, 0))
+	assert.NoError(t, err)
+
+	high, low, err = sessionSnapshot.FetchLastSessionHighLow()
+	assert.NoError(t, err)
+	assert.Equal(t, high, float64(14))
+	assert.Equal(t, low, float64(8))
+
+	// Ensure the last configured session closes the trading day, spanning every session.
+	name, err = sessionSnapshot.FetchLastSessionName()
+	assert.NoError(t, err)
+	assert.Equal(t, name, "rth")
+	assert.True(t, sessionSnapshot.ClosesDay(name))
+
+	high, low, err = sessionSnapshot.FetchLastDayHighLow()
+	assert.NoError(t, err)
+	assert.Equal(t, high, float64(14))
+	assert.Equal(t, low, float64(5))
+}
@@ -0,0 +1,82 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SessionWindow represents a daily window of time in new york time (ET).
+type SessionWindow struct {
+	// Start is the HH:MM time the window opens at.
+	Start string
+	// End is the HH:MM time the window closes at. Windows ending before they start close
+	// on the following day.
+	End string
+}
+
+// DefaultHighVolumeWindows are the high volume windows used if none are configured, the
+// london and new york session overlap.
+var DefaultHighVolumeWindows = []SessionWindow{
+	{Start: HighVolumeWindowOpen, End: HighVolumeWindowClose},
+}
+
+// Validate asserts the window sane inputs.
+func (w *SessionWindow) Validate() error {
+	var errs error
+
+	_, err := time.Parse(SessionTimeLayout, w.Start)
+	if err != nil {
+		errs = errors.Join(errs, fmt.Errorf("parsing window start: %v", err))
+	}
+	_, err = time.Parse(SessionTimeLayout, w.End)
+	if err != nil {
+		errs = errors.Join(errs, fmt.Errorf("parsing window end: %v", err))
+	}
+	if w.Start == w.End {
+		errs = errors.Join(errs, fmt.Errorf("window start and end cannot be the same"))
+	}
+
+	return errs
+}
+
+// Contains checks whether the provided new york localized time is within the window. The
+// window is inclusive of its start and end.
+func (w *SessionWindow) Contains(now time.Time) (bool, error) {
+	// Windows wrapping past midnight are checked from the previous day as well.
+	for _, day := range []time.Time{now, now.AddDate(0, 0, -1)} {
+		window, err := NewSession("window", w.Start, w.End, day)
+		if err != nil {
+			return false, fmt.Errorf("creating %s-%s window session: %v", w.Start, w.End, err)
+		}
+
+		if !now.Before(window.Open) && !now.After(window.Close) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// ParseSessionWindows parses session windows from the provided entries of the form
+// HH:MM-HH:MM.
+func ParseSessionWindows(entries []string) ([]SessionWindow, error) {
+	windows := make([]SessionWindow, 0, len(entries))
+	for idx := range entries {
+		start, end, ok := strings.Cut(entries[idx], "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid session window entry provided: %s", entries[idx])
+		}
+
+		window := SessionWindow{Start: start, End: end}
+		err := window.Validate()
+		if err != nil {
+			return nil, fmt.Errorf("validating %s session window: %v", entries[idx], err)
+		}
+
+		windows = append(windows, window)
+	}
+
+	return windows, nil
+}
@@ -0,0 +1,88 @@
+package shared
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestSessionWindowContains(t *testing.T) {
+	loc, err := time.LoadLocation(NewYorkLocation)
+	assert.NoError(t, err)
+
+	at := func(hour, minute int) time.Time {
+		return time.Date(2025, 5, 7, hour, minute, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name   string
+		window SessionWindow
+		now    time.Time
+		want   bool
+	}{
+		{"inside", SessionWindow{Start: "8:30", End: "11:00"}, at(9, 0), true},
+		{"at start", SessionWindow{Start: "8:30", End: "11:00"}, at(8, 30), true},
+		{"at end", SessionWindow{Start: "8:30", End: "11:00"}, at(11, 0), true},
+		{"before", SessionWindow{Start: "8:30", End: "11:00"}, at(8, 29), false},
+		{"after", SessionWindow{Start: "8:30", End: "11:00"}, at(11, 1), false},
+		{"wrapping before midnight", SessionWindow{Start: "22:00", End: "2:00"}, at(23, 0), true},
+		{"wrapping after midnight", SessionWindow{Start: "22:00", End: "2:00"}, at(1, 0), true},
+		{"outside wrapping", SessionWindow{Start: "22:00", End: "2:00"}, at(3, 0), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			in, err := test.window.Contains(test.now)
+			assert.NoError(t, err)
+			assert.Equal(t, in, test.want)
+		})
+	}
+
+	// Ensure times not localized to new york cannot be checked.
+	window := SessionWindow{Start: "8:30", End: "11:00"}
+	_, err = window.Contains(at(9, 0).UTC())
+	assert.Error(t, err)
+}
+
+func TestInHighVolumeWindowCustom(t *testing.T) {
+	loc, err := time.LoadLocation(NewYorkLocation)
+	assert.NoError(t, err)
+
+	windows := []SessionWindow{{Start: "3:00", End: "4:00"}, {Start: "19:00", End: "20:00"}}
+
+	// Ensure times within any of the provided windows are in the high volume window.
+	hvw, err := InHighVolumeWindow(time.Date(2025, 5, 7, 19, 30, 0, 0, loc), windows)
+	assert.NoError(t, err)
+	assert.True(t, hvw)
+
+	// Ensure the default window is replaced by the provided windows.
+	hvw, err = InHighVolumeWindow(time.Date(2025, 5, 7, 9, 0, 0, 0, loc), windows)
+	assert.NoError(t, err)
+	assert.False(t, hvw)
+}
+
+func TestParseSessionWindows(t *testing.T) {
+	// Ensure HH:MM-HH:MM entries are parsed.
+	windows, err := ParseSessionWindows([]string{"8:30-11:00", "22:00-02:00"})
+	assert.NoError(t, err)
+	assert.Equal(t, windows, []SessionWindow{{Start: "8:30", End: "11:00"}, {Start: "22:00", End: "02:00"}})
+
+	tests := []struct {
+		name  string
+		entry string
+	}{
+		{"missing separator", "8:30"},
+		{"invalid start", "8h30-11:00"},
+		{"invalid end", "8:30-25:00"},
+		{"empty window", "8:30-8:30"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Ensure malformed entries are rejected.
+			_, err := ParseSessionWindows([]string{test.entry})
+			assert.Error(t, err)
+		})
+	}
+}
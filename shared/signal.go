@@ -1,9 +1,17 @@
 package shared
 
 import (
+	"fmt"
+	"strings"
 	"time"
 )
 
+const (
+	// MaxConfluenceRatio is the multiple of the applicable confluence threshold at which
+	// signals reach full confidence.
+	MaxConfluenceRatio = 2
+)
+
 // StatusCode represents a request or signal status code.
 type StatusCode int
 
@@ -14,21 +22,48 @@ const (
 
 // EntrySignal represents an entry signal for a position.
 type EntrySignal struct {
-	Market              string
-	Timeframe           Timeframe
-	Direction           Direction
-	Price               float64
-	Reasons             []Reason
-	Confluence          uint32
+	// CorrelationID is the correlation id of the reaction the signal was produced by.
+	CorrelationID string
+	Market        string
+	Timeframe     Timeframe
+	Direction     Direction
+	Price         float64
+	Reasons       []Reason
+	Confluence    uint32
+	// Confidence is the confidence in [0, 1] of the signal's confluence relative to the
+	// applicable threshold, see ConfluenceConfidence.
+	Confidence float64
+	// WeightedConfidence is the confidence between 0 and 100 combining confluence, level
+	// quality and trend alignment by the engine's confidence weights.
+	WeightedConfidence  float64
 	StopLoss            float64
 	StopLossPointsRange float64
-	CreatedOn           time.Time
-	Status              chan StatusCode
+	// Bracket is the stop and target of the entry as linked one-cancels-other orders. It
+	// is nil if the entry is not bracketed.
+	Bracket *Bracket
+	// LevelPrice is the price of the level the entry reacted to, zero for entries at dynamic
+	// focuses.
+	LevelPrice float64
+	// ReasonPriority is the priority reasons are explained in. The default priority is used
+	// if nil.
+	ReasonPriority ReasonPriority
+	CreatedOn      time.Time
+	Status         chan StatusCode
 }
 
-// NewEntrySignal initializes a new entry signal.
+// ConfluenceConfidence returns the confidence in [0, 1] of the provided confluence relative to
+// the threshold it was confirmed against, reaching full confidence at MaxConfluenceRatio times
+// the threshold. A zero threshold is treated as a threshold of one.
+func ConfluenceConfidence(confluence uint32, threshold uint32) float64 {
+	threshold = max(threshold, 1)
+	return min(float64(confluence)/float64(threshold*MaxConfluenceRatio), 1)
+}
+
+// NewEntrySignal initializes a new entry signal. The signal's confidence is derived from the
+// provided confluence relative to the applicable threshold.
 func NewEntrySignal(market string, timeframe Timeframe, direction Direction, price float64,
-	reasons []Reason, confluence uint32, created time.Time, stopLoss float64, stopLossPointsRange float64) EntrySignal {
+	reasons []Reason, confluence uint32, threshold uint32, created time.Time, stopLoss float64,
+	stopLossPointsRange float64) EntrySignal {
 	return EntrySignal{
 		Market:              market,
 		Timeframe:           timeframe,
@@ -36,6 +71,7 @@ func NewEntrySignal(market string, timeframe Timeframe, direction Direction, pri
 		Price:               price,
 		Reasons:             reasons,
 		Confluence:          confluence,
+		Confidence:          ConfluenceConfidence(confluence, threshold),
 		CreatedOn:           created,
 		StopLoss:            stopLoss,
 		StopLossPointsRange: stopLossPointsRange,
@@ -43,16 +79,98 @@ func NewEntrySignal(market string, timeframe Timeframe, direction Direction, pri
 	}
 }
 
+// Explain returns a human-readable explanation of the entry signal's confluence, its
+// confluence confidence and the reasons contributing to it, in priority order. The weighted
+// confidence is not part of the explanation.
+func (s *EntrySignal) Explain() string {
+	explanation := fmt.Sprintf("confluence %d (%.0f%% confluence confidence)", s.Confluence, s.Confidence*100)
+	if len(s.Reasons) == 0 {
+		return explanation
+	}
+
+	reasons := s.ReasonPriority.Sort(s.Reasons)
+	descriptions := make([]string, 0, len(reasons))
+	for idx := range reasons {
+		descriptions = append(descriptions, reasons[idx].String())
+	}
+
+	return fmt.Sprintf("%s: %s", explanation, strings.Join(descriptions, ", "))
+}
+
+// Bracket represents a stop and target linked around an entry as one-cancels-other orders,
+// filling either cancels the other.
+type Bracket struct {
+	Direction Direction
+	Entry     float64
+	StopLoss  float64
+	Target    float64
+}
+
+// NewBracket initializes a new bracket around the provided entry. The target is placed at
+// the provided multiple of the points risked to the stop loss.
+func NewBracket(direction Direction, entry float64, stopLoss float64, rewardRatio float64) (*Bracket, error) {
+	if rewardRatio <= 0 {
+		return nil, fmt.Errorf("bracket reward ratio must be greater than zero")
+	}
+
+	// The target mirrors the stop loss across the entry, scaled by the reward ratio.
+	bracket := &Bracket{
+		Direction: direction,
+		Entry:     entry,
+		StopLoss:  stopLoss,
+		Target:    entry + (entry-stopLoss)*rewardRatio,
+	}
+
+	err := bracket.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return bracket, nil
+}
+
+// Validate asserts the stop loss and target of the bracket are on opposing sides of the
+// entry for its direction.
+func (b *Bracket) Validate() error {
+	switch b.Direction {
+	case Long:
+		if b.StopLoss >= b.Entry || b.Target <= b.Entry {
+			return fmt.Errorf("long bracket must have its stop loss below and target above "+
+				"the entry: stop loss %.2f, entry %.2f, target %.2f", b.StopLoss, b.Entry, b.Target)
+		}
+	case Short:
+		if b.StopLoss <= b.Entry || b.Target >= b.Entry {
+			return fmt.Errorf("short bracket must have its stop loss above and target below "+
+				"the entry: stop loss %.2f, entry %.2f, target %.2f", b.StopLoss, b.Entry, b.Target)
+		}
+	default:
+		return fmt.Errorf("unknown bracket direction provided: %s", b.Direction.String())
+	}
+
+	return nil
+}
+
+// String stringifies the bracket.
+func (b *Bracket) String() string {
+	return fmt.Sprintf("%s oco bracket: entry @ %.2f, stoploss @ %.2f, target @ %.2f",
+		b.Direction.String(), b.Entry, b.StopLoss, b.Target)
+}
+
 // ExitSignal represents an exit signal for a position.
 type ExitSignal struct {
-	Market     string
-	Timeframe  Timeframe
-	Direction  Direction
-	Price      float64
-	Reasons    []Reason
-	Confluence uint32
-	CreatedOn  time.Time
-	Status     chan StatusCode
+	// CorrelationID is the correlation id of the reaction the signal was produced by.
+	CorrelationID string
+	Market        string
+	Timeframe     Timeframe
+	Direction     Direction
+	Price         float64
+	Reasons       []Reason
+	Confluence    uint32
+	// WeightedConfidence is the confidence between 0 and 100 combining confluence, level
+	// quality and trend alignment by the engine's confidence weights.
+	WeightedConfidence float64
+	CreatedOn          time.Time
+	Status             chan StatusCode
 }
 
 // NewExitSignal initializes a new exit signal.
@@ -75,6 +193,12 @@ type LevelSignal struct {
 	Market string
 	Price  float64
 	Close  float64
+	// Manual indicates whether the level is manually drawn, the kind of manual levels is
+	// provided instead of being derived from the close.
+	Manual bool
+	Kind   LevelKind
+	// Source is the session the level was derived from.
+	Source LevelSource
 	Status chan StatusCode
 }
 
@@ -88,6 +212,17 @@ func NewLevelSignal(market string, price float64, close float64) LevelSignal {
 	}
 }
 
+// NewManualLevelSignal initializes a new manually drawn level signal.
+func NewManualLevelSignal(market string, price float64, kind LevelKind) LevelSignal {
+	return LevelSignal{
+		Market: market,
+		Price:  price,
+		Manual: true,
+		Kind:   kind,
+		Status: make(chan StatusCode, 1),
+	}
+}
+
 // CatchUpSignal represents a signal to catchup on market data.
 type CatchUpSignal struct {
 	Market    string
@@ -135,3 +270,19 @@ func NewImbalanceSignal(market string, imbalance Imbalance) ImbalanceSignal {
 		Status:    make(chan StatusCode, 1),
 	}
 }
+
+// OrderBlockSignal represents an order block signal to outline a price level.
+type OrderBlockSignal struct {
+	Market     string
+	OrderBlock OrderBlockZone
+	Status     chan StatusCode
+}
+
+// NewOrderBlockSignal initializes a new order block signal.
+func NewOrderBlockSignal(market string, orderBlock OrderBlockZone) OrderBlockSignal {
+	return OrderBlockSignal{
+		Market:     market,
+		OrderBlock: orderBlock,
+		Status:     make(chan StatusCode, 1),
+	}
+}
@@ -1,6 +1,7 @@
 package shared
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/peterldowns/testy/assert"
@@ -13,7 +14,7 @@ func TestSignalStatus(t *testing.T) {
 	now, _, _ := NewYorkTime()
 
 	entrySignal := NewEntrySignal(market, timeframe, Long, float64(10),
-		[]Reason{BullishEngulfing, StrongMove, StrongVolume}, 8, now, 6, float64(2))
+		[]Reason{BullishEngulfing, StrongMove, StrongVolume}, 8, 6, now, 6, float64(2))
 	assert.NotNil(t, entrySignal)
 	go func() { entrySignal.Status <- Processed }()
 	status := <-entrySignal.Status
@@ -48,4 +49,110 @@ func TestSignalStatus(t *testing.T) {
 	go func() { imbalanceSignal.Status <- Processed }()
 	status = <-imbalanceSignal.Status
 	assert.Equal(t, status, Processed)
+
+	orderBlockSignal := NewOrderBlockSignal(market, OrderBlockZone{})
+	assert.NotNil(t, orderBlockSignal)
+	go func() { orderBlockSignal.Status <- Processed }()
+	status = <-orderBlockSignal.Status
+	assert.Equal(t, status, Processed)
+}
+
+func TestConfluenceConfidence(t *testing.T) {
+	tests := []struct {
+		name       string
+		confluence uint32
+		threshold  uint32
+		want       float64
+	}{
+		{"no confluence", 0, 6, 0},
+		{"half the threshold", 3, 6, 0.25},
+		{"at the threshold", 6, 6, 0.5},
+		{"between the threshold and max", 9, 6, 0.75},
+		{"at the max", 12, 6, 1},
+		{"clamped beyond the max", 30, 6, 1},
+		{"zero threshold", 1, 0, 0.5},
+	}
+
+	for _, test := range tests {
+		confidence := ConfluenceConfidence(test.confluence, test.threshold)
+		assert.Equal(t, confidence, test.want)
+	}
+
+	// Ensure confidence scales with confluence.
+	prev := float64(-1)
+	for confluence := range uint32(15) {
+		confidence := ConfluenceConfidence(confluence, 6)
+		assert.True(t, confidence >= prev)
+		assert.True(t, confidence >= 0 && confidence <= 1)
+		prev = confidence
+	}
+}
+
+func TestEntrySignalExplain(t *testing.T) {
+	now, _, _ := NewYorkTime()
+
+	// Ensure entry signals are populated with confidence.
+	signal := NewEntrySignal("^GSPC", FiveMinute, Long, float64(10),
+		[]Reason{StrongVolume, BullishEngulfing, LiquiditySweep, ReversalAtSupport}, 9, 6, now, 8, float64(2))
+	assert.Equal(t, signal.Confidence, float64(0.75))
+
+	// Ensure the explanation lists reasons in priority order.
+	assert.Equal(t, signal.Explain(), "confluence 9 (75% confluence confidence): bullish engulfing, "+
+		"liquidity sweep, price reversal at support, strong volume")
+
+	// Ensure the explanation follows the signal's reason priority when configured.
+	signal.ReasonPriority = ReasonPriority{StrongVolume, ReversalAtSupport}
+	assert.Equal(t, signal.Explain(), "confluence 9 (75% confluence confidence): strong volume, "+
+		"price reversal at support, bullish engulfing, liquidity sweep")
+	signal.ReasonPriority = nil
+
+	// Ensure the signal's reasons are left unsorted.
+	assert.Equal(t, signal.Reasons[0], StrongVolume)
+
+	// Ensure signals without reasons are explained by confidence alone.
+	signal = NewEntrySignal("^GSPC", FiveMinute, Short, float64(10), nil, 12, 6, now, 12, float64(2))
+	assert.Equal(t, signal.Explain(), "confluence 12 (100% confluence confidence)")
+}
+
+func TestNewBracket(t *testing.T) {
+	tests := []struct {
+		name        string
+		direction   Direction
+		entry       float64
+		stopLoss    float64
+		rewardRatio float64
+		target      float64
+		wantErr     bool
+	}{
+		{"long bracket", Long, 100, 98, 2, 104, false},
+		{"short bracket", Short, 100, 101, 1.5, 98.5, false},
+		{"long stop loss above entry", Long, 100, 101, 2, 0, true},
+		{"short stop loss below entry", Short, 100, 99, 2, 0, true},
+		{"stop loss at entry", Long, 100, 100, 2, 0, true},
+		{"zero reward ratio", Long, 100, 98, 0, 0, true},
+		{"unknown direction", Direction(9), 100, 98, 2, 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bracket, err := NewBracket(test.direction, test.entry, test.stopLoss, test.rewardRatio)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			// Ensure the bracket is consistent with the entry, stop loss and direction.
+			assert.NoError(t, err)
+			assert.Equal(t, bracket.Direction, test.direction)
+			assert.Equal(t, bracket.Entry, test.entry)
+			assert.Equal(t, bracket.StopLoss, test.stopLoss)
+			assert.Equal(t, bracket.Target, test.target)
+			assert.NoError(t, bracket.Validate())
+			assert.True(t, strings.Contains(bracket.String(), "oco bracket"))
+		})
+	}
+
+	// Ensure brackets with a target on the stop loss side of the entry are invalid.
+	bracket := &Bracket{Direction: Short, Entry: 100, StopLoss: 102, Target: 101}
+	assert.Error(t, bracket.Validate())
 }
@@ -39,12 +39,17 @@ func (t Timeframe) String() string {
 
 // NewYorkTime returns the current time in new york (EST/EDT adjusted automatically).
 func NewYorkTime() (time.Time, *time.Location, error) {
+	return NewYorkTimeFrom(WallClock{})
+}
+
+// NewYorkTimeFrom returns the current time of the provided clock in new york.
+func NewYorkTimeFrom(clock Clock) (time.Time, *time.Location, error) {
 	loc, err := time.LoadLocation(NewYorkLocation)
 	if err != nil {
 		return time.Time{}, nil, fmt.Errorf("loading new york timezone: %w", err)
 	}
 
-	now := time.Now().In(loc)
+	now := clock.Now().In(loc)
 	return now, loc, nil
 }
 
@@ -1,5 +1,7 @@
 package shared
 
+import "time"
+
 // Trend represents the market trend.
 type Trend int
 
@@ -28,3 +30,22 @@ func (t Trend) String() string {
 		return "unknown trend"
 	}
 }
+
+// MovingAverage represents a unit moving average entry for a market.
+type MovingAverage struct {
+	Value float64
+	Date  time.Time
+}
+
+// Bias returns the trend sentiment of the provided price relative to the moving average,
+// price above the moving average is bullish and price below it is bearish.
+func (m *MovingAverage) Bias(price float64) Sentiment {
+	switch {
+	case price > m.Value:
+		return Bullish
+	case price < m.Value:
+		return Bearish
+	default:
+		return Neutral
+	}
+}
@@ -48,3 +48,18 @@ func TestTrendString(t *testing.T) {
 		}
 	}
 }
+
+func TestMovingAverageBias(t *testing.T) {
+	ma := &MovingAverage{Value: 10}
+
+	// Ensure price above the moving average is bullish, below it bearish and at it neutral.
+	if bias := ma.Bias(11); bias != Bullish {
+		t.Errorf("expected bullish bias above the moving average, got %s", bias.String())
+	}
+	if bias := ma.Bias(9); bias != Bearish {
+		t.Errorf("expected bearish bias below the moving average, got %s", bias.String())
+	}
+	if bias := ma.Bias(10); bias != Neutral {
+		t.Errorf("expected neutral bias at the moving average, got %s", bias.String())
+	}
+}
@@ -0,0 +1,164 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+)
+
+const (
+	// DefaultVolumeProfileBinSize is the default price range covered by a volume profile bin.
+	DefaultVolumeProfileBinSize = 0.25
+	// MinVolumeProfileBinSize is the minimum price range covered by a volume profile bin.
+	MinVolumeProfileBinSize = 0.0001
+	// MaxVolumeProfileBins is the maximum number of bins a volume profile spans, bounding
+	// the bins walked by updates, the point of control and the value area.
+	MaxVolumeProfileBins = 100000
+	// ValueAreaPercent is the percentage of the total volume covered by the value area.
+	ValueAreaPercent = 0.7
+)
+
+// VolumeProfile represents the distribution of traded volume by price.
+type VolumeProfile struct {
+	binSize float64
+	bins    map[int64]float64
+	minBin  int64
+	maxBin  int64
+	total   float64
+	dataMtx sync.RWMutex
+}
+
+// NewVolumeProfile initializes a new volume profile with the provided bin size.
+func NewVolumeProfile(binSize float64) (*VolumeProfile, error) {
+	if binSize <= 0 {
+		return nil, errors.New("volume profile bin size must be greater than zero")
+	}
+	if binSize < MinVolumeProfileBinSize {
+		return nil, fmt.Errorf("volume profile bin size cannot be below %v", MinVolumeProfileBinSize)
+	}
+
+	return &VolumeProfile{
+		binSize: binSize,
+		bins:    make(map[int64]float64),
+	}, nil
+}
+
+// bin returns the index of the bin the provided price falls in.
+func (p *VolumeProfile) bin(price float64) int64 {
+	return int64(math.Floor(price / p.binSize))
+}
+
+// Update distributes the volume of the provided candle evenly across the bins spanned
+// by its range. Candles extending the profile beyond the maximum number of bins are
+// rejected.
+func (p *VolumeProfile) Update(candle *Candlestick) error {
+	if candle.Volume <= 0 {
+		return nil
+	}
+
+	low := p.bin(candle.Low)
+	high := p.bin(candle.High)
+	if high < low {
+		low, high = high, low
+	}
+
+	p.dataMtx.Lock()
+	defer p.dataMtx.Unlock()
+
+	minBin, maxBin := low, high
+	if p.total > 0 {
+		minBin = min(minBin, p.minBin)
+		maxBin = max(maxBin, p.maxBin)
+	}
+	if maxBin-minBin+1 > MaxVolumeProfileBins {
+		return fmt.Errorf("candle at %s extends the volume profile beyond %d bins of size %v",
+			candle.Date, MaxVolumeProfileBins, p.binSize)
+	}
+
+	share := candle.Volume / float64(high-low+1)
+	for idx := low; idx <= high; idx++ {
+		p.bins[idx] += share
+	}
+
+	p.minBin = minBin
+	p.maxBin = maxBin
+	p.total += candle.Volume
+
+	return nil
+}
+
+// TotalVolume returns the total volume of the profile.
+func (p *VolumeProfile) TotalVolume() float64 {
+	p.dataMtx.RLock()
+	defer p.dataMtx.RUnlock()
+
+	return p.total
+}
+
+// pointOfControlBin returns the index of the bin with the most volume. Ties are resolved
+// in favour of the lower price.
+//
+// This assumes the data mutex is held by the caller.
+func (p *VolumeProfile) pointOfControlBin() int64 {
+	poc := p.minBin
+	for idx := p.minBin; idx <= p.maxBin; idx++ {
+		if p.bins[idx] > p.bins[poc] {
+			poc = idx
+		}
+	}
+
+	return poc
+}
+
+// PointOfControl returns the midpoint price of the bin with the most volume.
+func (p *VolumeProfile) PointOfControl() (float64, error) {
+	p.dataMtx.RLock()
+	defer p.dataMtx.RUnlock()
+
+	if p.total == 0 {
+		return 0, errors.New("volume profile has no volume")
+	}
+
+	poc := p.pointOfControlBin()
+	return (float64(poc) + 0.5) * p.binSize, nil
+}
+
+// ValueArea returns the high and low of the price range around the point of control
+// covering the value area percentage of the total volume.
+//
+// The value area is expanded from the point of control one bin at a time towards the
+// adjacent bin with more volume.
+func (p *VolumeProfile) ValueArea() (float64, float64, error) {
+	p.dataMtx.RLock()
+	defer p.dataMtx.RUnlock()
+
+	if p.total == 0 {
+		return 0, 0, errors.New("volume profile has no volume")
+	}
+
+	poc := p.pointOfControlBin()
+	target := p.total * ValueAreaPercent
+	low, high := poc, poc
+	volume := p.bins[poc]
+	for volume < target && (low > p.minBin || high < p.maxBin) {
+		below, above := -1.0, -1.0
+		if low > p.minBin {
+			below = p.bins[low-1]
+		}
+		if high < p.maxBin {
+			above = p.bins[high+1]
+		}
+
+		if above >= below {
+			high++
+			volume += above
+			continue
+		}
+
+		low--
+		volume += below
+	}
+
+	return float64(high+1) * p.binSize, float64(low) * p.binSize, nil
+}
@@ -0,0 +1,88 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/peterldowns/testy/assert"
+)
+
+func TestVolumeProfile(t *testing.T) {
+	// Ensure the bin size cannot be zero or negative.
+	_, err := NewVolumeProfile(0)
+	assert.Error(t, err)
+
+	_, err = NewVolumeProfile(-1)
+	assert.Error(t, err)
+
+	// Ensure the bin size cannot be below the minimum bin size.
+	_, err = NewVolumeProfile(MinVolumeProfileBinSize / 2)
+	assert.Error(t, err)
+
+	// Ensure a volume profile can be created.
+	profile, err := NewVolumeProfile(1)
+	assert.NoError(t, err)
+
+	// Ensure an empty profile has no point of control or value area.
+	_, err = profile.PointOfControl()
+	assert.Error(t, err)
+
+	_, _, err = profile.ValueArea()
+	assert.Error(t, err)
+
+	// Ensure candles without volume are ignored.
+	profile.Update(&Candlestick{High: 12, Low: 10, Volume: 0})
+	assert.Equal(t, profile.TotalVolume(), float64(0))
+
+	// Ensure candle volume is spread evenly across the bins of its range.
+	candles := []*Candlestick{
+		{Open: 10.5, Close: 11.5, High: 12, Low: 10, Volume: 30},
+		{Open: 11.2, Close: 11.4, High: 11.5, Low: 11, Volume: 40},
+		{Open: 12.5, Close: 13.5, High: 14, Low: 12, Volume: 30},
+	}
+
+	for idx := range candles {
+		profile.Update(candles[idx])
+	}
+
+	// bins: [10,11) = 10, [11,12) = 50, [12,13) = 20, [13,14) = 10, [14,15) = 10
+	assert.Equal(t, profile.TotalVolume(), float64(100))
+	assert.Equal(t, profile.bins[10], float64(10))
+	assert.Equal(t, profile.bins[11], float64(50))
+	assert.Equal(t, profile.bins[12], float64(20))
+	assert.Equal(t, profile.bins[13], float64(10))
+	assert.Equal(t, profile.bins[14], float64(10))
+
+	// Ensure the point of control is the midpoint of the bin with the most volume.
+	poc, err := profile.PointOfControl()
+	assert.NoError(t, err)
+	assert.Equal(t, poc, float64(11.5))
+
+	// Ensure the value area expands from the point of control towards the heavier adjacent
+	// bin until it covers 70% of the volume.
+	high, low, err := profile.ValueArea()
+	assert.NoError(t, err)
+	assert.Equal(t, high, float64(13))
+	assert.Equal(t, low, float64(11))
+
+	// Ensure the value area keeps expanding until the value area percentage is reached.
+	profile.Update(&Candlestick{High: 10.5, Low: 10, Volume: 60})
+
+	// bins: [10,11) = 70, [11,12) = 50, [12,13) = 20, [13,14) = 10, [14,15) = 10
+	poc, err = profile.PointOfControl()
+	assert.NoError(t, err)
+	assert.Equal(t, poc, float64(10.5))
+
+	high, low, err = profile.ValueArea()
+	assert.NoError(t, err)
+	assert.Equal(t, high, float64(12))
+	assert.Equal(t, low, float64(10))
+
+	// Ensure candles extending the profile beyond the maximum number of bins are rejected.
+	err = profile.Update(&Candlestick{High: 10 + MaxVolumeProfileBins, Low: 10, Volume: 10})
+	assert.Error(t, err)
+	assert.Equal(t, profile.TotalVolume(), float64(160))
+
+	err = profile.Update(&Candlestick{High: 5 + MaxVolumeProfileBins, Low: 10, Volume: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, profile.TotalVolume(), float64(170))
+}
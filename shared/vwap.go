@@ -5,16 +5,97 @@ import (
 	"time"
 )
 
+// VWAPBand represents the vwap line or one of its standard deviation bands.
+type VWAPBand int
+
+const (
+	VWAPLine VWAPBand = iota
+	UpperBand1
+	LowerBand1
+	UpperBand2
+	LowerBand2
+)
+
+// VWAPBands are the standard deviation bands around the vwap line, ordered from the nearest.
+var VWAPBands = []VWAPBand{UpperBand1, LowerBand1, UpperBand2, LowerBand2}
+
+// String stringifies the provided vwap band.
+func (b VWAPBand) String() string {
+	switch b {
+	case VWAPLine:
+		return "vwap"
+	case UpperBand1:
+		return "upper band 1"
+	case LowerBand1:
+		return "lower band 1"
+	case UpperBand2:
+		return "upper band 2"
+	case LowerBand2:
+		return "lower band 2"
+	default:
+		return "unknown"
+	}
+}
+
+// Deviations returns the signed number of standard deviations the band is offset from the
+// vwap line.
+func (b VWAPBand) Deviations() float64 {
+	switch b {
+	case UpperBand1:
+		return 1
+	case LowerBand1:
+		return -1
+	case UpperBand2:
+		return 2
+	case LowerBand2:
+		return -2
+	default:
+		return 0
+	}
+}
+
 // VWAP represents a unit VWAP entry for a market.
 type VWAP struct {
-	Value float64
-	Date  time.Time
+	Value  float64
+	StdDev float64
+	Date   time.Time
+}
+
+// Band returns the value of the provided band of the vwap entry.
+func (v *VWAP) Band(band VWAPBand) float64 {
+	return v.Value + band.Deviations()*v.StdDev
+}
+
+// AtBand returns a copy of the vwap entry with its value set to the provided band.
+func (v *VWAP) AtBand(band VWAPBand) *VWAP {
+	return &VWAP{
+		Value:  v.Band(band),
+		StdDev: v.StdDev,
+		Date:   v.Date,
+	}
 }
 
 // ReactionAtVWAP describes the reaction of price relative to vwap.
 type ReactionAtVWAP struct {
 	ReactionAtFocus
 	VWAPData []*VWAP
+	// Band is the vwap line or band the reaction occurred at.
+	Band VWAPBand
+}
+
+// Line returns the vwap line at the close of the reaction, the latest vwap entry offset back
+// from the band the reaction occurred at.
+func (r *ReactionAtVWAP) Line() *VWAP {
+	if len(r.VWAPData) == 0 {
+		return nil
+	}
+
+	last := r.VWAPData[len(r.VWAPData)-1]
+	return &VWAP{
+		Value:  last.Value - r.Band.Deviations()*last.StdDev,
+		StdDev: last.StdDev,
+		Date:   last.Date,
+	}
 }
 
 // fetchVWAPLevelKind returns the level kind status of the provided vwap.
@@ -29,25 +110,48 @@ func fetchVWAPLevelKind(vwap *VWAP, candle *Candlestick) LevelKind {
 	return levelKind
 }
 
+// alignVWAPData truncates the provided vwap and price data to the length of the shorter,
+// keeping the most recent entries of both. Length mismatches beyond the provided tolerance
+// are an error.
+func alignVWAPData(vwapData []*VWAP, priceData []*Candlestick, tolerance uint32) ([]*VWAP, []*Candlestick, error) {
+	mismatch := len(vwapData) - len(priceData)
+	if mismatch < 0 {
+		mismatch = -mismatch
+	}
+	if mismatch > int(tolerance) {
+		return nil, nil, fmt.Errorf("vwap data is not the expected size: %d != expected(%d), "+
+			"tolerance(%d)", len(vwapData), len(priceData), tolerance)
+	}
+
+	size := min(len(vwapData), len(priceData))
+	return vwapData[len(vwapData)-size:], priceData[len(priceData)-size:], nil
+}
+
 // NewReactionAtVWAP initializes a new reaction from the provided vwap and candlestick data.
-func NewReactionAtVWAP(market string, vwapData []*VWAP, priceData []*Candlestick) (*ReactionAtVWAP, error) {
-	if len(vwapData) != VWAPDataPayloadSize {
-		return nil, fmt.Errorf("vwap data is not the expected size: %d != expected(%d)",
-			len(vwapData), VWAPDataPayloadSize)
+//
+// The vwap and price data are expected to be of the same length, mismatches of up to the
+// provided tolerance are truncated to the shorter of the two.
+func NewReactionAtVWAP(market string, vwapData []*VWAP, priceData []*Candlestick, tolerance uint32) (*ReactionAtVWAP, error) {
+	vwapData, priceData, err := alignVWAPData(vwapData, priceData, tolerance)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(priceData) != PriceDataPayloadSize {
-		return nil, fmt.Errorf("price data is not the expected size: %d != expected(%d)",
-			len(priceData), PriceDataPayloadSize)
+	if len(priceData) < MinReactionWindow {
+		return nil, fmt.Errorf("price data is below the minimum reaction window: %d < minimum(%d)",
+			len(priceData), MinReactionWindow)
 	}
 
 	levelKind := fetchVWAPLevelKind(vwapData[0], priceData[0])
 	vr := &ReactionAtVWAP{
 		ReactionAtFocus: ReactionAtFocus{
+			CorrelationID: NewCorrelationID(),
 			Market:        market,
+			Focus:         VWAPFocus,
 			LevelKind:     levelKind,
 			Timeframe:     priceData[len(priceData)-1].Timeframe,
 			PriceMovement: make([]PriceMovement, 0, len(priceData)),
+			TaggingCandle: priceData[0],
 			Status:        make(chan StatusCode, 1),
 			CurrentPrice:  priceData[len(priceData)-1].Close,
 			CreatedOn:     priceData[len(priceData)-1].Date,
@@ -71,24 +175,13 @@ func NewReactionAtVWAP(market string, vwapData []*VWAP, priceData []*Candlestick
 	}
 
 	// Generate a price reaction based on the price movement data.
-	var above, below uint32
-	for idx := range vr.PriceMovement {
-		switch {
-		case vr.PriceMovement[idx] == Above:
-			above++
-		case vr.PriceMovement[idx] == Below:
-			below++
-		}
-	}
+	above, below := countPriceMovement(vr.PriceMovement)
 
-	// The vwap reaction is currently rooted in being able to make a decision
-	// on a reaction using 4 5-minute candles. Changing the data size would
-	// require reworking the logic here.
+	// Only the first and last closes and the shape of the window are evaluated so the
+	// classification holds for any window length.
 
 	first := vr.PriceMovement[0]
-	second := vr.PriceMovement[1]
-	third := vr.PriceMovement[2]
-	fourth := vr.PriceMovement[3]
+	last := vr.PriceMovement[len(vr.PriceMovement)-1]
 
 	switch levelKind {
 	case Support:
@@ -97,22 +190,20 @@ func NewReactionAtVWAP(market string, vwapData []*VWAP, priceData []*Candlestick
 			// If price consistently stayed below a support vwap it tagged then it
 			// it is likely reversing at the vwap.
 			vr.Reaction = Reversal
-		case first == Above && third == Below && fourth == Below:
+		case first == Above && closedConsistently(vr.PriceMovement, Below):
 			// If price was above a vwap acting as support but starts to consistently close below it
 			// then it is likely breaking the vwap.
 			vr.Reaction = Break
-		case first == Above && second == Above && third == Above && fourth == Below:
+		case turnedSharply(vr.PriceMovement, Above, Below):
 			// If price was above a vwap acting as support but turns sharply to close below it then
 			// it is likely breaking the vwap.
 			vr.Reaction = Break
-		case first == Above && below > 0 && fourth == Above:
+		case first == Above && below > 0 && last == Above:
 			// If price was above a vwap acting as support but closed below it briefly and
 			// pushed back above it then it is likely reversing at the vwap.
 			vr.Reaction = Reversal
-		case first == Above && second == Below && third == Above && fourth == Below:
-			// If price is consistently closing aimlessly above and below a vwap it is chopping.
-			vr.Reaction = Chop
 		default:
+			// If price is consistently closing aimlessly above and below a vwap it is chopping.
 			vr.Reaction = Chop
 		}
 	case Resistance:
@@ -121,22 +212,20 @@ func NewReactionAtVWAP(market string, vwapData []*VWAP, priceData []*Candlestick
 			// If price consistently stayed below a vwap acting as resistance it tagged then
 			// it is likely reversing at the vwap.
 			vr.Reaction = Reversal
-		case first == Below && third == Above && fourth == Above:
+		case first == Below && closedConsistently(vr.PriceMovement, Above):
 			// If price was below a vwap acting as resistance but starts to consistently close
 			// above it then it is likely breaking the vwap.
 			vr.Reaction = Break
-		case first == Below && second == Below && third == Below && fourth == Above:
+		case turnedSharply(vr.PriceMovement, Below, Above):
 			// If price was below a vwap acting as resistance but turns sharply to close above it
 			// then it is likely breaking the vwap.
 			vr.Reaction = Break
-		case first == Below && above > 0 && fourth == Below:
+		case first == Below && above > 0 && last == Below:
 			// If price was below a vwap acting as resistance but closed above it briefly and pushed
 			// back below it then it is likely breaking the vwap.
 			vr.Reaction = Reversal
-		case first == Below && second == Above && third == Below && fourth == Above:
-			// If price is consistently closing aimlessly above and below a vwap it is chopping.
-			vr.Reaction = Chop
 		default:
+			// If price is consistently closing aimlessly above and below a vwap it is chopping.
 			vr.Reaction = Chop
 		}
 	}
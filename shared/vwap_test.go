@@ -1046,7 +1046,7 @@ func TestNewReactionAtVWAP(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		reaction, err := NewReactionAtVWAP(market, test.vwapData, test.candles)
+		reaction, err := NewReactionAtVWAP(market, test.vwapData, test.candles, 0)
 		if test.wantErr && err == nil {
 			t.Errorf("%s: unexpected error, got %v", test.name, err)
 		}
@@ -1072,6 +1072,120 @@ func TestNewReactionAtVWAP(t *testing.T) {
 			}
 
 			assert.NotNil(t, reaction.ReactionAtFocus.Status)
+			assert.NotEqual(t, reaction.ReactionAtFocus.CorrelationID, "")
 		}
 	}
 }
+
+func TestNewReactionAtVWAPWindows(t *testing.T) {
+	market := "^GSPC"
+	value := float64(12)
+
+	vwapData := func(window int) []*VWAP {
+		data := make([]*VWAP, window)
+		for idx := range data {
+			data[idx] = &VWAP{Value: value}
+		}
+		return data
+	}
+
+	// Ensure the same scenarios classify consistently across reaction windows.
+	for _, window := range []int{PriceDataPayloadSize, 6} {
+		for _, scenario := range reactionScenarios() {
+			movement := scenario.movement(window)
+
+			reaction, err := NewReactionAtVWAP(market, vwapData(window), movementCandles(movement, value), 0)
+			assert.NoError(t, err)
+			assert.Equal(t, reaction.LevelKind, Support)
+			assert.Equal(t, len(reaction.PriceMovement), window)
+			assert.Equal(t, reaction.Reaction, scenario.want)
+
+			reaction, err = NewReactionAtVWAP(market, vwapData(window), movementCandles(mirrorMovement(movement), value), 0)
+			assert.NoError(t, err)
+			assert.Equal(t, reaction.LevelKind, Resistance)
+			assert.Equal(t, reaction.Reaction, scenario.want)
+		}
+	}
+
+	// Ensure mismatched vwap and price data windows error.
+	_, err := NewReactionAtVWAP(market, vwapData(PriceDataPayloadSize),
+		movementCandles([]PriceMovement{Above, Above, Above, Above, Above, Above}, value), 0)
+	assert.Error(t, err)
+}
+
+func TestNewReactionAtVWAPMismatchTolerance(t *testing.T) {
+	market := "^GSPC"
+	value := float64(12)
+
+	vwapData := func(window int) []*VWAP {
+		data := make([]*VWAP, window)
+		for idx := range data {
+			data[idx] = &VWAP{Value: value}
+		}
+		return data
+	}
+
+	movement := []PriceMovement{Below, Above, Above, Above, Above}
+
+	// Ensure a one element mismatch is tolerated by truncating the price data to the
+	// most recent candles.
+	reaction, err := NewReactionAtVWAP(market, vwapData(len(movement)-1), movementCandles(movement, value), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, len(reaction.PriceMovement), len(movement)-1)
+	assert.Equal(t, reaction.PriceMovement, movement[1:])
+	assert.Equal(t, len(reaction.VWAPData), len(movement)-1)
+
+	// Ensure a one element mismatch is tolerated by truncating the vwap data.
+	reaction, err = NewReactionAtVWAP(market, vwapData(len(movement)+1), movementCandles(movement, value), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, reaction.PriceMovement, movement)
+	assert.Equal(t, len(reaction.VWAPData), len(movement))
+
+	// Ensure a one element mismatch errors without a tolerance.
+	_, err = NewReactionAtVWAP(market, vwapData(len(movement)-1), movementCandles(movement, value), 0)
+	assert.Error(t, err)
+
+	// Ensure a mismatch beyond the tolerance still errors.
+	_, err = NewReactionAtVWAP(market, vwapData(len(movement)-3), movementCandles(movement, value), 1)
+	assert.Error(t, err)
+
+	// Ensure truncated data below the minimum reaction window errors.
+	_, err = NewReactionAtVWAP(market, vwapData(MinReactionWindow-1),
+		movementCandles(movement[:MinReactionWindow], value), 1)
+	assert.Error(t, err)
+}
+
+func TestVWAPBand(t *testing.T) {
+	vwap := &VWAP{Value: 100, StdDev: 2.5}
+
+	tests := []struct {
+		name  string
+		band  VWAPBand
+		str   string
+		value float64
+	}{
+		{"vwap line", VWAPLine, "vwap", 100},
+		{"upper band 1", UpperBand1, "upper band 1", 102.5},
+		{"lower band 1", LowerBand1, "lower band 1", 97.5},
+		{"upper band 2", UpperBand2, "upper band 2", 105},
+		{"lower band 2", LowerBand2, "lower band 2", 95},
+		{"unknown", VWAPBand(999), "unknown", 100},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Ensure the band is stringified and valued as expected.
+			assert.Equal(t, test.band.String(), test.str)
+			assert.Equal(t, vwap.Band(test.band), test.value)
+
+			// Ensure a vwap entry can be projected onto the band.
+			projected := vwap.AtBand(test.band)
+			assert.Equal(t, projected.Value, test.value)
+			assert.Equal(t, projected.StdDev, vwap.StdDev)
+
+			// Ensure the vwap line is recovered from a reaction at the band.
+			reaction := &ReactionAtVWAP{VWAPData: []*VWAP{projected}, Band: test.band}
+			assert.Equal(t, reaction.Line().Value, vwap.Value)
+		})
+	}
+}
@@ -20,7 +20,8 @@ const (
 	minStrongSlope = 0.5
 )
 
-// VWAPSnapshot represents a snapshot of vwap data.
+// VWAPSnapshot represents a snapshot of vwap data. It is safe for concurrent use, updates
+// from the market worker are serialized against reads from request workers.
 type VWAPSnapshot struct {
 	data      []*VWAP
 	dataMtx   sync.RWMutex
@@ -67,6 +68,29 @@ func (s *VWAPSnapshot) Update(vwap *VWAP) {
 	}
 }
 
+// ReplaceLast replaces the last entry of the snapshot with the provided vwap, it is added if
+// the snapshot is empty.
+func (s *VWAPSnapshot) ReplaceLast(vwap *VWAP) {
+	s.dataMtx.Lock()
+	defer s.dataMtx.Unlock()
+
+	start := s.start.Load()
+	count := s.count.Load()
+	size := s.size.Load()
+	if count == 0 {
+		s.data[start] = vwap
+		s.count.Add(1)
+		return
+	}
+
+	s.data[(start+count-1)%size] = vwap
+}
+
+// Len returns the number of entries in the snapshot.
+func (s *VWAPSnapshot) Len() int32 {
+	return s.count.Load()
+}
+
 // Last returns the last added entry for the snapshot.
 func (s *VWAPSnapshot) Last() *VWAP {
 	s.dataMtx.RLock()
@@ -179,8 +203,12 @@ func (s *VWAPSnapshot) Trend(n int32) (Trend, float64, float64) {
 		return ChoppyTrend, 0, 0
 	}
 
+	// Evaluate the trend over the available entries if the snapshot has fewer than requested.
 	values := s.LastN(n)
-	nf := float64(n)
+	if len(values) < 2 {
+		return ChoppyTrend, 0, 0
+	}
+	nf := float64(len(values))
 
 	// Calculate the linear regression slope of the vwap which is the strength of the trend.
 	// The slope can either be positive or negative. A high slope value regardless of sign
@@ -201,7 +229,7 @@ func (s *VWAPSnapshot) Trend(n int32) (Trend, float64, float64) {
 	}
 
 	slope := numerator / denominator
-	meanY := sumY / float64(n)
+	meanY := sumY / nf
 
 	// Calculate total sum of squares and residual sum of squares.
 	var totalSum, residualSum float64
@@ -2,7 +2,9 @@ package shared
 
 import (
 	"math"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/peterldowns/testy/assert"
 )
@@ -21,6 +23,9 @@ func TestVWAPSnapshot(t *testing.T) {
 	vwapSnapshot, err = NewVWAPSnapshot(size, timeframe)
 	assert.NoError(t, err)
 
+	// Ensure an empty snapshot has no entries.
+	assert.Equal(t, vwapSnapshot.Len(), int32(0))
+
 	// Ensure calling last on an empty snapshot returns nothing.
 	last := vwapSnapshot.Last()
 	assert.Nil(t, last)
@@ -91,9 +96,24 @@ func TestVWAPSnapshot(t *testing.T) {
 	assert.Equal(t, vwapSnapshot.start.Load(), 2)
 	assert.Equal(t, len(vwapSnapshot.data), int(size))
 
+	// Ensure the snapshot length is capped at its size.
+	assert.Equal(t, vwapSnapshot.Len(), size)
+
 	// Ensure vwap entries can be fetched by their associated timestamps.
 	vwapAtTime := vwapSnapshot.At(now)
 	assert.NotNil(t, vwapAtTime)
+
+	// Ensure the last entry can be replaced in place.
+	vwapSnapshot.ReplaceLast(&VWAP{Value: 7})
+	assert.Equal(t, vwapSnapshot.start.Load(), 2)
+	assert.Equal(t, vwapSnapshot.Last().Value, float64(7))
+
+	// Ensure replacing the last entry of an empty snapshot adds it.
+	emptySnapshot, err := NewVWAPSnapshot(size, FiveMinute)
+	assert.NoError(t, err)
+	emptySnapshot.ReplaceLast(&VWAP{Value: 7})
+	assert.Equal(t, emptySnapshot.Len(), 1)
+	assert.Equal(t, emptySnapshot.Last().Value, float64(7))
 }
 
 func TestVWAPTrend(t *testing.T) {
@@ -182,3 +202,63 @@ func TestVWAPTrend(t *testing.T) {
 		}
 	}
 }
+
+func TestVWAPTrendPartialSnapshot(t *testing.T) {
+	snapshot, err := NewVWAPSnapshot(30, FiveMinute)
+	assert.NoError(t, err)
+
+	// Ensure a snapshot with too few entries is choppy.
+	snapshot.Update(&VWAP{Value: 2})
+	trend, _, _ := snapshot.Trend(20)
+	assert.Equal(t, trend, ChoppyTrend)
+
+	// Ensure the trend is evaluated over the available entries when fewer than requested.
+	for idx := range 9 {
+		snapshot.Update(&VWAP{Value: float64(2 * (idx + 2))})
+	}
+	trend, slope, r2 := snapshot.Trend(20)
+	assert.Equal(t, trend, StrongBullishTrend)
+	assert.True(t, math.Abs(slope-2) < 0.0001)
+	assert.True(t, math.Abs(r2-1) < 0.0001)
+}
+
+func TestVWAPSnapshotConcurrentAccess(t *testing.T) {
+	size := int32(16)
+	snapshot, err := NewVWAPSnapshot(size, FiveMinute)
+	assert.NoError(t, err)
+
+	now := time.Now()
+	updates := 200
+
+	// Ensure the snapshot can be updated and read concurrently.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for idx := range updates {
+			snapshot.Update(&VWAP{
+				Value: float64(idx),
+				Date:  now.Add(time.Minute * time.Duration(idx)),
+			})
+		}
+	}()
+
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range updates {
+				set := snapshot.LastN(size)
+				assert.True(t, len(set) <= int(size))
+				assert.True(t, snapshot.Len() <= size)
+				snapshot.At(now.Add(time.Minute * time.Duration(idx)))
+				snapshot.Last()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, snapshot.Len(), size)
+	assert.Equal(t, snapshot.Last().Value, float64(updates-1))
+}